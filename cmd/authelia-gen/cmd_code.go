@@ -282,6 +282,12 @@ func readTags(prefix string, t reflect.Type) (tags []string) {
 			case reflect.Slice:
 				tags = append(tags, readTags(getKeyNameFromTagAndPrefix(prefix, tag, true), field.Type.Elem())...)
 			}
+		case reflect.Map:
+			if field.Type.Elem().Kind() == reflect.Struct && !containsType(field.Type.Elem(), decodedTypes) {
+				tags = append(tags, readTags(getKeyNameFromTagAndPrefix(prefix, tag, false)+".*", field.Type.Elem())...)
+
+				continue
+			}
 		case reflect.Ptr:
 			switch field.Type.Elem().Kind() {
 			case reflect.Struct:
@@ -92,7 +92,7 @@ func docsKeysRunE(cmd *cobra.Command, args []string) (err error) {
 	keys := readTags("", reflect.TypeOf(schema.Configuration{}))
 
 	for _, key := range keys {
-		if strings.Contains(key, "[]") {
+		if strings.Contains(key, "[]") || strings.Contains(key, ".*.") {
 			continue
 		}
 
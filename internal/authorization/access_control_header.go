@@ -0,0 +1,78 @@
+package authorization
+
+import (
+	"fmt"
+	"net/textproto"
+	"strings"
+	"text/template"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// NewAccessControlHeaders creates a new AccessControlHeader slice from a schema.ACLHeader slice.
+func NewAccessControlHeaders(config []schema.ACLHeader) (headers []AccessControlHeader) {
+	if len(config) == 0 {
+		return nil
+	}
+
+	for _, h := range config {
+		header, err := NewAccessControlHeader(h)
+		if err != nil {
+			continue
+		}
+
+		headers = append(headers, header)
+	}
+
+	return headers
+}
+
+// NewAccessControlHeader creates a new AccessControlHeader from a schema.ACLHeader.
+func NewAccessControlHeader(config schema.ACLHeader) (header AccessControlHeader, err error) {
+	if config.Name == "" {
+		return header, fmt.Errorf("header name is empty")
+	}
+
+	header = AccessControlHeader{
+		Name: textproto.CanonicalMIMEHeaderKey(config.Name),
+	}
+
+	if header.Template, err = template.New(header.Name).Funcs(templates.FuncMap()).Option("missingkey=error").Parse(config.Value); err != nil {
+		return header, fmt.Errorf("header '%s' has an invalid value template: %w", config.Name, err)
+	}
+
+	return header, nil
+}
+
+// AccessControlHeader represents a header which is returned to the proxy when a request is authorized.
+type AccessControlHeader struct {
+	Name     string
+	Template *template.Template
+}
+
+// Value returns the header value rendered using the HeaderValues.
+func (h AccessControlHeader) Value(values HeaderValues) (value string, err error) {
+	buf := &strings.Builder{}
+
+	if err = h.Template.Execute(buf, values); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// HeaderValues represents the values available to an AccessControlHeader template.
+type HeaderValues struct {
+	Username    string
+	DisplayName string
+	Groups      []string
+	Emails      []string
+
+	AuthenticationLevel string
+	AMR                 []string
+	SessionIDHash       string
+
+	URL    string
+	Method string
+}
@@ -27,6 +27,8 @@ func NewAccessControlRule(pos int, rule schema.ACLRule, networksMap map[string][
 		Networks: schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
 		Subjects: schemaSubjectsToACL(rule.Subjects),
 		Policy:   NewLevel(rule.Policy),
+		Headers:  NewAccessControlHeaders(rule.Headers),
+		Audience: rule.IdentityAssertionAudience,
 	}
 
 	if len(r.Subjects) != 0 {
@@ -52,6 +54,8 @@ type AccessControlRule struct {
 	Networks  []*net.IPNet
 	Subjects  []AccessControlSubjects
 	Policy    Level
+	Headers   []AccessControlHeader
+	Audience  []string
 }
 
 // IsMatch returns true if all elements of an AccessControlRule match the object and subject.
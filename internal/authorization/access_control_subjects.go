@@ -48,3 +48,13 @@ type AccessControlGroup struct {
 func (acg AccessControlGroup) IsMatch(subject Subject) (match bool) {
 	return utils.IsStringInSlice(acg.Name, subject.Groups)
 }
+
+// AccessControlClient represents an ACL subject of type `oauth2:client:`.
+type AccessControlClient struct {
+	ID string
+}
+
+// IsMatch returns true if the AccessControlClient id matches the Subject client id.
+func (acc AccessControlClient) IsMatch(subject Subject) (match bool) {
+	return subject.ClientID != "" && subject.ClientID == acc.ID
+}
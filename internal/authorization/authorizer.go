@@ -11,6 +11,7 @@ import (
 type Authorizer struct {
 	defaultPolicy Level
 	rules         []*AccessControlRule
+	headers       []AccessControlHeader
 	mfa           bool
 	config        *schema.Configuration
 	log           *logrus.Logger
@@ -21,6 +22,7 @@ func NewAuthorizer(config *schema.Configuration) (authorizer *Authorizer) {
 	authorizer = &Authorizer{
 		defaultPolicy: NewLevel(config.AccessControl.DefaultPolicy),
 		rules:         NewAccessControlRules(config.AccessControl),
+		headers:       NewAccessControlHeaders(config.AccessControl.Headers),
 		config:        config,
 		log:           logging.Logger(),
 	}
@@ -40,6 +42,13 @@ func NewAuthorizer(config *schema.Configuration) (authorizer *Authorizer) {
 	}
 
 	if authorizer.config.IdentityProviders.OIDC != nil {
+		// Dynamically registered clients always use the default two_factor policy.
+		if authorizer.config.IdentityProviders.OIDC.DynamicClientRegistration.Enabled {
+			authorizer.mfa = true
+
+			return authorizer
+		}
+
 		for _, client := range authorizer.config.IdentityProviders.OIDC.Clients {
 			if client.Policy == twoFactor {
 				authorizer.mfa = true
@@ -77,6 +86,37 @@ func (p Authorizer) GetRequiredLevel(subject Subject, object Object) (hasSubject
 	return false, p.defaultPolicy
 }
 
+// GetHeaders retrieves the headers which should be returned to the proxy when the subject is authorized to access the
+// object. The headers of the first matching rule are returned if configured, otherwise the default headers are
+// returned. If neither are configured nil is returned.
+func (p Authorizer) GetHeaders(subject Subject, object Object) (headers []AccessControlHeader) {
+	if rule := p.getMatchingRule(subject, object); rule != nil && len(rule.Headers) != 0 {
+		return rule.Headers
+	}
+
+	return p.headers
+}
+
+// GetAudience retrieves the audience of the identity assertion for the subject accessing the object. The audience of
+// the first matching rule is returned if configured, otherwise the domain of the object is returned.
+func (p Authorizer) GetAudience(subject Subject, object Object) (audience []string) {
+	if rule := p.getMatchingRule(subject, object); rule != nil && len(rule.Audience) != 0 {
+		return rule.Audience
+	}
+
+	return []string{object.Domain}
+}
+
+func (p Authorizer) getMatchingRule(subject Subject, object Object) (rule *AccessControlRule) {
+	for _, rule = range p.rules {
+		if rule.IsMatch(subject, object) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
 // GetRuleMatchResults iterates through the rules and produces a list of RuleMatchResult provided a subject and object.
 func (p Authorizer) GetRuleMatchResults(subject Subject, object Object) (results []RuleMatchResult) {
 	skipped := false
@@ -109,6 +109,11 @@ var UserWithIPv6AddressAndGroups = Subject{
 
 var Sally = UserWithIPv6AddressAndGroups
 
+var OAuth2Client = Subject{
+	ClientID: "machine",
+	IP:       net.ParseIP("10.0.0.9"),
+}
+
 func (s *AuthorizerSuite) TestShouldCheckDefaultBypassConfig() {
 	tester := NewAuthorizerBuilder().
 		WithDefaultPolicy(bypass).Build()
@@ -653,6 +658,27 @@ func (s *AuthorizerSuite) TestShouldCheckGroupMatching() {
 	tester.CheckAuthorizations(s.T(), Bob, "https://protected.example.com/", "GET", Denied)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckOAuth2ClientMatching() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(deny).
+		WithRule(schema.ACLRule{
+			Domains:  []string{"api.example.com"},
+			Policy:   oneFactor,
+			Subjects: [][]string{{"oauth2:client:machine"}},
+		}).
+		WithRule(schema.ACLRule{
+			Domains:  []string{"protected.example.com"},
+			Policy:   oneFactor,
+			Subjects: [][]string{{"user:john"}},
+		}).
+		Build()
+
+	tester.CheckAuthorizations(s.T(), OAuth2Client, "https://api.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), John, "https://api.example.com/", "GET", Denied)
+	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://api.example.com/", "GET", OneFactor)
+	tester.CheckAuthorizations(s.T(), OAuth2Client, "https://protected.example.com/", "GET", Denied)
+}
+
 func (s *AuthorizerSuite) TestShouldCheckSubjectsMatching() {
 	tester := NewAuthorizerBuilder().
 		WithDefaultPolicy(deny).
@@ -1099,7 +1125,126 @@ func TestAuthorizerIsSecondFactorEnabledRuleWithOIDC(t *testing.T) {
 	authorizer = NewAuthorizer(config)
 	assert.False(t, authorizer.IsSecondFactorEnabled())
 
+	config.IdentityProviders.OIDC.DynamicClientRegistration.Enabled = true
+	authorizer = NewAuthorizer(config)
+	assert.True(t, authorizer.IsSecondFactorEnabled())
+
 	config.AccessControl.DefaultPolicy = twoFactor
 	authorizer = NewAuthorizer(config)
 	assert.True(t, authorizer.IsSecondFactorEnabled())
 }
+
+func TestAuthorizerGetHeaders(t *testing.T) {
+	config := &schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: deny,
+			Headers: []schema.ACLHeader{
+				{Name: "Remote-User", Value: "{{ .Username }}"},
+			},
+			Rules: []schema.ACLRule{
+				{
+					Domains: []string{"app.example.com"},
+					Policy:  oneFactor,
+					Headers: []schema.ACLHeader{
+						{Name: "x-forwarded-user", Value: "{{ .Username }}"},
+						{Name: "X-Forwarded-Groups", Value: "{{ toJson .Groups }}"},
+						{Name: "X-Forwarded-Email", Value: "{{ with .Emails }}{{ index . 0 }}{{ end }}"},
+					},
+				},
+				{
+					Domains: []string{"*.example.com"},
+					Policy:  oneFactor,
+				},
+			},
+		},
+	}
+
+	authorizer := NewAuthorizer(config)
+
+	headers := authorizer.GetHeaders(John, NewObject(&url.URL{Scheme: "https", Host: "app.example.com", Path: "/"}, "GET"))
+	require.Len(t, headers, 3)
+
+	values := HeaderValues{
+		Username: "john",
+		Groups:   []string{"dev", "admins"},
+		Emails:   []string{"john@example.com", "jsmith@example.com"},
+	}
+
+	expected := map[string]string{
+		"X-Forwarded-User":   "john",
+		"X-Forwarded-Groups": `["dev","admins"]`,
+		"X-Forwarded-Email":  "john@example.com",
+	}
+
+	for _, header := range headers {
+		value, err := header.Value(values)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected[header.Name], value)
+	}
+
+	headers = authorizer.GetHeaders(John, NewObject(&url.URL{Scheme: "https", Host: "other.example.com", Path: "/"}, "GET"))
+	require.Len(t, headers, 1)
+	assert.Equal(t, "Remote-User", headers[0].Name)
+
+	headers = authorizer.GetHeaders(John, NewObject(&url.URL{Scheme: "https", Host: "example.org", Path: "/"}, "GET"))
+	require.Len(t, headers, 1)
+
+	config.AccessControl.Headers = nil
+
+	authorizer = NewAuthorizer(config)
+
+	assert.Nil(t, authorizer.GetHeaders(John, NewObject(&url.URL{Scheme: "https", Host: "other.example.com", Path: "/"}, "GET")))
+}
+
+func TestNewAccessControlHeader(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.ACLHeader
+		expected string
+		err      string
+	}{
+		{"ShouldParseAndCanonicalizeName", schema.ACLHeader{Name: "remote-user", Value: "{{ .Username }}"}, "Remote-User", ""},
+		{"ShouldErrorOnEmptyName", schema.ACLHeader{Value: "{{ .Username }}"}, "", "header name is empty"},
+		{"ShouldErrorOnInvalidTemplate", schema.ACLHeader{Name: "Remote-User", Value: "{{ .Username "}, "", "header 'Remote-User' has an invalid value template: template: Remote-User:1: unclosed action"},
+		{"ShouldErrorOnUnknownFunc", schema.ACLHeader{Name: "Remote-User", Value: "{{ abc .Username }}"}, "", "header 'Remote-User' has an invalid value template: template: Remote-User:1: function \"abc\" not defined"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			header, err := NewAccessControlHeader(tc.have)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, header.Name)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+
+	assert.Len(t, NewAccessControlHeaders([]schema.ACLHeader{{Name: "A", Value: "a"}, {Name: "", Value: "b"}}), 1)
+}
+
+func TestAuthorizerGetAudience(t *testing.T) {
+	authorizer := NewAuthorizer(&schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: deny,
+			Rules: []schema.ACLRule{
+				{
+					Domains:                   []string{"app.example.com"},
+					Policy:                    oneFactor,
+					IdentityAssertionAudience: []string{"app", "https://app.example.com"},
+				},
+				{
+					Domains: []string{"*.example.com"},
+					Policy:  oneFactor,
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, []string{"app", "https://app.example.com"}, authorizer.GetAudience(John, NewObject(&url.URL{Scheme: "https", Host: "app.example.com", Path: "/"}, "GET")))
+	assert.Equal(t, []string{"other.example.com"}, authorizer.GetAudience(John, NewObject(&url.URL{Scheme: "https", Host: "other.example.com", Path: "/"}, "GET")))
+	assert.Equal(t, []string{"example.org"}, authorizer.GetAudience(John, NewObject(&url.URL{Scheme: "https", Host: "example.org:8443", Path: "/"}, "GET")))
+}
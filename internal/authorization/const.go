@@ -15,8 +15,9 @@ const (
 )
 
 const (
-	prefixUser  = "user:"
-	prefixGroup = "group:"
+	prefixUser         = "user:"
+	prefixGroup        = "group:"
+	prefixOAuth2Client = "oauth2:client:"
 )
 
 const (
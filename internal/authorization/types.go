@@ -29,21 +29,26 @@ type ObjectMatcher interface {
 	IsMatch(object Object) (match bool)
 }
 
-// Subject represents the identity of a user for the purposes of ACL matching.
+// Subject represents the identity of a user or an OAuth 2.0 client for the purposes of ACL matching.
 type Subject struct {
 	Username string
 	Groups   []string
+	ClientID string
 	IP       net.IP
 }
 
 // String returns a string representation of the Subject.
 func (s Subject) String() string {
+	if s.ClientID != "" {
+		return fmt.Sprintf("client_id=%s ip=%s", s.ClientID, s.IP.String())
+	}
+
 	return fmt.Sprintf("username=%s groups=%s ip=%s", s.Username, strings.Join(s.Groups, ","), s.IP.String())
 }
 
-// IsAnonymous returns true if the Subject username and groups are empty.
+// IsAnonymous returns true if the Subject username, groups, and client id are empty.
 func (s Subject) IsAnonymous() bool {
-	return s.Username == "" && len(s.Groups) == 0
+	return s.Username == "" && len(s.Groups) == 0 && s.ClientID == ""
 }
 
 // Object represents a protected object for the purposes of ACL matching.
@@ -67,6 +67,12 @@ func schemaSubjectToACLSubject(subjectRule string) (subject SubjectMatcher) {
 		return AccessControlGroup{Name: group}
 	}
 
+	if strings.HasPrefix(subjectRule, prefixOAuth2Client) {
+		clientID := strings.Trim(subjectRule[len(prefixOAuth2Client):], " ")
+
+		return AccessControlClient{ID: clientID}
+	}
+
 	return nil
 }
 
@@ -114,6 +114,17 @@ This subcommand allows you to change the encryption key of an Authelia SQL datab
 	cmdAutheliaStorageEncryptionChangeKeyExample = `authelia storage encryption change-key --config config.yml --new-encryption-key 0e95cb49-5804-4ad9-be82-bb04a9ddecd8
 authelia storage encryption change-key --encryption-key b3453fde-ecc2-4a1f-9422-2707ddbed495 --new-encryption-key 0e95cb49-5804-4ad9-be82-bb04a9ddecd8 --postgres.host postgres --postgres.password autheliapw`
 
+	cmdAutheliaStorageEncryptionReEncryptShort = "Re-encrypts the database data with the encryption key"
+
+	cmdAutheliaStorageEncryptionReEncryptLong = `Re-encrypts the database data with the encryption key.
+
+This subcommand allows you to complete a rotation of the encryption key of an Authelia SQL database. The data encrypted
+with any of the previous encryption keys is encrypted again with the current encryption key. Data isn't re-encrypted when
+it's read, so this must be run before the previous encryption keys are removed from the configuration.`
+
+	cmdAutheliaStorageEncryptionReEncryptExample = `authelia storage encryption re-encrypt --config config.yml
+authelia storage encryption re-encrypt --encryption-key 0e95cb49-5804-4ad9-be82-bb04a9ddecd8 --previous-encryption-keys b3453fde-ecc2-4a1f-9422-2707ddbed495 --postgres.host postgres --postgres.password autheliapw`
+
 	cmdAutheliaStorageUserShort = "Manages user settings"
 
 	cmdAutheliaStorageUserLong = `Manages user settings.
@@ -236,6 +247,45 @@ authelia storage user webauthn delete --kid abc123
 authelia storage user webauthn delete --kid abc123 --config config.yml
 authelia storage user webauthn delete --kid abc123 --encryption-key b3453fde-ecc2-4a1f-9422-2707ddbed495 --postgres.host postgres --postgres.password autheliapw`
 
+	cmdAutheliaStorageUserOpenIDConnectShort = "Manage OpenID Connect 1.0 user settings"
+
+	cmdAutheliaStorageUserOpenIDConnectLong = `Manage OpenID Connect 1.0 user settings.
+
+This subcommand allows interacting with the OpenID Connect 1.0 settings of users.`
+
+	cmdAutheliaStorageUserOpenIDConnectExample = `authelia storage user oidc --help`
+
+	cmdAutheliaStorageUserOpenIDConnectConsentsShort = "Manage OpenID Connect 1.0 consents"
+
+	cmdAutheliaStorageUserOpenIDConnectConsentsLong = `Manage OpenID Connect 1.0 consents.
+
+This subcommand allows listing and revoking the OpenID Connect 1.0 consents users have granted to clients.`
+
+	cmdAutheliaStorageUserOpenIDConnectConsentsExample = `authelia storage user oidc consents --help`
+
+	cmdAutheliaStorageUserOpenIDConnectConsentsListShort = "List the OpenID Connect 1.0 consents of a user"
+
+	cmdAutheliaStorageUserOpenIDConnectConsentsListLong = `List the OpenID Connect 1.0 consents of a user.
+
+This subcommand allows listing the OpenID Connect 1.0 consents of a given user which have not been revoked and have
+not expired.`
+
+	cmdAutheliaStorageUserOpenIDConnectConsentsListExample = `authelia storage user oidc consents list john
+authelia storage user oidc consents list john --config config.yml
+authelia storage user oidc consents list john --encryption-key b3453fde-ecc2-4a1f-9422-2707ddbed495 --postgres.host postgres --postgres.password autheliapw`
+
+	cmdAutheliaStorageUserOpenIDConnectConsentsRevokeShort = "Revoke OpenID Connect 1.0 consents of a user"
+
+	cmdAutheliaStorageUserOpenIDConnectConsentsRevokeLong = `Revoke OpenID Connect 1.0 consents of a user.
+
+This subcommand allows revoking one or all of the OpenID Connect 1.0 consents of a given user. The access tokens and
+refresh tokens issued using a revoked consent are also revoked.`
+
+	cmdAutheliaStorageUserOpenIDConnectConsentsRevokeExample = `authelia storage user oidc consents revoke john --id 1
+authelia storage user oidc consents revoke john --id 1 --config config.yml
+authelia storage user oidc consents revoke john --all
+authelia storage user oidc consents revoke john --all --encryption-key b3453fde-ecc2-4a1f-9422-2707ddbed495 --postgres.host postgres --postgres.password autheliapw`
+
 	cmdAutheliaStorageUserTOTPShort = "Manage TOTP configurations"
 
 	cmdAutheliaStorageUserTOTPLong = `Manage TOTP configurations.
@@ -392,6 +442,93 @@ schema version of the database.`
 authelia storage migrate down --target 20 --config config.yml
 authelia storage migrate down --target 20 --encryption-key b3453fde-ecc2-4a1f-9422-2707ddbed495 --postgres.host postgres --postgres.password autheliapw`
 
+	cmdAutheliaSessionsShort = "Manage the active sessions of users"
+
+	cmdAutheliaSessionsLong = `Manage the active sessions of users.
+
+This subcommand allows listing and revoking the active sessions of a user directly from the database. Revoked sessions
+are signed out on their next request.`
+
+	cmdAutheliaSessionsExample = `authelia sessions --help`
+
+	cmdAutheliaSessionsListShort = "List the active sessions of a user"
+
+	cmdAutheliaSessionsListLong = `List the active sessions of a user.
+
+This subcommand allows listing the active sessions of a given user.`
+
+	cmdAutheliaSessionsListExample = `authelia sessions list john
+authelia sessions list john --config config.yml
+authelia sessions list john --encryption-key b3453fde-ecc2-4a1f-9422-2707ddbed495 --postgres.host postgres --postgres.password autheliapw`
+
+	cmdAutheliaSessionsRevokeShort = "Revoke all of the active sessions of a user"
+
+	cmdAutheliaSessionsRevokeLong = `Revoke all of the active sessions of a user.
+
+This subcommand allows revoking all of the active sessions of a given user.`
+
+	cmdAutheliaSessionsRevokeExample = `authelia sessions revoke john
+authelia sessions revoke john --config config.yml
+authelia sessions revoke john --encryption-key b3453fde-ecc2-4a1f-9422-2707ddbed495 --postgres.host postgres --postgres.password autheliapw`
+
+	cmdAutheliaOpenIDConnectShort = "Manage the OpenID Connect 1.0 provider"
+
+	cmdAutheliaOpenIDConnectLong = `Manage the OpenID Connect 1.0 provider.
+
+This subcommand allows managing the OpenID Connect 1.0 provider.`
+
+	cmdAutheliaOpenIDConnectExample = `authelia oidc --help`
+
+	cmdAutheliaOpenIDConnectClientsShort = "Manage the OpenID Connect 1.0 clients stored in the database"
+
+	cmdAutheliaOpenIDConnectClientsLong = `Manage the OpenID Connect 1.0 clients stored in the database.
+
+This subcommand allows creating, listing, rotating the secret of, and deleting the OpenID Connect 1.0 clients which are
+stored in the database. These clients are used alongside the clients in the configuration and do not require a restart.`
+
+	cmdAutheliaOpenIDConnectClientsExample = `authelia oidc clients --help`
+
+	cmdAutheliaOpenIDConnectClientsCreateShort = "Create an OpenID Connect 1.0 client"
+
+	cmdAutheliaOpenIDConnectClientsCreateLong = `Create an OpenID Connect 1.0 client.
+
+This subcommand allows creating an OpenID Connect 1.0 client in the database. If the id is omitted a random one is
+generated. The client is validated using the same rules as clients in the configuration and the generated secret is
+only displayed once.`
+
+	cmdAutheliaOpenIDConnectClientsCreateExample = `authelia oidc clients create myapp --redirect-uri https://app.example.com/oauth2/callback
+authelia oidc clients create myapp --redirect-uri https://app.example.com/oauth2/callback --config config.yml
+authelia oidc clients create --description "My App" --redirect-uri https://app.example.com/oauth2/callback --scope openid,profile,groups --policy one_factor`
+
+	cmdAutheliaOpenIDConnectClientsListShort = "List the OpenID Connect 1.0 clients"
+
+	cmdAutheliaOpenIDConnectClientsListLong = `List the OpenID Connect 1.0 clients.
+
+This subcommand allows listing the OpenID Connect 1.0 clients stored in the database.`
+
+	cmdAutheliaOpenIDConnectClientsListExample = `authelia oidc clients list
+authelia oidc clients list --config config.yml
+authelia oidc clients list --encryption-key b3453fde-ecc2-4a1f-9422-2707ddbed495 --postgres.host postgres --postgres.password autheliapw`
+
+	cmdAutheliaOpenIDConnectClientsRotateSecretShort = "Rotate the secret of an OpenID Connect 1.0 client"
+
+	cmdAutheliaOpenIDConnectClientsRotateSecretLong = `Rotate the secret of an OpenID Connect 1.0 client.
+
+This subcommand allows generating a new secret for an OpenID Connect 1.0 client stored in the database. The previous
+secret stops working immediately and the new secret is only displayed once.`
+
+	cmdAutheliaOpenIDConnectClientsRotateSecretExample = `authelia oidc clients rotate-secret myapp
+authelia oidc clients rotate-secret myapp --config config.yml`
+
+	cmdAutheliaOpenIDConnectClientsDeleteShort = "Delete an OpenID Connect 1.0 client"
+
+	cmdAutheliaOpenIDConnectClientsDeleteLong = `Delete an OpenID Connect 1.0 client.
+
+This subcommand allows deleting an OpenID Connect 1.0 client stored in the database.`
+
+	cmdAutheliaOpenIDConnectClientsDeleteExample = `authelia oidc clients delete myapp
+authelia oidc clients delete myapp --config config.yml`
+
 	cmdAutheliaValidateConfigShort = "Check a configuration against the internal configuration validation mechanisms"
 
 	cmdAutheliaValidateConfigLong = `Check a configuration against the internal configuration validation mechanisms.
@@ -626,20 +763,32 @@ const (
 	cmdFlagNamePath        = "path"
 	cmdFlagNameTarget      = "target"
 	cmdFlagNameDestroyData = "destroy-data"
-
-	cmdFlagNameEncryptionKey      = "encryption-key"
-	cmdFlagNameSQLite3Path        = "sqlite.path"
-	cmdFlagNameMySQLHost          = "mysql.host"
-	cmdFlagNameMySQLPort          = "mysql.port"
-	cmdFlagNameMySQLDatabase      = "mysql.database"
-	cmdFlagNameMySQLUsername      = "mysql.username"
-	cmdFlagNameMySQLPassword      = "mysql.password"
-	cmdFlagNamePostgreSQLHost     = "postgres.host"
-	cmdFlagNamePostgreSQLPort     = "postgres.port"
-	cmdFlagNamePostgreSQLDatabase = "postgres.database"
-	cmdFlagNamePostgreSQLSchema   = "postgres.schema"
-	cmdFlagNamePostgreSQLUsername = "postgres.username"
-	cmdFlagNamePostgreSQLPassword = "postgres.password"
+	cmdFlagNameID          = "id"
+
+	cmdFlagNameRedirectURI             = "redirect-uri"
+	cmdFlagNameScope                   = "scope"
+	cmdFlagNameGrantType               = "grant-type"
+	cmdFlagNameResponseType            = "response-type"
+	cmdFlagNameAudience                = "audience"
+	cmdFlagNameTokenEndpointAuthMethod = "token-endpoint-auth-method"
+	cmdFlagNamePolicy                  = "policy"
+	cmdFlagNameConsentMode             = "consent-mode"
+	cmdFlagNameSectorIdentifier        = "sector-identifier"
+
+	cmdFlagNameEncryptionKey          = "encryption-key"
+	cmdFlagNamePreviousEncryptionKeys = "previous-encryption-keys"
+	cmdFlagNameSQLite3Path            = "sqlite.path"
+	cmdFlagNameMySQLHost              = "mysql.host"
+	cmdFlagNameMySQLPort              = "mysql.port"
+	cmdFlagNameMySQLDatabase          = "mysql.database"
+	cmdFlagNameMySQLUsername          = "mysql.username"
+	cmdFlagNameMySQLPassword          = "mysql.password"
+	cmdFlagNamePostgreSQLHost         = "postgres.host"
+	cmdFlagNamePostgreSQLPort         = "postgres.port"
+	cmdFlagNamePostgreSQLDatabase     = "postgres.database"
+	cmdFlagNamePostgreSQLSchema       = "postgres.schema"
+	cmdFlagNamePostgreSQLUsername     = "postgres.username"
+	cmdFlagNamePostgreSQLPassword     = "postgres.password"
 )
 
 const (
@@ -146,7 +146,7 @@ func (ctx *CmdCtx) LoadProviders() (warns, errs []error) {
 		NTP:             ntp.NewProvider(&ctx.config.NTP),
 		PasswordPolicy:  middlewares.NewPasswordPolicyProvider(ctx.config.PasswordPolicy),
 		Regulator:       regulation.NewRegulator(ctx.config.Regulation, storage, utils.RealClock{}),
-		SessionProvider: session.NewProvider(ctx.config.Session, ctx.trusted),
+		SessionProvider: session.NewProvider(ctx.config.Session, ctx.trusted, storage),
 		StorageProvider: storage,
 		TOTP:            totp.NewTimeBasedProvider(ctx.config.TOTP),
 	}
@@ -175,6 +175,17 @@ func (ctx *CmdCtx) LoadProviders() (warns, errs []error) {
 		errs = append(errs, err)
 	}
 
+	if ctx.config.AccessControl.IdentityAssertion.Enable && providers.OpenIDConnect != nil {
+		// Without a dedicated key the active key of the OpenID Connect 1.0 Provider is resolved for each request.
+		if ctx.config.AccessControl.IdentityAssertion.PrivateKey == nil {
+			if providers.OpenIDConnect.KeyManager.GetActiveKey() == nil {
+				warns = append(warns, fmt.Errorf("access control: identity_assertion: no %s issuer private key is currently valid so the identity assertion can't be signed until one is", oidc.SigningAlgorithmRSAWithSHA256))
+			}
+		} else if providers.IdentityAssertion, err = providers.OpenIDConnect.KeyManager.AddJWK(schema.X509CertificateChain{}, ctx.config.AccessControl.IdentityAssertion.PrivateKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	if ctx.config.Telemetry.Metrics.Enabled {
 		providers.Metrics = metrics.NewPrometheus()
 	}
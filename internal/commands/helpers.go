@@ -119,3 +119,37 @@ func storageWebauthnDeleteRunEOptsFromFlags(flags *pflag.FlagSet, args []string)
 
 	return
 }
+
+func storageOpenIDConnectConsentsRevokeRunEOptsFromFlags(flags *pflag.FlagSet) (all bool, id int64, err error) {
+	f := 0
+
+	if flags.Changed(cmdFlagNameAll) {
+		if all, err = flags.GetBool(cmdFlagNameAll); err != nil {
+			return
+		}
+
+		f++
+	}
+
+	if flags.Changed(cmdFlagNameID) {
+		if id, err = flags.GetInt64(cmdFlagNameID); err != nil {
+			return
+		}
+
+		f++
+	}
+
+	if f > 1 {
+		err = fmt.Errorf("must only supply one of the flags --all and --id but %d were specified", f)
+
+		return
+	}
+
+	if f == 0 {
+		err = fmt.Errorf("must supply one of the flags --all or --id")
+
+		return
+	}
+
+	return
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+func newOpenIDConnectCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "oidc",
+		Short:   cmdAutheliaOpenIDConnectShort,
+		Long:    cmdAutheliaOpenIDConnectLong,
+		Example: cmdAutheliaOpenIDConnectExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newOpenIDConnectClientsCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newOpenIDConnectClientsCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "clients",
+		Short:   cmdAutheliaOpenIDConnectClientsShort,
+		Long:    cmdAutheliaOpenIDConnectClientsLong,
+		Example: cmdAutheliaOpenIDConnectClientsExample,
+		PersistentPreRunE: ctx.ChainRunE(
+			ctx.ConfigStorageCommandLineConfigRunE,
+			ctx.ConfigLoadRunE,
+			ctx.ConfigValidateStorageRunE,
+			ctx.ConfigValidateOpenIDConnectRunE,
+			ctx.LoadProvidersStorageRunE,
+		),
+		Args: cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagsStorage(cmd)
+
+	cmd.AddCommand(
+		newOpenIDConnectClientsCreateCmd(ctx),
+		newOpenIDConnectClientsListCmd(ctx),
+		newOpenIDConnectClientsRotateSecretCmd(ctx),
+		newOpenIDConnectClientsDeleteCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newOpenIDConnectClientsCreateCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "create [id]",
+		Short:   cmdAutheliaOpenIDConnectClientsCreateShort,
+		Long:    cmdAutheliaOpenIDConnectClientsCreateLong,
+		Example: cmdAutheliaOpenIDConnectClientsCreateExample,
+		RunE:    ctx.OpenIDConnectClientsCreateRunE,
+		Args:    cobra.MaximumNArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().String(cmdFlagNameDescription, "", "the description of the client")
+	cmd.Flags().StringSlice(cmdFlagNameRedirectURI, nil, "the redirect uris of the client")
+	cmd.Flags().StringSlice(cmdFlagNameScope, nil, "the scopes the client is allowed to request")
+	cmd.Flags().StringSlice(cmdFlagNameGrantType, nil, "the grant types the client is allowed to use")
+	cmd.Flags().StringSlice(cmdFlagNameResponseType, nil, "the response types the client is allowed to use")
+	cmd.Flags().StringSlice(cmdFlagNameAudience, nil, "the audiences the client is allowed to request")
+	cmd.Flags().String(cmdFlagNameTokenEndpointAuthMethod, oidc.ClientAuthMethodClientSecretBasic, "the token endpoint auth method of the client, the value 'none' creates a public client")
+	cmd.Flags().String(cmdFlagNamePolicy, "", "the authorization policy of the client, options are 'one_factor' and 'two_factor'")
+	cmd.Flags().String(cmdFlagNameConsentMode, "", "the consent mode of the client, options are 'auto', 'explicit', 'implicit', and 'pre-configured'")
+	cmd.Flags().String(cmdFlagNameSectorIdentifier, "", "the sector identifier of the client")
+
+	return cmd
+}
+
+func newOpenIDConnectClientsListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "list",
+		Short:   cmdAutheliaOpenIDConnectClientsListShort,
+		Long:    cmdAutheliaOpenIDConnectClientsListLong,
+		Example: cmdAutheliaOpenIDConnectClientsListExample,
+		RunE:    ctx.OpenIDConnectClientsListRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newOpenIDConnectClientsRotateSecretCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "rotate-secret <id>",
+		Short:   cmdAutheliaOpenIDConnectClientsRotateSecretShort,
+		Long:    cmdAutheliaOpenIDConnectClientsRotateSecretLong,
+		Example: cmdAutheliaOpenIDConnectClientsRotateSecretExample,
+		RunE:    ctx.OpenIDConnectClientsRotateSecretRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newOpenIDConnectClientsDeleteCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "delete <id>",
+		Short:   cmdAutheliaOpenIDConnectClientsDeleteShort,
+		Long:    cmdAutheliaOpenIDConnectClientsDeleteLong,
+		Example: cmdAutheliaOpenIDConnectClientsDeleteExample,
+		RunE:    ctx.OpenIDConnectClientsDeleteRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
@@ -0,0 +1,313 @@
+package commands
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+// ConfigValidateOpenIDConnectRunE validates the OpenID Connect 1.0 config before running commands using it.
+func (ctx *CmdCtx) ConfigValidateOpenIDConnectRunE(_ *cobra.Command, _ []string) (err error) {
+	if ctx.config.IdentityProviders.OIDC == nil {
+		return errors.New("identity_providers: oidc: the OpenID Connect 1.0 provider is not configured")
+	}
+
+	val := schema.NewStructValidator()
+
+	validator.ValidateIdentityProviders(&ctx.config.IdentityProviders, val)
+
+	return joinValidatorErrors(val.Errors())
+}
+
+// OpenIDConnectClientsCreateRunE is the RunE for the authelia oidc clients create command.
+func (ctx *CmdCtx) OpenIDConnectClientsCreateRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var metadata oidc.ClientRegistrationMetadata
+
+	if metadata, err = openIDConnectClientMetadataFromFlags(cmd); err != nil {
+		return err
+	}
+
+	id := uuid.New().String()
+
+	if len(args) != 0 {
+		id = args[0]
+	}
+
+	for _, c := range ctx.config.IdentityProviders.OIDC.Clients {
+		if c.ID == id {
+			return fmt.Errorf("client with id '%s' already exists in the configuration", id)
+		}
+	}
+
+	if _, err = ctx.providers.StorageProvider.LoadOAuth2Client(ctx, id); err == nil {
+		return fmt.Errorf("client with id '%s' already exists in the storage", id)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to check if client with id '%s' exists: %w", id, err)
+	}
+
+	var (
+		secret string
+		digest *schema.PasswordDigest
+		config schema.OpenIDConnectClientConfiguration
+	)
+
+	switch metadata.TokenEndpointAuthMethod {
+	case oidc.ClientAuthMethodNone, oidc.ClientAuthMethodPrivateKeyJWT:
+		break
+	default:
+		if secret, digest, err = oidc.GenerateClientSecret(metadata.TokenEndpointAuthMethod); err != nil {
+			return fmt.Errorf("failed to generate the secret for client with id '%s': %w", id, err)
+		}
+	}
+
+	if config, err = metadata.ToConfiguration(id, digest); err != nil {
+		return err
+	}
+
+	val := schema.NewStructValidator()
+
+	validator.ValidateIdentityProvidersOpenIDConnectClient(&config, ctx.config.IdentityProviders.OIDC, val)
+
+	if err = joinValidatorErrors(val.Errors()); err != nil {
+		return fmt.Errorf("failed to validate the client with id '%s': %w", id, err)
+	}
+
+	client := model.OAuth2Client{
+		ClientID:  id,
+		CreatedAt: time.Now(),
+		Source:    oidc.ClientSourceCLI,
+	}
+
+	client.UpdatedAt = client.CreatedAt
+
+	if digest != nil {
+		client.Secret = []byte(digest.Encode())
+	}
+
+	if client.Metadata, err = json.Marshal(oidc.NewClientRegistrationMetadata(config)); err != nil {
+		return fmt.Errorf("failed to marshal the metadata of client with id '%s': %w", id, err)
+	}
+
+	if err = ctx.providers.StorageProvider.SaveOAuth2Client(ctx, client); err != nil {
+		return fmt.Errorf("failed to save the client with id '%s': %w", id, err)
+	}
+
+	fmt.Printf("Successfully created client with id '%s'\n", id)
+
+	if secret != "" {
+		fmt.Printf("Client Secret: %s\n", secret)
+	}
+
+	return nil
+}
+
+// OpenIDConnectClientsListRunE is the RunE for the authelia oidc clients list command.
+func (ctx *CmdCtx) OpenIDConnectClientsListRunE(_ *cobra.Command, _ []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var (
+		clients []model.OAuth2Client
+		count   int
+	)
+
+	limit := 10
+
+	for page := 0; true; page++ {
+		if clients, err = ctx.providers.StorageProvider.LoadOAuth2Clients(ctx, limit, page); err != nil {
+			return fmt.Errorf("can't list clients: %w", err)
+		}
+
+		if page == 0 && len(clients) == 0 {
+			return errors.New("no clients exist in the storage")
+		}
+
+		if page == 0 {
+			fmt.Printf("ID\tDescription\tSource\tCreated At\tUpdated At\n")
+		}
+
+		for _, client := range clients {
+			var metadata oidc.ClientRegistrationMetadata
+
+			if err = json.Unmarshal(client.Metadata, &metadata); err != nil {
+				return fmt.Errorf("failed to unmarshal the metadata of client with id '%s': %w", client.ClientID, err)
+			}
+
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n", client.ClientID, metadata.ClientName, client.Source,
+				client.CreatedAt.Format(time.RFC3339), client.UpdatedAt.Format(time.RFC3339))
+		}
+
+		count += len(clients)
+
+		if len(clients) < limit {
+			break
+		}
+	}
+
+	fmt.Printf("\nTotal: %d\n", count)
+
+	return nil
+}
+
+// OpenIDConnectClientsRotateSecretRunE is the RunE for the authelia oidc clients rotate-secret command.
+func (ctx *CmdCtx) OpenIDConnectClientsRotateSecretRunE(_ *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var (
+		client   *model.OAuth2Client
+		metadata oidc.ClientRegistrationMetadata
+		secret   string
+		digest   *schema.PasswordDigest
+	)
+
+	id := args[0]
+
+	if client, err = ctx.providers.StorageProvider.LoadOAuth2Client(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("client with id '%s' does not exist in the storage", id)
+		}
+
+		return fmt.Errorf("failed to load the client with id '%s': %w", id, err)
+	}
+
+	if err = json.Unmarshal(client.Metadata, &metadata); err != nil {
+		return fmt.Errorf("failed to unmarshal the metadata of client with id '%s': %w", id, err)
+	}
+
+	switch metadata.TokenEndpointAuthMethod {
+	case oidc.ClientAuthMethodNone, oidc.ClientAuthMethodPrivateKeyJWT:
+		return fmt.Errorf("client with id '%s' does not use a secret as it has the token endpoint auth method '%s'", id, metadata.TokenEndpointAuthMethod)
+	}
+
+	if secret, digest, err = oidc.GenerateClientSecret(metadata.TokenEndpointAuthMethod); err != nil {
+		return fmt.Errorf("failed to generate the secret for client with id '%s': %w", id, err)
+	}
+
+	client.Secret = []byte(digest.Encode())
+	client.UpdatedAt = time.Now()
+
+	if err = ctx.providers.StorageProvider.UpdateOAuth2Client(ctx, *client); err != nil {
+		return fmt.Errorf("failed to save the client with id '%s': %w", id, err)
+	}
+
+	fmt.Printf("Successfully rotated the secret for client with id '%s'\n", id)
+	fmt.Printf("Client Secret: %s\n", secret)
+
+	return nil
+}
+
+// OpenIDConnectClientsDeleteRunE is the RunE for the authelia oidc clients delete command.
+func (ctx *CmdCtx) OpenIDConnectClientsDeleteRunE(_ *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	id := args[0]
+
+	if err = ctx.providers.StorageProvider.DeleteOAuth2Client(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("client with id '%s' does not exist in the storage", id)
+		}
+
+		return fmt.Errorf("failed to delete the client with id '%s': %w", id, err)
+	}
+
+	fmt.Printf("Successfully deleted client with id '%s'\n", id)
+
+	return nil
+}
+
+func openIDConnectClientMetadataFromFlags(cmd *cobra.Command) (metadata oidc.ClientRegistrationMetadata, err error) {
+	var scopes []string
+
+	if metadata.ClientName, err = cmd.Flags().GetString(cmdFlagNameDescription); err != nil {
+		return metadata, err
+	}
+
+	if metadata.RedirectURIs, err = cmd.Flags().GetStringSlice(cmdFlagNameRedirectURI); err != nil {
+		return metadata, err
+	}
+
+	if scopes, err = cmd.Flags().GetStringSlice(cmdFlagNameScope); err != nil {
+		return metadata, err
+	}
+
+	metadata.Scope = strings.Join(scopes, " ")
+
+	if metadata.GrantTypes, err = cmd.Flags().GetStringSlice(cmdFlagNameGrantType); err != nil {
+		return metadata, err
+	}
+
+	if metadata.ResponseTypes, err = cmd.Flags().GetStringSlice(cmdFlagNameResponseType); err != nil {
+		return metadata, err
+	}
+
+	if metadata.Audience, err = cmd.Flags().GetStringSlice(cmdFlagNameAudience); err != nil {
+		return metadata, err
+	}
+
+	if metadata.TokenEndpointAuthMethod, err = cmd.Flags().GetString(cmdFlagNameTokenEndpointAuthMethod); err != nil {
+		return metadata, err
+	}
+
+	if metadata.Policy, err = cmd.Flags().GetString(cmdFlagNamePolicy); err != nil {
+		return metadata, err
+	}
+
+	if metadata.ConsentMode, err = cmd.Flags().GetString(cmdFlagNameConsentMode); err != nil {
+		return metadata, err
+	}
+
+	if metadata.SectorIdentifier, err = cmd.Flags().GetString(cmdFlagNameSectorIdentifier); err != nil {
+		return metadata, err
+	}
+
+	return metadata, nil
+}
+
+func joinValidatorErrors(errs []error) (err error) {
+	for i, e := range errs {
+		if i == 0 {
+			err = e
+			continue
+		}
+
+		err = fmt.Errorf("%w, %v", err, e)
+	}
+
+	return err
+}
@@ -53,6 +53,8 @@ func NewRootCmd() (cmd *cobra.Command) {
 		newAccessControlCommand(ctx),
 		newBuildInfoCmd(ctx),
 		newCryptoCmd(ctx),
+		newOpenIDConnectCmd(ctx),
+		newSessionsCmd(ctx),
 		newStorageCmd(ctx),
 		newValidateConfigCmd(ctx),
 
@@ -293,6 +295,12 @@ func doStartupChecks(ctx *CmdCtx) {
 		failures = append(failures, "user")
 	}
 
+	if err = doStartupCheck(ctx, "session", ctx.providers.SessionProvider, false); err != nil {
+		ctx.log.Errorf("Failure running the session provider startup check: %+v", err)
+
+		failures = append(failures, "session")
+	}
+
 	if err = doStartupCheck(ctx, "notification", ctx.providers.Notifier, ctx.config.Notifier.DisableStartupCheck); err != nil {
 		ctx.log.Errorf("Failure running the notification provider startup check: %+v", err)
 
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newSessionsCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "sessions",
+		Short:   cmdAutheliaSessionsShort,
+		Long:    cmdAutheliaSessionsLong,
+		Example: cmdAutheliaSessionsExample,
+		PersistentPreRunE: ctx.ChainRunE(
+			ctx.ConfigStorageCommandLineConfigRunE,
+			ctx.ConfigLoadRunE,
+			ctx.ConfigValidateStorageRunE,
+			ctx.LoadProvidersStorageRunE,
+		),
+		Args: cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmdFlagsStorage(cmd)
+
+	cmd.AddCommand(
+		newSessionsListCmd(ctx),
+		newSessionsRevokeCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newSessionsListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "list <username>",
+		Short:   cmdAutheliaSessionsListShort,
+		Long:    cmdAutheliaSessionsListLong,
+		Example: cmdAutheliaSessionsListExample,
+		RunE:    ctx.SessionsListRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newSessionsRevokeCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "revoke <username>",
+		Short:   cmdAutheliaSessionsRevokeShort,
+		Long:    cmdAutheliaSessionsRevokeLong,
+		Example: cmdAutheliaSessionsRevokeExample,
+		RunE:    ctx.SessionsRevokeRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
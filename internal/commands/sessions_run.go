@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// SessionsListRunE is the RunE for the authelia sessions list command.
+func (ctx *CmdCtx) SessionsListRunE(_ *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var sessions []model.ActiveSession
+
+	user := args[0]
+
+	if sessions, err = ctx.providers.StorageProvider.LoadActiveSessionsByUsername(ctx, user, time.Now()); err != nil {
+		return fmt.Errorf("can't list sessions for user '%s': %w", user, err)
+	}
+
+	if len(sessions) == 0 {
+		return fmt.Errorf("user '%s' has no active sessions", user)
+	}
+
+	fmt.Printf("Active Sessions for user '%s':\n\n", user)
+	fmt.Printf("ID\tCookie Domain\tCreated At\tLast Activity At\tExpires At\tIP\tAuthentication Level\tUser Agent\n")
+
+	for _, s := range sessions {
+		fmt.Printf("%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.ID, s.CookieDomain,
+			s.CreatedAt.Format(time.RFC3339), s.LastActivityAt.Format(time.RFC3339), s.ExpiresAt.Format(time.RFC3339),
+			s.IP.IP, authentication.Level(s.AuthLevel), s.UserAgent)
+	}
+
+	return nil
+}
+
+// SessionsRevokeRunE is the RunE for the authelia sessions revoke command.
+func (ctx *CmdCtx) SessionsRevokeRunE(_ *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var revoked int64
+
+	user := args[0]
+
+	if revoked, err = ctx.providers.StorageProvider.RevokeActiveSessionsByUsername(ctx, user); err != nil {
+		return fmt.Errorf("failed to revoke sessions for user '%s': %w", user, err)
+	}
+
+	fmt.Printf("Successfully revoked %d sessions for user '%s'\n", revoked, user)
+
+	return nil
+}
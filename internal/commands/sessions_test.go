@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func newTestCmdCtxSQLite(t *testing.T) (ctx *CmdCtx, path string) {
+	path = filepath.Join(t.TempDir(), "db.sqlite3")
+
+	ctx = NewCmdCtx()
+
+	ctx.config.Storage = schema.StorageConfiguration{
+		EncryptionKey: "a-long-encryption-key-used-only-for-tests",
+		Local:         &schema.LocalStorageConfiguration{Path: path},
+	}
+
+	ctx.providers.StorageProvider = newTestCmdCtxSQLiteStorage(t, ctx)
+
+	return ctx, path
+}
+
+func newTestCmdCtxSQLiteStorage(t *testing.T, ctx *CmdCtx) storage.Provider {
+	provider := storage.NewSQLiteProvider(ctx.config)
+
+	require.NoError(t, provider.StartupCheck())
+
+	return provider
+}
+
+func captureTestStdout(t *testing.T, fn func() error) (output string, err error) {
+	stdout := os.Stdout
+
+	r, w, perr := os.Pipe()
+	require.NoError(t, perr)
+
+	os.Stdout = w
+
+	defer func() {
+		os.Stdout = stdout
+	}()
+
+	err = fn()
+
+	require.NoError(t, w.Close())
+
+	buf := &bytes.Buffer{}
+
+	_, cerr := io.Copy(buf, r)
+	require.NoError(t, cerr)
+
+	return buf.String(), err
+}
+
+func TestSessionsListRunE(t *testing.T) {
+	ctx, _ := newTestCmdCtxSQLite(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, ctx.providers.StorageProvider.SaveActiveSession(ctx, model.ActiveSession{
+		SessionID:      "abc",
+		CookieDomain:   "example.com",
+		Username:       "john",
+		CreatedAt:      now,
+		LastActivityAt: now,
+		ExpiresAt:      now.Add(time.Hour),
+		IP:             model.NewIP(net.ParseIP("192.168.0.1")),
+		UserAgent:      "Mozilla/5.0",
+		AuthLevel:      2,
+	}))
+
+	output, err := captureTestStdout(t, func() error {
+		return ctx.SessionsListRunE(newSessionsListCmd(ctx), []string{"john"})
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, output, "Active Sessions for user 'john':")
+	assert.Contains(t, output, "example.com\t"+now.Format(time.RFC3339))
+	assert.Contains(t, output, "192.168.0.1\ttwo_factor\tMozilla/5.0")
+}
+
+func TestSessionsListRunEShouldErrorWithoutSessions(t *testing.T) {
+	ctx, _ := newTestCmdCtxSQLite(t)
+
+	_, err := captureTestStdout(t, func() error {
+		return ctx.SessionsListRunE(newSessionsListCmd(ctx), []string{"john"})
+	})
+
+	assert.EqualError(t, err, "user 'john' has no active sessions")
+}
+
+func TestSessionsRevokeRunE(t *testing.T) {
+	ctx, _ := newTestCmdCtxSQLite(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, session := range []model.ActiveSession{
+		{SessionID: "abc", Username: "john"},
+		{SessionID: "def", Username: "john"},
+		{SessionID: "ghi", Username: "harry"},
+	} {
+		session.CookieDomain = "example.com"
+		session.CreatedAt, session.LastActivityAt, session.ExpiresAt = now, now, now.Add(time.Hour)
+		session.IP = model.NewIP(net.ParseIP("192.168.0.1"))
+
+		require.NoError(t, ctx.providers.StorageProvider.SaveActiveSession(ctx, session))
+	}
+
+	output, err := captureTestStdout(t, func() error {
+		return ctx.SessionsRevokeRunE(newSessionsRevokeCmd(ctx), []string{"john"})
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Successfully revoked 2 sessions for user 'john'\n", output)
+
+	// The command closes the storage provider so it's opened again to check the result.
+	provider := newTestCmdCtxSQLiteStorage(t, ctx)
+
+	defer provider.Close()
+
+	sessions, err := provider.LoadActiveSessionsByUsername(ctx, "john", now)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 0)
+
+	sessions, err = provider.LoadActiveSessionsByUsername(ctx, "harry", now)
+	require.NoError(t, err)
+	assert.Len(t, sessions, 1)
+}
+
+func TestSessionsCmdShouldRequireUsername(t *testing.T) {
+	ctx := NewCmdCtx()
+
+	for _, cmd := range []*cobra.Command{newSessionsListCmd(ctx), newSessionsRevokeCmd(ctx)} {
+		assert.EqualError(t, cmd.Args(cmd, nil), "accepts 1 arg(s), received 0")
+		assert.NoError(t, cmd.Args(cmd, []string{"john"}))
+	}
+}
@@ -26,7 +26,21 @@ func newStorageCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		DisableAutoGenTag: true,
 	}
 
+	cmdFlagsStorage(cmd)
+
+	cmd.AddCommand(
+		newStorageMigrateCmd(ctx),
+		newStorageSchemaInfoCmd(ctx),
+		newStorageEncryptionCmd(ctx),
+		newStorageUserCmd(ctx),
+	)
+
+	return cmd
+}
+
+func cmdFlagsStorage(cmd *cobra.Command) {
 	cmd.PersistentFlags().String(cmdFlagNameEncryptionKey, "", "the storage encryption key to use")
+	cmd.PersistentFlags().StringSlice(cmdFlagNamePreviousEncryptionKeys, nil, "the previous storage encryption keys to use for decryption")
 
 	cmd.PersistentFlags().String(cmdFlagNameSQLite3Path, "", "the SQLite database path")
 
@@ -46,15 +60,6 @@ func newStorageCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd.PersistentFlags().String("postgres.ssl.root_certificate", "", "the PostgreSQL ssl root certificate file location")
 	cmd.PersistentFlags().String("postgres.ssl.certificate", "", "the PostgreSQL ssl certificate file location")
 	cmd.PersistentFlags().String("postgres.ssl.key", "", "the PostgreSQL ssl key file location")
-
-	cmd.AddCommand(
-		newStorageMigrateCmd(ctx),
-		newStorageSchemaInfoCmd(ctx),
-		newStorageEncryptionCmd(ctx),
-		newStorageUserCmd(ctx),
-	)
-
-	return cmd
 }
 
 func newStorageEncryptionCmd(ctx *CmdCtx) (cmd *cobra.Command) {
@@ -71,6 +76,7 @@ func newStorageEncryptionCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd.AddCommand(
 		newStorageEncryptionChangeKeyCmd(ctx),
 		newStorageEncryptionCheckCmd(ctx),
+		newStorageEncryptionReEncryptCmd(ctx),
 	)
 
 	return cmd
@@ -110,6 +116,21 @@ func newStorageEncryptionChangeKeyCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	return cmd
 }
 
+func newStorageEncryptionReEncryptCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "re-encrypt",
+		Short:   cmdAutheliaStorageEncryptionReEncryptShort,
+		Long:    cmdAutheliaStorageEncryptionReEncryptLong,
+		Example: cmdAutheliaStorageEncryptionReEncryptExample,
+		RunE:    ctx.StorageSchemaEncryptionReEncryptRunE,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
 func newStorageUserCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:     "user",
@@ -125,6 +146,7 @@ func newStorageUserCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 		newStorageUserIdentifiersCmd(ctx),
 		newStorageUserTOTPCmd(ctx),
 		newStorageUserWebauthnCmd(ctx),
+		newStorageUserOpenIDConnectCmd(ctx),
 	)
 
 	return cmd
@@ -308,6 +330,76 @@ func newStorageUserWebauthnDeleteCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	return cmd
 }
 
+func newStorageUserOpenIDConnectCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "oidc",
+		Short:   cmdAutheliaStorageUserOpenIDConnectShort,
+		Long:    cmdAutheliaStorageUserOpenIDConnectLong,
+		Example: cmdAutheliaStorageUserOpenIDConnectExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newStorageUserOpenIDConnectConsentsCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newStorageUserOpenIDConnectConsentsCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "consents",
+		Short:   cmdAutheliaStorageUserOpenIDConnectConsentsShort,
+		Long:    cmdAutheliaStorageUserOpenIDConnectConsentsLong,
+		Example: cmdAutheliaStorageUserOpenIDConnectConsentsExample,
+		Args:    cobra.NoArgs,
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.AddCommand(
+		newStorageUserOpenIDConnectConsentsListCmd(ctx),
+		newStorageUserOpenIDConnectConsentsRevokeCmd(ctx),
+	)
+
+	return cmd
+}
+
+func newStorageUserOpenIDConnectConsentsListCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "list <username>",
+		Short:   cmdAutheliaStorageUserOpenIDConnectConsentsListShort,
+		Long:    cmdAutheliaStorageUserOpenIDConnectConsentsListLong,
+		Example: cmdAutheliaStorageUserOpenIDConnectConsentsListExample,
+		RunE:    ctx.StorageUserOpenIDConnectConsentsListRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	return cmd
+}
+
+func newStorageUserOpenIDConnectConsentsRevokeCmd(ctx *CmdCtx) (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:     "revoke <username>",
+		Short:   cmdAutheliaStorageUserOpenIDConnectConsentsRevokeShort,
+		Long:    cmdAutheliaStorageUserOpenIDConnectConsentsRevokeLong,
+		Example: cmdAutheliaStorageUserOpenIDConnectConsentsRevokeExample,
+		RunE:    ctx.StorageUserOpenIDConnectConsentsRevokeRunE,
+		Args:    cobra.ExactArgs(1),
+
+		DisableAutoGenTag: true,
+	}
+
+	cmd.Flags().Int64(cmdFlagNameID, 0, "revoke a users consent by id")
+	cmd.Flags().Bool(cmdFlagNameAll, false, "revoke all of the users consents")
+
+	return cmd
+}
+
 func newStorageUserTOTPCmd(ctx *CmdCtx) (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:     "totp",
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
@@ -52,7 +54,8 @@ func (ctx *CmdCtx) LoadProvidersStorageRunE(cmd *cobra.Command, args []string) (
 // ConfigStorageCommandLineConfigRunE configures the storage command mapping.
 func (ctx *CmdCtx) ConfigStorageCommandLineConfigRunE(cmd *cobra.Command, _ []string) (err error) {
 	flagsMap := map[string]string{
-		cmdFlagNameEncryptionKey: "storage.encryption_key",
+		cmdFlagNameEncryptionKey:          "storage.encryption_key",
+		cmdFlagNamePreviousEncryptionKeys: "storage.previous_encryption_keys",
 
 		cmdFlagNameSQLite3Path: "storage.local.path",
 
@@ -174,7 +177,7 @@ func (ctx *CmdCtx) StorageSchemaEncryptionCheckRunE(cmd *cobra.Command, args []s
 			for _, name := range tables {
 				table := result.Tables[name]
 
-				fmt.Printf("\n\n\tTable (%s): %s\n\t\tInvalid Rows: %d\n\t\tTotal Rows: %d", name, table.ResultDescriptor(), table.Invalid, table.Total)
+				fmt.Printf("\n\n\tTable (%s): %s\n\t\tInvalid Rows: %d\n\t\tPrevious Key Rows: %d\n\t\tTotal Rows: %d", name, table.ResultDescriptor(), table.Invalid, table.Stale, table.Total)
 			}
 
 			fmt.Printf("\n")
@@ -236,6 +239,35 @@ func (ctx *CmdCtx) StorageSchemaEncryptionChangeKeyRunE(cmd *cobra.Command, args
 	return nil
 }
 
+// StorageSchemaEncryptionReEncryptRunE is the RunE for the authelia storage encryption re-encrypt command.
+func (ctx *CmdCtx) StorageSchemaEncryptionReEncryptRunE(_ *cobra.Command, _ []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	var version int
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	if version, err = ctx.providers.StorageProvider.SchemaVersion(ctx); err != nil {
+		return err
+	}
+
+	if version <= 0 {
+		return errors.New("schema version must be at least version 1 to re-encrypt the data")
+	}
+
+	if err = ctx.providers.StorageProvider.SchemaEncryptionReEncrypt(ctx); err != nil {
+		return err
+	}
+
+	fmt.Println("Completed re-encrypting the data with the encryption key. The previous encryption keys can now be removed from your configuration.")
+
+	return nil
+}
+
 // StorageMigrateHistoryRunE is the RunE for the authelia storage migrate history command.
 func (ctx *CmdCtx) StorageMigrateHistoryRunE(_ *cobra.Command, _ []string) (err error) {
 	defer func() {
@@ -654,6 +686,120 @@ func (ctx *CmdCtx) StorageUserWebauthnDeleteRunE(cmd *cobra.Command, args []stri
 	return nil
 }
 
+// StorageUserOpenIDConnectConsentsListRunE is the RunE for the authelia storage user oidc consents list command.
+func (ctx *CmdCtx) StorageUserOpenIDConnectConsentsListRunE(_ *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var configs []model.OAuth2ConsentPreConfig
+
+	user := args[0]
+
+	if configs, err = ctx.providers.StorageProvider.LoadOAuth2ConsentPreConfigurationsByUsername(ctx, user); err != nil {
+		return fmt.Errorf("can't list consents for user '%s': %w", user, err)
+	}
+
+	if len(configs) == 0 {
+		return fmt.Errorf("user '%s' has no consents", user)
+	}
+
+	fmt.Printf("OpenID Connect 1.0 Consents for user '%s':\n\n", user)
+	fmt.Printf("ID\tClient ID\tCreated At\tExpires At\tScopes\tAudience\n")
+
+	for _, config := range configs {
+		expires := "never"
+
+		if config.ExpiresAt.Valid {
+			expires = config.ExpiresAt.Time.Format(time.RFC3339)
+		}
+
+		fmt.Printf("%d\t%s\t%s\t%s\t%s\t%s\n", config.ID, config.ClientID, config.CreatedAt.Format(time.RFC3339), expires,
+			strings.Join(config.Scopes, " "), strings.Join(config.Audience, " "))
+	}
+
+	return nil
+}
+
+// StorageUserOpenIDConnectConsentsRevokeRunE is the RunE for the authelia storage user oidc consents revoke command.
+func (ctx *CmdCtx) StorageUserOpenIDConnectConsentsRevokeRunE(cmd *cobra.Command, args []string) (err error) {
+	defer func() {
+		_ = ctx.providers.StorageProvider.Close()
+	}()
+
+	var (
+		all bool
+		id  int64
+	)
+
+	if all, id, err = storageOpenIDConnectConsentsRevokeRunEOptsFromFlags(cmd.Flags()); err != nil {
+		return err
+	}
+
+	if err = ctx.CheckSchema(); err != nil {
+		return storageWrapCheckSchemaErr(err)
+	}
+
+	var configs []model.OAuth2ConsentPreConfig
+
+	user := args[0]
+
+	if configs, err = ctx.providers.StorageProvider.LoadOAuth2ConsentPreConfigurationsByUsername(ctx, user); err != nil {
+		return fmt.Errorf("can't list consents for user '%s': %w", user, err)
+	}
+
+	revoked := 0
+
+	for _, config := range configs {
+		if !all && config.ID != id {
+			continue
+		}
+
+		if err = ctx.storageRevokeOpenIDConnectConsent(config.ID); err != nil {
+			return fmt.Errorf("failed to revoke consent with id '%d' for user '%s': %w", config.ID, user, err)
+		}
+
+		revoked++
+	}
+
+	switch {
+	case all:
+		fmt.Printf("Successfully revoked %d consents for user '%s'\n", revoked, user)
+	case revoked == 0:
+		return fmt.Errorf("user '%s' has no consent with id '%d'", user, id)
+	default:
+		fmt.Printf("Successfully revoked consent with id '%d' for user '%s'\n", id, user)
+	}
+
+	return nil
+}
+
+func (ctx *CmdCtx) storageRevokeOpenIDConnectConsent(id int64) (err error) {
+	provider := ctx.providers.StorageProvider
+
+	var c context.Context
+
+	if c, err = provider.BeginTX(ctx); err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	if err = provider.RevokeOAuth2ConsentPreConfiguration(c, id); err == nil {
+		err = provider.RevokeOAuth2SessionsByConsentPreConfiguration(c, id)
+	}
+
+	if err != nil {
+		_ = provider.Rollback(c)
+
+		return err
+	}
+
+	return provider.Commit(c)
+}
+
 // StorageUserTOTPGenerateRunE is the RunE for the authelia storage user totp generate command.
 func (ctx *CmdCtx) StorageUserTOTPGenerateRunE(cmd *cobra.Command, args []string) (err error) {
 	defer func() {
@@ -0,0 +1,220 @@
+package commands
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// seedTestStorageUserOpenIDConnectConsents saves a consent pre-configuration for each of the given clients for the
+// user 'john', and an access token and refresh token session granted using each of them. It returns the ids of the
+// consent pre-configurations in the same order as the clients.
+func seedTestStorageUserOpenIDConnectConsents(t *testing.T, ctx *CmdCtx, now time.Time, clients ...string) (ids []int64) {
+	provider := ctx.providers.StorageProvider
+
+	subject := uuid.New()
+
+	require.NoError(t, provider.SaveUserOpaqueIdentifier(ctx, model.UserOpaqueIdentifier{Service: "openid", Username: "john", Identifier: subject}))
+
+	for _, client := range clients {
+		id, err := provider.SaveOAuth2ConsentPreConfiguration(ctx, model.OAuth2ConsentPreConfig{
+			ClientID:  client,
+			Subject:   subject,
+			CreatedAt: now,
+			ExpiresAt: sql.NullTime{Time: now.Add(time.Hour), Valid: true},
+			Scopes:    []string{"openid", "profile"},
+			Audience:  []string{client},
+		})
+		require.NoError(t, err)
+
+		challengeID := uuid.New()
+
+		require.NoError(t, provider.SaveOAuth2ConsentSession(ctx, model.OAuth2ConsentSession{
+			ChallengeID:      challengeID,
+			ClientID:         client,
+			Subject:          uuid.NullUUID{UUID: subject, Valid: true},
+			RequestedAt:      now,
+			PreConfiguration: sql.NullInt64{Int64: id, Valid: true},
+		}))
+
+		for _, sessionType := range []storage.OAuth2SessionType{storage.OAuth2SessionTypeAccessToken, storage.OAuth2SessionTypeRefreshToken} {
+			require.NoError(t, provider.SaveOAuth2Session(ctx, sessionType, model.OAuth2Session{
+				ChallengeID: uuid.NullUUID{UUID: challengeID, Valid: true},
+				RequestID:   "request-" + client,
+				ClientID:    client,
+				Signature:   "signature-" + client,
+				RequestedAt: now,
+				Subject:     sql.NullString{String: subject.String(), Valid: true},
+				Active:      true,
+				Session:     []byte("{}"),
+			}))
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+func TestStorageUserOpenIDConnectConsentsListRunE(t *testing.T) {
+	ctx, _ := newTestCmdCtxSQLite(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	ids := seedTestStorageUserOpenIDConnectConsents(t, ctx, now, "app")
+
+	output, err := captureTestStdout(t, func() error {
+		return ctx.StorageUserOpenIDConnectConsentsListRunE(newStorageUserOpenIDConnectConsentsListCmd(ctx), []string{"john"})
+	})
+
+	require.NoError(t, err)
+	assert.Contains(t, output, "OpenID Connect 1.0 Consents for user 'john':")
+	assert.Contains(t, output, fmt.Sprintf("%d\tapp\t%s\t%s\topenid profile\tapp\n", ids[0], now.Format(time.RFC3339), now.Add(time.Hour).Format(time.RFC3339)))
+}
+
+func TestStorageUserOpenIDConnectConsentsListRunEShouldErrorWithoutConsents(t *testing.T) {
+	ctx, _ := newTestCmdCtxSQLite(t)
+
+	_, err := captureTestStdout(t, func() error {
+		return ctx.StorageUserOpenIDConnectConsentsListRunE(newStorageUserOpenIDConnectConsentsListCmd(ctx), []string{"john"})
+	})
+
+	assert.EqualError(t, err, "user 'john' has no consents")
+}
+
+func TestStorageUserOpenIDConnectConsentsRevokeRunE(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     func(ids []int64) []string
+		expected func(ids []int64) string
+		revoked  []string
+		retained []string
+	}{
+		{
+			"ShouldRevokeByID",
+			func(ids []int64) []string {
+				return []string{"--id", fmt.Sprintf("%d", ids[0])}
+			},
+			func(ids []int64) string {
+				return fmt.Sprintf("Successfully revoked consent with id '%d' for user 'john'\n", ids[0])
+			},
+			[]string{"app"},
+			[]string{"other"},
+		},
+		{
+			"ShouldRevokeAll",
+			func(_ []int64) []string {
+				return []string{"--all"}
+			},
+			func(_ []int64) string {
+				return "Successfully revoked 2 consents for user 'john'\n"
+			},
+			[]string{"app", "other"},
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := newTestCmdCtxSQLite(t)
+
+			now := time.Now().UTC().Truncate(time.Second)
+
+			ids := seedTestStorageUserOpenIDConnectConsents(t, ctx, now, "app", "other")
+
+			cmd := newStorageUserOpenIDConnectConsentsRevokeCmd(ctx)
+			require.NoError(t, cmd.ParseFlags(tc.args(ids)))
+
+			output, err := captureTestStdout(t, func() error {
+				return ctx.StorageUserOpenIDConnectConsentsRevokeRunE(cmd, []string{"john"})
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected(ids), output)
+
+			// The command closes the storage provider so it's opened again to check the result.
+			provider := newTestCmdCtxSQLiteStorage(t, ctx)
+
+			defer provider.Close()
+
+			configs, err := provider.LoadOAuth2ConsentPreConfigurationsByUsername(ctx, "john")
+			require.NoError(t, err)
+			require.Len(t, configs, len(tc.retained))
+
+			for i, client := range tc.retained {
+				assert.Equal(t, client, configs[i].ClientID)
+			}
+
+			for _, sessionType := range []storage.OAuth2SessionType{storage.OAuth2SessionTypeAccessToken, storage.OAuth2SessionTypeRefreshToken} {
+				for _, client := range tc.revoked {
+					_, err = provider.LoadOAuth2Session(ctx, sessionType, "signature-"+client)
+					assert.ErrorIs(t, err, sql.ErrNoRows)
+				}
+
+				for _, client := range tc.retained {
+					_, err = provider.LoadOAuth2Session(ctx, sessionType, "signature-"+client)
+					assert.NoError(t, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStorageUserOpenIDConnectConsentsRevokeRunEShouldError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			"ShouldErrorWithoutFlags",
+			nil,
+			"must supply one of the flags --all or --id",
+		},
+		{
+			"ShouldErrorWithBothFlags",
+			[]string{"--all", "--id", "1"},
+			"must only supply one of the flags --all and --id but 2 were specified",
+		},
+		{
+			"ShouldErrorWithUnknownID",
+			[]string{"--id", "100"},
+			"user 'john' has no consent with id '100'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, _ := newTestCmdCtxSQLite(t)
+
+			seedTestStorageUserOpenIDConnectConsents(t, ctx, time.Now().UTC().Truncate(time.Second), "app")
+
+			cmd := newStorageUserOpenIDConnectConsentsRevokeCmd(ctx)
+			require.NoError(t, cmd.ParseFlags(tc.args))
+
+			_, err := captureTestStdout(t, func() error {
+				return ctx.StorageUserOpenIDConnectConsentsRevokeRunE(cmd, []string{"john"})
+			})
+
+			assert.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestStorageUserOpenIDConnectConsentsCmdShouldRequireUsername(t *testing.T) {
+	ctx := NewCmdCtx()
+
+	for _, cmd := range []*cobra.Command{newStorageUserOpenIDConnectConsentsListCmd(ctx), newStorageUserOpenIDConnectConsentsRevokeCmd(ctx)} {
+		assert.EqualError(t, cmd.Args(cmd, nil), "accepts 1 arg(s), received 0")
+		assert.NoError(t, cmd.Args(cmd, []string{"john"}))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"net"
 	"net/mail"
 	"net/url"
 	"reflect"
@@ -867,6 +868,83 @@ func TestStringToAddressHookFunc(t *testing.T) {
 	}
 }
 
+func TestStringToIPNetworkHookFunc(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     any
+		expected any
+		err      string
+		decode   bool
+	}{
+		{
+			name:     "ShouldDecodeCIDRNonPtr",
+			have:     "10.0.0.0/8",
+			expected: net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)},
+			decode:   true,
+		},
+		{
+			name:     "ShouldDecodeCIDRPtr",
+			have:     "192.168.1.0/24",
+			expected: &net.IPNet{IP: net.IPv4(192, 168, 1, 0).To4(), Mask: net.CIDRMask(24, 32)},
+			decode:   true,
+		},
+		{
+			name:     "ShouldDecodeIPv4AsSingleAddressNetwork",
+			have:     "192.168.1.5",
+			expected: &net.IPNet{IP: net.IPv4(192, 168, 1, 5).To4(), Mask: net.CIDRMask(32, 32)},
+			decode:   true,
+		},
+		{
+			name:     "ShouldDecodeIPv6AsSingleAddressNetwork",
+			have:     "fec0::1",
+			expected: &net.IPNet{IP: net.ParseIP("fec0::1"), Mask: net.CIDRMask(128, 128)},
+			decode:   true,
+		},
+		{
+			name:     "ShouldNotDecodeIntegerToCorrectType",
+			have:     1,
+			expected: net.IPNet{},
+			decode:   false,
+		},
+		{
+			name:     "ShouldNotDecodeToString",
+			have:     "10.0.0.0/8",
+			expected: "",
+			decode:   false,
+		},
+		{
+			name:     "ShouldFailDecode",
+			have:     "10.0.0.0/33",
+			expected: &net.IPNet{},
+			err:      "could not decode '10.0.0.0/33' to a *net.IPNet: invalid CIDR address: 10.0.0.0/33",
+			decode:   false,
+		},
+	}
+
+	hook := configuration.StringToIPNetworkHookFunc()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := hook(reflect.TypeOf(tc.have), reflect.TypeOf(tc.expected), tc.have)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				if !tc.decode {
+					assert.Nil(t, actual)
+				}
+			} else {
+				assert.NoError(t, err)
+
+				if tc.decode {
+					assert.Equal(t, tc.expected, actual)
+				} else {
+					assert.Equal(t, tc.have, actual)
+				}
+			}
+		})
+	}
+}
+
 func TestStringToPrivateKeyHookFunc(t *testing.T) {
 	var (
 		nilRSA   *rsa.PrivateKey
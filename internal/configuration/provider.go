@@ -2,6 +2,7 @@ package configuration
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/knadh/koanf"
 	"github.com/mitchellh/mapstructure"
@@ -61,6 +62,7 @@ func unmarshal(ko *koanf.Koanf, val *schema.StructValidator, path string, o any)
 				StringToURLHookFunc(),
 				StringToRegexpHookFunc(),
 				StringToAddressHookFunc(),
+				StringToIPNetworkHookFunc(),
 				StringToX509CertificateHookFunc(),
 				StringToX509CertificateChainHookFunc(),
 				StringToPrivateKeyHookFunc(),
@@ -68,6 +70,7 @@ func unmarshal(ko *koanf.Koanf, val *schema.StructValidator, path string, o any)
 				StringToTLSVersionHookFunc(),
 				StringToPasswordDigestHookFunc(),
 				ToTimeDurationHookFunc(),
+				mapstructure.StringToTimeHookFunc(time.RFC3339),
 			),
 			Metadata:         nil,
 			Result:           o,
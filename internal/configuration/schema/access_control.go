@@ -1,14 +1,28 @@
 package schema
 
 import (
+	"crypto/rsa"
 	"regexp"
+	"time"
 )
 
 // AccessControlConfiguration represents the configuration related to ACLs.
 type AccessControlConfiguration struct {
-	DefaultPolicy string       `koanf:"default_policy"`
-	Networks      []ACLNetwork `koanf:"networks"`
-	Rules         []ACLRule    `koanf:"rules"`
+	DefaultPolicy     string                            `koanf:"default_policy"`
+	Headers           []ACLHeader                       `koanf:"headers"`
+	IdentityAssertion ACLIdentityAssertionConfiguration `koanf:"identity_assertion"`
+	Networks          []ACLNetwork                      `koanf:"networks"`
+	Rules             []ACLRule                         `koanf:"rules"`
+}
+
+// ACLIdentityAssertionConfiguration represents the configuration related to the signed identity assertion JWT which
+// is returned to the proxy for authorized requests.
+type ACLIdentityAssertionConfiguration struct {
+	Enable     bool            `koanf:"enable"`
+	Header     string          `koanf:"header"`
+	Issuer     string          `koanf:"issuer"`
+	Lifespan   time.Duration   `koanf:"lifespan"`
+	PrivateKey *rsa.PrivateKey `koanf:"private_key"`
 }
 
 // ACLNetwork represents one ACL network group entry.
@@ -27,6 +41,9 @@ type ACLRule struct {
 	Resources    []regexp.Regexp  `koanf:"resources"`
 	Methods      []string         `koanf:"methods"`
 	Query        [][]ACLQueryRule `koanf:"query"`
+	Headers      []ACLHeader      `koanf:"headers"`
+
+	IdentityAssertionAudience []string `koanf:"identity_assertion_audience"`
 }
 
 // ACLQueryRule represents the ACL query criteria.
@@ -36,6 +53,18 @@ type ACLQueryRule struct {
 	Value    any    `koanf:"value"`
 }
 
+// ACLHeader represents a header returned to the proxy when a request is authorized. The value is a template.
+type ACLHeader struct {
+	Name  string `koanf:"name"`
+	Value string `koanf:"value"`
+}
+
+// DefaultACLIdentityAssertionConfiguration represents the default configuration related to the identity assertion.
+var DefaultACLIdentityAssertionConfiguration = ACLIdentityAssertionConfiguration{
+	Header:   "Remote-Identity-Assertion",
+	Lifespan: time.Minute,
+}
+
 // DefaultACLNetwork represents the default configuration related to access control network group configuration.
 var DefaultACLNetwork = []ACLNetwork{
 	{
@@ -75,6 +75,62 @@ const (
 	LDAPImplementationGLAuth = "glauth"
 )
 
+const (
+	// AuthzEndpointNameLegacy is the name of the legacy Authz endpoint which is mounted at /api/verify.
+	AuthzEndpointNameLegacy = "legacy"
+
+	// AuthzEndpointNameAuthRequest is the name of the default AuthRequest Authz endpoint.
+	AuthzEndpointNameAuthRequest = "auth-request"
+
+	// AuthzEndpointNameForwardAuth is the name of the default ForwardAuth Authz endpoint.
+	AuthzEndpointNameForwardAuth = "forward-auth"
+
+	// AuthzEndpointNameExtAuthz is the name of the default ExtAuthz Authz endpoint.
+	AuthzEndpointNameExtAuthz = "ext-authz"
+)
+
+const (
+	// AuthzImplementationLegacy is the Legacy Authz implementation which detects the proxy from the request.
+	AuthzImplementationLegacy = "Legacy"
+
+	// AuthzImplementationForwardAuth is the ForwardAuth Authz implementation used by Traefik, Caddy, and Skipper.
+	AuthzImplementationForwardAuth = "ForwardAuth"
+
+	// AuthzImplementationAuthRequest is the AuthRequest Authz implementation used by NGINX and HAProxy.
+	AuthzImplementationAuthRequest = "AuthRequest"
+
+	// AuthzImplementationExtAuthz is the ExtAuthz Authz implementation used by Envoy.
+	AuthzImplementationExtAuthz = "ExtAuthz"
+)
+
+const (
+	// AuthzStrategyHeaderCookieSession is the Authz strategy which uses the session cookie.
+	AuthzStrategyHeaderCookieSession = "CookieSession"
+
+	// AuthzStrategyHeaderAuthorization is the Authz strategy which uses the Authorization header and responds with
+	// 401 Unauthorized and the WWW-Authenticate header.
+	AuthzStrategyHeaderAuthorization = "HeaderAuthorization"
+
+	// AuthzStrategyHeaderProxyAuthorization is the Authz strategy which uses the Proxy-Authorization header and
+	// responds with 407 Proxy Authentication Required and the Proxy-Authenticate header.
+	AuthzStrategyHeaderProxyAuthorization = "HeaderProxyAuthorization"
+
+	// AuthzStrategyHeaderAuthRequestProxyAuthorization is the Authz strategy which uses the Proxy-Authorization
+	// header and responds with 401 Unauthorized and the WWW-Authenticate header as AuthRequest proxies can only
+	// handle the 401 and 403 status codes.
+	AuthzStrategyHeaderAuthRequestProxyAuthorization = "HeaderAuthRequestProxyAuthorization"
+)
+
+const (
+	// ProxyProtocolModeStrict is the PROXY protocol mode which requires every connection from a trusted source to send
+	// a PROXY protocol header.
+	ProxyProtocolModeStrict = "strict"
+
+	// ProxyProtocolModeOptional is the PROXY protocol mode which accepts connections from a trusted source with or
+	// without a PROXY protocol header.
+	ProxyProtocolModeOptional = "optional"
+)
+
 // TOTP Algorithm.
 const (
 	TOTPAlgorithmSHA1   = "SHA1"
@@ -82,6 +138,18 @@ const (
 	TOTPAlgorithmSHA512 = "SHA512"
 )
 
+const (
+	// SessionBindingPolicyLog is the session binding policy which only logs a violation.
+	SessionBindingPolicyLog = "log"
+
+	// SessionBindingPolicyReauthenticate is the session binding policy which requires the user to authenticate again
+	// when a violation occurs.
+	SessionBindingPolicyReauthenticate = "reauthenticate"
+
+	// SessionBindingPolicyDestroy is the session binding policy which destroys the session when a violation occurs.
+	SessionBindingPolicyDestroy = "destroy"
+)
+
 const (
 	// RememberMeDisabled represents the duration for a disabled remember me session configuration.
 	RememberMeDisabled = time.Second * -1
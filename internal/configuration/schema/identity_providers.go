@@ -17,10 +17,18 @@ type OpenIDConnectConfiguration struct {
 	IssuerCertificateChain X509CertificateChain `koanf:"issuer_certificate_chain"`
 	IssuerPrivateKey       *rsa.PrivateKey      `koanf:"issuer_private_key"`
 
+	IssuerPrivateKeys []OpenIDConnectIssuerPrivateKey `koanf:"issuer_private_keys"`
+
 	AccessTokenLifespan   time.Duration `koanf:"access_token_lifespan"`
 	AuthorizeCodeLifespan time.Duration `koanf:"authorize_code_lifespan"`
 	IDTokenLifespan       time.Duration `koanf:"id_token_lifespan"`
 	RefreshTokenLifespan  time.Duration `koanf:"refresh_token_lifespan"`
+	DeviceCodeLifespan    time.Duration `koanf:"device_code_lifespan"`
+
+	RefreshTokenAbsoluteLifespan time.Duration `koanf:"refresh_token_absolute_lifespan"`
+	RefreshTokenReuseGracePeriod time.Duration `koanf:"refresh_token_reuse_grace_period"`
+
+	DeviceCodePollingInterval time.Duration `koanf:"device_code_polling_interval"`
 
 	EnableClientDebugMessages bool `koanf:"enable_client_debug_messages"`
 	MinimumParameterEntropy   int  `koanf:"minimum_parameter_entropy"`
@@ -30,9 +38,27 @@ type OpenIDConnectConfiguration struct {
 
 	CORS OpenIDConnectCORSConfiguration `koanf:"cors"`
 
+	DynamicClientRegistration OpenIDConnectDynamicClientRegistrationConfiguration `koanf:"dynamic_client_registration"`
+
+	Claims map[string]OpenIDConnectClaimConfiguration `koanf:"claims"`
+	Scopes map[string]OpenIDConnectScopeConfiguration `koanf:"scopes"`
+
 	Clients []OpenIDConnectClientConfiguration `koanf:"clients"`
 }
 
+// OpenIDConnectIssuerPrivateKey represents a JSON Web Key used to sign the tokens issued by the OpenID Connect provider,
+// or to decrypt the Request Objects sent to it when the use is 'enc'.
+type OpenIDConnectIssuerPrivateKey struct {
+	KeyID            string                  `koanf:"key_id"`
+	Use              string                  `koanf:"use"`
+	Algorithm        string                  `koanf:"algorithm"`
+	Key              CryptographicPrivateKey `koanf:"key"`
+	CertificateChain X509CertificateChain    `koanf:"certificate_chain"`
+
+	NotBefore time.Time `koanf:"not_before"`
+	NotAfter  time.Time `koanf:"not_after"`
+}
+
 // OpenIDConnectCORSConfiguration represents an OpenID Connect CORS config.
 type OpenIDConnectCORSConfiguration struct {
 	Endpoints      []string  `koanf:"endpoints"`
@@ -41,6 +67,27 @@ type OpenIDConnectCORSConfiguration struct {
 	AllowedOriginsFromClientRedirectURIs bool `koanf:"allowed_origins_from_client_redirect_uris"`
 }
 
+// OpenIDConnectDynamicClientRegistrationConfiguration represents the OpenID Connect Dynamic Client Registration config.
+type OpenIDConnectDynamicClientRegistrationConfiguration struct {
+	Enabled            bool   `koanf:"enabled"`
+	InitialAccessToken string `koanf:"initial_access_token"`
+}
+
+// OpenIDConnectClaimConfiguration represents a custom claim definition. Only one of the attribute, value, or template
+// options may be configured.
+type OpenIDConnectClaimConfiguration struct {
+	Attribute string `koanf:"attribute"`
+	Filter    string `koanf:"filter"`
+	Value     string `koanf:"value"`
+	Template  string `koanf:"template"`
+}
+
+// OpenIDConnectScopeConfiguration represents a custom scope definition.
+type OpenIDConnectScopeConfiguration struct {
+	Description string   `koanf:"description"`
+	Claims      []string `koanf:"claims"`
+}
+
 // OpenIDConnectClientConfiguration configuration for an OpenID Connect client.
 type OpenIDConnectClientConfiguration struct {
 	ID               string          `koanf:"id"`
@@ -49,20 +96,63 @@ type OpenIDConnectClientConfiguration struct {
 	SectorIdentifier url.URL         `koanf:"sector_identifier"`
 	Public           bool            `koanf:"public"`
 
+	TokenEndpointAuthMethod           string `koanf:"token_endpoint_auth_method"`
+	TokenEndpointAuthSigningAlgorithm string `koanf:"token_endpoint_auth_signing_algorithm"`
+
+	JSONWebKeys     string `koanf:"jwks"`
+	JSONWebKeysPath string `koanf:"jwks_path"`
+
 	RedirectURIs []string `koanf:"redirect_uris"`
 
+	PostLogoutRedirectURIs            []string `koanf:"post_logout_redirect_uris"`
+	FrontChannelLogoutURI             string   `koanf:"frontchannel_logout_uri"`
+	FrontChannelLogoutSessionRequired bool     `koanf:"frontchannel_logout_session_required"`
+	BackChannelLogoutURI              string   `koanf:"backchannel_logout_uri"`
+
+	RequirePushedAuthorizationRequests bool `koanf:"require_pushed_authorization_requests"`
+
+	RequestURIs                   []string `koanf:"request_uris"`
+	RequestObjectSigningAlgorithm string   `koanf:"request_object_signing_algorithm"`
+	RequireSignedRequestObject    bool     `koanf:"require_signed_request_object"`
+
 	Audience      []string `koanf:"audience"`
 	Scopes        []string `koanf:"scopes"`
 	GrantTypes    []string `koanf:"grant_types"`
 	ResponseTypes []string `koanf:"response_types"`
 	ResponseModes []string `koanf:"response_modes"`
 
-	UserinfoSigningAlgorithm string `koanf:"userinfo_signing_algorithm"`
+	IDTokenSigningAlgorithm     string `koanf:"id_token_signing_algorithm"`
+	UserinfoSigningAlgorithm    string `koanf:"userinfo_signing_algorithm"`
+	AccessTokenSigningAlgorithm string `koanf:"access_token_signing_algorithm"`
 
 	Policy string `koanf:"authorization_policy"`
 
 	ConsentMode                  string         `koanf:"consent_mode"`
 	ConsentPreConfiguredDuration *time.Duration `koanf:"pre_configured_consent_duration"`
+
+	TokenLifespans OpenIDConnectClientTokenLifespans `koanf:"token_lifespans"`
+
+	TokenExchange OpenIDConnectClientTokenExchange `koanf:"token_exchange"`
+
+	Claims map[string]OpenIDConnectClaimConfiguration `koanf:"claims"`
+}
+
+// OpenIDConnectClientTokenLifespans represents the token lifespans specific to a client which override the global
+// lifespans when configured.
+type OpenIDConnectClientTokenLifespans struct {
+	AccessToken          time.Duration `koanf:"access_token"`
+	IDToken              time.Duration `koanf:"id_token"`
+	RefreshToken         time.Duration `koanf:"refresh_token"`
+	RefreshTokenAbsolute time.Duration `koanf:"refresh_token_absolute"`
+
+	RefreshTokenReuseGracePeriod time.Duration `koanf:"refresh_token_reuse_grace_period"`
+}
+
+// OpenIDConnectClientTokenExchange represents the policy which restricts the audience and scopes a client may request
+// using the OAuth 2.0 Token Exchange grant.
+type OpenIDConnectClientTokenExchange struct {
+	Audience []string `koanf:"audience"`
+	Scopes   []string `koanf:"scopes"`
 }
 
 // DefaultOpenIDConnectConfiguration contains defaults for OIDC.
@@ -71,7 +161,10 @@ var DefaultOpenIDConnectConfiguration = OpenIDConnectConfiguration{
 	AuthorizeCodeLifespan: time.Minute,
 	IDTokenLifespan:       time.Hour,
 	RefreshTokenLifespan:  time.Minute * 90,
+	DeviceCodeLifespan:    time.Minute * 10,
 	EnforcePKCE:           "public_clients_only",
+
+	DeviceCodePollingInterval: time.Second * 5,
 }
 
 var defaultOIDCClientConsentPreConfiguredDuration = time.Hour * 24 * 7
@@ -84,7 +177,9 @@ var DefaultOpenIDConnectClientConfiguration = OpenIDConnectClientConfiguration{
 	ResponseTypes: []string{"code"},
 	ResponseModes: []string{"form_post", "query", "fragment"},
 
+	IDTokenSigningAlgorithm:      "RS256",
 	UserinfoSigningAlgorithm:     "none",
+	AccessTokenSigningAlgorithm:  "none",
 	ConsentMode:                  "auto",
 	ConsentPreConfiguredDuration: &defaultOIDCClientConsentPreConfiguredDuration,
 }
@@ -20,10 +20,26 @@ var Keys = []string{
 	"identity_providers.oidc.hmac_secret",
 	"identity_providers.oidc.issuer_certificate_chain",
 	"identity_providers.oidc.issuer_private_key",
+	"identity_providers.oidc.issuer_private_keys",
+	"identity_providers.oidc.issuer_private_keys[].key_id",
+	"identity_providers.oidc.issuer_private_keys[].use",
+	"identity_providers.oidc.issuer_private_keys[].algorithm",
+	"identity_providers.oidc.issuer_private_keys[].key",
+	"identity_providers.oidc.issuer_private_keys[].certificate_chain",
+	"identity_providers.oidc.issuer_private_keys[].not_before",
+	"identity_providers.oidc.issuer_private_keys[].not_before",
+	"identity_providers.oidc.issuer_private_keys[].not_before",
+	"identity_providers.oidc.issuer_private_keys[].not_after",
+	"identity_providers.oidc.issuer_private_keys[].not_after",
+	"identity_providers.oidc.issuer_private_keys[].not_after",
 	"identity_providers.oidc.access_token_lifespan",
 	"identity_providers.oidc.authorize_code_lifespan",
 	"identity_providers.oidc.id_token_lifespan",
 	"identity_providers.oidc.refresh_token_lifespan",
+	"identity_providers.oidc.device_code_lifespan",
+	"identity_providers.oidc.refresh_token_absolute_lifespan",
+	"identity_providers.oidc.refresh_token_reuse_grace_period",
+	"identity_providers.oidc.device_code_polling_interval",
 	"identity_providers.oidc.enable_client_debug_messages",
 	"identity_providers.oidc.minimum_parameter_entropy",
 	"identity_providers.oidc.enforce_pkce",
@@ -31,22 +47,55 @@ var Keys = []string{
 	"identity_providers.oidc.cors.endpoints",
 	"identity_providers.oidc.cors.allowed_origins",
 	"identity_providers.oidc.cors.allowed_origins_from_client_redirect_uris",
+	"identity_providers.oidc.dynamic_client_registration.enabled",
+	"identity_providers.oidc.dynamic_client_registration.initial_access_token",
+	"identity_providers.oidc.claims.*.attribute",
+	"identity_providers.oidc.claims.*.filter",
+	"identity_providers.oidc.claims.*.value",
+	"identity_providers.oidc.claims.*.template",
+	"identity_providers.oidc.scopes.*.description",
+	"identity_providers.oidc.scopes.*.claims",
 	"identity_providers.oidc.clients",
 	"identity_providers.oidc.clients[].id",
 	"identity_providers.oidc.clients[].description",
 	"identity_providers.oidc.clients[].secret",
 	"identity_providers.oidc.clients[].sector_identifier",
 	"identity_providers.oidc.clients[].public",
+	"identity_providers.oidc.clients[].token_endpoint_auth_method",
+	"identity_providers.oidc.clients[].token_endpoint_auth_signing_algorithm",
+	"identity_providers.oidc.clients[].jwks",
+	"identity_providers.oidc.clients[].jwks_path",
 	"identity_providers.oidc.clients[].redirect_uris",
+	"identity_providers.oidc.clients[].post_logout_redirect_uris",
+	"identity_providers.oidc.clients[].frontchannel_logout_uri",
+	"identity_providers.oidc.clients[].frontchannel_logout_session_required",
+	"identity_providers.oidc.clients[].backchannel_logout_uri",
+	"identity_providers.oidc.clients[].require_pushed_authorization_requests",
+	"identity_providers.oidc.clients[].request_uris",
+	"identity_providers.oidc.clients[].request_object_signing_algorithm",
+	"identity_providers.oidc.clients[].require_signed_request_object",
 	"identity_providers.oidc.clients[].audience",
 	"identity_providers.oidc.clients[].scopes",
 	"identity_providers.oidc.clients[].grant_types",
 	"identity_providers.oidc.clients[].response_types",
 	"identity_providers.oidc.clients[].response_modes",
+	"identity_providers.oidc.clients[].id_token_signing_algorithm",
 	"identity_providers.oidc.clients[].userinfo_signing_algorithm",
+	"identity_providers.oidc.clients[].access_token_signing_algorithm",
 	"identity_providers.oidc.clients[].authorization_policy",
 	"identity_providers.oidc.clients[].consent_mode",
 	"identity_providers.oidc.clients[].pre_configured_consent_duration",
+	"identity_providers.oidc.clients[].token_lifespans.access_token",
+	"identity_providers.oidc.clients[].token_lifespans.id_token",
+	"identity_providers.oidc.clients[].token_lifespans.refresh_token",
+	"identity_providers.oidc.clients[].token_lifespans.refresh_token_absolute",
+	"identity_providers.oidc.clients[].token_lifespans.refresh_token_reuse_grace_period",
+	"identity_providers.oidc.clients[].token_exchange.audience",
+	"identity_providers.oidc.clients[].token_exchange.scopes",
+	"identity_providers.oidc.clients[].claims.*.attribute",
+	"identity_providers.oidc.clients[].claims.*.filter",
+	"identity_providers.oidc.clients[].claims.*.value",
+	"identity_providers.oidc.clients[].claims.*.template",
 	"authentication_backend.password_reset.disable",
 	"authentication_backend.password_reset.custom_url",
 	"authentication_backend.refresh_interval",
@@ -107,9 +156,25 @@ var Keys = []string{
 	"session.domain",
 	"session.same_site",
 	"session.secret",
+	"session.previous_secrets",
 	"session.expiration",
 	"session.inactivity",
 	"session.remember_me_duration",
+	"session.cookies",
+	"session.cookies[].name",
+	"session.cookies[].domain",
+	"session.cookies[].same_site",
+	"session.cookies[].expiration",
+	"session.cookies[].inactivity",
+	"session.cookies[].remember_me_duration",
+	"session.cookies[].authelia_url",
+	"session.binding.ip",
+	"session.binding.ipv4_mask",
+	"session.binding.ipv6_mask",
+	"session.binding.user_agent",
+	"session.binding.anti_replay",
+	"session.binding.anti_replay_rotation_interval",
+	"session.binding.policy",
 	"session.redis.host",
 	"session.redis.port",
 	"session.redis.username",
@@ -131,6 +196,13 @@ var Keys = []string{
 	"session.redis.high_availability.nodes[].port",
 	"session.redis.high_availability.route_by_latency",
 	"session.redis.high_availability.route_randomly",
+	"session.redis.cluster.nodes",
+	"session.redis.cluster.nodes[].host",
+	"session.redis.cluster.nodes[].port",
+	"session.redis.cluster.route_by_latency",
+	"session.redis.cluster.route_randomly",
+	"session.sql.enabled",
+	"session.sql.cleanup_interval",
 	"totp.disable",
 	"totp.issuer",
 	"totp.algorithm",
@@ -144,6 +216,14 @@ var Keys = []string{
 	"duo_api.secret_key",
 	"duo_api.enable_self_enrollment",
 	"access_control.default_policy",
+	"access_control.headers",
+	"access_control.headers[].name",
+	"access_control.headers[].value",
+	"access_control.identity_assertion.enable",
+	"access_control.identity_assertion.header",
+	"access_control.identity_assertion.issuer",
+	"access_control.identity_assertion.lifespan",
+	"access_control.identity_assertion.private_key",
 	"access_control.networks",
 	"access_control.networks[].name",
 	"access_control.networks[].networks",
@@ -159,6 +239,10 @@ var Keys = []string{
 	"access_control.rules[].query[][].key",
 	"access_control.rules[].query[][].value",
 	"access_control.rules[].query",
+	"access_control.rules[].headers",
+	"access_control.rules[].headers[].name",
+	"access_control.rules[].headers[].value",
+	"access_control.rules[].identity_assertion_audience",
 	"ntp.address",
 	"ntp.version",
 	"ntp.max_desync",
@@ -198,6 +282,7 @@ var Keys = []string{
 	"storage.postgres.ssl.certificate",
 	"storage.postgres.ssl.key",
 	"storage.encryption_key",
+	"storage.previous_encryption_keys",
 	"notifier.disable_startup_check",
 	"notifier.filesystem.filename",
 	"notifier.smtp.host",
@@ -226,10 +311,20 @@ var Keys = []string{
 	"server.enable_pprof",
 	"server.enable_expvars",
 	"server.disable_healthcheck",
+	"server.trusted_proxies",
 	"server.tls.certificate",
 	"server.tls.key",
 	"server.tls.client_certificates",
 	"server.headers.csp_template",
+	"server.endpoints.authz.*.implementation",
+	"server.endpoints.authz.*.authn_strategies",
+	"server.endpoints.authz.*.authn_strategies[].name",
+	"server.endpoints.admin.enabled",
+	"server.endpoints.admin.groups",
+	"server.proxy_protocol.enabled",
+	"server.proxy_protocol.mode",
+	"server.proxy_protocol.trusted_sources",
+	"server.proxy_protocol.timeout",
 	"server.buffers.read",
 	"server.buffers.write",
 	"server.timeouts.read",
@@ -242,6 +337,10 @@ var Keys = []string{
 	"telemetry.metrics.timeouts.read",
 	"telemetry.metrics.timeouts.write",
 	"telemetry.metrics.timeouts.idle",
+	"telemetry.metrics.proxy_protocol.enabled",
+	"telemetry.metrics.proxy_protocol.mode",
+	"telemetry.metrics.proxy_protocol.trusted_sources",
+	"telemetry.metrics.proxy_protocol.timeout",
 	"webauthn.disable",
 	"webauthn.display_name",
 	"webauthn.attestation_conveyance_preference",
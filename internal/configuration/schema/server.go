@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"net"
 	"time"
 )
 
@@ -14,8 +15,12 @@ type ServerConfiguration struct {
 	EnableExpvars      bool   `koanf:"enable_expvars"`
 	DisableHealthcheck bool   `koanf:"disable_healthcheck"`
 
-	TLS     ServerTLSConfiguration     `koanf:"tls"`
-	Headers ServerHeadersConfiguration `koanf:"headers"`
+	TrustedProxies []*net.IPNet `koanf:"trusted_proxies"`
+
+	TLS           ServerTLSConfiguration     `koanf:"tls"`
+	Headers       ServerHeadersConfiguration `koanf:"headers"`
+	Endpoints     ServerEndpoints            `koanf:"endpoints"`
+	ProxyProtocol ServerProxyProtocol        `koanf:"proxy_protocol"`
 
 	Buffers  ServerBuffers  `koanf:"buffers"`
 	Timeouts ServerTimeouts `koanf:"timeouts"`
@@ -28,15 +33,96 @@ type ServerTLSConfiguration struct {
 	ClientCertificates []string `koanf:"client_certificates"`
 }
 
+// ServerProxyProtocol represents the PROXY protocol configuration of a listener.
+type ServerProxyProtocol struct {
+	Enabled        bool          `koanf:"enabled"`
+	Mode           string        `koanf:"mode"`
+	TrustedSources []*net.IPNet  `koanf:"trusted_sources"`
+	Timeout        time.Duration `koanf:"timeout"`
+}
+
 // ServerHeadersConfiguration represents the customization of the http server headers.
 type ServerHeadersConfiguration struct {
 	CSPTemplate string `koanf:"csp_template"`
 }
 
+// ServerEndpoints is the endpoints configuration for the HTTP server.
+type ServerEndpoints struct {
+	Authz map[string]ServerAuthzEndpoint `koanf:"authz"`
+	Admin ServerEndpointsAdmin           `koanf:"admin"`
+}
+
+// ServerEndpointsAdmin is the administrative endpoints configuration for the HTTP server.
+type ServerEndpointsAdmin struct {
+	Enabled bool     `koanf:"enabled"`
+	Groups  []string `koanf:"groups"`
+}
+
+// ServerAuthzEndpoint is the Authz endpoints configuration for the HTTP server.
+type ServerAuthzEndpoint struct {
+	Implementation string `koanf:"implementation"`
+
+	AuthnStrategies []ServerAuthzEndpointAuthnStrategy `koanf:"authn_strategies"`
+}
+
+// ServerAuthzEndpointAuthnStrategy is the Authz endpoint authentication strategy configuration.
+type ServerAuthzEndpointAuthnStrategy struct {
+	Name string `koanf:"name"`
+}
+
 // DefaultServerConfiguration represents the default values of the ServerConfiguration.
 var DefaultServerConfiguration = ServerConfiguration{
 	Host: "0.0.0.0",
 	Port: 9091,
+	TrustedProxies: []*net.IPNet{
+		{IP: net.IPv4(127, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)},
+		{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)},
+		{IP: net.IPv4(172, 16, 0, 0).To4(), Mask: net.CIDRMask(12, 32)},
+		{IP: net.IPv4(192, 168, 0, 0).To4(), Mask: net.CIDRMask(16, 32)},
+		{IP: net.IPv6loopback, Mask: net.CIDRMask(128, 128)},
+		{IP: net.IP{0xfc, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, Mask: net.CIDRMask(7, 128)},
+	},
+	Endpoints: ServerEndpoints{
+		Authz: map[string]ServerAuthzEndpoint{
+			AuthzEndpointNameLegacy: {
+				Implementation: AuthzImplementationLegacy,
+			},
+			AuthzEndpointNameAuthRequest: {
+				Implementation: AuthzImplementationAuthRequest,
+				AuthnStrategies: []ServerAuthzEndpointAuthnStrategy{
+					{
+						Name: AuthzStrategyHeaderAuthRequestProxyAuthorization,
+					},
+					{
+						Name: AuthzStrategyHeaderCookieSession,
+					},
+				},
+			},
+			AuthzEndpointNameForwardAuth: {
+				Implementation: AuthzImplementationForwardAuth,
+				AuthnStrategies: []ServerAuthzEndpointAuthnStrategy{
+					{
+						Name: AuthzStrategyHeaderProxyAuthorization,
+					},
+					{
+						Name: AuthzStrategyHeaderCookieSession,
+					},
+				},
+			},
+			AuthzEndpointNameExtAuthz: {
+				Implementation: AuthzImplementationExtAuthz,
+				AuthnStrategies: []ServerAuthzEndpointAuthnStrategy{
+					{
+						Name: AuthzStrategyHeaderProxyAuthorization,
+					},
+					{
+						Name: AuthzStrategyHeaderCookieSession,
+					},
+				},
+			},
+		},
+	},
+	ProxyProtocol: DefaultServerProxyProtocol,
 	Buffers: ServerBuffers{
 		Read:  4096,
 		Write: 4096,
@@ -47,3 +133,9 @@ var DefaultServerConfiguration = ServerConfiguration{
 		Idle:  time.Second * 30,
 	},
 }
+
+// DefaultServerProxyProtocol represents the default values of the ServerProxyProtocol.
+var DefaultServerProxyProtocol = ServerProxyProtocol{
+	Mode:    ProxyProtocolModeStrict,
+	Timeout: time.Second * 5,
+}
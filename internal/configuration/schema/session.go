@@ -2,6 +2,7 @@ package schema
 
 import (
 	"crypto/tls"
+	"net/url"
 	"time"
 )
 
@@ -21,6 +22,13 @@ type RedisHighAvailabilityConfiguration struct {
 	RouteRandomly    bool        `koanf:"route_randomly"`
 }
 
+// RedisClusterConfiguration holds configuration variables for Redis Cluster.
+type RedisClusterConfiguration struct {
+	Nodes          []RedisNode `koanf:"nodes"`
+	RouteByLatency bool        `koanf:"route_by_latency"`
+	RouteRandomly  bool        `koanf:"route_randomly"`
+}
+
 // RedisSessionConfiguration represents the configuration related to redis session store.
 type RedisSessionConfiguration struct {
 	Host                     string                              `koanf:"host"`
@@ -32,6 +40,24 @@ type RedisSessionConfiguration struct {
 	MinimumIdleConnections   int                                 `koanf:"minimum_idle_connections"`
 	TLS                      *TLSConfig                          `koanf:"tls"`
 	HighAvailability         *RedisHighAvailabilityConfiguration `koanf:"high_availability"`
+	Cluster                  *RedisClusterConfiguration          `koanf:"cluster"`
+}
+
+// SessionSQLConfiguration represents the configuration related to the SQL session store.
+type SessionSQLConfiguration struct {
+	Enabled         bool          `koanf:"enabled"`
+	CleanupInterval time.Duration `koanf:"cleanup_interval"`
+}
+
+// SessionBindingConfiguration represents the configuration related to binding user sessions to client characteristics.
+type SessionBindingConfiguration struct {
+	IP                         bool          `koanf:"ip"`
+	IPv4Mask                   int           `koanf:"ipv4_mask"`
+	IPv6Mask                   int           `koanf:"ipv6_mask"`
+	UserAgent                  bool          `koanf:"user_agent"`
+	AntiReplay                 bool          `koanf:"anti_replay"`
+	AntiReplayRotationInterval time.Duration `koanf:"anti_replay_rotation_interval"`
+	Policy                     string        `koanf:"policy"`
 }
 
 // SessionConfiguration represents the configuration related to user sessions.
@@ -40,11 +66,50 @@ type SessionConfiguration struct {
 	Domain             string        `koanf:"domain"`
 	SameSite           string        `koanf:"same_site"`
 	Secret             string        `koanf:"secret"`
+	PreviousSecrets    []string      `koanf:"previous_secrets"`
 	Expiration         time.Duration `koanf:"expiration"`
 	Inactivity         time.Duration `koanf:"inactivity"`
 	RememberMeDuration time.Duration `koanf:"remember_me_duration"`
 
+	Cookies []SessionCookieConfiguration `koanf:"cookies"`
+
+	Binding SessionBindingConfiguration `koanf:"binding"`
+
 	Redis *RedisSessionConfiguration `koanf:"redis"`
+	SQL   SessionSQLConfiguration    `koanf:"sql"`
+}
+
+// SessionCookieConfiguration represents the configuration of a session cookie for a specific domain.
+type SessionCookieConfiguration struct {
+	Name               string        `koanf:"name"`
+	Domain             string        `koanf:"domain"`
+	SameSite           string        `koanf:"same_site"`
+	Expiration         time.Duration `koanf:"expiration"`
+	Inactivity         time.Duration `koanf:"inactivity"`
+	RememberMeDuration time.Duration `koanf:"remember_me_duration"`
+
+	AutheliaURL *url.URL `koanf:"authelia_url"`
+}
+
+// GetCookies returns all of the session cookie configurations. If the legacy domain option is configured it is
+// returned as the first session cookie configuration using the top level options.
+func (c SessionConfiguration) GetCookies() (cookies []SessionCookieConfiguration) {
+	if c.Domain == "" {
+		return c.Cookies
+	}
+
+	cookies = make([]SessionCookieConfiguration, 0, len(c.Cookies)+1)
+
+	cookies = append(cookies, SessionCookieConfiguration{
+		Name:               c.Name,
+		Domain:             c.Domain,
+		SameSite:           c.SameSite,
+		Expiration:         c.Expiration,
+		Inactivity:         c.Inactivity,
+		RememberMeDuration: c.RememberMeDuration,
+	})
+
+	return append(cookies, c.Cookies...)
 }
 
 // DefaultSessionConfiguration is the default session configuration.
@@ -56,6 +121,24 @@ var DefaultSessionConfiguration = SessionConfiguration{
 	SameSite:           "lax",
 }
 
+// Enabled returns true if any of the client characteristics are bound to the user session.
+func (c SessionBindingConfiguration) Enabled() bool {
+	return c.IP || c.UserAgent || c.AntiReplay
+}
+
+// DefaultSessionBindingConfiguration is the default session binding configuration.
+var DefaultSessionBindingConfiguration = SessionBindingConfiguration{
+	IPv4Mask:                   24,
+	IPv6Mask:                   64,
+	AntiReplayRotationInterval: time.Minute * 5,
+	Policy:                     SessionBindingPolicyReauthenticate,
+}
+
+// DefaultSessionSQLConfiguration is the default SQL session store configuration.
+var DefaultSessionSQLConfiguration = SessionSQLConfiguration{
+	CleanupInterval: time.Minute * 5,
+}
+
 // DefaultRedisConfiguration is the default redis configuration.
 var DefaultRedisConfiguration = RedisSessionConfiguration{
 	TLS: &TLSConfig{
@@ -51,7 +51,8 @@ type StorageConfiguration struct {
 	MySQL      *MySQLStorageConfiguration      `koanf:"mysql"`
 	PostgreSQL *PostgreSQLStorageConfiguration `koanf:"postgres"`
 
-	EncryptionKey string `koanf:"encryption_key"`
+	EncryptionKey          string   `koanf:"encryption_key"`
+	PreviousEncryptionKeys []string `koanf:"previous_encryption_keys"`
 }
 
 // DefaultSQLStorageConfiguration represents the default SQL configuration.
@@ -15,8 +15,9 @@ type TelemetryMetricsConfig struct {
 	Enabled bool     `koanf:"enabled"`
 	Address *Address `koanf:"address"`
 
-	Buffers  ServerBuffers  `koanf:"buffers"`
-	Timeouts ServerTimeouts `koanf:"timeouts"`
+	Buffers       ServerBuffers       `koanf:"buffers"`
+	Timeouts      ServerTimeouts      `koanf:"timeouts"`
+	ProxyProtocol ServerProxyProtocol `koanf:"proxy_protocol"`
 }
 
 // DefaultTelemetryConfig is the default telemetry configuration.
@@ -32,5 +33,6 @@ var DefaultTelemetryConfig = TelemetryConfig{
 			Write: time.Second * 6,
 			Idle:  time.Second * 30,
 		},
+		ProxyProtocol: DefaultServerProxyProtocol,
 	},
 }
@@ -140,6 +140,25 @@ type PasswordDigest struct {
 	algorithm.Digest
 }
 
+// PlainText returns the plain text value of the digest if it's a plaintext digest.
+func (d *PasswordDigest) PlainText() (value []byte, ok bool) {
+	if d == nil || d.Digest == nil {
+		return nil, false
+	}
+
+	if _, ok = d.Digest.(*plaintext.Digest); !ok {
+		return nil, false
+	}
+
+	parts := strings.SplitN(d.Encode(), "$", 3)
+
+	if len(parts) != 3 || parts[1] != plaintext.AlgIdentifierPlainText {
+		return nil, false
+	}
+
+	return []byte(parts[2]), true
+}
+
 // NewX509CertificateChain creates a new *X509CertificateChain from a given string, parsing each PEM block one by one.
 func NewX509CertificateChain(in string) (chain *X509CertificateChain, err error) {
 	if in == "" {
@@ -56,6 +56,35 @@ func TestNewAddressFromString(t *testing.T) {
 	}
 }
 
+func TestPasswordDigest_PlainText(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected []byte
+		ok       bool
+	}{
+		{"ShouldDecodePlainText", "$plaintext$example", []byte("example"), true},
+		{"ShouldNotDecodeHash", "$pbkdf2-sha512$310000$c8p78n7pUMln0jzvd4aK4Q$JNRBzwAo0ek5qKn50cFzzvE9RXV88h1wJn5KGiHrD0YKtZaR/nCb2CJPOsKaPK0hjf.9yHxzQGZziziccp6Yng", nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			digest, err := DecodePasswordDigest(tc.have)
+			require.NoError(t, err)
+
+			value, ok := digest.PlainText()
+
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+
+	value, ok := (*PasswordDigest)(nil).PlainText()
+
+	assert.False(t, ok)
+	assert.Nil(t, value)
+}
+
 func TestNewX509CertificateChain(t *testing.T) {
 	testCases := []struct {
 		name             string
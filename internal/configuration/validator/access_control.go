@@ -1,8 +1,10 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"regexp"
 	"strings"
 
@@ -18,7 +20,7 @@ func IsPolicyValid(policy string) (isValid bool) {
 
 // IsSubjectValid check if a subject is valid.
 func IsSubjectValid(subject string) (isValid bool) {
-	return subject == "" || strings.HasPrefix(subject, "user:") || strings.HasPrefix(subject, "group:")
+	return subject == "" || strings.HasPrefix(subject, "user:") || strings.HasPrefix(subject, "group:") || strings.HasPrefix(subject, "oauth2:client:")
 }
 
 // IsNetworkGroupValid check if a network group is valid.
@@ -71,6 +73,83 @@ func ValidateAccessControl(config *schema.Configuration, validator *schema.Struc
 			}
 		}
 	}
+
+	validateHeaders("access control", config.AccessControl.Headers, validator)
+
+	validateIdentityAssertion(config, validator)
+}
+
+func validateIdentityAssertion(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.AccessControl.IdentityAssertion.Enable {
+		return
+	}
+
+	if config.IdentityProviders.OIDC == nil {
+		validator.Push(errors.New(errStrAccessControlIdentityAssertionNoOIDC))
+	}
+
+	switch {
+	case config.AccessControl.IdentityAssertion.Header == "":
+		config.AccessControl.IdentityAssertion.Header = schema.DefaultACLIdentityAssertionConfiguration.Header
+	case !reHeaderName.MatchString(config.AccessControl.IdentityAssertion.Header):
+		validator.Push(fmt.Errorf(errFmtAccessControlIdentityAssertionHeader, config.AccessControl.IdentityAssertion.Header))
+	}
+
+	switch {
+	case config.AccessControl.IdentityAssertion.Lifespan == 0:
+		config.AccessControl.IdentityAssertion.Lifespan = schema.DefaultACLIdentityAssertionConfiguration.Lifespan
+	case config.AccessControl.IdentityAssertion.Lifespan < 0:
+		validator.Push(fmt.Errorf(errFmtAccessControlIdentityAssertionLifespan, config.AccessControl.IdentityAssertion.Lifespan))
+	}
+
+	if config.AccessControl.IdentityAssertion.Issuer != "" {
+		if issuer, err := url.Parse(config.AccessControl.IdentityAssertion.Issuer); err != nil || !issuer.IsAbs() {
+			validator.Push(fmt.Errorf(errFmtAccessControlIdentityAssertionIssuer, config.AccessControl.IdentityAssertion.Issuer))
+		}
+	}
+}
+
+func validateIdentityAssertionAudience(rulePosition int, rule schema.ACLRule, config *schema.Configuration, validator *schema.StructValidator) {
+	if len(rule.IdentityAssertionAudience) == 0 {
+		return
+	}
+
+	if !config.AccessControl.IdentityAssertion.Enable {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleIdentityAssertionAudienceDisabled, ruleDescriptor(rulePosition, rule)))
+
+		return
+	}
+
+	for i, audience := range rule.IdentityAssertionAudience {
+		if audience == "" {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleIdentityAssertionAudience, ruleDescriptor(rulePosition, rule), i+1))
+		}
+	}
+}
+
+func validateHeaders(descriptor string, headers []schema.ACLHeader, validator *schema.StructValidator) {
+	names := make([]string, 0, len(headers))
+
+	for i, header := range headers {
+		switch {
+		case header.Name == "":
+			validator.Push(fmt.Errorf(errFmtAccessControlHeaderNoName, descriptor, i+1))
+
+			continue
+		case !reHeaderName.MatchString(header.Name):
+			validator.Push(fmt.Errorf(errFmtAccessControlHeaderInvalidName, descriptor, header.Name))
+
+			continue
+		case utils.IsStringInSliceFold(header.Name, names):
+			validator.Push(fmt.Errorf(errFmtAccessControlHeaderDuplicateName, descriptor, header.Name))
+		}
+
+		names = append(names, header.Name)
+
+		if _, err := authorization.NewAccessControlHeader(header); err != nil {
+			validator.Push(fmt.Errorf(errFmtAccessControlHeaderInvalidValue, descriptor, err))
+		}
+	}
 }
 
 // ValidateRules validates an ACL Rule configuration.
@@ -104,6 +183,10 @@ func ValidateRules(config *schema.Configuration, validator *schema.StructValidat
 
 		validateQuery(i, rule, config, validator)
 
+		validateHeaders(fmt.Sprintf("access control: rule %s", ruleDescriptor(rulePosition, rule)), rule.Headers, validator)
+
+		validateIdentityAssertionAudience(rulePosition, rule, config, validator)
+
 		if rule.Policy == policyBypass {
 			validateBypass(rulePosition, rule, validator)
 		}
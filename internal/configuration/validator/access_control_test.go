@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -213,7 +214,7 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidSubject() {
 	suite.Require().Len(suite.validator.Warnings(), 0)
 	suite.Require().Len(suite.validator.Errors(), 2)
 
-	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'subject' option 'invalid' is invalid: must start with 'user:' or 'group:'")
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'subject' option 'invalid' is invalid: must start with 'user:', 'group:', or 'oauth2:client:'")
 	suite.Assert().EqualError(suite.validator.Errors()[1], fmt.Sprintf(errAccessControlRuleBypassPolicyInvalidWithSubjects, ruleDescriptor(1, suite.config.AccessControl.Rules[0])))
 }
 
@@ -233,6 +234,96 @@ func (suite *AccessControl) TestShouldRaiseErrorBypassWithSubjectDomainRegexGrou
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1: 'policy' option 'bypass' is not supported when 'domain_regex' option contains the user or group named matches. For more information see: https://www.authelia.com/c/acl-match-concept-2")
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorInvalidHeaders() {
+	suite.config.AccessControl.Headers = []schema.ACLHeader{
+		{Name: "", Value: "{{ .Username }}"},
+		{Name: "Remote User", Value: "{{ .Username }}"},
+		{Name: "Remote-User", Value: "{{ .Username }}"},
+	}
+
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains: []string{"public.example.com"},
+			Policy:  "one_factor",
+			Headers: []schema.ACLHeader{
+				{Name: "X-Forwarded-User", Value: "{{ .Username }}"},
+				{Name: "x-forwarded-user", Value: "{{ .Username }}"},
+				{Name: "X-Forwarded-Groups", Value: "{{ toJson .Groups"},
+			},
+		},
+	}
+
+	ValidateAccessControl(suite.config, suite.validator)
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 4)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: 'headers' option 'name' is invalid: header #1 must have a value")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: 'headers' option 'name' with value 'Remote User' is invalid: must only contain valid header field name characters")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: rule #1 (domain 'public.example.com'): 'headers' option 'name' with value 'x-forwarded-user' is invalid: must be unique but it's configured more than once")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: rule #1 (domain 'public.example.com'): 'headers' option 'value' is invalid: header 'X-Forwarded-Groups' has an invalid value template: template: X-Forwarded-Groups:1: unclosed action")
+}
+
+func (suite *AccessControl) TestShouldSetIdentityAssertionDefaults() {
+	suite.config.IdentityProviders.OIDC = &schema.OpenIDConnectConfiguration{}
+	suite.config.AccessControl.IdentityAssertion.Enable = true
+
+	ValidateAccessControl(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultACLIdentityAssertionConfiguration.Header, suite.config.AccessControl.IdentityAssertion.Header)
+	suite.Assert().Equal(schema.DefaultACLIdentityAssertionConfiguration.Lifespan, suite.config.AccessControl.IdentityAssertion.Lifespan)
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorInvalidIdentityAssertion() {
+	suite.config.AccessControl.IdentityAssertion = schema.ACLIdentityAssertionConfiguration{
+		Enable:   true,
+		Header:   "Remote Identity",
+		Issuer:   "auth.example.com",
+		Lifespan: -time.Second,
+	}
+
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:                   []string{"public.example.com"},
+			Policy:                    "one_factor",
+			IdentityAssertionAudience: []string{"app", ""},
+		},
+	}
+
+	ValidateAccessControl(suite.config, suite.validator)
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 5)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: identity_assertion: option 'enable' is invalid: the OpenID Connect 1.0 provider must be configured so the signing key is published in the JSON Web Key Set")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "access control: identity_assertion: option 'header' with value 'Remote Identity' is invalid: must only contain valid header field name characters")
+	suite.Assert().EqualError(suite.validator.Errors()[2], "access control: identity_assertion: option 'lifespan' must be greater than 0 but it is configured as '-1s'")
+	suite.Assert().EqualError(suite.validator.Errors()[3], "access control: identity_assertion: option 'issuer' with value 'auth.example.com' is invalid: must be an absolute URL")
+	suite.Assert().EqualError(suite.validator.Errors()[4], "access control: rule #1 (domain 'public.example.com'): 'identity_assertion_audience' option is invalid: audience #2 must have a value")
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorIdentityAssertionAudienceWhenDisabled() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:                   []string{"public.example.com"},
+			Policy:                    "one_factor",
+			IdentityAssertionAudience: []string{"app"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'identity_assertion_audience' option is invalid: the identity assertion is not enabled")
+}
+
 func (suite *AccessControl) TestShouldSetQueryDefaults() {
 	domains := []string{"public.example.com"}
 	suite.config.AccessControl.Rules = []schema.ACLRule{
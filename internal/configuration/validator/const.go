@@ -126,6 +126,8 @@ const (
 	errStrStorage                                 = "storage: configuration for a 'local', 'mysql' or 'postgres' database must be provided"
 	errStrStorageEncryptionKeyMustBeProvided      = "storage: option 'encryption_key' is required"
 	errStrStorageEncryptionKeyTooShort            = "storage: option 'encryption_key' must be 20 characters or longer"
+	errFmtStoragePreviousEncryptionKeyTooShort    = "storage: option 'previous_encryption_keys' must only contain values 20 characters or longer but value #%d is shorter"
+	errFmtStoragePreviousEncryptionKeyIsCurrent   = "storage: option 'previous_encryption_keys' must not contain the value of option 'encryption_key' but value #%d is the same"
 	errFmtStorageUserPassMustBeProvided           = "storage: %s: option 'username' and 'password' are required" //nolint:gosec
 	errFmtStorageOptionMustBeProvided             = "storage: %s: option '%s' is required"
 	errFmtStorageTLSConfigInvalid                 = "storage: %s: tls: %w"
@@ -147,7 +149,25 @@ const (
 	errFmtOIDCInvalidPrivateKeyBitSize = "identity_providers: oidc: option 'issuer_private_key' must be an RSA private key with %d bits or more but it only has %d bits"
 	errFmtOIDCCertificateMismatch      = "identity_providers: oidc: option 'issuer_private_key' does not appear to be the private key the certificate provided by option 'issuer_certificate_chain'"
 	errFmtOIDCCertificateChain         = "identity_providers: oidc: option 'issuer_certificate_chain' produced an error during validation of the chain: %w"
-	errFmtOIDCEnforcePKCEInvalidValue  = "identity_providers: oidc: option 'enforce_pkce' must be 'never', " +
+	errFmtOIDCPrivateKeysLegacy        = "identity_providers: oidc: option 'issuer_private_key' and option 'issuer_private_keys' must not both be configured"
+	errFmtOIDCPrivateKeysNoRS256       = "identity_providers: oidc: option 'issuer_private_keys' must contain at least one key with the algorithm '%s'"
+	errFmtOIDCPrivateKeysNoKey         = "identity_providers: oidc: issuer_private_keys: key #%d: option 'key' is required"
+	errFmtOIDCPrivateKeysKeyType       = "identity_providers: oidc: issuer_private_keys: key #%d: option 'key' must be an RSA private key, " +
+		"an ECDSA private key using the P-256, P-384, or P-521 curve, or an Ed25519 private key"
+	errFmtOIDCPrivateKeysEncryptionKeyType = "identity_providers: oidc: issuer_private_keys: key #%d: option 'key' must be an RSA private key " +
+		"or an ECDSA private key when option 'use' is 'enc'"
+	errFmtOIDCPrivateKeysEncryptionShared = "identity_providers: oidc: issuer_private_keys: key #%d: option 'key' must not be the same " +
+		"private key as a key with option 'use' configured as 'sig' when option 'use' is 'enc'"
+	errFmtOIDCPrivateKeysInvalidUse       = "identity_providers: oidc: issuer_private_keys: key #%d: option 'use' must be one of '%s' but it is configured as '%s'"
+	errFmtOIDCPrivateKeysInvalidAlgorithm = "identity_providers: oidc: issuer_private_keys: key #%d: option 'algorithm' must be one of " +
+		"'%s' for the configured key but it is configured as '%s'"
+	errFmtOIDCPrivateKeysBitSize          = "identity_providers: oidc: issuer_private_keys: key #%d: option 'key' must be an RSA private key with %d bits or more but it only has %d bits"
+	errFmtOIDCPrivateKeysDuplicateKeyID   = "identity_providers: oidc: issuer_private_keys: key #%d: option 'key_id' with value '%s' must be unique but it is used by more than one key"
+	errFmtOIDCPrivateKeysCertificateMatch = "identity_providers: oidc: issuer_private_keys: key #%d: option 'key' does not appear to be the private key the certificate provided by option 'certificate_chain'"
+	errFmtOIDCPrivateKeysCertificateChain = "identity_providers: oidc: issuer_private_keys: key #%d: option 'certificate_chain' produced an error during validation of the chain: %w"
+	errFmtOIDCPrivateKeysNotAfter         = "identity_providers: oidc: issuer_private_keys: key #%d: option 'not_after' must be after option 'not_before'"
+	errFmtOIDCNegativeDuration            = "identity_providers: oidc: option '%s' must not be a negative duration but it is configured as '%s'"
+	errFmtOIDCEnforcePKCEInvalidValue     = "identity_providers: oidc: option 'enforce_pkce' must be 'never', " +
 		"'public_clients_only' or 'always', but it is configured as '%s'"
 
 	errFmtOIDCCORSInvalidOrigin                    = "identity_providers: oidc: cors: option 'allowed_origins' contains an invalid value '%s' as it has a %s: origins must only be scheme, hostname, and an optional port"
@@ -155,6 +175,31 @@ const (
 	errFmtOIDCCORSInvalidOriginWildcardWithClients = "identity_providers: oidc: cors: option 'allowed_origins' contains the wildcard origin '*' cannot be specified with option 'allowed_origins_from_client_redirect_uris' enabled"
 	errFmtOIDCCORSInvalidEndpoint                  = "identity_providers: oidc: cors: option 'endpoints' contains an invalid value '%s': must be one of '%s'"
 
+	errFmtOIDCDynamicClientRegistrationNoInitialAccessToken = "identity_providers: oidc: dynamic_client_registration: " +
+		"option 'initial_access_token' is required when dynamic client registration is enabled"
+
+	errFmtOIDCScopeInvalidName = "identity_providers: oidc: scopes: scope '%s': the name must not be empty or " +
+		"contain whitespace"
+	errFmtOIDCScopeStandardName = "identity_providers: oidc: scopes: scope '%s': the name must not be the same " +
+		"as one of the standard scopes '%s'"
+
+	errFmtOIDCScopeUnknownClaim = "identity_providers: oidc: scopes: scope '%s': option 'claims' contains the " +
+		"claim '%s' but it is not defined by the 'claims' option or the 'claims' option of any client"
+
+	errFmtOIDCClaimInvalidName = "identity_providers: oidc: %sclaims: claim '%s': the name must not be empty or " +
+		"contain whitespace"
+	errFmtOIDCClaimReservedName = "identity_providers: oidc: %sclaims: claim '%s': the name must not be the same " +
+		"as one of the reserved claims '%s'"
+	errFmtOIDCClaimNoSource = "identity_providers: oidc: %sclaims: claim '%s': one of the options 'attribute', " +
+		"'value', or 'template' must be configured"
+	errFmtOIDCClaimMultipleSources = "identity_providers: oidc: %sclaims: claim '%s': only one of the options " +
+		"'attribute', 'value', or 'template' may be configured"
+	errFmtOIDCClaimInvalidAttribute = "identity_providers: oidc: %sclaims: claim '%s': option 'attribute' must be " +
+		"one of '%s' but it is configured as '%s'"
+	errFmtOIDCClaimInvalidFilter = "identity_providers: oidc: %sclaims: claim '%s': option 'filter' may only be " +
+		"configured when option 'attribute' is one of '%s'"
+	errFmtOIDCClaimInvalidDefinition = "identity_providers: oidc: %sclaims: claim '%s': %v"
+
 	errFmtOIDCClientsDuplicateID = "identity_providers: oidc: one or more clients have the same id but all client" +
 		"id's must be unique"
 	errFmtOIDCClientsWithEmptyID = "identity_providers: oidc: one or more clients have been configured with " +
@@ -170,14 +215,57 @@ const (
 		"for the openid connect confidential client type"
 	errFmtOIDCClientRedirectURIAbsolute = "identity_providers: oidc: client '%s': option 'redirect_uris' has an " +
 		"invalid value: redirect uri '%s' must have the scheme but it is absent"
+	errFmtOIDCClientLogoutURICantBeParsed = "identity_providers: oidc: client '%s': option '%s' has an " +
+		"invalid value: uri '%s' could not be parsed: %v"
+	errFmtOIDCClientLogoutURIInvalid = "identity_providers: oidc: client '%s': option '%s' has an " +
+		"invalid value: uri '%s' must be an absolute uri with the 'http' or 'https' scheme and without a fragment"
 	errFmtOIDCClientInvalidPolicy = "identity_providers: oidc: client '%s': option 'policy' must be 'one_factor' " +
 		"or 'two_factor' but it is configured as '%s'"
 	errFmtOIDCClientInvalidConsentMode = "identity_providers: oidc: client '%s': consent: option 'mode' must be one of " +
 		"'%s' but it is configured as '%s'"
+	errFmtOIDCClientInvalidGrantTypePublic = "identity_providers: oidc: client '%s': option 'grant_types' " +
+		"must not contain the '%s' grant type when option 'public' is true"
+	errFmtOIDCClientTokenExchangeNoAudience = "identity_providers: oidc: client '%s': token_exchange: option " +
+		"'audience' must be configured when option 'grant_types' contains the '%s' grant type"
+	errFmtOIDCClientInvalidTokenLifespan = "identity_providers: oidc: client '%s': token_lifespans: option '%s' " +
+		"must not be a negative duration but it is configured as '%s'"
 	errFmtOIDCClientInvalidEntry = "identity_providers: oidc: client '%s': option '%s' must only have the values " +
 		"'%s' but one option is configured as '%s'"
+	errFmtOIDCClientInvalidIDTokenAlgorithm = "identity_providers: oidc: client '%s': option " +
+		"'id_token_signing_algorithm' must be one of '%s' but it is configured as '%s'"
 	errFmtOIDCClientInvalidUserinfoAlgorithm = "identity_providers: oidc: client '%s': option " +
 		"'userinfo_signing_algorithm' must be one of '%s' but it is configured as '%s'"
+	errFmtOIDCClientInvalidAccessTokenAlgorithm = "identity_providers: oidc: client '%s': option " +
+		"'access_token_signing_algorithm' must be one of '%s' but it is configured as '%s'"
+	errFmtOIDCClientInvalidTokenEndpointAuthMethod = "identity_providers: oidc: client '%s': option " +
+		"'token_endpoint_auth_method' must be one of '%s' but it is configured as '%s'"
+	errFmtOIDCClientInvalidTokenEndpointAuthMethodPublic = "identity_providers: oidc: client '%s': option " +
+		"'token_endpoint_auth_method' must not be '%s' when option 'public' is %s"
+	errFmtOIDCClientInvalidTokenEndpointAuthMethodSecretJWT = "identity_providers: oidc: client '%s': option " +
+		"'secret' must be a plaintext value when option 'token_endpoint_auth_method' is '%s'"
+	errFmtOIDCClientInvalidTokenEndpointAuthMethodJWKS = "identity_providers: oidc: client '%s': option " +
+		"'jwks' or 'jwks_path' is required when option 'token_endpoint_auth_method' is '%s'"
+	errFmtOIDCClientInvalidTokenEndpointAuthSigningAlgorithm = "identity_providers: oidc: client '%s': option " +
+		"'token_endpoint_auth_signing_algorithm' must be one of '%s' when option 'token_endpoint_auth_method' is '%s' but it is configured as '%s'"
+	errFmtOIDCClientInvalidTokenEndpointAuthSigningAlgorithmMethod = "identity_providers: oidc: client '%s': option " +
+		"'token_endpoint_auth_signing_algorithm' must only be configured when option 'token_endpoint_auth_method' is " +
+		"'private_key_jwt' or 'client_secret_jwt' but it is configured as '%s'"
+	errFmtOIDCClientInvalidRequestObjectSigningAlgorithm = "identity_providers: oidc: client '%s': option " +
+		"'request_object_signing_algorithm' must be one of '%s' but it is configured as '%s'"
+	errFmtOIDCClientInvalidRequestObjectSigningAlgorithmNone = "identity_providers: oidc: client '%s': option " +
+		"'request_object_signing_algorithm' must not be 'none' when option 'require_signed_request_object' is true"
+	errFmtOIDCClientInvalidRequestObjectJWKS = "identity_providers: oidc: client '%s': option " +
+		"'jwks' or 'jwks_path' is required when option 'request_object_signing_algorithm' is '%s'"
+	errFmtOIDCClientInvalidJWKSBoth = "identity_providers: oidc: client '%s': option " +
+		"'jwks' and option 'jwks_path' must not both be configured"
+	errFmtOIDCClientInvalidJWKSPath = "identity_providers: oidc: client '%s': option " +
+		"'jwks_path' could not be read: %w"
+	errFmtOIDCClientInvalidJWKS = "identity_providers: oidc: client '%s': option " +
+		"'jwks' could not be parsed as a JSON Web Key Set: %w"
+	errFmtOIDCClientInvalidJWKSPrivateKey = "identity_providers: oidc: client '%s': option " +
+		"'jwks' must only contain public keys but key #%d is a private key"
+	errFmtOIDCClientInvalidJWKSNoCompatibleKey = "identity_providers: oidc: client '%s': option " +
+		"'jwks' must contain at least one signing key compatible with the '%s' algorithm"
 	errFmtOIDCClientInvalidSectorIdentifier = "identity_providers: oidc: client '%s': option " +
 		"'sector_identifier' with value '%s': must be a URL with only the host component for example '%s' but it has a %s with the value '%s'"
 	errFmtOIDCClientInvalidSectorIdentifierWithoutValue = "identity_providers: oidc: client '%s': option " +
@@ -217,7 +305,7 @@ const (
 	errFmtAccessControlRuleNetworksInvalid = "access control: rule %s: the network '%s' is not a " +
 		"valid Group Name, IP, or CIDR notation"
 	errFmtAccessControlRuleSubjectInvalid = "access control: rule %s: 'subject' option '%s' is " +
-		"invalid: must start with 'user:' or 'group:'"
+		"invalid: must start with 'user:', 'group:', or 'oauth2:client:'"
 	errFmtAccessControlRuleMethodInvalid = "access control: rule %s: 'methods' option '%s' is " +
 		"invalid: must be one of '%s'"
 	errFmtAccessControlRuleQueryInvalid = "access control: rule %s: 'query' option 'operator' with value '%s' is " +
@@ -232,6 +320,25 @@ const (
 		"invalid: %w"
 	errFmtAccessControlRuleQueryInvalidValueType = "access control: rule %s: 'query' option 'value' is " +
 		"invalid: expected type was string but got %T"
+	errFmtAccessControlHeaderNoName      = "%s: 'headers' option 'name' is invalid: header #%d must have a value"
+	errFmtAccessControlHeaderInvalidName = "%s: 'headers' option 'name' with value '%s' is invalid: must only " +
+		"contain valid header field name characters"
+	errFmtAccessControlHeaderDuplicateName = "%s: 'headers' option 'name' with value '%s' is invalid: must be unique " +
+		"but it's configured more than once"
+	errFmtAccessControlHeaderInvalidValue = "%s: 'headers' option 'value' is invalid: %w"
+
+	errStrAccessControlIdentityAssertionNoOIDC = "access control: identity_assertion: option 'enable' is invalid: " +
+		"the OpenID Connect 1.0 provider must be configured so the signing key is published in the JSON Web Key Set"
+	errFmtAccessControlIdentityAssertionHeader = "access control: identity_assertion: option 'header' with value '%s' " +
+		"is invalid: must only contain valid header field name characters"
+	errFmtAccessControlIdentityAssertionLifespan = "access control: identity_assertion: option 'lifespan' must be " +
+		"greater than 0 but it is configured as '%s'"
+	errFmtAccessControlIdentityAssertionIssuer = "access control: identity_assertion: option 'issuer' with value '%s' " +
+		"is invalid: must be an absolute URL"
+	errFmtAccessControlRuleIdentityAssertionAudience = "access control: rule %s: 'identity_assertion_audience' " +
+		"option is invalid: audience #%d must have a value"
+	errFmtAccessControlRuleIdentityAssertionAudienceDisabled = "access control: rule %s: 'identity_assertion_audience' " +
+		"option is invalid: the identity assertion is not enabled"
 )
 
 // Theme Error constants.
@@ -249,14 +356,29 @@ const (
 	errFmtSessionOptionRequired           = "session: option '%s' is required"
 	errFmtSessionDomainMustBeRoot         = "session: option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '%s'"
 	errFmtSessionSameSite                 = "session: option 'same_site' must be one of '%s' but is configured as '%s'"
+	errFmtSessionCookiesDomainRequired    = "session: cookies: #%d: option 'domain' is required"
+	errFmtSessionCookiesDomainMustBeRoot  = "session: cookies: #%d (domain '%s'): option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '%s'"
+	errFmtSessionCookiesDomainDuplicate   = "session: cookies: #%d (domain '%s'): option 'domain' is a duplicate value for another configured session cookie domain"
+	errFmtSessionCookiesSameSite          = "session: cookies: #%d (domain '%s'): option 'same_site' must be one of '%s' but is configured as '%s'"
+	errFmtSessionCookiesAutheliaURLScheme = "session: cookies: #%d (domain '%s'): option 'authelia_url' must have the 'https' scheme but is configured as '%s'"
+	errFmtSessionCookiesAutheliaURLDomain = "session: cookies: #%d (domain '%s'): option 'authelia_url' does not share a cookie scope with the domain '%s'"
 	errFmtSessionSecretRequired           = "session: option 'secret' is required when using the '%s' provider"
+	errFmtSessionBindingPolicy            = "session: binding: option 'policy' must be one of '%s' but is configured as '%s'"
+	errFmtSessionBindingMask              = "session: binding: option '%s' must be between 1 and %d but is configured as '%d'"
+	errFmtSessionPreviousSecretsEmpty     = "session: option 'previous_secrets' must not contain empty values but value #%d is empty"
 	errFmtSessionRedisPortRange           = "session: redis: option 'port' must be between 1 and 65535 but is configured as '%d'"
 	errFmtSessionRedisHostRequired        = "session: redis: option 'host' is required"
 	errFmtSessionRedisHostOrNodesRequired = "session: redis: option 'host' or the 'high_availability' option 'nodes' is required"
 	errFmtSessionRedisTLSConfigInvalid    = "session: redis: tls: %w"
+	errFmtSessionSQLRedisConfigured       = "session: sql: option 'enabled' must not be true when the 'redis' provider is configured"
 
 	errFmtSessionRedisSentinelMissingName     = "session: redis: high_availability: option 'sentinel_name' is required"
 	errFmtSessionRedisSentinelNodeHostMissing = "session: redis: high_availability: option 'nodes': option 'host' is required for each node but one or more nodes are missing this"
+
+	errFmtSessionRedisClusterHostOrNodesRequired = "session: redis: option 'host' or the 'cluster' option 'nodes' is required"
+	errFmtSessionRedisClusterHighAvailability    = "session: redis: option 'cluster' and option 'high_availability' must not both be configured"
+	errFmtSessionRedisClusterDatabaseIndex       = "session: redis: option 'database_index' must be 0 when option 'cluster' is configured as redis cluster only supports database 0 but is configured as '%d'"
+	errFmtSessionRedisClusterNodeHostMissing     = "session: redis: cluster: option 'nodes': option 'host' is required for each node but one or more nodes are missing this"
 )
 
 // Regulation Error Consts.
@@ -273,8 +395,22 @@ const (
 	errFmtServerTLSClientAuthCertFileDoesNotExist = "server: tls: client_certificates: certificates: file path %s does not exist"
 	errFmtServerTLSClientAuthNoAuth               = "server: tls: client authentication cannot be configured if no server certificate and key are provided"
 
+	errFmtProxyProtocolMode           = "%s: proxy_protocol: option 'mode' must be one of '%s' but it's configured as '%s'"
+	errFmtProxyProtocolTrustedSources = "%s: proxy_protocol: option 'trusted_sources' is required when the PROXY protocol is enabled"
+
 	errFmtServerPathNoForwardSlashes = "server: option 'path' must not contain any forward slashes"
 	errFmtServerPathAlphaNum         = "server: option 'path' must only contain alpha numeric characters"
+
+	errFmtServerEndpointsAuthzImplementation    = "server: endpoints: authz: %s: option 'implementation' must be one of '%s' but is configured as '%s'"
+	errFmtServerEndpointsAuthzStrategy          = "server: endpoints: authz: %s: authn_strategies: option 'name' must be one of '%s' but is configured as '%s'"
+	errFmtServerEndpointsAuthzStrategyDuplicate = "server: endpoints: authz: %s: authn_strategies: duplicate strategy name detected with name '%s'"
+	errFmtServerEndpointsAuthzPrefixDuplicate   = "server: endpoints: authz: %s: endpoint starts with the same prefix as the '%s' endpoint with the '%s' implementation which accepts prefixes as part of its implementation"
+	errFmtServerEndpointsAuthzInvalidName       = "server: endpoints: authz: %s: contains invalid characters"
+
+	errFmtServerEndpointsAuthzLegacyInvalidImplementation = "server: endpoints: authz: %s: option 'implementation' is invalid: the endpoint with the name 'legacy' must use the 'Legacy' implementation"
+	errFmtServerEndpointsAuthzLegacyStrategies            = "server: endpoints: authz: %s: option 'authn_strategies' is invalid: the 'Legacy' implementation does not support configuring authentication strategies"
+
+	errFmtServerEndpointsAdminGroups = "server: endpoints: admin: option 'groups' must have at least one group when the admin endpoints are enabled"
 )
 
 const (
@@ -338,6 +474,7 @@ var (
 	validStoragePostgreSQLSSLModes           = []string{"disable", "require", "verify-ca", "verify-full"}
 	validThemeNames                          = []string{"light", "dark", "grey", "auto"}
 	validSessionSameSiteValues               = []string{"none", "lax", "strict"}
+	validSessionBindingPolicies              = []string{schema.SessionBindingPolicyLog, schema.SessionBindingPolicyReauthenticate, schema.SessionBindingPolicyDestroy}
 	validLogLevels                           = []string{"trace", "debug", "info", "warn", "error"}
 	validWebauthnConveyancePreferences       = []string{string(protocol.PreferNoAttestation), string(protocol.PreferIndirectAttestation), string(protocol.PreferDirectAttestation)}
 	validWebauthnUserVerificationRequirement = []string{string(protocol.VerificationDiscouraged), string(protocol.VerificationPreferred), string(protocol.VerificationRequired)}
@@ -354,16 +491,55 @@ var (
 var validDefault2FAMethods = []string{"totp", "webauthn", "mobile_push"}
 
 var (
-	validOIDCScopes             = []string{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeProfile, oidc.ScopeGroups, oidc.ScopeOfflineAccess}
-	validOIDCGrantTypes         = []string{oidc.GrantTypeImplicit, oidc.GrantTypeRefreshToken, oidc.GrantTypeAuthorizationCode, oidc.GrantTypePassword, oidc.GrantTypeClientCredentials}
-	validOIDCResponseModes      = []string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery, oidc.ResponseModeFragment}
-	validOIDCUserinfoAlgorithms = []string{oidc.SigningAlgorithmNone, oidc.SigningAlgorithmRSAWithSHA256}
-	validOIDCCORSEndpoints      = []string{oidc.EndpointAuthorization, oidc.EndpointToken, oidc.EndpointIntrospection, oidc.EndpointRevocation, oidc.EndpointUserinfo}
+	validOIDCScopes                         = []string{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeProfile, oidc.ScopeGroups, oidc.ScopeOfflineAccess}
+	validOIDCGrantTypes                     = []string{oidc.GrantTypeImplicit, oidc.GrantTypeRefreshToken, oidc.GrantTypeAuthorizationCode, oidc.GrantTypePassword, oidc.GrantTypeClientCredentials, oidc.GrantTypeDeviceCode, oidc.GrantTypeTokenExchange}
+	validOIDCResponseModes                  = []string{oidc.ResponseModeFormPost, oidc.ResponseModeQuery, oidc.ResponseModeFragment}
+	validOIDCClientTokenEndpointAuthMethods = []string{
+		oidc.ClientAuthMethodClientSecretBasic, oidc.ClientAuthMethodClientSecretPost, oidc.ClientAuthMethodClientSecretJWT,
+		oidc.ClientAuthMethodPrivateKeyJWT, oidc.ClientAuthMethodNone,
+	}
+	validOIDCClientSecretJWTAlgorithms = []string{
+		oidc.SigningAlgorithmHMACWithSHA256, oidc.SigningAlgorithmHMACWithSHA384, oidc.SigningAlgorithmHMACWithSHA512,
+	}
+	validOIDCClientPrivateKeyJWTAlgorithms = []string{
+		oidc.SigningAlgorithmRSAWithSHA256, oidc.SigningAlgorithmRSAWithSHA384, oidc.SigningAlgorithmRSAWithSHA512,
+		oidc.SigningAlgorithmRSAPSSWithSHA256, oidc.SigningAlgorithmRSAPSSWithSHA384, oidc.SigningAlgorithmRSAPSSWithSHA512,
+		oidc.SigningAlgorithmECDSAWithP256AndSHA256, oidc.SigningAlgorithmECDSAWithP384AndSHA384, oidc.SigningAlgorithmECDSAWithP521AndSHA512,
+		oidc.SigningAlgorithmEdDSA,
+	}
+	validOIDCIssuerRSAAlgorithms = []string{
+		oidc.SigningAlgorithmRSAWithSHA256, oidc.SigningAlgorithmRSAWithSHA384, oidc.SigningAlgorithmRSAWithSHA512,
+		oidc.SigningAlgorithmRSAPSSWithSHA256, oidc.SigningAlgorithmRSAPSSWithSHA384, oidc.SigningAlgorithmRSAPSSWithSHA512,
+	}
+	validOIDCCORSEndpoints      = []string{oidc.EndpointAuthorization, oidc.EndpointToken, oidc.EndpointIntrospection, oidc.EndpointRevocation, oidc.EndpointUserinfo, oidc.EndpointDeviceAuthorization, oidc.EndpointPushedAuthorizationRequest, oidc.EndpointRegistration}
 	validOIDCClientConsentModes = []string{"auto", oidc.ClientConsentModeImplicit.String(), oidc.ClientConsentModeExplicit.String(), oidc.ClientConsentModePreConfigured.String()}
 )
 
+var (
+	validOIDCClaimAttributes       = []string{oidc.ClaimAttributeUsername, oidc.ClaimAttributeDisplayName, oidc.ClaimAttributeEmail, oidc.ClaimAttributeEmails, oidc.ClaimAttributeGroups}
+	validOIDCClaimFilterAttributes = []string{oidc.ClaimAttributeEmails, oidc.ClaimAttributeGroups}
+	reservedOIDCClaims             = []string{
+		oidc.ClaimJWTID, oidc.ClaimSessionID, oidc.ClaimAccessTokenHash, oidc.ClaimCodeHash, oidc.ClaimIssuedAt,
+		oidc.ClaimNotBefore, oidc.ClaimRequestedAt, oidc.ClaimExpirationTime, oidc.ClaimAuthenticationTime, oidc.ClaimIssuer,
+		oidc.ClaimSubject, oidc.ClaimNonce, oidc.ClaimAudience, oidc.ClaimGroups, oidc.ClaimFullName,
+		oidc.ClaimPreferredUsername, oidc.ClaimPreferredEmail, oidc.ClaimEmailVerified, oidc.ClaimEmailAlts,
+		oidc.ClaimAuthorizedParty, oidc.ClaimAuthenticationContextClassReference, oidc.ClaimAuthenticationMethodsReference,
+		oidc.ClaimClientIdentifier, oidc.ClaimScope, oidc.ClaimActor,
+	}
+)
+
+var (
+	validProxyProtocolModes   = []string{schema.ProxyProtocolModeStrict, schema.ProxyProtocolModeOptional}
+	validAuthzImplementations = []string{schema.AuthzImplementationAuthRequest, schema.AuthzImplementationForwardAuth, schema.AuthzImplementationExtAuthz, schema.AuthzImplementationLegacy}
+	validAuthzAuthnStrategies = []string{schema.AuthzStrategyHeaderCookieSession, schema.AuthzStrategyHeaderAuthorization, schema.AuthzStrategyHeaderProxyAuthorization, schema.AuthzStrategyHeaderAuthRequestProxyAuthorization}
+)
+
 var reKeyReplacer = regexp.MustCompile(`\[\d+]`)
 
+var reHeaderName = regexp.MustCompile("^[a-zA-Z0-9!#$%&'*+.^_`|~-]+$")
+
+var reAuthzEndpointName = regexp.MustCompile(`^[a-zA-Z](([a-zA-Z0-9/_-]*)([a-zA-Z0-9]))?$`)
+
 var replacedKeys = map[string]string{
 	"authentication_backend.ldap.skip_verify":         "authentication_backend.ldap.tls.skip_verify",
 	"authentication_backend.ldap.minimum_tls_version": "authentication_backend.ldap.tls.minimum_version",
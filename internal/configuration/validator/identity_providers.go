@@ -1,11 +1,18 @@
 package validator
 
 import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"os"
+	"sort"
 	"strings"
 	"time"
 
+	"gopkg.in/square/go-jose.v2"
+
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -24,6 +31,12 @@ func validateOIDC(config *schema.OpenIDConnectConfiguration, val *schema.StructV
 	setOIDCDefaults(config)
 
 	switch {
+	case len(config.IssuerPrivateKeys) != 0:
+		if config.IssuerPrivateKey != nil {
+			val.Push(fmt.Errorf(errFmtOIDCPrivateKeysLegacy))
+		}
+
+		validateOIDCIssuerPrivateKeys(config, val)
 	case config.IssuerPrivateKey == nil:
 		val.Push(fmt.Errorf(errFmtOIDCNoPrivateKey))
 	default:
@@ -50,13 +63,168 @@ func validateOIDC(config *schema.OpenIDConnectConfiguration, val *schema.StructV
 		val.Push(fmt.Errorf(errFmtOIDCEnforcePKCEInvalidValue, config.EnforcePKCE))
 	}
 
+	if config.RefreshTokenAbsoluteLifespan < 0 {
+		val.Push(fmt.Errorf(errFmtOIDCNegativeDuration, "refresh_token_absolute_lifespan", config.RefreshTokenAbsoluteLifespan))
+	}
+
+	if config.RefreshTokenReuseGracePeriod < 0 {
+		val.Push(fmt.Errorf(errFmtOIDCNegativeDuration, "refresh_token_reuse_grace_period", config.RefreshTokenReuseGracePeriod))
+	}
+
 	validateOIDCOptionsCORS(config, val)
+	validateOIDCClaims("", config.Claims, val)
+	validateOIDCScopes(config, val)
 
-	if len(config.Clients) == 0 {
-		val.Push(fmt.Errorf(errFmtOIDCNoClientsConfigured))
-	} else {
+	if config.DynamicClientRegistration.Enabled && config.DynamicClientRegistration.InitialAccessToken == "" {
+		val.Push(fmt.Errorf(errFmtOIDCDynamicClientRegistrationNoInitialAccessToken))
+	}
+
+	switch {
+	case len(config.Clients) != 0:
 		validateOIDCClients(config, val)
+	case !config.DynamicClientRegistration.Enabled:
+		val.Push(fmt.Errorf(errFmtOIDCNoClientsConfigured))
+	}
+}
+
+func validateOIDCIssuerPrivateKeys(config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	var (
+		kids  []string
+		rs256 bool
+	)
+
+	for i := range config.IssuerPrivateKeys {
+		key := &config.IssuerPrivateKeys[i]
+
+		if key.Key == nil {
+			val.Push(fmt.Errorf(errFmtOIDCPrivateKeysNoKey, i+1))
+
+			continue
+		}
+
+		var algs []string
+
+		switch key.Use {
+		case "":
+			key.Use = oidc.KeyUseSignature
+
+			fallthrough
+		case oidc.KeyUseSignature:
+			if algs = oidcIssuerPrivateKeyAlgorithms(key.Key); len(algs) == 0 {
+				val.Push(fmt.Errorf(errFmtOIDCPrivateKeysKeyType, i+1))
+
+				continue
+			}
+		case oidc.KeyUseEncryption:
+			if algs = oidc.KeyManagementAlgorithms(key.Key); len(algs) == 0 {
+				val.Push(fmt.Errorf(errFmtOIDCPrivateKeysEncryptionKeyType, i+1))
+
+				continue
+			}
+
+			if oidcIssuerPrivateKeyIsShared(config, key) {
+				val.Push(fmt.Errorf(errFmtOIDCPrivateKeysEncryptionShared, i+1))
+			}
+		default:
+			val.Push(fmt.Errorf(errFmtOIDCPrivateKeysInvalidUse, i+1, strings.Join([]string{oidc.KeyUseSignature, oidc.KeyUseEncryption}, "', '"), key.Use))
+
+			continue
+		}
+
+		switch {
+		case key.Algorithm == "":
+			key.Algorithm = algs[0]
+		case !utils.IsStringInSlice(key.Algorithm, algs):
+			val.Push(fmt.Errorf(errFmtOIDCPrivateKeysInvalidAlgorithm, i+1, strings.Join(algs, "', '"), key.Algorithm))
+		}
+
+		if k, ok := key.Key.(*rsa.PrivateKey); ok && k.Size()*8 < 2048 {
+			val.Push(fmt.Errorf(errFmtOIDCPrivateKeysBitSize, i+1, 2048, k.Size()*8))
+		}
+
+		if key.KeyID != "" {
+			if utils.IsStringInSlice(key.KeyID, kids) {
+				val.Push(fmt.Errorf(errFmtOIDCPrivateKeysDuplicateKeyID, i+1, key.KeyID))
+			}
+
+			kids = append(kids, key.KeyID)
+		}
+
+		if key.CertificateChain.HasCertificates() {
+			if !key.CertificateChain.EqualKey(key.Key) {
+				val.Push(fmt.Errorf(errFmtOIDCPrivateKeysCertificateMatch, i+1))
+			}
+
+			if err := key.CertificateChain.Validate(); err != nil {
+				val.Push(fmt.Errorf(errFmtOIDCPrivateKeysCertificateChain, i+1, err))
+			}
+		}
+
+		if !key.NotBefore.IsZero() && !key.NotAfter.IsZero() && !key.NotAfter.After(key.NotBefore) {
+			val.Push(fmt.Errorf(errFmtOIDCPrivateKeysNotAfter, i+1))
+		}
+
+		if key.Use == oidc.KeyUseSignature && key.Algorithm == oidc.SigningAlgorithmRSAWithSHA256 {
+			rs256 = true
+		}
+	}
+
+	if !rs256 {
+		val.Push(fmt.Errorf(errFmtOIDCPrivateKeysNoRS256, oidc.SigningAlgorithmRSAWithSHA256))
+	}
+}
+
+// oidcIssuerPrivateKeyAlgorithms returns the signing algorithms which can be used with a private key with the default
+// algorithm being first, or nil if the key type is not supported.
+func oidcIssuerPrivateKeyAlgorithms(key schema.CryptographicPrivateKey) (algs []string) {
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		return validOIDCIssuerRSAAlgorithms
+	}
+
+	if alg := oidc.DefaultSigningAlgorithm(key); alg != "" {
+		return []string{alg}
+	}
+
+	return nil
+}
+
+// oidcIssuerPrivateKeyIsShared returns true if the private key of an encryption key is also used by a signing key.
+func oidcIssuerPrivateKeyIsShared(config *schema.OpenIDConnectConfiguration, key *schema.OpenIDConnectIssuerPrivateKey) bool {
+	public, ok := key.Key.Public().(interface{ Equal(x crypto.PublicKey) bool })
+	if !ok {
+		return false
+	}
+
+	for _, other := range config.IssuerPrivateKeys {
+		if other.Key == nil || other.Use == oidc.KeyUseEncryption {
+			continue
+		}
+
+		if public.Equal(other.Key.Public()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// oidcSigningAlgorithms returns the unique signing algorithms of the configured issuer keys.
+func oidcSigningAlgorithms(config *schema.OpenIDConnectConfiguration) (algs []string) {
+	if len(config.IssuerPrivateKeys) == 0 {
+		return []string{oidc.SigningAlgorithmRSAWithSHA256}
 	}
+
+	for _, key := range config.IssuerPrivateKeys {
+		if key.Use == oidc.KeyUseEncryption {
+			continue
+		}
+
+		if key.Algorithm != "" && !utils.IsStringInSlice(key.Algorithm, algs) {
+			algs = append(algs, key.Algorithm)
+		}
+	}
+
+	return algs
 }
 
 func setOIDCDefaults(config *schema.OpenIDConnectConfiguration) {
@@ -76,6 +244,14 @@ func setOIDCDefaults(config *schema.OpenIDConnectConfiguration) {
 		config.RefreshTokenLifespan = schema.DefaultOpenIDConnectConfiguration.RefreshTokenLifespan
 	}
 
+	if config.DeviceCodeLifespan == time.Duration(0) {
+		config.DeviceCodeLifespan = schema.DefaultOpenIDConnectConfiguration.DeviceCodeLifespan
+	}
+
+	if config.DeviceCodePollingInterval == time.Duration(0) {
+		config.DeviceCodePollingInterval = schema.DefaultOpenIDConnectConfiguration.DeviceCodePollingInterval
+	}
+
 	if config.EnforcePKCE == "" {
 		config.EnforcePKCE = schema.DefaultOpenIDConnectConfiguration.EnforcePKCE
 	}
@@ -140,6 +316,97 @@ func validateOIDCOptionsCORSEndpoints(config *schema.OpenIDConnectConfiguration,
 	}
 }
 
+func validateOIDCScopes(config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	for _, name := range oidcCustomScopeNames(config) {
+		switch {
+		case name == "" || strings.ContainsAny(name, " \t\r\n"):
+			val.Push(fmt.Errorf(errFmtOIDCScopeInvalidName, name))
+		case utils.IsStringInSlice(name, validOIDCScopes):
+			val.Push(fmt.Errorf(errFmtOIDCScopeStandardName, name, strings.Join(validOIDCScopes, "', '")))
+		}
+
+		for _, claim := range config.Scopes[name].Claims {
+			if !oidcIsClaimDefined(config, claim) {
+				val.Push(fmt.Errorf(errFmtOIDCScopeUnknownClaim, name, claim))
+			}
+		}
+	}
+}
+
+func validateOIDCClaims(prefix string, claims map[string]schema.OpenIDConnectClaimConfiguration, val *schema.StructValidator) {
+	names := make([]string, 0, len(claims))
+
+	for name := range claims {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		switch {
+		case name == "" || strings.ContainsAny(name, " \t\r\n"):
+			val.Push(fmt.Errorf(errFmtOIDCClaimInvalidName, prefix, name))
+		case utils.IsStringInSlice(name, reservedOIDCClaims):
+			val.Push(fmt.Errorf(errFmtOIDCClaimReservedName, prefix, name, strings.Join(reservedOIDCClaims, "', '")))
+		}
+
+		validateOIDCClaim(prefix, name, claims[name], val)
+	}
+}
+
+func validateOIDCClaim(prefix, name string, claim schema.OpenIDConnectClaimConfiguration, val *schema.StructValidator) {
+	sources := 0
+
+	for _, option := range []string{claim.Attribute, claim.Value, claim.Template} {
+		if option != "" {
+			sources++
+		}
+	}
+
+	switch {
+	case sources == 0:
+		val.Push(fmt.Errorf(errFmtOIDCClaimNoSource, prefix, name))
+	case sources > 1:
+		val.Push(fmt.Errorf(errFmtOIDCClaimMultipleSources, prefix, name))
+	}
+
+	if claim.Attribute != "" && !utils.IsStringInSlice(claim.Attribute, validOIDCClaimAttributes) {
+		val.Push(fmt.Errorf(errFmtOIDCClaimInvalidAttribute, prefix, name, strings.Join(validOIDCClaimAttributes, "', '"), claim.Attribute))
+	}
+
+	if claim.Filter != "" && !utils.IsStringInSlice(claim.Attribute, validOIDCClaimFilterAttributes) {
+		val.Push(fmt.Errorf(errFmtOIDCClaimInvalidFilter, prefix, name, strings.Join(validOIDCClaimFilterAttributes, "', '")))
+	}
+
+	if _, err := oidc.NewClaimDefinition(claim); err != nil {
+		val.Push(fmt.Errorf(errFmtOIDCClaimInvalidDefinition, prefix, name, err))
+	}
+}
+
+func oidcIsClaimDefined(config *schema.OpenIDConnectConfiguration, name string) bool {
+	if _, ok := config.Claims[name]; ok {
+		return true
+	}
+
+	for _, client := range config.Clients {
+		if _, ok := client.Claims[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func oidcCustomScopeNames(config *schema.OpenIDConnectConfiguration) (names []string) {
+	for name := range config.Scopes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
 func validateOIDCClients(config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
 	invalidID, duplicateIDs := false, false
 
@@ -149,40 +416,13 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, val *schema.
 		if client.ID == "" {
 			invalidID = true
 		} else {
-			if client.Description == "" {
-				config.Clients[c].Description = client.ID
-			}
-
 			if utils.IsStringInSliceFold(client.ID, ids) {
 				duplicateIDs = true
 			}
 			ids = append(ids, client.ID)
 		}
 
-		if client.Public {
-			if client.Secret != nil {
-				val.Push(fmt.Errorf(errFmtOIDCClientPublicInvalidSecret, client.ID))
-			}
-		} else {
-			if client.Secret == nil {
-				val.Push(fmt.Errorf(errFmtOIDCClientInvalidSecret, client.ID))
-			}
-		}
-
-		if client.Policy == "" {
-			config.Clients[c].Policy = schema.DefaultOpenIDConnectClientConfiguration.Policy
-		} else if client.Policy != policyOneFactor && client.Policy != policyTwoFactor {
-			val.Push(fmt.Errorf(errFmtOIDCClientInvalidPolicy, client.ID, client.Policy))
-		}
-
-		validateOIDCClientConsentMode(c, config, val)
-		validateOIDCClientSectorIdentifier(client, val)
-		validateOIDCClientScopes(c, config, val)
-		validateOIDCClientGrantTypes(c, config, val)
-		validateOIDCClientResponseTypes(c, config, val)
-		validateOIDCClientResponseModes(c, config, val)
-		validateOIDDClientUserinfoAlgorithm(c, config, val)
-		validateOIDCClientRedirectURIs(client, val)
+		validateOIDCClient(c, config, val)
 	}
 
 	if invalidID {
@@ -194,6 +434,62 @@ func validateOIDCClients(config *schema.OpenIDConnectConfiguration, val *schema.
 	}
 }
 
+// ValidateIdentityProvidersOpenIDConnectClient validates and updates an individual OpenID Connect client which is not
+// part of the configuration such as clients managed via the storage provider. The provided
+// schema.OpenIDConnectConfiguration must have already been validated.
+func ValidateIdentityProvidersOpenIDConnectClient(client *schema.OpenIDConnectClientConfiguration, config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	c := *config
+
+	c.Clients = []schema.OpenIDConnectClientConfiguration{*client}
+
+	if client.ID == "" {
+		val.Push(fmt.Errorf(errFmtOIDCClientsWithEmptyID))
+	}
+
+	validateOIDCClient(0, &c, val)
+
+	*client = c.Clients[0]
+}
+
+func validateOIDCClient(c int, config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	client := config.Clients[c]
+
+	if client.ID != "" && client.Description == "" {
+		config.Clients[c].Description = client.ID
+	}
+
+	if client.Public {
+		if client.Secret != nil {
+			val.Push(fmt.Errorf(errFmtOIDCClientPublicInvalidSecret, client.ID))
+		}
+	} else {
+		if client.Secret == nil && client.TokenEndpointAuthMethod != oidc.ClientAuthMethodPrivateKeyJWT {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidSecret, client.ID))
+		}
+	}
+
+	if client.Policy == "" {
+		config.Clients[c].Policy = schema.DefaultOpenIDConnectClientConfiguration.Policy
+	} else if client.Policy != policyOneFactor && client.Policy != policyTwoFactor {
+		val.Push(fmt.Errorf(errFmtOIDCClientInvalidPolicy, client.ID, client.Policy))
+	}
+
+	validateOIDCClientConsentMode(c, config, val)
+	validateOIDCClientSectorIdentifier(client, val)
+	validateOIDCClientScopes(c, config, val)
+	validateOIDCClientGrantTypes(c, config, val)
+	validateOIDCClientTokenExchange(c, config, val)
+	validateOIDCClientResponseTypes(c, config, val)
+	validateOIDCClientResponseModes(c, config, val)
+	validateOIDCClientSigningAlgorithms(c, config, val)
+	validateOIDCClientTokenEndpointAuth(c, config, val)
+	validateOIDCClientRequestObject(c, config, val)
+	validateOIDCClientTokenLifespans(client, val)
+	validateOIDCClientRedirectURIs(client, val)
+	validateOIDCClientLogoutURIs(client, val)
+	validateOIDCClaims(fmt.Sprintf("client '%s': ", client.ID), client.Claims, val)
+}
+
 func validateOIDCClientSectorIdentifier(client schema.OpenIDConnectClientConfiguration, val *schema.StructValidator) {
 	if client.SectorIdentifier.String() != "" {
 		if utils.IsURLHostComponent(client.SectorIdentifier) || utils.IsURLHostComponentWithPort(client.SectorIdentifier) {
@@ -250,20 +546,29 @@ func validateOIDCClientConsentMode(c int, config *schema.OpenIDConnectConfigurat
 }
 
 func validateOIDCClientScopes(c int, config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	// Clients which are only permitted to use the client credentials grant act on their own behalf, so the user scopes
+	// are neither defaulted nor implied.
+	machine := len(config.Clients[c].GrantTypes) == 1 && config.Clients[c].GrantTypes[0] == oidc.GrantTypeClientCredentials
+
 	if len(config.Clients[c].Scopes) == 0 {
-		config.Clients[c].Scopes = schema.DefaultOpenIDConnectClientConfiguration.Scopes
+		if !machine {
+			config.Clients[c].Scopes = schema.DefaultOpenIDConnectClientConfiguration.Scopes
+		}
+
 		return
 	}
 
-	if !utils.IsStringInSlice(oidc.ScopeOpenID, config.Clients[c].Scopes) {
+	if !machine && !utils.IsStringInSlice(oidc.ScopeOpenID, config.Clients[c].Scopes) {
 		config.Clients[c].Scopes = append(config.Clients[c].Scopes, oidc.ScopeOpenID)
 	}
 
+	valid := append(append([]string{}, validOIDCScopes...), oidcCustomScopeNames(config)...)
+
 	for _, scope := range config.Clients[c].Scopes {
-		if !utils.IsStringInSlice(scope, validOIDCScopes) {
+		if !utils.IsStringInSlice(scope, valid) {
 			val.Push(fmt.Errorf(
 				errFmtOIDCClientInvalidEntry,
-				config.Clients[c].ID, "scopes", strings.Join(validOIDCScopes, "', '"), scope))
+				config.Clients[c].ID, "scopes", strings.Join(valid, "', '"), scope))
 		}
 	}
 }
@@ -280,6 +585,49 @@ func validateOIDCClientGrantTypes(c int, config *schema.OpenIDConnectConfigurati
 				errFmtOIDCClientInvalidEntry,
 				config.Clients[c].ID, "grant_types", strings.Join(validOIDCGrantTypes, "', '"), grantType))
 		}
+
+		if (grantType == oidc.GrantTypeClientCredentials || grantType == oidc.GrantTypeTokenExchange) && config.Clients[c].Public {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidGrantTypePublic, config.Clients[c].ID, grantType))
+		}
+	}
+}
+
+func validateOIDCClientTokenExchange(c int, config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	if !utils.IsStringInSlice(oidc.GrantTypeTokenExchange, config.Clients[c].GrantTypes) {
+		return
+	}
+
+	if len(config.Clients[c].TokenExchange.Audience) == 0 {
+		val.Push(fmt.Errorf(errFmtOIDCClientTokenExchangeNoAudience, config.Clients[c].ID, oidc.GrantTypeTokenExchange))
+	}
+
+	valid := append(append([]string{}, validOIDCScopes...), oidcCustomScopeNames(config)...)
+
+	for _, scope := range config.Clients[c].TokenExchange.Scopes {
+		if !utils.IsStringInSlice(scope, valid) {
+			val.Push(fmt.Errorf(
+				errFmtOIDCClientInvalidEntry,
+				config.Clients[c].ID, "token_exchange.scopes", strings.Join(valid, "', '"), scope))
+		}
+	}
+}
+
+func validateOIDCClientTokenLifespans(client schema.OpenIDConnectClientConfiguration, val *schema.StructValidator) {
+	lifespans := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"access_token", client.TokenLifespans.AccessToken},
+		{"id_token", client.TokenLifespans.IDToken},
+		{"refresh_token", client.TokenLifespans.RefreshToken},
+		{"refresh_token_absolute", client.TokenLifespans.RefreshTokenAbsolute},
+		{"refresh_token_reuse_grace_period", client.TokenLifespans.RefreshTokenReuseGracePeriod},
+	}
+
+	for _, lifespan := range lifespans {
+		if lifespan.value < 0 {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenLifespan, client.ID, lifespan.name, lifespan.value))
+		}
 	}
 }
 
@@ -305,12 +653,205 @@ func validateOIDCClientResponseModes(c int, config *schema.OpenIDConnectConfigur
 	}
 }
 
-func validateOIDDClientUserinfoAlgorithm(c int, config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+func validateOIDCClientSigningAlgorithms(c int, config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	algs := oidcSigningAlgorithms(config)
+
+	if config.Clients[c].IDTokenSigningAlgorithm == "" {
+		config.Clients[c].IDTokenSigningAlgorithm = schema.DefaultOpenIDConnectClientConfiguration.IDTokenSigningAlgorithm
+	} else if !utils.IsStringInSlice(config.Clients[c].IDTokenSigningAlgorithm, algs) {
+		val.Push(fmt.Errorf(errFmtOIDCClientInvalidIDTokenAlgorithm,
+			config.Clients[c].ID, strings.Join(algs, ", "), config.Clients[c].IDTokenSigningAlgorithm))
+	}
+
+	algs = append([]string{oidc.SigningAlgorithmNone}, algs...)
+
 	if config.Clients[c].UserinfoSigningAlgorithm == "" {
 		config.Clients[c].UserinfoSigningAlgorithm = schema.DefaultOpenIDConnectClientConfiguration.UserinfoSigningAlgorithm
-	} else if !utils.IsStringInSlice(config.Clients[c].UserinfoSigningAlgorithm, validOIDCUserinfoAlgorithms) {
+	} else if !utils.IsStringInSlice(config.Clients[c].UserinfoSigningAlgorithm, algs) {
 		val.Push(fmt.Errorf(errFmtOIDCClientInvalidUserinfoAlgorithm,
-			config.Clients[c].ID, strings.Join(validOIDCUserinfoAlgorithms, ", "), config.Clients[c].UserinfoSigningAlgorithm))
+			config.Clients[c].ID, strings.Join(algs, ", "), config.Clients[c].UserinfoSigningAlgorithm))
+	}
+
+	if config.Clients[c].AccessTokenSigningAlgorithm == "" {
+		config.Clients[c].AccessTokenSigningAlgorithm = schema.DefaultOpenIDConnectClientConfiguration.AccessTokenSigningAlgorithm
+	} else if !utils.IsStringInSlice(config.Clients[c].AccessTokenSigningAlgorithm, algs) {
+		val.Push(fmt.Errorf(errFmtOIDCClientInvalidAccessTokenAlgorithm,
+			config.Clients[c].ID, strings.Join(algs, ", "), config.Clients[c].AccessTokenSigningAlgorithm))
+	}
+}
+
+func validateOIDCClientTokenEndpointAuth(c int, config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	client := &config.Clients[c]
+
+	validateOIDCClientJSONWebKeys(client, val)
+
+	switch client.TokenEndpointAuthMethod {
+	case "":
+		if client.Public {
+			client.TokenEndpointAuthMethod = oidc.ClientAuthMethodNone
+		}
+	case oidc.ClientAuthMethodNone:
+		if !client.Public {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethodPublic, client.ID, client.TokenEndpointAuthMethod, "false"))
+		}
+	case oidc.ClientAuthMethodClientSecretBasic, oidc.ClientAuthMethodClientSecretPost:
+		if client.Public {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethodPublic, client.ID, client.TokenEndpointAuthMethod, "true"))
+		}
+	case oidc.ClientAuthMethodClientSecretJWT:
+		if client.Public {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethodPublic, client.ID, client.TokenEndpointAuthMethod, "true"))
+		}
+
+		if _, ok := client.Secret.PlainText(); client.Secret != nil && !ok {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethodSecretJWT, client.ID, client.TokenEndpointAuthMethod))
+		}
+
+		validateOIDCClientTokenEndpointAuthSigningAlgorithm(client, validOIDCClientSecretJWTAlgorithms, val)
+	case oidc.ClientAuthMethodPrivateKeyJWT:
+		if client.Public {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethodPublic, client.ID, client.TokenEndpointAuthMethod, "true"))
+		}
+
+		if client.JSONWebKeys == "" {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethodJWKS, client.ID, client.TokenEndpointAuthMethod))
+		}
+
+		validateOIDCClientTokenEndpointAuthSigningAlgorithm(client, validOIDCClientPrivateKeyJWTAlgorithms, val)
+	default:
+		val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthMethod, client.ID, strings.Join(validOIDCClientTokenEndpointAuthMethods, "', '"), client.TokenEndpointAuthMethod))
+	}
+
+	switch client.TokenEndpointAuthMethod {
+	case oidc.ClientAuthMethodClientSecretJWT, oidc.ClientAuthMethodPrivateKeyJWT:
+		break
+	default:
+		if client.TokenEndpointAuthSigningAlgorithm != "" {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthSigningAlgorithmMethod, client.ID, client.TokenEndpointAuthMethod))
+		}
+	}
+}
+
+func validateOIDCClientTokenEndpointAuthSigningAlgorithm(client *schema.OpenIDConnectClientConfiguration, algs []string, val *schema.StructValidator) {
+	if client.TokenEndpointAuthSigningAlgorithm == "" {
+		client.TokenEndpointAuthSigningAlgorithm = algs[0]
+	} else if !utils.IsStringInSlice(client.TokenEndpointAuthSigningAlgorithm, algs) {
+		val.Push(fmt.Errorf(errFmtOIDCClientInvalidTokenEndpointAuthSigningAlgorithm, client.ID, strings.Join(algs, "', '"), client.TokenEndpointAuthMethod, client.TokenEndpointAuthSigningAlgorithm))
+
+		return
+	}
+
+	if client.TokenEndpointAuthMethod != oidc.ClientAuthMethodPrivateKeyJWT || client.JSONWebKeys == "" {
+		return
+	}
+
+	jwks := &jose.JSONWebKeySet{}
+
+	if err := json.Unmarshal([]byte(client.JSONWebKeys), jwks); err != nil {
+		return
+	}
+
+	for _, jwk := range jwks.Keys {
+		if (jwk.Use == "" || jwk.Use == "sig") && oidc.IsSigningAlgorithmCompatibleKey(client.TokenEndpointAuthSigningAlgorithm, jwk.Key) {
+			return
+		}
+	}
+
+	val.Push(fmt.Errorf(errFmtOIDCClientInvalidJWKSNoCompatibleKey, client.ID, client.TokenEndpointAuthSigningAlgorithm))
+}
+
+func validateOIDCClientRequestObject(c int, config *schema.OpenIDConnectConfiguration, val *schema.StructValidator) {
+	client := &config.Clients[c]
+
+	for _, uri := range client.RequestURIs {
+		validateOIDCClientLogoutURI(client.ID, "request_uris", uri, val)
+	}
+
+	switch {
+	case client.RequestObjectSigningAlgorithm == "":
+		if !client.RequireSignedRequestObject {
+			return
+		}
+
+		client.RequestObjectSigningAlgorithm = oidc.SigningAlgorithmRSAWithSHA256
+	case client.RequestObjectSigningAlgorithm == oidc.SigningAlgorithmNone:
+		if client.RequireSignedRequestObject {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidRequestObjectSigningAlgorithmNone, client.ID))
+		}
+
+		return
+	case !utils.IsStringInSlice(client.RequestObjectSigningAlgorithm, validOIDCClientPrivateKeyJWTAlgorithms):
+		val.Push(fmt.Errorf(errFmtOIDCClientInvalidRequestObjectSigningAlgorithm, client.ID, strings.Join(append([]string{oidc.SigningAlgorithmNone}, validOIDCClientPrivateKeyJWTAlgorithms...), "', '"), client.RequestObjectSigningAlgorithm))
+
+		return
+	}
+
+	if client.JSONWebKeys == "" {
+		val.Push(fmt.Errorf(errFmtOIDCClientInvalidRequestObjectJWKS, client.ID, client.RequestObjectSigningAlgorithm))
+	}
+}
+
+func validateOIDCClientJSONWebKeys(client *schema.OpenIDConnectClientConfiguration, val *schema.StructValidator) {
+	if client.JSONWebKeysPath != "" {
+		if client.JSONWebKeys != "" {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidJWKSBoth, client.ID))
+
+			return
+		}
+
+		data, err := os.ReadFile(client.JSONWebKeysPath)
+		if err != nil {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidJWKSPath, client.ID, err))
+
+			return
+		}
+
+		client.JSONWebKeys = string(data)
+	}
+
+	if client.JSONWebKeys == "" {
+		return
+	}
+
+	jwks := &jose.JSONWebKeySet{}
+
+	if err := json.Unmarshal([]byte(client.JSONWebKeys), jwks); err != nil {
+		val.Push(fmt.Errorf(errFmtOIDCClientInvalidJWKS, client.ID, err))
+
+		return
+	}
+
+	for i, jwk := range jwks.Keys {
+		if !jwk.IsPublic() {
+			val.Push(fmt.Errorf(errFmtOIDCClientInvalidJWKSPrivateKey, client.ID, i+1))
+		}
+	}
+}
+
+func validateOIDCClientLogoutURIs(client schema.OpenIDConnectClientConfiguration, val *schema.StructValidator) {
+	for _, uri := range client.PostLogoutRedirectURIs {
+		validateOIDCClientLogoutURI(client.ID, "post_logout_redirect_uris", uri, val)
+	}
+
+	if client.FrontChannelLogoutURI != "" {
+		validateOIDCClientLogoutURI(client.ID, "frontchannel_logout_uri", client.FrontChannelLogoutURI, val)
+	}
+
+	if client.BackChannelLogoutURI != "" {
+		validateOIDCClientLogoutURI(client.ID, "backchannel_logout_uri", client.BackChannelLogoutURI, val)
+	}
+}
+
+func validateOIDCClientLogoutURI(id, option, uri string, val *schema.StructValidator) {
+	parsedURL, err := url.Parse(uri)
+	if err != nil {
+		val.Push(fmt.Errorf(errFmtOIDCClientLogoutURICantBeParsed, id, option, uri, err))
+
+		return
+	}
+
+	if !parsedURL.IsAbs() || (parsedURL.Scheme != schemeHTTP && parsedURL.Scheme != schemeHTTPS) || parsedURL.Fragment != "" {
+		val.Push(fmt.Errorf(errFmtOIDCClientLogoutURIInvalid, id, option, uri))
 	}
 }
 
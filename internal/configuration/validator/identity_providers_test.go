@@ -1,18 +1,26 @@
 package validator
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/oidc"
@@ -80,7 +88,7 @@ func TestShouldRaiseErrorWhenCORSEndpointsNotValid(t *testing.T) {
 
 	require.Len(t, validator.Errors(), 1)
 
-	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: cors: option 'endpoints' contains an invalid value 'invalid_endpoint': must be one of 'authorization', 'token', 'introspection', 'revocation', 'userinfo'")
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: cors: option 'endpoints' contains an invalid value 'invalid_endpoint': must be one of 'authorization', 'token', 'introspection', 'revocation', 'userinfo', 'device-authorization', 'pushed-authorization-request', 'registration'")
 }
 
 func TestShouldRaiseErrorWhenOIDCPKCEEnforceValueInvalid(t *testing.T) {
@@ -101,6 +109,39 @@ func TestShouldRaiseErrorWhenOIDCPKCEEnforceValueInvalid(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[1], errFmtOIDCNoClientsConfigured)
 }
 
+func TestShouldRaiseErrorWhenOIDCRefreshTokenDurationsNegative(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:                   "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey:             MustParseRSAPrivateKey(testKey1),
+			RefreshTokenAbsoluteLifespan: -time.Hour,
+			RefreshTokenReuseGracePeriod: -time.Second,
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:           "good_id",
+					Secret:       MustDecodeSecret("$plaintext$good_secret"),
+					Policy:       "two_factor",
+					RedirectURIs: []string{"https://google.com/callback"},
+					TokenLifespans: schema.OpenIDConnectClientTokenLifespans{
+						RefreshTokenAbsolute:         -time.Minute,
+						RefreshTokenReuseGracePeriod: -time.Second,
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 4)
+
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: option 'refresh_token_absolute_lifespan' must not be a negative duration but it is configured as '-1h0m0s'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: option 'refresh_token_reuse_grace_period' must not be a negative duration but it is configured as '-1s'")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: client 'good_id': token_lifespans: option 'refresh_token_absolute' must not be a negative duration but it is configured as '-1m0s'")
+	assert.EqualError(t, validator.Errors()[3], "identity_providers: oidc: client 'good_id': token_lifespans: option 'refresh_token_reuse_grace_period' must not be a negative duration but it is configured as '-1s'")
+}
+
 func TestShouldRaiseErrorWhenOIDCCORSOriginsHasInvalidValues(t *testing.T) {
 	validator := schema.NewStructValidator()
 
@@ -153,6 +194,74 @@ func TestShouldRaiseErrorWhenOIDCServerNoClients(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], errFmtOIDCNoClientsConfigured)
 }
 
+func TestShouldNotRaiseErrorWhenOIDCServerNoClientsWithDynamicClientRegistration(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: MustParseRSAPrivateKey(testKey1),
+			DynamicClientRegistration: schema.OpenIDConnectDynamicClientRegistrationConfiguration{
+				Enabled:            true,
+				InitialAccessToken: "kq3QTZrsSMLKWRrtfbbCE7j5ZEX7VCcG",
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldRaiseErrorWhenOIDCServerDynamicClientRegistrationNoInitialAccessToken(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: MustParseRSAPrivateKey(testKey1),
+			DynamicClientRegistration: schema.OpenIDConnectDynamicClientRegistrationConfiguration{
+				Enabled: true,
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], errFmtOIDCDynamicClientRegistrationNoInitialAccessToken)
+}
+
+func TestShouldValidateIdentityProvidersOpenIDConnectClient(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.OpenIDConnectConfiguration{}
+
+	client := &schema.OpenIDConnectClientConfiguration{
+		ID:           "myclient",
+		Secret:       MustDecodeSecret("$plaintext$example"),
+		RedirectURIs: []string{"https://google.com"},
+	}
+
+	ValidateIdentityProvidersOpenIDConnectClient(client, config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "myclient", client.Description)
+	assert.Equal(t, policyTwoFactor, client.Policy)
+	assert.Len(t, config.Clients, 0)
+
+	client = &schema.OpenIDConnectClientConfiguration{
+		Secret:       MustDecodeSecret("$plaintext$example"),
+		RedirectURIs: []string{"https://google.com"},
+		Policy:       "unknown",
+	}
+
+	ValidateIdentityProvidersOpenIDConnectClient(client, config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], errFmtOIDCClientsWithEmptyID)
+	assert.EqualError(t, validator.Errors()[1], fmt.Sprintf(errFmtOIDCClientInvalidPolicy, "", "unknown"))
+}
+
 func TestShouldRaiseErrorWhenOIDCServerClientBadValues(t *testing.T) {
 	mustParseURL := func(u string) url.URL {
 		out, err := url.Parse(u)
@@ -407,7 +516,191 @@ func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadGrantTypes(t *testing.T)
 	ValidateIdentityProviders(config, validator)
 
 	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'grant_types' must only have the values 'implicit', 'refresh_token', 'authorization_code', 'password', 'client_credentials' but one option is configured as 'bad_grant_type'")
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'grant_types' must only have the values 'implicit', 'refresh_token', 'authorization_code', 'password', 'client_credentials', 'urn:ietf:params:oauth:grant-type:device_code', 'urn:ietf:params:oauth:grant-type:token-exchange' but one option is configured as 'bad_grant_type'")
+}
+
+func TestShouldValidateOIDCCustomScopes(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: MustParseRSAPrivateKey(testKey1),
+			Scopes: map[string]schema.OpenIDConnectScopeConfiguration{
+				"api:read":  {Description: "Read the API"},
+				"bad scope": {},
+				"profile":   {},
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "good_id",
+					Secret: MustDecodeSecret("$plaintext$good_secret"),
+					Scopes: []string{"openid", "api:read"},
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: scopes: scope 'bad scope': the name must not be empty or contain whitespace")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: scopes: scope 'profile': the name must not be the same as one of the standard scopes 'openid', 'email', 'profile', 'groups', 'offline_access'")
+}
+
+func TestShouldValidateOIDCCustomClaims(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: MustParseRSAPrivateKey(testKey1),
+			Claims: map[string]schema.OpenIDConnectClaimConfiguration{
+				"roles":      {Attribute: "groups", Filter: "^app-"},
+				"department": {Value: "engineering"},
+				"mailbox":    {Template: "{{ .Username }}@mail.example.com"},
+				"bad claim":  {Value: "abc"},
+				"email":      {Attribute: "email"},
+				"empty":      {},
+				"multiple":   {Attribute: "username", Value: "abc"},
+				"attribute":  {Attribute: "phone"},
+				"filter":     {Attribute: "username", Filter: "^a"},
+				"regex":      {Attribute: "groups", Filter: "^(app"},
+				"template":   {Template: "{{ .Username"},
+			},
+			Scopes: map[string]schema.OpenIDConnectScopeConfiguration{
+				"roles": {Claims: []string{"roles", "department", "mailbox", "location", "unknown"}},
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "good_id",
+					Secret: MustDecodeSecret("$plaintext$good_secret"),
+					Scopes: []string{"openid", "roles"},
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+					Claims: map[string]schema.OpenIDConnectClaimConfiguration{
+						"department": {Value: "sales"},
+						"location":   {Value: "remote"},
+						"sub":        {Value: "abc"},
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	errs := validator.Errors()
+
+	require.Len(t, errs, 10)
+	assert.EqualError(t, errs[0], "identity_providers: oidc: claims: claim 'attribute': option 'attribute' must be one of 'username', 'display_name', 'email', 'emails', 'groups' but it is configured as 'phone'")
+	assert.EqualError(t, errs[1], "identity_providers: oidc: claims: claim 'bad claim': the name must not be empty or contain whitespace")
+	assert.EqualError(t, errs[2], "identity_providers: oidc: claims: claim 'email': the name must not be the same as one of the reserved claims 'jti', 'sid', 'at_hash', 'c_hash', 'iat', 'nbf', 'rat', 'exp', 'auth_time', 'iss', 'sub', 'nonce', 'aud', 'groups', 'name', 'preferred_username', 'email', 'email_verified', 'alt_emails', 'azp', 'acr', 'amr', 'client_id', 'scope', 'act'")
+	assert.EqualError(t, errs[3], "identity_providers: oidc: claims: claim 'empty': one of the options 'attribute', 'value', or 'template' must be configured")
+	assert.EqualError(t, errs[4], "identity_providers: oidc: claims: claim 'filter': option 'filter' may only be configured when option 'attribute' is one of 'emails', 'groups'")
+	assert.EqualError(t, errs[5], "identity_providers: oidc: claims: claim 'multiple': only one of the options 'attribute', 'value', or 'template' may be configured")
+	assert.EqualError(t, errs[6], "identity_providers: oidc: claims: claim 'regex': option 'filter' could not be compiled: error parsing regexp: missing closing ): `^(app`")
+	assert.EqualError(t, errs[7], "identity_providers: oidc: claims: claim 'template': option 'template' could not be parsed: template: claim:1: unclosed action")
+	assert.EqualError(t, errs[8], "identity_providers: oidc: scopes: scope 'roles': option 'claims' contains the claim 'unknown' but it is not defined by the 'claims' option or the 'claims' option of any client")
+	assert.EqualError(t, errs[9], "identity_providers: oidc: client 'good_id': claims: claim 'sub': the name must not be the same as one of the reserved claims 'jti', 'sid', 'at_hash', 'c_hash', 'iat', 'nbf', 'rat', 'exp', 'auth_time', 'iss', 'sub', 'nonce', 'aud', 'groups', 'name', 'preferred_username', 'email', 'email_verified', 'alt_emails', 'azp', 'acr', 'amr', 'client_id', 'scope', 'act'")
+}
+
+func TestShouldValidateOIDCClientCredentialsClient(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: MustParseRSAPrivateKey(testKey1),
+			Scopes: map[string]schema.OpenIDConnectScopeConfiguration{
+				"api:read": {},
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:         "machine",
+					Secret:     MustDecodeSecret("$plaintext$good_secret"),
+					Scopes:     []string{"api:read"},
+					Audience:   []string{"https://api.example.com"},
+					GrantTypes: []string{"client_credentials"},
+					TokenLifespans: schema.OpenIDConnectClientTokenLifespans{
+						AccessToken: time.Minute,
+					},
+				},
+				{
+					ID:         "machine-default",
+					Secret:     MustDecodeSecret("$plaintext$good_secret"),
+					GrantTypes: []string{"client_credentials"},
+				},
+				{
+					ID:                      "public",
+					Public:                  true,
+					TokenEndpointAuthMethod: "none",
+					GrantTypes:              []string{"client_credentials"},
+					TokenLifespans: schema.OpenIDConnectClientTokenLifespans{
+						RefreshToken: -time.Minute,
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	assert.Equal(t, []string{"api:read"}, config.OIDC.Clients[0].Scopes)
+	assert.Len(t, config.OIDC.Clients[1].Scopes, 0)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'public': option 'grant_types' must not contain the 'client_credentials' grant type when option 'public' is true")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'public': token_lifespans: option 'refresh_token' must not be a negative duration but it is configured as '-1m0s'")
+}
+
+func TestShouldValidateOIDCTokenExchangeClient(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: MustParseRSAPrivateKey(testKey1),
+			Scopes: map[string]schema.OpenIDConnectScopeConfiguration{
+				"api:read": {},
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:         "gateway",
+					Secret:     MustDecodeSecret("$plaintext$good_secret"),
+					GrantTypes: []string{"urn:ietf:params:oauth:grant-type:token-exchange"},
+					TokenExchange: schema.OpenIDConnectClientTokenExchange{
+						Audience: []string{"https://api.example.com"},
+						Scopes:   []string{"api:read"},
+					},
+				},
+				{
+					ID:         "no-audience",
+					Secret:     MustDecodeSecret("$plaintext$good_secret"),
+					GrantTypes: []string{"urn:ietf:params:oauth:grant-type:token-exchange"},
+					TokenExchange: schema.OpenIDConnectClientTokenExchange{
+						Scopes: []string{"api:write"},
+					},
+				},
+				{
+					ID:                      "public",
+					Public:                  true,
+					TokenEndpointAuthMethod: "none",
+					GrantTypes:              []string{"urn:ietf:params:oauth:grant-type:token-exchange"},
+					TokenExchange: schema.OpenIDConnectClientTokenExchange{
+						Audience: []string{"https://api.example.com"},
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'no-audience': token_exchange: option 'audience' must be configured when option 'grant_types' contains the 'urn:ietf:params:oauth:grant-type:token-exchange' grant type")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'no-audience': option 'token_exchange.scopes' must only have the values 'openid', 'email', 'profile', 'groups', 'offline_access', 'api:read' but one option is configured as 'api:write'")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: client 'public': option 'grant_types' must not contain the 'urn:ietf:params:oauth:grant-type:token-exchange' grant type when option 'public' is true")
 }
 
 func TestShouldNotErrorOnCertificateValid(t *testing.T) {
@@ -543,6 +836,292 @@ func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadUserinfoAlg(t *testing.T
 	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'userinfo_signing_algorithm' must be one of 'none, RS256' but it is configured as 'rs256'")
 }
 
+func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadAccessTokenAlg(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: MustParseRSAPrivateKey(testKey1),
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:                          "good_id",
+					Secret:                      MustDecodeSecret("$plaintext$good_secret"),
+					Policy:                      "two_factor",
+					AccessTokenSigningAlgorithm: oidc.SigningAlgorithmRSAWithSHA256,
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+				{
+					ID:                          "bad_id",
+					Secret:                      MustDecodeSecret("$plaintext$good_secret"),
+					Policy:                      "two_factor",
+					AccessTokenSigningAlgorithm: oidc.SigningAlgorithmEdDSA,
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+				{
+					ID:     "default_id",
+					Secret: MustDecodeSecret("$plaintext$good_secret"),
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'bad_id': option 'access_token_signing_algorithm' must be one of 'none, RS256' but it is configured as 'EdDSA'")
+
+	assert.Equal(t, oidc.SigningAlgorithmRSAWithSHA256, config.OIDC.Clients[0].AccessTokenSigningAlgorithm)
+	assert.Equal(t, oidc.SigningAlgorithmNone, config.OIDC.Clients[2].AccessTokenSigningAlgorithm)
+}
+
+func TestValidateOIDCIssuerPrivateKeys(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecKeyP224, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	now := time.Now()
+
+	testCases := []struct {
+		name     string
+		have     []schema.OpenIDConnectIssuerPrivateKey
+		legacy   bool
+		expected []string
+		errs     []string
+	}{
+		{
+			"ShouldSetDefaultAlgorithms",
+			[]schema.OpenIDConnectIssuerPrivateKey{
+				{Key: MustParseRSAPrivateKey(testKey1)},
+				{Key: ecKey},
+				{Key: edKey},
+				{Key: MustParseRSAPrivateKey(testKey2), Algorithm: oidc.SigningAlgorithmRSAPSSWithSHA256, NotBefore: now, NotAfter: now.Add(time.Hour)},
+			},
+			false,
+			[]string{oidc.SigningAlgorithmRSAWithSHA256, oidc.SigningAlgorithmECDSAWithP256AndSHA256, oidc.SigningAlgorithmEdDSA, oidc.SigningAlgorithmRSAPSSWithSHA256},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnLegacyKey",
+			[]schema.OpenIDConnectIssuerPrivateKey{
+				{Key: MustParseRSAPrivateKey(testKey1)},
+			},
+			true,
+			[]string{oidc.SigningAlgorithmRSAWithSHA256},
+			[]string{
+				"identity_providers: oidc: option 'issuer_private_key' and option 'issuer_private_keys' must not both be configured",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnInvalidKeys",
+			[]schema.OpenIDConnectIssuerPrivateKey{
+				{},
+				{Key: ecKeyP224},
+				{Key: ecKey, Algorithm: oidc.SigningAlgorithmECDSAWithP384AndSHA384},
+				{Key: edKey, KeyID: "abc", NotBefore: now, NotAfter: now},
+				{Key: MustParseRSAPrivateKey(testKey3), KeyID: "abc"},
+			},
+			false,
+			[]string{"", "", oidc.SigningAlgorithmECDSAWithP384AndSHA384, oidc.SigningAlgorithmEdDSA, oidc.SigningAlgorithmRSAWithSHA256},
+			[]string{
+				"identity_providers: oidc: issuer_private_keys: key #1: option 'key' is required",
+				"identity_providers: oidc: issuer_private_keys: key #2: option 'key' must be an RSA private key, an ECDSA private key using the P-256, P-384, or P-521 curve, or an Ed25519 private key",
+				"identity_providers: oidc: issuer_private_keys: key #3: option 'algorithm' must be one of 'ES256' for the configured key but it is configured as 'ES384'",
+				"identity_providers: oidc: issuer_private_keys: key #4: option 'not_after' must be after option 'not_before'",
+				"identity_providers: oidc: issuer_private_keys: key #5: option 'key' must be an RSA private key with 2048 bits or more but it only has 1024 bits",
+				"identity_providers: oidc: issuer_private_keys: key #5: option 'key_id' with value 'abc' must be unique but it is used by more than one key",
+			},
+		},
+		{
+			"ShouldRaiseErrorWithoutRS256Key",
+			[]schema.OpenIDConnectIssuerPrivateKey{
+				{Key: MustParseRSAPrivateKey(testKey1), Algorithm: oidc.SigningAlgorithmRSAWithSHA512},
+				{Key: edKey},
+			},
+			false,
+			[]string{oidc.SigningAlgorithmRSAWithSHA512, oidc.SigningAlgorithmEdDSA},
+			[]string{
+				"identity_providers: oidc: option 'issuer_private_keys' must contain at least one key with the algorithm 'RS256'",
+			},
+		},
+		{
+			"ShouldSetDefaultEncryptionAlgorithms",
+			[]schema.OpenIDConnectIssuerPrivateKey{
+				{Key: MustParseRSAPrivateKey(testKey1)},
+				{Key: MustParseRSAPrivateKey(testKey2), Use: oidc.KeyUseEncryption},
+				{Key: ecKey, Use: oidc.KeyUseEncryption},
+			},
+			false,
+			[]string{oidc.SigningAlgorithmRSAWithSHA256, oidc.EncryptionAlgorithmRSAOAEP256, oidc.EncryptionAlgorithmECDHES},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnInvalidEncryptionKeys",
+			[]schema.OpenIDConnectIssuerPrivateKey{
+				{Key: MustParseRSAPrivateKey(testKey1)},
+				{Key: MustParseRSAPrivateKey(testKey1), Use: oidc.KeyUseEncryption},
+				{Key: edKey, Use: oidc.KeyUseEncryption},
+				{Key: MustParseRSAPrivateKey(testKey2), Use: "bad"},
+			},
+			false,
+			[]string{oidc.SigningAlgorithmRSAWithSHA256, oidc.EncryptionAlgorithmRSAOAEP256, ""},
+			[]string{
+				"identity_providers: oidc: issuer_private_keys: key #2: option 'key' must not be the same private key as a key with option 'use' configured as 'sig' when option 'use' is 'enc'",
+				"identity_providers: oidc: issuer_private_keys: key #3: option 'key' must be an RSA private key or an ECDSA private key when option 'use' is 'enc'",
+				"identity_providers: oidc: issuer_private_keys: key #4: option 'use' must be one of 'sig', 'enc' but it is configured as 'bad'",
+			},
+		},
+		{
+			"ShouldRaiseErrorWithoutRS256SigningKey",
+			[]schema.OpenIDConnectIssuerPrivateKey{
+				{Key: MustParseRSAPrivateKey(testKey1), Use: oidc.KeyUseEncryption},
+				{Key: edKey},
+			},
+			false,
+			[]string{oidc.EncryptionAlgorithmRSAOAEP256, oidc.SigningAlgorithmEdDSA},
+			[]string{
+				"identity_providers: oidc: option 'issuer_private_keys' must contain at least one key with the algorithm 'RS256'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.OpenIDConnectConfiguration{
+				HMACSecret:        "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+				IssuerPrivateKeys: tc.have,
+				Clients: []schema.OpenIDConnectClientConfiguration{
+					{
+						ID:           "good_id",
+						Secret:       MustDecodeSecret("$plaintext$good_secret"),
+						Policy:       "two_factor",
+						RedirectURIs: []string{"https://google.com/callback"},
+					},
+				},
+			}
+
+			if tc.legacy {
+				config.IssuerPrivateKey = MustParseRSAPrivateKey(testKey1)
+			}
+
+			validateOIDC(config, validator)
+
+			for i, alg := range tc.expected {
+				assert.Equal(t, alg, config.IssuerPrivateKeys[i].Algorithm)
+			}
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, errs[i], err)
+			}
+		})
+	}
+}
+
+func TestShouldRaiseErrorWhenOIDCClientConfiguredWithUnavailableSigningAlg(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret: "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKey{
+				{Key: MustParseRSAPrivateKey(testKey1)},
+				{Key: edKey},
+			},
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:                       "good_id",
+					Secret:                   MustDecodeSecret("$plaintext$good_secret"),
+					Policy:                   "two_factor",
+					IDTokenSigningAlgorithm:  oidc.SigningAlgorithmEdDSA,
+					UserinfoSigningAlgorithm: oidc.SigningAlgorithmEdDSA,
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+				{
+					ID:                       "bad_id",
+					Secret:                   MustDecodeSecret("$plaintext$good_secret"),
+					Policy:                   "two_factor",
+					IDTokenSigningAlgorithm:  oidc.SigningAlgorithmECDSAWithP256AndSHA256,
+					UserinfoSigningAlgorithm: oidc.SigningAlgorithmRSAWithSHA384,
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+				{
+					ID:     "default_id",
+					Secret: MustDecodeSecret("$plaintext$good_secret"),
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'bad_id': option 'id_token_signing_algorithm' must be one of 'RS256, EdDSA' but it is configured as 'ES256'")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'bad_id': option 'userinfo_signing_algorithm' must be one of 'none, RS256, EdDSA' but it is configured as 'RS384'")
+
+	assert.Equal(t, oidc.SigningAlgorithmRSAWithSHA256, config.OIDC.Clients[2].IDTokenSigningAlgorithm)
+	assert.Equal(t, oidc.SigningAlgorithmNone, config.OIDC.Clients[2].UserinfoSigningAlgorithm)
+}
+
+func TestShouldRaiseErrorWhenOIDCClientConfiguredWithBadLogoutURIs(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.IdentityProvidersConfiguration{
+		OIDC: &schema.OpenIDConnectConfiguration{
+			HMACSecret:       "rLABDrx87et5KvRHVUgTm3pezWWd8LMN",
+			IssuerPrivateKey: MustParseRSAPrivateKey(testKey1),
+			Clients: []schema.OpenIDConnectClientConfiguration{
+				{
+					ID:     "good_id",
+					Secret: MustDecodeSecret("$plaintext$good_secret"),
+					Policy: "two_factor",
+					RedirectURIs: []string{
+						"https://google.com/callback",
+					},
+					PostLogoutRedirectURIs: []string{
+						"https://google.com/logged-out",
+						"/logged-out",
+					},
+					FrontChannelLogoutURI: "https://google.com/logout#fragment",
+					BackChannelLogoutURI:  "ftp://google.com/logout",
+				},
+			},
+		},
+	}
+
+	ValidateIdentityProviders(config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "identity_providers: oidc: client 'good_id': option 'post_logout_redirect_uris' has an invalid value: uri '/logged-out' must be an absolute uri with the 'http' or 'https' scheme and without a fragment")
+	assert.EqualError(t, validator.Errors()[1], "identity_providers: oidc: client 'good_id': option 'frontchannel_logout_uri' has an invalid value: uri 'https://google.com/logout#fragment' must be an absolute uri with the 'http' or 'https' scheme and without a fragment")
+	assert.EqualError(t, validator.Errors()[2], "identity_providers: oidc: client 'good_id': option 'backchannel_logout_uri' has an invalid value: uri 'ftp://google.com/logout' must be an absolute uri with the 'http' or 'https' scheme and without a fragment")
+}
+
 func TestValidateIdentityProvidersShouldRaiseWarningOnSecurityIssue(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := &schema.IdentityProvidersConfiguration{
@@ -968,3 +1547,298 @@ KBL3XEahgs6Os5EYZ4aBAkEAjKE+2/nBYUdHVusjMXeNsE5rqwJND5zvYzmToG7+
 xhv4RUAe4dHL4IDQoQRjhr3Nw+JYvtzBx0Iq/178xMnGKg==
 -----END RSA PRIVATE KEY-----`
 )
+
+func TestValidateOIDCClientTokenEndpointAuth(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	jwks := func(keys ...any) string {
+		set := jose.JSONWebKeySet{}
+
+		for _, key := range keys {
+			set.Keys = append(set.Keys, jose.JSONWebKey{Key: key, Use: "sig"})
+		}
+
+		data, err := json.Marshal(set)
+		require.NoError(t, err)
+
+		return string(data)
+	}
+
+	rsaJWKS := jwks(MustParseRSAPrivateKey(testKey1).Public())
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "jwks.json")
+
+	require.NoError(t, os.WriteFile(path, []byte(rsaJWKS), 0600))
+
+	testCases := []struct {
+		name         string
+		have         schema.OpenIDConnectClientConfiguration
+		expectedAuth string
+		expectedAlg  string
+		errs         []string
+	}{
+		{
+			"ShouldAllowDefaultConfidentialClient",
+			schema.OpenIDConnectClientConfiguration{Secret: MustDecodeSecret("$plaintext$good_secret")},
+			"",
+			"",
+			nil,
+		},
+		{
+			"ShouldSetDefaultPublicClientMethod",
+			schema.OpenIDConnectClientConfiguration{Public: true},
+			oidc.ClientAuthMethodNone,
+			"",
+			nil,
+		},
+		{
+			"ShouldSetDefaultClientSecretJWTAlgorithm",
+			schema.OpenIDConnectClientConfiguration{Secret: MustDecodeSecret("$plaintext$good_secret"), TokenEndpointAuthMethod: oidc.ClientAuthMethodClientSecretJWT},
+			oidc.ClientAuthMethodClientSecretJWT,
+			oidc.SigningAlgorithmHMACWithSHA256,
+			nil,
+		},
+		{
+			"ShouldSetDefaultPrivateKeyJWTAlgorithmWithoutSecret",
+			schema.OpenIDConnectClientConfiguration{TokenEndpointAuthMethod: oidc.ClientAuthMethodPrivateKeyJWT, JSONWebKeys: rsaJWKS},
+			oidc.ClientAuthMethodPrivateKeyJWT,
+			oidc.SigningAlgorithmRSAWithSHA256,
+			nil,
+		},
+		{
+			"ShouldAllowPrivateKeyJWTWithJWKSPath",
+			schema.OpenIDConnectClientConfiguration{TokenEndpointAuthMethod: oidc.ClientAuthMethodPrivateKeyJWT, TokenEndpointAuthSigningAlgorithm: oidc.SigningAlgorithmRSAPSSWithSHA512, JSONWebKeysPath: path},
+			oidc.ClientAuthMethodPrivateKeyJWT,
+			oidc.SigningAlgorithmRSAPSSWithSHA512,
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnInvalidMethod",
+			schema.OpenIDConnectClientConfiguration{Secret: MustDecodeSecret("$plaintext$good_secret"), TokenEndpointAuthMethod: "bad"},
+			"bad",
+			"",
+			[]string{
+				"identity_providers: oidc: client 'test': option 'token_endpoint_auth_method' must be one of 'client_secret_basic', 'client_secret_post', 'client_secret_jwt', 'private_key_jwt', 'none' but it is configured as 'bad'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnPublicClientWithSecretMethod",
+			schema.OpenIDConnectClientConfiguration{Public: true, TokenEndpointAuthMethod: oidc.ClientAuthMethodClientSecretBasic},
+			oidc.ClientAuthMethodClientSecretBasic,
+			"",
+			[]string{
+				"identity_providers: oidc: client 'test': option 'token_endpoint_auth_method' must not be 'client_secret_basic' when option 'public' is true",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnConfidentialClientWithNoneMethod",
+			schema.OpenIDConnectClientConfiguration{Secret: MustDecodeSecret("$plaintext$good_secret"), TokenEndpointAuthMethod: oidc.ClientAuthMethodNone},
+			oidc.ClientAuthMethodNone,
+			"",
+			[]string{
+				"identity_providers: oidc: client 'test': option 'token_endpoint_auth_method' must not be 'none' when option 'public' is false",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnClientSecretJWTWithHashedSecret",
+			schema.OpenIDConnectClientConfiguration{
+				Secret:                            MustDecodeSecret("$pbkdf2-sha512$310000$c8p78n7pUMln0jzvd4aK4Q$JNRBzwAo0ek5qKn50cFzzvE9RXV88h1wJn5KGiHrD0YKtZaR/nCb2CJPOsKaPK0hjf.9yHxzQGZziziccp6Yng"),
+				TokenEndpointAuthMethod:           oidc.ClientAuthMethodClientSecretJWT,
+				TokenEndpointAuthSigningAlgorithm: oidc.SigningAlgorithmRSAWithSHA256,
+			},
+			oidc.ClientAuthMethodClientSecretJWT,
+			oidc.SigningAlgorithmRSAWithSHA256,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'secret' must be a plaintext value when option 'token_endpoint_auth_method' is 'client_secret_jwt'",
+				"identity_providers: oidc: client 'test': option 'token_endpoint_auth_signing_algorithm' must be one of 'HS256', 'HS384', 'HS512' when option 'token_endpoint_auth_method' is 'client_secret_jwt' but it is configured as 'RS256'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnPrivateKeyJWTWithoutJWKS",
+			schema.OpenIDConnectClientConfiguration{TokenEndpointAuthMethod: oidc.ClientAuthMethodPrivateKeyJWT},
+			oidc.ClientAuthMethodPrivateKeyJWT,
+			oidc.SigningAlgorithmRSAWithSHA256,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'jwks' or 'jwks_path' is required when option 'token_endpoint_auth_method' is 'private_key_jwt'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnPrivateKeyJWTWithoutCompatibleKey",
+			schema.OpenIDConnectClientConfiguration{TokenEndpointAuthMethod: oidc.ClientAuthMethodPrivateKeyJWT, JSONWebKeys: jwks(edKey.Public())},
+			oidc.ClientAuthMethodPrivateKeyJWT,
+			oidc.SigningAlgorithmRSAWithSHA256,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'jwks' must contain at least one signing key compatible with the 'RS256' algorithm",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnPrivateKeys",
+			schema.OpenIDConnectClientConfiguration{TokenEndpointAuthMethod: oidc.ClientAuthMethodPrivateKeyJWT, TokenEndpointAuthSigningAlgorithm: oidc.SigningAlgorithmEdDSA, JSONWebKeys: jwks(edKey)},
+			oidc.ClientAuthMethodPrivateKeyJWT,
+			oidc.SigningAlgorithmEdDSA,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'jwks' must only contain public keys but key #1 is a private key",
+				"identity_providers: oidc: client 'test': option 'jwks' must contain at least one signing key compatible with the 'EdDSA' algorithm",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnBothJWKSOptions",
+			schema.OpenIDConnectClientConfiguration{TokenEndpointAuthMethod: oidc.ClientAuthMethodPrivateKeyJWT, JSONWebKeys: rsaJWKS, JSONWebKeysPath: path},
+			oidc.ClientAuthMethodPrivateKeyJWT,
+			oidc.SigningAlgorithmRSAWithSHA256,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'jwks' and option 'jwks_path' must not both be configured",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnBadJWKSPath",
+			schema.OpenIDConnectClientConfiguration{TokenEndpointAuthMethod: oidc.ClientAuthMethodPrivateKeyJWT, JSONWebKeysPath: filepath.Join(dir, "missing.json")},
+			oidc.ClientAuthMethodPrivateKeyJWT,
+			oidc.SigningAlgorithmRSAWithSHA256,
+			[]string{
+				fmt.Sprintf("identity_providers: oidc: client 'test': option 'jwks_path' could not be read: open %s: no such file or directory", filepath.Join(dir, "missing.json")),
+				"identity_providers: oidc: client 'test': option 'jwks' or 'jwks_path' is required when option 'token_endpoint_auth_method' is 'private_key_jwt'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnBadJWKS",
+			schema.OpenIDConnectClientConfiguration{TokenEndpointAuthMethod: oidc.ClientAuthMethodPrivateKeyJWT, JSONWebKeys: "{"},
+			oidc.ClientAuthMethodPrivateKeyJWT,
+			oidc.SigningAlgorithmRSAWithSHA256,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'jwks' could not be parsed as a JSON Web Key Set: unexpected end of JSON input",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnSigningAlgorithmWithoutJWTMethod",
+			schema.OpenIDConnectClientConfiguration{Secret: MustDecodeSecret("$plaintext$good_secret"), TokenEndpointAuthMethod: oidc.ClientAuthMethodClientSecretPost, TokenEndpointAuthSigningAlgorithm: oidc.SigningAlgorithmHMACWithSHA256},
+			oidc.ClientAuthMethodClientSecretPost,
+			oidc.SigningAlgorithmHMACWithSHA256,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'token_endpoint_auth_signing_algorithm' must only be configured when option 'token_endpoint_auth_method' is 'private_key_jwt' or 'client_secret_jwt' but it is configured as 'client_secret_post'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			tc.have.ID = "test"
+
+			config := &schema.OpenIDConnectConfiguration{
+				Clients: []schema.OpenIDConnectClientConfiguration{tc.have},
+			}
+
+			validateOIDCClientTokenEndpointAuth(0, config, validator)
+
+			assert.Equal(t, tc.expectedAuth, config.Clients[0].TokenEndpointAuthMethod)
+			assert.Equal(t, tc.expectedAlg, config.Clients[0].TokenEndpointAuthSigningAlgorithm)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, errs[i], err)
+			}
+		})
+	}
+}
+
+func TestValidateOIDCClientRequestObject(t *testing.T) {
+	data, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: MustParseRSAPrivateKey(testKey1).Public(), Use: "sig"}}})
+	require.NoError(t, err)
+
+	jwks := string(data)
+
+	testCases := []struct {
+		name     string
+		have     schema.OpenIDConnectClientConfiguration
+		expected string
+		errs     []string
+	}{
+		{
+			"ShouldAllowDefaultClient",
+			schema.OpenIDConnectClientConfiguration{},
+			"",
+			nil,
+		},
+		{
+			"ShouldAllowUnsignedRequestObjects",
+			schema.OpenIDConnectClientConfiguration{RequestObjectSigningAlgorithm: oidc.SigningAlgorithmNone, RequestURIs: []string{"https://app.example.com/request.jwt"}},
+			oidc.SigningAlgorithmNone,
+			nil,
+		},
+		{
+			"ShouldSetDefaultAlgorithmWhenSignedRequired",
+			schema.OpenIDConnectClientConfiguration{RequireSignedRequestObject: true, JSONWebKeys: jwks},
+			oidc.SigningAlgorithmRSAWithSHA256,
+			nil,
+		},
+		{
+			"ShouldAllowSigningAlgorithmWithJWKS",
+			schema.OpenIDConnectClientConfiguration{RequestObjectSigningAlgorithm: oidc.SigningAlgorithmRSAPSSWithSHA256, JSONWebKeys: jwks},
+			oidc.SigningAlgorithmRSAPSSWithSHA256,
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnNoneWhenSignedRequired",
+			schema.OpenIDConnectClientConfiguration{RequestObjectSigningAlgorithm: oidc.SigningAlgorithmNone, RequireSignedRequestObject: true},
+			oidc.SigningAlgorithmNone,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'request_object_signing_algorithm' must not be 'none' when option 'require_signed_request_object' is true",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnInvalidAlgorithm",
+			schema.OpenIDConnectClientConfiguration{RequestObjectSigningAlgorithm: oidc.SigningAlgorithmHMACWithSHA256, JSONWebKeys: jwks},
+			oidc.SigningAlgorithmHMACWithSHA256,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'request_object_signing_algorithm' must be one of 'none', 'RS256', 'RS384', 'RS512', 'PS256', 'PS384', 'PS512', 'ES256', 'ES384', 'ES512', 'EdDSA' but it is configured as 'HS256'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnMissingJWKS",
+			schema.OpenIDConnectClientConfiguration{RequireSignedRequestObject: true},
+			oidc.SigningAlgorithmRSAWithSHA256,
+			[]string{
+				"identity_providers: oidc: client 'test': option 'jwks' or 'jwks_path' is required when option 'request_object_signing_algorithm' is 'RS256'",
+			},
+		},
+		{
+			"ShouldRaiseErrorOnInvalidRequestURI",
+			schema.OpenIDConnectClientConfiguration{RequestURIs: []string{"urn:example:request"}},
+			"",
+			[]string{
+				"identity_providers: oidc: client 'test': option 'request_uris' has an invalid value: uri 'urn:example:request' must be an absolute uri with the 'http' or 'https' scheme and without a fragment",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+
+			tc.have.ID = "test"
+
+			config := &schema.OpenIDConnectConfiguration{
+				Clients: []schema.OpenIDConnectClientConfiguration{tc.have},
+			}
+
+			validateOIDCClientRequestObject(0, config, validator)
+
+			assert.Equal(t, tc.expected, config.Clients[0].RequestObjectSigningAlgorithm)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, err := range tc.errs {
+				assert.EqualError(t, errs[i], err)
+			}
+		})
+	}
+}
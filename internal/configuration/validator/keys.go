@@ -3,6 +3,7 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -11,8 +12,18 @@ import (
 
 // ValidateKeys determines if all provided keys are valid.
 func ValidateKeys(keys []string, prefix string, validator *schema.StructValidator) {
-	var errStrings []string
+	var (
+		errStrings []string
+		patterns   []*regexp.Regexp
+	)
 
+	for _, key := range schema.Keys {
+		if pattern := NewKeyPattern(key); pattern != nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+
+KEYS:
 	for _, key := range keys {
 		expectedKey := reKeyReplacer.ReplaceAllString(key, "[]")
 
@@ -20,6 +31,12 @@ func ValidateKeys(keys []string, prefix string, validator *schema.StructValidato
 			continue
 		}
 
+		for _, pattern := range patterns {
+			if pattern.MatchString(expectedKey) {
+				continue KEYS
+			}
+		}
+
 		if newKey, ok := replacedKeys[expectedKey]; ok {
 			validator.Push(fmt.Errorf(errFmtReplacedConfigurationKey, key, newKey))
 			continue
@@ -42,3 +59,19 @@ func ValidateKeys(keys []string, prefix string, validator *schema.StructValidato
 		validator.Push(errors.New(err))
 	}
 }
+
+// NewKeyPattern returns a pattern which matches the keys of a map for a schema key containing the map wildcard
+// character. It returns nil if the key does not contain the map wildcard character.
+func NewKeyPattern(key string) (pattern *regexp.Regexp) {
+	if !strings.Contains(key, ".*.") {
+		return nil
+	}
+
+	parts := strings.Split(key, ".*.")
+
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+
+	return regexp.MustCompile(fmt.Sprintf("^%s$", strings.Join(parts, `\.[a-zA-Z0-9/_-]+\.`)))
+}
@@ -33,6 +33,36 @@ func TestShouldNotValidateBadKeys(t *testing.T) {
 	assert.EqualError(t, errs[1], "configuration key not expected: totp.skewy")
 }
 
+func TestShouldValidateMapKeys(t *testing.T) {
+	configKeys := []string{
+		"server.endpoints.authz.forward-auth.implementation",
+		"server.endpoints.authz.forward-auth.authn_strategies[0].name",
+		"server.endpoints.authz.example/nested_name.implementation",
+		"server.endpoints.authz.forward-auth.bad_key",
+		"server.endpoints.authz.implementation",
+	}
+
+	val := schema.NewStructValidator()
+	ValidateKeys(configKeys, "AUTHELIA_", val)
+
+	errs := val.Errors()
+	require.Len(t, errs, 2)
+
+	assert.EqualError(t, errs[0], "configuration key not expected: server.endpoints.authz.forward-auth.bad_key")
+	assert.EqualError(t, errs[1], "configuration key not expected: server.endpoints.authz.implementation")
+}
+
+func TestNewKeyPattern(t *testing.T) {
+	assert.Nil(t, NewKeyPattern("server.host"))
+
+	pattern := NewKeyPattern("server.endpoints.authz.*.implementation")
+	require.NotNil(t, pattern)
+
+	assert.True(t, pattern.MatchString("server.endpoints.authz.legacy.implementation"))
+	assert.False(t, pattern.MatchString("server.endpoints.authz..implementation"))
+	assert.False(t, pattern.MatchString("server.endpoints.authz.a.b.implementation"))
+}
+
 func TestShouldNotValidateBadEnvKeys(t *testing.T) {
 	configKeys := schema.Keys
 	configKeys = append(configKeys, "AUTHELIA__BAD_ENV_KEY")
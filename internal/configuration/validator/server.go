@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -57,7 +58,12 @@ func ValidateServer(config *schema.Configuration, validator *schema.StructValida
 		config.Server.Port = schema.DefaultServerConfiguration.Port
 	}
 
+	if len(config.Server.TrustedProxies) == 0 {
+		config.Server.TrustedProxies = schema.DefaultServerConfiguration.TrustedProxies
+	}
+
 	ValidateServerTLS(config, validator)
+	validateProxyProtocol("server", &config.Server.ProxyProtocol, validator)
 
 	switch {
 	case strings.Contains(config.Server.Path, "/"):
@@ -89,4 +95,133 @@ func ValidateServer(config *schema.Configuration, validator *schema.StructValida
 	if config.Server.Timeouts.Idle <= 0 {
 		config.Server.Timeouts.Idle = schema.DefaultServerConfiguration.Timeouts.Idle
 	}
+
+	ValidateServerEndpoints(config, validator)
+}
+
+// ValidateServerEndpoints configures the default endpoints and checks the configuration of custom endpoints.
+func ValidateServerEndpoints(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.Server.Endpoints.Admin.Enabled && len(config.Server.Endpoints.Admin.Groups) == 0 {
+		validator.Push(fmt.Errorf(errFmtServerEndpointsAdminGroups))
+	}
+
+	if len(config.Server.Endpoints.Authz) == 0 {
+		config.Server.Endpoints.Authz = make(map[string]schema.ServerAuthzEndpoint, len(schema.DefaultServerConfiguration.Endpoints.Authz))
+
+		for name, endpoint := range schema.DefaultServerConfiguration.Endpoints.Authz {
+			config.Server.Endpoints.Authz[name] = endpoint
+		}
+
+		return
+	}
+
+	authzs := make([]string, 0, len(config.Server.Endpoints.Authz))
+
+	for name := range config.Server.Endpoints.Authz {
+		authzs = append(authzs, name)
+	}
+
+	sort.Strings(authzs)
+
+	for _, name := range authzs {
+		endpoint := config.Server.Endpoints.Authz[name]
+
+		validateServerEndpointsAuthzEndpoint(config, name, endpoint, validator)
+
+		for _, oName := range authzs {
+			oEndpoint := config.Server.Endpoints.Authz[oName]
+
+			if oName == name || oEndpoint.Implementation != schema.AuthzImplementationExtAuthz {
+				continue
+			}
+
+			if strings.HasPrefix(name, oName+"/") {
+				validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzPrefixDuplicate, name, oName, oEndpoint.Implementation))
+			}
+		}
+
+		validateServerEndpointsAuthzStrategies(config, name, validator)
+	}
+}
+
+func validateServerEndpointsAuthzEndpoint(config *schema.Configuration, name string, endpoint schema.ServerAuthzEndpoint, validator *schema.StructValidator) {
+	if name == schema.AuthzEndpointNameLegacy {
+		switch endpoint.Implementation {
+		case schema.AuthzImplementationLegacy:
+			break
+		case "":
+			endpoint.Implementation = schema.AuthzImplementationLegacy
+
+			config.Server.Endpoints.Authz[name] = endpoint
+		default:
+			validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzLegacyInvalidImplementation, name))
+		}
+	} else if !utils.IsStringInSlice(endpoint.Implementation, validAuthzImplementations) {
+		validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzImplementation, name, strings.Join(validAuthzImplementations, "', '"), endpoint.Implementation))
+	}
+
+	if !reAuthzEndpointName.MatchString(name) {
+		validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzInvalidName, name))
+	}
+}
+
+func validateServerEndpointsAuthzStrategies(config *schema.Configuration, name string, validator *schema.StructValidator) {
+	endpoint := config.Server.Endpoints.Authz[name]
+
+	switch {
+	case endpoint.Implementation == schema.AuthzImplementationLegacy:
+		if len(endpoint.AuthnStrategies) != 0 {
+			validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzLegacyStrategies, name))
+		}
+
+		return
+	case len(endpoint.AuthnStrategies) == 0:
+		switch endpoint.Implementation {
+		case schema.AuthzImplementationAuthRequest:
+			endpoint.AuthnStrategies = schema.DefaultServerConfiguration.Endpoints.Authz[schema.AuthzEndpointNameAuthRequest].AuthnStrategies
+		default:
+			endpoint.AuthnStrategies = schema.DefaultServerConfiguration.Endpoints.Authz[schema.AuthzEndpointNameForwardAuth].AuthnStrategies
+		}
+
+		config.Server.Endpoints.Authz[name] = endpoint
+
+		return
+	}
+
+	names := make([]string, 0, len(endpoint.AuthnStrategies))
+
+	for _, strategy := range endpoint.AuthnStrategies {
+		if utils.IsStringInSlice(strategy.Name, names) {
+			validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzStrategyDuplicate, name, strategy.Name))
+		}
+
+		names = append(names, strategy.Name)
+
+		if !utils.IsStringInSlice(strategy.Name, validAuthzAuthnStrategies) {
+			validator.Push(fmt.Errorf(errFmtServerEndpointsAuthzStrategy, name, strings.Join(validAuthzAuthnStrategies, "', '"), strategy.Name))
+		}
+	}
+}
+
+func validateProxyProtocol(prefix string, config *schema.ServerProxyProtocol, validator *schema.StructValidator) {
+	if !config.Enabled {
+		return
+	}
+
+	switch config.Mode {
+	case "":
+		config.Mode = schema.DefaultServerProxyProtocol.Mode
+	case schema.ProxyProtocolModeStrict, schema.ProxyProtocolModeOptional:
+		break
+	default:
+		validator.Push(fmt.Errorf(errFmtProxyProtocolMode, prefix, strings.Join(validProxyProtocolModes, "', '"), config.Mode))
+	}
+
+	if len(config.TrustedSources) == 0 {
+		validator.Push(fmt.Errorf(errFmtProxyProtocolTrustedSources, prefix))
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultServerProxyProtocol.Timeout
+	}
 }
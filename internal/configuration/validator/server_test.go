@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"net"
 	"os"
 	"testing"
 	"time"
@@ -31,6 +32,8 @@ func TestShouldSetDefaultServerValues(t *testing.T) {
 	assert.Equal(t, schema.DefaultServerConfiguration.Path, config.Server.Path)
 	assert.Equal(t, schema.DefaultServerConfiguration.EnableExpvars, config.Server.EnableExpvars)
 	assert.Equal(t, schema.DefaultServerConfiguration.EnablePprof, config.Server.EnablePprof)
+	assert.Equal(t, schema.DefaultServerConfiguration.Endpoints.Authz, config.Server.Endpoints.Authz)
+	assert.Equal(t, schema.DefaultServerConfiguration.TrustedProxies, config.Server.TrustedProxies)
 }
 
 func TestShouldSetDefaultConfig(t *testing.T) {
@@ -278,3 +281,208 @@ func TestShouldValidateAndUpdatePort(t *testing.T) {
 	require.Len(t, validator.Errors(), 0)
 	assert.Equal(t, 9091, config.Server.Port)
 }
+
+func TestServerAuthzEndpointErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		have map[string]schema.ServerAuthzEndpoint
+		errs []string
+	}{
+		{"ShouldAllowDefaultEndpoints", schema.DefaultServerConfiguration.Endpoints.Authz, nil},
+		{"ShouldAllowSetDefaultEndpoints", nil, nil},
+		{
+			"ShouldErrorOnInvalidEndpointImplementations",
+			map[string]schema.ServerAuthzEndpoint{
+				"example": {Implementation: "zero"},
+			},
+			[]string{"server: endpoints: authz: example: option 'implementation' must be one of 'AuthRequest', 'ForwardAuth', 'ExtAuthz', 'Legacy' but is configured as 'zero'"},
+		},
+		{
+			"ShouldErrorOnInvalidEndpointImplementationLegacy",
+			map[string]schema.ServerAuthzEndpoint{
+				"legacy": {Implementation: "zero"},
+			},
+			[]string{"server: endpoints: authz: legacy: option 'implementation' is invalid: the endpoint with the name 'legacy' must use the 'Legacy' implementation"},
+		},
+		{
+			"ShouldErrorOnInvalidEndpointLegacyStrategies",
+			map[string]schema.ServerAuthzEndpoint{
+				"legacy": {Implementation: schema.AuthzImplementationLegacy, AuthnStrategies: []schema.ServerAuthzEndpointAuthnStrategy{{Name: schema.AuthzStrategyHeaderCookieSession}}},
+			},
+			[]string{"server: endpoints: authz: legacy: option 'authn_strategies' is invalid: the 'Legacy' implementation does not support configuring authentication strategies"},
+		},
+		{
+			"ShouldErrorOnInvalidEndpointName",
+			map[string]schema.ServerAuthzEndpoint{
+				"-example": {Implementation: schema.AuthzImplementationForwardAuth},
+				"example/": {Implementation: schema.AuthzImplementationForwardAuth},
+			},
+			[]string{
+				"server: endpoints: authz: -example: contains invalid characters",
+				"server: endpoints: authz: example/: contains invalid characters",
+			},
+		},
+		{
+			"ShouldErrorOnDuplicatePrefix",
+			map[string]schema.ServerAuthzEndpoint{
+				"example":     {Implementation: schema.AuthzImplementationExtAuthz},
+				"example/abc": {Implementation: schema.AuthzImplementationForwardAuth},
+			},
+			[]string{
+				"server: endpoints: authz: example/abc: endpoint starts with the same prefix as the 'example' endpoint with the 'ExtAuthz' implementation which accepts prefixes as part of its implementation",
+			},
+		},
+		{
+			"ShouldErrorOnInvalidStrategies",
+			map[string]schema.ServerAuthzEndpoint{
+				"example": {
+					Implementation: schema.AuthzImplementationForwardAuth,
+					AuthnStrategies: []schema.ServerAuthzEndpointAuthnStrategy{
+						{Name: schema.AuthzStrategyHeaderCookieSession},
+						{Name: "bad-name"},
+						{Name: schema.AuthzStrategyHeaderCookieSession},
+					},
+				},
+			},
+			[]string{
+				"server: endpoints: authz: example: authn_strategies: option 'name' must be one of 'CookieSession', 'HeaderAuthorization', 'HeaderProxyAuthorization', 'HeaderAuthRequestProxyAuthorization' but is configured as 'bad-name'",
+				"server: endpoints: authz: example: authn_strategies: duplicate strategy name detected with name 'CookieSession'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Server: schema.ServerConfiguration{
+					Endpoints: schema.ServerEndpoints{
+						Authz: tc.have,
+					},
+				},
+			}
+
+			ValidateServer(config, validator)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, expected := range tc.errs {
+				assert.EqualError(t, errs[i], expected)
+			}
+		})
+	}
+}
+
+func TestServerAuthzEndpointDefaultStrategies(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.ServerConfiguration{
+			Endpoints: schema.ServerEndpoints{
+				Authz: map[string]schema.ServerAuthzEndpoint{
+					"nginx":   {Implementation: schema.AuthzImplementationAuthRequest},
+					"traefik": {Implementation: schema.AuthzImplementationForwardAuth},
+					"legacy":  {},
+				},
+			},
+		},
+	}
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Len(t, validator.Warnings(), 0)
+
+	assert.Equal(t, schema.AuthzImplementationLegacy, config.Server.Endpoints.Authz["legacy"].Implementation)
+	assert.Len(t, config.Server.Endpoints.Authz["legacy"].AuthnStrategies, 0)
+	assert.Equal(t, schema.DefaultServerConfiguration.Endpoints.Authz[schema.AuthzEndpointNameAuthRequest].AuthnStrategies, config.Server.Endpoints.Authz["nginx"].AuthnStrategies)
+	assert.Equal(t, schema.DefaultServerConfiguration.Endpoints.Authz[schema.AuthzEndpointNameForwardAuth].AuthnStrategies, config.Server.Endpoints.Authz["traefik"].AuthnStrategies)
+}
+
+func TestServerAdminEndpoint(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Server: schema.ServerConfiguration{
+			Endpoints: schema.ServerEndpoints{
+				Admin: schema.ServerEndpointsAdmin{
+					Enabled: true,
+				},
+			},
+		},
+	}
+
+	ValidateServer(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "server: endpoints: admin: option 'groups' must have at least one group when the admin endpoints are enabled")
+
+	validator = schema.NewStructValidator()
+	config.Server.Endpoints.Admin.Groups = []string{"admins"}
+
+	ValidateServer(config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestServerProxyProtocol(t *testing.T) {
+	trusted := []*net.IPNet{{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}}
+
+	testCases := []struct {
+		name     string
+		have     schema.ServerProxyProtocol
+		expected schema.ServerProxyProtocol
+		errs     []string
+	}{
+		{
+			"ShouldNotValidateWhenDisabled",
+			schema.ServerProxyProtocol{Mode: "abc"},
+			schema.ServerProxyProtocol{Mode: "abc"},
+			nil,
+		},
+		{
+			"ShouldSetDefaults",
+			schema.ServerProxyProtocol{Enabled: true, TrustedSources: trusted},
+			schema.ServerProxyProtocol{Enabled: true, TrustedSources: trusted, Mode: schema.ProxyProtocolModeStrict, Timeout: time.Second * 5},
+			nil,
+		},
+		{
+			"ShouldNotOverrideValues",
+			schema.ServerProxyProtocol{Enabled: true, TrustedSources: trusted, Mode: schema.ProxyProtocolModeOptional, Timeout: time.Second},
+			schema.ServerProxyProtocol{Enabled: true, TrustedSources: trusted, Mode: schema.ProxyProtocolModeOptional, Timeout: time.Second},
+			nil,
+		},
+		{
+			"ShouldRaiseErrorOnInvalidModeAndMissingSources",
+			schema.ServerProxyProtocol{Enabled: true, Mode: "abc"},
+			schema.ServerProxyProtocol{Enabled: true, Mode: "abc", Timeout: time.Second * 5},
+			[]string{
+				"server: proxy_protocol: option 'mode' must be one of 'strict', 'optional' but it's configured as 'abc'",
+				"server: proxy_protocol: option 'trusted_sources' is required when the PROXY protocol is enabled",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := &schema.Configuration{
+				Server: schema.ServerConfiguration{
+					ProxyProtocol: tc.have,
+				},
+			}
+
+			ValidateServer(config, validator)
+
+			errs := validator.Errors()
+
+			require.Len(t, errs, len(tc.errs))
+
+			for i, expected := range tc.errs {
+				assert.EqualError(t, errs[i], expected)
+			}
+
+			assert.Equal(t, tc.expected, config.Server.ProxyProtocol)
+		})
+	}
+}
@@ -16,13 +16,20 @@ func ValidateSession(config *schema.SessionConfiguration, validator *schema.Stru
 	}
 
 	if config.Redis != nil {
-		if config.Redis.HighAvailability != nil {
+		switch {
+		case config.Redis.Cluster != nil:
+			validateRedisCluster(config, validator)
+		case config.Redis.HighAvailability != nil:
 			validateRedisSentinel(config, validator)
-		} else {
+		default:
 			validateRedis(config, validator)
 		}
 	}
 
+	if config.SQL.Enabled {
+		validateSessionSQL(config, validator)
+	}
+
 	validateSession(config, validator)
 }
 
@@ -35,11 +42,17 @@ func validateSession(config *schema.SessionConfiguration, validator *schema.Stru
 		config.Inactivity = schema.DefaultSessionConfiguration.Inactivity // 5 min.
 	}
 
+	for i, secret := range config.PreviousSecrets {
+		if secret == "" {
+			validator.Push(fmt.Errorf(errFmtSessionPreviousSecretsEmpty, i+1))
+		}
+	}
+
 	if config.RememberMeDuration <= 0 && config.RememberMeDuration != schema.RememberMeDisabled {
 		config.RememberMeDuration = schema.DefaultSessionConfiguration.RememberMeDuration // 1 month.
 	}
 
-	if config.Domain == "" {
+	if config.Domain == "" && len(config.Cookies) == 0 {
 		validator.Push(fmt.Errorf(errFmtSessionOptionRequired, "domain"))
 	} else if strings.HasPrefix(config.Domain, ".") {
 		validator.PushWarning(fmt.Errorf("session: option 'domain' has a prefix of '.' which is not supported or intended behaviour: you can use this at your own risk but we recommend removing it"))
@@ -54,6 +67,107 @@ func validateSession(config *schema.SessionConfiguration, validator *schema.Stru
 	} else if !utils.IsStringInSlice(config.SameSite, validSessionSameSiteValues) {
 		validator.Push(fmt.Errorf(errFmtSessionSameSite, strings.Join(validSessionSameSiteValues, "', '"), config.SameSite))
 	}
+
+	validateSessionCookies(config, validator)
+	validateSessionBinding(config, validator)
+}
+
+func validateSessionBinding(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	switch {
+	case config.Binding.IPv4Mask == 0:
+		config.Binding.IPv4Mask = schema.DefaultSessionBindingConfiguration.IPv4Mask
+	case config.Binding.IPv4Mask < 0 || config.Binding.IPv4Mask > 32:
+		validator.Push(fmt.Errorf(errFmtSessionBindingMask, "ipv4_mask", 32, config.Binding.IPv4Mask))
+	}
+
+	switch {
+	case config.Binding.IPv6Mask == 0:
+		config.Binding.IPv6Mask = schema.DefaultSessionBindingConfiguration.IPv6Mask
+	case config.Binding.IPv6Mask < 0 || config.Binding.IPv6Mask > 128:
+		validator.Push(fmt.Errorf(errFmtSessionBindingMask, "ipv6_mask", 128, config.Binding.IPv6Mask))
+	}
+
+	if config.Binding.AntiReplayRotationInterval <= 0 {
+		config.Binding.AntiReplayRotationInterval = schema.DefaultSessionBindingConfiguration.AntiReplayRotationInterval
+	}
+
+	if config.Binding.Policy == "" {
+		config.Binding.Policy = schema.DefaultSessionBindingConfiguration.Policy
+	} else if !utils.IsStringInSlice(config.Binding.Policy, validSessionBindingPolicies) {
+		validator.Push(fmt.Errorf(errFmtSessionBindingPolicy, strings.Join(validSessionBindingPolicies, "', '"), config.Binding.Policy))
+	}
+}
+
+func validateSessionCookies(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	var domains []string
+
+	if config.Domain != "" {
+		domains = append(domains, config.Domain)
+	}
+
+	for i := range config.Cookies {
+		cookie := &config.Cookies[i]
+
+		if cookie.Domain == "" {
+			validator.Push(fmt.Errorf(errFmtSessionCookiesDomainRequired, i+1))
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(cookie.Domain, "*."):
+			validator.Push(fmt.Errorf(errFmtSessionCookiesDomainMustBeRoot, i+1, cookie.Domain, cookie.Domain))
+		case utils.IsStringInSliceFold(cookie.Domain, domains):
+			validator.Push(fmt.Errorf(errFmtSessionCookiesDomainDuplicate, i+1, cookie.Domain))
+		}
+
+		domains = append(domains, cookie.Domain)
+
+		if cookie.Name == "" {
+			cookie.Name = config.Name
+		}
+
+		if cookie.SameSite == "" {
+			cookie.SameSite = config.SameSite
+		} else if !utils.IsStringInSlice(cookie.SameSite, validSessionSameSiteValues) {
+			validator.Push(fmt.Errorf(errFmtSessionCookiesSameSite, i+1, cookie.Domain, strings.Join(validSessionSameSiteValues, "', '"), cookie.SameSite))
+		}
+
+		if cookie.Expiration <= 0 {
+			cookie.Expiration = config.Expiration
+		}
+
+		if cookie.Inactivity <= 0 {
+			cookie.Inactivity = config.Inactivity
+		}
+
+		if cookie.RememberMeDuration <= 0 && cookie.RememberMeDuration != schema.RememberMeDisabled {
+			cookie.RememberMeDuration = config.RememberMeDuration
+		}
+
+		if cookie.AutheliaURL != nil {
+			switch {
+			case cookie.AutheliaURL.Scheme != "https":
+				validator.Push(fmt.Errorf(errFmtSessionCookiesAutheliaURLScheme, i+1, cookie.Domain, cookie.AutheliaURL.Scheme))
+			case !utils.IsURISafeRedirection(cookie.AutheliaURL, cookie.Domain):
+				validator.Push(fmt.Errorf(errFmtSessionCookiesAutheliaURLDomain, i+1, cookie.Domain, cookie.Domain))
+			}
+		}
+	}
+}
+
+func validateSessionSQL(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if config.Redis != nil {
+		validator.Push(fmt.Errorf(errFmtSessionSQLRedisConfigured))
+	}
+
+	if config.Secret == "" {
+		validator.Push(fmt.Errorf(errFmtSessionSecretRequired, "sql"))
+	}
+
+	if config.SQL.CleanupInterval <= 0 {
+		config.SQL.CleanupInterval = schema.DefaultSessionSQLConfiguration.CleanupInterval
+	}
 }
 
 func validateRedisCommon(config *schema.SessionConfiguration, validator *schema.StructValidator) {
@@ -123,3 +237,45 @@ func validateRedisSentinel(config *schema.SessionConfiguration, validator *schem
 		validator.Push(fmt.Errorf(errFmtSessionRedisSentinelNodeHostMissing))
 	}
 }
+
+func validateRedisCluster(config *schema.SessionConfiguration, validator *schema.StructValidator) {
+	if config.Redis.HighAvailability != nil {
+		validator.Push(fmt.Errorf(errFmtSessionRedisClusterHighAvailability))
+	}
+
+	if config.Redis.DatabaseIndex != 0 {
+		validator.Push(fmt.Errorf(errFmtSessionRedisClusterDatabaseIndex, config.Redis.DatabaseIndex))
+	}
+
+	if config.Redis.Host != "" && config.Redis.Port == 0 {
+		config.Redis.Port = 6379
+	} else if config.Redis.Port < 0 || config.Redis.Port > 65535 {
+		validator.Push(fmt.Errorf(errFmtSessionRedisPortRange, config.Redis.Port))
+	}
+
+	if config.Redis.Host == "" && len(config.Redis.Cluster.Nodes) == 0 {
+		validator.Push(fmt.Errorf(errFmtSessionRedisClusterHostOrNodesRequired))
+	}
+
+	validateRedisCommon(config, validator)
+
+	if config.Redis.MaximumActiveConnections <= 0 {
+		config.Redis.MaximumActiveConnections = 8
+	}
+
+	hostMissing := false
+
+	for i, node := range config.Redis.Cluster.Nodes {
+		if node.Host == "" {
+			hostMissing = true
+		}
+
+		if node.Port == 0 {
+			config.Redis.Cluster.Nodes[i].Port = 6379
+		}
+	}
+
+	if hostMissing {
+		validator.Push(fmt.Errorf(errFmtSessionRedisClusterNodeHostMissing))
+	}
+}
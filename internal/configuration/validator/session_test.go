@@ -3,7 +3,9 @@ package validator
 import (
 	"crypto/tls"
 	"fmt"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -49,6 +51,56 @@ func TestShouldSetDefaultSessionValuesWhenNegative(t *testing.T) {
 	assert.Equal(t, schema.DefaultSessionConfiguration.RememberMeDuration, config.RememberMeDuration)
 }
 
+func TestShouldRaiseErrorWhenPreviousSecretsContainsEmptyValue(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.PreviousSecrets = []string{"abc", ""}
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "session: option 'previous_secrets' must not contain empty values but value #2 is empty")
+}
+
+func TestShouldSetDefaultSessionBindingValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.Binding.IP = true
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+	assert.True(t, config.Binding.Enabled())
+	assert.Equal(t, 24, config.Binding.IPv4Mask)
+	assert.Equal(t, 64, config.Binding.IPv6Mask)
+	assert.Equal(t, time.Minute*5, config.Binding.AntiReplayRotationInterval)
+	assert.Equal(t, schema.SessionBindingPolicyReauthenticate, config.Binding.Policy)
+}
+
+func TestShouldRaiseErrorOnInvalidSessionBindingValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.Binding = schema.SessionBindingConfiguration{
+		IP:       true,
+		IPv4Mask: 33,
+		IPv6Mask: -1,
+		Policy:   "block",
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "session: binding: option 'ipv4_mask' must be between 1 and 32 but is configured as '33'")
+	assert.EqualError(t, validator.Errors()[1], "session: binding: option 'ipv6_mask' must be between 1 and 128 but is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[2], "session: binding: option 'policy' must be one of 'log', 'reauthenticate', 'destroy' but is configured as 'block'")
+}
+
 func TestShouldWarnSessionValuesWhenPotentiallyInvalid(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
@@ -144,6 +196,39 @@ func TestShouldRaiseErrorWhenRedisIsUsedAndSecretNotSet(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf(errFmtSessionSecretRequired, "redis"))
 }
 
+func TestShouldSetDefaultSessionSQLCleanupInterval(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.SQL.Enabled = true
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+	assert.Equal(t, schema.DefaultSessionSQLConfiguration.CleanupInterval, config.SQL.CleanupInterval)
+}
+
+func TestShouldRaiseErrorsWhenSessionSQLIncorrectlyConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Secret = ""
+	config.SQL = schema.SessionSQLConfiguration{Enabled: true, CleanupInterval: time.Minute}
+	config.Redis = &schema.RedisSessionConfiguration{
+		Host: "redis.localhost",
+		Port: 6379,
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 3)
+
+	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf(errFmtSessionSecretRequired, "redis"))
+	assert.EqualError(t, validator.Errors()[1], errFmtSessionSQLRedisConfigured)
+	assert.EqualError(t, validator.Errors()[2], fmt.Sprintf(errFmtSessionSecretRequired, "sql"))
+	assert.Equal(t, time.Minute, config.SQL.CleanupInterval)
+}
+
 func TestShouldRaiseErrorWhenRedisHasHostnameButNoPort(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
@@ -351,6 +436,79 @@ func TestShouldRaiseErrorWhenRedisHostAndHighAvailabilityNodesEmpty(t *testing.T
 	assert.EqualError(t, validator.Errors()[0], errFmtSessionRedisHostOrNodesRequired)
 }
 
+func TestShouldSetDefaultRedisClusterValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.Redis = &schema.RedisSessionConfiguration{
+		Host: "redis.example.com",
+		Cluster: &schema.RedisClusterConfiguration{
+			Nodes: []schema.RedisNode{
+				{
+					Host: "redis2.example.com",
+				},
+				{
+					Host: "redis3.example.com",
+					Port: 7000,
+				},
+			},
+		},
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	assert.False(t, validator.HasErrors())
+
+	assert.Equal(t, 6379, config.Redis.Port)
+	assert.Equal(t, 8, config.Redis.MaximumActiveConnections)
+	assert.Equal(t, 6379, config.Redis.Cluster.Nodes[0].Port)
+	assert.Equal(t, 7000, config.Redis.Cluster.Nodes[1].Port)
+}
+
+func TestShouldRaiseErrorsWhenRedisClusterOptionsIncorrectlyConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+
+	config.Redis = &schema.RedisSessionConfiguration{
+		Port:          -1,
+		DatabaseIndex: 2,
+		HighAvailability: &schema.RedisHighAvailabilityConfiguration{
+			SentinelName: "sentinel",
+		},
+		Cluster: &schema.RedisClusterConfiguration{
+			Nodes: []schema.RedisNode{
+				{
+					Port: 6379,
+				},
+			},
+		},
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 4)
+
+	assert.EqualError(t, validator.Errors()[0], errFmtSessionRedisClusterHighAvailability)
+	assert.EqualError(t, validator.Errors()[1], fmt.Sprintf(errFmtSessionRedisClusterDatabaseIndex, 2))
+	assert.EqualError(t, validator.Errors()[2], fmt.Sprintf(errFmtSessionRedisPortRange, -1))
+	assert.EqualError(t, validator.Errors()[3], errFmtSessionRedisClusterNodeHostMissing)
+
+	validator.Clear()
+
+	config.Redis = &schema.RedisSessionConfiguration{
+		Cluster: &schema.RedisClusterConfiguration{},
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.False(t, validator.HasWarnings())
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], errFmtSessionRedisClusterHostOrNodesRequired)
+}
+
 func TestShouldRaiseErrorsWhenRedisHostNotSet(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultSessionConfig()
@@ -509,3 +667,107 @@ func TestShouldSetDefaultRememberMeDuration(t *testing.T) {
 	assert.False(t, validator.HasErrors())
 	assert.Equal(t, config.RememberMeDuration, schema.DefaultSessionConfiguration.RememberMeDuration)
 }
+
+func TestShouldNotRaiseErrorWhenOnlyCookiesConfigured(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Domain = ""
+	config.Cookies = []schema.SessionCookieConfiguration{
+		{
+			Domain: examplecom,
+		},
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldSetDefaultSessionCookieValues(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultSessionConfig()
+	config.Cookies = []schema.SessionCookieConfiguration{
+		{
+			Domain: "example.org",
+		},
+		{
+			Domain:             "example.net",
+			Name:               "authelia_net",
+			SameSite:           "strict",
+			Expiration:         time.Hour * 2,
+			Inactivity:         time.Minute * 10,
+			RememberMeDuration: schema.RememberMeDisabled,
+		},
+	}
+
+	ValidateSession(&config, validator)
+
+	assert.Len(t, validator.Warnings(), 0)
+	assert.Len(t, validator.Errors(), 0)
+
+	assert.Equal(t, schema.DefaultSessionConfiguration.Name, config.Cookies[0].Name)
+	assert.Equal(t, schema.DefaultSessionConfiguration.SameSite, config.Cookies[0].SameSite)
+	assert.Equal(t, schema.DefaultSessionConfiguration.Expiration, config.Cookies[0].Expiration)
+	assert.Equal(t, schema.DefaultSessionConfiguration.Inactivity, config.Cookies[0].Inactivity)
+	assert.Equal(t, schema.DefaultSessionConfiguration.RememberMeDuration, config.Cookies[0].RememberMeDuration)
+
+	assert.Equal(t, "authelia_net", config.Cookies[1].Name)
+	assert.Equal(t, "strict", config.Cookies[1].SameSite)
+	assert.Equal(t, time.Hour*2, config.Cookies[1].Expiration)
+	assert.Equal(t, time.Minute*10, config.Cookies[1].Inactivity)
+	assert.Equal(t, schema.RememberMeDisabled, config.Cookies[1].RememberMeDuration)
+}
+
+func TestShouldRaiseErrorsWhenSessionCookiesInvalid(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     schema.SessionCookieConfiguration
+		expected string
+	}{
+		{
+			"ShouldRaiseErrorWhenDomainNotSet",
+			schema.SessionCookieConfiguration{},
+			"session: cookies: #1: option 'domain' is required",
+		},
+		{
+			"ShouldRaiseErrorWhenDomainIsWildcard",
+			schema.SessionCookieConfiguration{Domain: "*.example.org"},
+			"session: cookies: #1 (domain '*.example.org'): option 'domain' must be the domain you wish to protect not a wildcard domain but it is configured as '*.example.org'",
+		},
+		{
+			"ShouldRaiseErrorWhenDomainIsDuplicate",
+			schema.SessionCookieConfiguration{Domain: "Example.com"},
+			"session: cookies: #1 (domain 'Example.com'): option 'domain' is a duplicate value for another configured session cookie domain",
+		},
+		{
+			"ShouldRaiseErrorWhenSameSiteSetIncorrectly",
+			schema.SessionCookieConfiguration{Domain: "example.org", SameSite: "NOne"},
+			"session: cookies: #1 (domain 'example.org'): option 'same_site' must be one of 'none', 'lax', 'strict' but is configured as 'NOne'",
+		},
+		{
+			"ShouldRaiseErrorWhenAutheliaURLInsecure",
+			schema.SessionCookieConfiguration{Domain: "example.org", AutheliaURL: &url.URL{Scheme: "http", Host: "auth.example.org"}},
+			"session: cookies: #1 (domain 'example.org'): option 'authelia_url' must have the 'https' scheme but is configured as 'http'",
+		},
+		{
+			"ShouldRaiseErrorWhenAutheliaURLNotUnderDomain",
+			schema.SessionCookieConfiguration{Domain: "example.org", AutheliaURL: &url.URL{Scheme: "https", Host: "auth.example.net"}},
+			"session: cookies: #1 (domain 'example.org'): option 'authelia_url' does not share a cookie scope with the domain 'example.org'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultSessionConfig()
+			config.Cookies = []schema.SessionCookieConfiguration{tc.have}
+
+			ValidateSession(&config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+			require.Len(t, validator.Errors(), 1)
+			assert.EqualError(t, validator.Errors()[0], tc.expected)
+		})
+	}
+}
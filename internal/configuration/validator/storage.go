@@ -29,6 +29,15 @@ func ValidateStorage(config schema.StorageConfiguration, validator *schema.Struc
 	} else if len(config.EncryptionKey) < 20 {
 		validator.Push(errors.New(errStrStorageEncryptionKeyTooShort))
 	}
+
+	for i, key := range config.PreviousEncryptionKeys {
+		switch {
+		case len(key) < 20:
+			validator.Push(fmt.Errorf(errFmtStoragePreviousEncryptionKeyTooShort, i+1))
+		case key == config.EncryptionKey:
+			validator.Push(fmt.Errorf(errFmtStoragePreviousEncryptionKeyIsCurrent, i+1))
+		}
+	}
 }
 
 func validateSQLConfiguration(config *schema.SQLStorageConfiguration, validator *schema.StructValidator, provider string) {
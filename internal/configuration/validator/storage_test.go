@@ -18,6 +18,7 @@ type StorageSuite struct {
 func (suite *StorageSuite) SetupTest() {
 	suite.validator = schema.NewStructValidator()
 	suite.config.EncryptionKey = testEncryptionKey
+	suite.config.PreviousEncryptionKeys = nil
 	suite.config.Local = nil
 	suite.config.PostgreSQL = nil
 	suite.config.MySQL = nil
@@ -389,6 +390,21 @@ func (suite *StorageSuite) TestShouldRaiseErrorOnShortEncryptionKey() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "storage: option 'encryption_key' must be 20 characters or longer")
 }
 
+func (suite *StorageSuite) TestShouldRaiseErrorOnInvalidPreviousEncryptionKeys() {
+	suite.config.EncryptionKey = "this is a really long key"
+	suite.config.PreviousEncryptionKeys = []string{"abc", "this is a really long key", "this is another really long key"}
+	suite.config.Local = &schema.LocalStorageConfiguration{
+		Path: "/this/is/a/path",
+	}
+
+	ValidateStorage(suite.config, suite.validator)
+
+	suite.Require().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+	suite.Assert().EqualError(suite.validator.Errors()[0], "storage: option 'previous_encryption_keys' must only contain values 20 characters or longer but value #1 is shorter")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "storage: option 'previous_encryption_keys' must not contain the value of option 'encryption_key' but value #2 is the same")
+}
+
 func TestShouldRunStorageSuite(t *testing.T) {
 	suite.Run(t, new(StorageSuite))
 }
@@ -42,4 +42,6 @@ func ValidateTelemetry(config *schema.Configuration, validator *schema.StructVal
 	if config.Telemetry.Metrics.Timeouts.Idle <= 0 {
 		config.Telemetry.Metrics.Timeouts.Idle = schema.DefaultTelemetryConfig.Metrics.Timeouts.Idle
 	}
+
+	validateProxyProtocol("telemetry: metrics", &config.Telemetry.Metrics.ProxyProtocol, validator)
 }
@@ -60,6 +60,13 @@ func TestValidateTelemetry(t *testing.T) {
 			nil,
 			[]string{"telemetry: metrics: option 'address' must have a scheme 'tcp://' but it is configured as 'udp'"},
 		},
+		{
+			"ShouldRaiseErrorProxyProtocolWithoutTrustedSources",
+			&schema.Configuration{Telemetry: schema.TelemetryConfig{Metrics: schema.TelemetryMetricsConfig{ProxyProtocol: schema.ServerProxyProtocol{Enabled: true}}}},
+			&schema.Configuration{Telemetry: schema.DefaultTelemetryConfig},
+			nil,
+			[]string{"telemetry: metrics: proxy_protocol: option 'trusted_sources' is required when the PROXY protocol is enabled"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -20,6 +20,8 @@ const (
 var (
 	headerAuthorization      = []byte(fasthttp.HeaderAuthorization)
 	headerProxyAuthorization = []byte(fasthttp.HeaderProxyAuthorization)
+	headerWWWAuthenticate    = []byte(fasthttp.HeaderWWWAuthenticate)
+	headerProxyAuthenticate  = []byte(fasthttp.HeaderProxyAuthenticate)
 
 	headerSessionUsername = []byte("Session-Username")
 	headerRemoteUser      = []byte("Remote-User")
@@ -28,17 +30,65 @@ var (
 	headerRemoteEmail     = []byte("Remote-Email")
 )
 
+const (
+	claimAuthenticationLevel = "auth_level"
+)
+
+var (
+	headerValueAuthenticateBasic              = []byte(`Basic realm="Authentication required"`)
+	headerValueAuthenticateBearerInvalidToken = []byte(`Bearer error="invalid_token"`)
+
+	headerValueAuthenticateBearerInsufficientScope = []byte(`Bearer error="insufficient_scope"`)
+)
+
+const (
+	prefixAuthorizationBearer = "Bearer "
+)
+
+const (
+	headerValueContentTypeJSON = "application/json;charset=UTF-8"
+	headerValueNoStore         = "no-store"
+	headerValueNoCache         = "no-cache"
+)
+
 const (
 	queryArgRD         = "rd"
+	queryArgRM         = "rm"
 	queryArgID         = "id"
 	queryArgConsentID  = "consent_id"
+	queryArgType       = "type"
 	queryArgWorkflow   = "workflow"
 	queryArgWorkflowID = "workflow_id"
+	queryArgStatus     = "status"
+	queryArgLoginHint  = "login_hint"
+)
+
+const (
+	oidcConsentTypePreConfiguration = "pre-configuration"
+	oidcConsentTypeSession          = "session"
+)
+
+const (
+	deviceStatusAuthorized = "authorized"
+	deviceStatusDenied     = "denied"
 )
 
 var (
 	qryArgID        = []byte(queryArgID)
 	qryArgConsentID = []byte(queryArgConsentID)
+	qryArgType      = []byte(queryArgType)
+)
+
+const (
+	userValueKeySessionID = "session_id"
+	userValueKeyUsername  = "username"
+	userValueKeyClientID  = "client_id"
+	userValueKeyConsentID = "consent_id"
+)
+
+const (
+	// activeSessionActivityInterval is the minimum interval between updates of the last activity of an active session.
+	activeSessionActivityInterval = time.Minute
 )
 
 const (
@@ -58,6 +108,7 @@ const (
 	messageUnableToResetPassword           = "Unable to reset your password."
 	messageMFAValidationFailed             = "Authentication failed, please retry later."
 	messagePasswordWeak                    = "Your supplied password does not meet the password policy requirements"
+	messageDeviceUserCodeInvalid           = "The code is invalid or has expired."
 )
 
 const (
@@ -75,11 +126,21 @@ const (
 	logFmtTraceProfileDetails     = "Profile details for user '%s' => groups: %s, emails %s"
 )
 
+const (
+	logFmtAuthzUnauthorized = "Access to '%s' (method %s) is not authorized to user '%s', responding with status code %d"
+	logFmtAuthzRedirect     = logFmtAuthzUnauthorized + " with location redirect to '%s'"
+)
+
 const (
 	logFmtAuthorizationPrefix = "Authorization Request with id '%s' on client with id '%s' "
 
 	logFmtErrConsentCantDetermineConsentMode = logFmtAuthorizationPrefix + "could not be processed: error occurred generating consent: client consent mode could not be reliably determined"
 
+	logFmtDbgPromptNone         = logFmtAuthorizationPrefix + "could not be processed: %s and the 'prompt' parameter was set to 'none', responding with the '%s' error"
+	logFmtDbgPromptLogin        = logFmtAuthorizationPrefix + "requires user '%s' to authenticate again, proceeding to reset the authentication of the session"
+	logFmtErrPromptAuthTime     = logFmtAuthorizationPrefix + "could not be processed: error occurred checking authentication time: %+v"
+	logFmtErrPromptResetSession = logFmtAuthorizationPrefix + "could not be processed: error occurred resetting the authentication of the session for user '%s': %+v"
+
 	logFmtConsentPrefix = logFmtAuthorizationPrefix + "using consent mode '%s' "
 
 	logFmtErrConsentParseChallengeID = logFmtConsentPrefix + "could not be processed: error occurred parsing the consent id (challenge) '%s': %+v"
@@ -99,7 +160,7 @@ const (
 	logFmtErrConsentWithIDCouldNotBeProcessed = logFmtConsentPrefix + "could not be processed: error occurred performing consent for consent session with id '%s': "
 
 	logFmtErrConsentLookupLoadingSession        = logFmtErrConsentWithIDCouldNotBeProcessed + "error occurred while loading session: %+v"
-	logFmtErrConsentSessionSubjectNotAuthorized = logFmtErrConsentWithIDCouldNotBeProcessed + "user '%s' with subject '%s' is not authorized to consent for subject '%s'"
+	logFmtErrConsentSessionSubjectNotAuthorized = logFmtErrConsentWithIDCouldNotBeProcessed + "user '%s' is not authorized to consent: %+v"
 	logFmtErrConsentCantGrant                   = logFmtErrConsentWithIDCouldNotBeProcessed + "the session does not appear to be valid for %s consent: either the subject is null, the consent has already been granted, or the consent session is a pre-configured session"
 	logFmtErrConsentCantGrantPreConf            = logFmtErrConsentWithIDCouldNotBeProcessed + "the session does not appear to be valid for pre-configured consent: either the subject is null, the consent has been granted and is either not pre-configured, or the pre-configuration is expired"
 	logFmtErrConsentCantGrantRejected           = logFmtErrConsentWithIDCouldNotBeProcessed + "the user explicitly rejected this consent session"
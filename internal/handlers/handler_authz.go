@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// Handler is the middlewares.RequestHandler for Authz.
+func (authz *Authz) Handler(ctx *middlewares.AutheliaCtx) {
+	var (
+		object      authorization.Object
+		autheliaURL *url.URL
+		err         error
+	)
+
+	if object, err = authz.handleGetObject(ctx); err != nil {
+		ctx.Logger.Errorf("Error getting the Target URL and Request Method for the %s implementation: %+v", authz.implementation, err)
+
+		ctx.ReplyUnauthorized()
+
+		return
+	}
+
+	if !utils.IsURISecure(object.URL) {
+		ctx.Logger.Errorf("Target URL '%s' has an insecure scheme '%s', only the 'https' and 'wss' schemes are supported so session cookies can be transmitted securely", object.URL.String(), object.URL.Scheme)
+
+		ctx.ReplyUnauthorized()
+
+		return
+	}
+
+	cookieDomain := ctx.GetCookieDomainFromTargetURI(object.URL)
+
+	if cookieDomain == "" {
+		ctx.Logger.Errorf("Target URL '%s' does not appear to be a protected domain as it is not under any of the configured session cookie domains", object.URL.String())
+
+		ctx.ReplyUnauthorized()
+
+		return
+	}
+
+	ctx.SetSessionCookieDomain(cookieDomain)
+
+	if autheliaURL, err = authz.handleGetAutheliaURL(ctx); err != nil {
+		ctx.Logger.Errorf("Target URL '%s' does not appear to be configured with a valid Authelia Portal URL: %+v", object.URL.String(), err)
+
+		ctx.ReplyUnauthorized()
+
+		return
+	}
+
+	if autheliaURL == nil {
+		autheliaURL = getSessionCookieAutheliaURL(ctx)
+	}
+
+	var (
+		authn    Authn
+		strategy AuthnStrategy
+	)
+
+	if authn, strategy, err = authz.authn(ctx, &object); err != nil {
+		authn.Object = object
+
+		ctx.Logger.Errorf("Target URL '%s' could not be authorized because an error occurred authenticating the request: %+v", object.URL.String(), err)
+
+		if strategy != nil && strategy.CanHandleUnauthorized() {
+			strategy.HandleUnauthorized(ctx, &authn, authz.getRedirectionURL(&object, autheliaURL))
+
+			return
+		}
+
+		ctx.ReplyUnauthorized()
+
+		return
+	}
+
+	authn.Object = object
+
+	switch authz.getAuthzResult(ctx, &authn, &object) {
+	case AuthzResultForbidden:
+		ctx.Logger.Infof("Access to '%s' (method %s) is forbidden to user '%s'", object.URL.String(), object.Method, friendlyAuthnIdentity(&authn))
+		ctx.ReplyForbidden()
+	case AuthzResultUnauthorized:
+		handler := authz.handleUnauthorized
+
+		if strategy != nil && strategy.CanHandleUnauthorized() {
+			handler = strategy.HandleUnauthorized
+		}
+
+		handler(ctx, &authn, authz.getRedirectionURL(&object, autheliaURL))
+	case AuthzResultAuthorized:
+		authz.handleAuthorized(ctx, &authn)
+	}
+
+	if authn.Type == AuthnTypeCookie {
+		if err = updateActivityTimestamp(ctx, false); err != nil {
+			ctx.Logger.Errorf("Unable to update the last activity of user '%s': %+v", authn.Details.Username, err)
+		}
+	}
+}
+
+func (authz *Authz) authn(ctx *middlewares.AutheliaCtx, object *authorization.Object) (authn Authn, strategy AuthnStrategy, err error) {
+	for _, strategy = range authz.strategies {
+		if authn, err = strategy.Get(ctx, object); err != nil {
+			return authn, strategy, err
+		}
+
+		if authn.Type != AuthnTypeNone {
+			return authn, strategy, nil
+		}
+	}
+
+	return Authn{Type: AuthnTypeNone, Level: authentication.NotAuthenticated}, nil, nil
+}
+
+func (authz *Authz) getAuthzResult(ctx *middlewares.AutheliaCtx, authn *Authn, object *authorization.Object) AuthzResult {
+	hasSubject, required := ctx.Providers.Authorizer.GetRequiredLevel(
+		authorization.Subject{
+			Username: authn.Details.Username,
+			Groups:   authn.Details.Groups,
+			ClientID: authn.ClientID,
+			IP:       ctx.RemoteIP(),
+		},
+		*object,
+	)
+
+	identity := authn.Details.Username
+
+	if identity == "" {
+		identity = authn.ClientID
+	}
+
+	return isAuthzResult(identity, hasSubject, required, authn.Level)
+}
+
+func (authz *Authz) getRedirectionURL(object *authorization.Object, autheliaURL *url.URL) (redirectionURL *url.URL) {
+	if autheliaURL == nil {
+		return nil
+	}
+
+	redirectionURL, _ = url.ParseRequestURI(autheliaURL.String())
+
+	qry := redirectionURL.Query()
+
+	qry.Set(queryArgRD, object.URL.String())
+
+	if object.Method != "" {
+		qry.Set(queryArgRM, object.Method)
+	}
+
+	redirectionURL.RawQuery = qry.Encode()
+
+	return redirectionURL
+}
+
+func isAuthzResult(username string, hasSubject bool, required authorization.Level, level authentication.Level) AuthzResult {
+	switch {
+	case required == authorization.Bypass:
+		return AuthzResultAuthorized
+	case required == authorization.Denied && (username != "" || !hasSubject):
+		// If the user is not anonymous, it means that we went through all the rules related to that user identity and
+		// can safely conclude their access is actually forbidden. If a user is anonymous however this is not actually
+		// possible without some more advanced logic.
+		return AuthzResultForbidden
+	case required == authorization.OneFactor && level >= authentication.OneFactor,
+		required == authorization.TwoFactor && level >= authentication.TwoFactor:
+		return AuthzResultAuthorized
+	default:
+		return AuthzResultUnauthorized
+	}
+}
+
+func handleAuthzPortalURLFromQuery(ctx *middlewares.AutheliaCtx) (portalURL *url.URL, err error) {
+	rawURL := ctx.QueryArgRedirect()
+
+	if len(rawURL) == 0 {
+		return nil, nil
+	}
+
+	return handleAuthzParsePortalURL(ctx, rawURL)
+}
+
+func handleAuthzPortalURLFromHeader(ctx *middlewares.AutheliaCtx) (portalURL *url.URL, err error) {
+	rawURL := ctx.XAutheliaURL()
+
+	if len(rawURL) == 0 {
+		return nil, nil
+	}
+
+	return handleAuthzParsePortalURL(ctx, rawURL)
+}
+
+func handleAuthzParsePortalURL(ctx *middlewares.AutheliaCtx, rawURL []byte) (portalURL *url.URL, err error) {
+	if portalURL, err = url.ParseRequestURI(string(rawURL)); err != nil {
+		return nil, fmt.Errorf("failed to parse the portal url '%s': %w", rawURL, err)
+	}
+
+	var provider *session.Session
+
+	if provider, err = ctx.GetSessionProvider(); err != nil {
+		return nil, fmt.Errorf("failed to get the session provider for the portal url '%s': %w", portalURL, err)
+	}
+
+	if !utils.IsURISafeRedirection(portalURL, provider.Config.Domain) {
+		return nil, fmt.Errorf("the portal url '%s' does not appear to be able to write cookies for the '%s' domain", portalURL, provider.Config.Domain)
+	}
+
+	return portalURL, nil
+}
+
+// getSessionCookieAutheliaURL returns the Authelia URL configured for the session cookie of the request if any.
+func getSessionCookieAutheliaURL(ctx *middlewares.AutheliaCtx) (autheliaURL *url.URL) {
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return nil
+	}
+
+	return provider.Config.AutheliaURL
+}
+
+func handleAuthzAuthorizedStandard(ctx *middlewares.AutheliaCtx, authn *Authn) {
+	ctx.ReplyStatusCode(fasthttp.StatusOK)
+
+	setAuthzHeaders(ctx, authn)
+}
+
+func friendlyAuthnIdentity(authn *Authn) string {
+	if authn.ClientID != "" {
+		return "oauth2:client:" + authn.ClientID
+	}
+
+	return friendlyUsername(authn.Details.Username)
+}
+
+func friendlyUsername(username string) string {
+	if username == "" {
+		return "<anonymous>"
+	}
+
+	return username
+}
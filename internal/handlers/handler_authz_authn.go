@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"net/url"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+// NewCookieSessionAuthnStrategy creates a new CookieSessionAuthnStrategy.
+func NewCookieSessionAuthnStrategy(refresh bool, refreshInterval time.Duration) *CookieSessionAuthnStrategy {
+	return &CookieSessionAuthnStrategy{
+		refresh:         refresh,
+		refreshInterval: refreshInterval,
+	}
+}
+
+// NewHeaderAuthorizationAuthnStrategy creates a new HeaderAuthnStrategy using the Authorization and WWW-Authenticate
+// headers, and the 401 Unauthorized status code.
+func NewHeaderAuthorizationAuthnStrategy() *HeaderAuthnStrategy {
+	return &HeaderAuthnStrategy{
+		authn:              AuthnTypeAuthorization,
+		headerAuthorize:    headerAuthorization,
+		headerAuthenticate: headerWWWAuthenticate,
+		statusAuthenticate: fasthttp.StatusUnauthorized,
+	}
+}
+
+// NewHeaderProxyAuthorizationAuthnStrategy creates a new HeaderAuthnStrategy using the Proxy-Authorization and
+// Proxy-Authenticate headers, and the 407 Proxy Authentication Required status code.
+func NewHeaderProxyAuthorizationAuthnStrategy() *HeaderAuthnStrategy {
+	return &HeaderAuthnStrategy{
+		authn:              AuthnTypeProxyAuthorization,
+		headerAuthorize:    headerProxyAuthorization,
+		headerAuthenticate: headerProxyAuthenticate,
+		statusAuthenticate: fasthttp.StatusProxyAuthRequired,
+	}
+}
+
+// NewHeaderProxyAuthorizationAuthRequestAuthnStrategy creates a new HeaderAuthnStrategy using the Proxy-Authorization
+// and WWW-Authenticate headers, and the 401 Unauthorized status code. It's intended to be used with the AuthRequest
+// implementation as those proxies only understand the 2xx, 401, and 403 status codes.
+func NewHeaderProxyAuthorizationAuthRequestAuthnStrategy() *HeaderAuthnStrategy {
+	return &HeaderAuthnStrategy{
+		authn:              AuthnTypeProxyAuthorization,
+		headerAuthorize:    headerProxyAuthorization,
+		headerAuthenticate: headerWWWAuthenticate,
+		statusAuthenticate: fasthttp.StatusUnauthorized,
+	}
+}
+
+// CookieSessionAuthnStrategy is a session cookie AuthnStrategy.
+type CookieSessionAuthnStrategy struct {
+	refresh         bool
+	refreshInterval time.Duration
+}
+
+// Get returns the Authn information for this AuthnStrategy.
+func (s *CookieSessionAuthnStrategy) Get(ctx *middlewares.AutheliaCtx, object *authorization.Object) (authn Authn, err error) {
+	authn = Authn{
+		Type:  AuthnTypeCookie,
+		Level: authentication.NotAuthenticated,
+	}
+
+	userSession := ctx.GetSession()
+
+	var (
+		username, name string
+		groups, emails []string
+		level          authentication.Level
+	)
+
+	if username, name, groups, emails, level, err = verifySessionCookie(ctx, object.URL, &userSession, s.refresh, s.refreshInterval); err != nil {
+		return authn, err
+	}
+
+	authn.Details = authentication.UserDetails{
+		Username:    username,
+		DisplayName: name,
+		Groups:      groups,
+		Emails:      emails,
+	}
+
+	authn.Level = level
+	authn.AuthenticationMethodRefs = userSession.AuthenticationMethodRefs
+	authn.AuthenticatedAt = getSessionAuthenticatedAt(&userSession)
+
+	return authn, nil
+}
+
+// CanHandleUnauthorized returns true if this AuthnStrategy should handle Unauthorized requests.
+func (s *CookieSessionAuthnStrategy) CanHandleUnauthorized() (handle bool) {
+	return false
+}
+
+// HandleUnauthorized is the Unauthorized handler for the cookie AuthnStrategy.
+func (s *CookieSessionAuthnStrategy) HandleUnauthorized(_ *middlewares.AutheliaCtx, _ *Authn, _ *url.URL) {
+}
+
+// HeaderAuthnStrategy is a header AuthnStrategy.
+type HeaderAuthnStrategy struct {
+	authn              AuthnType
+	headerAuthorize    []byte
+	headerAuthenticate []byte
+	statusAuthenticate int
+}
+
+// Get returns the Authn information for this AuthnStrategy. If the header is absent it returns an Authn with the
+// AuthnTypeNone type so the next AuthnStrategy is tried. Bearer tokens issued to OAuth 2.0 clients via the client
+// credentials grant are accepted in addition to the Basic scheme.
+func (s *HeaderAuthnStrategy) Get(ctx *middlewares.AutheliaCtx, object *authorization.Object) (authn Authn, err error) {
+	value := ctx.Request.Header.PeekBytes(s.headerAuthorize)
+
+	if len(value) == 0 {
+		return Authn{Type: AuthnTypeNone, Level: authentication.NotAuthenticated}, nil
+	}
+
+	authn = Authn{
+		Type:  s.authn,
+		Level: authentication.NotAuthenticated,
+	}
+
+	if bytes.HasPrefix(value, []byte(prefixAuthorizationBearer)) {
+		if authn.ClientID, err = verifyBearerAuth(ctx, s.headerAuthorize, value, object); err != nil {
+			return authn, err
+		}
+
+		authn.Level = authentication.OneFactor
+		authn.AuthenticatedAt = ctx.Clock.Now()
+
+		return authn, nil
+	}
+
+	var (
+		username, name string
+		groups, emails []string
+		level          authentication.Level
+	)
+
+	if username, name, groups, emails, level, err = verifyBasicAuth(ctx, s.headerAuthorize, value); err != nil {
+		return authn, err
+	}
+
+	authn.Details = authentication.UserDetails{
+		Username:    username,
+		DisplayName: name,
+		Groups:      groups,
+		Emails:      emails,
+	}
+
+	authn.Level = level
+	authn.AuthenticationMethodRefs = oidc.AuthenticationMethodsReferences{UsernameAndPassword: true}
+	authn.AuthenticatedAt = ctx.Clock.Now()
+
+	return authn, nil
+}
+
+// CanHandleUnauthorized returns true if this AuthnStrategy should handle Unauthorized requests.
+func (s *HeaderAuthnStrategy) CanHandleUnauthorized() (handle bool) {
+	return true
+}
+
+// HandleUnauthorized is the Unauthorized handler for the header AuthnStrategy.
+func (s *HeaderAuthnStrategy) HandleUnauthorized(ctx *middlewares.AutheliaCtx, authn *Authn, _ *url.URL) {
+	ctx.Logger.Infof(logFmtAuthzUnauthorized, authn.Object.URL.String(), authn.Object.Method, friendlyAuthnIdentity(authn), s.statusAuthenticate)
+
+	ctx.ReplyStatusCode(s.statusAuthenticate)
+
+	switch {
+	case !bytes.HasPrefix(ctx.Request.Header.PeekBytes(s.headerAuthorize), []byte(prefixAuthorizationBearer)):
+		ctx.Response.Header.SetBytesKV(s.headerAuthenticate, headerValueAuthenticateBasic)
+	case authn.ClientID == "":
+		ctx.Response.Header.SetBytesKV(s.headerAuthenticate, headerValueAuthenticateBearerInvalidToken)
+	default:
+		ctx.Response.Header.SetBytesKV(s.headerAuthenticate, headerValueAuthenticateBearerInsufficientScope)
+	}
+}
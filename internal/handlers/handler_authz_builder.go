@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// NewAuthzBuilder creates a new AuthzBuilder.
+func NewAuthzBuilder() *AuthzBuilder {
+	return &AuthzBuilder{
+		config: AuthzConfig{RefreshProfile: true, RefreshProfileInterval: schema.RefreshIntervalAlways},
+	}
+}
+
+// WithStrategies replaces all of the strategies with the provided value.
+func (b *AuthzBuilder) WithStrategies(strategies ...AuthnStrategy) *AuthzBuilder {
+	b.strategies = strategies
+
+	return b
+}
+
+// WithImplementationForwardAuth configures this builder to output an Authz which is used with the ForwardAuth
+// implementation traditionally used by Traefik, Caddy, and Skipper.
+func (b *AuthzBuilder) WithImplementationForwardAuth() *AuthzBuilder {
+	b.impl = AuthzImplForwardAuth
+
+	return b
+}
+
+// WithImplementationAuthRequest configures this builder to output an Authz which is used with the AuthRequest
+// implementation traditionally used by NGINX.
+func (b *AuthzBuilder) WithImplementationAuthRequest() *AuthzBuilder {
+	b.impl = AuthzImplAuthRequest
+
+	return b
+}
+
+// WithImplementationExtAuthz configures this builder to output an Authz which is used with the ExtAuthz
+// implementation traditionally used by Envoy.
+func (b *AuthzBuilder) WithImplementationExtAuthz() *AuthzBuilder {
+	b.impl = AuthzImplExtAuthz
+
+	return b
+}
+
+// WithConfig allows configuring the refresh interval using the *schema.Configuration type.
+func (b *AuthzBuilder) WithConfig(config *schema.Configuration) *AuthzBuilder {
+	if config == nil {
+		return b
+	}
+
+	b.config.RefreshProfile, b.config.RefreshProfileInterval = getProfileRefreshSettings(config.AuthenticationBackend)
+
+	return b
+}
+
+// WithEndpointConfig configures the AuthzBuilder with a *schema.ServerAuthzEndpoint.
+func (b *AuthzBuilder) WithEndpointConfig(config schema.ServerAuthzEndpoint) *AuthzBuilder {
+	switch config.Implementation {
+	case schema.AuthzImplementationAuthRequest:
+		b.WithImplementationAuthRequest()
+	case schema.AuthzImplementationExtAuthz:
+		b.WithImplementationExtAuthz()
+	default:
+		b.WithImplementationForwardAuth()
+	}
+
+	b.WithStrategies()
+
+	for _, strategy := range config.AuthnStrategies {
+		switch strategy.Name {
+		case schema.AuthzStrategyHeaderCookieSession:
+			b.strategies = append(b.strategies, NewCookieSessionAuthnStrategy(b.config.RefreshProfile, b.config.RefreshProfileInterval))
+		case schema.AuthzStrategyHeaderAuthorization:
+			b.strategies = append(b.strategies, NewHeaderAuthorizationAuthnStrategy())
+		case schema.AuthzStrategyHeaderProxyAuthorization:
+			b.strategies = append(b.strategies, NewHeaderProxyAuthorizationAuthnStrategy())
+		case schema.AuthzStrategyHeaderAuthRequestProxyAuthorization:
+			b.strategies = append(b.strategies, NewHeaderProxyAuthorizationAuthRequestAuthnStrategy())
+		}
+	}
+
+	return b
+}
+
+// Build returns a new Authz from the currently configured options in this builder.
+func (b *AuthzBuilder) Build() (authz *Authz) {
+	authz = &Authz{
+		config:               b.config,
+		strategies:           b.strategies,
+		handleGetAutheliaURL: handleAuthzPortalURLFromQuery,
+		handleAuthorized:     handleAuthzAuthorizedStandard,
+	}
+
+	if len(authz.strategies) == 0 {
+		switch b.impl {
+		case AuthzImplAuthRequest:
+			authz.strategies = []AuthnStrategy{NewHeaderProxyAuthorizationAuthRequestAuthnStrategy(), NewCookieSessionAuthnStrategy(b.config.RefreshProfile, b.config.RefreshProfileInterval)}
+		default:
+			authz.strategies = []AuthnStrategy{NewHeaderProxyAuthorizationAuthnStrategy(), NewCookieSessionAuthnStrategy(b.config.RefreshProfile, b.config.RefreshProfileInterval)}
+		}
+	}
+
+	switch b.impl {
+	case AuthzImplAuthRequest:
+		authz.handleGetObject = handleAuthzGetObjectAuthRequest
+		authz.handleUnauthorized = handleAuthzUnauthorizedAuthRequest
+	case AuthzImplExtAuthz:
+		authz.handleGetObject = handleAuthzGetObjectExtAuthz
+		authz.handleGetAutheliaURL = handleAuthzPortalURLFromHeader
+		authz.handleUnauthorized = handleAuthzUnauthorizedExtAuthz
+	default:
+		authz.handleGetObject = handleAuthzGetObjectForwardAuth
+		authz.handleUnauthorized = handleAuthzUnauthorizedForwardAuth
+	}
+
+	authz.implementation = b.impl
+
+	return authz
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+)
+
+func handleAuthzGetObjectForwardAuth(ctx *middlewares.AutheliaCtx) (object authorization.Object, err error) {
+	var targetURL *url.URL
+
+	if targetURL, err = ctx.GetXForwardedURL(); err != nil {
+		return object, fmt.Errorf("failed to get target URL: %w", err)
+	}
+
+	return authorization.NewObjectRaw(targetURL, ctx.XForwardedMethod()), nil
+}
+
+func handleAuthzGetObjectAuthRequest(ctx *middlewares.AutheliaCtx) (object authorization.Object, err error) {
+	var targetURL *url.URL
+
+	if targetURL, err = ctx.GetXOriginalURL(); err != nil {
+		return object, fmt.Errorf("failed to get target URL: %w", err)
+	}
+
+	return authorization.NewObjectRaw(targetURL, ctx.XOriginalMethod()), nil
+}
+
+func handleAuthzGetObjectExtAuthz(ctx *middlewares.AutheliaCtx) (object authorization.Object, err error) {
+	var targetURL *url.URL
+
+	if targetURL, err = ctx.GetXForwardedProtoHostAuthzPathURL(); err != nil {
+		return object, fmt.Errorf("failed to get target URL: %w", err)
+	}
+
+	method := ctx.XForwardedMethod()
+
+	if len(method) == 0 {
+		method = ctx.Method()
+	}
+
+	return authorization.NewObjectRaw(targetURL, method), nil
+}
+
+// handleAuthzUnauthorizedForwardAuth responds with a redirect to the portal for browsers and 401 Unauthorized for
+// other clients.
+func handleAuthzUnauthorizedForwardAuth(ctx *middlewares.AutheliaCtx, authn *Authn, redirectionURL *url.URL) {
+	handleAuthzUnauthorizedRedirect(ctx, authn, redirectionURL)
+}
+
+// handleAuthzUnauthorizedExtAuthz responds with a redirect to the portal for browsers and 401 Unauthorized for other
+// clients.
+func handleAuthzUnauthorizedExtAuthz(ctx *middlewares.AutheliaCtx, authn *Authn, redirectionURL *url.URL) {
+	handleAuthzUnauthorizedRedirect(ctx, authn, redirectionURL)
+}
+
+// handleAuthzUnauthorizedAuthRequest always responds with 401 Unauthorized as the AuthRequest proxies only understand
+// the 2xx, 401, and 403 status codes. The Location header is included so the proxy can perform the redirection.
+func handleAuthzUnauthorizedAuthRequest(ctx *middlewares.AutheliaCtx, authn *Authn, redirectionURL *url.URL) {
+	ctx.ReplyUnauthorized()
+
+	if redirectionURL == nil {
+		ctx.Logger.Infof(logFmtAuthzUnauthorized, authn.Object.URL.String(), authn.Object.Method, friendlyUsername(authn.Details.Username), fasthttp.StatusUnauthorized)
+
+		return
+	}
+
+	ctx.Logger.Infof(logFmtAuthzRedirect, authn.Object.URL.String(), authn.Object.Method, friendlyUsername(authn.Details.Username), fasthttp.StatusUnauthorized, redirectionURL)
+
+	ctx.Response.Header.Set(fasthttp.HeaderLocation, redirectionURL.String())
+}
+
+func handleAuthzUnauthorizedRedirect(ctx *middlewares.AutheliaCtx, authn *Authn, redirectionURL *url.URL) {
+	var statusCode int
+
+	switch {
+	case redirectionURL == nil || ctx.IsXHR() || !ctx.AcceptsMIME("text/html"):
+		statusCode = fasthttp.StatusUnauthorized
+	default:
+		switch authn.Object.Method {
+		case fasthttp.MethodGet, fasthttp.MethodOptions, fasthttp.MethodHead, "":
+			statusCode = fasthttp.StatusFound
+		default:
+			statusCode = fasthttp.StatusSeeOther
+		}
+	}
+
+	if redirectionURL == nil {
+		ctx.Logger.Infof(logFmtAuthzUnauthorized, authn.Object.URL.String(), authn.Object.Method, friendlyUsername(authn.Details.Username), statusCode)
+
+		ctx.ReplyUnauthorized()
+
+		return
+	}
+
+	ctx.Logger.Infof(logFmtAuthzRedirect, authn.Object.URL.String(), authn.Object.Method, friendlyUsername(authn.Details.Username), statusCode, redirectionURL)
+
+	ctx.SpecialRedirect(redirectionURL.String(), statusCode)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+// Authz is effectively a middlewares.RequestHandler for authorization requests from a specific proxy
+// implementation. It should be constructed using the AuthzBuilder. The Legacy implementation is handled by VerifyGET.
+type Authz struct {
+	config AuthzConfig
+
+	strategies []AuthnStrategy
+
+	handleGetObject      HandlerAuthzGetObject
+	handleGetAutheliaURL HandlerAuthzGetAutheliaURL
+
+	handleAuthorized   HandlerAuthzAuthorized
+	handleUnauthorized HandlerAuthzUnauthorized
+
+	implementation AuthzImplementation
+}
+
+// AuthzConfig represents the configuration elements of the Authz type.
+type AuthzConfig struct {
+	RefreshProfile         bool
+	RefreshProfileInterval time.Duration
+}
+
+// AuthzBuilder is a builder pattern for the Authz type.
+type AuthzBuilder struct {
+	config     AuthzConfig
+	impl       AuthzImplementation
+	strategies []AuthnStrategy
+}
+
+// AuthnStrategy is a strategy used for Authz authentication.
+type AuthnStrategy interface {
+	Get(ctx *middlewares.AutheliaCtx, object *authorization.Object) (authn Authn, err error)
+	CanHandleUnauthorized() (handle bool)
+	HandleUnauthorized(ctx *middlewares.AutheliaCtx, authn *Authn, redirectionURL *url.URL)
+}
+
+// AuthzResult is a result for Authz response handling determination.
+type AuthzResult int
+
+const (
+	// AuthzResultForbidden means the user is forbidden the access to a resource.
+	AuthzResultForbidden AuthzResult = iota
+
+	// AuthzResultUnauthorized means the user can access the resource with more permissions.
+	AuthzResultUnauthorized
+
+	// AuthzResultAuthorized means the user is authorized given their current permissions.
+	AuthzResultAuthorized
+)
+
+// AuthzImplementation represents an Authz implementation.
+type AuthzImplementation int
+
+const (
+	// AuthzImplForwardAuth is the Forward Auth Authz implementation which is used by Caddy, Skipper, and Traefik.
+	AuthzImplForwardAuth AuthzImplementation = iota
+
+	// AuthzImplAuthRequest is the Auth Request Authz implementation which is used by NGINX and HAProxy.
+	AuthzImplAuthRequest
+
+	// AuthzImplExtAuthz is the ExtAuthz Authz implementation which is used by Envoy.
+	AuthzImplExtAuthz
+)
+
+// String returns the text representation of this AuthzImplementation.
+func (i AuthzImplementation) String() string {
+	switch i {
+	case AuthzImplForwardAuth:
+		return "ForwardAuth"
+	case AuthzImplAuthRequest:
+		return "AuthRequest"
+	case AuthzImplExtAuthz:
+		return "ExtAuthz"
+	default:
+		return ""
+	}
+}
+
+// AuthnType is an auth type.
+type AuthnType int
+
+const (
+	// AuthnTypeNone is a nil Authentication AuthnType.
+	AuthnTypeNone AuthnType = iota
+
+	// AuthnTypeCookie is an Authentication AuthnType based on the Cookie header.
+	AuthnTypeCookie
+
+	// AuthnTypeProxyAuthorization is an Authentication AuthnType based on the Proxy-Authorization header.
+	AuthnTypeProxyAuthorization
+
+	// AuthnTypeAuthorization is an Authentication AuthnType based on the Authorization header.
+	AuthnTypeAuthorization
+)
+
+// Authn is authentication.
+type Authn struct {
+	Details  authentication.UserDetails
+	ClientID string
+	Level    authentication.Level
+	Object   authorization.Object
+	Type     AuthnType
+
+	AuthenticationMethodRefs oidc.AuthenticationMethodsReferences
+	AuthenticatedAt          time.Time
+}
+
+// HandlerAuthzUnauthorized is a Authz handler func that handles unauthorized responses.
+type HandlerAuthzUnauthorized func(ctx *middlewares.AutheliaCtx, authn *Authn, redirectionURL *url.URL)
+
+// HandlerAuthzAuthorized is a Authz handler func that handles authorized responses.
+type HandlerAuthzAuthorized func(ctx *middlewares.AutheliaCtx, authn *Authn)
+
+// HandlerAuthzGetAutheliaURL is a Authz handler func that handles retrieval of the Portal URL.
+type HandlerAuthzGetAutheliaURL func(ctx *middlewares.AutheliaCtx) (portalURL *url.URL, err error)
+
+// HandlerAuthzGetObject is a Authz handler func that handles retrieval of the authorization.Object to authorize.
+type HandlerAuthzGetObject func(ctx *middlewares.AutheliaCtx) (object authorization.Object, err error)
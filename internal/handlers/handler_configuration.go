@@ -10,7 +10,7 @@ func ConfigurationGET(ctx *middlewares.AutheliaCtx) {
 		AvailableMethods: make(MethodList, 0, 3),
 	}
 
-	if ctx.Providers.Authorizer.IsSecondFactorEnabled() {
+	if ctxIsSecondFactorEnabled(ctx) {
 		body.AvailableMethods = ctx.AvailableSecondFactorMethods()
 	}
 
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -8,6 +10,8 @@ import (
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
 )
 
 type SecondFactorAvailableMethodsFixture struct {
@@ -208,6 +212,82 @@ func (s *SecondFactorAvailableMethodsFixture) TestShouldRemoveAllMethodsWhenAllD
 	})
 }
 
+func (s *SecondFactorAvailableMethodsFixture) TestShouldHaveAllConfiguredMethodsWhenStorageClientRequiresTwoFactor() {
+	s.mock.Ctx.Configuration = schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: "one_factor",
+		},
+		IdentityProviders: schema.IdentityProvidersConfiguration{
+			OIDC: &schema.OpenIDConnectConfiguration{
+				HMACSecret: "asbdhaaskmdlkamdklasmdlkams",
+				Clients: []schema.OpenIDConnectClientConfiguration{
+					{
+						ID:     "app",
+						Policy: "one_factor",
+					},
+				},
+			},
+		},
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Configuration.IdentityProviders.OIDC.IssuerPrivateKey = key
+
+	s.mock.Ctx.Providers.Authorizer = authorization.NewAuthorizer(&s.mock.Ctx.Configuration)
+	s.mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(s.mock.Ctx.Configuration.IdentityProviders.OIDC, s.mock.StorageMock)
+	s.Require().NoError(err)
+
+	s.Require().False(s.mock.Ctx.Providers.Authorizer.IsSecondFactorEnabled())
+
+	s.mock.StorageMock.EXPECT().
+		LoadOAuth2Clients(s.mock.Ctx, 20, 0).
+		Return([]model.OAuth2Client{
+			{ClientID: "stored", Metadata: []byte(`{"authelia_authorization_policy":"two_factor"}`)},
+		}, nil)
+
+	ConfigurationGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), configurationBody{
+		AvailableMethods: []string{"totp", "webauthn", "mobile_push"},
+	})
+}
+
+func (s *SecondFactorAvailableMethodsFixture) TestShouldRemoveAllMethodsWhenStorageClientsDoNotRequireTwoFactor() {
+	s.mock.Ctx.Configuration = schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: "one_factor",
+		},
+		IdentityProviders: schema.IdentityProvidersConfiguration{
+			OIDC: &schema.OpenIDConnectConfiguration{
+				HMACSecret: "asbdhaaskmdlkamdklasmdlkams",
+			},
+		},
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Configuration.IdentityProviders.OIDC.IssuerPrivateKey = key
+
+	s.mock.Ctx.Providers.Authorizer = authorization.NewAuthorizer(&s.mock.Ctx.Configuration)
+	s.mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(s.mock.Ctx.Configuration.IdentityProviders.OIDC, s.mock.StorageMock)
+	s.Require().NoError(err)
+
+	s.mock.StorageMock.EXPECT().
+		LoadOAuth2Clients(s.mock.Ctx, 20, 0).
+		Return([]model.OAuth2Client{
+			{ClientID: "stored", Metadata: []byte(`{"authelia_authorization_policy":"one_factor"}`)},
+		}, nil)
+
+	ConfigurationGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), configurationBody{
+		AvailableMethods: []string{},
+	})
+}
+
 func TestRunSuite(t *testing.T) {
 	s := new(SecondFactorAvailableMethodsFixture)
 	suite.Run(t, s)
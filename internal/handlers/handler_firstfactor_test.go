@@ -63,7 +63,7 @@ func (s *FirstFactorSuite) TestShouldFailIfUserProviderCheckPasswordFail() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthType1FA,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		}))
 
 	s.mock.Ctx.Request.SetBodyString(`{
@@ -91,7 +91,7 @@ func (s *FirstFactorSuite) TestShouldCheckAuthenticationIsNotMarkedWhenProviderC
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthType1FA,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		}))
 
 	s.mock.Ctx.Request.SetBodyString(`{
@@ -117,7 +117,7 @@ func (s *FirstFactorSuite) TestShouldCheckAuthenticationIsMarkedWhenInvalidCrede
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthType1FA,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		}))
 
 	s.mock.Ctx.Request.SetBodyString(`{
@@ -198,6 +198,11 @@ func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeChecked() {
 		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
 		Return(nil)
 
+	s.mock.StorageMock.
+		EXPECT().
+		SaveActiveSession(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
 	s.mock.Ctx.Request.SetBodyString(`{
 		"username": "test",
 		"password": "hello",
@@ -238,6 +243,11 @@ func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeUnchecked() {
 		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
 		Return(nil)
 
+	s.mock.StorageMock.
+		EXPECT().
+		SaveActiveSession(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
 	s.mock.Ctx.Request.SetBodyString(`{
 		"username": "test",
 		"password": "hello",
@@ -282,6 +292,11 @@ func (s *FirstFactorSuite) TestShouldSaveUsernameFromAuthenticationBackendInSess
 		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
 		Return(nil)
 
+	s.mock.StorageMock.
+		EXPECT().
+		SaveActiveSession(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
 	s.mock.Ctx.Request.SetBodyString(`{
 		"username": "test",
 		"password": "hello",
@@ -339,6 +354,11 @@ func (s *FirstFactorRedirectionSuite) SetupTest() {
 		EXPECT().
 		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
 		Return(nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		SaveActiveSession(s.mock.Ctx, gomock.Any()).
+		Return(nil)
 }
 
 func (s *FirstFactorRedirectionSuite) TearDownTest() {
@@ -5,7 +5,8 @@ import (
 	"net/url"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
-	"github.com/authelia/authelia/v4/internal/utils"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/session"
 )
 
 type logoutBody struct {
@@ -13,7 +14,8 @@ type logoutBody struct {
 }
 
 type logoutResponseBody struct {
-	SafeTargetURL bool `json:"safeTargetURL"`
+	SafeTargetURL         bool   `json:"safeTargetURL"`
+	FrontChannelLogoutURL string `json:"frontChannelLogoutURL,omitempty"`
 }
 
 // LogoutPOST is the handler logging out the user attached to the given cookie.
@@ -26,22 +28,50 @@ func LogoutPOST(ctx *middlewares.AutheliaCtx) {
 		ctx.Error(fmt.Errorf("unable to parse body during logout: %s", err), messageOperationFailed)
 	}
 
-	err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx)
+	userSession := ctx.GetSession()
+
+	frontChannelLogoutURIs := oidcLogoutSession(ctx, ctx.RootURL().String(), &userSession)
+
+	err = ctx.DestroySession()
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to destroy session during logout: %s", err), messageOperationFailed)
 	}
 
 	redirectionURL, err := url.ParseRequestURI(body.TargetURL)
 	if err == nil {
-		responseBody.SafeTargetURL = utils.IsURISafeRedirection(redirectionURL, ctx.Configuration.Session.Domain)
+		responseBody.SafeTargetURL = ctx.IsSafeRedirectionTargetURI(redirectionURL)
 	}
 
 	if body.TargetURL != "" {
 		ctx.Logger.Debugf("Logout target url is %s, safe %t", body.TargetURL, responseBody.SafeTargetURL)
 	}
 
+	if len(frontChannelLogoutURIs) != 0 {
+		if err = logoutSaveFrontChannelLogoutSession(ctx, frontChannelLogoutURIs, body.TargetURL, responseBody.SafeTargetURL); err != nil {
+			ctx.Logger.Errorf("Unable to save the front-channel logout uris during logout: %+v", err)
+		} else {
+			responseBody.FrontChannelLogoutURL = ctx.RootURL().String() + oidc.EndpointPathEndSession
+		}
+	}
+
 	err = ctx.SetJSONBody(responseBody)
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to set body during logout: %s", err), messageOperationFailed)
 	}
 }
+
+// logoutSaveFrontChannelLogoutSession saves the front-channel logout uris of the terminated session to a new anonymous
+// session so the End Session endpoint can render them, as the portal itself can't embed the relying parties.
+func logoutSaveFrontChannelLogoutSession(ctx *middlewares.AutheliaCtx, uris []*url.URL, targetURL string, safe bool) (err error) {
+	userSession := session.NewDefaultUserSession()
+
+	for _, uri := range uris {
+		userSession.OpenIDConnect.FrontChannelLogoutURIs = append(userSession.OpenIDConnect.FrontChannelLogoutURIs, uri.String())
+	}
+
+	if safe {
+		userSession.OpenIDConnect.PostLogoutRedirectURI = targetURL
+	}
+
+	return ctx.SaveSession(userSession)
+}
@@ -30,6 +30,10 @@ func (s *LogoutSuite) TearDownTest() {
 }
 
 func (s *LogoutSuite) TestShouldDestroySession() {
+	s.mock.StorageMock.EXPECT().
+		RevokeActiveSession(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash()).
+		Return(nil)
+
 	LogoutPOST(s.mock.Ctx)
 	b := s.mock.Ctx.Response.Header.PeekCookie("authelia_session")
 
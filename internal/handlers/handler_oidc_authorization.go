@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/ory/fosite"
@@ -40,7 +41,7 @@ func OpenIDConnectAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWr
 
 	ctx.Logger.Debugf("Authorization Request with id '%s' on client with id '%s' is being processed", requester.GetID(), clientID)
 
-	if client, err = ctx.Providers.OpenIDConnect.GetFullClient(clientID); err != nil {
+	if client, err = ctx.Providers.OpenIDConnect.GetFullClient(ctx, clientID); err != nil {
 		if errors.Is(err, fosite.ErrNotFound) {
 			ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: client was not found", requester.GetID(), clientID)
 		} else {
@@ -52,6 +53,17 @@ func OpenIDConnectAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWr
 		return
 	}
 
+	requestURI := requester.GetRequestForm().Get(oidc.FormParameterRequestURI)
+	pushed := strings.HasPrefix(requestURI, ctx.Providers.OpenIDConnect.Config.GetPushedAuthorizeRequestURIPrefix(ctx))
+
+	if client.RequirePushedAuthorizationRequests && !pushed {
+		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: the client requires Pushed Authorization Requests but the request was not pushed", requester.GetID(), clientID)
+
+		ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrInvalidRequest.WithHint("Pushed Authorization Requests are required for this client but no such request was sent."))
+
+		return
+	}
+
 	issuer = ctx.RootURL()
 
 	userSession := ctx.GetSession()
@@ -61,11 +73,15 @@ func OpenIDConnectAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWr
 		handled bool
 	)
 
+	if handleOIDCAuthorizationPrompt(ctx, issuer, client, userSession, rw, r, requester) {
+		return
+	}
+
 	if consent, handled = handleOIDCAuthorizationConsent(ctx, issuer, client, userSession, rw, r, requester); handled {
 		return
 	}
 
-	extraClaims := oidcGrantRequests(requester, consent, &userSession)
+	extraClaims := oidcGrantRequests(requester, consent, &userSession, client, ctx.Providers.OpenIDConnect.Claims)
 
 	if authTime, err = userSession.AuthenticatedTime(client.Policy); err != nil {
 		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred checking authentication time: %+v", requester.GetID(), client.GetID(), err)
@@ -77,9 +93,17 @@ func OpenIDConnectAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWr
 
 	ctx.Logger.Debugf("Authorization Request with id '%s' on client with id '%s' was successfully processed, proceeding to build Authorization Response", requester.GetID(), clientID)
 
-	oidcSession := oidc.NewSessionWithAuthorizeRequest(issuer, ctx.Providers.OpenIDConnect.KeyManager.GetActiveKeyID(),
+	oidcSession := oidc.NewSessionWithAuthorizeRequest(issuer, ctx.Providers.OpenIDConnect.KeyManager.GetKeyID(client.IDTokenSigningAlgorithm),
 		userSession.Username, userSession.AuthenticationMethodRefs.MarshalRFC8176(), extraClaims, authTime, consent, requester)
 
+	oidcSession.Headers.Add(oidc.JWTHeaderAlgorithm, client.IDTokenSigningAlgorithm)
+
+	middlewares.AutheliaCtxWithValue(ctx, oidc.IDTokenSigningAlgorithmContextKey, client.IDTokenSigningAlgorithm)
+
+	oidcSession.Claims.AuthenticationContextClassReference = oidc.GetACR(userSession.AuthenticationLevel)
+
+	oidcSession.Claims.Add(oidc.ClaimSessionID, userSession.GetOpenIDConnectSessionID())
+
 	ctx.Logger.Tracef("Authorization Request with id '%s' on client with id '%s' creating session for Authorization Response for subject '%s' with username '%s' with claims: %+v",
 		requester.GetID(), oidcSession.ClientID, oidcSession.Subject, oidcSession.Username, oidcSession.Claims)
 
@@ -101,5 +125,25 @@ func OpenIDConnectAuthorization(ctx *middlewares.AutheliaCtx, rw http.ResponseWr
 		return
 	}
 
+	if userSession.AddOpenIDConnectClient(client.GetID(), oidcSession.Subject) {
+		if err = ctx.SaveSession(userSession); err != nil {
+			ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred saving the session: %+v", requester.GetID(), client.GetID(), err)
+
+			ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrServerError.WithHint("Could not save the session."))
+
+			return
+		}
+	}
+
+	if pushed {
+		if err = ctx.Providers.OpenIDConnect.Store.RevokePARSession(ctx, requestURI); err != nil {
+			ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred revoking the pushed authorization request: %+v", requester.GetID(), client.GetID(), err)
+
+			ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrServerError.WithHint("Could not revoke the pushed authorization request."))
+
+			return
+		}
+	}
+
 	ctx.Providers.OpenIDConnect.WriteAuthorizeResponse(ctx, rw, requester, responder)
 }
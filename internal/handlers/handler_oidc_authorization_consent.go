@@ -31,7 +31,7 @@ func handleOIDCAuthorizationConsent(ctx *middlewares.AutheliaCtx, issuer *url.UR
 	switch {
 	case userSession.IsAnonymous():
 		handler = handleOIDCAuthorizationConsentNotAuthenticated
-	case client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel):
+	case client.IsAuthenticationLevelSufficientForForm(userSession.AuthenticationLevel, requester.GetRequestForm()):
 		if subject, err = ctx.Providers.OpenIDConnect.GetSubject(ctx, client.GetSectorIdentifier(), userSession.Username); err != nil {
 			ctx.Logger.Errorf(logFmtErrConsentCantGetSubject, requester.GetID(), client.GetID(), client.Consent, userSession.Username, client.GetSectorIdentifier(), err)
 
@@ -96,6 +96,14 @@ func handleOIDCAuthorizationConsentGenerate(ctx *middlewares.AutheliaCtx, issuer
 		return nil, true
 	}
 
+	if oidc.HasPrompt(requester.GetRequestForm(), oidc.PromptNone) {
+		ctx.Logger.Debugf(logFmtDbgPromptNone, requester.GetID(), client.GetID(), "the user must consent", fosite.ErrConsentRequired.ErrorField)
+
+		ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrConsentRequired.WithHint("The user must consent and the 'prompt' parameter was set to 'none'."))
+
+		return nil, true
+	}
+
 	if consent, err = model.NewOAuth2ConsentSession(subject, requester); err != nil {
 		ctx.Logger.Errorf(logFmtErrConsentGenerateError, requester.GetID(), client.GetID(), client.Consent, "generating", err)
 
@@ -121,7 +129,7 @@ func handleOIDCAuthorizationConsentRedirect(ctx *middlewares.AutheliaCtx, issuer
 	userSession session.UserSession, rw http.ResponseWriter, r *http.Request, requester fosite.AuthorizeRequester) {
 	var location *url.URL
 
-	if client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel) {
+	if client.IsAuthenticationLevelSufficientForForm(userSession.AuthenticationLevel, requester.GetRequestForm()) {
 		location, _ = url.ParseRequestURI(issuer.String())
 		location.Path = path.Join(location.Path, oidc.EndpointPathConsent)
 
@@ -154,6 +162,12 @@ func handleOIDCAuthorizationConsentGetRedirectionURL(issuer *url.URL, consent *m
 	query := redirectURL.Query()
 	query.Set(queryArgWorkflow, workflowOpenIDConnect)
 
+	if requester != nil {
+		if hint := requester.GetRequestForm().Get(oidc.FormParameterLoginHint); hint != "" {
+			query.Set(queryArgLoginHint, hint)
+		}
+	}
+
 	switch {
 	case consent != nil:
 		query.Set(queryArgWorkflowID, consent.ChallengeID.String())
@@ -174,7 +188,7 @@ func verifyOIDCUserAuthorizedForConsent(ctx *middlewares.AutheliaCtx, client *oi
 	var sid uint32
 
 	if client == nil {
-		if client, err = ctx.Providers.OpenIDConnect.GetFullClient(consent.ClientID); err != nil {
+		if client, err = ctx.Providers.OpenIDConnect.GetFullClient(ctx, consent.ClientID); err != nil {
 			return fmt.Errorf("failed to retrieve client: %w", err)
 		}
 	}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// handleOIDCAuthorizationPrompt enforces the prompt, max_age, and acr_values parameters of an Authorization Request
+// against the user session. Requests which require the user to interact with Authelia are either rejected when the
+// prompt is none, or the user is redirected to authenticate. A user who must authenticate again has the authentication
+// of their session reset rather than the session destroyed so their identity and OpenID Connect 1.0 session state are
+// retained. Returns true if the request was handled.
+//
+// The parameters are only enforced before the consent session exists, once it exists the time the user authenticated is
+// validated against the time the consent session was requested at when the Authorization Response is created.
+func handleOIDCAuthorizationPrompt(ctx *middlewares.AutheliaCtx, issuer *url.URL, client *oidc.Client,
+	userSession session.UserSession, rw http.ResponseWriter, r *http.Request, requester fosite.AuthorizeRequester) (handled bool) {
+	if len(ctx.QueryArgs().PeekBytes(qryArgConsentID)) != 0 {
+		return false
+	}
+
+	var (
+		form     = requester.GetRequestForm()
+		none     = oidc.HasPrompt(form, oidc.PromptNone)
+		authTime time.Time
+		err      error
+	)
+
+	if none && len(oidc.GetPrompts(form)) > 1 {
+		ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrInvalidRequest.WithHint("Parameter 'prompt' was set to 'none', but contains other values as well which is not allowed."))
+
+		return true
+	}
+
+	switch {
+	case userSession.IsAnonymous():
+		if none {
+			ctx.Logger.Debugf(logFmtDbgPromptNone, requester.GetID(), client.GetID(), "the user is not authenticated", fosite.ErrLoginRequired.ErrorField)
+
+			ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrLoginRequired.WithHint("The user is not authenticated and the 'prompt' parameter was set to 'none'."))
+
+			return true
+		}
+
+		if !oidc.IsLoginPrompted(form) {
+			return false
+		}
+	case none && !client.IsAuthenticationLevelSufficientForForm(userSession.AuthenticationLevel, form):
+		ctx.Logger.Debugf(logFmtDbgPromptNone, requester.GetID(), client.GetID(), "the authentication level of the user is insufficient", fosite.ErrInteractionRequired.ErrorField)
+
+		ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrInteractionRequired.WithHint("The user must complete additional authentication and the 'prompt' parameter was set to 'none'."))
+
+		return true
+	default:
+		if authTime, err = userSession.AuthenticatedTime(client.Policy); err != nil {
+			ctx.Logger.Errorf(logFmtErrPromptAuthTime, requester.GetID(), client.GetID(), err)
+
+			ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrServerError.WithHint("Could not obtain the authentication time."))
+
+			return true
+		}
+
+		if !oidc.IsLoginRequired(form, authTime, requester.GetRequestedAt()) {
+			return false
+		}
+
+		if none {
+			ctx.Logger.Debugf(logFmtDbgPromptNone, requester.GetID(), client.GetID(), "the user must authenticate again to satisfy the 'max_age' parameter", fosite.ErrLoginRequired.ErrorField)
+
+			ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrLoginRequired.WithHint("The user must authenticate again and the 'prompt' parameter was set to 'none'."))
+
+			return true
+		}
+
+		ctx.Logger.Debugf(logFmtDbgPromptLogin, requester.GetID(), client.GetID(), userSession.Username)
+
+		userSession.ResetAuthentication()
+
+		if err = ctx.SaveSession(userSession); err != nil {
+			ctx.Logger.Errorf(logFmtErrPromptResetSession, requester.GetID(), client.GetID(), userSession.Username, err)
+
+			ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, fosite.ErrServerError.WithHint("Could not reset the session."))
+
+			return true
+		}
+	}
+
+	handleOIDCAuthorizationPromptLoginRedirect(ctx, issuer, client, rw, r, requester)
+
+	return true
+}
+
+// handleOIDCAuthorizationPromptLoginRedirect generates a consent session without a subject which records the time of
+// the request, and redirects the user to authenticate. The subject is assigned to the consent session once the user
+// has authenticated.
+func handleOIDCAuthorizationPromptLoginRedirect(ctx *middlewares.AutheliaCtx, issuer *url.URL, client *oidc.Client,
+	rw http.ResponseWriter, r *http.Request, requester fosite.AuthorizeRequester) {
+	var (
+		consent *model.OAuth2ConsentSession
+		err     error
+	)
+
+	if consent, err = model.NewOAuth2ConsentSession(uuid.UUID{}, requester); err != nil {
+		ctx.Logger.Errorf(logFmtErrConsentGenerateError, requester.GetID(), client.GetID(), client.Consent, "generating", err)
+
+		ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, oidc.ErrConsentCouldNotGenerate)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveOAuth2ConsentSession(ctx, *consent); err != nil {
+		ctx.Logger.Errorf(logFmtErrConsentGenerateError, requester.GetID(), client.GetID(), client.Consent, "saving", err)
+
+		ctx.Providers.OpenIDConnect.WriteAuthorizeError(ctx, rw, requester, oidc.ErrConsentCouldNotSave)
+
+		return
+	}
+
+	location := handleOIDCAuthorizationConsentGetRedirectionURL(issuer, consent, requester)
+
+	ctx.Logger.Debugf(logFmtDbgConsentRedirect, requester.GetID(), client.GetID(), client.Consent, location)
+
+	http.Redirect(rw, r, location.String(), http.StatusFound)
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+const (
+	testPromptClientID    = "app"
+	testPromptRedirectURI = "https://app.example.com/callback"
+	testPromptSID         = "5c4a6f0e-4b0a-4c41-9a55-0f3f2e8ad0c1"
+)
+
+func setupOpenIDConnectAuthorizationPromptTest(t *testing.T) *mocks.MockAutheliaCtx {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: key,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:            testPromptClientID,
+				Policy:        "one_factor",
+				RedirectURIs:  []string{testPromptRedirectURI},
+				Scopes:        []string{oidc.ScopeOpenID},
+				ResponseTypes: []string{"code"},
+				GrantTypes:    []string{oidc.GrantTypeAuthorizationCode},
+				ConsentMode:   oidc.ClientConsentModeExplicit.String(),
+			},
+		},
+	}, mock.StorageMock)
+	require.NoError(t, err)
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+
+	return mock
+}
+
+func setOpenIDConnectAuthorizationPromptTestSession(t *testing.T, mock *mocks.MockAutheliaCtx, level authentication.Level, authenticatedAt time.Time) {
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = level
+	userSession.FirstFactorAuthnTimestamp = authenticatedAt.Unix()
+	userSession.AuthenticationMethodRefs.UsernameAndPassword = true
+	userSession.OpenIDConnect = session.OpenIDConnectSession{
+		SessionID: testPromptSID,
+		Clients:   []session.OpenIDConnectSessionClient{{ID: testPromptClientID, Subject: "subject-a"}},
+	}
+
+	if level == authentication.TwoFactor {
+		userSession.SecondFactorAuthnTimestamp = authenticatedAt.Unix()
+	}
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+}
+
+func doOpenIDConnectAuthorizationPromptTest(mock *mocks.MockAutheliaCtx, values url.Values) *httptest.ResponseRecorder {
+	form := url.Values{
+		oidc.FormParameterClientID: []string{testPromptClientID},
+		"response_type":            []string{"code"},
+		"redirect_uri":             []string{testPromptRedirectURI},
+		oidc.FormParameterScope:    []string{oidc.ScopeOpenID},
+		oidc.FormParameterState:    []string{"random-state-value"},
+	}
+
+	for k, v := range values {
+		form[k] = v
+	}
+
+	uri := "https://auth.example.com/api/oidc/authorization?" + form.Encode()
+
+	mock.Ctx.Request.SetRequestURI(uri)
+
+	rw := httptest.NewRecorder()
+
+	OpenIDConnectAuthorization(mock.Ctx, rw, httptest.NewRequest(http.MethodGet, uri, nil))
+
+	return rw
+}
+
+func assertOpenIDConnectAuthorizationPromptError(t *testing.T, rw *httptest.ResponseRecorder, expected string) {
+	require.Equal(t, http.StatusSeeOther, rw.Code)
+
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "app.example.com", location.Host)
+	assert.Equal(t, "/callback", location.Path)
+	assert.Equal(t, expected, location.Query().Get("error"))
+	assert.Equal(t, "random-state-value", location.Query().Get("state"))
+}
+
+func assertOpenIDConnectAuthorizationPromptLoginRedirect(t *testing.T, rw *httptest.ResponseRecorder, consent *model.OAuth2ConsentSession) {
+	require.Equal(t, http.StatusFound, rw.Code)
+
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "auth.example.com", location.Host)
+	assert.Equal(t, "/", location.Path)
+	assert.Equal(t, workflowOpenIDConnect, location.Query().Get(queryArgWorkflow))
+	assert.Equal(t, consent.ChallengeID.String(), location.Query().Get(queryArgWorkflowID))
+	assert.False(t, consent.Subject.Valid)
+}
+
+func expectOpenIDConnectAuthorizationPromptConsentSession(mock *mocks.MockAutheliaCtx, consent *model.OAuth2ConsentSession) {
+	mock.StorageMock.EXPECT().
+		SaveOAuth2ConsentSession(mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ interface{}, c model.OAuth2ConsentSession) error {
+			*consent = c
+
+			return nil
+		})
+}
+
+func TestOpenIDConnectAuthorizationPromptNoneShouldRespondLoginRequiredWhenNotAuthenticated(t *testing.T) {
+	mock := setupOpenIDConnectAuthorizationPromptTest(t)
+	defer mock.Close()
+
+	rw := doOpenIDConnectAuthorizationPromptTest(mock, url.Values{oidc.FormParameterPrompt: []string{oidc.PromptNone}})
+
+	assertOpenIDConnectAuthorizationPromptError(t, rw, "login_required")
+}
+
+func TestOpenIDConnectAuthorizationPromptNoneShouldRespondLoginRequiredWhenMaxAgeExceeded(t *testing.T) {
+	mock := setupOpenIDConnectAuthorizationPromptTest(t)
+	defer mock.Close()
+
+	setOpenIDConnectAuthorizationPromptTestSession(t, mock, authentication.OneFactor, time.Now().Add(-time.Hour))
+
+	rw := doOpenIDConnectAuthorizationPromptTest(mock, url.Values{
+		oidc.FormParameterPrompt: []string{oidc.PromptNone},
+		oidc.FormParameterMaxAge: []string{"60"},
+	})
+
+	assertOpenIDConnectAuthorizationPromptError(t, rw, "login_required")
+
+	assert.Equal(t, authentication.OneFactor, mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func TestOpenIDConnectAuthorizationPromptNoneShouldRespondInteractionRequiredWhenACRValuesNotSatisfied(t *testing.T) {
+	mock := setupOpenIDConnectAuthorizationPromptTest(t)
+	defer mock.Close()
+
+	setOpenIDConnectAuthorizationPromptTestSession(t, mock, authentication.OneFactor, time.Now())
+
+	rw := doOpenIDConnectAuthorizationPromptTest(mock, url.Values{
+		oidc.FormParameterPrompt:    []string{oidc.PromptNone},
+		oidc.FormParameterACRValues: []string{oidc.ACRTwoFactor},
+	})
+
+	assertOpenIDConnectAuthorizationPromptError(t, rw, "interaction_required")
+}
+
+func TestOpenIDConnectAuthorizationPromptNoneShouldRespondConsentRequiredWhenNotConsented(t *testing.T) {
+	mock := setupOpenIDConnectAuthorizationPromptTest(t)
+	defer mock.Close()
+
+	setOpenIDConnectAuthorizationPromptTestSession(t, mock, authentication.OneFactor, time.Now())
+
+	mock.StorageMock.EXPECT().
+		LoadUserOpaqueIdentifierBySignature(mock.Ctx, "openid", "", testUsername).
+		Return(&model.UserOpaqueIdentifier{Service: "openid", Username: testUsername, Identifier: uuid.New()}, nil)
+
+	rw := doOpenIDConnectAuthorizationPromptTest(mock, url.Values{oidc.FormParameterPrompt: []string{oidc.PromptNone}})
+
+	assertOpenIDConnectAuthorizationPromptError(t, rw, "consent_required")
+}
+
+func TestOpenIDConnectAuthorizationPromptNoneShouldRejectOtherPrompts(t *testing.T) {
+	mock := setupOpenIDConnectAuthorizationPromptTest(t)
+	defer mock.Close()
+
+	rw := doOpenIDConnectAuthorizationPromptTest(mock, url.Values{oidc.FormParameterPrompt: []string{"none login"}})
+
+	assertOpenIDConnectAuthorizationPromptError(t, rw, "invalid_request")
+}
+
+func TestOpenIDConnectAuthorizationPromptLoginShouldResetAuthenticationAndRedirect(t *testing.T) {
+	mock := setupOpenIDConnectAuthorizationPromptTest(t)
+	defer mock.Close()
+
+	setOpenIDConnectAuthorizationPromptTestSession(t, mock, authentication.TwoFactor, time.Now().Add(-time.Minute))
+
+	consent := &model.OAuth2ConsentSession{}
+
+	expectOpenIDConnectAuthorizationPromptConsentSession(mock, consent)
+
+	rw := doOpenIDConnectAuthorizationPromptTest(mock, url.Values{oidc.FormParameterPrompt: []string{oidc.PromptLogin}})
+
+	assertOpenIDConnectAuthorizationPromptLoginRedirect(t, rw, consent)
+
+	userSession := mock.Ctx.GetSession()
+
+	assert.True(t, userSession.IsAnonymous())
+	assert.Equal(t, testUsername, userSession.Username)
+	assert.Equal(t, authentication.NotAuthenticated, userSession.AuthenticationLevel)
+	assert.Equal(t, int64(0), userSession.FirstFactorAuthnTimestamp)
+	assert.Equal(t, int64(0), userSession.SecondFactorAuthnTimestamp)
+	assert.False(t, userSession.AuthenticationMethodRefs.UsernameAndPassword)
+	assert.Equal(t, testPromptSID, userSession.OpenIDConnect.SessionID)
+	assert.Len(t, userSession.OpenIDConnect.Clients, 1)
+}
+
+func TestOpenIDConnectAuthorizationPromptMaxAgeShouldResetAuthenticationAndRedirectWhenExceeded(t *testing.T) {
+	mock := setupOpenIDConnectAuthorizationPromptTest(t)
+	defer mock.Close()
+
+	setOpenIDConnectAuthorizationPromptTestSession(t, mock, authentication.OneFactor, time.Now().Add(-time.Hour))
+
+	consent := &model.OAuth2ConsentSession{}
+
+	expectOpenIDConnectAuthorizationPromptConsentSession(mock, consent)
+
+	rw := doOpenIDConnectAuthorizationPromptTest(mock, url.Values{oidc.FormParameterMaxAge: []string{"60"}})
+
+	assertOpenIDConnectAuthorizationPromptLoginRedirect(t, rw, consent)
+
+	userSession := mock.Ctx.GetSession()
+
+	assert.True(t, userSession.IsAnonymous())
+	assert.Equal(t, testUsername, userSession.Username)
+	assert.Equal(t, testPromptSID, userSession.OpenIDConnect.SessionID)
+}
+
+func TestOpenIDConnectAuthorizationPromptLoginShouldRedirectWhenNotAuthenticated(t *testing.T) {
+	mock := setupOpenIDConnectAuthorizationPromptTest(t)
+	defer mock.Close()
+
+	consent := &model.OAuth2ConsentSession{}
+
+	expectOpenIDConnectAuthorizationPromptConsentSession(mock, consent)
+
+	rw := doOpenIDConnectAuthorizationPromptTest(mock, url.Values{oidc.FormParameterPrompt: []string{oidc.PromptLogin}})
+
+	assertOpenIDConnectAuthorizationPromptLoginRedirect(t, rw, consent)
+}
+
+func TestOpenIDConnectAuthorizationPromptShouldNotResetAuthenticationWhenMaxAgeSatisfied(t *testing.T) {
+	mock := setupOpenIDConnectAuthorizationPromptTest(t)
+	defer mock.Close()
+
+	setOpenIDConnectAuthorizationPromptTestSession(t, mock, authentication.OneFactor, time.Now())
+
+	mock.StorageMock.EXPECT().
+		LoadUserOpaqueIdentifierBySignature(mock.Ctx, "openid", "", testUsername).
+		Return(&model.UserOpaqueIdentifier{Service: "openid", Username: testUsername, Identifier: uuid.New()}, nil)
+
+	consent := &model.OAuth2ConsentSession{}
+
+	expectOpenIDConnectAuthorizationPromptConsentSession(mock, consent)
+
+	rw := doOpenIDConnectAuthorizationPromptTest(mock, url.Values{oidc.FormParameterMaxAge: []string{"3600"}})
+
+	require.Equal(t, http.StatusFound, rw.Code)
+
+	location, err := url.Parse(rw.Header().Get("Location"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/consent", location.Path)
+	assert.Equal(t, consent.ChallengeID.String(), location.Query().Get(queryArgID))
+	assert.True(t, consent.Subject.Valid)
+
+	assert.Equal(t, authentication.OneFactor, mock.Ctx.GetSession().AuthenticationLevel)
+}
@@ -40,7 +40,10 @@ func OpenIDConnectConsentGET(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if err = ctx.SetJSONBody(client.GetConsentResponseBody(consent)); err != nil {
+	body := client.GetConsentResponseBody(consent)
+	body.ScopeDescriptions = ctx.Providers.OpenIDConnect.Claims.GetScopeDescriptions(body.Scopes)
+
+	if err = ctx.SetJSONBody(body); err != nil {
 		ctx.Error(fmt.Errorf("unable to set JSON body: %v", err), "Operation failed")
 	}
 }
@@ -86,10 +89,12 @@ func OpenIDConnectConsentPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	device := isOIDCDeviceConsent(consent)
+
 	if bodyJSON.Consent {
 		consent.Grant()
 
-		if bodyJSON.PreConfigure {
+		if bodyJSON.PreConfigure && !device {
 			if client.Consent.Mode == oidc.ClientConsentModePreConfigured {
 				config := model.OAuth2ConsentPreConfig{
 					ClientID:  consent.ClientID,
@@ -132,6 +137,29 @@ func OpenIDConnectConsentPOST(ctx *middlewares.AutheliaCtx) {
 
 	redirectURI = ctx.RootURL()
 
+	if device {
+		var status string
+
+		if status, err = handleOIDCDeviceConsentResponse(ctx, client, userSession, consent, bodyJSON.Consent); err != nil {
+			ctx.Logger.Errorf("Failed to save the device code session response for consent session with id '%s' for user '%s': %+v", consent.ChallengeID, userSession.Username, err)
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+
+		query = url.Values{}
+		query.Set(queryArgStatus, status)
+
+		redirectURI.Path = path.Join(redirectURI.Path, oidc.EndpointPathDevice)
+		redirectURI.RawQuery = query.Encode()
+
+		if err = ctx.SetJSONBody(oidc.ConsentPostResponseBody{RedirectURI: redirectURI.String()}); err != nil {
+			ctx.Error(fmt.Errorf("unable to set JSON bodyJSON in response"), "Operation failed")
+		}
+
+		return
+	}
+
 	if query, err = url.ParseQuery(consent.Form); err != nil {
 		ctx.Logger.Errorf("Failed to parse the consent form values: %+v", err)
 		ctx.SetJSONError(messageOperationFailed)
@@ -165,7 +193,7 @@ func oidcConsentGetSessionsAndClient(ctx *middlewares.AutheliaCtx, consentID uui
 		return userSession, nil, nil, true
 	}
 
-	if client, err = ctx.Providers.OpenIDConnect.GetFullClient(consent.ClientID); err != nil {
+	if client, err = ctx.Providers.OpenIDConnect.GetFullClient(ctx, consent.ClientID); err != nil {
 		ctx.Logger.Errorf("Unable to find related client configuration with name '%s': %v", consent.ClientID, err)
 		ctx.ReplyForbidden()
 
@@ -180,8 +208,10 @@ func oidcConsentGetSessionsAndClient(ctx *middlewares.AutheliaCtx, consentID uui
 		return userSession, nil, nil, true
 	}
 
-	switch client.Consent.Mode {
-	case oidc.ClientConsentModeImplicit:
+	// The device authorization grant always requires explicit consent as the end user is not otherwise able to verify
+	// the device that is requesting authorization.
+	switch {
+	case client.Consent.Mode == oidc.ClientConsentModeImplicit && !isOIDCDeviceConsent(consent):
 		ctx.Logger.Errorf("Unable to perform OpenID Connect Consent for user '%s' and client id '%s': the client is using the implicit consent mode", userSession.Username, consent.ClientID)
 		ctx.ReplyForbidden()
 
@@ -201,7 +231,16 @@ func oidcConsentGetSessionsAndClient(ctx *middlewares.AutheliaCtx, consentID uui
 		}
 	}
 
-	if !client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel) {
+	var form url.Values
+
+	if form, err = consent.GetForm(); err != nil {
+		ctx.Logger.Errorf("Unable to perform OpenID Connect Consent for user '%s' and client id '%s': the consent session form could not be parsed: %v", userSession.Username, consent.ClientID, err)
+		ctx.ReplyForbidden()
+
+		return userSession, nil, nil, true
+	}
+
+	if !client.IsAuthenticationLevelSufficientForForm(userSession.AuthenticationLevel, form) {
 		ctx.Logger.Errorf("Unable to perform OpenID Connect Consent for user '%s' and client id '%s': the user is not sufficiently authenticated", userSession.Username, consent.ClientID)
 		ctx.ReplyForbidden()
 
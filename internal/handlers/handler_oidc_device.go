@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// OpenIDConnectDeviceAuthorizationPOST handles POST requests to the OAuth 2.0 Device Authorization endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.1
+func OpenIDConnectDeviceAuthorizationPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter, req *http.Request) {
+	var (
+		requester fosite.Requester
+		response  *oidc.DeviceAuthorizeResponse
+		err       error
+	)
+
+	if requester, err = ctx.Providers.OpenIDConnect.NewDeviceAuthorizeRequest(ctx, req, oidc.NewSession()); err != nil {
+		rfc := fosite.ErrorToRFC6749Error(err)
+
+		ctx.Logger.Errorf("Device Authorization Request failed with error: %s", rfc.WithExposeDebug(true).GetDescription())
+
+		ctx.Providers.OpenIDConnect.WriteAccessError(ctx, rw, nil, err)
+
+		return
+	}
+
+	clientID := requester.GetClient().GetID()
+
+	ctx.Logger.Debugf("Device Authorization Request with id '%s' on client with id '%s' is being processed", requester.GetID(), clientID)
+
+	verificationURI := ctx.RootURL()
+	verificationURI.Path = path.Join(verificationURI.Path, oidc.EndpointPathDevice)
+
+	if response, err = ctx.Providers.OpenIDConnect.NewDeviceAuthorizeResponse(ctx, requester, verificationURI.String()); err != nil {
+		rfc := fosite.ErrorToRFC6749Error(err)
+
+		ctx.Logger.Errorf("Device Authorization Response for Request with id '%s' on client with id '%s' could not be created: %s", requester.GetID(), clientID, rfc.WithExposeDebug(true).GetDescription())
+
+		ctx.Providers.OpenIDConnect.WriteAccessError(ctx, rw, nil, err)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Device Authorization Request with id '%s' on client with id '%s' has successfully been processed", requester.GetID(), clientID)
+
+	rw.Header().Set(fasthttp.HeaderContentType, headerValueContentTypeJSON)
+	rw.Header().Set(fasthttp.HeaderCacheControl, headerValueNoStore)
+	rw.Header().Set(fasthttp.HeaderPragma, headerValueNoCache)
+
+	rw.WriteHeader(http.StatusOK)
+
+	if err = json.NewEncoder(rw).Encode(response); err != nil {
+		ctx.Logger.Errorf("Device Authorization Response for Request with id '%s' on client with id '%s' could not be written: %+v", requester.GetID(), clientID, err)
+	}
+}
+
+// OpenIDConnectDeviceUserCodePOST handles the end user submitting the user code displayed by a device. It creates the
+// consent session for the device authorization request and returns the URL the end user should be redirected to in
+// order to authenticate and respond to the consent request.
+//
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.3
+func OpenIDConnectDeviceUserCodePOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		bodyJSON  oidc.DeviceUserCodePostRequestBody
+		signature string
+		device    *model.OAuth2DeviceCodeSession
+		client    *oidc.Client
+		request   *fosite.Request
+		subject   uuid.UUID
+		consent   *model.OAuth2ConsentSession
+		err       error
+	)
+
+	if err = json.Unmarshal(ctx.Request.Body(), &bodyJSON); err != nil {
+		ctx.Logger.Errorf("Failed to parse JSON body in device user code POST: %+v", err)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	userSession := ctx.GetSession()
+
+	var bannedUntil time.Time
+
+	if bannedUntil, err = ctx.Providers.Regulator.RegulateRemoteIP(ctx, ctx.RemoteIP(), regulation.AuthTypeDeviceUserCode); err != nil {
+		if errors.Is(err, regulation.ErrUserIsBanned) {
+			ctx.Logger.Errorf("Device user code POST failed: the remote ip '%s' is banned until %s", ctx.RemoteIP(), bannedUntil)
+
+			markOIDCDeviceUserCodeAttempt(ctx, false, true, userSession.Username)
+		} else {
+			ctx.Logger.Errorf("Device user code POST failed: error occurred regulating the remote ip '%s': %+v", ctx.RemoteIP(), err)
+		}
+
+		ctx.SetJSONError(messageDeviceUserCodeInvalid)
+
+		return
+	}
+
+	if signature, err = ctx.Providers.OpenIDConnect.Config.Strategy.DeviceCode.UserCodeSignature(ctx, bodyJSON.UserCode); err != nil {
+		ctx.Logger.Errorf("Failed to generate the user code signature in device user code POST: %+v", err)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if device, err = ctx.Providers.StorageProvider.LoadOAuth2DeviceCodeSessionByUserCodeSignature(ctx, signature); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.Logger.Errorf("Device user code POST failed: the user code was not found")
+
+			markOIDCDeviceUserCodeAttempt(ctx, false, false, userSession.Username)
+		} else {
+			ctx.Logger.Errorf("Device user code POST failed: error occurred loading the device code session: %+v", err)
+		}
+
+		ctx.SetJSONError(messageDeviceUserCodeInvalid)
+
+		return
+	}
+
+	if !device.IsPending() || device.IsExpired() {
+		ctx.Logger.Errorf("Device user code POST failed for request with id '%s' on client with id '%s': the device code session is either expired or has already been responded to", device.RequestID, device.ClientID)
+
+		markOIDCDeviceUserCodeAttempt(ctx, false, false, userSession.Username)
+
+		ctx.SetJSONError(messageDeviceUserCodeInvalid)
+
+		return
+	}
+
+	if client, err = ctx.Providers.OpenIDConnect.GetFullClient(ctx, device.ClientID); err != nil {
+		ctx.Logger.Errorf("Device user code POST failed for request with id '%s': unable to find related client configuration with name '%s': %+v", device.RequestID, device.ClientID, err)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if request, err = device.ToRequest(ctx, oidc.NewSession(), ctx.Providers.OpenIDConnect.Store); err != nil {
+		ctx.Logger.Errorf("Device user code POST failed for request with id '%s' on client with id '%s': error occurred restoring the request: %+v", device.RequestID, client.GetID(), err)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if !userSession.IsAnonymous() {
+		if subject, err = ctx.Providers.OpenIDConnect.GetSubject(ctx, client.GetSectorIdentifier(), userSession.Username); err != nil {
+			ctx.Logger.Errorf("Device user code POST failed for request with id '%s' on client with id '%s': error occurred looking up the subject for user '%s': %+v", device.RequestID, client.GetID(), userSession.Username, err)
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+	}
+
+	request.GetRequestForm().Set(oidc.FormParameterGrantType, oidc.GrantTypeDeviceCode)
+
+	if consent, err = model.NewOAuth2ConsentSession(subject, request); err != nil {
+		ctx.Logger.Errorf("Device user code POST failed for request with id '%s' on client with id '%s': error occurred generating the consent session: %+v", device.RequestID, client.GetID(), err)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveOAuth2ConsentSession(ctx, *consent); err != nil {
+		ctx.Logger.Errorf("Device user code POST failed for request with id '%s' on client with id '%s': error occurred saving the consent session: %+v", device.RequestID, client.GetID(), err)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveOAuth2DeviceCodeSessionChallengeID(ctx, device.ID, consent.ChallengeID); err != nil {
+		ctx.Logger.Errorf("Device user code POST failed for request with id '%s' on client with id '%s': error occurred saving the device code session: %+v", device.RequestID, client.GetID(), err)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	var location *url.URL
+
+	if !userSession.IsAnonymous() && client.IsAuthenticationLevelSufficient(userSession.AuthenticationLevel) {
+		location = ctx.RootURL()
+		location.Path = path.Join(location.Path, oidc.EndpointPathConsent)
+
+		query := location.Query()
+		query.Set(queryArgID, consent.ChallengeID.String())
+
+		location.RawQuery = query.Encode()
+	} else {
+		location = handleOIDCAuthorizationConsentGetRedirectionURL(ctx.RootURL(), consent, nil)
+	}
+
+	markOIDCDeviceUserCodeAttempt(ctx, true, false, userSession.Username)
+
+	ctx.Logger.Debugf("Device user code POST for request with id '%s' on client with id '%s' was successfully processed, redirecting to '%s'", device.RequestID, client.GetID(), location)
+
+	if err = ctx.SetJSONBody(redirectResponse{Redirect: location.String()}); err != nil {
+		ctx.Logger.Errorf("Unable to set JSON body in response: %+v", err)
+	}
+}
+
+// markOIDCDeviceUserCodeAttempt records an attempt to enter a user code in the authentication log. These attempts are
+// regulated by the remote IP as the end user is not necessarily signed in.
+func markOIDCDeviceUserCodeAttempt(ctx *middlewares.AutheliaCtx, successful, banned bool, username string) {
+	if err := ctx.Providers.Regulator.Mark(ctx, successful, banned, username, string(ctx.RequestURI()), string(ctx.Method()), regulation.AuthTypeDeviceUserCode); err != nil {
+		ctx.Logger.Errorf("Unable to mark the device user code attempt from remote ip '%s': %+v", ctx.RemoteIP(), err)
+	}
+}
+
+// isOIDCDeviceConsent returns true if the consent session was created for an OAuth 2.0 Device Authorization Grant.
+func isOIDCDeviceConsent(consent *model.OAuth2ConsentSession) bool {
+	form, err := consent.GetForm()
+	if err != nil {
+		return false
+	}
+
+	return form.Get(oidc.FormParameterGrantType) == oidc.GrantTypeDeviceCode
+}
+
+// handleOIDCDeviceConsentResponse records the response of the end user to the consent session of an OAuth 2.0 Device
+// Authorization Grant against the device code session so the device can complete polling the token endpoint.
+func handleOIDCDeviceConsentResponse(ctx *middlewares.AutheliaCtx, client *oidc.Client, userSession session.UserSession,
+	consent *model.OAuth2ConsentSession, authorized bool) (status string, err error) {
+	var (
+		device  *model.OAuth2DeviceCodeSession
+		request *fosite.Request
+	)
+
+	if device, err = ctx.Providers.StorageProvider.LoadOAuth2DeviceCodeSessionByChallengeID(ctx, consent.ChallengeID); err != nil {
+		return "", fmt.Errorf("error occurred loading the device code session: %w", err)
+	}
+
+	if !device.IsPending() || device.IsExpired() {
+		return "", fmt.Errorf("the device code session with request id '%s' is either expired or has already been responded to", device.RequestID)
+	}
+
+	if !authorized {
+		device.Status = model.OAuth2DeviceCodeStatusDenied
+
+		if err = ctx.Providers.StorageProvider.SaveOAuth2DeviceCodeSessionResponse(ctx, *device); err != nil {
+			return "", fmt.Errorf("error occurred saving the device code session: %w", err)
+		}
+
+		return deviceStatusDenied, nil
+	}
+
+	if request, err = device.ToRequest(ctx, oidc.NewSession(), ctx.Providers.OpenIDConnect.Store); err != nil {
+		return "", fmt.Errorf("error occurred restoring the request: %w", err)
+	}
+
+	requester := &fosite.AuthorizeRequest{Request: *request}
+
+	extraClaims := oidcGrantRequests(requester, consent, &userSession, client, ctx.Providers.OpenIDConnect.Claims)
+
+	authTime, err := userSession.AuthenticatedTime(client.Policy)
+	if err != nil {
+		return "", fmt.Errorf("error occurred checking authentication time: %w", err)
+	}
+
+	oidcSession := oidc.NewSessionWithAuthorizeRequest(ctx.RootURL(), ctx.Providers.OpenIDConnect.KeyManager.GetKeyID(client.IDTokenSigningAlgorithm),
+		userSession.Username, userSession.AuthenticationMethodRefs.MarshalRFC8176(), extraClaims, authTime, consent, requester)
+
+	oidcSession.Headers.Add(oidc.JWTHeaderAlgorithm, client.IDTokenSigningAlgorithm)
+
+	oidcSession.Claims.AuthenticationContextClassReference = oidc.GetACR(userSession.AuthenticationLevel)
+
+	oidcSession.Claims.Add(oidc.ClaimSessionID, userSession.GetOpenIDConnectSessionID())
+
+	device.Status = model.OAuth2DeviceCodeStatusAuthorized
+	device.Subject = sql.NullString{String: consent.Subject.UUID.String(), Valid: consent.Subject.Valid}
+	device.GrantedScopes = model.StringSlicePipeDelimited(requester.GetGrantedScopes())
+	device.GrantedAudience = model.StringSlicePipeDelimited(requester.GetGrantedAudience())
+
+	if device.Session, err = json.Marshal(oidcSession); err != nil {
+		return "", fmt.Errorf("error occurred marshalling the session: %w", err)
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveOAuth2DeviceCodeSessionResponse(ctx, *device); err != nil {
+		return "", fmt.Errorf("error occurred saving the device code session: %w", err)
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveOAuth2ConsentSessionGranted(ctx, consent.ID); err != nil {
+		return "", fmt.Errorf("error occurred saving the consent session: %w", err)
+	}
+
+	if userSession.AddOpenIDConnectClient(client.GetID(), oidcSession.Subject) {
+		if err = ctx.SaveSession(userSession); err != nil {
+			return "", fmt.Errorf("error occurred saving the session: %w", err)
+		}
+	}
+
+	return deviceStatusAuthorized, nil
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func setupOpenIDConnectDeviceUserCodeTest(t *testing.T) *mocks.MockAutheliaCtx {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: key,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		Clients: []schema.OpenIDConnectClientConfiguration{{
+			ID:         "device",
+			Policy:     "one_factor",
+			GrantTypes: []string{oidc.GrantTypeDeviceCode},
+		}},
+	}, mock.StorageMock)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.Regulator = regulation.NewRegulator(schema.RegulationConfiguration{
+		MaxRetries: 3,
+		FindTime:   time.Minute,
+		BanTime:    time.Minute * 5,
+	}, mock.StorageMock, &mock.Clock)
+
+	mock.Ctx.Request.SetBodyString(`{"user_code":"BCDF-GHJK"}`)
+
+	return mock
+}
+
+func TestOpenIDConnectDeviceUserCodePOSTShouldMarkUnknownUserCode(t *testing.T) {
+	mock := setupOpenIDConnectDeviceUserCodeTest(t)
+	defer mock.Close()
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().
+			LoadAuthenticationLogsByRemoteIP(mock.Ctx, gomock.Any(), regulation.AuthTypeDeviceUserCode, gomock.Any(), 10, 0).
+			Return(nil, storage.ErrNoAuthenticationLogs),
+		mock.StorageMock.EXPECT().
+			LoadOAuth2DeviceCodeSessionByUserCodeSignature(mock.Ctx, gomock.Any()).
+			Return(nil, fmt.Errorf("error selecting oauth2 device code session: %w", sql.ErrNoRows)),
+		mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, attempt model.AuthenticationAttempt) error {
+				assert.Equal(t, regulation.AuthTypeDeviceUserCode, attempt.Type)
+				assert.Equal(t, mock.Ctx.RemoteIP().String(), attempt.RemoteIP.IP.String())
+				assert.False(t, attempt.Successful)
+				assert.False(t, attempt.Banned)
+
+				return nil
+			}),
+	)
+
+	OpenIDConnectDeviceUserCodePOST(mock.Ctx)
+
+	mock.Assert200KO(t, messageDeviceUserCodeInvalid)
+}
+
+func TestOpenIDConnectDeviceUserCodePOSTShouldRejectBannedRemoteIP(t *testing.T) {
+	mock := setupOpenIDConnectDeviceUserCodeTest(t)
+	defer mock.Close()
+
+	attempts := []model.AuthenticationAttempt{
+		{Successful: false, Time: mock.Clock.Now().Add(-time.Second)},
+		{Successful: false, Time: mock.Clock.Now().Add(-time.Second * 2)},
+		{Successful: false, Time: mock.Clock.Now().Add(-time.Second * 3)},
+	}
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().
+			LoadAuthenticationLogsByRemoteIP(mock.Ctx, gomock.Any(), regulation.AuthTypeDeviceUserCode, gomock.Any(), 10, 0).
+			Return(attempts, nil),
+		mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, attempt model.AuthenticationAttempt) error {
+				assert.Equal(t, regulation.AuthTypeDeviceUserCode, attempt.Type)
+				assert.False(t, attempt.Successful)
+				assert.True(t, attempt.Banned)
+
+				return nil
+			}),
+	)
+
+	OpenIDConnectDeviceUserCodePOST(mock.Ctx)
+
+	mock.Assert200KO(t, messageDeviceUserCodeInvalid)
+}
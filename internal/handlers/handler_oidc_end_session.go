@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"net/url"
+
+	"github.com/ory/fosite"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// OpenIDConnectEndSession handles GET/POST requests to the OpenID Connect 1.0 End Session endpoint. The session is
+// only terminated when the request includes a valid id_token_hint issued during the current session, which prevents a
+// third party from terminating the session with a cross-site request. Otherwise the logout can't be confirmed so an
+// error is returned instead of redirecting the user agent as if the user was logged out.
+//
+// https://openid.net/specs/openid-connect-rpinitiated-1_0.html#RPLogout
+func OpenIDConnectEndSession(ctx *middlewares.AutheliaCtx) {
+	var (
+		args   *fasthttp.Args
+		hint   *oidc.IDTokenHint
+		client *oidc.Client
+		err    error
+	)
+
+	if ctx.IsPost() {
+		args = ctx.PostArgs()
+	} else {
+		args = ctx.QueryArgs()
+	}
+
+	issuer := ctx.RootURL().String()
+	clientID := string(args.Peek(oidc.FormParameterClientID))
+
+	if idTokenHint := string(args.Peek(oidc.FormParameterIDTokenHint)); idTokenHint != "" {
+		if hint, err = ctx.Providers.OpenIDConnect.DecodeIDTokenHint(issuer, clientID, idTokenHint); err != nil {
+			ctx.Logger.Errorf("End Session Request failed with error: the id_token_hint could not be validated: %+v", err)
+
+			replyOpenIDConnectEndSessionError(ctx, fosite.ErrInvalidRequest.WithHint("The 'id_token_hint' parameter could not be validated."))
+
+			return
+		}
+
+		if clientID == "" {
+			clientID = hint.ClientID()
+		}
+	}
+
+	if clientID != "" {
+		if client, err = ctx.Providers.OpenIDConnect.GetFullClient(ctx, clientID); err != nil {
+			ctx.Logger.Errorf("End Session Request failed with error: failed to find client with id '%s': %+v", clientID, err)
+
+			replyOpenIDConnectEndSessionError(ctx, fosite.ErrInvalidRequest.WithHint("The client is not registered."))
+
+			return
+		}
+	}
+
+	redirectURI, hasPostLogoutRedirectURI := ctx.RootURLSlash().String(), false
+
+	if postLogoutRedirectURI := string(args.Peek(oidc.FormParameterPostLogoutRedirectURI)); postLogoutRedirectURI != "" {
+		if client == nil {
+			ctx.Logger.Errorf("End Session Request failed with error: the post_logout_redirect_uri '%s' was provided without identifying the client", postLogoutRedirectURI)
+
+			replyOpenIDConnectEndSessionError(ctx, fosite.ErrInvalidRequest.WithHint("The 'post_logout_redirect_uri' parameter requires either the 'id_token_hint' or 'client_id' parameter."))
+
+			return
+		}
+
+		if !client.IsValidPostLogoutRedirectURI(postLogoutRedirectURI) {
+			ctx.Logger.Errorf("End Session Request failed with error: the post_logout_redirect_uri '%s' is not registered for the client with id '%s'", postLogoutRedirectURI, client.GetID())
+
+			replyOpenIDConnectEndSessionError(ctx, fosite.ErrInvalidRequest.WithHint("The 'post_logout_redirect_uri' parameter does not match any of the registered post logout redirect uris."))
+
+			return
+		}
+
+		uri, _ := url.Parse(postLogoutRedirectURI)
+
+		if state := string(args.Peek(oidc.FormParameterState)); state != "" {
+			query := uri.Query()
+
+			query.Set(oidc.FormParameterState, state)
+
+			uri.RawQuery = query.Encode()
+		}
+
+		redirectURI, hasPostLogoutRedirectURI = uri.String(), true
+	}
+
+	userSession := ctx.GetSession()
+
+	var frontChannelLogoutURIs []*url.URL
+
+	switch {
+	case userSession.IsAnonymous() && len(userSession.OpenIDConnect.FrontChannelLogoutURIs) != 0:
+		frontChannelLogoutURIs = oidcPendingFrontChannelLogoutURIs(ctx, &userSession)
+
+		if !hasPostLogoutRedirectURI && userSession.OpenIDConnect.PostLogoutRedirectURI != "" {
+			redirectURI = userSession.OpenIDConnect.PostLogoutRedirectURI
+		}
+
+		if err = ctx.DestroySession(); err != nil {
+			ctx.Logger.Errorf("End Session Request failed to destroy the session containing the pending front-channel logout uris: %+v", err)
+		}
+	case userSession.IsAnonymous():
+		ctx.Logger.Debug("End Session Request did not terminate a session: the user is not logged in")
+	case hint == nil:
+		ctx.Logger.Warnf("End Session Request did not terminate the session for user '%s': the request did not include an id_token_hint", userSession.Username)
+
+		replyOpenIDConnectEndSessionError(ctx, fosite.ErrInvalidRequest.WithHint("The 'id_token_hint' parameter is required to terminate the session."))
+
+		return
+	case hint.SessionID == "" || hint.SessionID != userSession.OpenIDConnect.SessionID:
+		ctx.Logger.Warnf("End Session Request did not terminate the session for user '%s': the session id of the id_token_hint does not match the current session", userSession.Username)
+
+		replyOpenIDConnectEndSessionError(ctx, fosite.ErrInvalidRequest.WithHint("The 'id_token_hint' parameter was not issued during the current session."))
+
+		return
+	default:
+		frontChannelLogoutURIs = oidcLogoutSession(ctx, issuer, &userSession)
+
+		if err = ctx.DestroySession(); err != nil {
+			ctx.Logger.Errorf("End Session Request failed to destroy the session for user '%s': %+v", userSession.Username, err)
+		}
+	}
+
+	if len(frontChannelLogoutURIs) != 0 && ctx.Providers.OpenIDConnect.WriteLogoutResponse(ctx, redirectURI, frontChannelLogoutURIs) {
+		return
+	}
+
+	ctx.SpecialRedirect(redirectURI, fasthttp.StatusFound)
+}
+
+func replyOpenIDConnectEndSessionError(ctx *middlewares.AutheliaCtx, rfc *fosite.RFC6749Error) {
+	if err := ctx.ReplyJSON(rfc, fasthttp.StatusBadRequest); err != nil {
+		ctx.Logger.Errorf("End Session Request failed to write the error response: %+v", err)
+	}
+}
+
+// oidcPendingFrontChannelLogoutURIs returns the front-channel logout uris of a session terminated by the portal.
+func oidcPendingFrontChannelLogoutURIs(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) (uris []*url.URL) {
+	for _, raw := range userSession.OpenIDConnect.FrontChannelLogoutURIs {
+		uri, err := url.ParseRequestURI(raw)
+		if err != nil {
+			ctx.Logger.Errorf("End Session Request failed to parse the pending front-channel logout uri '%s': %+v", raw, err)
+
+			continue
+		}
+
+		uris = append(uris, uri)
+	}
+
+	return uris
+}
+
+// oidcLogoutSession notifies each client which was issued tokens during the session using the back-channel and returns
+// the front-channel logout uris which the user agent must load.
+func oidcLogoutSession(ctx *middlewares.AutheliaCtx, issuer string, userSession *session.UserSession) (frontChannelLogoutURIs []*url.URL) {
+	if ctx.Providers.OpenIDConnect == nil || userSession.OpenIDConnect.SessionID == "" {
+		return nil
+	}
+
+	var (
+		client *oidc.Client
+		err    error
+	)
+
+	provider, logger, sid, username := ctx.Providers.OpenIDConnect, ctx.Logger, userSession.OpenIDConnect.SessionID, userSession.Username
+
+	var targets []oidc.BackChannelLogoutTarget
+
+	for _, c := range userSession.OpenIDConnect.Clients {
+		if client, err = provider.GetFullClient(ctx, c.ID); err != nil {
+			logger.Errorf("Failed to logout the session for user '%s' from client with id '%s': %+v", username, c.ID, err)
+
+			continue
+		}
+
+		if uri := client.GetFrontChannelLogoutURI(issuer, sid); uri != nil {
+			frontChannelLogoutURIs = append(frontChannelLogoutURIs, uri)
+		}
+
+		if client.BackChannelLogoutURI != "" {
+			targets = append(targets, oidc.BackChannelLogoutTarget{Client: client, Subject: c.Subject})
+		}
+	}
+
+	provider.DispatchBackChannelLogout(issuer, sid, targets, func(target oidc.BackChannelLogoutTarget, err error) {
+		if err != nil {
+			logger.Errorf("Failed to logout the session for user '%s' from client with id '%s' using the back-channel: %+v", username, target.Client.GetID(), err)
+
+			return
+		}
+
+		logger.Debugf("Successfully logged out the session for user '%s' from client with id '%s' using the back-channel", username, target.Client.GetID())
+	})
+
+	return frontChannelLogoutURIs
+}
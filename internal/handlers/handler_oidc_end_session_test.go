@@ -0,0 +1,374 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+const (
+	testEndSessionIssuer   = "https://auth.example.com"
+	testEndSessionClientID = "app"
+	testEndSessionSID      = "8a4f2a34-8b0b-4a3f-9a54-1f0c4e6b3f11"
+)
+
+func setupOpenIDConnectEndSessionTest(t *testing.T, client schema.OpenIDConnectClientConfiguration) *mocks.MockAutheliaCtx {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	client.ID = testEndSessionClientID
+	client.Policy = "one_factor"
+	client.RedirectURIs = []string{"https://app.example.com/callback"}
+
+	mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: key,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		Clients:          []schema.OpenIDConnectClientConfiguration{client},
+	}, mock.StorageMock)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.OpenIDConnect.Config.GetHTTPClient(context.Background()).RetryMax = 0
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+
+	mockActiveSession(mock)
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = 1
+	userSession.OpenIDConnect = session.OpenIDConnectSession{
+		SessionID: testEndSessionSID,
+		Clients:   []session.OpenIDConnectSessionClient{{ID: testEndSessionClientID, Subject: "subject-a"}},
+	}
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	return mock
+}
+
+func newTestIDTokenHint(t *testing.T, mock *mocks.MockAutheliaCtx, sid string) string {
+	token, _, err := mock.Ctx.Providers.OpenIDConnect.KeyManager.Strategy().Generate(context.Background(), jwt.MapClaims{
+		oidc.ClaimIssuer:          testEndSessionIssuer,
+		oidc.ClaimSubject:         "subject-a",
+		oidc.ClaimAudience:        []string{testEndSessionClientID},
+		oidc.ClaimAuthorizedParty: testEndSessionClientID,
+		oidc.ClaimSessionID:       sid,
+	}, &jwt.Headers{Extra: map[string]any{oidc.JWTHeaderKeyIdentifier: mock.Ctx.Providers.OpenIDConnect.KeyManager.GetActiveKeyID()}})
+
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestOpenIDConnectEndSession(t *testing.T) {
+	testCases := []struct {
+		name           string
+		args           func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string
+		expectedStatus int
+		expectedLoc    string
+		expectedBody   string
+		expectedLogout bool
+	}{
+		{
+			"ShouldRejectWithoutIDTokenHint",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return nil
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'id_token_hint' parameter is required to terminate the session."}`,
+			false,
+		},
+		{
+			"ShouldRejectRedirectURIWithoutIDTokenHint",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return map[string]string{
+					oidc.FormParameterClientID:              testEndSessionClientID,
+					oidc.FormParameterPostLogoutRedirectURI: "https://app.example.com/logged-out",
+				}
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'id_token_hint' parameter is required to terminate the session."}`,
+			false,
+		},
+		{
+			"ShouldLogoutAndRedirectToRegisteredURIWithState",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return map[string]string{
+					oidc.FormParameterIDTokenHint:           newTestIDTokenHint(t, mock, testEndSessionSID),
+					oidc.FormParameterClientID:              testEndSessionClientID,
+					oidc.FormParameterPostLogoutRedirectURI: "https://app.example.com/logged-out",
+					oidc.FormParameterState:                 "abc123",
+				}
+			},
+			fasthttp.StatusFound,
+			"https://app.example.com/logged-out?state=abc123",
+			"",
+			true,
+		},
+		{
+			"ShouldLogoutWithIDTokenHint",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return map[string]string{
+					oidc.FormParameterIDTokenHint:           newTestIDTokenHint(t, mock, testEndSessionSID),
+					oidc.FormParameterPostLogoutRedirectURI: "https://app.example.com/logged-out",
+				}
+			},
+			fasthttp.StatusFound,
+			"https://app.example.com/logged-out",
+			"",
+			true,
+		},
+		{
+			"ShouldRejectWhenIDTokenHintSessionDoesNotMatch",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return map[string]string{
+					oidc.FormParameterIDTokenHint: newTestIDTokenHint(t, mock, "another-session"),
+				}
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'id_token_hint' parameter was not issued during the current session."}`,
+			false,
+		},
+		{
+			"ShouldRejectWhenIDTokenHintHasNoSessionID",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return map[string]string{
+					oidc.FormParameterIDTokenHint: newTestIDTokenHint(t, mock, ""),
+				}
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'id_token_hint' parameter was not issued during the current session."}`,
+			false,
+		},
+		{
+			"ShouldRejectLogoutTokenAsIDTokenHint",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				token, err := mock.Ctx.Providers.OpenIDConnect.NewBackChannelLogoutToken(context.Background(), testEndSessionIssuer, testEndSessionClientID, "subject-a", testEndSessionSID)
+				require.NoError(t, err)
+
+				return map[string]string{
+					oidc.FormParameterIDTokenHint: token,
+				}
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'id_token_hint' parameter could not be validated."}`,
+			false,
+		},
+		{
+			"ShouldRejectUnregisteredRedirectURI",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return map[string]string{
+					oidc.FormParameterClientID:              testEndSessionClientID,
+					oidc.FormParameterPostLogoutRedirectURI: "https://evil.example.com/logged-out",
+				}
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'post_logout_redirect_uri' parameter does not match any of the registered post logout redirect uris."}`,
+			false,
+		},
+		{
+			"ShouldRejectRedirectURIWithoutClient",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return map[string]string{
+					oidc.FormParameterPostLogoutRedirectURI: "https://app.example.com/logged-out",
+				}
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'post_logout_redirect_uri' parameter requires either the 'id_token_hint' or 'client_id' parameter."}`,
+			false,
+		},
+		{
+			"ShouldRejectUnknownClient",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				mock.StorageMock.EXPECT().LoadOAuth2Client(gomock.Any(), "unknown").Return(nil, sql.ErrNoRows)
+
+				return map[string]string{
+					oidc.FormParameterClientID: "unknown",
+				}
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The client is not registered."}`,
+			false,
+		},
+		{
+			"ShouldRejectInvalidIDTokenHint",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return map[string]string{
+					oidc.FormParameterIDTokenHint: "not-a-token",
+				}
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'id_token_hint' parameter could not be validated."}`,
+			false,
+		},
+		{
+			"ShouldRejectClientIDNotMatchingIDTokenHint",
+			func(t *testing.T, mock *mocks.MockAutheliaCtx) map[string]string {
+				return map[string]string{
+					oidc.FormParameterIDTokenHint: newTestIDTokenHint(t, mock, testEndSessionSID),
+					oidc.FormParameterClientID:    "other",
+				}
+			},
+			fasthttp.StatusBadRequest,
+			"",
+			`{"error":"invalid_request","error_description":"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. The 'id_token_hint' parameter could not be validated."}`,
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := setupOpenIDConnectEndSessionTest(t, schema.OpenIDConnectClientConfiguration{
+				PostLogoutRedirectURIs: []string{"https://app.example.com/logged-out"},
+			})
+
+			defer mock.Close()
+
+			for k, v := range tc.args(t, mock) {
+				mock.Ctx.QueryArgs().Add(k, v)
+			}
+
+			OpenIDConnectEndSession(mock.Ctx)
+
+			assert.Equal(t, tc.expectedStatus, mock.Ctx.Response.StatusCode())
+			assert.Equal(t, tc.expectedLoc, string(mock.Ctx.Response.Header.Peek(fasthttp.HeaderLocation)))
+
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, string(mock.Ctx.Response.Body()))
+			}
+
+			userSession := mock.Ctx.GetSession()
+
+			assert.Equal(t, tc.expectedLogout, userSession.IsAnonymous())
+		})
+	}
+}
+
+func TestOpenIDConnectEndSessionShouldNotifyClients(t *testing.T) {
+	tokens := make(chan string, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens <- r.PostFormValue(oidc.FormParameterLogoutToken)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer server.Close()
+
+	mock := setupOpenIDConnectEndSessionTest(t, schema.OpenIDConnectClientConfiguration{
+		FrontChannelLogoutURI:             "https://app.example.com/frontchannel",
+		FrontChannelLogoutSessionRequired: true,
+		BackChannelLogoutURI:              server.URL,
+	})
+
+	defer mock.Close()
+
+	var (
+		data    map[string]any
+		sources []string
+	)
+
+	middlewares.AutheliaCtxWithValue(mock.Ctx, oidc.WriteLogoutResponseFnContextKey, func(templateData map[string]any, frameSources []string) {
+		data, sources = templateData, frameSources
+	})
+
+	mock.Ctx.QueryArgs().Add(oidc.FormParameterIDTokenHint, newTestIDTokenHint(t, mock, testEndSessionSID))
+
+	OpenIDConnectEndSession(mock.Ctx)
+
+	userSession := mock.Ctx.GetSession()
+
+	assert.True(t, userSession.IsAnonymous())
+
+	assert.Equal(t, []string{"https://app.example.com"}, sources)
+	assert.Equal(t, "https://auth.example.com/", data["RedirURL"])
+	assert.Equal(t, []string{"https://app.example.com/frontchannel?iss=https%3A%2F%2Fauth.example.com&sid=" + testEndSessionSID}, data["FrontChannelLogoutURIs"])
+
+	select {
+	case token := <-tokens:
+		_, err := mock.Ctx.Providers.OpenIDConnect.KeyManager.Verify(token)
+		assert.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("the back-channel logout token was not received")
+	}
+}
+
+func TestLogoutPOSTShouldPerformFrontChannelLogoutUsingEndSession(t *testing.T) {
+	mock := setupOpenIDConnectEndSessionTest(t, schema.OpenIDConnectClientConfiguration{
+		FrontChannelLogoutURI:             "https://app.example.com/frontchannel",
+		FrontChannelLogoutSessionRequired: true,
+	})
+
+	defer mock.Close()
+
+	mock.Ctx.Request.SetBodyString(`{"targetURL":"https://app.example.com/signed-out"}`)
+
+	LogoutPOST(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.JSONEq(t, `{"status":"OK","data":{"safeTargetURL":true,"frontChannelLogoutURL":"https://auth.example.com/api/oidc/logout"}}`, string(mock.Ctx.Response.Body()))
+
+	userSession := mock.Ctx.GetSession()
+
+	assert.True(t, userSession.IsAnonymous())
+	assert.Equal(t, "https://app.example.com/signed-out", userSession.OpenIDConnect.PostLogoutRedirectURI)
+
+	var (
+		data    map[string]any
+		sources []string
+	)
+
+	middlewares.AutheliaCtxWithValue(mock.Ctx, oidc.WriteLogoutResponseFnContextKey, func(templateData map[string]any, frameSources []string) {
+		data, sources = templateData, frameSources
+	})
+
+	OpenIDConnectEndSession(mock.Ctx)
+
+	assert.Equal(t, []string{"https://app.example.com"}, sources)
+	assert.Equal(t, "https://app.example.com/signed-out", data["RedirURL"])
+	assert.Equal(t, []string{"https://app.example.com/frontchannel?iss=https%3A%2F%2Fauth.example.com&sid=" + testEndSessionSID}, data["FrontChannelLogoutURIs"])
+
+	userSession = mock.Ctx.GetSession()
+
+	assert.Len(t, userSession.OpenIDConnect.FrontChannelLogoutURIs, 0)
+}
+
+func TestLogoutPOSTShouldNotReturnFrontChannelLogoutURLWithoutFrontChannelClients(t *testing.T) {
+	mock := setupOpenIDConnectEndSessionTest(t, schema.OpenIDConnectClientConfiguration{})
+
+	defer mock.Close()
+
+	LogoutPOST(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+	assert.JSONEq(t, `{"status":"OK","data":{"safeTargetURL":false}}`, string(mock.Ctx.Response.Body()))
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+func setupOpenIDConnectPushedAuthorizationRequestTest(t *testing.T) *mocks.MockAutheliaCtx {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: key,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:                      "app",
+				Public:                  true,
+				Policy:                  "one_factor",
+				RedirectURIs:            []string{"https://app.example.com/callback"},
+				Scopes:                  []string{oidc.ScopeOpenID},
+				ResponseTypes:           []string{"code"},
+				TokenEndpointAuthMethod: "none",
+			},
+		},
+	}, mock.StorageMock)
+	require.NoError(t, err)
+
+	return mock
+}
+
+func newOpenIDConnectPushedAuthorizationRequest(form url.Values) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/pushed-authorization-request", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return req
+}
+
+func TestOpenIDConnectPushedAuthorizationRequestShouldStoreRequest(t *testing.T) {
+	mock := setupOpenIDConnectPushedAuthorizationRequestTest(t)
+	defer mock.Close()
+
+	var par model.OAuth2PARContext
+
+	mock.StorageMock.EXPECT().
+		SaveOAuth2PARContext(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ interface{}, ctx model.OAuth2PARContext) error {
+			par = ctx
+
+			return nil
+		})
+
+	rw := httptest.NewRecorder()
+
+	OpenIDConnectPushedAuthorizationRequest(mock.Ctx, rw, newOpenIDConnectPushedAuthorizationRequest(url.Values{
+		oidc.FormParameterClientID: []string{"app"},
+		"response_type":            []string{"code"},
+		"redirect_uri":             []string{"https://app.example.com/callback"},
+		oidc.FormParameterScope:    []string{oidc.ScopeOpenID},
+		oidc.FormParameterState:    []string{"abcdefghijklmnop"},
+	}))
+
+	require.Equal(t, http.StatusCreated, rw.Code)
+
+	response := struct {
+		RequestURI string `json:"request_uri"`
+		ExpiresIn  int    `json:"expires_in"`
+	}{}
+
+	require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &response))
+
+	assert.True(t, strings.HasPrefix(response.RequestURI, "urn:ietf:params:oauth:request_uri:"), response.RequestURI)
+	assert.Greater(t, response.ExpiresIn, 0)
+
+	assert.Equal(t, response.RequestURI, par.Signature)
+	assert.Equal(t, "app", par.ClientID)
+	assert.Equal(t, []string{oidc.ScopeOpenID}, []string(par.Scopes))
+	assert.Contains(t, par.Form, "state=abcdefghijklmnop")
+	assert.False(t, par.Revoked)
+}
+
+func TestOpenIDConnectPushedAuthorizationRequestShouldRejectInvalidRequests(t *testing.T) {
+	testCases := []struct {
+		name     string
+		form     url.Values
+		setup    func(mock *mocks.MockAutheliaCtx)
+		code     int
+		expected string
+	}{
+		{
+			"ShouldRejectUnknownClient",
+			url.Values{
+				oidc.FormParameterClientID: []string{"unknown"},
+				"response_type":            []string{"code"},
+				"redirect_uri":             []string{"https://app.example.com/callback"},
+				oidc.FormParameterScope:    []string{oidc.ScopeOpenID},
+				oidc.FormParameterState:    []string{"abcdefghijklmnop"},
+			},
+			func(mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().
+					LoadOAuth2Client(gomock.Any(), "unknown").
+					Return(nil, sql.ErrNoRows).
+					AnyTimes()
+			},
+			http.StatusUnauthorized,
+			"invalid_client",
+		},
+		{
+			"ShouldRejectRequestURI",
+			url.Values{
+				oidc.FormParameterClientID:   []string{"app"},
+				"response_type":              []string{"code"},
+				"redirect_uri":               []string{"https://app.example.com/callback"},
+				oidc.FormParameterScope:      []string{oidc.ScopeOpenID},
+				oidc.FormParameterState:      []string{"abcdefghijklmnop"},
+				oidc.FormParameterRequestURI: []string{"urn:ietf:params:oauth:request_uri:abc"},
+			},
+			nil,
+			http.StatusBadRequest,
+			"invalid_request",
+		},
+		{
+			"ShouldRejectUnregisteredRedirectURI",
+			url.Values{
+				oidc.FormParameterClientID: []string{"app"},
+				"response_type":            []string{"code"},
+				"redirect_uri":             []string{"https://evil.example.com/callback"},
+				oidc.FormParameterScope:    []string{oidc.ScopeOpenID},
+				oidc.FormParameterState:    []string{"abcdefghijklmnop"},
+			},
+			nil,
+			http.StatusBadRequest,
+			"invalid_request",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := setupOpenIDConnectPushedAuthorizationRequestTest(t)
+			defer mock.Close()
+
+			if tc.setup != nil {
+				tc.setup(mock)
+			}
+
+			rw := httptest.NewRecorder()
+
+			OpenIDConnectPushedAuthorizationRequest(mock.Ctx, rw, newOpenIDConnectPushedAuthorizationRequest(tc.form))
+
+			assert.Equal(t, tc.code, rw.Code)
+			assert.Contains(t, rw.Body.String(), tc.expected)
+		})
+	}
+}
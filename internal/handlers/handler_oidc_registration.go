@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+)
+
+// OpenIDConnectRegistrationPOST handles POST requests to the OAuth 2.0 Dynamic Client Registration endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc7591#section-3.1
+func OpenIDConnectRegistrationPOST(ctx *middlewares.AutheliaCtx) {
+	config := ctx.Configuration.IdentityProviders.OIDC
+
+	if !config.DynamicClientRegistration.Enabled {
+		ctx.ReplyStatusCode(fasthttp.StatusNotFound)
+
+		return
+	}
+
+	token := oidcRegistrationBearerToken(ctx)
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(config.DynamicClientRegistration.InitialAccessToken)) != 1 {
+		ctx.Logger.Errorf("Client Registration Request failed: the initial access token is missing or invalid")
+
+		oidcRegistrationReplyInvalidToken(ctx)
+
+		return
+	}
+
+	var (
+		metadata oidc.ClientRegistrationMetadata
+		err      error
+	)
+
+	if err = json.Unmarshal(ctx.PostBody(), &metadata); err != nil {
+		ctx.Logger.Errorf("Client Registration Request failed: error occurred parsing the body: %+v", err)
+
+		oidcRegistrationReplyError(ctx, oidc.ErrorCodeInvalidClientMetadata, "The client metadata could not be parsed.")
+
+		return
+	}
+
+	client := &model.OAuth2Client{
+		ClientID: uuid.New().String(),
+		Source:   oidc.ClientSourceRegistration,
+	}
+
+	var (
+		secret, registrationToken, signature string
+		registered                           oidc.ClientRegistrationMetadata
+	)
+
+	if registered, secret, err = oidcRegistrationValidate(ctx, client, metadata.WithoutAdministrativeMetadata(), nil); err != nil {
+		ctx.Logger.Errorf("Client Registration Request failed: %+v", err)
+
+		oidcRegistrationReplyError(ctx, oidc.ErrorCodeInvalidClientMetadata, err.Error())
+
+		return
+	}
+
+	registrationToken, signature = oidc.GenerateRegistrationAccessToken()
+
+	client.RegistrationTokenSignature = sql.NullString{String: signature, Valid: true}
+	client.CreatedAt = ctx.Clock.Now()
+	client.UpdatedAt = client.CreatedAt
+
+	if err = ctx.Providers.StorageProvider.SaveOAuth2Client(ctx, *client); err != nil {
+		ctx.Logger.Errorf("Client Registration Request failed for client with id '%s': error occurred saving the client: %+v", client.ClientID, err)
+
+		ctx.ReplyStatusCode(fasthttp.StatusInternalServerError)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Client Registration Request for client with id '%s' was successfully processed", client.ClientID)
+
+	oidcRegistrationReply(ctx, fasthttp.StatusCreated,
+		oidc.NewClientRegistrationResponse(client, registered.WithoutAdministrativeMetadata(), secret, registrationToken, oidcRegistrationClientURI(ctx, client.ClientID)))
+}
+
+// OpenIDConnectRegistrationClientGET handles GET requests to the OAuth 2.0 Dynamic Client Registration Management
+// client configuration endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc7592#section-2.1
+func OpenIDConnectRegistrationClientGET(ctx *middlewares.AutheliaCtx) {
+	client, metadata, ok := oidcRegistrationLoadClient(ctx)
+	if !ok {
+		return
+	}
+
+	oidcRegistrationReply(ctx, fasthttp.StatusOK,
+		oidc.NewClientRegistrationResponse(client, metadata.WithoutAdministrativeMetadata(), "", "", oidcRegistrationClientURI(ctx, client.ClientID)))
+}
+
+// OpenIDConnectRegistrationClientPUT handles PUT requests to the OAuth 2.0 Dynamic Client Registration Management
+// client configuration endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc7592#section-2.2
+func OpenIDConnectRegistrationClientPUT(ctx *middlewares.AutheliaCtx) {
+	client, current, ok := oidcRegistrationLoadClient(ctx)
+	if !ok {
+		return
+	}
+
+	var (
+		request struct {
+			ClientID string `json:"client_id"`
+
+			oidc.ClientRegistrationMetadata
+		}
+		err error
+	)
+
+	if err = json.Unmarshal(ctx.PostBody(), &request); err != nil {
+		ctx.Logger.Errorf("Client Registration Update Request failed for client with id '%s': error occurred parsing the body: %+v", client.ClientID, err)
+
+		oidcRegistrationReplyError(ctx, oidc.ErrorCodeInvalidClientMetadata, "The client metadata could not be parsed.")
+
+		return
+	}
+
+	if request.ClientID != client.ClientID {
+		ctx.Logger.Errorf("Client Registration Update Request failed for client with id '%s': the client id '%s' in the body does not match", client.ClientID, request.ClientID)
+
+		oidcRegistrationReplyError(ctx, oidc.ErrorCodeInvalidClientMetadata, "The client_id in the body must match the client being updated.")
+
+		return
+	}
+
+	metadata := request.ClientRegistrationMetadata.WithoutAdministrativeMetadata()
+
+	metadata.SectorIdentifier, metadata.Policy = current.SectorIdentifier, current.Policy
+	metadata.ConsentMode, metadata.ConsentPreConfiguredDuration = current.ConsentMode, current.ConsentPreConfiguredDuration
+
+	var (
+		secret     string
+		registered oidc.ClientRegistrationMetadata
+	)
+
+	if registered, secret, err = oidcRegistrationValidate(ctx, client, metadata, &current); err != nil {
+		ctx.Logger.Errorf("Client Registration Update Request failed for client with id '%s': %+v", client.ClientID, err)
+
+		oidcRegistrationReplyError(ctx, oidc.ErrorCodeInvalidClientMetadata, err.Error())
+
+		return
+	}
+
+	client.UpdatedAt = ctx.Clock.Now()
+
+	if err = ctx.Providers.StorageProvider.UpdateOAuth2Client(ctx, *client); err != nil {
+		ctx.Logger.Errorf("Client Registration Update Request failed for client with id '%s': error occurred saving the client: %+v", client.ClientID, err)
+
+		ctx.ReplyStatusCode(fasthttp.StatusInternalServerError)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Client Registration Update Request for client with id '%s' was successfully processed", client.ClientID)
+
+	oidcRegistrationReply(ctx, fasthttp.StatusOK,
+		oidc.NewClientRegistrationResponse(client, registered.WithoutAdministrativeMetadata(), secret, "", oidcRegistrationClientURI(ctx, client.ClientID)))
+}
+
+// OpenIDConnectRegistrationClientDELETE handles DELETE requests to the OAuth 2.0 Dynamic Client Registration
+// Management client configuration endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc7592#section-2.3
+func OpenIDConnectRegistrationClientDELETE(ctx *middlewares.AutheliaCtx) {
+	client, _, ok := oidcRegistrationLoadClient(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.Providers.StorageProvider.DeleteOAuth2Client(ctx, client.ClientID); err != nil {
+		ctx.Logger.Errorf("Client Registration Delete Request failed for client with id '%s': error occurred deleting the client: %+v", client.ClientID, err)
+
+		ctx.ReplyStatusCode(fasthttp.StatusInternalServerError)
+
+		return
+	}
+
+	ctx.Logger.Debugf("Client Registration Delete Request for client with id '%s' was successfully processed", client.ClientID)
+
+	ctx.Response.Header.Set(fasthttp.HeaderCacheControl, headerValueNoStore)
+	ctx.Response.Header.Set(fasthttp.HeaderPragma, headerValueNoCache)
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// oidcRegistrationLoadClient loads the client referenced by the client configuration endpoint and ensures the
+// registration access token is valid for it. If false is returned the response has already been written.
+func oidcRegistrationLoadClient(ctx *middlewares.AutheliaCtx) (client *model.OAuth2Client, metadata oidc.ClientRegistrationMetadata, ok bool) {
+	if !ctx.Configuration.IdentityProviders.OIDC.DynamicClientRegistration.Enabled {
+		ctx.ReplyStatusCode(fasthttp.StatusNotFound)
+
+		return nil, metadata, false
+	}
+
+	var err error
+
+	clientID := ctxUserValueString(ctx, userValueKeyClientID)
+
+	token := oidcRegistrationBearerToken(ctx)
+
+	if client, err = ctx.Providers.StorageProvider.LoadOAuth2Client(ctx, clientID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			ctx.Logger.Errorf("Client Registration Management Request failed for client with id '%s': the client does not exist", clientID)
+		} else {
+			ctx.Logger.Errorf("Client Registration Management Request failed for client with id '%s': error occurred loading the client: %+v", clientID, err)
+		}
+
+		oidcRegistrationReplyInvalidToken(ctx)
+
+		return nil, metadata, false
+	}
+
+	if token == "" || !client.HasRegistrationToken() ||
+		subtle.ConstantTimeCompare([]byte(oidc.RegistrationAccessTokenSignature(token)), []byte(client.RegistrationTokenSignature.String)) != 1 {
+		ctx.Logger.Errorf("Client Registration Management Request failed for client with id '%s': the registration access token is missing or invalid", clientID)
+
+		oidcRegistrationReplyInvalidToken(ctx)
+
+		return nil, metadata, false
+	}
+
+	if err = json.Unmarshal(client.Metadata, &metadata); err != nil {
+		ctx.Logger.Errorf("Client Registration Management Request failed for client with id '%s': error occurred parsing the stored metadata: %+v", clientID, err)
+
+		ctx.ReplyStatusCode(fasthttp.StatusInternalServerError)
+
+		return nil, metadata, false
+	}
+
+	return client, metadata, true
+}
+
+// oidcRegistrationValidate validates the requested metadata using the same rules as the configuration and updates the
+// secret and metadata of the client. A new secret is generated if the client requires one and either doesn't have one
+// or the token endpoint auth method changes the type of secret which must be stored.
+func oidcRegistrationValidate(ctx *middlewares.AutheliaCtx, client *model.OAuth2Client, metadata oidc.ClientRegistrationMetadata, current *oidc.ClientRegistrationMetadata) (registered oidc.ClientRegistrationMetadata, secret string, err error) {
+	if metadata.TokenEndpointAuthMethod == "" {
+		metadata.TokenEndpointAuthMethod = oidc.ClientAuthMethodClientSecretBasic
+	}
+
+	var digest *schema.PasswordDigest
+
+	switch metadata.TokenEndpointAuthMethod {
+	case oidc.ClientAuthMethodNone, oidc.ClientAuthMethodPrivateKeyJWT:
+		client.Secret = nil
+	default:
+		if current == nil || len(client.Secret) == 0 ||
+			(current.TokenEndpointAuthMethod == oidc.ClientAuthMethodClientSecretJWT) != (metadata.TokenEndpointAuthMethod == oidc.ClientAuthMethodClientSecretJWT) {
+			if secret, digest, err = oidc.GenerateClientSecret(metadata.TokenEndpointAuthMethod); err != nil {
+				return registered, "", fmt.Errorf("error occurred generating the client secret: %w", err)
+			}
+
+			client.Secret = []byte(digest.Encode())
+		} else if digest, err = schema.DecodePasswordDigest(string(client.Secret)); err != nil {
+			return registered, "", fmt.Errorf("error occurred decoding the client secret: %w", err)
+		}
+	}
+
+	var config schema.OpenIDConnectClientConfiguration
+
+	if config, err = metadata.ToConfiguration(client.ClientID, digest); err != nil {
+		return registered, "", err
+	}
+
+	val := schema.NewStructValidator()
+
+	validator.ValidateIdentityProvidersOpenIDConnectClient(&config, ctx.Configuration.IdentityProviders.OIDC, val)
+
+	if errs := val.Errors(); len(errs) != 0 {
+		descriptions := make([]string, len(errs))
+
+		for i, e := range errs {
+			descriptions[i] = e.Error()
+		}
+
+		return registered, "", errors.New(strings.Join(descriptions, ", "))
+	}
+
+	registered = oidc.NewClientRegistrationMetadata(config)
+
+	if client.Metadata, err = json.Marshal(registered); err != nil {
+		return registered, "", fmt.Errorf("error occurred marshalling the client metadata: %w", err)
+	}
+
+	return registered, secret, nil
+}
+
+func oidcRegistrationBearerToken(ctx *middlewares.AutheliaCtx) (token string) {
+	value := string(ctx.Request.Header.PeekBytes(headerAuthorization))
+
+	if len(value) <= len(prefixAuthorizationBearer) || !strings.EqualFold(value[:len(prefixAuthorizationBearer)], prefixAuthorizationBearer) {
+		return ""
+	}
+
+	return value[len(prefixAuthorizationBearer):]
+}
+
+func oidcRegistrationClientURI(ctx *middlewares.AutheliaCtx, clientID string) string {
+	uri := ctx.RootURL()
+	uri.Path = path.Join(uri.Path, oidc.EndpointPathRegistration, clientID)
+
+	return uri.String()
+}
+
+func oidcRegistrationReply(ctx *middlewares.AutheliaCtx, statusCode int, data any) {
+	ctx.Response.Header.Set(fasthttp.HeaderCacheControl, headerValueNoStore)
+	ctx.Response.Header.Set(fasthttp.HeaderPragma, headerValueNoCache)
+
+	if err := ctx.ReplyJSON(data, statusCode); err != nil {
+		ctx.Logger.Errorf("Unable to set JSON body in response: %+v", err)
+	}
+}
+
+func oidcRegistrationReplyError(ctx *middlewares.AutheliaCtx, code, description string) {
+	oidcRegistrationReply(ctx, fasthttp.StatusBadRequest, oidc.ClientRegistrationErrorResponse{Error: code, ErrorDescription: description})
+}
+
+func oidcRegistrationReplyInvalidToken(ctx *middlewares.AutheliaCtx) {
+	ctx.Response.Header.SetBytesKV(headerWWWAuthenticate, headerValueAuthenticateBearerInvalidToken)
+
+	oidcRegistrationReply(ctx, fasthttp.StatusUnauthorized, oidc.ClientRegistrationErrorResponse{Error: oidc.ErrorCodeInvalidToken})
+}
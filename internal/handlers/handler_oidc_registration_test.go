@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+const (
+	testRegistrationInitialAccessToken = "an-initial-access-token-used-only-for-tests"
+	testRegistrationClientID           = "7d2b5b0e-9a8a-4c8e-9f0a-2f6d4b1c3e5a"
+	testRegistrationAccessToken        = "a-registration-access-token-used-only-for-tests"
+)
+
+func setupOpenIDConnectRegistrationTest(t *testing.T) *mocks.MockAutheliaCtx {
+	mock := mocks.NewMockAutheliaCtx(t)
+
+	mock.Ctx.Configuration.IdentityProviders.OIDC = &schema.OpenIDConnectConfiguration{
+		DynamicClientRegistration: schema.OpenIDConnectDynamicClientRegistrationConfiguration{
+			Enabled:            true,
+			InitialAccessToken: testRegistrationInitialAccessToken,
+		},
+	}
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+
+	return mock
+}
+
+func setOpenIDConnectRegistrationTestRequest(mock *mocks.MockAutheliaCtx, method, token, clientID string, body any) {
+	mock.Ctx.Response.Reset()
+	mock.Ctx.Request.Header.SetMethod(method)
+	mock.Ctx.Request.Header.Del(fasthttp.HeaderAuthorization)
+	mock.Ctx.Request.SetBody(nil)
+
+	if token != "" {
+		mock.Ctx.Request.Header.Set(fasthttp.HeaderAuthorization, token)
+	}
+
+	if clientID != "" {
+		mock.Ctx.SetUserValue(userValueKeyClientID, clientID)
+	}
+
+	if body != nil {
+		data, _ := json.Marshal(body)
+
+		mock.Ctx.Request.SetBody(data)
+	}
+}
+
+func assertOpenIDConnectRegistrationInvalidToken(t *testing.T, mock *mocks.MockAutheliaCtx) {
+	assert.Equal(t, fasthttp.StatusUnauthorized, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, `Bearer error="invalid_token"`, string(mock.Ctx.Response.Header.Peek(fasthttp.HeaderWWWAuthenticate)))
+	assert.Equal(t, headerValueNoStore, string(mock.Ctx.Response.Header.Peek(fasthttp.HeaderCacheControl)))
+
+	response := oidc.ClientRegistrationErrorResponse{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &response))
+	assert.Equal(t, oidc.ErrorCodeInvalidToken, response.Error)
+}
+
+func TestOpenIDConnectRegistrationShouldRespondNotFoundWhenDisabled(t *testing.T) {
+	mock := setupOpenIDConnectRegistrationTest(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.IdentityProviders.OIDC.DynamicClientRegistration.Enabled = false
+
+	setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodPost, "Bearer "+testRegistrationInitialAccessToken, "", map[string]any{})
+
+	OpenIDConnectRegistrationPOST(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusNotFound, mock.Ctx.Response.StatusCode())
+
+	setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodGet, "Bearer "+testRegistrationAccessToken, testRegistrationClientID, nil)
+
+	OpenIDConnectRegistrationClientGET(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusNotFound, mock.Ctx.Response.StatusCode())
+}
+
+func TestOpenIDConnectRegistrationPOSTShouldRejectInvalidInitialAccessToken(t *testing.T) {
+	testCases := []struct {
+		name  string
+		token string
+	}{
+		{"ShouldRejectMissing", ""},
+		{"ShouldRejectEmptyBearer", "Bearer "},
+		{"ShouldRejectWrongToken", "Bearer not-the-initial-access-token"},
+		{"ShouldRejectPrefixOfToken", "Bearer " + testRegistrationInitialAccessToken[:10]},
+		{"ShouldRejectBasicScheme", "Basic " + testRegistrationInitialAccessToken},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := setupOpenIDConnectRegistrationTest(t)
+			defer mock.Close()
+
+			setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodPost, tc.token, "", map[string]any{
+				"redirect_uris": []string{"https://app.example.com/callback"},
+			})
+
+			// The storage mock has no expectations so any attempt to save the client fails the test.
+			OpenIDConnectRegistrationPOST(mock.Ctx)
+
+			assertOpenIDConnectRegistrationInvalidToken(t, mock)
+		})
+	}
+}
+
+func TestOpenIDConnectRegistrationClientShouldRejectInvalidRegistrationAccessToken(t *testing.T) {
+	_, signature := oidc.GenerateRegistrationAccessToken()
+
+	handlers := []struct {
+		name    string
+		method  string
+		handler func(mock *mocks.MockAutheliaCtx)
+	}{
+		{"GET", fasthttp.MethodGet, func(mock *mocks.MockAutheliaCtx) { OpenIDConnectRegistrationClientGET(mock.Ctx) }},
+		{"PUT", fasthttp.MethodPut, func(mock *mocks.MockAutheliaCtx) { OpenIDConnectRegistrationClientPUT(mock.Ctx) }},
+		{"DELETE", fasthttp.MethodDelete, func(mock *mocks.MockAutheliaCtx) { OpenIDConnectRegistrationClientDELETE(mock.Ctx) }},
+	}
+
+	testCases := []struct {
+		name   string
+		token  string
+		client *model.OAuth2Client
+		err    error
+	}{
+		{
+			"ShouldRejectWrongToken",
+			"Bearer " + testRegistrationAccessToken,
+			&model.OAuth2Client{ClientID: testRegistrationClientID, RegistrationTokenSignature: sql.NullString{String: signature, Valid: true}, Metadata: []byte("{}")},
+			nil,
+		},
+		{
+			"ShouldRejectMissingToken",
+			"",
+			&model.OAuth2Client{ClientID: testRegistrationClientID, RegistrationTokenSignature: sql.NullString{String: signature, Valid: true}, Metadata: []byte("{}")},
+			nil,
+		},
+		{
+			"ShouldRejectInitialAccessToken",
+			"Bearer " + testRegistrationInitialAccessToken,
+			&model.OAuth2Client{ClientID: testRegistrationClientID, RegistrationTokenSignature: sql.NullString{String: signature, Valid: true}, Metadata: []byte("{}")},
+			nil,
+		},
+		{
+			"ShouldRejectTokenForClientWithoutRegistrationToken",
+			"Bearer " + testRegistrationAccessToken,
+			&model.OAuth2Client{ClientID: testRegistrationClientID, Source: oidc.ClientSourceCLI, Metadata: []byte("{}")},
+			nil,
+		},
+		{
+			"ShouldRejectUnknownClient",
+			"Bearer " + testRegistrationAccessToken,
+			nil,
+			sql.ErrNoRows,
+		},
+		{
+			"ShouldRejectOnStorageError",
+			"Bearer " + testRegistrationAccessToken,
+			nil,
+			errors.New("bad conn"),
+		},
+	}
+
+	for _, h := range handlers {
+		t.Run(h.name, func(t *testing.T) {
+			for _, tc := range testCases {
+				t.Run(tc.name, func(t *testing.T) {
+					mock := setupOpenIDConnectRegistrationTest(t)
+					defer mock.Close()
+
+					mock.StorageMock.EXPECT().
+						LoadOAuth2Client(mock.Ctx, testRegistrationClientID).
+						Return(tc.client, tc.err)
+
+					setOpenIDConnectRegistrationTestRequest(mock, h.method, tc.token, testRegistrationClientID, map[string]any{
+						"client_id":     testRegistrationClientID,
+						"redirect_uris": []string{"https://app.example.com/callback"},
+					})
+
+					// The storage mock has no other expectations so any attempt to update or delete the client fails the
+					// test.
+					h.handler(mock)
+
+					assertOpenIDConnectRegistrationInvalidToken(t, mock)
+				})
+			}
+		})
+	}
+}
+
+func TestOpenIDConnectRegistrationShouldRegisterUpdateAndDeleteClientWithSQLite(t *testing.T) {
+	mock := setupOpenIDConnectRegistrationTest(t)
+	defer mock.Close()
+
+	// The database/sql package requires a context which can be canceled.
+	mock.Ctx.RequestCtx.Init2(nil, nil, false)
+
+	provider := storage.NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a-long-encryption-key-used-only-for-tests",
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+		},
+	})
+
+	require.NoError(t, provider.StartupCheck())
+
+	defer provider.Close()
+
+	mock.Ctx.Providers.StorageProvider = provider
+
+	setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodPost, "Bearer "+testRegistrationInitialAccessToken, "", map[string]any{
+		"client_name":                   "Example App",
+		"redirect_uris":                 []string{"https://app.example.com/callback"},
+		"scope":                         "openid profile",
+		"authelia_authorization_policy": "one_factor",
+		"sector_identifier":             "example.com",
+	})
+
+	OpenIDConnectRegistrationPOST(mock.Ctx)
+
+	require.Equal(t, fasthttp.StatusCreated, mock.Ctx.Response.StatusCode(), string(mock.Ctx.Response.Body()))
+
+	registered := oidc.ClientRegistrationResponse{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &registered))
+
+	assert.NotEmpty(t, registered.ClientID)
+	assert.NotEmpty(t, registered.ClientSecret)
+	assert.NotEmpty(t, registered.RegistrationAccessToken)
+	assert.True(t, strings.HasSuffix(registered.RegistrationClientURI, "/api/oidc/registration/"+registered.ClientID))
+	assert.Equal(t, "Example App", registered.ClientName)
+	assert.Equal(t, []string{"https://app.example.com/callback"}, registered.RedirectURIs)
+
+	stored, err := provider.LoadOAuth2Client(mock.Ctx, registered.ClientID)
+	require.NoError(t, err)
+
+	assert.Equal(t, oidc.ClientSourceRegistration, stored.Source)
+	assert.Equal(t, oidc.RegistrationAccessTokenSignature(registered.RegistrationAccessToken), stored.RegistrationTokenSignature.String)
+	assert.NotContains(t, string(stored.Secret), registered.ClientSecret)
+
+	client, err := oidc.NewClientFromStorage(stored)
+	require.NoError(t, err)
+
+	// The administrative metadata can't be set by the client.
+	assert.Equal(t, "two_factor", client.Policy.String())
+	assert.Equal(t, "", client.SectorIdentifier)
+
+	setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodGet, "Bearer "+registered.RegistrationAccessToken, registered.ClientID, nil)
+
+	OpenIDConnectRegistrationClientGET(mock.Ctx)
+
+	require.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode(), string(mock.Ctx.Response.Body()))
+
+	current := oidc.ClientRegistrationResponse{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &current))
+
+	assert.Equal(t, registered.ClientID, current.ClientID)
+	assert.Empty(t, current.ClientSecret)
+	assert.Empty(t, current.RegistrationAccessToken)
+	assert.Equal(t, "Example App", current.ClientName)
+
+	setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodPut, "Bearer "+registered.RegistrationAccessToken, registered.ClientID, map[string]any{
+		"client_id":     "another-client",
+		"client_name":   "Example App Renamed",
+		"redirect_uris": []string{"https://app.example.com/callback"},
+	})
+
+	OpenIDConnectRegistrationClientPUT(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusBadRequest, mock.Ctx.Response.StatusCode())
+
+	setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodPut, "Bearer "+registered.RegistrationAccessToken, registered.ClientID, map[string]any{
+		"client_id":     registered.ClientID,
+		"client_name":   "Example App Renamed",
+		"redirect_uris": []string{"https://app.example.com/callback", "https://app.example.com/other"},
+	})
+
+	OpenIDConnectRegistrationClientPUT(mock.Ctx)
+
+	require.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode(), string(mock.Ctx.Response.Body()))
+
+	updated := oidc.ClientRegistrationResponse{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &updated))
+
+	assert.Equal(t, "Example App Renamed", updated.ClientName)
+	assert.Equal(t, []string{"https://app.example.com/callback", "https://app.example.com/other"}, updated.RedirectURIs)
+
+	// The secret is retained when the token endpoint auth method doesn't change the type of secret.
+	assert.Empty(t, updated.ClientSecret)
+
+	updatedStored, err := provider.LoadOAuth2Client(mock.Ctx, registered.ClientID)
+	require.NoError(t, err)
+
+	assert.Equal(t, stored.Secret, updatedStored.Secret)
+	assert.Equal(t, stored.RegistrationTokenSignature, updatedStored.RegistrationTokenSignature)
+
+	setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodPut, "Bearer "+registered.RegistrationAccessToken, registered.ClientID, map[string]any{
+		"client_id":                  registered.ClientID,
+		"redirect_uris":              []string{"https://app.example.com/callback"},
+		"token_endpoint_auth_method": oidc.ClientAuthMethodClientSecretJWT,
+	})
+
+	OpenIDConnectRegistrationClientPUT(mock.Ctx)
+
+	require.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode(), string(mock.Ctx.Response.Body()))
+
+	regenerated := oidc.ClientRegistrationResponse{}
+
+	require.NoError(t, json.Unmarshal(mock.Ctx.Response.Body(), &regenerated))
+
+	// The secret is regenerated when the token endpoint auth method requires a plaintext secret.
+	assert.NotEmpty(t, regenerated.ClientSecret)
+	assert.NotEqual(t, registered.ClientSecret, regenerated.ClientSecret)
+
+	setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodDelete, "Bearer "+registered.RegistrationAccessToken, registered.ClientID, nil)
+
+	OpenIDConnectRegistrationClientDELETE(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusNoContent, mock.Ctx.Response.StatusCode())
+
+	_, err = provider.LoadOAuth2Client(mock.Ctx, registered.ClientID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	setOpenIDConnectRegistrationTestRequest(mock, fasthttp.MethodGet, "Bearer "+registered.RegistrationAccessToken, registered.ClientID, nil)
+
+	OpenIDConnectRegistrationClientGET(mock.Ctx)
+
+	assertOpenIDConnectRegistrationInvalidToken(t, mock)
+}
@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/ory/fosite"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/regulation"
 )
 
 // OpenIDConnectTokenPOST handles POST requests to the OpenID Connect 1.0 Token endpoint.
@@ -26,6 +29,10 @@ func OpenIDConnectTokenPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 
 		ctx.Logger.Errorf("Access Request failed with error: %s", rfc.WithExposeDebug(true).GetDescription())
 
+		if requester != nil && requester.GetClient() != nil && errors.Is(err, fosite.ErrInactiveToken) && requester.GetGrantTypes().ExactOne(oidc.GrantTypeRefreshToken) {
+			markOpenIDConnectRefreshTokenReuse(ctx, requester.GetClient().GetID(), oidcSession)
+		}
+
 		ctx.Providers.OpenIDConnect.WriteAccessError(ctx, rw, requester, err)
 
 		return
@@ -33,14 +40,22 @@ func OpenIDConnectTokenPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 
 	client := requester.GetClient()
 
+	if c, ok := client.(*oidc.Client); ok {
+		middlewares.AutheliaCtxWithValue(ctx, oidc.IDTokenSigningAlgorithmContextKey, c.IDTokenSigningAlgorithm)
+	}
+
 	ctx.Logger.Debugf("Access Request with id '%s' on client with id '%s' is being processed", requester.GetID(), client.GetID())
 
-	// If this is a client_credentials grant, grant all scopes the client is allowed to perform.
-	if requester.GetGrantTypes().ExactOne("client_credentials") {
-		for _, scope := range requester.GetRequestedScopes() {
-			if fosite.HierarchicScopeStrategy(client.GetScopes(), scope) {
-				requester.GrantScope(scope)
-			}
+	// If this is a client_credentials grant, grant all scopes and audiences the client is allowed to request.
+	if requester.GetGrantTypes().ExactOne(oidc.GrantTypeClientCredentials) {
+		if err = oidc.PopulateClientCredentialsFlowRequester(ctx, ctx.Providers.OpenIDConnect.Config, client, requester); err != nil {
+			rfc := fosite.ErrorToRFC6749Error(err)
+
+			ctx.Logger.Errorf("Access Request with id '%s' on client with id '%s' failed with error: %s", requester.GetID(), client.GetID(), rfc.WithExposeDebug(true).GetDescription())
+
+			ctx.Providers.OpenIDConnect.WriteAccessError(ctx, rw, requester, err)
+
+			return
 		}
 	}
 
@@ -62,3 +77,12 @@ func OpenIDConnectTokenPOST(ctx *middlewares.AutheliaCtx, rw http.ResponseWriter
 
 	ctx.Providers.OpenIDConnect.WriteAccessResponse(ctx, rw, requester, responder)
 }
+
+func markOpenIDConnectRefreshTokenReuse(ctx *middlewares.AutheliaCtx, clientID string, session *model.OpenIDSession) {
+	ctx.Logger.Errorf("Security Event: Refresh Token reuse detected on client with id '%s' for user '%s' (subject '%s') from remote address '%s': the Refresh Token was already rotated so every Access Token and Refresh Token issued to the grant has been revoked",
+		clientID, session.Username, session.GetSubject(), ctx.RemoteIP())
+
+	if err := ctx.Providers.Regulator.Mark(ctx, false, false, session.Username, string(ctx.RequestURI()), string(ctx.Method()), regulation.AuthTypeRefreshTokenReuse); err != nil {
+		ctx.Logger.Errorf("Unable to mark the Refresh Token reuse on client with id '%s' for user '%s': %+v", clientID, session.Username, err)
+	}
+}
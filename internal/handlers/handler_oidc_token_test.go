@@ -0,0 +1,377 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestOpenIDConnectTokenPOSTShouldMarkRefreshTokenReuse(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: key,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:                      "app",
+				Public:                  true,
+				Policy:                  "one_factor",
+				RedirectURIs:            []string{"https://app.example.com/callback"},
+				Scopes:                  []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+				GrantTypes:              []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken},
+				TokenEndpointAuthMethod: "none",
+			},
+		},
+	}, mock.StorageMock)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.Regulator = regulation.NewRegulator(schema.RegulationConfiguration{
+		MaxRetries: 3,
+		FindTime:   time.Minute,
+		BanTime:    time.Minute * 5,
+	}, mock.StorageMock, &mock.Clock)
+
+	session := oidc.NewSession()
+	session.Username = testUsername
+	session.Subject = "subject-a"
+
+	data, err := json.Marshal(session)
+	require.NoError(t, err)
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().
+			LoadOAuth2Session(gomock.Any(), storage.OAuth2SessionTypeRefreshToken, "signature").
+			Return(&model.OAuth2Session{
+				RequestID:       "request-a",
+				ClientID:        "app",
+				Signature:       "signature",
+				RequestedAt:     time.Now().Add(-time.Hour),
+				RequestedScopes: []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+				GrantedScopes:   []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+				Active:          false,
+				Session:         data,
+				FamilyCreatedAt: sql.NullTime{Time: time.Now().Add(-time.Hour), Valid: true},
+				RotatedAt:       sql.NullTime{Time: time.Now().Add(-time.Minute), Valid: true},
+			}, nil),
+		mock.StorageMock.EXPECT().
+			BeginTX(gomock.Any()).
+			DoAndReturn(func(ctx context.Context) (context.Context, error) {
+				return ctx, nil
+			}),
+		mock.StorageMock.EXPECT().
+			RevokeOAuth2Session(gomock.Any(), storage.OAuth2SessionTypeRefreshToken, "signature").
+			Return(nil),
+		mock.StorageMock.EXPECT().
+			RevokeOAuth2SessionByRequestID(gomock.Any(), storage.OAuth2SessionTypeRefreshToken, "request-a").
+			Return(nil),
+		mock.StorageMock.EXPECT().
+			RevokeOAuth2SessionByRequestID(gomock.Any(), storage.OAuth2SessionTypeAccessToken, "request-a").
+			Return(nil),
+		mock.StorageMock.EXPECT().
+			Commit(gomock.Any()).
+			Return(nil),
+		mock.StorageMock.EXPECT().
+			AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, attempt model.AuthenticationAttempt) error {
+				assert.Equal(t, regulation.AuthTypeRefreshTokenReuse, attempt.Type)
+				assert.Equal(t, testUsername, attempt.Username)
+				assert.Equal(t, mock.Ctx.RemoteIP().String(), attempt.RemoteIP.IP.String())
+				assert.False(t, attempt.Successful)
+				assert.False(t, attempt.Banned)
+
+				return nil
+			}),
+	)
+
+	form := url.Values{
+		oidc.FormParameterGrantType:    []string{oidc.GrantTypeRefreshToken},
+		oidc.FormParameterClientID:     []string{"app"},
+		oidc.FormParameterRefreshToken: []string{"token.signature"},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rw := httptest.NewRecorder()
+
+	OpenIDConnectTokenPOST(mock.Ctx, rw, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	assert.Contains(t, rw.Body.String(), "token_inactive")
+}
+
+func TestOpenIDConnectTokenPOSTShouldRevokeRefreshTokenFamilyOnReuse(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	// The database/sql package requires a context which can be canceled.
+	mock.Ctx.RequestCtx.Init2(nil, nil, false)
+
+	provider := storage.NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a-long-encryption-key-used-only-for-tests",
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+		},
+	})
+
+	require.NoError(t, provider.StartupCheck())
+
+	defer provider.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey:             key,
+		HMACSecret:                   "asbdhaaskmdlkamdklasmdlkams",
+		RefreshTokenReuseGracePeriod: time.Hour,
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:                      "app",
+				Public:                  true,
+				Policy:                  "one_factor",
+				RedirectURIs:            []string{"https://app.example.com/callback"},
+				Scopes:                  []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+				GrantTypes:              []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken},
+				TokenEndpointAuthMethod: "none",
+				TokenLifespans: schema.OpenIDConnectClientTokenLifespans{
+					RefreshTokenReuseGracePeriod: time.Second * 30,
+				},
+			},
+		},
+	}, provider)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.Regulator = regulation.NewRegulator(schema.RegulationConfiguration{
+		MaxRetries: 3,
+		FindTime:   time.Minute,
+		BanTime:    time.Minute * 5,
+	}, mock.StorageMock, &mock.Clock)
+
+	mock.StorageMock.EXPECT().
+		AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	store := mock.Ctx.Providers.OpenIDConnect.Store
+
+	client, err := store.GetClient(mock.Ctx, "app")
+	require.NoError(t, err)
+
+	subject, challengeID := uuid.New(), uuid.New()
+
+	require.NoError(t, provider.SaveUserOpaqueIdentifier(mock.Ctx, model.UserOpaqueIdentifier{Service: "openid", Username: testUsername, Identifier: subject}))
+	require.NoError(t, provider.SaveOAuth2ConsentSession(mock.Ctx, model.OAuth2ConsentSession{
+		ChallengeID:     challengeID,
+		ClientID:        "app",
+		Subject:         uuid.NullUUID{UUID: subject, Valid: true},
+		Authorized:      true,
+		Granted:         true,
+		RequestedAt:     time.Now().UTC(),
+		RespondedAt:     sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		RequestedScopes: []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+		GrantedScopes:   []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+	}))
+
+	newRequest := func(id string) *fosite.Request {
+		session := oidc.NewSession()
+		session.Username = testUsername
+		session.Subject = subject.String()
+		session.ChallengeID = challengeID
+		session.SetExpiresAt(fosite.RefreshToken, time.Now().Add(time.Hour).UTC())
+		session.SetExpiresAt(fosite.AccessToken, time.Now().Add(time.Hour).UTC())
+
+		request := fosite.NewRequest()
+		request.ID = id
+		request.RequestedAt = time.Now().Add(-time.Hour).UTC()
+		request.Client = client
+		request.Session = session
+		request.RequestedScope = fosite.Arguments{oidc.ScopeOpenID, oidc.ScopeOfflineAccess}
+		request.GrantedScope = fosite.Arguments{oidc.ScopeOpenID, oidc.ScopeOfflineAccess}
+
+		return request
+	}
+
+	require.NoError(t, store.CreateRefreshTokenSession(mock.Ctx, "rotated", newRequest("request-a")))
+	require.NoError(t, store.CreateRefreshTokenSession(mock.Ctx, "current", newRequest("request-a")))
+	require.NoError(t, store.CreateAccessTokenSession(mock.Ctx, "access", newRequest("request-a")))
+	require.NoError(t, store.CreateRefreshTokenSession(mock.Ctx, "other", newRequest("request-b")))
+
+	// The refresh token was rotated after the grace period of the client but within the global grace period.
+	require.NoError(t, provider.RotateOAuth2RefreshTokenSession(mock.Ctx, "rotated", time.Now().Add(-time.Minute).UTC()))
+
+	form := url.Values{
+		oidc.FormParameterGrantType:    []string{oidc.GrantTypeRefreshToken},
+		oidc.FormParameterClientID:     []string{"app"},
+		oidc.FormParameterRefreshToken: []string{"token.rotated"},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rw := httptest.NewRecorder()
+
+	OpenIDConnectTokenPOST(mock.Ctx, rw, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rw.Code)
+	assert.Contains(t, rw.Body.String(), "token_inactive")
+
+	// Every refresh token and access token in the family has been revoked.
+	for _, signature := range []string{"rotated", "current"} {
+		_, err = store.GetRefreshTokenSession(mock.Ctx, signature, oidc.NewSession())
+		assert.ErrorIs(t, err, fosite.ErrNotFound, signature)
+	}
+
+	_, err = store.GetAccessTokenSession(mock.Ctx, "access", oidc.NewSession())
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	// Other families are not affected.
+	_, err = store.GetRefreshTokenSession(mock.Ctx, "other", oidc.NewSession())
+	assert.NoError(t, err)
+}
+
+func TestOpenIDConnectTokenPOSTShouldNotRevokeAccessTokensOnReuseWithinGracePeriod(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	// The database/sql package requires a context which can be canceled.
+	mock.Ctx.RequestCtx.Init2(nil, nil, false)
+
+	provider := storage.NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a-long-encryption-key-used-only-for-tests",
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+		},
+	})
+
+	require.NoError(t, provider.StartupCheck())
+
+	defer provider.Close()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey:             key,
+		HMACSecret:                   "asbdhaaskmdlkamdklasmdlkams",
+		RefreshTokenReuseGracePeriod: time.Minute,
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:                      "app",
+				Public:                  true,
+				Policy:                  "one_factor",
+				RedirectURIs:            []string{"https://app.example.com/callback"},
+				Scopes:                  []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+				GrantTypes:              []string{oidc.GrantTypeAuthorizationCode, oidc.GrantTypeRefreshToken},
+				TokenEndpointAuthMethod: "none",
+			},
+		},
+	}, provider)
+	require.NoError(t, err)
+
+	store := mock.Ctx.Providers.OpenIDConnect.Store
+
+	client, err := store.GetClient(mock.Ctx, "app")
+	require.NoError(t, err)
+
+	subject, challengeID := uuid.New(), uuid.New()
+
+	require.NoError(t, provider.SaveUserOpaqueIdentifier(mock.Ctx, model.UserOpaqueIdentifier{Service: "openid", Username: testUsername, Identifier: subject}))
+	require.NoError(t, provider.SaveOAuth2ConsentSession(mock.Ctx, model.OAuth2ConsentSession{
+		ChallengeID:     challengeID,
+		ClientID:        "app",
+		Subject:         uuid.NullUUID{UUID: subject, Valid: true},
+		Authorized:      true,
+		Granted:         true,
+		RequestedAt:     time.Now().UTC(),
+		RespondedAt:     sql.NullTime{Time: time.Now().UTC(), Valid: true},
+		RequestedScopes: []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+		GrantedScopes:   []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+	}))
+
+	session := oidc.NewSession()
+	session.Username = testUsername
+	session.Subject = subject.String()
+	session.ChallengeID = challengeID
+	session.Claims.Subject = subject.String()
+	session.SetExpiresAt(fosite.RefreshToken, time.Now().Add(time.Hour).UTC())
+
+	request := fosite.NewRequest()
+	request.ID = "request-a"
+	request.RequestedAt = time.Now().Add(-time.Hour).UTC()
+	request.Client = client
+	request.Session = session
+	request.RequestedScope = fosite.Arguments{oidc.ScopeOpenID, oidc.ScopeOfflineAccess}
+	request.GrantedScope = fosite.Arguments{oidc.ScopeOpenID, oidc.ScopeOfflineAccess}
+
+	refreshToken, signature, err := mock.Ctx.Providers.OpenIDConnect.Config.Strategy.Core.GenerateRefreshToken(mock.Ctx, request)
+	require.NoError(t, err)
+
+	require.NoError(t, store.CreateRefreshTokenSession(mock.Ctx, signature, request))
+
+	form := url.Values{
+		oidc.FormParameterGrantType:    []string{oidc.GrantTypeRefreshToken},
+		oidc.FormParameterClientID:     []string{"app"},
+		oidc.FormParameterRefreshToken: []string{refreshToken},
+	}
+
+	var accessTokens []string
+
+	// The second refresh reuses the refresh token after the first one rotated it, as happens when a client refreshes
+	// the same refresh token concurrently.
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/token", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		rw := httptest.NewRecorder()
+
+		OpenIDConnectTokenPOST(mock.Ctx, rw, req)
+
+		require.Equal(t, http.StatusOK, rw.Code, rw.Body.String())
+
+		response := struct {
+			AccessToken  string `json:"access_token"`
+			RefreshToken string `json:"refresh_token"`
+		}{}
+
+		require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &response))
+		require.NotEmpty(t, response.AccessToken)
+		require.NotEmpty(t, response.RefreshToken)
+
+		accessTokens = append(accessTokens, response.AccessToken)
+	}
+
+	require.NotEqual(t, accessTokens[0], accessTokens[1])
+
+	for _, accessToken := range accessTokens {
+		tokenType, _, err := mock.Ctx.Providers.OpenIDConnect.IntrospectToken(mock.Ctx, accessToken, fosite.AccessToken, oidc.NewSession())
+		assert.NoError(t, err)
+		assert.Equal(t, fosite.AccessToken, tokenType)
+	}
+}
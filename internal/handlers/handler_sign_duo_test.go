@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/duo"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
@@ -97,7 +98,7 @@ func (s *SecondFactorDuoPostSuite) TestShouldAutoSelect() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeDuo,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		})).
 		Return(nil)
 
@@ -117,6 +118,11 @@ func (s *SecondFactorDuoPostSuite) TestShouldAutoSelect() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	DuoPOST(duoMock)(s.mock.Ctx)
 	assert.Equal(s.T(), 200, s.mock.Ctx.Response.StatusCode())
 }
@@ -286,7 +292,7 @@ func (s *SecondFactorDuoPostSuite) TestShouldUseInvalidMethodAndAutoSelect() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeDuo,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		})).
 		Return(nil)
 
@@ -323,6 +329,11 @@ func (s *SecondFactorDuoPostSuite) TestShouldUseInvalidMethodAndAutoSelect() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	DuoPOST(duoMock)(s.mock.Ctx)
 	assert.Equal(s.T(), 200, s.mock.Ctx.Response.StatusCode())
 }
@@ -346,6 +357,11 @@ func (s *SecondFactorDuoPostSuite) TestShouldCallDuoPreauthAPIAndAllowAccess() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	DuoPOST(duoMock)(s.mock.Ctx)
 
 	assert.Equal(s.T(), 200, s.mock.Ctx.Response.StatusCode())
@@ -414,7 +430,7 @@ func (s *SecondFactorDuoPostSuite) TestShouldCallDuoAPIAndDenyAccess() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeDuo,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		})).
 		Return(nil)
 
@@ -497,7 +513,7 @@ func (s *SecondFactorDuoPostSuite) TestShouldRedirectUserToDefaultURL() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeDuo,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		})).
 		Return(nil)
 
@@ -525,6 +541,11 @@ func (s *SecondFactorDuoPostSuite) TestShouldRedirectUserToDefaultURL() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	DuoPOST(duoMock)(s.mock.Ctx)
 	s.mock.Assert200OK(s.T(), redirectResponse{
 		Redirect: testRedirectionURL,
@@ -546,7 +567,7 @@ func (s *SecondFactorDuoPostSuite) TestShouldNotReturnRedirectURL() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeDuo,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		})).
 		Return(nil)
 
@@ -572,6 +593,11 @@ func (s *SecondFactorDuoPostSuite) TestShouldNotReturnRedirectURL() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	DuoPOST(duoMock)(s.mock.Ctx)
 	s.mock.Assert200OK(s.T(), nil)
 }
@@ -591,7 +617,7 @@ func (s *SecondFactorDuoPostSuite) TestShouldRedirectUserToSafeTargetURL() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeDuo,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		})).
 		Return(nil)
 
@@ -619,6 +645,11 @@ func (s *SecondFactorDuoPostSuite) TestShouldRedirectUserToSafeTargetURL() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	DuoPOST(duoMock)(s.mock.Ctx)
 	s.mock.Assert200OK(s.T(), redirectResponse{
 		Redirect: "https://example.com",
@@ -640,7 +671,7 @@ func (s *SecondFactorDuoPostSuite) TestShouldNotRedirectToUnsafeURL() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeDuo,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		})).
 		Return(nil)
 
@@ -668,6 +699,11 @@ func (s *SecondFactorDuoPostSuite) TestShouldNotRedirectToUnsafeURL() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	DuoPOST(duoMock)(s.mock.Ctx)
 	s.mock.Assert200OK(s.T(), nil)
 }
@@ -687,7 +723,7 @@ func (s *SecondFactorDuoPostSuite) TestShouldRegenerateSessionForPreventingSessi
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeDuo,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		})).
 		Return(nil)
 
@@ -715,6 +751,11 @@ func (s *SecondFactorDuoPostSuite) TestShouldRegenerateSessionForPreventingSessi
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	r := regexp.MustCompile("^authelia_session=(.*); path=")
 	res := r.FindAllStringSubmatch(string(s.mock.Ctx.Response.Header.PeekCookie("authelia_session")), -1)
 
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/regulation"
@@ -48,7 +49,7 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToDefaultURL() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeTOTP,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		}))
 
 	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
@@ -65,6 +66,11 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToDefaultURL() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
 	s.mock.Assert200OK(s.T(), redirectResponse{
 		Redirect: testRedirectionURL,
@@ -86,7 +92,7 @@ func (s *HandlerSignTOTPSuite) TestShouldFailWhenTOTPSignInInfoFailsToUpdate() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeTOTP,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		}))
 
 	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
@@ -122,7 +128,7 @@ func (s *HandlerSignTOTPSuite) TestShouldNotReturnRedirectURL() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeTOTP,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		}))
 
 	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
@@ -137,6 +143,11 @@ func (s *HandlerSignTOTPSuite) TestShouldNotReturnRedirectURL() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
 	s.mock.Assert200OK(s.T(), nil)
 }
@@ -156,7 +167,7 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToSafeTargetURL() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeTOTP,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		}))
 
 	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
@@ -173,6 +184,11 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToSafeTargetURL() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
 	s.mock.Assert200OK(s.T(), redirectResponse{
 		Redirect: "https://mydomain.example.com",
@@ -192,7 +208,7 @@ func (s *HandlerSignTOTPSuite) TestShouldNotRedirectToUnsafeURL() {
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeTOTP,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		}))
 
 	s.mock.StorageMock.
@@ -211,6 +227,11 @@ func (s *HandlerSignTOTPSuite) TestShouldNotRedirectToUnsafeURL() {
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
 	s.mock.Assert200OK(s.T(), nil)
 }
@@ -230,7 +251,7 @@ func (s *HandlerSignTOTPSuite) TestShouldRegenerateSessionForPreventingSessionFi
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeTOTP,
-			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			RemoteIP:   model.NewNullIPFromString("127.0.0.1"),
 		}))
 
 	s.mock.TOTPMock.EXPECT().
@@ -247,6 +268,11 @@ func (s *HandlerSignTOTPSuite) TestShouldRegenerateSessionForPreventingSessionFi
 	s.Require().NoError(err)
 	s.mock.Ctx.Request.SetBody(bodyBytes)
 
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateActiveSessionAuthLevel(s.mock.Ctx, s.mock.Ctx.GetSessionIDHash(), gomock.Any(), int(authentication.TwoFactor)).
+		Return(nil)
+
 	r := regexp.MustCompile("^authelia_session=(.*); path=")
 	res := r.FindAllStringSubmatch(string(s.mock.Ctx.Response.Header.PeekCookie("authelia_session")), -1)
 
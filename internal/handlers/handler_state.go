@@ -1,12 +1,25 @@
 package handlers
 
 import (
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 )
 
 // StateGET is the handler serving the user state.
 func StateGET(ctx *middlewares.AutheliaCtx) {
 	userSession := ctx.GetSession()
+
+	// The portal requests the state whenever it's loaded which makes it the response the anti-replay token is rotated on.
+	if userSession.AuthenticationLevel != authentication.NotAuthenticated {
+		if provider, err := ctx.GetSessionProvider(); err != nil {
+			ctx.Logger.Errorf("Unable to retrieve the session provider to rotate the anti-replay token for user '%s': %+v", userSession.Username, err)
+		} else if rotateSessionAntiReplayToken(ctx, provider, &userSession) {
+			if err = ctx.SaveSession(userSession); err != nil {
+				ctx.Logger.Errorf("Unable to save the rotated anti-replay token for user '%s': %+v", userSession.Username, err)
+			}
+		}
+	}
+
 	stateResponse := StateResponse{
 		Username:              userSession.Username,
 		AuthenticationLevel:   userSession.AuthenticationLevel,
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// UserOIDCConsentsGET returns the OpenID Connect 1.0 consents the current user has granted which have neither been
+// revoked nor expired. This includes both the pre-configured consents and the one-time consents which still have an
+// active refresh token.
+func UserOIDCConsentsGET(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	configs, consents, err := loadOIDCConsents(ctx, userSession.Username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the oidc consents for user '%s': %w", userSession.Username, err), messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(newOIDCConsentsResponse(ctx, configs, consents)); err != nil {
+		ctx.Logger.Errorf(logFmtErrWriteResponseBody, "user oidc consents", userSession.Username, err)
+	}
+}
+
+// UserOIDCConsentDELETE revokes one of the OpenID Connect 1.0 consents of the current user and the access tokens and
+// refresh tokens which were issued using it. The type query argument selects between pre-configured consents, which
+// is the default, and one-time consents.
+func UserOIDCConsentDELETE(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	id, err := strconv.ParseInt(ctxUserValueString(ctx, userValueKeyConsentID), 10, 64)
+	if err != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	consentType := string(ctx.QueryArgs().PeekBytes(qryArgType))
+
+	switch consentType {
+	case "":
+		consentType = oidcConsentTypePreConfiguration
+	case oidcConsentTypePreConfiguration, oidcConsentTypeSession:
+		break
+	default:
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	configs, consents, err := loadOIDCConsents(ctx, userSession.Username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the oidc consents for user '%s': %w", userSession.Username, err), messageOperationFailed)
+
+		return
+	}
+
+	clientID, found := findOIDCConsentClientID(configs, consents, consentType, id)
+	if !found {
+		ctx.SetStatusCode(fasthttp.StatusNotFound)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = revokeOIDCConsent(ctx, consentType, id); err != nil {
+		ctx.Error(fmt.Errorf("unable to revoke the oidc %s consent with id '%d' for user '%s': %w", consentType, id, userSession.Username, err), messageOperationFailed)
+
+		return
+	}
+
+	ctx.Logger.Infof("OpenID Connect 1.0 %s consent with id '%d' for client '%s' and user '%s' has been revoked by the user", consentType, id, clientID, userSession.Username)
+
+	ctx.ReplyOK()
+}
+
+func loadOIDCConsents(ctx *middlewares.AutheliaCtx, username string) (configs []model.OAuth2ConsentPreConfig, consents []model.OAuth2ConsentSession, err error) {
+	if configs, err = ctx.Providers.StorageProvider.LoadOAuth2ConsentPreConfigurationsByUsername(ctx, username); err != nil {
+		return nil, nil, err
+	}
+
+	if consents, err = ctx.Providers.StorageProvider.LoadOAuth2ConsentSessionsByUsername(ctx, username); err != nil {
+		return nil, nil, err
+	}
+
+	return configs, consents, nil
+}
+
+// findOIDCConsentClientID returns the client id of the consent with the given type and id, if the user has it.
+func findOIDCConsentClientID(configs []model.OAuth2ConsentPreConfig, consents []model.OAuth2ConsentSession, consentType string, id int64) (clientID string, found bool) {
+	switch consentType {
+	case oidcConsentTypeSession:
+		for _, consent := range consents {
+			if int64(consent.ID) == id {
+				return consent.ClientID, true
+			}
+		}
+	default:
+		for _, config := range configs {
+			if config.ID == id {
+				return config.ClientID, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// revokeOIDCConsent revokes a consent and the access tokens and refresh tokens which were issued using it in a single
+// transaction. Pre-configured consents are revoked themselves, one-time consents are revoked by revoking their tokens.
+func revokeOIDCConsent(ctx *middlewares.AutheliaCtx, consentType string, id int64) (err error) {
+	provider := ctx.Providers.StorageProvider
+
+	var c context.Context
+
+	if c, err = provider.BeginTX(ctx); err != nil {
+		return fmt.Errorf("error beginning transaction: %w", err)
+	}
+
+	switch consentType {
+	case oidcConsentTypeSession:
+		err = provider.RevokeOAuth2SessionsByConsentSession(c, int(id))
+	default:
+		if err = provider.RevokeOAuth2ConsentPreConfiguration(c, id); err == nil {
+			err = provider.RevokeOAuth2SessionsByConsentPreConfiguration(c, id)
+		}
+	}
+
+	if err != nil {
+		if rerr := provider.Rollback(c); rerr != nil {
+			ctx.Logger.Errorf("Error occurred rolling back the revocation of the oidc consent with id '%d': %+v", id, rerr)
+		}
+
+		return err
+	}
+
+	if err = provider.Commit(c); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+func newOIDCConsentsResponse(ctx *middlewares.AutheliaCtx, configs []model.OAuth2ConsentPreConfig, consents []model.OAuth2ConsentSession) (response []oidcConsentResponse) {
+	response = make([]oidcConsentResponse, 0, len(configs)+len(consents))
+
+	for i, config := range configs {
+		item := oidcConsentResponse{
+			ID:        config.ID,
+			Type:      oidcConsentTypePreConfiguration,
+			ClientID:  config.ClientID,
+			Scopes:    config.Scopes,
+			Audience:  config.Audience,
+			CreatedAt: config.CreatedAt,
+		}
+
+		if config.ExpiresAt.Valid {
+			item.ExpiresAt = &configs[i].ExpiresAt.Time
+		}
+
+		response = append(response, item)
+	}
+
+	for _, consent := range consents {
+		response = append(response, oidcConsentResponse{
+			ID:        int64(consent.ID),
+			Type:      oidcConsentTypeSession,
+			ClientID:  consent.ClientID,
+			Scopes:    consent.GrantedScopes,
+			Audience:  consent.GrantedAudience,
+			CreatedAt: consent.RespondedAt.Time,
+		})
+	}
+
+	if ctx.Providers.OpenIDConnect == nil {
+		return response
+	}
+
+	for i := range response {
+		if client, err := ctx.Providers.OpenIDConnect.GetFullClient(ctx, response[i].ClientID); err == nil {
+			response[i].ClientDescription = client.Description
+		}
+	}
+
+	return response
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestUserOIDCConsentsGET(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	setUserSessionsTestSession(t, mock)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	mock.Ctx.Providers.OpenIDConnect, err = oidc.NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: key,
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{ID: "app", Description: "Example App", Policy: "one_factor", RedirectURIs: []string{"https://app.example.com/callback"}},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	created := time.Unix(1600000000, 0).UTC()
+	expires := created.Add(time.Hour * 24)
+
+	mock.StorageMock.EXPECT().
+		LoadOAuth2ConsentPreConfigurationsByUsername(mock.Ctx, testUsername).
+		Return([]model.OAuth2ConsentPreConfig{
+			{ID: 1, ClientID: "app", CreatedAt: created, ExpiresAt: sql.NullTime{Time: expires, Valid: true}, Scopes: []string{oidc.ScopeOpenID, oidc.ScopeProfile}, Audience: []string{"app"}},
+			{ID: 2, ClientID: "unknown", CreatedAt: created, Scopes: []string{oidc.ScopeOpenID}, Audience: []string{}},
+		}, nil)
+
+	mock.StorageMock.EXPECT().
+		LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername).
+		Return([]model.OAuth2ConsentSession{
+			{ID: 7, ClientID: "app", RespondedAt: sql.NullTime{Time: created, Valid: true}, GrantedScopes: []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess}, GrantedAudience: []string{"app"}},
+		}, nil)
+
+	UserOIDCConsentsGET(mock.Ctx)
+
+	mock.Assert200OK(t, []oidcConsentResponse{
+		{ID: 1, Type: oidcConsentTypePreConfiguration, ClientID: "app", ClientDescription: "Example App", Scopes: []string{oidc.ScopeOpenID, oidc.ScopeProfile}, Audience: []string{"app"}, CreatedAt: created, ExpiresAt: &expires},
+		{ID: 2, Type: oidcConsentTypePreConfiguration, ClientID: "unknown", Scopes: []string{oidc.ScopeOpenID}, Audience: []string{}, CreatedAt: created},
+		{ID: 7, Type: oidcConsentTypeSession, ClientID: "app", ClientDescription: "Example App", Scopes: []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess}, Audience: []string{"app"}, CreatedAt: created},
+	})
+}
+
+func TestUserOIDCConsentsGETShouldHandleStorageError(t *testing.T) {
+	testCases := []struct {
+		name  string
+		setup func(mock *mocks.MockAutheliaCtx)
+	}{
+		{
+			name: "PreConfigurations",
+			setup: func(mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentPreConfigurationsByUsername(mock.Ctx, testUsername).Return(nil, errors.New("failed to connect"))
+			},
+		},
+		{
+			name: "Sessions",
+			setup: func(mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentPreConfigurationsByUsername(mock.Ctx, testUsername).Return(nil, nil)
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername).Return(nil, errors.New("failed to connect"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			setUserSessionsTestSession(t, mock)
+
+			tc.setup(mock)
+
+			UserOIDCConsentsGET(mock.Ctx)
+
+			mock.Assert200KO(t, messageOperationFailed)
+			assert.Equal(t, "unable to load the oidc consents for user 'john': failed to connect", mock.Hook.LastEntry().Message)
+		})
+	}
+}
+
+func TestUserOIDCConsentDELETE(t *testing.T) {
+	configs := []model.OAuth2ConsentPreConfig{
+		{ID: 4, ClientID: "app", Scopes: []string{oidc.ScopeOpenID}},
+	}
+
+	consents := []model.OAuth2ConsentSession{
+		{ID: 9, ClientID: "app", GrantedScopes: []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess}},
+	}
+
+	type txKey struct{}
+
+	testCases := []struct {
+		name     string
+		have     string
+		typ      string
+		setup    func(mock *mocks.MockAutheliaCtx)
+		expected int
+	}{
+		{
+			name: "ShouldRevokeConsentAndTokens",
+			have: "4",
+			setup: func(mock *mocks.MockAutheliaCtx) {
+				tx := context.WithValue(mock.Ctx, txKey{}, true)
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadOAuth2ConsentPreConfigurationsByUsername(mock.Ctx, testUsername).Return(configs, nil),
+					mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername).Return(consents, nil),
+					mock.StorageMock.EXPECT().BeginTX(mock.Ctx).Return(tx, nil),
+					mock.StorageMock.EXPECT().RevokeOAuth2ConsentPreConfiguration(tx, int64(4)).Return(nil),
+					mock.StorageMock.EXPECT().RevokeOAuth2SessionsByConsentPreConfiguration(tx, int64(4)).Return(nil),
+					mock.StorageMock.EXPECT().Commit(tx).Return(nil),
+				)
+			},
+			expected: fasthttp.StatusOK,
+		},
+		{
+			name: "ShouldRevokeOneTimeConsentTokens",
+			have: "9",
+			typ:  oidcConsentTypeSession,
+			setup: func(mock *mocks.MockAutheliaCtx) {
+				tx := context.WithValue(mock.Ctx, txKey{}, true)
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadOAuth2ConsentPreConfigurationsByUsername(mock.Ctx, testUsername).Return(configs, nil),
+					mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername).Return(consents, nil),
+					mock.StorageMock.EXPECT().BeginTX(mock.Ctx).Return(tx, nil),
+					mock.StorageMock.EXPECT().RevokeOAuth2SessionsByConsentSession(tx, 9).Return(nil),
+					mock.StorageMock.EXPECT().Commit(tx).Return(nil),
+				)
+			},
+			expected: fasthttp.StatusOK,
+		},
+		{
+			name:     "ShouldRespondBadRequestOnInvalidID",
+			have:     "abc",
+			expected: fasthttp.StatusBadRequest,
+		},
+		{
+			name:     "ShouldRespondBadRequestOnInvalidType",
+			have:     "4",
+			typ:      "abc",
+			expected: fasthttp.StatusBadRequest,
+		},
+		{
+			name: "ShouldRespondNotFoundOnConsentOfAnotherUser",
+			have: "5",
+			setup: func(mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentPreConfigurationsByUsername(mock.Ctx, testUsername).Return(configs, nil)
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername).Return(consents, nil)
+			},
+			expected: fasthttp.StatusNotFound,
+		},
+		{
+			name: "ShouldRespondNotFoundOnConsentOfAnotherType",
+			have: "9",
+			setup: func(mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentPreConfigurationsByUsername(mock.Ctx, testUsername).Return(configs, nil)
+				mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername).Return(consents, nil)
+			},
+			expected: fasthttp.StatusNotFound,
+		},
+		{
+			name: "ShouldRollbackOnRevokeError",
+			have: "4",
+			setup: func(mock *mocks.MockAutheliaCtx) {
+				tx := context.WithValue(mock.Ctx, txKey{}, true)
+
+				gomock.InOrder(
+					mock.StorageMock.EXPECT().LoadOAuth2ConsentPreConfigurationsByUsername(mock.Ctx, testUsername).Return(configs, nil),
+					mock.StorageMock.EXPECT().LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername).Return(consents, nil),
+					mock.StorageMock.EXPECT().BeginTX(mock.Ctx).Return(tx, nil),
+					mock.StorageMock.EXPECT().RevokeOAuth2ConsentPreConfiguration(tx, int64(4)).Return(nil),
+					mock.StorageMock.EXPECT().RevokeOAuth2SessionsByConsentPreConfiguration(tx, int64(4)).Return(errors.New("failed to connect")),
+					mock.StorageMock.EXPECT().Rollback(tx).Return(nil),
+				)
+			},
+			expected: fasthttp.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			setUserSessionsTestSession(t, mock)
+
+			if tc.setup != nil {
+				tc.setup(mock)
+			}
+
+			mock.Ctx.SetUserValue(userValueKeyConsentID, tc.have)
+
+			if tc.typ != "" {
+				mock.Ctx.QueryArgs().Set(queryArgType, tc.typ)
+			}
+
+			UserOIDCConsentDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expected, mock.Ctx.Response.StatusCode())
+		})
+	}
+}
+
+func TestUserOIDCConsentsShouldListAndRevokeOneTimeConsentsWithSQLite(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	setUserSessionsTestSession(t, mock)
+
+	// The database/sql package requires a context which can be canceled.
+	mock.Ctx.RequestCtx.Init2(nil, nil, false)
+
+	provider := storage.NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a-long-encryption-key-used-only-for-tests",
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+		},
+	})
+
+	require.NoError(t, provider.StartupCheck())
+
+	defer provider.Close()
+
+	mock.Ctx.Providers.StorageProvider = provider
+
+	subject := uuid.New()
+	challengeID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, provider.SaveUserOpaqueIdentifier(mock.Ctx, model.UserOpaqueIdentifier{Service: "openid", Username: testUsername, Identifier: subject}))
+	require.NoError(t, provider.SaveOAuth2ConsentSession(mock.Ctx, model.OAuth2ConsentSession{
+		ChallengeID:     challengeID,
+		ClientID:        "app",
+		Subject:         uuid.NullUUID{UUID: subject, Valid: true},
+		Authorized:      true,
+		Granted:         true,
+		RequestedAt:     now,
+		RespondedAt:     sql.NullTime{Time: now, Valid: true},
+		RequestedScopes: []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+		GrantedScopes:   []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+	}))
+
+	for _, sessionType := range []storage.OAuth2SessionType{storage.OAuth2SessionTypeAccessToken, storage.OAuth2SessionTypeRefreshToken} {
+		require.NoError(t, provider.SaveOAuth2Session(mock.Ctx, sessionType, model.OAuth2Session{
+			ChallengeID:   uuid.NullUUID{UUID: challengeID, Valid: true},
+			RequestID:     "req1",
+			ClientID:      "app",
+			Signature:     "sig-" + sessionType.String(),
+			RequestedAt:   now,
+			Subject:       sql.NullString{String: subject.String(), Valid: true},
+			GrantedScopes: []string{oidc.ScopeOpenID, oidc.ScopeOfflineAccess},
+			Active:        true,
+			Session:       []byte("{}"),
+		}))
+	}
+
+	consents, err := provider.LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername)
+	require.NoError(t, err)
+	require.Len(t, consents, 1)
+
+	mock.Ctx.SetUserValue(userValueKeyConsentID, strconv.Itoa(consents[0].ID))
+	mock.Ctx.QueryArgs().Set(queryArgType, oidcConsentTypeSession)
+
+	UserOIDCConsentDELETE(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	for _, sessionType := range []storage.OAuth2SessionType{storage.OAuth2SessionTypeAccessToken, storage.OAuth2SessionTypeRefreshToken} {
+		_, err = provider.LoadOAuth2Session(mock.Ctx, sessionType, "sig-"+sessionType.String())
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+	}
+
+	consents, err = provider.LoadOAuth2ConsentSessionsByUsername(mock.Ctx, testUsername)
+	require.NoError(t, err)
+	assert.Len(t, consents, 0)
+}
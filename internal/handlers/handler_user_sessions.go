@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// UserSessionsGET returns the active sessions of the current user.
+func UserSessionsGET(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	sessions, err := ctx.Providers.StorageProvider.LoadActiveSessionsByUsername(ctx, userSession.Username, ctx.Clock.Now())
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the active sessions for user '%s': %w", userSession.Username, err), messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(newActiveSessionsResponse(sessions, ctx.GetSessionIDHash())); err != nil {
+		ctx.Logger.Errorf(logFmtErrWriteResponseBody, "user sessions", userSession.Username, err)
+	}
+}
+
+// UserSessionDELETE revokes one of the active sessions of the current user.
+func UserSessionDELETE(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	id, err := strconv.Atoi(ctxUserValueString(ctx, userValueKeySessionID))
+	if err != nil {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+		ctx.SetJSONError(messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.RevokeActiveSessionByID(ctx, userSession.Username, id); err != nil {
+		if errors.Is(err, storage.ErrNoActiveSession) {
+			ctx.SetStatusCode(fasthttp.StatusNotFound)
+			ctx.SetJSONError(messageOperationFailed)
+
+			return
+		}
+
+		ctx.Error(fmt.Errorf("unable to revoke the active session with id '%d' for user '%s': %w", id, userSession.Username, err), messageOperationFailed)
+
+		return
+	}
+
+	ctx.Logger.Infof("Session with id '%d' for user '%s' has been revoked by the user", id, userSession.Username)
+
+	ctx.ReplyOK()
+}
+
+// AdminUserSessionsGET returns the active sessions of the user in the path.
+func AdminUserSessionsGET(ctx *middlewares.AutheliaCtx) {
+	username := ctxUserValueString(ctx, userValueKeyUsername)
+
+	sessions, err := ctx.Providers.StorageProvider.LoadActiveSessionsByUsername(ctx, username, ctx.Clock.Now())
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the active sessions for user '%s': %w", username, err), messageOperationFailed)
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(newActiveSessionsResponse(sessions, ctx.GetSessionIDHash())); err != nil {
+		ctx.Logger.Errorf(logFmtErrWriteResponseBody, "admin user sessions", username, err)
+	}
+}
+
+// AdminUserSessionsDELETE revokes all of the active sessions of the user in the path.
+func AdminUserSessionsDELETE(ctx *middlewares.AutheliaCtx) {
+	username := ctxUserValueString(ctx, userValueKeyUsername)
+
+	revoked, err := ctx.Providers.StorageProvider.RevokeActiveSessionsByUsername(ctx, username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to revoke the active sessions for user '%s': %w", username, err), messageOperationFailed)
+
+		return
+	}
+
+	ctx.Logger.Infof("All %d sessions for user '%s' have been revoked by administrator '%s'", revoked, username, ctx.GetSession().Username)
+
+	if err = ctx.SetJSONBody(revokeActiveSessionsResponse{Revoked: revoked}); err != nil {
+		ctx.Logger.Errorf(logFmtErrWriteResponseBody, "admin user sessions", username, err)
+	}
+}
+
+// saveActiveSession records the user session of the request as a new active session and marks the user session as
+// recorded. The caller is responsible for saving the user session.
+func saveActiveSession(ctx *middlewares.AutheliaCtx, provider *session.Session, userSession *session.UserSession) (err error) {
+	now := ctx.Clock.Now()
+
+	userSession.ActiveSessionRecorded = true
+
+	return ctx.Providers.StorageProvider.SaveActiveSession(ctx, model.ActiveSession{
+		SessionID:      provider.GetSessionIDHash(ctx.RequestCtx),
+		CookieDomain:   provider.Config.Domain,
+		Username:       userSession.Username,
+		CreatedAt:      now,
+		LastActivityAt: now,
+		ExpiresAt:      now.Add(getActiveSessionExpiration(provider, userSession)),
+		IP:             model.NewIP(ctx.RemoteIP()),
+		UserAgent:      string(ctx.UserAgent()),
+		AuthLevel:      int(userSession.AuthenticationLevel),
+	})
+}
+
+// updateActiveSessionAuthLevel records the authentication level of the user session in the active session which had
+// the provided session id hash before the session was regenerated.
+func updateActiveSessionAuthLevel(ctx *middlewares.AutheliaCtx, id string, userSession *session.UserSession) {
+	if err := ctx.Providers.StorageProvider.UpdateActiveSessionAuthLevel(ctx, id, ctx.GetSessionIDHash(), int(userSession.AuthenticationLevel)); err != nil {
+		ctx.Logger.Errorf("Unable to update the authentication level of the active session for user '%s': %+v", userSession.Username, err)
+	}
+}
+
+// verifySessionActive returns true if the user session is still an active session and records the activity of the
+// active session at most once every activeSessionActivityInterval. A revoked session is not an active session, whereas
+// a session which was never recorded as an active session is recorded as one now. The active session is looked up on
+// every request so the revocation of a session takes effect immediately.
+func verifySessionActive(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) (active bool, err error) {
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return false, err
+	}
+
+	id := provider.GetSessionIDHash(ctx.RequestCtx)
+
+	if id == "" {
+		return false, nil
+	}
+
+	var activeSession *model.ActiveSession
+
+	if activeSession, err = ctx.Providers.StorageProvider.LoadActiveSession(ctx, id); err != nil {
+		return false, err
+	}
+
+	if activeSession == nil {
+		if userSession.ActiveSessionRecorded {
+			return false, nil
+		}
+
+		if err = saveActiveSession(ctx, provider, userSession); err != nil {
+			return false, err
+		}
+
+		// This only happens once for each session which was created before sessions were recorded as active sessions.
+		if err = updateSessionBestEffort(ctx, userSession, func(userSession *session.UserSession) {
+			userSession.ActiveSessionRecorded = true
+		}); err != nil {
+			return false, err
+		}
+
+		ctx.Logger.Debugf("Session for user '%s' was not recorded as an active session so it has been recorded", userSession.Username)
+
+		return true, nil
+	}
+
+	if activeSession.Username != userSession.Username {
+		return false, nil
+	}
+
+	if now := ctx.Clock.Now(); now.Sub(activeSession.LastActivityAt) >= activeSessionActivityInterval {
+		if err = ctx.Providers.StorageProvider.UpdateActiveSessionActivity(ctx, id, now, now.Add(getActiveSessionExpiration(provider, userSession))); err != nil {
+			ctx.Logger.Errorf("Unable to update the activity of the active session for user '%s': %+v", userSession.Username, err)
+		}
+	}
+
+	return true, nil
+}
+
+func getActiveSessionExpiration(provider *session.Session, userSession *session.UserSession) time.Duration {
+	if userSession.KeepMeLoggedIn {
+		return provider.Config.RememberMeDuration
+	}
+
+	return provider.Config.Expiration
+}
+
+func newActiveSessionsResponse(sessions []model.ActiveSession, current string) (response []activeSessionResponse) {
+	response = make([]activeSessionResponse, len(sessions))
+
+	for i, s := range sessions {
+		response[i] = activeSessionResponse{
+			ID:                  s.ID,
+			CookieDomain:        s.CookieDomain,
+			CreatedAt:           s.CreatedAt,
+			LastActivityAt:      s.LastActivityAt,
+			ExpiresAt:           s.ExpiresAt,
+			IP:                  s.IP.IP.String(),
+			UserAgent:           s.UserAgent,
+			AuthenticationLevel: authentication.Level(s.AuthLevel).String(),
+			Current:             current != "" && s.SessionID == current,
+		}
+	}
+
+	return response
+}
+
+func ctxUserValueString(ctx *middlewares.AutheliaCtx, key string) string {
+	value, _ := ctx.UserValue(key).(string)
+
+	return value
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// mockActiveSession sets the expectations for a request which has a user session which is an active session.
+func mockActiveSession(mock *mocks.MockAutheliaCtx) {
+	mock.StorageMock.EXPECT().
+		LoadActiveSession(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ interface{}, id string) (*model.ActiveSession, error) {
+			return &model.ActiveSession{SessionID: id, Username: mock.Ctx.GetSession().Username, LastActivityAt: time.Now()}, nil
+		}).
+		AnyTimes()
+
+	mock.StorageMock.EXPECT().
+		UpdateActiveSessionActivity(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+
+	mock.StorageMock.EXPECT().
+		RevokeActiveSession(gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+}
+
+func setUserSessionsTestSession(t *testing.T, mock *mocks.MockAutheliaCtx) {
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.OneFactor
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+}
+
+func TestUserSessionsGET(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	setUserSessionsTestSession(t, mock)
+
+	current := mock.Ctx.GetSessionIDHash()
+	require.NotEqual(t, "", current)
+
+	created := time.Unix(1600000000, 0).UTC()
+
+	mock.StorageMock.EXPECT().
+		LoadActiveSessionsByUsername(mock.Ctx, testUsername, gomock.Any()).
+		Return([]model.ActiveSession{
+			{ID: 1, SessionID: current, CookieDomain: "example.com", Username: testUsername, CreatedAt: created, LastActivityAt: created, ExpiresAt: created.Add(time.Hour), IP: model.NewIP(net.ParseIP("127.0.0.1")), UserAgent: "agent", AuthLevel: 1},
+			{ID: 2, SessionID: "other", CookieDomain: "example.com", Username: testUsername, CreatedAt: created, LastActivityAt: created, ExpiresAt: created.Add(time.Hour), IP: model.NewIP(net.ParseIP("192.168.0.1")), UserAgent: "agent", AuthLevel: 2},
+		}, nil)
+
+	UserSessionsGET(mock.Ctx)
+
+	mock.Assert200OK(t, []activeSessionResponse{
+		{ID: 1, CookieDomain: "example.com", CreatedAt: created, LastActivityAt: created, ExpiresAt: created.Add(time.Hour), IP: "127.0.0.1", UserAgent: "agent", AuthenticationLevel: "one_factor", Current: true},
+		{ID: 2, CookieDomain: "example.com", CreatedAt: created, LastActivityAt: created, ExpiresAt: created.Add(time.Hour), IP: "192.168.0.1", UserAgent: "agent", AuthenticationLevel: "two_factor"},
+	})
+}
+
+func TestUserSessionsGETShouldHandleStorageError(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	setUserSessionsTestSession(t, mock)
+
+	mock.StorageMock.EXPECT().
+		LoadActiveSessionsByUsername(mock.Ctx, testUsername, gomock.Any()).
+		Return(nil, errors.New("failed to connect"))
+
+	UserSessionsGET(mock.Ctx)
+
+	mock.Assert200KO(t, messageOperationFailed)
+	assert.Equal(t, "unable to load the active sessions for user 'john': failed to connect", mock.Hook.LastEntry().Message)
+}
+
+func TestUserSessionDELETE(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		setup    func(mock *mocks.MockAutheliaCtx)
+		expected int
+	}{
+		{
+			name: "ShouldRevokeSession",
+			have: "2",
+			setup: func(mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().RevokeActiveSessionByID(mock.Ctx, testUsername, 2).Return(nil)
+			},
+			expected: fasthttp.StatusOK,
+		},
+		{
+			name:     "ShouldRespondBadRequestOnInvalidID",
+			have:     "abc",
+			expected: fasthttp.StatusBadRequest,
+		},
+		{
+			name: "ShouldRespondNotFoundOnSessionOfAnotherUser",
+			have: "3",
+			setup: func(mock *mocks.MockAutheliaCtx) {
+				mock.StorageMock.EXPECT().RevokeActiveSessionByID(mock.Ctx, testUsername, 3).Return(storage.ErrNoActiveSession)
+			},
+			expected: fasthttp.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			setUserSessionsTestSession(t, mock)
+
+			if tc.setup != nil {
+				tc.setup(mock)
+			}
+
+			mock.Ctx.SetUserValue(userValueKeySessionID, tc.have)
+
+			UserSessionDELETE(mock.Ctx)
+
+			assert.Equal(t, tc.expected, mock.Ctx.Response.StatusCode())
+		})
+	}
+}
+
+func TestAdminUserSessionsDELETE(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	setUserSessionsTestSession(t, mock)
+
+	mock.StorageMock.EXPECT().RevokeActiveSessionsByUsername(mock.Ctx, "harry").Return(int64(2), nil)
+
+	mock.Ctx.SetUserValue(userValueKeyUsername, "harry")
+
+	AdminUserSessionsDELETE(mock.Ctx)
+
+	mock.Assert200OK(t, revokeActiveSessionsResponse{Revoked: 2})
+}
+
+func TestShouldDestroyRevokedSession(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Clock = &mock.Clock
+	mock.Clock.Set(time.Now())
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.TwoFactor
+	userSession.LastActivity = mock.Clock.Now().Unix()
+	userSession.RefreshTTL = mock.Clock.Now().Add(5 * time.Minute)
+	userSession.ActiveSessionRecorded = true
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().LoadActiveSession(mock.Ctx, gomock.Any()).Return(nil, nil),
+		mock.StorageMock.EXPECT().RevokeActiveSession(mock.Ctx, gomock.Any()).Return(nil),
+	)
+
+	mock.Ctx.Request.Header.Set("X-Original-URL", "https://two-factor.example.com")
+
+	VerifyGET(verifyGetCfg)(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusUnauthorized, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "", mock.Ctx.GetSession().Username)
+}
+
+func TestShouldRecordSessionNotRecordedAsActiveSession(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Clock = &mock.Clock
+	mock.Clock.Set(time.Now())
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.TwoFactor
+	userSession.LastActivity = mock.Clock.Now().Unix()
+	userSession.RefreshTTL = mock.Clock.Now().Add(5 * time.Minute)
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().LoadActiveSession(mock.Ctx, gomock.Any()).Return(nil, nil),
+		mock.StorageMock.EXPECT().
+			SaveActiveSession(mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, session model.ActiveSession) error {
+				assert.Equal(t, testUsername, session.Username)
+				assert.Equal(t, int(authentication.TwoFactor), session.AuthLevel)
+				assert.Equal(t, mock.Clock.Now(), session.CreatedAt)
+
+				return nil
+			}),
+	)
+
+	mock.Ctx.Request.Header.Set("X-Original-URL", "https://two-factor.example.com")
+
+	VerifyGET(verifyGetCfg)(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+	userSession = mock.Ctx.GetSession()
+
+	assert.Equal(t, testUsername, userSession.Username)
+	assert.True(t, userSession.ActiveSessionRecorded)
+}
+
+func TestShouldCheckActiveSessionOnEveryRequest(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Clock = &mock.Clock
+	mock.Clock.Set(time.Now())
+
+	userSession := mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.TwoFactor
+	userSession.KeepMeLoggedIn = true
+	userSession.RefreshTTL = mock.Clock.Now().Add(5 * time.Minute)
+	userSession.ActiveSessionRecorded = true
+
+	require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+	gomock.InOrder(
+		mock.StorageMock.EXPECT().
+			LoadActiveSession(mock.Ctx, gomock.Any()).
+			Return(&model.ActiveSession{Username: testUsername, LastActivityAt: mock.Clock.Now()}, nil).
+			Times(2),
+		mock.StorageMock.EXPECT().
+			LoadActiveSession(mock.Ctx, gomock.Any()).
+			Return(nil, nil),
+		mock.StorageMock.EXPECT().RevokeActiveSession(mock.Ctx, gomock.Any()).Return(nil),
+	)
+
+	mock.Ctx.Request.Header.Set("X-Original-URL", "https://two-factor.example.com")
+
+	for i := 0; i < 2; i++ {
+		mock.Ctx.Response.Reset()
+
+		VerifyGET(verifyGetCfg)(mock.Ctx)
+
+		assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+
+		// Checking the active session doesn't save the user session.
+		assert.Nil(t, mock.Ctx.Response.Header.PeekCookie("authelia_session"))
+	}
+
+	// The revocation of the session takes effect on the very next request.
+	mock.Ctx.Response.Reset()
+
+	VerifyGET(verifyGetCfg)(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusUnauthorized, mock.Ctx.Response.StatusCode())
+	assert.Equal(t, "", mock.Ctx.GetSession().Username)
+}
@@ -3,19 +3,25 @@ package handlers
 import (
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/token/jwt"
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -112,6 +118,41 @@ func verifyBasicAuth(ctx *middlewares.AutheliaCtx, header, auth []byte) (usernam
 	return username, details.DisplayName, details.Groups, details.Emails, authentication.OneFactor, nil
 }
 
+// verifyBearerAuth verifies a bearer token issued to an OAuth 2.0 client via the client credentials grant and returns
+// the client id. The token must have been granted an audience which matches the target URL.
+func verifyBearerAuth(ctx *middlewares.AutheliaCtx, header, auth []byte, object *authorization.Object) (clientID string, err error) {
+	if ctx.Providers.OpenIDConnect == nil {
+		return "", fmt.Errorf("unable to verify the bearer token in the %s header: the OpenID Connect 1.0 provider is not configured", header)
+	}
+
+	token := strings.TrimSpace(string(auth[len(prefixAuthorizationBearer):]))
+
+	var (
+		tokenType fosite.TokenUse
+		requester fosite.AccessRequester
+	)
+
+	if tokenType, requester, err = ctx.Providers.OpenIDConnect.IntrospectToken(ctx, token, fosite.AccessToken, oidc.NewSession()); err != nil {
+		return "", fmt.Errorf("unable to verify the bearer token in the %s header: %s", header, fosite.ErrorToRFC6749Error(err).WithExposeDebug(true).GetDescription())
+	}
+
+	if tokenType != fosite.AccessToken {
+		return "", fmt.Errorf("unable to verify the bearer token in the %s header: the token is a '%s' but it must be an '%s'", header, tokenType, fosite.AccessToken)
+	}
+
+	clientID = requester.GetClient().GetID()
+
+	if !oidc.IsClientCredentialsSession(requester.GetSession()) {
+		return "", fmt.Errorf("unable to verify the bearer token in the %s header: the token for client with id '%s' was not issued via the client credentials grant", header, clientID)
+	}
+
+	if err = fosite.DefaultAudienceMatchingStrategy(requester.GetGrantedAudience(), []string{object.URL.String()}); err != nil {
+		return "", fmt.Errorf("unable to verify the bearer token in the %s header: the token for client with id '%s' has not been granted an audience for the target url '%s'", header, clientID, object.URL.String())
+	}
+
+	return clientID, nil
+}
+
 // setForwardedHeaders set the forwarded User, Groups, Name and Email headers.
 func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string, groups, emails []string) {
 	if username != "" {
@@ -127,14 +168,154 @@ func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string
 	}
 }
 
+// setAuthzHeaders sets the headers for an authorized request. If the matching access control rule or the access
+// control configuration has headers configured they're rendered and set, otherwise the standard forwarded headers are set.
+func setAuthzHeaders(ctx *middlewares.AutheliaCtx, authn *Authn) {
+	if authn.Details.Username == "" {
+		return
+	}
+
+	headers := ctx.Providers.Authorizer.GetHeaders(
+		authorization.Subject{
+			Username: authn.Details.Username,
+			Groups:   authn.Details.Groups,
+			IP:       ctx.RemoteIP(),
+		},
+		authn.Object,
+	)
+
+	setIdentityAssertionHeader(ctx, authn)
+
+	if len(headers) == 0 {
+		setForwardedHeaders(&ctx.Response.Header, authn.Details.Username, authn.Details.DisplayName, authn.Details.Groups, authn.Details.Emails)
+
+		return
+	}
+
+	values := authorization.HeaderValues{
+		Username:            authn.Details.Username,
+		DisplayName:         authn.Details.DisplayName,
+		Groups:              authn.Details.Groups,
+		Emails:              authn.Details.Emails,
+		AuthenticationLevel: authn.Level.String(),
+		AMR:                 authn.AuthenticationMethodRefs.MarshalRFC8176(),
+		Method:              authn.Object.Method,
+	}
+
+	if authn.Object.URL != nil {
+		values.URL = authn.Object.URL.String()
+	}
+
+	if authn.Type == AuthnTypeCookie {
+		values.SessionIDHash = ctx.GetSessionIDHash()
+	}
+
+	for _, header := range headers {
+		value, err := header.Value(values)
+		if err != nil {
+			ctx.Logger.Errorf("Error rendering the value of header '%s' for user '%s': %+v", header.Name, authn.Details.Username, err)
+
+			continue
+		}
+
+		ctx.Response.Header.Set(header.Name, value)
+	}
+}
+
+// setIdentityAssertionHeader sets the signed identity assertion JWT header for an authorized request if the identity
+// assertion is enabled.
+func setIdentityAssertionHeader(ctx *middlewares.AutheliaCtx, authn *Authn) {
+	config := ctx.Configuration.AccessControl.IdentityAssertion
+
+	if !config.Enable {
+		return
+	}
+
+	jwk := ctx.Providers.IdentityAssertion
+
+	if jwk == nil && ctx.Providers.OpenIDConnect != nil {
+		jwk = ctx.Providers.OpenIDConnect.KeyManager.GetActiveKey()
+	}
+
+	if jwk == nil {
+		ctx.Logger.Errorf("Error generating the identity assertion for user '%s': no %s issuer private key is currently valid", authn.Details.Username, oidc.SigningAlgorithmRSAWithSHA256)
+
+		return
+	}
+
+	jti, err := uuid.NewRandom()
+	if err != nil {
+		ctx.Logger.Errorf("Error generating the identity assertion for user '%s': could not generate the JTI: %+v", authn.Details.Username, err)
+
+		return
+	}
+
+	now := ctx.Clock.Now()
+
+	claims := jwt.MapClaims{
+		oidc.ClaimJWTID:                          jti.String(),
+		oidc.ClaimSubject:                        authn.Details.Username,
+		oidc.ClaimAudience:                       ctx.Providers.Authorizer.GetAudience(authorization.Subject{Username: authn.Details.Username, Groups: authn.Details.Groups, IP: ctx.RemoteIP()}, authn.Object),
+		oidc.ClaimIssuedAt:                       now.Unix(),
+		oidc.ClaimNotBefore:                      now.Unix(),
+		oidc.ClaimExpirationTime:                 now.Add(config.Lifespan).Unix(),
+		oidc.ClaimAuthenticationTime:             authn.AuthenticatedAt.Unix(),
+		oidc.ClaimAuthenticationMethodsReference: authn.AuthenticationMethodRefs.MarshalRFC8176(),
+		oidc.ClaimGroups:                         authn.Details.Groups,
+		oidc.ClaimFullName:                       authn.Details.DisplayName,
+		claimAuthenticationLevel:                 authn.Level.String(),
+	}
+
+	if config.Issuer != "" {
+		claims[oidc.ClaimIssuer] = config.Issuer
+	}
+
+	if len(authn.Details.Emails) != 0 {
+		claims[oidc.ClaimPreferredEmail] = authn.Details.Emails[0]
+	}
+
+	headers := &jwt.Headers{
+		Extra: map[string]any{
+			oidc.JWTHeaderKeyIdentifier: jwk.KeyID(),
+		},
+	}
+
+	var token string
+
+	if token, _, err = jwk.Strategy().Generate(ctx, claims, headers); err != nil {
+		ctx.Logger.Errorf("Error generating the identity assertion for user '%s': %+v", authn.Details.Username, err)
+
+		return
+	}
+
+	ctx.Response.Header.Set(config.Header, token)
+}
+
+func getSessionAuthenticatedAt(userSession *session.UserSession) time.Time {
+	if userSession.AuthenticationLevel >= authentication.TwoFactor && userSession.SecondFactorAuthnTimestamp != 0 {
+		return time.Unix(userSession.SecondFactorAuthnTimestamp, 0)
+	}
+
+	return time.Unix(userSession.FirstFactorAuthnTimestamp, 0)
+}
+
 func isSessionInactiveTooLong(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, isUserAnonymous bool) (isInactiveTooLong bool) {
-	if userSession.KeepMeLoggedIn || isUserAnonymous || int64(ctx.Providers.SessionProvider.Inactivity.Seconds()) == 0 {
+	if userSession.KeepMeLoggedIn || isUserAnonymous {
 		return false
 	}
 
-	isInactiveTooLong = time.Unix(userSession.LastActivity, 0).Add(ctx.Providers.SessionProvider.Inactivity).Before(ctx.Clock.Now())
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return false
+	}
 
-	ctx.Logger.Tracef("Inactivity report for user '%s'. Current Time: %d, Last Activity: %d, Maximum Inactivity: %d.", userSession.Username, ctx.Clock.Now().Unix(), userSession.LastActivity, int(ctx.Providers.SessionProvider.Inactivity.Seconds()))
+	if int64(provider.Config.Inactivity.Seconds()) == 0 {
+		return false
+	}
+
+	isInactiveTooLong = time.Unix(userSession.LastActivity, 0).Add(provider.Config.Inactivity).Before(ctx.Clock.Now())
+
+	ctx.Logger.Tracef("Inactivity report for user '%s'. Current Time: %d, Last Activity: %d, Maximum Inactivity: %d.", userSession.Username, ctx.Clock.Now().Unix(), userSession.LastActivity, int(provider.Config.Inactivity.Seconds()))
 
 	return isInactiveTooLong
 }
@@ -151,7 +332,7 @@ func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userS
 
 	if isSessionInactiveTooLong(ctx, userSession, isUserAnonymous) {
 		// Destroy the session a new one will be regenerated on next request.
-		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+		if err = ctx.DestroySession(); err != nil {
 			return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to destroy session for user '%s' after the session has been inactive too long: %w", userSession.Username, err)
 		}
 
@@ -160,9 +341,37 @@ func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userS
 		return "", "", nil, nil, authentication.NotAuthenticated, nil
 	}
 
+	if !isUserAnonymous {
+		var active bool
+
+		if active, err = verifySessionActive(ctx, userSession); err != nil {
+			return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to verify the active session for user '%s': %w", userSession.Username, err)
+		}
+
+		if !active {
+			if err = ctx.DestroySession(); err != nil {
+				return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to destroy session for user '%s' after the session has been revoked: %w", userSession.Username, err)
+			}
+
+			ctx.Logger.Warnf("Session destroyed for user '%s' as the session has been revoked", userSession.Username)
+
+			return "", "", nil, nil, authentication.NotAuthenticated, nil
+		}
+
+		var valid bool
+
+		if valid, err = verifySessionBinding(ctx, targetURL, userSession); err != nil {
+			return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to verify the session binding for user '%s': %w", userSession.Username, err)
+		}
+
+		if !valid {
+			return "", "", nil, nil, authentication.NotAuthenticated, nil
+		}
+	}
+
 	if err = verifySessionHasUpToDateProfile(ctx, targetURL, userSession, refreshProfile, refreshProfileInterval); err != nil {
 		if err == authentication.ErrUserNotFound {
-			if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+			if err = ctx.DestroySession(); err != nil {
 				ctx.Logger.Errorf("Unable to destroy user session after provider refresh didn't find the user: %v", err)
 			}
 
@@ -211,8 +420,8 @@ func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, is
 	redirectionURL := ctxGetPortalURL(ctx)
 
 	if redirectionURL != nil {
-		if !utils.IsURISafeRedirection(redirectionURL, ctx.Configuration.Session.Domain) {
-			ctx.Logger.Errorf("Configured Portal URL '%s' does not appear to be able to write cookies for the '%s' domain", redirectionURL, ctx.Configuration.Session.Domain)
+		if provider, err := ctx.GetSessionProvider(); err != nil || !utils.IsURISafeRedirection(redirectionURL, provider.Config.Domain) {
+			ctx.Logger.Errorf("Configured Portal URL '%s' does not appear to be able to write cookies for the domain of the target URL '%s'", redirectionURL, targetURL)
 
 			ctx.ReplyUnauthorized()
 
@@ -224,7 +433,7 @@ func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, is
 		qry.Set(queryArgRD, targetURL.String())
 
 		if rm != "" {
-			qry.Set("rm", rm)
+			qry.Set(queryArgRM, rm)
 		}
 
 		redirectionURL.RawQuery = qry.Encode()
@@ -262,10 +471,29 @@ func updateActivityTimestamp(ctx *middlewares.AutheliaCtx, isBasicAuth bool) err
 		return nil
 	}
 
+	now := ctx.Clock.Now().Unix()
+
 	// Mark current activity.
-	userSession.LastActivity = ctx.Clock.Now().Unix()
+	return updateSessionBestEffort(ctx, &userSession, func(userSession *session.UserSession) {
+		userSession.LastActivity = now
+	})
+}
+
+// updateSessionBestEffort applies the update to the user session and saves it. A conflict with concurrent requests
+// which persists after the update was retried is logged instead of returned as these updates only need to be saved on a
+// best effort basis, they're applied to the user session of the current request regardless and are applied again by
+// subsequent requests. This ensures concurrent requests for the same session never fail to be authorized because of
+// each other.
+func updateSessionBestEffort(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, update func(userSession *session.UserSession)) (err error) {
+	if err = ctx.UpdateSession(userSession, update); err != nil {
+		if !errors.Is(err, storage.ErrSessionDataVersionConflict) {
+			return err
+		}
+
+		ctx.Logger.Debugf("Session for user '%s' was not saved as it was concurrently modified by other requests", userSession.Username)
+	}
 
-	return ctx.SaveSession(userSession)
+	return nil
 }
 
 // generateVerifySessionHasUpToDateProfileTraceLogs is used to generate trace logs only when trace logging is enabled.
@@ -349,24 +577,31 @@ func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *ur
 		// This is so that we don't check every subsequent request after this one.
 		if refreshProfileInterval != schema.RefreshIntervalAlways {
 			// Update RefreshTTL and save session if refresh is not set to always.
-			userSession.RefreshTTL = ctx.Clock.Now().Add(refreshProfileInterval)
-			return ctx.SaveSession(*userSession)
+			refreshTTL := ctx.Clock.Now().Add(refreshProfileInterval)
+
+			return updateSessionBestEffort(ctx, userSession, func(userSession *session.UserSession) {
+				userSession.RefreshTTL = refreshTTL
+			})
 		}
 	} else {
 		ctx.Logger.Debugf("Updated profile detected for %s.", userSession.Username)
 		if ctx.Configuration.Log.Level == "trace" {
 			generateVerifySessionHasUpToDateProfileTraceLogs(ctx, userSession, details)
 		}
-		userSession.Emails = details.Emails
-		userSession.Groups = details.Groups
-		userSession.DisplayName = details.DisplayName
 
-		// Only update TTL if the user has a interval set.
-		if refreshProfileInterval != schema.RefreshIntervalAlways {
-			userSession.RefreshTTL = ctx.Clock.Now().Add(refreshProfileInterval)
-		}
+		refreshTTL := ctx.Clock.Now().Add(refreshProfileInterval)
+
 		// Return the result of save session if there were changes.
-		return ctx.SaveSession(*userSession)
+		return updateSessionBestEffort(ctx, userSession, func(userSession *session.UserSession) {
+			userSession.Emails = details.Emails
+			userSession.Groups = details.Groups
+			userSession.DisplayName = details.DisplayName
+
+			// Only update TTL if the user has a interval set.
+			if refreshProfileInterval != schema.RefreshIntervalAlways {
+				userSession.RefreshTTL = refreshTTL
+			}
+		})
 	}
 
 	// Return nil if disabled or if no changes and refresh interval set to always.
@@ -422,7 +657,7 @@ func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile
 	if sessionUsername != nil && !strings.EqualFold(string(sessionUsername), username) {
 		ctx.Logger.Warnf("Possible cookie hijack or attempt to bypass security detected destroying the session and sending 401 response")
 
-		if err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx); err != nil {
+		if err = ctx.DestroySession(); err != nil {
 			ctx.Logger.Errorf("Unable to destroy user session after handler could not match them to their %s header: %s", headerSessionUsername, err)
 		}
 
@@ -455,14 +690,17 @@ func VerifyGET(cfg schema.AuthenticationBackend) middlewares.RequestHandler {
 			return
 		}
 
-		if !isURLUnderProtectedDomain(targetURL, ctx.Configuration.Session.Domain) {
-			ctx.Logger.Errorf("Target URL %s is not under the protected domain %s",
-				targetURL.String(), ctx.Configuration.Session.Domain)
+		cookieDomain := ctx.GetCookieDomainFromTargetURI(targetURL)
+
+		if cookieDomain == "" {
+			ctx.Logger.Errorf("Target URL %s is not under any of the protected session cookie domains", targetURL.String())
 			ctx.ReplyUnauthorized()
 
 			return
 		}
 
+		ctx.SetSessionCookieDomain(cookieDomain)
+
 		method := ctx.XForwardedMethod()
 		isBasicAuth, username, name, groups, emails, authLevel, err := verifyAuth(ctx, targetURL, refreshProfile, refreshProfileInterval)
 
@@ -489,7 +727,30 @@ func VerifyGET(cfg schema.AuthenticationBackend) middlewares.RequestHandler {
 		case NotAuthorized:
 			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method)
 		case Authorized:
-			setForwardedHeaders(&ctx.Response.Header, username, name, groups, emails)
+			authn := Authn{
+				Details: authentication.UserDetails{
+					Username:    username,
+					DisplayName: name,
+					Groups:      groups,
+					Emails:      emails,
+				},
+				Level:  authLevel,
+				Object: authorization.NewObject(targetURL, string(method)),
+				Type:   AuthnTypeCookie,
+			}
+
+			if isBasicAuth {
+				authn.Type = AuthnTypeProxyAuthorization
+				authn.AuthenticationMethodRefs = oidc.AuthenticationMethodsReferences{UsernameAndPassword: true}
+				authn.AuthenticatedAt = ctx.Clock.Now()
+			} else {
+				userSession := ctx.GetSession()
+
+				authn.AuthenticationMethodRefs = userSession.AuthenticationMethodRefs
+				authn.AuthenticatedAt = getSessionAuthenticatedAt(&userSession)
+			}
+
+			setAuthzHeaders(ctx, &authn)
 		}
 
 		if err = updateActivityTimestamp(ctx, isBasicAuth); err != nil {
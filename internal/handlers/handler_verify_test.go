@@ -1,10 +1,15 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
+	"path/filepath"
 	"regexp"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,7 +23,9 @@ import (
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -49,6 +56,19 @@ func TestShouldRaiseWhenNoHeaderProvidedToDetectTargetURL(t *testing.T) {
 	assert.Equal(t, "Missing header X-Forwarded-Host", err.Error())
 }
 
+func TestShouldIgnoreXOriginalURLFromUntrustedProxy(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.RequestCtx.SetRemoteAddr(&net.TCPAddr{IP: net.ParseIP("203.0.113.10"), Port: 50000})
+	mock.Ctx.Request.Header.Set("X-Original-URL", "https://bypass.example.com")
+
+	VerifyGET(verifyGetCfg)(mock.Ctx)
+
+	assert.Equal(t, fasthttp.StatusUnauthorized, mock.Ctx.Response.StatusCode())
+	assert.Nil(t, mock.Ctx.Response.Header.Peek("Remote-User"))
+}
+
 func TestShouldRaiseWhenNoXForwardedHostHeaderProvidedToDetectTargetURL(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
@@ -502,6 +522,8 @@ func TestShouldNotCrashOnEmptyEmail(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	mock.Clock.Set(time.Now())
 
 	userSession := mock.Ctx.GetSession()
@@ -669,6 +691,8 @@ func TestShouldVerifyAuthorizationsUsingSessionCookie(t *testing.T) {
 			mock := mocks.NewMockAutheliaCtx(t)
 			defer mock.Close()
 
+			mockActiveSession(mock)
+
 			mock.Clock.Set(time.Now())
 
 			userSession := mock.Ctx.GetSession()
@@ -702,14 +726,16 @@ func TestShouldDestroySessionWhenInactiveForTooLong(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	clock := utils.TestingClock{}
 	clock.Set(time.Now())
 	past := clock.Now().Add(-1 * time.Hour)
 
 	mock.Ctx.Configuration.Session.Inactivity = testInactivity
 	// Reload the session provider since the configuration is indirect.
-	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil)
-	assert.Equal(t, time.Second*10, mock.Ctx.Providers.SessionProvider.Inactivity)
+	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil, nil)
+	assert.Equal(t, time.Second*10, mock.Ctx.Providers.SessionProvider.Config.Inactivity)
 
 	userSession := mock.Ctx.GetSession()
 	userSession.Username = testUsername
@@ -736,13 +762,15 @@ func TestShouldDestroySessionWhenInactiveForTooLongUsingDurationNotation(t *test
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	clock := utils.TestingClock{}
 	clock.Set(time.Now())
 
 	mock.Ctx.Configuration.Session.Inactivity = time.Second * 10
 	// Reload the session provider since the configuration is indirect.
-	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil)
-	assert.Equal(t, time.Second*10, mock.Ctx.Providers.SessionProvider.Inactivity)
+	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil, nil)
+	assert.Equal(t, time.Second*10, mock.Ctx.Providers.SessionProvider.Config.Inactivity)
 
 	userSession := mock.Ctx.GetSession()
 	userSession.Username = testUsername
@@ -766,6 +794,8 @@ func TestShouldKeepSessionWhenUserCheckedRememberMeAndIsInactiveForTooLong(t *te
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	mock.Clock.Set(time.Now())
 
 	mock.Ctx.Configuration.Session.Inactivity = testInactivity
@@ -798,6 +828,8 @@ func TestShouldKeepSessionWhenInactivityTimeoutHasNotBeenExceeded(t *testing.T)
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	mock.Clock.Set(time.Now())
 
 	mock.Ctx.Configuration.Session.Inactivity = testInactivity
@@ -833,13 +865,15 @@ func TestShouldRedirectWhenSessionInactiveForTooLongAndRDParamProvided(t *testin
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	clock := utils.TestingClock{}
 	clock.Set(time.Now())
 
 	mock.Ctx.Configuration.Session.Inactivity = testInactivity
 	// Reload the session provider since the configuration is indirect.
-	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil)
-	assert.Equal(t, time.Second*10, mock.Ctx.Providers.SessionProvider.Inactivity)
+	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil, nil)
+	assert.Equal(t, time.Second*10, mock.Ctx.Providers.SessionProvider.Config.Inactivity)
 
 	past := clock.Now().Add(-1 * time.Hour)
 
@@ -897,6 +931,8 @@ func TestShouldUpdateInactivityTimestampEvenWhenHittingForbiddenResources(t *tes
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	mock.Clock.Set(time.Now())
 
 	mock.Ctx.Configuration.Session.Inactivity = testInactivity
@@ -1037,6 +1073,8 @@ func TestShouldNotRefreshUserGroupsFromBackend(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	// Setup pointer to john so we can adjust it during the test.
 	user := &authentication.UserDetails{
 		Username: "john",
@@ -1101,6 +1139,8 @@ func TestShouldNotRefreshUserGroupsFromBackendWhenDisabled(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	// Setup user john.
 	user := &authentication.UserDetails{
 		Username: "john",
@@ -1147,6 +1187,8 @@ func TestShouldDestroySessionWhenUserNotExist(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	// Setup user john.
 	user := &authentication.UserDetails{
 		Username: "john",
@@ -1206,6 +1248,8 @@ func TestShouldGetRemovedUserGroupsFromBackend(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	// Setup pointer to john so we can adjust it during the test.
 	user := &authentication.UserDetails{
 		Username: "john",
@@ -1275,6 +1319,8 @@ func TestShouldGetRemovedUserGroupsFromBackend(t *testing.T) {
 func TestShouldGetAddedUserGroupsFromBackend(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 
+	mockActiveSession(mock)
+
 	// Setup pointer to john so we can adjust it during the test.
 	user := &authentication.UserDetails{
 		Username: "john",
@@ -1332,6 +1378,9 @@ func TestShouldGetAddedUserGroupsFromBackend(t *testing.T) {
 
 	mock = mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
+
+	mockActiveSession(mock)
+
 	err = mock.Ctx.SaveSession(userSession)
 	assert.NoError(t, err)
 
@@ -1360,6 +1409,8 @@ func TestShouldCheckValidSessionUsernameHeaderAndReturn200(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	mock.Clock.Set(time.Now())
 
 	expectedStatusCode := 200
@@ -1384,6 +1435,8 @@ func TestShouldCheckInvalidSessionUsernameHeaderAndReturn401(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	mock.Clock.Set(time.Now())
 
 	expectedStatusCode := 401
@@ -1431,14 +1484,16 @@ func TestShouldNotRedirectRequestsForBypassACLWhenInactiveForTooLong(t *testing.
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
 
+	mockActiveSession(mock)
+
 	clock := utils.TestingClock{}
 	clock.Set(time.Now())
 	past := clock.Now().Add(-1 * time.Hour)
 
 	mock.Ctx.Configuration.Session.Inactivity = testInactivity
 	// Reload the session provider since the configuration is indirect.
-	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil)
-	assert.Equal(t, time.Second*10, mock.Ctx.Providers.SessionProvider.Inactivity)
+	mock.Ctx.Providers.SessionProvider = session.NewProvider(mock.Ctx.Configuration.Session, nil, nil)
+	assert.Equal(t, time.Second*10, mock.Ctx.Providers.SessionProvider.Config.Inactivity)
 
 	userSession := mock.Ctx.GetSession()
 	userSession.Username = testUsername
@@ -1528,7 +1583,7 @@ func TestIsSessionInactiveTooLong(t *testing.T) {
 			defer ctx.Close()
 
 			ctx.Ctx.Configuration.Session.Inactivity = tc.inactivity
-			ctx.Ctx.Providers.SessionProvider = session.NewProvider(ctx.Ctx.Configuration.Session, nil)
+			ctx.Ctx.Providers.SessionProvider = session.NewProvider(ctx.Ctx.Configuration.Session, nil, nil)
 
 			ctx.Clock.Set(tc.now)
 			ctx.Ctx.Clock = &ctx.Clock
@@ -1539,3 +1594,110 @@ func TestIsSessionInactiveTooLong(t *testing.T) {
 		})
 	}
 }
+
+// testConcurrentSessionStorage holds the first two saves of the session data until both of them have been attempted,
+// so the two requests which made them have both loaded the same version of the session data before either saves it.
+type testConcurrentSessionStorage struct {
+	session.SQLStorage
+
+	barrier   *sync.WaitGroup
+	saves     int32
+	conflicts int32
+}
+
+func (s *testConcurrentSessionStorage) SaveSessionData(ctx context.Context, sessionID string, data []byte, expiresAt time.Time, version int64) (err error) {
+	if s.barrier != nil && atomic.AddInt32(&s.saves, 1) <= 2 {
+		s.barrier.Done()
+		s.barrier.Wait()
+	}
+
+	if err = s.SQLStorage.SaveSessionData(ctx, sessionID, data, expiresAt, version); errors.Is(err, storage.ErrSessionDataVersionConflict) {
+		atomic.AddInt32(&s.conflicts, 1)
+	}
+
+	return err
+}
+
+func TestShouldAuthorizeConcurrentVerifyRequestsForTheSameSession(t *testing.T) {
+	provider := storage.NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a-long-encryption-key-used-only-for-tests",
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+		},
+	})
+
+	require.NoError(t, provider.StartupCheck())
+
+	defer provider.Close()
+
+	store := &testConcurrentSessionStorage{SQLStorage: provider}
+
+	seed := mocks.NewMockAutheliaCtx(t)
+	defer seed.Close()
+
+	config := seed.Ctx.Configuration.Session
+	config.Secret = "abc"
+	config.SQL = schema.SessionSQLConfiguration{Enabled: true, CleanupInterval: time.Minute}
+
+	sessions := session.NewProvider(config, nil, store)
+
+	seed.Ctx.Providers.SessionProvider = sessions
+
+	userSession := seed.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.AuthenticationLevel = authentication.OneFactor
+	userSession.LastActivity = seed.Clock.Now().Add(-time.Minute).Unix()
+
+	require.NoError(t, seed.Ctx.SaveSession(userSession))
+
+	cookie := &fasthttp.Cookie{}
+	require.NoError(t, cookie.ParseBytes(seed.Ctx.Response.Header.PeekCookie("authelia_session")))
+
+	requests := []*mocks.MockAutheliaCtx{mocks.NewMockAutheliaCtx(t), mocks.NewMockAutheliaCtx(t)}
+
+	for _, mock := range requests {
+		defer mock.Close()
+
+		mock.Ctx.Clock = &mock.Clock
+		mock.Ctx.Providers.SessionProvider = sessions
+		mock.Ctx.Request.Header.SetCookie("authelia_session", string(cookie.Value()))
+		mock.Ctx.Request.Header.Set("X-Original-URL", "https://one-factor.example.com")
+
+		mock.StorageMock.EXPECT().
+			LoadActiveSession(gomock.Any(), gomock.Any()).
+			Return(&model.ActiveSession{Username: testUsername, LastActivityAt: mock.Clock.Now()}, nil).
+			AnyTimes()
+	}
+
+	store.barrier = &sync.WaitGroup{}
+	store.barrier.Add(2)
+
+	var wg sync.WaitGroup
+
+	for _, mock := range requests {
+		wg.Add(1)
+
+		go func(mock *mocks.MockAutheliaCtx) {
+			defer wg.Done()
+
+			VerifyGET(schema.AuthenticationBackend{})(mock.Ctx)
+		}(mock)
+	}
+
+	wg.Wait()
+
+	// Both requests loaded the same version of the session so at least one of them had to retry a save.
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&store.conflicts), int32(1))
+
+	for _, mock := range requests {
+		assert.Equal(t, fasthttp.StatusOK, mock.Ctx.Response.StatusCode())
+		assert.Equal(t, testUsername, string(mock.Ctx.Response.Header.Peek("Remote-User")))
+	}
+
+	seed.Ctx.Request.Header.SetCookie("authelia_session", string(cookie.Value()))
+
+	userSession = seed.Ctx.GetSession()
+
+	assert.Equal(t, testUsername, userSession.Username)
+	assert.Equal(t, seed.Clock.Now().Unix(), userSession.LastActivity)
+}
@@ -8,7 +8,8 @@ import (
 	"github.com/authelia/authelia/v4/internal/session"
 )
 
-func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2ConsentSession, userSession *session.UserSession) (extraClaims map[string]any) {
+func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2ConsentSession, userSession *session.UserSession,
+	client *oidc.Client, claims *oidc.ClaimsStrategy) (extraClaims map[string]any) {
 	extraClaims = map[string]any{}
 
 	for _, scope := range consent.GrantedScopes {
@@ -35,6 +36,9 @@ func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2Consen
 		}
 	}
 
+	claims.GrantClaims(client, consent.GrantedScopes,
+		oidc.NewClaimAttributes(userSession.Username, userSession.DisplayName, userSession.Emails, userSession.Groups), extraClaims)
+
 	if ar != nil {
 		for _, audience := range consent.GrantedAudience {
 			ar.GrantAudience(audience)
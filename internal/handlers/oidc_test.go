@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -16,7 +17,7 @@ func TestShouldGrantAppropriateClaimsForScopeProfile(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeProfile},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -32,7 +33,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeGroups},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil, nil)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -41,7 +42,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "admin")
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "dev")
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil, nil)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -55,7 +56,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeEmail},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil, nil)
 
 	assert.Len(t, extraClaims, 3)
 
@@ -69,7 +70,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimEmailVerified)
 	assert.Equal(t, true, extraClaims[oidc.ClaimEmailVerified])
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -85,7 +86,7 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeOpenID, oidc.ScopeProfile},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -95,7 +96,7 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimFullName)
 	assert.Equal(t, "John Smith", extraClaims[oidc.ClaimFullName])
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -106,6 +107,38 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	assert.Equal(t, extraClaims[oidc.ClaimFullName], "Fred Smith")
 }
 
+func TestShouldGrantAppropriateClaimsForCustomScope(t *testing.T) {
+	claims, err := oidc.NewClaimsStrategy(&schema.OpenIDConnectConfiguration{
+		Claims: map[string]schema.OpenIDConnectClaimConfiguration{
+			"roles":   {Attribute: oidc.ClaimAttributeGroups, Filter: "^adm"},
+			"mailbox": {Template: "{{ .Username }}@mail.example.com"},
+		},
+		Scopes: map[string]schema.OpenIDConnectScopeConfiguration{
+			"roles": {Claims: []string{"roles", "mailbox"}},
+		},
+	})
+	require.NoError(t, err)
+
+	consent := &model.OAuth2ConsentSession{
+		GrantedScopes: []string{oidc.ScopeGroups, "roles"},
+	}
+
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil, claims)
+
+	assert.Len(t, extraClaims, 3)
+
+	assert.Equal(t, []string{"admin", "dev"}, extraClaims[oidc.ClaimGroups])
+	assert.Equal(t, []string{"admin"}, extraClaims["roles"])
+	assert.Equal(t, "john@mail.example.com", extraClaims["mailbox"])
+
+	consent.GrantedScopes = []string{oidc.ScopeGroups}
+
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil, claims)
+
+	assert.Len(t, extraClaims, 1)
+	assert.NotContains(t, extraClaims, "roles")
+}
+
 var (
 	oidcUserSessionJohn = session.UserSession{
 		Username:    "john",
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// verifySessionBinding returns true if the client characteristics of the request match the characteristics the user
+// session is bound to. When they don't match the violation is recorded in the authentication log and the configured
+// policy is applied, only the log policy allows the request to continue.
+func verifySessionBinding(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userSession *session.UserSession) (valid bool, err error) {
+	config := ctx.Configuration.Session.Binding
+
+	if !config.Enabled() {
+		return true, nil
+	}
+
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return false, err
+	}
+
+	if violations := userSession.ClientBindingViolations(config, ctx.RemoteIP(), ctx.UserAgent(), provider.GetAntiReplayToken(ctx.RequestCtx)); len(violations) != 0 {
+		markSessionBindingViolation(ctx, targetURL, userSession.Username, violations)
+
+		switch config.Policy {
+		case schema.SessionBindingPolicyDestroy:
+			if err = ctx.DestroySession(); err != nil {
+				return false, fmt.Errorf("unable to destroy session for user '%s' after a session binding violation: %w", userSession.Username, err)
+			}
+
+			ctx.Logger.Warnf("Session destroyed for user '%s' after a session binding violation", userSession.Username)
+
+			return false, nil
+		case schema.SessionBindingPolicyReauthenticate:
+			if err = ctx.UpdateSession(userSession, func(userSession *session.UserSession) {
+				userSession.AuthenticationLevel = authentication.NotAuthenticated
+				userSession.ResetClientBinding()
+			}); err != nil {
+				return false, fmt.Errorf("unable to reset the authentication level of the session for user '%s' after a session binding violation: %w", userSession.Username, err)
+			}
+
+			ctx.Logger.Warnf("Session for user '%s' requires authentication after a session binding violation", userSession.Username)
+
+			return false, nil
+		}
+	}
+
+	if bindSessionClient(ctx, userSession) {
+		if err = updateSessionBestEffort(ctx, userSession, func(userSession *session.UserSession) {
+			bindSessionClient(ctx, userSession)
+		}); err != nil {
+			return false, fmt.Errorf("unable to save the session binding for user '%s': %w", userSession.Username, err)
+		}
+	}
+
+	return true, nil
+}
+
+// bindSessionClient binds the user session to the client characteristics of the request which it is not already bound
+// to. Returns true if the user session needs to be saved.
+func bindSessionClient(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) (changed bool) {
+	config := ctx.Configuration.Session.Binding
+
+	if !config.Enabled() {
+		return false
+	}
+
+	return userSession.BindClient(config, ctx.RemoteIP(), ctx.UserAgent())
+}
+
+// rotateSessionAntiReplayToken rotates the anti-replay token when it's due and sets the cookie on the response. It must
+// only be used for responses which are returned to the browser, i.e. the portal, as reverse proxies don't forward the
+// cookies set on the responses of authorization requests. The token is only rotated for clients which match the
+// session binding so a replayed session can't obtain a fresh token. Returns true if the user session needs to be saved.
+func rotateSessionAntiReplayToken(ctx *middlewares.AutheliaCtx, provider *session.Session, userSession *session.UserSession) (changed bool) {
+	config := ctx.Configuration.Session.Binding
+
+	if !config.Enabled() || !config.AntiReplay {
+		return false
+	}
+
+	now := ctx.Clock.Now()
+
+	if !userSession.IsAntiReplayTokenRotationDue(config, now) {
+		return false
+	}
+
+	if len(userSession.ClientBindingViolations(config, ctx.RemoteIP(), ctx.UserAgent(), provider.GetAntiReplayToken(ctx.RequestCtx))) != 0 {
+		return false
+	}
+
+	var expiration time.Duration
+
+	// The token cookie expires with the browser session unless the user session is remembered.
+	if userSession.KeepMeLoggedIn {
+		expiration = provider.Config.RememberMeDuration
+	}
+
+	provider.SetAntiReplayToken(ctx.RequestCtx, userSession.RotateAntiReplayToken(now), expiration)
+
+	return true
+}
+
+func markSessionBindingViolation(ctx *middlewares.AutheliaCtx, targetURL *url.URL, username string, violations []string) {
+	ctx.Logger.Warnf("Session binding violation for user '%s': the %s of the request does not match the session", username, strings.Join(violations, ", "))
+
+	var requestURI string
+
+	if targetURL != nil {
+		requestURI = targetURL.String()
+	}
+
+	if err := ctx.Providers.Regulator.Mark(ctx, false, false, username, requestURI, string(ctx.XForwardedMethod()), regulation.AuthTypeSessionBinding); err != nil {
+		ctx.Logger.Errorf("Unable to mark the session binding violation for user '%s': %+v", username, err)
+	}
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+func setSessionBindingTestConfig(mock *mocks.MockAutheliaCtx, policy string) {
+	mock.Ctx.Configuration.Session.Binding = schema.SessionBindingConfiguration{
+		IP:                         true,
+		IPv4Mask:                   24,
+		IPv6Mask:                   64,
+		UserAgent:                  true,
+		AntiReplay:                 true,
+		AntiReplayRotationInterval: time.Minute,
+		Policy:                     policy,
+	}
+}
+
+func TestVerifySessionBindingShouldBindAndAllowMatchingClient(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	setSessionBindingTestConfig(mock, schema.SessionBindingPolicyDestroy)
+	setUserSessionsTestSession(t, mock)
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-For", "192.168.1.20")
+	mock.Ctx.Request.Header.SetUserAgent("agent")
+
+	userSession := mock.Ctx.GetSession()
+
+	valid, err := verifySessionBinding(mock.Ctx, nil, &userSession)
+
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	userSession = mock.Ctx.GetSession()
+
+	assert.Equal(t, "192.168.1.0/24", userSession.Binding.IP)
+	assert.NotEqual(t, "", userSession.Binding.UserAgent)
+	assert.Equal(t, "", userSession.Binding.AntiReplayToken)
+	assert.Nil(t, mock.Ctx.Response.Header.PeekCookie("authelia_session_binding"))
+
+	mock.Ctx.Request.Header.Set("X-Forwarded-For", "192.168.1.50")
+
+	valid, err = verifySessionBinding(mock.Ctx, nil, &userSession)
+
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestVerifySessionBindingShouldApplyPolicyOnViolation(t *testing.T) {
+	testCases := []struct {
+		name      string
+		policy    string
+		valid     bool
+		username  string
+		level     authentication.Level
+		bindingIP string
+	}{
+		{"ShouldOnlyLog", schema.SessionBindingPolicyLog, true, testUsername, authentication.OneFactor, "192.168.1.0/24"},
+		{"ShouldRequireAuthentication", schema.SessionBindingPolicyReauthenticate, false, testUsername, authentication.NotAuthenticated, ""},
+		{"ShouldDestroySession", schema.SessionBindingPolicyDestroy, false, "", authentication.NotAuthenticated, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			mockActiveSession(mock)
+
+			setSessionBindingTestConfig(mock, tc.policy)
+			mock.Ctx.Configuration.Session.Binding.AntiReplay = false
+
+			userSession := mock.Ctx.GetSession()
+			userSession.Username = testUsername
+			userSession.AuthenticationLevel = authentication.OneFactor
+			userSession.Binding.IP = "192.168.1.0/24"
+
+			require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+			mock.Ctx.Request.Header.Set("X-Forwarded-For", "10.0.0.1")
+
+			mock.StorageMock.EXPECT().
+				AppendAuthenticationLog(mock.Ctx, gomock.Any()).
+				DoAndReturn(func(_ interface{}, attempt model.AuthenticationAttempt) error {
+					assert.Equal(t, testUsername, attempt.Username)
+					assert.Equal(t, regulation.AuthTypeSessionBinding, attempt.Type)
+					assert.Equal(t, "https://one-factor.example.com/", attempt.RequestURI)
+					assert.False(t, attempt.Successful)
+					assert.False(t, attempt.Banned)
+
+					return nil
+				})
+
+			valid, err := verifySessionBinding(mock.Ctx, &url.URL{Scheme: "https", Host: "one-factor.example.com", Path: "/"}, &userSession)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.valid, valid)
+
+			userSession = mock.Ctx.GetSession()
+
+			assert.Equal(t, tc.username, userSession.Username)
+			assert.Equal(t, tc.level, userSession.AuthenticationLevel)
+			assert.Equal(t, tc.bindingIP, userSession.Binding.IP)
+		})
+	}
+}
+
+func TestStateGETShouldRotateAntiReplayToken(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cookie  string
+		rotated bool
+	}{
+		{"ShouldRotateForMatchingClient", "current", true},
+		{"ShouldNotRotateForReplayedSession", "replayed", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			setSessionBindingTestConfig(mock, schema.SessionBindingPolicyLog)
+
+			userSession := mock.Ctx.GetSession()
+			userSession.Username = testUsername
+			userSession.AuthenticationLevel = authentication.OneFactor
+			userSession.Binding.AntiReplayToken = "current"
+			userSession.Binding.AntiReplayTokenRotated = mock.Clock.Now().Add(-time.Hour).Unix()
+
+			require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+			mock.Ctx.Request.Header.SetCookie("authelia_session_binding", tc.cookie)
+
+			StateGET(mock.Ctx)
+
+			assert.Equal(t, 200, mock.Ctx.Response.StatusCode())
+
+			userSession = mock.Ctx.GetSession()
+			cookie := mock.Ctx.Response.Header.PeekCookie("authelia_session_binding")
+
+			if tc.rotated {
+				assert.NotEqual(t, "current", userSession.Binding.AntiReplayToken)
+				assert.Equal(t, "current", userSession.Binding.AntiReplayTokenPrevious)
+				assert.Contains(t, string(cookie), userSession.Binding.AntiReplayToken)
+			} else {
+				assert.Equal(t, "current", userSession.Binding.AntiReplayToken)
+				assert.Nil(t, cookie)
+			}
+		})
+	}
+}
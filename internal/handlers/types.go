@@ -3,6 +3,7 @@ package handlers
 import (
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
@@ -144,6 +145,36 @@ type PasswordPolicyBody struct {
 	RequireSpecial   bool   `json:"require_special"`
 }
 
+// activeSessionResponse represents an active session of a user in the response of the sessions endpoints.
+type activeSessionResponse struct {
+	ID                  int       `json:"id"`
+	CookieDomain        string    `json:"cookie_domain"`
+	CreatedAt           time.Time `json:"created_at"`
+	LastActivityAt      time.Time `json:"last_activity_at"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	IP                  string    `json:"ip"`
+	UserAgent           string    `json:"user_agent"`
+	AuthenticationLevel string    `json:"authentication_level"`
+	Current             bool      `json:"current"`
+}
+
+// revokeActiveSessionsResponse represents the response of the endpoint revoking all active sessions of a user.
+type revokeActiveSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// oidcConsentResponse represents a response item of the endpoint listing the OpenID Connect 1.0 consents of a user.
+type oidcConsentResponse struct {
+	ID                int64      `json:"id"`
+	Type              string     `json:"type"`
+	ClientID          string     `json:"client_id"`
+	ClientDescription string     `json:"client_description"`
+	Scopes            []string   `json:"scopes"`
+	Audience          []string   `json:"audience"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+}
+
 type handlerAuthorizationConsent func(
 	ctx *middlewares.AutheliaCtx, issuer *url.URL, client *oidc.Client,
 	userSession session.UserSession, subject uuid.UUID,
@@ -25,6 +25,12 @@ func ctxGetPortalURL(ctx *middlewares.AutheliaCtx) (portalURL *url.URL) {
 		return portalURL
 	}
 
+	if provider, err := ctx.GetSessionProvider(); err == nil && provider.Config.AutheliaURL != nil {
+		portalURL, _ = url.ParseRequestURI(provider.Config.AutheliaURL.String())
+
+		return portalURL
+	}
+
 	return nil
 }
 
@@ -65,3 +71,25 @@ func ctxLogEvent(ctx *middlewares.AutheliaCtx, username, description string, eve
 		return
 	}
 }
+
+// ctxIsSecondFactorEnabled returns true if the second factor is required by a policy. The clients managed via the
+// storage provider are checked when the policies known at startup don't require it, as they may be registered using
+// the CLI at any time. The second factor is assumed to be required if the clients can't be checked.
+func ctxIsSecondFactorEnabled(ctx *middlewares.AutheliaCtx) bool {
+	if ctx.Providers.Authorizer.IsSecondFactorEnabled() {
+		return true
+	}
+
+	if ctx.Providers.OpenIDConnect == nil {
+		return false
+	}
+
+	enabled, err := ctx.Providers.OpenIDConnect.Store.HasTwoFactorStorageClient(ctx)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to determine if any OpenID Connect 1.0 client managed via the storage provider requires the two_factor policy: %+v", err)
+
+		return true
+	}
+
+	return enabled
+}
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -148,7 +149,7 @@ func (ctx *AutheliaCtx) ReplyBadRequest() {
 
 // XForwardedProto return the content of the X-Forwarded-Proto header.
 func (ctx *AutheliaCtx) XForwardedProto() (proto []byte) {
-	proto = ctx.RequestCtx.Request.Header.PeekBytes(headerXForwardedProto)
+	proto = ctx.trustedForwardedHeader(headerXForwardedProto)
 
 	if proto == nil {
 		if ctx.RequestCtx.IsTLS() {
@@ -163,12 +164,12 @@ func (ctx *AutheliaCtx) XForwardedProto() (proto []byte) {
 
 // XForwardedMethod return the content of the X-Forwarded-Method header.
 func (ctx *AutheliaCtx) XForwardedMethod() []byte {
-	return ctx.RequestCtx.Request.Header.PeekBytes(headerXForwardedMethod)
+	return ctx.trustedForwardedHeader(headerXForwardedMethod)
 }
 
 // XForwardedHost return the content of the X-Forwarded-Host header.
 func (ctx *AutheliaCtx) XForwardedHost() (host []byte) {
-	host = ctx.RequestCtx.Request.Header.PeekBytes(headerXForwardedHost)
+	host = ctx.trustedForwardedHeader(headerXForwardedHost)
 
 	if host == nil {
 		return ctx.RequestCtx.Host()
@@ -179,7 +180,7 @@ func (ctx *AutheliaCtx) XForwardedHost() (host []byte) {
 
 // XForwardedURI return the content of the X-Forwarded-URI header.
 func (ctx *AutheliaCtx) XForwardedURI() (uri []byte) {
-	uri = ctx.RequestCtx.Request.Header.PeekBytes(headerXForwardedURI)
+	uri = ctx.trustedForwardedHeader(headerXForwardedURI)
 
 	if len(uri) == 0 {
 		return ctx.RequestCtx.RequestURI()
@@ -190,12 +191,12 @@ func (ctx *AutheliaCtx) XForwardedURI() (uri []byte) {
 
 // XOriginalURL returns the content of the X-Original-URL header.
 func (ctx *AutheliaCtx) XOriginalURL() []byte {
-	return ctx.RequestCtx.Request.Header.PeekBytes(headerXOriginalURL)
+	return ctx.trustedForwardedHeader(headerXOriginalURL)
 }
 
 // XOriginalMethod return the content of the X-Original-Method header.
 func (ctx *AutheliaCtx) XOriginalMethod() []byte {
-	return ctx.RequestCtx.Request.Header.PeekBytes(headerXOriginalMethod)
+	return ctx.trustedForwardedHeader(headerXOriginalMethod)
 }
 
 // XAutheliaURL return the content of the X-Authelia-URL header which is used to communicate the location of the
@@ -245,9 +246,72 @@ func (ctx *AutheliaCtx) RootURLSlash() (issuerURL *url.URL) {
 	}
 }
 
+// GetSessionProvider returns the session provider for the session cookie domain of this request. The session cookie
+// domain is the one set by SetSessionCookieDomain if it has been called, otherwise it's determined by the host of the
+// request.
+func (ctx *AutheliaCtx) GetSessionProvider() (provider *session.Session, err error) {
+	domain := ctx.sessionCookieDomain
+
+	if domain == "" {
+		domain = string(ctx.XForwardedHost())
+
+		if host, _, err := net.SplitHostPort(domain); err == nil {
+			domain = host
+		}
+	}
+
+	return ctx.Providers.SessionProvider.Get(domain)
+}
+
+// SetSessionCookieDomain sets the session cookie domain used by GetSessionProvider for this request. This is used when
+// the session cookie is not for the host of the request, for example when authorizing a request to a target URL.
+func (ctx *AutheliaCtx) SetSessionCookieDomain(domain string) {
+	ctx.sessionCookieDomain = domain
+}
+
+// GetCookieDomainFromTargetURI returns the most specific session cookie domain which the target URI is equal to or a
+// subdomain of. An empty string is returned if the target URI is not within any of the session cookie domains.
+func (ctx *AutheliaCtx) GetCookieDomainFromTargetURI(targetURI *url.URL) (domain string) {
+	if targetURI == nil {
+		return ""
+	}
+
+	for _, cookie := range ctx.Configuration.Session.GetCookies() {
+		if utils.HasURIDomainSuffix(targetURI, cookie.Domain) && len(cookie.Domain) > len(domain) {
+			domain = cookie.Domain
+		}
+	}
+
+	return domain
+}
+
+// IsSafeRedirectionTargetURI returns true if the target URI has a secure scheme and is within one of the session
+// cookie domains.
+func (ctx *AutheliaCtx) IsSafeRedirectionTargetURI(targetURI *url.URL) bool {
+	return targetURI != nil && utils.IsURISecure(targetURI) && ctx.GetCookieDomainFromTargetURI(targetURI) != ""
+}
+
+// IsSafeRedirectionTargetURIString is the same as IsSafeRedirectionTargetURI except it parses the target URI first.
+func (ctx *AutheliaCtx) IsSafeRedirectionTargetURIString(targetURI string) (safe bool, err error) {
+	var parsedURI *url.URL
+
+	if parsedURI, err = url.ParseRequestURI(targetURI); err != nil {
+		return false, fmt.Errorf("failed to parse URI '%s': %w", targetURI, err)
+	}
+
+	return ctx.IsSafeRedirectionTargetURI(parsedURI), nil
+}
+
 // GetSession return the user session. Any update will be saved in cache.
 func (ctx *AutheliaCtx) GetSession() session.UserSession {
-	userSession, err := ctx.Providers.SessionProvider.GetSession(ctx.RequestCtx)
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		ctx.Logger.WithError(err).Error("Unable to retrieve user session provider")
+
+		return session.NewDefaultUserSession()
+	}
+
+	userSession, err := provider.GetSession(ctx.RequestCtx)
 	if err != nil {
 		ctx.Logger.Error("Unable to retrieve user session")
 		return session.NewDefaultUserSession()
@@ -258,7 +322,60 @@ func (ctx *AutheliaCtx) GetSession() session.UserSession {
 
 // SaveSession save the content of the session.
 func (ctx *AutheliaCtx) SaveSession(userSession session.UserSession) error {
-	return ctx.Providers.SessionProvider.SaveSession(ctx.RequestCtx, userSession)
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return fmt.Errorf("unable to save user session: %w", err)
+	}
+
+	return provider.SaveSession(ctx.RequestCtx, userSession)
+}
+
+// UpdateSession applies the update to the user session and saves it, applying the update again to the latest version of
+// the session if it was concurrently saved by another request.
+func (ctx *AutheliaCtx) UpdateSession(userSession *session.UserSession, update func(userSession *session.UserSession)) error {
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return fmt.Errorf("unable to update user session: %w", err)
+	}
+
+	return provider.UpdateSession(ctx.RequestCtx, userSession, update)
+}
+
+// RegenerateSession regenerates the session ID of the user session.
+func (ctx *AutheliaCtx) RegenerateSession() error {
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return fmt.Errorf("unable to regenerate user session: %w", err)
+	}
+
+	return provider.RegenerateSession(ctx.RequestCtx)
+}
+
+// DestroySession destroys the user session, removes it from the active sessions, and deletes the session cookie.
+func (ctx *AutheliaCtx) DestroySession() error {
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return fmt.Errorf("unable to destroy user session: %w", err)
+	}
+
+	if id := provider.GetSessionIDHash(ctx.RequestCtx); id != "" && ctx.Providers.StorageProvider != nil {
+		if err = ctx.Providers.StorageProvider.RevokeActiveSession(ctx, id); err != nil {
+			ctx.Logger.WithError(err).Error("Unable to remove the active session for the destroyed user session")
+		}
+	}
+
+	return provider.DestroySession(ctx.RequestCtx)
+}
+
+// GetSessionIDHash returns the hex encoded SHA256 hash of the session ID of the request or an empty string if there
+// is no session cookie.
+func (ctx *AutheliaCtx) GetSessionIDHash() string {
+	provider, err := ctx.GetSessionProvider()
+	if err != nil {
+		return ""
+	}
+
+	return provider.GetSessionIDHash(ctx.RequestCtx)
 }
 
 // ReplyOK is a helper method to reply ok.
@@ -295,19 +412,48 @@ func (ctx *AutheliaCtx) SetJSONBody(value any) error {
 
 // RemoteIP return the remote IP taking X-Forwarded-For header into account if provided.
 func (ctx *AutheliaCtx) RemoteIP() net.IP {
-	XForwardedFor := ctx.Request.Header.PeekBytes(headerXForwardedFor)
-	if XForwardedFor != nil {
-		ips := strings.Split(string(XForwardedFor), ",")
+	xff := ctx.Request.Header.PeekBytes(headerXForwardedFor)
 
-		if len(ips) > 0 {
-			return net.ParseIP(strings.Trim(ips[0], " "))
-		}
+	ip, trusted := utils.ParseXForwardedFor(ctx.RequestCtx.RemoteIP(), xff, ctx.Configuration.Server.TrustedProxies)
+
+	if !trusted && xff != nil {
+		ctx.warnUntrustedForwardedHeader(headerXForwardedFor)
+	}
+
+	return ip
+}
+
+// IsTrustedProxy returns true if the direct peer of the request is a trusted proxy.
+func (ctx *AutheliaCtx) IsTrustedProxy() bool {
+	return utils.IsIPInNetworks(ctx.RequestCtx.RemoteIP(), ctx.Configuration.Server.TrustedProxies)
+}
+
+// trustedForwardedHeader returns the value of a forwarded header only if the direct peer of the request is a trusted
+// proxy, otherwise it returns nil.
+func (ctx *AutheliaCtx) trustedForwardedHeader(header []byte) (value []byte) {
+	if value = ctx.RequestCtx.Request.Header.PeekBytes(header); value == nil {
+		return nil
+	}
+
+	if !ctx.IsTrustedProxy() {
+		ctx.warnUntrustedForwardedHeader(header)
+
+		return nil
+	}
+
+	return value
+}
+
+func (ctx *AutheliaCtx) warnUntrustedForwardedHeader(header []byte) {
+	if ctx.Logger == nil {
+		return
 	}
 
-	return ctx.RequestCtx.RemoteIP()
+	ctx.Logger.Warnf("Ignoring the '%s' header as the request was sent from '%s' which is not a trusted proxy", header, ctx.RequestCtx.RemoteIP())
 }
 
-// GetOriginalURL extract the URL from the request headers (X-Original-URL or X-Forwarded-* headers).
+// GetOriginalURL extract the URL from the request headers (X-Original-URL or X-Forwarded-* headers). The headers are
+// only honoured when sent by a trusted proxy, otherwise the URL of the request itself is used.
 func (ctx *AutheliaCtx) GetOriginalURL() (*url.URL, error) {
 	originalURL := ctx.XOriginalURL()
 	if originalURL != nil {
@@ -348,6 +494,82 @@ func (ctx *AutheliaCtx) GetOriginalURL() (*url.URL, error) {
 	return parsedURL, nil
 }
 
+// GetXForwardedURL extracts the URL from the X-Forwarded-Proto, X-Forwarded-Host, and X-Forwarded-URI headers. The
+// headers are only honoured when sent by a trusted proxy, and unlike GetOriginalURL the X-Forwarded-URI header does not
+// fall back to the URI of the request itself.
+func (ctx *AutheliaCtx) GetXForwardedURL() (requestURL *url.URL, err error) {
+	forwardedProto, forwardedHost, forwardedURI := ctx.XForwardedProto(), ctx.XForwardedHost(), ctx.trustedForwardedHeader(headerXForwardedURI)
+
+	switch {
+	case len(forwardedProto) == 0:
+		return nil, errMissingXForwardedProto
+	case len(forwardedHost) == 0:
+		return nil, errMissingXForwardedHost
+	case len(forwardedURI) == 0:
+		return nil, errMissingXForwardedURI
+	}
+
+	return ctx.parseXForwardedURL(forwardedProto, forwardedHost, forwardedURI)
+}
+
+// GetXOriginalURL extracts the URL from the X-Original-URL header. The header is only honoured when sent by a trusted
+// proxy.
+func (ctx *AutheliaCtx) GetXOriginalURL() (requestURL *url.URL, err error) {
+	originalURL := ctx.XOriginalURL()
+
+	if len(originalURL) == 0 {
+		return nil, errMissingXOriginalURL
+	}
+
+	if requestURL, err = url.ParseRequestURI(string(originalURL)); err != nil {
+		return nil, fmt.Errorf("failed to parse X-Original-URL header with value '%s': %w", originalURL, err)
+	}
+
+	return requestURL, nil
+}
+
+// GetXForwardedProtoHostAuthzPathURL extracts the URL from the X-Forwarded-Proto and X-Forwarded-Host headers (falling
+// back to the scheme and Host header of the request) and the path and query of the request following the authz path
+// prefix.
+func (ctx *AutheliaCtx) GetXForwardedProtoHostAuthzPathURL() (requestURL *url.URL, err error) {
+	forwardedProto, forwardedHost := ctx.XForwardedProto(), ctx.XForwardedHost()
+
+	switch {
+	case len(forwardedProto) == 0:
+		return nil, errMissingXForwardedProto
+	case len(forwardedHost) == 0:
+		return nil, errMissingXForwardedHost
+	}
+
+	uri := append([]byte(strSlash), bytes.TrimPrefix(ctx.AuthzPath(), []byte(strSlash))...)
+
+	if qry := ctx.URI().QueryString(); len(qry) != 0 {
+		uri = append(append(uri, '?'), qry...)
+	}
+
+	return ctx.parseXForwardedURL(forwardedProto, forwardedHost, uri)
+}
+
+func (ctx *AutheliaCtx) parseXForwardedURL(forwardedProto, forwardedHost, forwardedURI []byte) (requestURL *url.URL, err error) {
+	value := bytes.Join([][]byte{forwardedProto, protoHostSeparator, forwardedHost, forwardedURI}, nil)
+
+	if requestURL, err = url.ParseRequestURI(string(value)); err != nil {
+		return nil, fmt.Errorf("failed to parse X-Forwarded Headers with value '%s': %w", value, err)
+	}
+
+	return requestURL, nil
+}
+
+// AuthzPath returns the path of the request after the authz endpoint prefix which is used by the ExtAuthz
+// implementation.
+func (ctx *AutheliaCtx) AuthzPath() (uri []byte) {
+	if uv := ctx.UserValueBytes(UserValueKeyAuthzPath); uv != nil {
+		return []byte(uv.(string))
+	}
+
+	return nil
+}
+
 // IsXHR returns true if the request is a XMLHttpRequest.
 func (ctx *AutheliaCtx) IsXHR() (xhr bool) {
 	requestedWith := ctx.Request.Header.PeekBytes(headerXRequestedWith)
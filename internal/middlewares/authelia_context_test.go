@@ -1,10 +1,12 @@
 package middlewares_test
 
 import (
+	"net"
 	"net/url"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
@@ -68,7 +70,7 @@ func TestShouldCallNextWithAutheliaCtx(t *testing.T) {
 	ctx := &fasthttp.RequestCtx{}
 	configuration := schema.Configuration{}
 	userProvider := mocks.NewMockUserProvider(ctrl)
-	sessionProvider := session.NewProvider(configuration.Session, nil)
+	sessionProvider := session.NewProvider(configuration.Session, nil, nil)
 	providers := middlewares.Providers{
 		UserProvider:    userProvider,
 		SessionProvider: sessionProvider,
@@ -151,6 +153,101 @@ func TestShouldOnlyFallbackToNonXForwardedHeadersWhenNil(t *testing.T) {
 	assert.Equal(t, []byte("GET"), mock.Ctx.XForwardedMethod())
 }
 
+func TestShouldIgnoreXForwardedHeadersFromUntrustedProxy(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.RequestCtx.SetRemoteAddr(&net.TCPAddr{IP: net.ParseIP("203.0.113.10"), Port: 50000})
+	mock.Ctx.RequestCtx.Request.SetRequestURI("/api/authz/forward-auth")
+	mock.Ctx.RequestCtx.Request.SetHost("localhost")
+	mock.Ctx.RequestCtx.Request.Header.Set(fasthttp.HeaderXForwardedHost, "auth.example.com:1234")
+	mock.Ctx.RequestCtx.Request.Header.Set(fasthttp.HeaderXForwardedProto, "https")
+	mock.Ctx.RequestCtx.Request.Header.Set("X-Forwarded-URI", "/admin")
+	mock.Ctx.RequestCtx.Request.Header.Set("X-Forwarded-Method", "DELETE")
+	mock.Ctx.RequestCtx.Request.Header.Set(fasthttp.HeaderXForwardedFor, "198.51.100.1")
+
+	assert.Equal(t, []byte("http"), mock.Ctx.XForwardedProto())
+	assert.Equal(t, []byte("localhost"), mock.Ctx.XForwardedHost())
+	assert.Equal(t, []byte("/api/authz/forward-auth"), mock.Ctx.XForwardedURI())
+	assert.Nil(t, mock.Ctx.XForwardedMethod())
+
+	requestURL, err := mock.Ctx.GetXForwardedURL()
+	assert.Nil(t, requestURL)
+	assert.EqualError(t, err, "Missing header X-Forwarded-URI")
+
+	requestURL, err = mock.Ctx.GetXForwardedProtoHostAuthzPathURL()
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost/", requestURL.String())
+
+	assert.Equal(t, "203.0.113.10", mock.Ctx.RemoteIP().String())
+
+	require.NotNil(t, mock.Hook.LastEntry())
+	assert.Equal(t, logrus.WarnLevel, mock.Hook.LastEntry().Level)
+	assert.Equal(t, "Ignoring the 'X-Forwarded-For' header as the request was sent from '203.0.113.10' which is not a trusted proxy", mock.Hook.LastEntry().Message)
+}
+
+func TestShouldIgnoreXOriginalHeadersFromUntrustedProxy(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.RequestCtx.SetRemoteAddr(&net.TCPAddr{IP: net.ParseIP("203.0.113.10"), Port: 50000})
+	mock.Ctx.RequestCtx.Request.SetRequestURI("/api/verify")
+	mock.Ctx.RequestCtx.Request.SetHost("localhost")
+	mock.Ctx.RequestCtx.Request.Header.Set("X-Original-URL", "https://admin.example.com/")
+	mock.Ctx.RequestCtx.Request.Header.Set("X-Original-Method", "DELETE")
+
+	assert.Nil(t, mock.Ctx.XOriginalURL())
+	assert.Nil(t, mock.Ctx.XOriginalMethod())
+
+	requestURL, err := mock.Ctx.GetXOriginalURL()
+	assert.Nil(t, requestURL)
+	assert.EqualError(t, err, "Missing header X-Original-URL")
+
+	// The URL of the request itself is used instead.
+	requestURL, err = mock.Ctx.GetOriginalURL()
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost/api/verify", requestURL.String())
+
+	require.NotNil(t, mock.Hook.LastEntry())
+	assert.Equal(t, logrus.WarnLevel, mock.Hook.LastEntry().Level)
+	assert.Equal(t, "Ignoring the 'X-Original-URL' header as the request was sent from '203.0.113.10' which is not a trusted proxy", mock.Hook.LastEntry().Message)
+
+	mock.Ctx.RequestCtx.SetRemoteAddr(&net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 50000})
+
+	assert.Equal(t, []byte("https://admin.example.com/"), mock.Ctx.XOriginalURL())
+	assert.Equal(t, []byte("DELETE"), mock.Ctx.XOriginalMethod())
+
+	requestURL, err = mock.Ctx.GetXOriginalURL()
+	require.NoError(t, err)
+	assert.Equal(t, "https://admin.example.com/", requestURL.String())
+}
+
+func TestShouldGetRemoteIPFromTrustedProxy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{"ShouldUsePeerWithoutHeader", "", "127.0.0.1"},
+		{"ShouldUseOnlyHop", "198.51.100.1", "198.51.100.1"},
+		{"ShouldUseFirstUntrustedHopFromRight", "203.0.113.5, 198.51.100.1, 10.0.0.1", "198.51.100.1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			if tc.header != "" {
+				mock.Ctx.RequestCtx.Request.Header.Set(fasthttp.HeaderXForwardedFor, tc.header)
+			}
+
+			assert.True(t, mock.Ctx.IsTrustedProxy())
+			assert.Equal(t, tc.expected, mock.Ctx.RemoteIP().String())
+		})
+	}
+}
+
 func TestShouldDetectXHR(t *testing.T) {
 	mock := mocks.NewMockAutheliaCtx(t)
 	defer mock.Close()
@@ -191,3 +288,47 @@ func TestShouldReturnCorrectSecondFactorMethods(t *testing.T) {
 
 	assert.Equal(t, []string{}, mock.Ctx.AvailableSecondFactorMethods())
 }
+
+func TestShouldGetCookieDomainFromTargetURI(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.Session.Cookies = []schema.SessionCookieConfiguration{
+		{Domain: "example.org"},
+		{Domain: "secure.example.org"},
+	}
+
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+		safe     bool
+	}{
+		{"ShouldMatchLegacyDomain", "https://app.example.com/", "example.com", true},
+		{"ShouldMatchCookieDomain", "https://example.org/", "example.org", true},
+		{"ShouldMatchMostSpecificCookieDomain", "https://app.secure.example.org/", "secure.example.org", true},
+		{"ShouldNotMatchUnknownDomain", "https://app.example.net/", "", false},
+		{"ShouldNotMatchSuffixWithoutDot", "https://notexample.org/", "", false},
+		{"ShouldMatchButNotBeSafeWithInsecureScheme", "http://app.example.org/", "example.org", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			targetURI, err := url.ParseRequestURI(tc.have)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, mock.Ctx.GetCookieDomainFromTargetURI(targetURI))
+			assert.Equal(t, tc.safe, mock.Ctx.IsSafeRedirectionTargetURI(targetURI))
+
+			safe, err := mock.Ctx.IsSafeRedirectionTargetURIString(tc.have)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.safe, safe)
+		})
+	}
+
+	assert.Equal(t, "", mock.Ctx.GetCookieDomainFromTargetURI(nil))
+	assert.False(t, mock.Ctx.IsSafeRedirectionTargetURI(nil))
+
+	_, err := mock.Ctx.IsSafeRedirectionTargetURIString("not a uri")
+	assert.EqualError(t, err, "failed to parse URI 'not a uri': parse \"not a uri\": invalid URI for request")
+}
@@ -83,6 +83,10 @@ var (
 	// UserValueKeyBaseURL is the User Value key where we store the Base URL.
 	UserValueKeyBaseURL = []byte("base_url")
 
+	// UserValueKeyAuthzPath is the User Value key where the router stores the path of authz endpoints which accept
+	// prefixes.
+	UserValueKeyAuthzPath = []byte("authz_path")
+
 	headerSeparator = []byte(", ")
 
 	contentTypeTextPlain       = []byte("text/plain; charset=utf-8")
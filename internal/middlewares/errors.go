@@ -4,3 +4,5 @@ import "errors"
 
 var errMissingXForwardedHost = errors.New("Missing header X-Forwarded-Host")
 var errMissingXForwardedProto = errors.New("Missing header X-Forwarded-Proto")
+var errMissingXForwardedURI = errors.New("Missing header X-Forwarded-URI")
+var errMissingXOriginalURL = errors.New("Missing header X-Original-URL")
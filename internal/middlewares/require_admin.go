@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// RequireAdmin returns a middleware which checks the user has completed two factor authentication and is a member
+// of at least one of the administrative groups before executing the next handler.
+func RequireAdmin(groups []string) AutheliaMiddleware {
+	return func(next RequestHandler) RequestHandler {
+		return func(ctx *AutheliaCtx) {
+			userSession := ctx.GetSession()
+
+			if userSession.AuthenticationLevel < authentication.TwoFactor || !utils.IsStringSliceContainsAny(groups, userSession.Groups) {
+				ctx.Logger.Warnf("Access to the administrative endpoint '%s' is forbidden to user '%s'", ctx.Path(), userSession.Username)
+
+				ctx.ReplyForbidden()
+
+				return
+			}
+
+			next(ctx)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package middlewares_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	testCases := []struct {
+		name     string
+		level    authentication.Level
+		groups   []string
+		expected int
+	}{
+		{"ShouldAllowTwoFactorAdmin", authentication.TwoFactor, []string{"dev", "admins"}, fasthttp.StatusOK},
+		{"ShouldForbidOneFactorAdmin", authentication.OneFactor, []string{"admins"}, fasthttp.StatusForbidden},
+		{"ShouldForbidTwoFactorUser", authentication.TwoFactor, []string{"dev"}, fasthttp.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := mocks.NewMockAutheliaCtx(t)
+			defer mock.Close()
+
+			userSession := mock.Ctx.GetSession()
+			userSession.Username = "john"
+			userSession.AuthenticationLevel = tc.level
+			userSession.Groups = tc.groups
+
+			require.NoError(t, mock.Ctx.SaveSession(userSession))
+
+			middlewares.RequireAdmin([]string{"admins"})(func(ctx *middlewares.AutheliaCtx) {
+				ctx.SetStatusCode(fasthttp.StatusOK)
+			})(mock.Ctx)
+
+			assert.Equal(t, tc.expected, mock.Ctx.Response.StatusCode())
+		})
+	}
+}
@@ -30,22 +30,25 @@ type AutheliaCtx struct {
 	Clock utils.Clock
 
 	values map[any]any
+
+	sessionCookieDomain string
 }
 
 // Providers contain all provider provided to Authelia.
 type Providers struct {
-	Authorizer      *authorization.Authorizer
-	SessionProvider *session.Provider
-	Regulator       *regulation.Regulator
-	OpenIDConnect   *oidc.OpenIDConnectProvider
-	Metrics         metrics.Provider
-	NTP             *ntp.Provider
-	UserProvider    authentication.UserProvider
-	StorageProvider storage.Provider
-	Notifier        notification.Notifier
-	Templates       *templates.Provider
-	TOTP            totp.Provider
-	PasswordPolicy  PasswordPolicyProvider
+	Authorizer        *authorization.Authorizer
+	SessionProvider   *session.Provider
+	Regulator         *regulation.Regulator
+	OpenIDConnect     *oidc.OpenIDConnectProvider
+	IdentityAssertion *oidc.JWK
+	Metrics           metrics.Provider
+	NTP               *ntp.Provider
+	UserProvider      authentication.UserProvider
+	StorageProvider   storage.Provider
+	Notifier          notification.Notifier
+	Templates         *templates.Provider
+	TOTP              totp.Provider
+	PasswordPolicy    PasswordPolicyProvider
 }
 
 // RequestHandler represents an Authelia request handler.
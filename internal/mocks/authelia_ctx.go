@@ -3,6 +3,7 @@ package mocks
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"testing"
 	"time"
 
@@ -49,6 +50,7 @@ func NewMockAutheliaCtx(t *testing.T) *MockAutheliaCtx {
 	mockAuthelia.Clock.Set(datetime)
 
 	config := schema.Configuration{}
+	config.Server.TrustedProxies = schema.DefaultServerConfiguration.TrustedProxies
 	config.Session.RememberMeDuration = schema.DefaultSessionConfiguration.RememberMeDuration
 	config.Session.Name = "authelia_session"
 	config.Session.Domain = "example.com"
@@ -91,7 +93,7 @@ func NewMockAutheliaCtx(t *testing.T) *MockAutheliaCtx {
 		&config)
 
 	providers.SessionProvider = session.NewProvider(
-		config.Session, nil)
+		config.Session, nil, nil)
 
 	providers.Regulator = regulation.NewRegulator(config.Regulation, providers.StorageProvider, &mockAuthelia.Clock)
 
@@ -105,6 +107,8 @@ func NewMockAutheliaCtx(t *testing.T) *MockAutheliaCtx {
 	}
 
 	request := &fasthttp.RequestCtx{}
+	// Simulate requests being received via a reverse proxy on the loopback interface.
+	request.SetRemoteAddr(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 60000})
 	// Set a cookie to identify this client throughout the test.
 	// request.Request.Header.SetCookie("authelia_session", "client_cookie").
 
@@ -111,6 +111,35 @@ func (mr *MockStorageMockRecorder) ConsumeIdentityVerification(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeIdentityVerification", reflect.TypeOf((*MockStorage)(nil).ConsumeIdentityVerification), arg0, arg1, arg2)
 }
 
+// CountSessionData mocks base method.
+func (m *MockStorage) CountSessionData(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSessionData", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSessionData indicates an expected call of CountSessionData.
+func (mr *MockStorageMockRecorder) CountSessionData(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSessionData", reflect.TypeOf((*MockStorage)(nil).CountSessionData), arg0)
+}
+
+// DeactivateOAuth2DeviceCodeSession mocks base method.
+func (m *MockStorage) DeactivateOAuth2DeviceCodeSession(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateOAuth2DeviceCodeSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateOAuth2DeviceCodeSession indicates an expected call of DeactivateOAuth2DeviceCodeSession.
+func (mr *MockStorageMockRecorder) DeactivateOAuth2DeviceCodeSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateOAuth2DeviceCodeSession", reflect.TypeOf((*MockStorage)(nil).DeactivateOAuth2DeviceCodeSession), arg0, arg1)
+}
+
 // DeactivateOAuth2Session mocks base method.
 func (m *MockStorage) DeactivateOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -139,6 +168,35 @@ func (mr *MockStorageMockRecorder) DeactivateOAuth2SessionByRequestID(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateOAuth2SessionByRequestID", reflect.TypeOf((*MockStorage)(nil).DeactivateOAuth2SessionByRequestID), arg0, arg1, arg2)
 }
 
+// DeleteExpiredSessionData mocks base method.
+func (m *MockStorage) DeleteExpiredSessionData(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredSessionData", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredSessionData indicates an expected call of DeleteExpiredSessionData.
+func (mr *MockStorageMockRecorder) DeleteExpiredSessionData(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredSessionData", reflect.TypeOf((*MockStorage)(nil).DeleteExpiredSessionData), arg0, arg1)
+}
+
+// DeleteOAuth2Client mocks base method.
+func (m *MockStorage) DeleteOAuth2Client(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOAuth2Client", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOAuth2Client indicates an expected call of DeleteOAuth2Client.
+func (mr *MockStorageMockRecorder) DeleteOAuth2Client(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOAuth2Client", reflect.TypeOf((*MockStorage)(nil).DeleteOAuth2Client), arg0, arg1)
+}
+
 // DeletePreferredDuoDevice mocks base method.
 func (m *MockStorage) DeletePreferredDuoDevice(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -153,6 +211,20 @@ func (mr *MockStorageMockRecorder) DeletePreferredDuoDevice(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).DeletePreferredDuoDevice), arg0, arg1)
 }
 
+// DeleteSessionData mocks base method.
+func (m *MockStorage) DeleteSessionData(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessionData", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessionData indicates an expected call of DeleteSessionData.
+func (mr *MockStorageMockRecorder) DeleteSessionData(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessionData", reflect.TypeOf((*MockStorage)(nil).DeleteSessionData), arg0, arg1)
+}
+
 // DeleteTOTPConfiguration mocks base method.
 func (m *MockStorage) DeleteTOTPConfiguration(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -210,6 +282,50 @@ func (mr *MockStorageMockRecorder) FindIdentityVerification(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentityVerification", reflect.TypeOf((*MockStorage)(nil).FindIdentityVerification), arg0, arg1)
 }
 
+// InsertOAuth2BlacklistedJTI mocks base method.
+func (m *MockStorage) InsertOAuth2BlacklistedJTI(arg0 context.Context, arg1 model.OAuth2BlacklistedJTI, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOAuth2BlacklistedJTI", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertOAuth2BlacklistedJTI indicates an expected call of InsertOAuth2BlacklistedJTI.
+func (mr *MockStorageMockRecorder) InsertOAuth2BlacklistedJTI(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOAuth2BlacklistedJTI", reflect.TypeOf((*MockStorage)(nil).InsertOAuth2BlacklistedJTI), arg0, arg1, arg2)
+}
+
+// LoadActiveSession mocks base method.
+func (m *MockStorage) LoadActiveSession(arg0 context.Context, arg1 string) (*model.ActiveSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadActiveSession", arg0, arg1)
+	ret0, _ := ret[0].(*model.ActiveSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadActiveSession indicates an expected call of LoadActiveSession.
+func (mr *MockStorageMockRecorder) LoadActiveSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadActiveSession", reflect.TypeOf((*MockStorage)(nil).LoadActiveSession), arg0, arg1)
+}
+
+// LoadActiveSessionsByUsername mocks base method.
+func (m *MockStorage) LoadActiveSessionsByUsername(arg0 context.Context, arg1 string, arg2 time.Time) ([]model.ActiveSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadActiveSessionsByUsername", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.ActiveSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadActiveSessionsByUsername indicates an expected call of LoadActiveSessionsByUsername.
+func (mr *MockStorageMockRecorder) LoadActiveSessionsByUsername(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadActiveSessionsByUsername", reflect.TypeOf((*MockStorage)(nil).LoadActiveSessionsByUsername), arg0, arg1, arg2)
+}
+
 // LoadAuthenticationLogs mocks base method.
 func (m *MockStorage) LoadAuthenticationLogs(arg0 context.Context, arg1 string, arg2 time.Time, arg3, arg4 int) ([]model.AuthenticationAttempt, error) {
 	m.ctrl.T.Helper()
@@ -225,6 +341,36 @@ func (mr *MockStorageMockRecorder) LoadAuthenticationLogs(arg0, arg1, arg2, arg3
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAuthenticationLogs", reflect.TypeOf((*MockStorage)(nil).LoadAuthenticationLogs), arg0, arg1, arg2, arg3, arg4)
 }
 
+// LoadAuthenticationLogsByRemoteIP mocks base method.
+func (m *MockStorage) LoadAuthenticationLogsByRemoteIP(arg0 context.Context, arg1 model.NullIP, arg2 string, arg3 time.Time, arg4, arg5 int) ([]model.AuthenticationAttempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadAuthenticationLogsByRemoteIP", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].([]model.AuthenticationAttempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadAuthenticationLogsByRemoteIP indicates an expected call of LoadAuthenticationLogsByRemoteIP.
+func (mr *MockStorageMockRecorder) LoadAuthenticationLogsByRemoteIP(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAuthenticationLogsByRemoteIP", reflect.TypeOf((*MockStorage)(nil).LoadAuthenticationLogsByRemoteIP), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// LoadOAuth2AccessTokenJTIRevoked mocks base method.
+func (m *MockStorage) LoadOAuth2AccessTokenJTIRevoked(arg0 context.Context, arg1 string, arg2 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2AccessTokenJTIRevoked", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2AccessTokenJTIRevoked indicates an expected call of LoadOAuth2AccessTokenJTIRevoked.
+func (mr *MockStorageMockRecorder) LoadOAuth2AccessTokenJTIRevoked(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2AccessTokenJTIRevoked", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2AccessTokenJTIRevoked), arg0, arg1, arg2)
+}
+
 // LoadOAuth2BlacklistedJTI mocks base method.
 func (m *MockStorage) LoadOAuth2BlacklistedJTI(arg0 context.Context, arg1 string) (*model.OAuth2BlacklistedJTI, error) {
 	m.ctrl.T.Helper()
@@ -240,6 +386,36 @@ func (mr *MockStorageMockRecorder) LoadOAuth2BlacklistedJTI(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2BlacklistedJTI", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2BlacklistedJTI), arg0, arg1)
 }
 
+// LoadOAuth2Client mocks base method.
+func (m *MockStorage) LoadOAuth2Client(arg0 context.Context, arg1 string) (*model.OAuth2Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2Client", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2Client indicates an expected call of LoadOAuth2Client.
+func (mr *MockStorageMockRecorder) LoadOAuth2Client(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2Client", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2Client), arg0, arg1)
+}
+
+// LoadOAuth2Clients mocks base method.
+func (m *MockStorage) LoadOAuth2Clients(arg0 context.Context, arg1, arg2 int) ([]model.OAuth2Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2Clients", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.OAuth2Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2Clients indicates an expected call of LoadOAuth2Clients.
+func (mr *MockStorageMockRecorder) LoadOAuth2Clients(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2Clients", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2Clients), arg0, arg1, arg2)
+}
+
 // LoadOAuth2ConsentPreConfigurations mocks base method.
 func (m *MockStorage) LoadOAuth2ConsentPreConfigurations(arg0 context.Context, arg1 string, arg2 uuid.UUID) (*storage.ConsentPreConfigRows, error) {
 	m.ctrl.T.Helper()
@@ -255,6 +431,21 @@ func (mr *MockStorageMockRecorder) LoadOAuth2ConsentPreConfigurations(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentPreConfigurations", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentPreConfigurations), arg0, arg1, arg2)
 }
 
+// LoadOAuth2ConsentPreConfigurationsByUsername mocks base method.
+func (m *MockStorage) LoadOAuth2ConsentPreConfigurationsByUsername(arg0 context.Context, arg1 string) ([]model.OAuth2ConsentPreConfig, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2ConsentPreConfigurationsByUsername", arg0, arg1)
+	ret0, _ := ret[0].([]model.OAuth2ConsentPreConfig)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2ConsentPreConfigurationsByUsername indicates an expected call of LoadOAuth2ConsentPreConfigurationsByUsername.
+func (mr *MockStorageMockRecorder) LoadOAuth2ConsentPreConfigurationsByUsername(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentPreConfigurationsByUsername", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentPreConfigurationsByUsername), arg0, arg1)
+}
+
 // LoadOAuth2ConsentSessionByChallengeID mocks base method.
 func (m *MockStorage) LoadOAuth2ConsentSessionByChallengeID(arg0 context.Context, arg1 uuid.UUID) (*model.OAuth2ConsentSession, error) {
 	m.ctrl.T.Helper()
@@ -270,6 +461,96 @@ func (mr *MockStorageMockRecorder) LoadOAuth2ConsentSessionByChallengeID(arg0, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentSessionByChallengeID", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentSessionByChallengeID), arg0, arg1)
 }
 
+// LoadOAuth2ConsentSessionsByUsername mocks base method.
+func (m *MockStorage) LoadOAuth2ConsentSessionsByUsername(arg0 context.Context, arg1 string) ([]model.OAuth2ConsentSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2ConsentSessionsByUsername", arg0, arg1)
+	ret0, _ := ret[0].([]model.OAuth2ConsentSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2ConsentSessionsByUsername indicates an expected call of LoadOAuth2ConsentSessionsByUsername.
+func (mr *MockStorageMockRecorder) LoadOAuth2ConsentSessionsByUsername(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2ConsentSessionsByUsername", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2ConsentSessionsByUsername), arg0, arg1)
+}
+
+// LoadOAuth2DeviceCodeSession mocks base method.
+func (m *MockStorage) LoadOAuth2DeviceCodeSession(arg0 context.Context, arg1 string) (*model.OAuth2DeviceCodeSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2DeviceCodeSession", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2DeviceCodeSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2DeviceCodeSession indicates an expected call of LoadOAuth2DeviceCodeSession.
+func (mr *MockStorageMockRecorder) LoadOAuth2DeviceCodeSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2DeviceCodeSession", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2DeviceCodeSession), arg0, arg1)
+}
+
+// LoadOAuth2DeviceCodeSessionByChallengeID mocks base method.
+func (m *MockStorage) LoadOAuth2DeviceCodeSessionByChallengeID(arg0 context.Context, arg1 uuid.UUID) (*model.OAuth2DeviceCodeSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2DeviceCodeSessionByChallengeID", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2DeviceCodeSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2DeviceCodeSessionByChallengeID indicates an expected call of LoadOAuth2DeviceCodeSessionByChallengeID.
+func (mr *MockStorageMockRecorder) LoadOAuth2DeviceCodeSessionByChallengeID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2DeviceCodeSessionByChallengeID", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2DeviceCodeSessionByChallengeID), arg0, arg1)
+}
+
+// LoadOAuth2DeviceCodeSessionByUserCodeSignature mocks base method.
+func (m *MockStorage) LoadOAuth2DeviceCodeSessionByUserCodeSignature(arg0 context.Context, arg1 string) (*model.OAuth2DeviceCodeSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2DeviceCodeSessionByUserCodeSignature", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2DeviceCodeSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2DeviceCodeSessionByUserCodeSignature indicates an expected call of LoadOAuth2DeviceCodeSessionByUserCodeSignature.
+func (mr *MockStorageMockRecorder) LoadOAuth2DeviceCodeSessionByUserCodeSignature(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2DeviceCodeSessionByUserCodeSignature", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2DeviceCodeSessionByUserCodeSignature), arg0, arg1)
+}
+
+// LoadOAuth2PARContext mocks base method.
+func (m *MockStorage) LoadOAuth2PARContext(arg0 context.Context, arg1 string) (*model.OAuth2PARContext, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2PARContext", arg0, arg1)
+	ret0, _ := ret[0].(*model.OAuth2PARContext)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2PARContext indicates an expected call of LoadOAuth2PARContext.
+func (mr *MockStorageMockRecorder) LoadOAuth2PARContext(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2PARContext", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2PARContext), arg0, arg1)
+}
+
+// LoadOAuth2RefreshTokenFamilyCreatedAt mocks base method.
+func (m *MockStorage) LoadOAuth2RefreshTokenFamilyCreatedAt(arg0 context.Context, arg1 string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadOAuth2RefreshTokenFamilyCreatedAt", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadOAuth2RefreshTokenFamilyCreatedAt indicates an expected call of LoadOAuth2RefreshTokenFamilyCreatedAt.
+func (mr *MockStorageMockRecorder) LoadOAuth2RefreshTokenFamilyCreatedAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadOAuth2RefreshTokenFamilyCreatedAt", reflect.TypeOf((*MockStorage)(nil).LoadOAuth2RefreshTokenFamilyCreatedAt), arg0, arg1)
+}
+
 // LoadOAuth2Session mocks base method.
 func (m *MockStorage) LoadOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) (*model.OAuth2Session, error) {
 	m.ctrl.T.Helper()
@@ -315,6 +596,22 @@ func (mr *MockStorageMockRecorder) LoadPreferredDuoDevice(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).LoadPreferredDuoDevice), arg0, arg1)
 }
 
+// LoadSessionData mocks base method.
+func (m *MockStorage) LoadSessionData(arg0 context.Context, arg1 string) ([]byte, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadSessionData", arg0, arg1)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LoadSessionData indicates an expected call of LoadSessionData.
+func (mr *MockStorageMockRecorder) LoadSessionData(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadSessionData", reflect.TypeOf((*MockStorage)(nil).LoadSessionData), arg0, arg1)
+}
+
 // LoadTOTPConfiguration mocks base method.
 func (m *MockStorage) LoadTOTPConfiguration(arg0 context.Context, arg1 string) (*model.TOTPConfiguration, error) {
 	m.ctrl.T.Helper()
@@ -435,6 +732,91 @@ func (mr *MockStorageMockRecorder) LoadWebauthnDevicesByUsername(arg0, arg1 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadWebauthnDevicesByUsername", reflect.TypeOf((*MockStorage)(nil).LoadWebauthnDevicesByUsername), arg0, arg1)
 }
 
+// RegenerateSessionData mocks base method.
+func (m *MockStorage) RegenerateSessionData(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateSessionData", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegenerateSessionData indicates an expected call of RegenerateSessionData.
+func (mr *MockStorageMockRecorder) RegenerateSessionData(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateSessionData", reflect.TypeOf((*MockStorage)(nil).RegenerateSessionData), arg0, arg1, arg2, arg3)
+}
+
+// RevokeActiveSession mocks base method.
+func (m *MockStorage) RevokeActiveSession(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeActiveSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeActiveSession indicates an expected call of RevokeActiveSession.
+func (mr *MockStorageMockRecorder) RevokeActiveSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeActiveSession", reflect.TypeOf((*MockStorage)(nil).RevokeActiveSession), arg0, arg1)
+}
+
+// RevokeActiveSessionByID mocks base method.
+func (m *MockStorage) RevokeActiveSessionByID(arg0 context.Context, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeActiveSessionByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeActiveSessionByID indicates an expected call of RevokeActiveSessionByID.
+func (mr *MockStorageMockRecorder) RevokeActiveSessionByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeActiveSessionByID", reflect.TypeOf((*MockStorage)(nil).RevokeActiveSessionByID), arg0, arg1, arg2)
+}
+
+// RevokeActiveSessionsByUsername mocks base method.
+func (m *MockStorage) RevokeActiveSessionsByUsername(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeActiveSessionsByUsername", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeActiveSessionsByUsername indicates an expected call of RevokeActiveSessionsByUsername.
+func (mr *MockStorageMockRecorder) RevokeActiveSessionsByUsername(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeActiveSessionsByUsername", reflect.TypeOf((*MockStorage)(nil).RevokeActiveSessionsByUsername), arg0, arg1)
+}
+
+// RevokeOAuth2ConsentPreConfiguration mocks base method.
+func (m *MockStorage) RevokeOAuth2ConsentPreConfiguration(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuth2ConsentPreConfiguration", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuth2ConsentPreConfiguration indicates an expected call of RevokeOAuth2ConsentPreConfiguration.
+func (mr *MockStorageMockRecorder) RevokeOAuth2ConsentPreConfiguration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2ConsentPreConfiguration", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2ConsentPreConfiguration), arg0, arg1)
+}
+
+// RevokeOAuth2PARContext mocks base method.
+func (m *MockStorage) RevokeOAuth2PARContext(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuth2PARContext", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuth2PARContext indicates an expected call of RevokeOAuth2PARContext.
+func (mr *MockStorageMockRecorder) RevokeOAuth2PARContext(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2PARContext", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2PARContext), arg0, arg1)
+}
+
 // RevokeOAuth2Session mocks base method.
 func (m *MockStorage) RevokeOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -463,6 +845,34 @@ func (mr *MockStorageMockRecorder) RevokeOAuth2SessionByRequestID(arg0, arg1, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2SessionByRequestID", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2SessionByRequestID), arg0, arg1, arg2)
 }
 
+// RevokeOAuth2SessionsByConsentPreConfiguration mocks base method.
+func (m *MockStorage) RevokeOAuth2SessionsByConsentPreConfiguration(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuth2SessionsByConsentPreConfiguration", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuth2SessionsByConsentPreConfiguration indicates an expected call of RevokeOAuth2SessionsByConsentPreConfiguration.
+func (mr *MockStorageMockRecorder) RevokeOAuth2SessionsByConsentPreConfiguration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2SessionsByConsentPreConfiguration", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2SessionsByConsentPreConfiguration), arg0, arg1)
+}
+
+// RevokeOAuth2SessionsByConsentSession mocks base method.
+func (m *MockStorage) RevokeOAuth2SessionsByConsentSession(arg0 context.Context, arg1 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOAuth2SessionsByConsentSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOAuth2SessionsByConsentSession indicates an expected call of RevokeOAuth2SessionsByConsentSession.
+func (mr *MockStorageMockRecorder) RevokeOAuth2SessionsByConsentSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOAuth2SessionsByConsentSession", reflect.TypeOf((*MockStorage)(nil).RevokeOAuth2SessionsByConsentSession), arg0, arg1)
+}
+
 // Rollback mocks base method.
 func (m *MockStorage) Rollback(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -477,6 +887,34 @@ func (mr *MockStorageMockRecorder) Rollback(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockStorage)(nil).Rollback), arg0)
 }
 
+// RotateOAuth2RefreshTokenSession mocks base method.
+func (m *MockStorage) RotateOAuth2RefreshTokenSession(arg0 context.Context, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateOAuth2RefreshTokenSession", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateOAuth2RefreshTokenSession indicates an expected call of RotateOAuth2RefreshTokenSession.
+func (mr *MockStorageMockRecorder) RotateOAuth2RefreshTokenSession(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateOAuth2RefreshTokenSession", reflect.TypeOf((*MockStorage)(nil).RotateOAuth2RefreshTokenSession), arg0, arg1, arg2)
+}
+
+// SaveActiveSession mocks base method.
+func (m *MockStorage) SaveActiveSession(arg0 context.Context, arg1 model.ActiveSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveActiveSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveActiveSession indicates an expected call of SaveActiveSession.
+func (mr *MockStorageMockRecorder) SaveActiveSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveActiveSession", reflect.TypeOf((*MockStorage)(nil).SaveActiveSession), arg0, arg1)
+}
+
 // SaveIdentityVerification mocks base method.
 func (m *MockStorage) SaveIdentityVerification(arg0 context.Context, arg1 model.IdentityVerification) error {
 	m.ctrl.T.Helper()
@@ -491,6 +929,20 @@ func (mr *MockStorageMockRecorder) SaveIdentityVerification(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveIdentityVerification", reflect.TypeOf((*MockStorage)(nil).SaveIdentityVerification), arg0, arg1)
 }
 
+// SaveOAuth2AccessTokenRevokedJTI mocks base method.
+func (m *MockStorage) SaveOAuth2AccessTokenRevokedJTI(arg0 context.Context, arg1 model.OAuth2BlacklistedJTI) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2AccessTokenRevokedJTI", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2AccessTokenRevokedJTI indicates an expected call of SaveOAuth2AccessTokenRevokedJTI.
+func (mr *MockStorageMockRecorder) SaveOAuth2AccessTokenRevokedJTI(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2AccessTokenRevokedJTI", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2AccessTokenRevokedJTI), arg0, arg1)
+}
+
 // SaveOAuth2BlacklistedJTI mocks base method.
 func (m *MockStorage) SaveOAuth2BlacklistedJTI(arg0 context.Context, arg1 model.OAuth2BlacklistedJTI) error {
 	m.ctrl.T.Helper()
@@ -505,6 +957,20 @@ func (mr *MockStorageMockRecorder) SaveOAuth2BlacklistedJTI(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2BlacklistedJTI", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2BlacklistedJTI), arg0, arg1)
 }
 
+// SaveOAuth2Client mocks base method.
+func (m *MockStorage) SaveOAuth2Client(arg0 context.Context, arg1 model.OAuth2Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2Client", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2Client indicates an expected call of SaveOAuth2Client.
+func (mr *MockStorageMockRecorder) SaveOAuth2Client(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2Client", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2Client), arg0, arg1)
+}
+
 // SaveOAuth2ConsentPreConfiguration mocks base method.
 func (m *MockStorage) SaveOAuth2ConsentPreConfiguration(arg0 context.Context, arg1 model.OAuth2ConsentPreConfig) (int64, error) {
 	m.ctrl.T.Helper()
@@ -576,6 +1042,76 @@ func (mr *MockStorageMockRecorder) SaveOAuth2ConsentSessionSubject(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2ConsentSessionSubject", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2ConsentSessionSubject), arg0, arg1)
 }
 
+// SaveOAuth2DeviceCodeSession mocks base method.
+func (m *MockStorage) SaveOAuth2DeviceCodeSession(arg0 context.Context, arg1 model.OAuth2DeviceCodeSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2DeviceCodeSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2DeviceCodeSession indicates an expected call of SaveOAuth2DeviceCodeSession.
+func (mr *MockStorageMockRecorder) SaveOAuth2DeviceCodeSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2DeviceCodeSession", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2DeviceCodeSession), arg0, arg1)
+}
+
+// SaveOAuth2DeviceCodeSessionChallengeID mocks base method.
+func (m *MockStorage) SaveOAuth2DeviceCodeSessionChallengeID(arg0 context.Context, arg1 int, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2DeviceCodeSessionChallengeID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2DeviceCodeSessionChallengeID indicates an expected call of SaveOAuth2DeviceCodeSessionChallengeID.
+func (mr *MockStorageMockRecorder) SaveOAuth2DeviceCodeSessionChallengeID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2DeviceCodeSessionChallengeID", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2DeviceCodeSessionChallengeID), arg0, arg1, arg2)
+}
+
+// SaveOAuth2DeviceCodeSessionCheckedAt mocks base method.
+func (m *MockStorage) SaveOAuth2DeviceCodeSessionCheckedAt(arg0 context.Context, arg1 string, arg2 time.Time, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2DeviceCodeSessionCheckedAt", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2DeviceCodeSessionCheckedAt indicates an expected call of SaveOAuth2DeviceCodeSessionCheckedAt.
+func (mr *MockStorageMockRecorder) SaveOAuth2DeviceCodeSessionCheckedAt(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2DeviceCodeSessionCheckedAt", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2DeviceCodeSessionCheckedAt), arg0, arg1, arg2, arg3)
+}
+
+// SaveOAuth2DeviceCodeSessionResponse mocks base method.
+func (m *MockStorage) SaveOAuth2DeviceCodeSessionResponse(arg0 context.Context, arg1 model.OAuth2DeviceCodeSession) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2DeviceCodeSessionResponse", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2DeviceCodeSessionResponse indicates an expected call of SaveOAuth2DeviceCodeSessionResponse.
+func (mr *MockStorageMockRecorder) SaveOAuth2DeviceCodeSessionResponse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2DeviceCodeSessionResponse", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2DeviceCodeSessionResponse), arg0, arg1)
+}
+
+// SaveOAuth2PARContext mocks base method.
+func (m *MockStorage) SaveOAuth2PARContext(arg0 context.Context, arg1 model.OAuth2PARContext) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveOAuth2PARContext", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveOAuth2PARContext indicates an expected call of SaveOAuth2PARContext.
+func (mr *MockStorageMockRecorder) SaveOAuth2PARContext(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveOAuth2PARContext", reflect.TypeOf((*MockStorage)(nil).SaveOAuth2PARContext), arg0, arg1)
+}
+
 // SaveOAuth2Session mocks base method.
 func (m *MockStorage) SaveOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 model.OAuth2Session) error {
 	m.ctrl.T.Helper()
@@ -618,6 +1154,20 @@ func (mr *MockStorageMockRecorder) SavePreferredDuoDevice(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).SavePreferredDuoDevice), arg0, arg1)
 }
 
+// SaveSessionData mocks base method.
+func (m *MockStorage) SaveSessionData(arg0 context.Context, arg1 string, arg2 []byte, arg3 time.Time, arg4 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveSessionData", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveSessionData indicates an expected call of SaveSessionData.
+func (mr *MockStorageMockRecorder) SaveSessionData(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSessionData", reflect.TypeOf((*MockStorage)(nil).SaveSessionData), arg0, arg1, arg2, arg3, arg4)
+}
+
 // SaveTOTPConfiguration mocks base method.
 func (m *MockStorage) SaveTOTPConfiguration(arg0 context.Context, arg1 model.TOTPConfiguration) error {
 	m.ctrl.T.Helper()
@@ -689,6 +1239,20 @@ func (mr *MockStorageMockRecorder) SchemaEncryptionCheckKey(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaEncryptionCheckKey", reflect.TypeOf((*MockStorage)(nil).SchemaEncryptionCheckKey), arg0, arg1)
 }
 
+// SchemaEncryptionReEncrypt mocks base method.
+func (m *MockStorage) SchemaEncryptionReEncrypt(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SchemaEncryptionReEncrypt", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SchemaEncryptionReEncrypt indicates an expected call of SchemaEncryptionReEncrypt.
+func (mr *MockStorageMockRecorder) SchemaEncryptionReEncrypt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SchemaEncryptionReEncrypt", reflect.TypeOf((*MockStorage)(nil).SchemaEncryptionReEncrypt), arg0)
+}
+
 // SchemaLatestVersion mocks base method.
 func (m *MockStorage) SchemaLatestVersion() (int, error) {
 	m.ctrl.T.Helper()
@@ -807,6 +1371,48 @@ func (mr *MockStorageMockRecorder) StartupCheck() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartupCheck", reflect.TypeOf((*MockStorage)(nil).StartupCheck))
 }
 
+// UpdateActiveSessionActivity mocks base method.
+func (m *MockStorage) UpdateActiveSessionActivity(arg0 context.Context, arg1 string, arg2, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateActiveSessionActivity", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateActiveSessionActivity indicates an expected call of UpdateActiveSessionActivity.
+func (mr *MockStorageMockRecorder) UpdateActiveSessionActivity(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActiveSessionActivity", reflect.TypeOf((*MockStorage)(nil).UpdateActiveSessionActivity), arg0, arg1, arg2, arg3)
+}
+
+// UpdateActiveSessionAuthLevel mocks base method.
+func (m *MockStorage) UpdateActiveSessionAuthLevel(arg0 context.Context, arg1, arg2 string, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateActiveSessionAuthLevel", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateActiveSessionAuthLevel indicates an expected call of UpdateActiveSessionAuthLevel.
+func (mr *MockStorageMockRecorder) UpdateActiveSessionAuthLevel(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateActiveSessionAuthLevel", reflect.TypeOf((*MockStorage)(nil).UpdateActiveSessionAuthLevel), arg0, arg1, arg2, arg3)
+}
+
+// UpdateOAuth2Client mocks base method.
+func (m *MockStorage) UpdateOAuth2Client(arg0 context.Context, arg1 model.OAuth2Client) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOAuth2Client", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOAuth2Client indicates an expected call of UpdateOAuth2Client.
+func (mr *MockStorageMockRecorder) UpdateOAuth2Client(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOAuth2Client", reflect.TypeOf((*MockStorage)(nil).UpdateOAuth2Client), arg0, arg1)
+}
+
 // UpdateTOTPConfigurationSignIn mocks base method.
 func (m *MockStorage) UpdateTOTPConfigurationSignIn(arg0 context.Context, arg1 int, arg2 sql.NullTime) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// ActiveSession represents an active session index row in the database. The SessionID is a SHA256 hash of the
+// session cookie value so the session ID itself is never persisted.
+type ActiveSession struct {
+	ID             int       `db:"id"`
+	SessionID      string    `db:"session_id"`
+	CookieDomain   string    `db:"cookie_domain"`
+	Username       string    `db:"username"`
+	CreatedAt      time.Time `db:"created_at"`
+	LastActivityAt time.Time `db:"last_activity_at"`
+	ExpiresAt      time.Time `db:"expires_at"`
+	IP             IP        `db:"ip"`
+	UserAgent      string    `db:"user_agent"`
+	AuthLevel      int       `db:"auth_level"`
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -53,19 +54,15 @@ func NewOAuth2SessionFromRequest(signature string, r fosite.Requester) (session
 		return nil, fmt.Errorf("can't convert type '%T' to an *OAuth2Session", r.GetSession())
 	}
 
-	subject = sessionOpenID.GetSubject()
-
 	if sessionData, err = json.Marshal(sessionOpenID); err != nil {
 		return nil, err
 	}
 
-	return &OAuth2Session{
-		ChallengeID:       sessionOpenID.ChallengeID,
+	session = &OAuth2Session{
 		RequestID:         r.GetID(),
 		ClientID:          r.GetClient().GetID(),
 		Signature:         signature,
 		RequestedAt:       r.GetRequestedAt(),
-		Subject:           subject,
 		RequestedScopes:   StringSlicePipeDelimited(r.GetRequestedScopes()),
 		GrantedScopes:     StringSlicePipeDelimited(r.GetGrantedScopes()),
 		RequestedAudience: StringSlicePipeDelimited(r.GetRequestedAudience()),
@@ -74,6 +71,46 @@ func NewOAuth2SessionFromRequest(signature string, r fosite.Requester) (session
 		Revoked:           false,
 		Form:              r.GetRequestForm().Encode(),
 		Session:           sessionData,
+	}
+
+	// Sessions without a consent challenge such as those issued via the client credentials grant are not issued on
+	// behalf of a user and therefore do not have a challenge id or subject.
+	if sessionOpenID.ChallengeID != uuid.Nil {
+		session.ChallengeID = uuid.NullUUID{UUID: sessionOpenID.ChallengeID, Valid: true}
+
+		if subject = sessionOpenID.GetSubject(); subject != "" {
+			session.Subject = sql.NullString{String: subject, Valid: true}
+		}
+	}
+
+	return session, nil
+}
+
+// NewOAuth2DeviceCodeSessionFromRequest creates a new OAuth2DeviceCodeSession from a device code signature, user code
+// signature, expiration, and fosite.Requester.
+func NewOAuth2DeviceCodeSessionFromRequest(signature, userCodeSignature string, expires time.Time, r fosite.Requester) (session *OAuth2DeviceCodeSession, err error) {
+	var sessionData []byte
+
+	if sessionData, err = json.Marshal(r.GetSession()); err != nil {
+		return nil, err
+	}
+
+	return &OAuth2DeviceCodeSession{
+		RequestID:         r.GetID(),
+		ClientID:          r.GetClient().GetID(),
+		Signature:         signature,
+		UserCodeSignature: userCodeSignature,
+		Status:            OAuth2DeviceCodeStatusPending,
+		RequestedAt:       r.GetRequestedAt(),
+		CheckedAt:         r.GetRequestedAt(),
+		ExpiresAt:         expires,
+		RequestedScopes:   StringSlicePipeDelimited(r.GetRequestedScopes()),
+		GrantedScopes:     StringSlicePipeDelimited(r.GetGrantedScopes()),
+		RequestedAudience: StringSlicePipeDelimited(r.GetRequestedAudience()),
+		GrantedAudience:   StringSlicePipeDelimited(r.GetGrantedAudience()),
+		Active:            true,
+		Form:              r.GetRequestForm().Encode(),
+		Session:           sessionData,
 	}, nil
 }
 
@@ -200,6 +237,24 @@ func (s *OAuth2ConsentSession) GetForm() (form url.Values, err error) {
 	return url.ParseQuery(s.Form)
 }
 
+// OAuth2Client represents an OAuth 2.0 client which is managed via the storage provider rather than the
+// configuration.
+type OAuth2Client struct {
+	ID                         int            `db:"id"`
+	ClientID                   string         `db:"client_id"`
+	CreatedAt                  time.Time      `db:"created_at"`
+	UpdatedAt                  time.Time      `db:"updated_at"`
+	Source                     string         `db:"source"`
+	RegistrationTokenSignature sql.NullString `db:"registration_token_signature"`
+	Secret                     []byte         `db:"secret"`
+	Metadata                   []byte         `db:"metadata"`
+}
+
+// HasRegistrationToken returns true if the client has a registration access token.
+func (c *OAuth2Client) HasRegistrationToken() bool {
+	return c.RegistrationTokenSignature.Valid && c.RegistrationTokenSignature.String != ""
+}
+
 // OAuth2BlacklistedJTI represents a blacklisted JTI used with OAuth2.0.
 type OAuth2BlacklistedJTI struct {
 	ID        int       `db:"id"`
@@ -210,12 +265,12 @@ type OAuth2BlacklistedJTI struct {
 // OAuth2Session represents a OAuth2.0 session.
 type OAuth2Session struct {
 	ID                int                      `db:"id"`
-	ChallengeID       uuid.UUID                `db:"challenge_id"`
+	ChallengeID       uuid.NullUUID            `db:"challenge_id"`
 	RequestID         string                   `db:"request_id"`
 	ClientID          string                   `db:"client_id"`
 	Signature         string                   `db:"signature"`
 	RequestedAt       time.Time                `db:"requested_at"`
-	Subject           string                   `db:"subject"`
+	Subject           sql.NullString           `db:"subject"`
 	RequestedScopes   StringSlicePipeDelimited `db:"requested_scopes"`
 	GrantedScopes     StringSlicePipeDelimited `db:"granted_scopes"`
 	RequestedAudience StringSlicePipeDelimited `db:"requested_audience"`
@@ -224,11 +279,16 @@ type OAuth2Session struct {
 	Revoked           bool                     `db:"revoked"`
 	Form              string                   `db:"form_data"`
 	Session           []byte                   `db:"session_data"`
+
+	// FamilyCreatedAt and RotatedAt are only used by refresh token sessions. The family of a refresh token is all
+	// refresh tokens sharing its request id, i.e. the refresh token originally issued and those issued by rotation.
+	FamilyCreatedAt sql.NullTime `db:"family_created_at"`
+	RotatedAt       sql.NullTime `db:"rotated_at"`
 }
 
 // SetSubject implements an interface required for RFC7523.
 func (s *OAuth2Session) SetSubject(subject string) {
-	s.Subject = subject
+	s.Subject = sql.NullString{String: subject, Valid: len(subject) != 0}
 }
 
 // ToRequest converts an OAuth2Session into a fosite.Request given a fosite.Session and fosite.Storage.
@@ -264,6 +324,195 @@ func (s *OAuth2Session) ToRequest(ctx context.Context, session fosite.Session, s
 	}, nil
 }
 
+// OAuth2DeviceCodeStatus represents the status of an OAuth2DeviceCodeSession.
+type OAuth2DeviceCodeStatus int
+
+const (
+	// OAuth2DeviceCodeStatusPending indicates the user has not yet responded to the device authorization request.
+	OAuth2DeviceCodeStatusPending OAuth2DeviceCodeStatus = iota
+
+	// OAuth2DeviceCodeStatusAuthorized indicates the user has authorized the device authorization request.
+	OAuth2DeviceCodeStatusAuthorized
+
+	// OAuth2DeviceCodeStatusDenied indicates the user has denied the device authorization request.
+	OAuth2DeviceCodeStatusDenied
+)
+
+// OAuth2DeviceCodeSession represents an OAuth 2.0 Device Authorization Grant session.
+type OAuth2DeviceCodeSession struct {
+	ID                int                      `db:"id"`
+	ChallengeID       uuid.NullUUID            `db:"challenge_id"`
+	RequestID         string                   `db:"request_id"`
+	ClientID          string                   `db:"client_id"`
+	Signature         string                   `db:"signature"`
+	UserCodeSignature string                   `db:"user_code_signature"`
+	Status            OAuth2DeviceCodeStatus   `db:"status"`
+	Subject           sql.NullString           `db:"subject"`
+	RequestedAt       time.Time                `db:"requested_at"`
+	CheckedAt         time.Time                `db:"checked_at"`
+	ExpiresAt         time.Time                `db:"expires_at"`
+	PollingInterval   int                      `db:"polling_interval"`
+	RequestedScopes   StringSlicePipeDelimited `db:"requested_scopes"`
+	GrantedScopes     StringSlicePipeDelimited `db:"granted_scopes"`
+	RequestedAudience StringSlicePipeDelimited `db:"requested_audience"`
+	GrantedAudience   StringSlicePipeDelimited `db:"granted_audience"`
+	Active            bool                     `db:"active"`
+	Form              string                   `db:"form_data"`
+	Session           []byte                   `db:"session_data"`
+}
+
+// IsExpired returns true if the device code session has expired.
+func (s *OAuth2DeviceCodeSession) IsExpired() bool {
+	return s.ExpiresAt.Before(time.Now())
+}
+
+// GetPollingInterval returns the minimum interval the client must wait between polling the token endpoint. The
+// fallback is returned unless the interval was increased after the client was asked to slow down.
+func (s *OAuth2DeviceCodeSession) GetPollingInterval(fallback time.Duration) time.Duration {
+	if interval := time.Duration(s.PollingInterval) * time.Second; interval > fallback {
+		return interval
+	}
+
+	return fallback
+}
+
+// IsPending returns true if the user has not yet responded to the device code session.
+func (s *OAuth2DeviceCodeSession) IsPending() bool {
+	return s.Status == OAuth2DeviceCodeStatusPending
+}
+
+// ToRequest converts an OAuth2DeviceCodeSession into a fosite.Request given a fosite.Session and fosite.Storage.
+func (s *OAuth2DeviceCodeSession) ToRequest(ctx context.Context, session fosite.Session, store fosite.Storage) (request *fosite.Request, err error) {
+	if session != nil {
+		if err = json.Unmarshal(s.Session, session); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := store.GetClient(ctx, s.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(s.Form)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fosite.Request{
+		ID:                s.RequestID,
+		RequestedAt:       s.RequestedAt,
+		Client:            client,
+		RequestedScope:    fosite.Arguments(s.RequestedScopes),
+		GrantedScope:      fosite.Arguments(s.GrantedScopes),
+		RequestedAudience: fosite.Arguments(s.RequestedAudience),
+		GrantedAudience:   fosite.Arguments(s.GrantedAudience),
+		Form:              values,
+		Session:           session,
+	}, nil
+}
+
+// NewOAuth2PARContext creates a new OAuth2PARContext from a request URI signature and fosite.AuthorizeRequester. The
+// expiration is taken from the fosite.PushedAuthorizeRequestContext expiration of the session.
+func NewOAuth2PARContext(signature string, r fosite.AuthorizeRequester) (par *OAuth2PARContext, err error) {
+	var (
+		sessionOpenID *OpenIDSession
+		ok            bool
+		sessionData   []byte
+	)
+
+	if sessionOpenID, ok = r.GetSession().(*OpenIDSession); !ok {
+		return nil, fmt.Errorf("can't convert type '%T' to an *OpenIDSession", r.GetSession())
+	}
+
+	if sessionData, err = json.Marshal(sessionOpenID); err != nil {
+		return nil, err
+	}
+
+	return &OAuth2PARContext{
+		Signature:           signature,
+		RequestID:           r.GetID(),
+		ClientID:            r.GetClient().GetID(),
+		RequestedAt:         r.GetRequestedAt(),
+		ExpiresAt:           sessionOpenID.GetExpiresAt(fosite.PushedAuthorizeRequestContext),
+		Scopes:              StringSlicePipeDelimited(r.GetRequestedScopes()),
+		Audience:            StringSlicePipeDelimited(r.GetRequestedAudience()),
+		ResponseMode:        string(r.GetResponseMode()),
+		DefaultResponseMode: string(r.GetDefaultResponseMode()),
+		Form:                r.GetRequestForm().Encode(),
+		Session:             sessionData,
+	}, nil
+}
+
+// OAuth2PARContext holds a pushed authorization request context.
+type OAuth2PARContext struct {
+	ID                  int                      `db:"id"`
+	Signature           string                   `db:"signature"`
+	RequestID           string                   `db:"request_id"`
+	ClientID            string                   `db:"client_id"`
+	RequestedAt         time.Time                `db:"requested_at"`
+	ExpiresAt           time.Time                `db:"expires_at"`
+	Scopes              StringSlicePipeDelimited `db:"scopes"`
+	Audience            StringSlicePipeDelimited `db:"audience"`
+	ResponseMode        string                   `db:"response_mode"`
+	DefaultResponseMode string                   `db:"response_mode_default"`
+	Revoked             bool                     `db:"revoked"`
+	Form                string                   `db:"form_data"`
+	Session             []byte                   `db:"session_data"`
+}
+
+// IsExpired returns true if the pushed authorization request context has expired.
+func (par *OAuth2PARContext) IsExpired() bool {
+	return time.Now().After(par.ExpiresAt)
+}
+
+// ToAuthorizeRequest converts an OAuth2PARContext into a fosite.AuthorizeRequest given a fosite.Session and
+// fosite.Storage.
+func (par *OAuth2PARContext) ToAuthorizeRequest(ctx context.Context, session fosite.Session, store fosite.Storage) (request *fosite.AuthorizeRequest, err error) {
+	if session != nil {
+		if err = json.Unmarshal(par.Session, session); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := store.GetClient(ctx, par.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(par.Form)
+	if err != nil {
+		return nil, err
+	}
+
+	request = &fosite.AuthorizeRequest{
+		ResponseTypes:        fosite.RemoveEmpty(strings.Split(values.Get("response_type"), " ")),
+		State:                values.Get("state"),
+		HandledResponseTypes: fosite.Arguments{},
+		ResponseMode:         fosite.ResponseModeType(par.ResponseMode),
+		DefaultResponseMode:  fosite.ResponseModeType(par.DefaultResponseMode),
+		Request: fosite.Request{
+			ID:                par.RequestID,
+			RequestedAt:       par.RequestedAt,
+			Client:            client,
+			RequestedScope:    fosite.Arguments(par.Scopes),
+			GrantedScope:      fosite.Arguments{},
+			RequestedAudience: fosite.Arguments(par.Audience),
+			GrantedAudience:   fosite.Arguments{},
+			Form:              values,
+			Session:           session,
+		},
+	}
+
+	if redirectURI := values.Get("redirect_uri"); redirectURI != "" {
+		if request.RedirectURI, err = url.Parse(redirectURI); err != nil {
+			return nil, err
+		}
+	}
+
+	return request, nil
+}
+
 // OpenIDSession holds OIDC Session information.
 type OpenIDSession struct {
 	*openid.DefaultSession `json:"id_token"`
@@ -282,3 +531,14 @@ func (s *OpenIDSession) Clone() fosite.Session {
 
 	return deepcopy.Copy(s).(fosite.Session)
 }
+
+// GetExtraClaims returns the extra claims of the session which are included in the token introspection response.
+//
+// Implements the fosite.ExtraClaimsSession.
+func (s *OpenIDSession) GetExtraClaims() map[string]any {
+	if s.Extra == nil {
+		s.Extra = map[string]any{}
+	}
+
+	return s.Extra
+}
@@ -0,0 +1,250 @@
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/ory/x/errorsx"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// JWTAccessTokenClient is a fosite.Client which may be issued JWT access tokens.
+type JWTAccessTokenClient interface {
+	fosite.Client
+
+	GetAccessTokenSigningAlgorithm() (alg string)
+}
+
+// AccessTokenJTIDenylist stores the JTI's of revoked JWT access tokens.
+type AccessTokenJTIDenylist interface {
+	RevokeAccessTokenJTI(ctx context.Context, jti string, exp time.Time) (err error)
+	IsAccessTokenJTIRevoked(ctx context.Context, jti string) (revoked bool, err error)
+}
+
+// NewJWTCoreStrategy creates a new JWTCoreStrategy.
+func NewJWTCoreStrategy(core oauth2.CoreStrategy, signer jwt.Signer, denylist AccessTokenJTIDenylist, config *Config) *JWTCoreStrategy {
+	return &JWTCoreStrategy{
+		CoreStrategy: core,
+		Signer:       signer,
+		Denylist:     denylist,
+		Config:       config,
+	}
+}
+
+// JWTCoreStrategy implements oauth2.CoreStrategy. It issues JWT access tokens to clients which have an access token
+// signing algorithm other than none, and otherwise delegates to the wrapped oauth2.CoreStrategy. The JWT access tokens
+// are still persisted by their signature so introspection and revocation work the same as they do for opaque tokens.
+//
+// https://datatracker.ietf.org/doc/html/rfc9068
+type JWTCoreStrategy struct {
+	oauth2.CoreStrategy
+
+	Signer   jwt.Signer
+	Denylist AccessTokenJTIDenylist
+	Config   fosite.AccessTokenLifespanProvider
+}
+
+// AccessTokenSignature implements oauth2.AccessTokenStrategy.
+func (s *JWTCoreStrategy) AccessTokenSignature(ctx context.Context, token string) string {
+	if IsJWTAccessToken(token) {
+		return jwtAccessTokenSignature(token)
+	}
+
+	return s.CoreStrategy.AccessTokenSignature(ctx, token)
+}
+
+// GenerateAccessToken implements oauth2.AccessTokenStrategy.
+func (s *JWTCoreStrategy) GenerateAccessToken(ctx context.Context, requester fosite.Requester) (token string, signature string, err error) {
+	if requester == nil {
+		return s.CoreStrategy.GenerateAccessToken(ctx, requester)
+	}
+
+	client, ok := requester.GetClient().(JWTAccessTokenClient)
+	if !ok || client.GetAccessTokenSigningAlgorithm() == SigningAlgorithmNone {
+		return s.CoreStrategy.GenerateAccessToken(ctx, requester)
+	}
+
+	claims := s.claims(ctx, requester)
+
+	headers := ExplicitTypeHeaders{
+		JWTHeaderAlgorithm: client.GetAccessTokenSigningAlgorithm(),
+		JWTHeaderType:      JWTTypeAccessToken,
+	}
+
+	if token, _, err = s.Signer.Generate(ctx, claims, headers); err != nil {
+		return "", "", errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if session, ok := requester.GetSession().(*model.OpenIDSession); ok {
+		session.GetExtraClaims()[ClaimJWTID] = claims[ClaimJWTID]
+	}
+
+	return token, jwtAccessTokenSignature(token), nil
+}
+
+// ValidateAccessToken implements oauth2.AccessTokenStrategy.
+func (s *JWTCoreStrategy) ValidateAccessToken(ctx context.Context, requester fosite.Requester, token string) (err error) {
+	if !IsJWTAccessToken(token) {
+		return s.CoreStrategy.ValidateAccessToken(ctx, requester, token)
+	}
+
+	var t *jwt.Token
+
+	if t, err = s.Signer.Decode(ctx, token); err != nil {
+		return toRFCJWTAccessTokenError(err)
+	}
+
+	if typ, _ := t.Header[JWTHeaderType].(string); typ != JWTTypeAccessToken {
+		return errorsx.WithStack(fosite.ErrInvalidTokenFormat.WithHintf("The token has the typ header '%s' but it must be '%s'.", typ, JWTTypeAccessToken))
+	}
+
+	jti, _ := t.Claims[ClaimJWTID].(string)
+
+	if jti == "" {
+		return errorsx.WithStack(fosite.ErrInvalidTokenFormat.WithHint("The token does not have a jti claim."))
+	}
+
+	var revoked bool
+
+	if revoked, err = s.Denylist.IsAccessTokenJTIRevoked(ctx, jti); err != nil {
+		return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if revoked {
+		return errorsx.WithStack(fosite.ErrInactiveToken.WithHint("The token has been revoked."))
+	}
+
+	return nil
+}
+
+// RevokeToken implements fosite.RevocationHandler. It adds the JTI of a valid JWT access token to the denylist, any
+// other token is left to the next fosite.RevocationHandler.
+func (s *JWTCoreStrategy) RevokeToken(ctx context.Context, token string, _ fosite.TokenType, client fosite.Client) (err error) {
+	if !IsJWTAccessToken(token) {
+		return errorsx.WithStack(fosite.ErrUnknownRequest)
+	}
+
+	var t *jwt.Token
+
+	if t, err = s.Signer.Decode(ctx, token); err != nil {
+		return errorsx.WithStack(fosite.ErrUnknownRequest)
+	}
+
+	if clientID, _ := t.Claims[ClaimClientIdentifier].(string); clientID != client.GetID() {
+		return errorsx.WithStack(fosite.ErrUnauthorizedClient)
+	}
+
+	jti, _ := t.Claims[ClaimJWTID].(string)
+	exp := getExpirationTime(t.Claims)
+
+	if jti == "" || exp.IsZero() {
+		return errorsx.WithStack(fosite.ErrUnknownRequest)
+	}
+
+	if err = s.Denylist.RevokeAccessTokenJTI(ctx, jti, exp); err != nil {
+		return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	return nil
+}
+
+func (s *JWTCoreStrategy) claims(ctx context.Context, requester fosite.Requester) (claims jwt.MapClaims) {
+	now := time.Now().UTC()
+
+	session := requester.GetSession()
+
+	exp := session.GetExpiresAt(fosite.AccessToken)
+	if exp.IsZero() {
+		exp = now.Add(s.Config.GetAccessTokenLifespan(ctx))
+	}
+
+	audience := requester.GetGrantedAudience()
+	if len(audience) == 0 {
+		audience = fosite.Arguments{requester.GetClient().GetID()}
+	}
+
+	claims = jwt.MapClaims{
+		ClaimJWTID:            uuid.New().String(),
+		ClaimSubject:          session.GetSubject(),
+		ClaimAudience:         []string(audience),
+		ClaimClientIdentifier: requester.GetClient().GetID(),
+		ClaimScope:            strings.Join(requester.GetGrantedScopes(), " "),
+		ClaimIssuedAt:         now.Unix(),
+		ClaimExpirationTime:   exp.Unix(),
+	}
+
+	if rctx, ok := ctx.(RootURLContext); ok {
+		claims[ClaimIssuer] = rctx.RootURL().String()
+	}
+
+	openid, ok := session.(*model.OpenIDSession)
+	if !ok || openid.DefaultSession == nil {
+		return claims
+	}
+
+	if openid.Claims != nil {
+		if _, ok = claims[ClaimIssuer]; !ok && openid.Claims.Issuer != "" {
+			claims[ClaimIssuer] = openid.Claims.Issuer
+		}
+
+		if !openid.Claims.AuthTime.IsZero() {
+			claims[ClaimAuthenticationTime] = openid.Claims.AuthTime.Unix()
+		}
+
+		if openid.Claims.AuthenticationContextClassReference != "" {
+			claims[ClaimAuthenticationContextClassReference] = openid.Claims.AuthenticationContextClassReference
+		}
+
+		if len(openid.Claims.AuthenticationMethodsReferences) != 0 {
+			claims[ClaimAuthenticationMethodsReference] = openid.Claims.AuthenticationMethodsReferences
+		}
+	}
+
+	if act, ok := openid.Extra[ClaimActor]; ok {
+		claims[ClaimActor] = act
+	}
+
+	return claims
+}
+
+// IsJWTAccessToken returns true if the token has the structure of a compact serialized JWS as opposed to the structure
+// of an opaque access token.
+func IsJWTAccessToken(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// jwtAccessTokenSignature returns the signature used to persist a JWT access token. The JWS signature of a token signed
+// with an RSA key is longer than the persisted signature may be, so the SHA-256 hash of the JWS signature is used.
+func jwtAccessTokenSignature(token string) (signature string) {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token[strings.LastIndex(token, ".")+1:])))
+}
+
+func getExpirationTime(claims jwt.MapClaims) (exp time.Time) {
+	switch value := claims[ClaimExpirationTime].(type) {
+	case int64:
+		return time.Unix(value, 0)
+	case float64:
+		return time.Unix(int64(value), 0)
+	default:
+		return time.Time{}
+	}
+}
+
+func toRFCJWTAccessTokenError(err error) error {
+	var e *jwt.ValidationError
+
+	if errors.As(err, &e) && e.Has(jwt.ValidationErrorExpired) {
+		return errorsx.WithStack(fosite.ErrTokenExpired.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	return errorsx.WithStack(fosite.ErrTokenSignatureMismatch.WithWrap(err).WithDebug(err.Error()))
+}
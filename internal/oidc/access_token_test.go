@@ -0,0 +1,148 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestJWTCoreStrategy(t *testing.T) {
+	config := NewConfig(&schema.OpenIDConnectConfiguration{HMACSecret: "abc123", AccessTokenLifespan: time.Hour})
+
+	manager := NewKeyManager()
+
+	_, err := manager.AddActiveJWK(schema.X509CertificateChain{}, mustParseRSAPrivateKey(exampleIssuerPrivateKey))
+	require.NoError(t, err)
+
+	store := NewStore(&schema.OpenIDConnectConfiguration{}, newTestSQLiteProvider(t))
+
+	strategy := NewJWTCoreStrategy(config.Strategy.Core, manager.Strategy(), store, config)
+
+	ctx := &testRootURLContext{Context: context.Background(), root: &url.URL{Scheme: "https", Host: "auth.example.com"}}
+
+	newRequester := func(client fosite.Client) *fosite.AccessRequest {
+		session := NewSession()
+		session.SetSubject("0b9b9f4f-7d1a-4c93-9b0d-4d2f0b8c1a7e")
+		session.Claims.AuthTime = time.Unix(1680000000, 0)
+		session.Claims.AuthenticationContextClassReference = "urn:example:acr"
+		session.Claims.AuthenticationMethodsReferences = []string{AMRPasswordBasedAuthentication, AMRMultiFactorAuthentication}
+		session.SetExpiresAt(fosite.AccessToken, time.Now().Add(time.Minute).UTC().Round(time.Second))
+
+		requester := fosite.NewAccessRequest(session)
+		requester.Client = client
+		requester.GrantScope(ScopeOpenID)
+		requester.GrantScope("inventory.read")
+		requester.GrantAudience("https://inventory.example.com")
+
+		return requester
+	}
+
+	t.Run("ShouldIssueOpaqueTokenWithoutAlgorithm", func(t *testing.T) {
+		requester := newRequester(&Client{ID: "app"})
+
+		token, signature, err := strategy.GenerateAccessToken(ctx, requester)
+		require.NoError(t, err)
+
+		assert.False(t, IsJWTAccessToken(token))
+		assert.True(t, strings.HasPrefix(token, "authelia_at_"))
+		assert.Equal(t, signature, strategy.AccessTokenSignature(ctx, token))
+		assert.NoError(t, strategy.ValidateAccessToken(ctx, requester, token))
+		assert.ErrorIs(t, strategy.RevokeToken(ctx, token, fosite.AccessToken, requester.Client), fosite.ErrUnknownRequest)
+	})
+
+	t.Run("ShouldIssueJWTToken", func(t *testing.T) {
+		client := &Client{ID: "app", AccessTokenSigningAlgorithm: SigningAlgorithmRSAWithSHA256}
+		requester := newRequester(client)
+
+		token, signature, err := strategy.GenerateAccessToken(ctx, requester)
+		require.NoError(t, err)
+
+		require.True(t, IsJWTAccessToken(token))
+		assert.Equal(t, signature, strategy.AccessTokenSignature(ctx, token))
+		assert.Len(t, signature, 64)
+
+		decoded, err := manager.Decode(ctx, token)
+		require.NoError(t, err)
+
+		assert.Equal(t, JWTTypeAccessToken, decoded.Header[JWTHeaderType])
+		assert.Equal(t, SigningAlgorithmRSAWithSHA256, decoded.Header[JWTHeaderAlgorithm])
+		assert.Equal(t, manager.GetActiveKeyID(), decoded.Header[JWTHeaderKeyIdentifier])
+
+		assert.Equal(t, "https://auth.example.com", decoded.Claims[ClaimIssuer])
+		assert.Equal(t, "0b9b9f4f-7d1a-4c93-9b0d-4d2f0b8c1a7e", decoded.Claims[ClaimSubject])
+		assert.Equal(t, []any{"https://inventory.example.com"}, decoded.Claims[ClaimAudience])
+		assert.Equal(t, "app", decoded.Claims[ClaimClientIdentifier])
+		assert.Equal(t, "openid inventory.read", decoded.Claims[ClaimScope])
+		assert.Equal(t, int64(1680000000), decoded.Claims[ClaimAuthenticationTime])
+		assert.Equal(t, "urn:example:acr", decoded.Claims[ClaimAuthenticationContextClassReference])
+		assert.Equal(t, []any{AMRPasswordBasedAuthentication, AMRMultiFactorAuthentication}, decoded.Claims[ClaimAuthenticationMethodsReference])
+		assert.Equal(t, requester.GetSession().GetExpiresAt(fosite.AccessToken).Unix(), decoded.Claims[ClaimExpirationTime])
+		assert.NotEmpty(t, decoded.Claims[ClaimJWTID])
+
+		session, ok := requester.GetSession().(*model.OpenIDSession)
+		require.True(t, ok)
+		assert.Equal(t, decoded.Claims[ClaimJWTID], session.GetExtraClaims()[ClaimJWTID])
+
+		assert.NoError(t, strategy.ValidateAccessToken(ctx, requester, token))
+
+		assert.ErrorIs(t, strategy.RevokeToken(ctx, token, fosite.AccessToken, &Client{ID: "other"}), fosite.ErrUnauthorizedClient)
+		assert.NoError(t, strategy.ValidateAccessToken(ctx, requester, token))
+
+		require.NoError(t, strategy.RevokeToken(ctx, token, fosite.AccessToken, client))
+
+		err = strategy.ValidateAccessToken(ctx, requester, token)
+		assert.ErrorIs(t, err, fosite.ErrInactiveToken)
+		assert.Equal(t, "The token has been revoked.", fosite.ErrorToRFC6749Error(err).HintField)
+	})
+
+	t.Run("ShouldDefaultAudienceToClientID", func(t *testing.T) {
+		requester := newRequester(&Client{ID: "app", AccessTokenSigningAlgorithm: SigningAlgorithmRSAWithSHA256})
+		requester.GrantedAudience = fosite.Arguments{}
+
+		token, _, err := strategy.GenerateAccessToken(ctx, requester)
+		require.NoError(t, err)
+
+		decoded, err := manager.Decode(ctx, token)
+		require.NoError(t, err)
+
+		assert.Equal(t, []any{"app"}, decoded.Claims[ClaimAudience])
+	})
+
+	t.Run("ShouldRejectExpiredToken", func(t *testing.T) {
+		requester := newRequester(&Client{ID: "app", AccessTokenSigningAlgorithm: SigningAlgorithmRSAWithSHA256})
+		requester.GetSession().SetExpiresAt(fosite.AccessToken, time.Now().Add(time.Minute*-1))
+
+		token, _, err := strategy.GenerateAccessToken(ctx, requester)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, strategy.ValidateAccessToken(ctx, requester, token), fosite.ErrTokenExpired)
+	})
+
+	t.Run("ShouldRejectTokenWithoutAccessTokenType", func(t *testing.T) {
+		token, _, err := manager.Generate(ctx, jwt.MapClaims{ClaimJWTID: "abc"}, &jwt.Headers{})
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, strategy.ValidateAccessToken(ctx, nil, token), fosite.ErrInvalidTokenFormat)
+	})
+
+	t.Run("ShouldRejectTamperedToken", func(t *testing.T) {
+		requester := newRequester(&Client{ID: "app", AccessTokenSigningAlgorithm: SigningAlgorithmRSAWithSHA256})
+
+		token, _, err := strategy.GenerateAccessToken(ctx, requester)
+		require.NoError(t, err)
+
+		parts := strings.Split(token, ".")
+
+		assert.ErrorIs(t, strategy.ValidateAccessToken(ctx, requester, strings.Join([]string{parts[0], parts[1], "abc"}, ".")), fosite.ErrTokenSignatureMismatch)
+	})
+}
@@ -0,0 +1,238 @@
+package oidc
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/templates"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewClaimsStrategy creates a new ClaimsStrategy from the custom claims and custom scopes configuration.
+func NewClaimsStrategy(config *schema.OpenIDConnectConfiguration) (strategy *ClaimsStrategy, err error) {
+	strategy = &ClaimsStrategy{
+		claims: map[string]*ClaimDefinition{},
+		scopes: map[string]ClaimsScope{},
+	}
+
+	if config == nil {
+		return strategy, nil
+	}
+
+	for name, claim := range config.Claims {
+		if strategy.claims[name], err = NewClaimDefinition(claim); err != nil {
+			return nil, fmt.Errorf("error occurred parsing the claim '%s': %w", name, err)
+		}
+	}
+
+	for name, scope := range config.Scopes {
+		strategy.scopes[name] = ClaimsScope{
+			Description: scope.Description,
+			Claims:      scope.Claims,
+		}
+	}
+
+	return strategy, nil
+}
+
+// ClaimsStrategy grants the custom claims which are mapped to the custom scopes.
+type ClaimsStrategy struct {
+	claims map[string]*ClaimDefinition
+	scopes map[string]ClaimsScope
+}
+
+// ClaimsScope is a custom scope and the names of the claims it grants.
+type ClaimsScope struct {
+	Description string
+	Claims      []string
+}
+
+// GrantClaims adds the custom claims mapped to each of the granted scopes to the extra claims. The claim definitions
+// of the client take precedence over the global claim definitions, and claims which are already present in the extra
+// claims are never overwritten.
+func (s *ClaimsStrategy) GrantClaims(client *Client, scopes []string, attributes ClaimAttributes, extra map[string]any) {
+	if s == nil {
+		return
+	}
+
+	for _, scope := range scopes {
+		for _, name := range s.scopes[scope].Claims {
+			if _, ok := extra[name]; ok {
+				continue
+			}
+
+			definition := s.getClaimDefinition(client, name)
+			if definition == nil {
+				continue
+			}
+
+			if value, ok := definition.Resolve(attributes); ok {
+				extra[name] = value
+			}
+		}
+	}
+}
+
+// GetClaimNames returns the sorted names of all claims which are mapped to a custom scope.
+func (s *ClaimsStrategy) GetClaimNames() (names []string) {
+	if s == nil {
+		return nil
+	}
+
+	for _, scope := range s.scopes {
+		for _, name := range scope.Claims {
+			if !utils.IsStringInSlice(name, names) {
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// GetScopeDescriptions returns the descriptions of the custom scopes in the provided list which have one.
+func (s *ClaimsStrategy) GetScopeDescriptions(scopes []string) (descriptions map[string]string) {
+	if s == nil {
+		return nil
+	}
+
+	for _, scope := range scopes {
+		if definition, ok := s.scopes[scope]; ok && definition.Description != "" {
+			if descriptions == nil {
+				descriptions = map[string]string{}
+			}
+
+			descriptions[scope] = definition.Description
+		}
+	}
+
+	return descriptions
+}
+
+func (s *ClaimsStrategy) getClaimDefinition(client *Client, name string) (definition *ClaimDefinition) {
+	if client != nil {
+		if definition = client.Claims[name]; definition != nil {
+			return definition
+		}
+	}
+
+	return s.claims[name]
+}
+
+// NewClaimDefinition creates a new ClaimDefinition from the configuration, compiling the filter and template.
+func NewClaimDefinition(config schema.OpenIDConnectClaimConfiguration) (definition *ClaimDefinition, err error) {
+	definition = &ClaimDefinition{
+		Attribute: config.Attribute,
+		Value:     config.Value,
+	}
+
+	if config.Filter != "" {
+		if definition.Filter, err = regexp.Compile(config.Filter); err != nil {
+			return nil, fmt.Errorf("option 'filter' could not be compiled: %w", err)
+		}
+	}
+
+	if config.Template != "" {
+		funcs := templates.FuncMap()
+
+		// The values of claims are disclosed to clients so the environment must not be accessible.
+		delete(funcs, "env")
+		delete(funcs, "expandenv")
+
+		if definition.Template, err = template.New("claim").Funcs(funcs).Parse(config.Template); err != nil {
+			return nil, fmt.Errorf("option 'template' could not be parsed: %w", err)
+		}
+	}
+
+	return definition, nil
+}
+
+// ClaimDefinition describes how the value of a custom claim is resolved. The value is sourced from a user attribute
+// optionally filtered by a regular expression, a static value, or a template.
+type ClaimDefinition struct {
+	Attribute string
+	Filter    *regexp.Regexp
+	Value     string
+	Template  *template.Template
+}
+
+// Resolve returns the value of the claim for the provided attributes, and false if the claim has no value.
+func (d *ClaimDefinition) Resolve(attributes ClaimAttributes) (value any, ok bool) {
+	switch {
+	case d.Template != nil:
+		buf := &bytes.Buffer{}
+
+		if err := d.Template.Execute(buf, attributes); err != nil {
+			return nil, false
+		}
+
+		return buf.String(), true
+	case d.Attribute != "":
+		return d.resolveAttribute(attributes)
+	case d.Value != "":
+		return d.Value, true
+	default:
+		return nil, false
+	}
+}
+
+func (d *ClaimDefinition) resolveAttribute(attributes ClaimAttributes) (value any, ok bool) {
+	switch d.Attribute {
+	case ClaimAttributeUsername:
+		return attributes.Username, attributes.Username != ""
+	case ClaimAttributeDisplayName:
+		return attributes.DisplayName, attributes.DisplayName != ""
+	case ClaimAttributeEmail:
+		return attributes.Email, attributes.Email != ""
+	case ClaimAttributeEmails:
+		return d.filter(attributes.Emails), true
+	case ClaimAttributeGroups:
+		return d.filter(attributes.Groups), true
+	default:
+		return nil, false
+	}
+}
+
+func (d *ClaimDefinition) filter(values []string) (filtered []string) {
+	filtered = []string{}
+
+	for _, value := range values {
+		if d.Filter == nil || d.Filter.MatchString(value) {
+			filtered = append(filtered, value)
+		}
+	}
+
+	return filtered
+}
+
+// NewClaimAttributes creates a new ClaimAttributes.
+func NewClaimAttributes(username, displayName string, emails, groups []string) ClaimAttributes {
+	attributes := ClaimAttributes{
+		Username:    username,
+		DisplayName: displayName,
+		Emails:      emails,
+		Groups:      groups,
+	}
+
+	if len(emails) != 0 {
+		attributes.Email = emails[0]
+	}
+
+	return attributes
+}
+
+// ClaimAttributes are the user attributes which the value of a custom claim may be sourced from. These are also the
+// values available to claim templates.
+type ClaimAttributes struct {
+	Username    string
+	DisplayName string
+	Email       string
+	Emails      []string
+	Groups      []string
+}
@@ -0,0 +1,137 @@
+package oidc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestClaimsStrategy_GrantClaims(t *testing.T) {
+	strategy, err := NewClaimsStrategy(&schema.OpenIDConnectConfiguration{
+		Claims: map[string]schema.OpenIDConnectClaimConfiguration{
+			"roles":      {Attribute: ClaimAttributeGroups, Filter: "^app-"},
+			"department": {Value: "engineering"},
+			"mailbox":    {Template: "{{ .Username }}@mail.example.com"},
+			"groups":     {Value: "should-not-overwrite"},
+		},
+		Scopes: map[string]schema.OpenIDConnectScopeConfiguration{
+			"roles":   {Description: "Access your roles", Claims: []string{"roles", "department", "groups"}},
+			"mailbox": {Claims: []string{"mailbox"}},
+		},
+	})
+	require.NoError(t, err)
+
+	attributes := NewClaimAttributes("john", "John Smith", []string{"j.smith@authelia.com"}, []string{"app-admin", "dev", "app-user"})
+
+	client := NewClient(schema.OpenIDConnectClientConfiguration{
+		ID: "sales",
+		Claims: map[string]schema.OpenIDConnectClaimConfiguration{
+			"department": {Value: "sales"},
+		},
+	})
+
+	testCases := []struct {
+		name     string
+		client   *Client
+		scopes   []string
+		expected map[string]any
+	}{
+		{
+			"ShouldGrantClaimsForScope",
+			nil,
+			[]string{ScopeOpenID, "roles"},
+			map[string]any{ClaimGroups: []string{"dev"}, "roles": []string{"app-admin", "app-user"}, "department": "engineering"},
+		},
+		{
+			"ShouldGrantClientClaims",
+			client,
+			[]string{"roles"},
+			map[string]any{ClaimGroups: []string{"dev"}, "roles": []string{"app-admin", "app-user"}, "department": "sales"},
+		},
+		{
+			"ShouldGrantTemplateClaims",
+			client,
+			[]string{"mailbox"},
+			map[string]any{ClaimGroups: []string{"dev"}, "mailbox": "john@mail.example.com"},
+		},
+		{
+			"ShouldNotGrantClaimsWithoutScope",
+			client,
+			[]string{ScopeOpenID},
+			map[string]any{ClaimGroups: []string{"dev"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extra := map[string]any{ClaimGroups: []string{"dev"}}
+
+			strategy.GrantClaims(tc.client, tc.scopes, attributes, extra)
+
+			assert.Equal(t, tc.expected, extra)
+		})
+	}
+
+	assert.Equal(t, []string{"department", "groups", "mailbox", "roles"}, strategy.GetClaimNames())
+	assert.Equal(t, map[string]string{"roles": "Access your roles"}, strategy.GetScopeDescriptions([]string{ScopeOpenID, "roles", "mailbox"}))
+	assert.Nil(t, strategy.GetScopeDescriptions([]string{ScopeOpenID}))
+}
+
+func TestClaimsStrategy_ShouldHandleNil(t *testing.T) {
+	var strategy *ClaimsStrategy
+
+	extra := map[string]any{}
+
+	strategy.GrantClaims(nil, []string{ScopeOpenID}, ClaimAttributes{}, extra)
+
+	assert.Len(t, extra, 0)
+	assert.Nil(t, strategy.GetClaimNames())
+	assert.Nil(t, strategy.GetScopeDescriptions([]string{ScopeOpenID}))
+}
+
+func TestClaimDefinition_Resolve(t *testing.T) {
+	attributes := NewClaimAttributes("john", "John Smith", []string{"j.smith@authelia.com", "john@example.com"}, []string{"admin", "dev"})
+
+	testCases := []struct {
+		name     string
+		config   schema.OpenIDConnectClaimConfiguration
+		expected any
+		ok       bool
+	}{
+		{"ShouldResolveUsername", schema.OpenIDConnectClaimConfiguration{Attribute: ClaimAttributeUsername}, "john", true},
+		{"ShouldResolveDisplayName", schema.OpenIDConnectClaimConfiguration{Attribute: ClaimAttributeDisplayName}, "John Smith", true},
+		{"ShouldResolveEmail", schema.OpenIDConnectClaimConfiguration{Attribute: ClaimAttributeEmail}, "j.smith@authelia.com", true},
+		{"ShouldResolveEmails", schema.OpenIDConnectClaimConfiguration{Attribute: ClaimAttributeEmails}, []string{"j.smith@authelia.com", "john@example.com"}, true},
+		{"ShouldResolveFilteredEmails", schema.OpenIDConnectClaimConfiguration{Attribute: ClaimAttributeEmails, Filter: "@example\\.com$"}, []string{"john@example.com"}, true},
+		{"ShouldResolveGroups", schema.OpenIDConnectClaimConfiguration{Attribute: ClaimAttributeGroups}, []string{"admin", "dev"}, true},
+		{"ShouldResolveFilteredGroupsEmpty", schema.OpenIDConnectClaimConfiguration{Attribute: ClaimAttributeGroups, Filter: "^app-"}, []string{}, true},
+		{"ShouldResolveValue", schema.OpenIDConnectClaimConfiguration{Value: "engineering"}, "engineering", true},
+		{"ShouldResolveTemplate", schema.OpenIDConnectClaimConfiguration{Template: "{{ .DisplayName | upper }} <{{ .Email }}>"}, "JOHN SMITH <j.smith@authelia.com>", true},
+		{"ShouldNotResolveTemplateError", schema.OpenIDConnectClaimConfiguration{Template: "{{ .Unknown }}"}, nil, false},
+		{"ShouldNotResolveUnknownAttribute", schema.OpenIDConnectClaimConfiguration{Attribute: "phone"}, nil, false},
+		{"ShouldNotResolveEmpty", schema.OpenIDConnectClaimConfiguration{}, nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			definition, err := NewClaimDefinition(tc.config)
+			require.NoError(t, err)
+
+			value, ok := definition.Resolve(attributes)
+
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+
+	assert.Equal(t, "", NewClaimAttributes("john", "", nil, nil).Email)
+}
+
+func TestNewClaimDefinition_ShouldNotAllowEnvironment(t *testing.T) {
+	_, err := NewClaimDefinition(schema.OpenIDConnectClaimConfiguration{Template: `{{ env "HOME" }}`})
+
+	assert.EqualError(t, err, "option 'template' could not be parsed: template: claim:1: function \"env\" not defined")
+}
@@ -1,7 +1,12 @@
 package oidc
 
 import (
+	"encoding/json"
+	"net/url"
+	"time"
+
 	"github.com/ory/fosite"
+	"gopkg.in/square/go-jose.v2"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
@@ -25,17 +30,68 @@ func NewClient(config schema.OpenIDConnectClientConfiguration) (client *Client)
 		ResponseTypes: config.ResponseTypes,
 		ResponseModes: []fosite.ResponseModeType{fosite.ResponseModeDefault},
 
-		UserinfoSigningAlgorithm: config.UserinfoSigningAlgorithm,
+		PostLogoutRedirectURIs:            config.PostLogoutRedirectURIs,
+		FrontChannelLogoutURI:             config.FrontChannelLogoutURI,
+		FrontChannelLogoutSessionRequired: config.FrontChannelLogoutSessionRequired,
+		BackChannelLogoutURI:              config.BackChannelLogoutURI,
+
+		RequirePushedAuthorizationRequests: config.RequirePushedAuthorizationRequests,
+
+		RequestURIs:                   config.RequestURIs,
+		RequestObjectSigningAlgorithm: config.RequestObjectSigningAlgorithm,
+		RequireSignedRequestObject:    config.RequireSignedRequestObject,
+
+		TokenEndpointAuthMethod:           config.TokenEndpointAuthMethod,
+		TokenEndpointAuthSigningAlgorithm: config.TokenEndpointAuthSigningAlgorithm,
+
+		IDTokenSigningAlgorithm:     config.IDTokenSigningAlgorithm,
+		UserinfoSigningAlgorithm:    config.UserinfoSigningAlgorithm,
+		AccessTokenSigningAlgorithm: config.AccessTokenSigningAlgorithm,
 
 		Policy: authorization.NewLevel(config.Policy),
 
 		Consent: NewClientConsent(config.ConsentMode, config.ConsentPreConfiguredDuration),
+
+		TokenLifespans: ClientTokenLifespans{
+			AccessToken:          config.TokenLifespans.AccessToken,
+			IDToken:              config.TokenLifespans.IDToken,
+			RefreshToken:         config.TokenLifespans.RefreshToken,
+			RefreshTokenAbsolute: config.TokenLifespans.RefreshTokenAbsolute,
+
+			RefreshTokenReuseGracePeriod: config.TokenLifespans.RefreshTokenReuseGracePeriod,
+		},
+
+		TokenExchange: ClientTokenExchange{
+			Audience: config.TokenExchange.Audience,
+			Scopes:   config.TokenExchange.Scopes,
+		},
+	}
+
+	if config.JSONWebKeys != "" {
+		client.JSONWebKeys = &jose.JSONWebKeySet{}
+
+		if err := json.Unmarshal([]byte(config.JSONWebKeys), client.JSONWebKeys); err != nil {
+			client.JSONWebKeys = nil
+		}
 	}
 
 	for _, mode := range config.ResponseModes {
 		client.ResponseModes = append(client.ResponseModes, fosite.ResponseModeType(mode))
 	}
 
+	for name, claim := range config.Claims {
+		definition, err := NewClaimDefinition(claim)
+		if err != nil {
+			continue
+		}
+
+		if client.Claims == nil {
+			client.Claims = map[string]*ClaimDefinition{}
+		}
+
+		client.Claims[name] = definition
+	}
+
 	return client
 }
 
@@ -48,6 +104,12 @@ func (c *Client) IsAuthenticationLevelSufficient(level authentication.Level) boo
 	return authorization.IsAuthLevelSufficient(level, c.Policy)
 }
 
+// IsAuthenticationLevelSufficientForForm returns true if the authentication level is sufficient for both the policy of
+// the client and the acr_values parameter of the authorization request form.
+func (c *Client) IsAuthenticationLevelSufficientForForm(level authentication.Level, form url.Values) bool {
+	return c.IsAuthenticationLevelSufficient(level) && level >= GetRequestedAuthenticationLevel(form)
+}
+
 // GetID returns the ID.
 func (c *Client) GetID() string {
 	return c.ID
@@ -121,6 +183,70 @@ func (c *Client) GetAudience() fosite.Arguments {
 	return c.Audience
 }
 
+// GetAccessTokenSigningAlgorithm returns the algorithm used to sign the JWT access tokens issued to this client, or
+// none if the client is issued opaque access tokens.
+func (c *Client) GetAccessTokenSigningAlgorithm() (alg string) {
+	if c.AccessTokenSigningAlgorithm == "" {
+		return SigningAlgorithmNone
+	}
+
+	return c.AccessTokenSigningAlgorithm
+}
+
+// GetTokenExchangeAudience returns the audience this client may request using the OAuth 2.0 Token Exchange grant.
+func (c *Client) GetTokenExchangeAudience() fosite.Arguments {
+	return c.TokenExchange.Audience
+}
+
+// GetTokenExchangeScopes returns the scopes this client may request using the OAuth 2.0 Token Exchange grant.
+func (c *Client) GetTokenExchangeScopes() fosite.Arguments {
+	return c.TokenExchange.Scopes
+}
+
+// GetEffectiveLifespan returns the lifespan configured for the client for the given fosite.TokenType or the fallback
+// if the client has no specific lifespan for it.
+//
+// Implements the fosite.ClientWithCustomTokenLifespans.
+func (c *Client) GetEffectiveLifespan(_ fosite.GrantType, tt fosite.TokenType, fallback time.Duration) time.Duration {
+	var lifespan time.Duration
+
+	switch tt {
+	case fosite.AccessToken:
+		lifespan = c.TokenLifespans.AccessToken
+	case fosite.IDToken:
+		lifespan = c.TokenLifespans.IDToken
+	case fosite.RefreshToken:
+		lifespan = c.TokenLifespans.RefreshToken
+	}
+
+	if lifespan > 0 {
+		return lifespan
+	}
+
+	return fallback
+}
+
+// GetRefreshTokenAbsoluteLifespan returns the maximum lifespan of a refresh token family configured for the client or
+// the fallback if the client has no specific absolute lifespan. The sliding lifespan of each individual refresh token
+// is returned by GetEffectiveLifespan.
+func (c *Client) GetRefreshTokenAbsoluteLifespan(fallback time.Duration) time.Duration {
+	if c.TokenLifespans.RefreshTokenAbsolute > 0 {
+		return c.TokenLifespans.RefreshTokenAbsolute
+	}
+
+	return fallback
+}
+
+// GetRefreshTokenReuseGracePeriod returns the amount of time a rotated refresh token can still be used configured for
+// the client or the fallback if the client has no specific grace period.
+func (c *Client) GetRefreshTokenReuseGracePeriod(fallback time.Duration) time.Duration {
+	if c.TokenLifespans.RefreshTokenReuseGracePeriod > 0 {
+		return c.TokenLifespans.RefreshTokenReuseGracePeriod
+	}
+
+	return fallback
+}
+
 // GetResponseModes returns the valid response modes for this client.
 //
 // Implements the fosite.ResponseModeClient.
@@ -0,0 +1,300 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/ory/x/errorsx"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewClientAuthenticationStrategy creates a new ClientAuthenticationStrategy.
+func NewClientAuthenticationStrategy(store *Store, fallback fosite.ClientAuthenticationStrategy) *ClientAuthenticationStrategy {
+	return &ClientAuthenticationStrategy{
+		store:    store,
+		fallback: fallback,
+	}
+}
+
+// ClientAuthenticationStrategy is a fosite.ClientAuthenticationStrategy which implements the client_secret_jwt and
+// private_key_jwt client authentication methods and enforces the token_endpoint_auth_method of each client. The
+// client_secret_basic, client_secret_post, and none methods are subsequently handled by the fallback strategy.
+type ClientAuthenticationStrategy struct {
+	store    *Store
+	fallback fosite.ClientAuthenticationStrategy
+}
+
+// AuthenticateClient authenticates the client of a request.
+func (s *ClientAuthenticationStrategy) AuthenticateClient(ctx context.Context, r *http.Request, form url.Values) (client fosite.Client, err error) {
+	switch assertionType := form.Get(FormParameterClientAssertionType); assertionType {
+	case ClientAssertionJWTBearerType:
+		return s.authenticateClientAssertion(ctx, form)
+	case "":
+		return s.authenticateClientSecret(ctx, r, form)
+	default:
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("Unknown client_assertion_type '%s'.", assertionType))
+	}
+}
+
+func (s *ClientAuthenticationStrategy) authenticateClientSecret(ctx context.Context, r *http.Request, form url.Values) (client fosite.Client, err error) {
+	var (
+		clientID, method string
+		full             *Client
+	)
+
+	if id, secret, ok := r.BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(id)
+
+		if secret != "" {
+			method = ClientAuthMethodClientSecretBasic
+		}
+	} else {
+		clientID = form.Get(FormParameterClientID)
+	}
+
+	if method == "" {
+		if form.Get(FormParameterClientSecret) != "" {
+			method = ClientAuthMethodClientSecretPost
+		} else {
+			method = ClientAuthMethodNone
+		}
+	}
+
+	if full, err = s.store.GetFullClient(ctx, clientID); err == nil && !full.IsTokenEndpointAuthMethodAllowed(method) {
+		return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHintf("The OAuth 2.0 Client supports client authentication method '%s', but method '%s' was requested.", full.TokenEndpointAuthMethod, method))
+	}
+
+	return s.fallback(ctx, r, form)
+}
+
+func (s *ClientAuthenticationStrategy) authenticateClientAssertion(ctx context.Context, form url.Values) (client fosite.Client, err error) {
+	assertion := form.Get(FormParameterClientAssertion)
+
+	if len(assertion) == 0 {
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("The client_assertion request parameter must be set when using client_assertion_type of '%s'.", ClientAssertionJWTBearerType))
+	}
+
+	var (
+		full  *Client
+		token *jwt.Token
+	)
+
+	clientID := form.Get(FormParameterClientID)
+
+	token, err = jwt.ParseWithClaims(assertion, jwt.MapClaims{}, func(t *jwt.Token) (key any, err error) {
+		if clientID == "" {
+			if clientID, _ = t.Claims[ClaimSubject].(string); clientID == "" {
+				return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHint("The claim 'sub' from the client_assertion JSON Web Token is undefined."))
+			}
+		}
+
+		if full, err = s.store.GetFullClient(ctx, clientID); err != nil {
+			return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithWrap(err).WithDebug(err.Error()))
+		}
+
+		switch full.TokenEndpointAuthMethod {
+		case ClientAuthMethodPrivateKeyJWT, ClientAuthMethodClientSecretJWT:
+			break
+		default:
+			return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHintf("The OAuth 2.0 Client does not support the '%s' or '%s' client authentication methods, but 'client_assertion' was provided in the request.", ClientAuthMethodPrivateKeyJWT, ClientAuthMethodClientSecretJWT))
+		}
+
+		if alg, _ := t.Header[JWTHeaderAlgorithm].(string); alg != full.TokenEndpointAuthSigningAlgorithm {
+			return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHintf("The 'client_assertion' uses signing algorithm '%s' but the requested OAuth 2.0 Client enforces signing algorithm '%s'.", alg, full.TokenEndpointAuthSigningAlgorithm))
+		}
+
+		return full.getClientAssertionKey(t)
+	})
+
+	if err != nil {
+		var e *jwt.ValidationError
+
+		if errors.As(err, &e) {
+			if e.Inner != nil {
+				return nil, e.Inner
+			}
+
+			return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHint("Unable to verify the integrity of the 'client_assertion' value.").WithWrap(err).WithDebug(err.Error()))
+		}
+
+		return nil, err
+	}
+
+	claims := token.Claims
+
+	var (
+		jti string
+		exp time.Time
+		ok  bool
+	)
+
+	switch {
+	case !claims.VerifyIssuer(clientID, true):
+		return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHint("Claim 'iss' from 'client_assertion' must match the 'client_id' of the OAuth 2.0 Client."))
+	case claims[ClaimSubject] != clientID:
+		return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHint("Claim 'sub' from 'client_assertion' must match the 'client_id' of the OAuth 2.0 Client."))
+	case !isClientAssertionAudienceValid(ctx, claims):
+		return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHint("Claim 'aud' from 'client_assertion' must match the issuer or the endpoint the request was made to."))
+	}
+
+	if jti, ok = claims[ClaimJWTID].(string); !ok || len(jti) == 0 {
+		return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHint("Claim 'jti' from 'client_assertion' must be set but is not."))
+	}
+
+	if exp, ok = getClaimTime(claims, ClaimExpirationTime); !ok {
+		return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHint("Claim 'exp' from 'client_assertion' must be set but is not."))
+	}
+
+	if err = s.store.ConsumeClientAssertionJWT(ctx, jti, exp); err != nil {
+		if errors.Is(err, fosite.ErrJTIKnown) {
+			return nil, errorsx.WithStack(fosite.ErrJTIKnown.WithHint("Claim 'jti' from 'client_assertion' MUST only be used once.").WithWrap(err).WithDebug(err.Error()))
+		}
+
+		return nil, err
+	}
+
+	return full, nil
+}
+
+// IsTokenEndpointAuthMethodAllowed returns true if the client may use the provided client authentication method. Clients
+// without an explicitly configured method may use any of the methods which are not based on a client assertion.
+func (c *Client) IsTokenEndpointAuthMethodAllowed(method string) bool {
+	if c.TokenEndpointAuthMethod == "" {
+		return method != ClientAuthMethodPrivateKeyJWT && method != ClientAuthMethodClientSecretJWT
+	}
+
+	return c.TokenEndpointAuthMethod == method
+}
+
+func (c *Client) getClientAssertionKey(t *jwt.Token) (key any, err error) {
+	switch t.Method {
+	case jose.HS256, jose.HS384, jose.HS512:
+		var secret []byte
+
+		if digest, ok := c.Secret.(PlainTextDigest); ok {
+			secret, _ = digest.PlainText()
+		}
+
+		if len(secret) == 0 {
+			return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHint("The OAuth 2.0 Client does not have a plain text secret which is required for the 'client_secret_jwt' client authentication method."))
+		}
+
+		return &jose.JSONWebKey{Key: secret, Algorithm: string(t.Method)}, nil
+	default:
+		if c.JSONWebKeys == nil {
+			return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHint("The OAuth 2.0 Client has no JSON Web Keys set registered, but they are needed to complete the request."))
+		}
+
+		kid, _ := t.Header[JWTHeaderKeyIdentifier].(string)
+
+		if jwk := c.getSigningJSONWebKey(kid, string(t.Method)); jwk != nil {
+			return jwk, nil
+		}
+
+		return nil, errorsx.WithStack(fosite.ErrInvalidClient.WithHintf("Unable to find a public key with use='sig' for kid '%s' compatible with the algorithm '%s' in the JSON Web Key Set of the OAuth 2.0 Client.", kid, t.Method))
+	}
+}
+
+// getSigningJSONWebKey returns the first key from the JSON Web Key Set of the client which may be used to verify
+// signatures with the provided algorithm, only considering keys with the provided kid if it's not empty.
+func (c *Client) getSigningJSONWebKey(kid, alg string) *jose.JSONWebKey {
+	if c.JSONWebKeys == nil {
+		return nil
+	}
+
+	keys := c.JSONWebKeys.Keys
+
+	if kid != "" {
+		keys = c.JSONWebKeys.Key(kid)
+	}
+
+	for _, jwk := range keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		if IsSigningAlgorithmCompatibleKey(alg, jwk.Key) {
+			return &jwk
+		}
+	}
+
+	return nil
+}
+
+// IsSigningAlgorithmCompatibleKey returns true if the provided public key can be used to verify signatures using the
+// provided algorithm.
+func IsSigningAlgorithmCompatibleKey(alg string, key any) bool {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return utils.IsStringInSlice(alg, []string{SigningAlgorithmRSAWithSHA256, SigningAlgorithmRSAWithSHA384, SigningAlgorithmRSAWithSHA512,
+			SigningAlgorithmRSAPSSWithSHA256, SigningAlgorithmRSAPSSWithSHA384, SigningAlgorithmRSAPSSWithSHA512})
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().BitSize {
+		case 256:
+			return alg == SigningAlgorithmECDSAWithP256AndSHA256
+		case 384:
+			return alg == SigningAlgorithmECDSAWithP384AndSHA384
+		case 521:
+			return alg == SigningAlgorithmECDSAWithP521AndSHA512
+		}
+	case ed25519.PublicKey:
+		return alg == SigningAlgorithmEdDSA
+	}
+
+	return false
+}
+
+// isClientAssertionAudienceValid returns true if the aud claim of a client assertion contains the issuer or one of the
+// endpoints which accept client authentication.
+func isClientAssertionAudienceValid(ctx context.Context, claims jwt.MapClaims) bool {
+	rctx, ok := ctx.(RootURLContext)
+	if !ok {
+		return false
+	}
+
+	issuer := rctx.RootURL().String()
+
+	valid := []string{
+		issuer,
+		fmt.Sprintf("%s%s", issuer, EndpointPathToken),
+		fmt.Sprintf("%s%s", issuer, EndpointPathIntrospection),
+		fmt.Sprintf("%s%s", issuer, EndpointPathRevocation),
+		fmt.Sprintf("%s%s", issuer, EndpointPathDeviceAuthorization),
+		fmt.Sprintf("%s%s", issuer, EndpointPathPushedAuthorizationRequest),
+	}
+
+	switch aud := claims[ClaimAudience].(type) {
+	case string:
+		return utils.IsStringInSlice(aud, valid)
+	case []any:
+		for _, value := range aud {
+			if v, ok := value.(string); ok && utils.IsStringInSlice(v, valid) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func getClaimTime(claims jwt.MapClaims, claim string) (value time.Time, ok bool) {
+	switch v := claims[claim].(type) {
+	case float64:
+		return time.Unix(int64(v), 0), true
+	case int64:
+		return time.Unix(v, 0), true
+	default:
+		return time.Time{}, false
+	}
+}
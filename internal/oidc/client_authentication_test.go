@@ -0,0 +1,321 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestClientAuthenticationStrategy_AuthenticateClient(t *testing.T) {
+	key := mustParseRSAPrivateKey(exampleIssuerPrivateKey)
+
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: key.Public(), KeyID: "abc", Use: "sig"}}})
+	require.NoError(t, err)
+
+	provider := &testJTIStorageProvider{jtis: map[string]time.Time{}}
+
+	store := NewStore(&schema.OpenIDConnectConfiguration{
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:                                "private-key-jwt",
+				Policy:                            "two_factor",
+				TokenEndpointAuthMethod:           ClientAuthMethodPrivateKeyJWT,
+				TokenEndpointAuthSigningAlgorithm: SigningAlgorithmRSAWithSHA256,
+				JSONWebKeys:                       string(jwks),
+			},
+			{
+				ID:                                "client-secret-jwt",
+				Policy:                            "two_factor",
+				Secret:                            MustDecodeSecret("$plaintext$a-client-secret-which-is-long-enough"),
+				TokenEndpointAuthMethod:           ClientAuthMethodClientSecretJWT,
+				TokenEndpointAuthSigningAlgorithm: SigningAlgorithmHMACWithSHA256,
+			},
+			{
+				ID:                      "client-secret-post",
+				Policy:                  "two_factor",
+				Secret:                  MustDecodeSecret("$plaintext$secret"),
+				TokenEndpointAuthMethod: ClientAuthMethodClientSecretPost,
+			},
+			{
+				ID:     "default",
+				Policy: "two_factor",
+				Secret: MustDecodeSecret("$plaintext$secret"),
+			},
+		},
+	}, provider)
+
+	errFallback := errors.New("fallback")
+
+	strategy := NewClientAuthenticationStrategy(store, func(_ context.Context, _ *http.Request, _ url.Values) (fosite.Client, error) {
+		return nil, errFallback
+	})
+
+	ctx := &testRootURLContext{Context: context.Background(), root: &url.URL{Scheme: "https", Host: "auth.example.com"}}
+
+	sign := func(alg jose.SignatureAlgorithm, k any, kid string, claims josejwt.Claims) string {
+		opts := &jose.SignerOptions{}
+
+		if kid != "" {
+			opts = opts.WithHeader(jose.HeaderKey(JWTHeaderKeyIdentifier), kid)
+		}
+
+		signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: k}, opts)
+		require.NoError(t, err)
+
+		token, err := josejwt.Signed(signer).Claims(claims).CompactSerialize()
+		require.NoError(t, err)
+
+		return token
+	}
+
+	claims := func(id, jti string) josejwt.Claims {
+		return josejwt.Claims{
+			Issuer:   id,
+			Subject:  id,
+			Audience: josejwt.Audience{"https://auth.example.com/api/oidc/token"},
+			ID:       jti,
+			Expiry:   josejwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}
+	}
+
+	assertion := func(token string) url.Values {
+		return url.Values{
+			FormParameterClientAssertionType: []string{ClientAssertionJWTBearerType},
+			FormParameterClientAssertion:     []string{token},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		form   url.Values
+		basic  []string
+		client string
+		err    string
+		hint   string
+	}{
+		{
+			"ShouldAuthenticatePrivateKeyJWT",
+			assertion(sign(jose.RS256, key, "abc", claims("private-key-jwt", "jti1"))),
+			nil,
+			"private-key-jwt",
+			"",
+			"",
+		},
+		{
+			"ShouldRejectReusedJTI",
+			assertion(sign(jose.RS256, key, "abc", claims("private-key-jwt", "jti1"))),
+			nil,
+			"",
+			"jti_known",
+			"Claim 'jti' from 'client_assertion' MUST only be used once.",
+		},
+		{
+			"ShouldAuthenticateClientSecretJWT",
+			assertion(sign(jose.HS256, []byte("a-client-secret-which-is-long-enough"), "", claims("client-secret-jwt", "jti2"))),
+			nil,
+			"client-secret-jwt",
+			"",
+			"",
+		},
+		{
+			"ShouldRejectWrongClientSecret",
+			assertion(sign(jose.HS256, []byte("a-wrong-client-secret-which-is-long-enough"), "", claims("client-secret-jwt", "jti3"))),
+			nil,
+			"",
+			"invalid_client",
+			"Unable to verify the integrity of the 'client_assertion' value.",
+		},
+		{
+			"ShouldRejectWrongSigningAlgorithm",
+			assertion(sign(jose.HS512, []byte("a-client-secret-which-is-long-enough"), "", claims("client-secret-jwt", "jti4"))),
+			nil,
+			"",
+			"invalid_client",
+			"The 'client_assertion' uses signing algorithm 'HS512' but the requested OAuth 2.0 Client enforces signing algorithm 'HS256'.",
+		},
+		{
+			"ShouldRejectAssertionForSecretClient",
+			assertion(sign(jose.HS256, []byte("secret-secret-secret-secret-secret"), "", claims("default", "jti5"))),
+			nil,
+			"",
+			"invalid_client",
+			"The OAuth 2.0 Client does not support the 'private_key_jwt' or 'client_secret_jwt' client authentication methods, but 'client_assertion' was provided in the request.",
+		},
+		{
+			"ShouldRejectInvalidAudience",
+			assertion(sign(jose.RS256, key, "abc", josejwt.Claims{Issuer: "private-key-jwt", Subject: "private-key-jwt", Audience: josejwt.Audience{"https://example.com"}, ID: "jti6", Expiry: josejwt.NewNumericDate(time.Now().Add(time.Minute))})),
+			nil,
+			"",
+			"invalid_client",
+			"Claim 'aud' from 'client_assertion' must match the issuer or the endpoint the request was made to.",
+		},
+		{
+			"ShouldRejectMissingJTI",
+			assertion(sign(jose.RS256, key, "abc", claims("private-key-jwt", ""))),
+			nil,
+			"",
+			"invalid_client",
+			"Claim 'jti' from 'client_assertion' must be set but is not.",
+		},
+		{
+			"ShouldRejectUnknownAssertionType",
+			url.Values{FormParameterClientAssertionType: []string{"abc"}},
+			nil,
+			"",
+			"invalid_request",
+			"Unknown client_assertion_type 'abc'.",
+		},
+		{
+			"ShouldRejectBasicForPostClient",
+			url.Values{},
+			[]string{"client-secret-post", "secret"},
+			"",
+			"invalid_client",
+			"The OAuth 2.0 Client supports client authentication method 'client_secret_post', but method 'client_secret_basic' was requested.",
+		},
+		{
+			"ShouldRejectBasicForPrivateKeyJWTClient",
+			url.Values{},
+			[]string{"private-key-jwt", "secret"},
+			"",
+			"invalid_client",
+			"The OAuth 2.0 Client supports client authentication method 'private_key_jwt', but method 'client_secret_basic' was requested.",
+		},
+		{
+			"ShouldFallbackForPostClient",
+			url.Values{FormParameterClientID: []string{"client-secret-post"}, FormParameterClientSecret: []string{"secret"}},
+			nil,
+			"",
+			"fallback",
+			"",
+		},
+		{
+			"ShouldFallbackForDefaultClient",
+			url.Values{},
+			[]string{"default", "secret"},
+			"",
+			"fallback",
+			"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/token", strings.NewReader(tc.form.Encode()))
+			require.NoError(t, err)
+
+			if tc.basic != nil {
+				r.SetBasicAuth(tc.basic[0], tc.basic[1])
+			}
+
+			client, err := strategy.AuthenticateClient(ctx, r, tc.form)
+
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.client, client.GetID())
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, client)
+
+				if tc.hint != "" {
+					assert.Equal(t, tc.hint, fosite.ErrorToRFC6749Error(err).HintField)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_IsTokenEndpointAuthMethodAllowed(t *testing.T) {
+	client := &Client{}
+
+	assert.True(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretBasic))
+	assert.True(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretPost))
+	assert.True(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodNone))
+	assert.False(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretJWT))
+	assert.False(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodPrivateKeyJWT))
+
+	client.TokenEndpointAuthMethod = ClientAuthMethodPrivateKeyJWT
+
+	assert.False(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodClientSecretBasic))
+	assert.True(t, client.IsTokenEndpointAuthMethodAllowed(ClientAuthMethodPrivateKeyJWT))
+}
+
+func TestIsSigningAlgorithmCompatibleKey(t *testing.T) {
+	rsaKey := mustParseRSAPrivateKey(exampleIssuerPrivateKey)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	assert.True(t, IsSigningAlgorithmCompatibleKey(SigningAlgorithmRSAWithSHA256, rsaKey.Public()))
+	assert.True(t, IsSigningAlgorithmCompatibleKey(SigningAlgorithmRSAPSSWithSHA512, rsaKey.Public()))
+	assert.False(t, IsSigningAlgorithmCompatibleKey(SigningAlgorithmECDSAWithP256AndSHA256, rsaKey.Public()))
+	assert.True(t, IsSigningAlgorithmCompatibleKey(SigningAlgorithmECDSAWithP384AndSHA384, ecKey.Public()))
+	assert.False(t, IsSigningAlgorithmCompatibleKey(SigningAlgorithmECDSAWithP256AndSHA256, ecKey.Public()))
+	assert.True(t, IsSigningAlgorithmCompatibleKey(SigningAlgorithmEdDSA, edKey))
+	assert.False(t, IsSigningAlgorithmCompatibleKey(SigningAlgorithmHMACWithSHA256, edKey))
+	assert.False(t, IsSigningAlgorithmCompatibleKey(SigningAlgorithmRSAWithSHA256, rsaKey))
+}
+
+type testRootURLContext struct {
+	context.Context
+
+	root *url.URL
+}
+
+func (ctx *testRootURLContext) RootURL() *url.URL {
+	return ctx.root
+}
+
+type testJTIStorageProvider struct {
+	storage.Provider
+
+	jtis map[string]time.Time
+}
+
+func (p *testJTIStorageProvider) SaveOAuth2BlacklistedJTI(_ context.Context, blacklistedJTI model.OAuth2BlacklistedJTI) (err error) {
+	p.jtis[blacklistedJTI.Signature] = blacklistedJTI.ExpiresAt
+
+	return nil
+}
+
+func (p *testJTIStorageProvider) InsertOAuth2BlacklistedJTI(_ context.Context, blacklistedJTI model.OAuth2BlacklistedJTI, now time.Time) (err error) {
+	if exp, ok := p.jtis[blacklistedJTI.Signature]; ok && exp.After(now) {
+		return storage.ErrOAuth2BlacklistedJTIExists
+	}
+
+	p.jtis[blacklistedJTI.Signature] = blacklistedJTI.ExpiresAt
+
+	return nil
+}
+
+func (p *testJTIStorageProvider) LoadOAuth2BlacklistedJTI(_ context.Context, signature string) (blacklistedJTI *model.OAuth2BlacklistedJTI, err error) {
+	exp, ok := p.jtis[signature]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return &model.OAuth2BlacklistedJTI{Signature: signature, ExpiresAt: exp}, nil
+}
@@ -0,0 +1,65 @@
+package oidc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// userScopes are the scopes which are only valid for requests made on behalf of a user.
+var userScopes = []string{ScopeOpenID, ScopeOfflineAccess, ScopeOffline, ScopeProfile, ScopeEmail, ScopeGroups}
+
+// PopulateClientCredentialsFlowRequester populates a client credentials fosite.AccessRequester with the granted scopes,
+// granted audience, and the session subject. The client credentials flow acts on behalf of the client itself and not a
+// user so the user scopes are rejected and the subject of the session is the client id.
+func PopulateClientCredentialsFlowRequester(ctx context.Context, config fosite.Configurator, client fosite.Client, requester fosite.AccessRequester) (err error) {
+	scopes := requester.GetRequestedScopes()
+
+	for _, scope := range scopes {
+		if utils.IsStringInSlice(scope, userScopes) {
+			return fosite.ErrInvalidScope.WithHintf("The scope '%s' is not valid for the client credentials grant as it is only valid for requests made on behalf of a user.", scope)
+		}
+	}
+
+	strategy := config.GetScopeStrategy(ctx)
+
+	for _, scope := range scopes {
+		if strategy(client.GetScopes(), scope) {
+			requester.GrantScope(scope)
+		}
+	}
+
+	audience := requester.GetRequestedAudience()
+
+	if len(audience) == 0 {
+		audience = client.GetAudience()
+	} else if err = config.GetAudienceStrategy(ctx)(client.GetAudience(), audience); err != nil {
+		return err
+	}
+
+	for _, aud := range audience {
+		requester.GrantAudience(aud)
+	}
+
+	if session, ok := requester.GetSession().(*model.OpenIDSession); ok {
+		session.ClientID = client.GetID()
+		session.SetSubject(client.GetID())
+	}
+
+	return nil
+}
+
+// IsClientCredentialsSession returns true if the fosite.Session was issued via the client credentials grant. These
+// sessions are not linked to a consent session and are issued to the client itself.
+func IsClientCredentialsSession(session fosite.Session) bool {
+	s, ok := session.(*model.OpenIDSession)
+	if !ok || s.DefaultSession == nil {
+		return false
+	}
+
+	return s.ChallengeID == uuid.Nil && s.ClientID != "" && s.GetSubject() == s.ClientID
+}
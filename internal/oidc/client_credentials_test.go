@@ -0,0 +1,103 @@
+package oidc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestPopulateClientCredentialsFlowRequester(t *testing.T) {
+	config := NewConfig(&schema.OpenIDConnectConfiguration{})
+
+	client := &Client{
+		ID:         "machine",
+		Scopes:     []string{"api:read", "api:write"},
+		Audience:   []string{"https://api.example.com", "https://other.example.com"},
+		GrantTypes: []string{GrantTypeClientCredentials},
+	}
+
+	testCases := []struct {
+		name             string
+		scopes, audience []string
+		expectedScopes   []string
+		expectedAudience []string
+		err              string
+	}{
+		{
+			"ShouldGrantAllAudienceWhenNoneRequested",
+			[]string{"api:read"}, nil,
+			[]string{"api:read"}, []string{"https://api.example.com", "https://other.example.com"},
+			"",
+		},
+		{
+			"ShouldGrantRequestedAudience",
+			[]string{"api:read", "api:write"}, []string{"https://api.example.com"},
+			[]string{"api:read", "api:write"}, []string{"https://api.example.com"},
+			"",
+		},
+		{
+			"ShouldRejectUserScope",
+			[]string{"api:read", ScopeOpenID}, nil,
+			nil, nil,
+			"The requested scope is invalid, unknown, or malformed. The scope 'openid' is not valid for the client credentials grant as it is only valid for requests made on behalf of a user.",
+		},
+		{
+			"ShouldRejectAudience",
+			nil, []string{"https://evil.example.com"},
+			nil, nil,
+			"The request is missing a required parameter, includes an invalid parameter value, includes a parameter more than once, or is otherwise malformed. Requested audience 'https://evil.example.com' has not been whitelisted by the OAuth 2.0 Client.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			session := NewSession()
+
+			requester := fosite.NewAccessRequest(session)
+			requester.Client = client
+			requester.RequestedScope = tc.scopes
+			requester.RequestedAudience = tc.audience
+
+			err := PopulateClientCredentialsFlowRequester(context.Background(), config, client, requester)
+
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.err, fosite.ErrorToRFC6749Error(err).WithExposeDebug(true).GetDescription())
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, fosite.Arguments(tc.expectedScopes), requester.GetGrantedScopes())
+			assert.Equal(t, fosite.Arguments(tc.expectedAudience), requester.GetGrantedAudience())
+			assert.Equal(t, "machine", session.GetSubject())
+			assert.Equal(t, "machine", session.ClientID)
+			assert.True(t, IsClientCredentialsSession(session))
+		})
+	}
+}
+
+func TestIsClientCredentialsSession(t *testing.T) {
+	session := NewSession()
+
+	assert.False(t, IsClientCredentialsSession(session))
+	assert.False(t, IsClientCredentialsSession(&fosite.DefaultSession{}))
+	assert.False(t, IsClientCredentialsSession(&model.OpenIDSession{}))
+
+	session.ClientID = "machine"
+	session.SetSubject("machine")
+
+	assert.True(t, IsClientCredentialsSession(session))
+
+	session.ChallengeID = uuid.New()
+
+	assert.False(t, IsClientCredentialsSession(session))
+}
@@ -0,0 +1,262 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-crypt/crypt/algorithm"
+	"github.com/go-crypt/crypt/algorithm/pbkdf2"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewClientRegistrationMetadata creates a new ClientRegistrationMetadata from a schema.OpenIDConnectClientConfiguration.
+func NewClientRegistrationMetadata(config schema.OpenIDConnectClientConfiguration) (metadata ClientRegistrationMetadata) {
+	metadata = ClientRegistrationMetadata{
+		ClientName:                         config.Description,
+		RedirectURIs:                       config.RedirectURIs,
+		TokenEndpointAuthMethod:            config.TokenEndpointAuthMethod,
+		TokenEndpointAuthSigningAlgorithm:  config.TokenEndpointAuthSigningAlgorithm,
+		GrantTypes:                         config.GrantTypes,
+		ResponseTypes:                      config.ResponseTypes,
+		ResponseModes:                      config.ResponseModes,
+		Scope:                              strings.Join(config.Scopes, " "),
+		Audience:                           config.Audience,
+		IDTokenSigningAlgorithm:            config.IDTokenSigningAlgorithm,
+		UserinfoSigningAlgorithm:           config.UserinfoSigningAlgorithm,
+		AccessTokenSigningAlgorithm:        config.AccessTokenSigningAlgorithm,
+		PostLogoutRedirectURIs:             config.PostLogoutRedirectURIs,
+		FrontChannelLogoutURI:              config.FrontChannelLogoutURI,
+		FrontChannelLogoutSessionRequired:  config.FrontChannelLogoutSessionRequired,
+		BackChannelLogoutURI:               config.BackChannelLogoutURI,
+		RequirePushedAuthorizationRequests: config.RequirePushedAuthorizationRequests,
+		RequestURIs:                        config.RequestURIs,
+		RequestObjectSigningAlgorithm:      config.RequestObjectSigningAlgorithm,
+		RequireSignedRequestObject:         config.RequireSignedRequestObject,
+		SectorIdentifier:                   config.SectorIdentifier.String(),
+		Policy:                             config.Policy,
+		ConsentMode:                        config.ConsentMode,
+		ConsentPreConfiguredDuration:       config.ConsentPreConfiguredDuration,
+	}
+
+	if config.JSONWebKeys != "" {
+		metadata.JSONWebKeys = json.RawMessage(config.JSONWebKeys)
+	}
+
+	return metadata
+}
+
+// ToConfiguration converts the ClientRegistrationMetadata into a schema.OpenIDConnectClientConfiguration given the
+// client id and the secret.
+func (m ClientRegistrationMetadata) ToConfiguration(id string, secret *schema.PasswordDigest) (config schema.OpenIDConnectClientConfiguration, err error) {
+	config = schema.OpenIDConnectClientConfiguration{
+		ID:                                 id,
+		Description:                        m.ClientName,
+		Secret:                             secret,
+		Public:                             m.TokenEndpointAuthMethod == ClientAuthMethodNone,
+		TokenEndpointAuthMethod:            m.TokenEndpointAuthMethod,
+		TokenEndpointAuthSigningAlgorithm:  m.TokenEndpointAuthSigningAlgorithm,
+		RedirectURIs:                       m.RedirectURIs,
+		PostLogoutRedirectURIs:             m.PostLogoutRedirectURIs,
+		FrontChannelLogoutURI:              m.FrontChannelLogoutURI,
+		FrontChannelLogoutSessionRequired:  m.FrontChannelLogoutSessionRequired,
+		BackChannelLogoutURI:               m.BackChannelLogoutURI,
+		RequirePushedAuthorizationRequests: m.RequirePushedAuthorizationRequests,
+		RequestURIs:                        m.RequestURIs,
+		RequestObjectSigningAlgorithm:      m.RequestObjectSigningAlgorithm,
+		RequireSignedRequestObject:         m.RequireSignedRequestObject,
+		Audience:                           m.Audience,
+		Scopes:                             strings.Fields(m.Scope),
+		GrantTypes:                         m.GrantTypes,
+		ResponseTypes:                      m.ResponseTypes,
+		ResponseModes:                      m.ResponseModes,
+		IDTokenSigningAlgorithm:            m.IDTokenSigningAlgorithm,
+		UserinfoSigningAlgorithm:           m.UserinfoSigningAlgorithm,
+		AccessTokenSigningAlgorithm:        m.AccessTokenSigningAlgorithm,
+		Policy:                             m.Policy,
+		ConsentMode:                        m.ConsentMode,
+		ConsentPreConfiguredDuration:       m.ConsentPreConfiguredDuration,
+	}
+
+	if len(m.JSONWebKeys) != 0 {
+		config.JSONWebKeys = string(m.JSONWebKeys)
+	}
+
+	if m.SectorIdentifier != "" {
+		var sectorIdentifier *url.URL
+
+		if sectorIdentifier, err = url.Parse(m.SectorIdentifier); err != nil {
+			return config, fmt.Errorf("error parsing the sector identifier: %w", err)
+		}
+
+		config.SectorIdentifier = *sectorIdentifier
+	}
+
+	return config, nil
+}
+
+// NewClientFromStorage creates a new Client from a model.OAuth2Client.
+func NewClientFromStorage(client *model.OAuth2Client) (c *Client, err error) {
+	var (
+		metadata ClientRegistrationMetadata
+		secret   *schema.PasswordDigest
+		config   schema.OpenIDConnectClientConfiguration
+	)
+
+	if err = json.Unmarshal(client.Metadata, &metadata); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the metadata of client with id '%s': %w", client.ClientID, err)
+	}
+
+	if len(client.Secret) != 0 {
+		if secret, err = schema.DecodePasswordDigest(string(client.Secret)); err != nil {
+			return nil, fmt.Errorf("error decoding the secret of client with id '%s': %w", client.ClientID, err)
+		}
+	}
+
+	if config, err = metadata.ToConfiguration(client.ClientID, secret); err != nil {
+		return nil, fmt.Errorf("error converting the metadata of client with id '%s': %w", client.ClientID, err)
+	}
+
+	return NewClient(config), nil
+}
+
+// GenerateClientSecret generates a new random client secret and the digest of it which should be stored. The digest is
+// a plaintext digest when the token endpoint auth method is client_secret_jwt as the secret is required to validate
+// the HMAC signature, otherwise it's a PBKDF2 digest.
+func GenerateClientSecret(method string) (secret string, digest *schema.PasswordDigest, err error) {
+	secret = utils.RandomString(clientSecretLength, utils.CharSetRFC3986Unreserved)
+
+	var d algorithm.Digest
+
+	switch method {
+	case ClientAuthMethodClientSecretJWT:
+		if digest, err = decodePlainTextSecret(secret); err != nil {
+			return "", nil, err
+		}
+
+		return secret, digest, nil
+	default:
+		var hash *pbkdf2.Hasher
+
+		if hash, err = pbkdf2.NewSHA512(); err != nil {
+			return "", nil, err
+		}
+
+		if d, err = hash.Hash(secret); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return secret, &schema.PasswordDigest{Digest: d}, nil
+}
+
+// decodePlainTextSecret decodes a plaintext secret into a *schema.PasswordDigest.
+func decodePlainTextSecret(secret string) (digest *schema.PasswordDigest, err error) {
+	if digest, err = schema.DecodePasswordDigest(fmt.Sprintf("$plaintext$%s", secret)); err != nil {
+		return nil, fmt.Errorf("error decoding the plaintext client secret: %w", err)
+	}
+
+	return digest, nil
+}
+
+// GenerateRegistrationAccessToken generates a new registration access token and the signature of it which should be
+// stored.
+func GenerateRegistrationAccessToken() (token, signature string) {
+	token = utils.RandomString(registrationAccessTokenLength, utils.CharSetRFC3986Unreserved)
+
+	return token, RegistrationAccessTokenSignature(token)
+}
+
+// RegistrationAccessTokenSignature returns the signature of a registration access token.
+func RegistrationAccessTokenSignature(token string) (signature string) {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(token)))
+}
+
+// NewClientRegistrationResponse creates a new ClientRegistrationResponse.
+func NewClientRegistrationResponse(client *model.OAuth2Client, metadata ClientRegistrationMetadata, secret, token, uri string) ClientRegistrationResponse {
+	response := ClientRegistrationResponse{
+		ClientID:                   client.ClientID,
+		ClientIDIssuedAt:           client.CreatedAt.Unix(),
+		ClientSecret:               secret,
+		RegistrationAccessToken:    token,
+		RegistrationClientURI:      uri,
+		ClientRegistrationMetadata: metadata,
+	}
+
+	if secret != "" {
+		var expires int64
+
+		response.ClientSecretExpiresAt = &expires
+	}
+
+	return response
+}
+
+// ClientRegistrationMetadata represents the metadata of a client as described in RFC7591 and RFC7592 including the
+// Authelia specific metadata which can only be set by an administrator.
+//
+// https://datatracker.ietf.org/doc/html/rfc7591#section-2
+type ClientRegistrationMetadata struct {
+	ClientName                         string          `json:"client_name,omitempty"`
+	RedirectURIs                       []string        `json:"redirect_uris,omitempty"`
+	TokenEndpointAuthMethod            string          `json:"token_endpoint_auth_method,omitempty"`
+	TokenEndpointAuthSigningAlgorithm  string          `json:"token_endpoint_auth_signing_alg,omitempty"`
+	GrantTypes                         []string        `json:"grant_types,omitempty"`
+	ResponseTypes                      []string        `json:"response_types,omitempty"`
+	ResponseModes                      []string        `json:"response_modes,omitempty"`
+	Scope                              string          `json:"scope,omitempty"`
+	Audience                           []string        `json:"audience,omitempty"`
+	JSONWebKeys                        json.RawMessage `json:"jwks,omitempty"`
+	IDTokenSigningAlgorithm            string          `json:"id_token_signed_response_alg,omitempty"`
+	UserinfoSigningAlgorithm           string          `json:"userinfo_signed_response_alg,omitempty"`
+	AccessTokenSigningAlgorithm        string          `json:"access_token_signed_response_alg,omitempty"`
+	PostLogoutRedirectURIs             []string        `json:"post_logout_redirect_uris,omitempty"`
+	FrontChannelLogoutURI              string          `json:"frontchannel_logout_uri,omitempty"`
+	FrontChannelLogoutSessionRequired  bool            `json:"frontchannel_logout_session_required,omitempty"`
+	BackChannelLogoutURI               string          `json:"backchannel_logout_uri,omitempty"`
+	RequirePushedAuthorizationRequests bool            `json:"require_pushed_authorization_requests,omitempty"`
+	RequestURIs                        []string        `json:"request_uris,omitempty"`
+	RequestObjectSigningAlgorithm      string          `json:"request_object_signing_alg,omitempty"`
+	RequireSignedRequestObject         bool            `json:"require_signed_request_object,omitempty"`
+
+	SectorIdentifier             string         `json:"authelia_sector_identifier,omitempty"`
+	Policy                       string         `json:"authelia_authorization_policy,omitempty"`
+	ConsentMode                  string         `json:"authelia_consent_mode,omitempty"`
+	ConsentPreConfiguredDuration *time.Duration `json:"authelia_pre_configured_consent_duration,omitempty"`
+}
+
+// WithoutAdministrativeMetadata returns a copy of the ClientRegistrationMetadata without the metadata which can only be
+// set by an administrator.
+func (m ClientRegistrationMetadata) WithoutAdministrativeMetadata() ClientRegistrationMetadata {
+	m.SectorIdentifier, m.Policy, m.ConsentMode, m.ConsentPreConfiguredDuration = "", "", "", nil
+
+	return m
+}
+
+// ClientRegistrationResponse represents the response to a successful client registration or client read request.
+//
+// https://datatracker.ietf.org/doc/html/rfc7591#section-3.2.1
+type ClientRegistrationResponse struct {
+	ClientID                string `json:"client_id"`
+	ClientIDIssuedAt        int64  `json:"client_id_issued_at"`
+	ClientSecret            string `json:"client_secret,omitempty"`
+	ClientSecretExpiresAt   *int64 `json:"client_secret_expires_at,omitempty"`
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+	RegistrationClientURI   string `json:"registration_client_uri,omitempty"`
+
+	ClientRegistrationMetadata
+}
+
+// ClientRegistrationErrorResponse represents the response to an unsuccessful client registration request.
+//
+// https://datatracker.ietf.org/doc/html/rfc7591#section-3.2.2
+type ClientRegistrationErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
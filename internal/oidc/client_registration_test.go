@@ -0,0 +1,141 @@
+package oidc
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestClientRegistrationMetadata_RoundTrip(t *testing.T) {
+	duration := time.Hour
+
+	config := schema.OpenIDConnectClientConfiguration{
+		ID:                                 "myclient",
+		Description:                        "My Client",
+		Secret:                             MustDecodeSecret("$plaintext$mysecret"),
+		SectorIdentifier:                   url.URL{Scheme: "https", Host: "example.com"},
+		TokenEndpointAuthMethod:            ClientAuthMethodClientSecretPost,
+		RedirectURIs:                       []string{"https://app.example.com/callback"},
+		Audience:                           []string{"https://api.example.com"},
+		Scopes:                             []string{ScopeOpenID, ScopeProfile},
+		GrantTypes:                         []string{GrantTypeAuthorizationCode},
+		ResponseTypes:                      []string{"code"},
+		RequirePushedAuthorizationRequests: true,
+		Policy:                             "one_factor",
+		ConsentMode:                        ClientConsentModePreConfigured.String(),
+		ConsentPreConfiguredDuration:       &duration,
+	}
+
+	metadata := NewClientRegistrationMetadata(config)
+
+	assert.Equal(t, "My Client", metadata.ClientName)
+	assert.Equal(t, "openid profile", metadata.Scope)
+	assert.Equal(t, "https://example.com", metadata.SectorIdentifier)
+
+	actual, err := metadata.ToConfiguration(config.ID, config.Secret)
+	require.NoError(t, err)
+
+	assert.Equal(t, config, actual)
+
+	stripped := metadata.WithoutAdministrativeMetadata()
+
+	assert.Equal(t, "", stripped.SectorIdentifier)
+	assert.Equal(t, "", stripped.Policy)
+	assert.Equal(t, "", stripped.ConsentMode)
+	assert.Nil(t, stripped.ConsentPreConfiguredDuration)
+	assert.Equal(t, metadata.RedirectURIs, stripped.RedirectURIs)
+}
+
+func TestClientRegistrationMetadata_ToConfigurationPublic(t *testing.T) {
+	metadata := ClientRegistrationMetadata{
+		TokenEndpointAuthMethod: ClientAuthMethodNone,
+		JSONWebKeys:             json.RawMessage(`{"keys":[]}`),
+	}
+
+	config, err := metadata.ToConfiguration("myclient", nil)
+	require.NoError(t, err)
+
+	assert.True(t, config.Public)
+	assert.Nil(t, config.Secret)
+	assert.Equal(t, `{"keys":[]}`, config.JSONWebKeys)
+
+	metadata.SectorIdentifier = "https://example.com/%zz"
+
+	_, err = metadata.ToConfiguration("myclient", nil)
+	assert.EqualError(t, err, "error parsing the sector identifier: parse \"https://example.com/%zz\": invalid URL escape \"%zz\"")
+}
+
+func TestNewClientFromStorage(t *testing.T) {
+	metadata, err := json.Marshal(ClientRegistrationMetadata{
+		ClientName:   "My Client",
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		Scope:        "openid groups",
+		Policy:       "one_factor",
+	})
+	require.NoError(t, err)
+
+	client, err := NewClientFromStorage(&model.OAuth2Client{
+		ClientID: "myclient",
+		Secret:   []byte("$plaintext$mysecret"),
+		Metadata: metadata,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "myclient", client.GetID())
+	assert.Equal(t, "My Client", client.Description)
+	assert.Equal(t, []string{"https://app.example.com/callback"}, client.GetRedirectURIs())
+	assert.Equal(t, []string{ScopeOpenID, ScopeGroups}, []string(client.GetScopes()))
+	assert.True(t, client.Secret.MatchBytes([]byte("mysecret")))
+
+	_, err = NewClientFromStorage(&model.OAuth2Client{ClientID: "myclient", Metadata: []byte("{")})
+	assert.EqualError(t, err, "error unmarshalling the metadata of client with id 'myclient': unexpected end of JSON input")
+
+	_, err = NewClientFromStorage(&model.OAuth2Client{ClientID: "myclient", Secret: []byte("$bad$secret"), Metadata: metadata})
+	assert.Regexp(t, "^error decoding the secret of client with id 'myclient': ", err.Error())
+}
+
+func TestGenerateClientSecret(t *testing.T) {
+	secret, digest, err := GenerateClientSecret(ClientAuthMethodClientSecretBasic)
+	require.NoError(t, err)
+
+	assert.Len(t, secret, clientSecretLength)
+	assert.Regexp(t, `^\$pbkdf2-sha512\$`, digest.Encode())
+	assert.True(t, digest.MatchBytes([]byte(secret)))
+
+	secret, digest, err = GenerateClientSecret(ClientAuthMethodClientSecretJWT)
+	require.NoError(t, err)
+
+	assert.Len(t, secret, clientSecretLength)
+	assert.Equal(t, "$plaintext$"+secret, digest.Encode())
+}
+
+func TestGenerateRegistrationAccessToken(t *testing.T) {
+	token, signature := GenerateRegistrationAccessToken()
+
+	assert.Len(t, token, registrationAccessTokenLength)
+	assert.Len(t, signature, 64)
+	assert.Equal(t, signature, RegistrationAccessTokenSignature(token))
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", RegistrationAccessTokenSignature("test"))
+}
+
+func TestNewClientRegistrationResponse(t *testing.T) {
+	client := &model.OAuth2Client{ClientID: "myclient", CreatedAt: time.Unix(1000, 0)}
+
+	response := NewClientRegistrationResponse(client, ClientRegistrationMetadata{ClientName: "My Client"}, "secret", "token", "https://auth.example.com/api/oidc/registration/myclient")
+
+	data, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"client_id":"myclient","client_id_issued_at":1000,"client_secret":"secret","client_secret_expires_at":0,"registration_access_token":"token","registration_client_uri":"https://auth.example.com/api/oidc/registration/myclient","client_name":"My Client"}`, string(data))
+
+	response = NewClientRegistrationResponse(client, ClientRegistrationMetadata{}, "", "", "")
+
+	assert.Nil(t, response.ClientSecretExpiresAt)
+}
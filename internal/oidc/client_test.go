@@ -2,6 +2,7 @@ package oidc
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ory/fosite"
 	"github.com/stretchr/testify/assert"
@@ -135,6 +136,46 @@ func TestClient_GetGrantTypes(t *testing.T) {
 	assert.Equal(t, "device_code", grantTypes[0])
 }
 
+func TestClient_GetEffectiveLifespan(t *testing.T) {
+	c := Client{}
+
+	assert.Equal(t, time.Hour, c.GetEffectiveLifespan(fosite.GrantTypeClientCredentials, fosite.AccessToken, time.Hour))
+	assert.Equal(t, time.Hour, fosite.GetEffectiveLifespan(&c, fosite.GrantTypeAuthorizationCode, fosite.IDToken, time.Hour))
+
+	c.TokenLifespans = ClientTokenLifespans{
+		AccessToken:  time.Minute,
+		IDToken:      time.Minute * 2,
+		RefreshToken: time.Minute * 3,
+	}
+
+	assert.Equal(t, time.Minute, c.GetEffectiveLifespan(fosite.GrantTypeClientCredentials, fosite.AccessToken, time.Hour))
+	assert.Equal(t, time.Minute*2, fosite.GetEffectiveLifespan(&c, fosite.GrantTypeAuthorizationCode, fosite.IDToken, time.Hour))
+	assert.Equal(t, time.Minute*3, c.GetEffectiveLifespan(fosite.GrantTypeRefreshToken, fosite.RefreshToken, time.Hour))
+	assert.Equal(t, time.Hour, c.GetEffectiveLifespan(fosite.GrantTypeAuthorizationCode, fosite.AuthorizeCode, time.Hour))
+}
+
+func TestClient_GetRefreshTokenAbsoluteLifespan(t *testing.T) {
+	c := Client{}
+
+	assert.Equal(t, time.Hour, c.GetRefreshTokenAbsoluteLifespan(time.Hour))
+	assert.Equal(t, time.Duration(0), c.GetRefreshTokenAbsoluteLifespan(0))
+
+	c.TokenLifespans.RefreshTokenAbsolute = time.Hour * 24
+
+	assert.Equal(t, time.Hour*24, c.GetRefreshTokenAbsoluteLifespan(time.Hour))
+}
+
+func TestClient_GetRefreshTokenReuseGracePeriod(t *testing.T) {
+	c := Client{}
+
+	assert.Equal(t, time.Minute, c.GetRefreshTokenReuseGracePeriod(time.Minute))
+	assert.Equal(t, time.Duration(0), c.GetRefreshTokenReuseGracePeriod(0))
+
+	c.TokenLifespans.RefreshTokenReuseGracePeriod = time.Minute * 5
+
+	assert.Equal(t, time.Minute*5, c.GetRefreshTokenReuseGracePeriod(time.Minute))
+}
+
 func TestClient_Hashing(t *testing.T) {
 	c := Client{}
 
@@ -32,6 +32,10 @@ func NewConfig(config *schema.OpenIDConnectConfiguration) *Config {
 			AuthorizeCode: config.AuthorizeCodeLifespan,
 			IDToken:       config.IDTokenLifespan,
 			RefreshToken:  config.RefreshTokenLifespan,
+			DeviceCode:    config.DeviceCodeLifespan,
+		},
+		DeviceCode: DeviceCodeConfig{
+			PollingInterval: config.DeviceCodePollingInterval,
 		},
 		ProofKeyCodeExchange: ProofKeyCodeExchangeConfig{
 			Enforce:                   config.EnforcePKCE == "always",
@@ -41,12 +45,15 @@ func NewConfig(config *schema.OpenIDConnectConfiguration) *Config {
 	}
 
 	prefix := "authelia_%s_"
-	c.Strategy.Core = &HMACCoreStrategy{
+	core := &HMACCoreStrategy{
 		Enigma: &hmac.HMACStrategy{Config: c},
 		Config: c,
 		prefix: &prefix,
 	}
 
+	c.Strategy.Core = core
+	c.Strategy.DeviceCode = core
+
 	return c
 }
 
@@ -70,6 +77,7 @@ type Config struct {
 	Hash                 HashConfig
 	Strategy             StrategyConfig
 	PAR                  PARConfig
+	DeviceCode           DeviceCodeConfig
 	Handlers             HandlersConfig
 	Lifespans            LifespanConfig
 	ProofKeyCodeExchange ProofKeyCodeExchangeConfig
@@ -100,6 +108,7 @@ type StrategyConfig struct {
 	Scope                fosite.ScopeStrategy
 	JWKSFetcher          fosite.JWKSFetcherStrategy
 	ClientAuthentication fosite.ClientAuthenticationStrategy
+	DeviceCode           DeviceCodeStrategy
 }
 
 type PARConfig struct {
@@ -108,6 +117,10 @@ type PARConfig struct {
 	ContextLifespan time.Duration
 }
 
+type DeviceCodeConfig struct {
+	PollingInterval time.Duration
+}
+
 type IssuersConfig struct {
 	IDToken     string
 	AccessToken string
@@ -150,6 +163,7 @@ type LifespanConfig struct {
 	AuthorizeCode time.Duration
 	IDToken       time.Duration
 	RefreshToken  time.Duration
+	DeviceCode    time.Duration
 }
 
 const (
@@ -183,11 +197,14 @@ func (c *Config) LoadHandlers(store *Store, strategy jwt.Signer) {
 			},
 			Config: c,
 		},
-		&oauth2.RefreshTokenGrantHandler{
-			AccessTokenStrategy:    c.Strategy.Core,
-			RefreshTokenStrategy:   c.Strategy.Core,
-			TokenRevocationStorage: store,
-			Config:                 c,
+		&RefreshTokenGrantHandler{
+			RefreshTokenGrantHandler: &oauth2.RefreshTokenGrantHandler{
+				AccessTokenStrategy:    c.Strategy.Core,
+				RefreshTokenStrategy:   c.Strategy.Core,
+				TokenRevocationStorage: store,
+				Config:                 c,
+			},
+			Storage: store,
 		},
 		&openid.OpenIDConnectExplicitHandler{
 			IDTokenHandleHelper: &openid.IDTokenHandleHelper{
@@ -250,10 +267,26 @@ func (c *Config) LoadHandlers(store *Store, strategy jwt.Signer) {
 			Storage:               store,
 			Config:                c,
 		},
+		&DeviceCodeGrantHandler{
+			CoreStrategy:       c.Strategy.Core,
+			DeviceCodeStrategy: c.Strategy.DeviceCode,
+			IDTokenHandleHelper: &openid.IDTokenHandleHelper{
+				IDTokenStrategy: c.Strategy.OpenID,
+			},
+			Storage: store,
+			Config:  c,
+		},
+		&TokenExchangeGrantHandler{
+			CoreStrategy: c.Strategy.Core,
+			Storage:      store,
+			Config:       c,
+		},
 		&par.PushedAuthorizeHandler{
 			Storage: store,
 			Config:  c,
 		},
+		// The core strategy is also a fosite.RevocationHandler when it issues JWT access tokens.
+		c.Strategy.Core,
 	}
 
 	x := HandlersConfig{}
@@ -274,6 +307,10 @@ func (c *Config) LoadHandlers(store *Store, strategy jwt.Signer) {
 		if h, ok := handler.(fosite.RevocationHandler); ok {
 			x.Revocation.Append(h)
 		}
+
+		if h, ok := handler.(fosite.PushedAuthorizeEndpointHandler); ok {
+			x.PushedAuthorizeEndpoint.Append(h)
+		}
 	}
 
 	c.Handlers = x
@@ -404,6 +441,24 @@ func (c *Config) GetAccessTokenLifespan(ctx context.Context) (lifespan time.Dura
 	return c.Lifespans.AccessToken
 }
 
+// GetDeviceCodeLifespan returns the device code lifespan.
+func (c *Config) GetDeviceCodeLifespan(ctx context.Context) (lifespan time.Duration) {
+	if c.Lifespans.DeviceCode <= 0 {
+		c.Lifespans.DeviceCode = lifespanDeviceCodeDefault
+	}
+
+	return c.Lifespans.DeviceCode
+}
+
+// GetDeviceCodePollingInterval returns the minimum interval clients should wait between device code polling requests.
+func (c *Config) GetDeviceCodePollingInterval(ctx context.Context) (interval time.Duration) {
+	if c.DeviceCode.PollingInterval <= 0 {
+		c.DeviceCode.PollingInterval = intervalDeviceCodePollingDefault
+	}
+
+	return c.DeviceCode.PollingInterval
+}
+
 // GetTokenEntropy returns the token entropy.
 func (c *Config) GetTokenEntropy(ctx context.Context) (entropy int) {
 	if c.TokenEntropy == 0 {
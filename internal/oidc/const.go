@@ -38,6 +38,13 @@ const (
 	ClaimAuthenticationContextClassReference = "acr"
 	ClaimAuthenticationMethodsReference      = "amr"
 	ClaimClientIdentifier                    = "client_id"
+	ClaimScope                               = "scope"
+)
+
+// Authentication Context Class Reference values. Each value represents one of the authentication levels.
+const (
+	ACROneFactor = "urn:authelia:acr:one_factor"
+	ACRTwoFactor = "urn:authelia:acr:two_factor"
 )
 
 const (
@@ -45,6 +52,30 @@ const (
 	lifespanRefreshTokenDefault  = time.Hour * 24 * 30
 	lifespanAuthorizeCodeDefault = time.Minute * 15
 	lifespanPARContextDefault    = time.Minute * 5
+	lifespanDeviceCodeDefault    = time.Minute * 10
+
+	intervalDeviceCodePollingDefault  = time.Second * 5
+	intervalDeviceCodePollingSlowDown = time.Second * 5
+
+	cacheTwoFactorStorageClientLifespan = time.Minute
+)
+
+const (
+	clientSecretLength            = 72
+	registrationAccessTokenLength = 64
+)
+
+// Client Registration Error codes.
+const (
+	ErrorCodeInvalidClientMetadata = "invalid_client_metadata"
+	ErrorCodeInvalidRedirectURI    = "invalid_redirect_uri"
+	ErrorCodeInvalidToken          = "invalid_token"
+)
+
+// Client Sources are the sources which clients managed via the storage provider can originate from.
+const (
+	ClientSourceCLI          = "cli"
+	ClientSourceRegistration = "registration"
 )
 
 const (
@@ -71,12 +102,67 @@ const (
 	GrantTypeAuthorizationCode = "authorization_code"
 	GrantTypePassword          = "password"
 	GrantTypeClientCredentials = "client_credentials"
+	GrantTypeDeviceCode        = "urn:ietf:params:oauth:grant-type:device_code"
+	GrantTypeTokenExchange     = "urn:ietf:params:oauth:grant-type:token-exchange"
 )
 
 // Signing Algorithm strings.
 const (
-	SigningAlgorithmNone          = none
-	SigningAlgorithmRSAWithSHA256 = "RS256"
+	SigningAlgorithmNone                   = none
+	SigningAlgorithmRSAWithSHA256          = "RS256"
+	SigningAlgorithmRSAWithSHA384          = "RS384"
+	SigningAlgorithmRSAWithSHA512          = "RS512"
+	SigningAlgorithmRSAPSSWithSHA256       = "PS256"
+	SigningAlgorithmRSAPSSWithSHA384       = "PS384"
+	SigningAlgorithmRSAPSSWithSHA512       = "PS512"
+	SigningAlgorithmECDSAWithP256AndSHA256 = "ES256"
+	SigningAlgorithmECDSAWithP384AndSHA384 = "ES384"
+	SigningAlgorithmECDSAWithP521AndSHA512 = "ES512"
+	SigningAlgorithmEdDSA                  = "EdDSA"
+	SigningAlgorithmHMACWithSHA256         = "HS256"
+	SigningAlgorithmHMACWithSHA384         = "HS384"
+	SigningAlgorithmHMACWithSHA512         = "HS512"
+)
+
+// JSON Web Key use strings.
+const (
+	KeyUseSignature  = "sig"
+	KeyUseEncryption = "enc"
+)
+
+// Encryption Algorithm strings used to decrypt Request Objects.
+const (
+	EncryptionAlgorithmRSAOAEP             = "RSA-OAEP"
+	EncryptionAlgorithmRSAOAEP256          = "RSA-OAEP-256"
+	EncryptionAlgorithmECDHES              = "ECDH-ES"
+	EncryptionAlgorithmECDHESWithA128KW    = "ECDH-ES+A128KW"
+	EncryptionAlgorithmECDHESWithA192KW    = "ECDH-ES+A192KW"
+	EncryptionAlgorithmECDHESWithA256KW    = "ECDH-ES+A256KW"
+	ContentEncryptionAlgorithmA128CBCHS256 = "A128CBC-HS256"
+	ContentEncryptionAlgorithmA192CBCHS384 = "A192CBC-HS384"
+	ContentEncryptionAlgorithmA256CBCHS512 = "A256CBC-HS512"
+	ContentEncryptionAlgorithmA128GCM      = "A128GCM"
+	ContentEncryptionAlgorithmA192GCM      = "A192GCM"
+	ContentEncryptionAlgorithmA256GCM      = "A256GCM"
+)
+
+// Client Authentication Method strings.
+const (
+	ClientAuthMethodClientSecretBasic = "client_secret_basic"
+	ClientAuthMethodClientSecretPost  = "client_secret_post"
+	ClientAuthMethodClientSecretJWT   = "client_secret_jwt"
+	ClientAuthMethodPrivateKeyJWT     = "private_key_jwt"
+	ClientAuthMethodNone              = none
+)
+
+// Client Assertion strings.
+const (
+	// ClientAssertionJWTBearerType is the client_assertion_type value for JWT client assertions as per RFC7523.
+	ClientAssertionJWTBearerType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	FormParameterClientAssertionType = "client_assertion_type"
+	FormParameterClientAssertion     = "client_assertion"
+	FormParameterClientSecret        = "client_secret"
 )
 
 // Subject Type strings.
@@ -98,17 +184,116 @@ const (
 	EndpointUserinfo      = "userinfo"
 	EndpointIntrospection = "introspection"
 	EndpointRevocation    = "revocation"
+	EndpointEndSession    = "logout"
+
+	EndpointDeviceAuthorization        = "device-authorization"
+	EndpointPushedAuthorizationRequest = "pushed-authorization-request"
+	EndpointRegistration               = "registration"
+)
+
+// Device Authorization Grant strings.
+const (
+	FormParameterDeviceCode = "device_code"
+	FormParameterUserCode   = "user_code"
+
+	// DeviceUserCodeCharset is the charset used to generate user codes. It's the base-20 charset recommended by RFC8628
+	// which excludes vowels and characters which are easily confused with one another.
+	DeviceUserCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+
+	// DeviceUserCodeLength is the number of characters from the DeviceUserCodeCharset used in a user code.
+	DeviceUserCodeLength = 8
+)
+
+// JWT Access Token strings.
+const (
+	// JWTTypeAccessToken is the explicit typ header value for JWT Access Tokens as per RFC9068.
+	JWTTypeAccessToken = "at+jwt"
+)
+
+// Token Exchange strings.
+const (
+	FormParameterSubjectToken       = "subject_token"
+	FormParameterSubjectTokenType   = "subject_token_type"
+	FormParameterActorToken         = "actor_token"
+	FormParameterActorTokenType     = "actor_token_type"
+	FormParameterRequestedTokenType = "requested_token_type"
+	FormParameterIssuedTokenType    = "issued_token_type"
+	FormParameterResource           = "resource"
+
+	// TokenTypeAccessToken is the token type identifier for OAuth 2.0 access tokens as per RFC8693.
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+	// ClaimActor is the claim which identifies the acting party to whom authority has been delegated.
+	ClaimActor = "act"
+)
+
+// Claim Attributes are the user attributes which the value of a custom claim may be sourced from.
+const (
+	ClaimAttributeUsername    = "username"
+	ClaimAttributeDisplayName = "display_name"
+	ClaimAttributeEmail       = "email"
+	ClaimAttributeEmails      = "emails"
+	ClaimAttributeGroups      = "groups"
 )
 
 // JWT Headers.
 const (
 	// JWTHeaderKeyIdentifier is the JWT Header referencing the JWS Key Identifier used to sign a token.
 	JWTHeaderKeyIdentifier = "kid"
+
+	// JWTHeaderAlgorithm is the JWT Header referencing the JWS Algorithm used to sign a token.
+	JWTHeaderAlgorithm = "alg"
+
+	// JWTHeaderType is the JWT Header referencing the media type of the token.
+	JWTHeaderType = "typ"
+
+	// JWTTypeJWT is the typ header value for a JWT with no explicit type such as an ID Token.
+	JWTTypeJWT = "JWT"
+)
+
+// Logout strings.
+const (
+	// JWTTypeLogout is the explicit typ header value for Logout Tokens.
+	JWTTypeLogout = "logout+jwt"
+
+	// ClaimEvents is the Logout Token claim which contains the back-channel logout event.
+	ClaimEvents = "events"
+
+	// EventBackChannelLogout is the event identifier for back-channel logout.
+	EventBackChannelLogout = "http://schemas.openid.net/event/backchannel-logout"
+
+	// FormParameterLogoutToken is the form parameter used to transmit the Logout Token to a client.
+	FormParameterLogoutToken = "logout_token"
+
+	FormParameterIDTokenHint           = "id_token_hint"
+	FormParameterPostLogoutRedirectURI = "post_logout_redirect_uri"
+	FormParameterClientID              = "client_id"
+	FormParameterState                 = "state"
+	FormParameterScope                 = "scope"
+	FormParameterAudience              = "audience"
+	FormParameterRefreshToken          = "refresh_token"
+	FormParameterGrantType             = "grant_type"
+	FormParameterRequest               = "request"
+	FormParameterRequestURI            = "request_uri"
+	FormParameterPrompt                = "prompt"
+	FormParameterMaxAge                = "max_age"
+	FormParameterLoginHint             = "login_hint"
+	FormParameterACRValues             = "acr_values"
+
+	lifespanLogoutTokenDefault = time.Minute * 2
+
+	// timeoutBackChannelLogoutDelivery is the maximum duration of the delivery of a Logout Token to a back-channel
+	// logout uri including any retries.
+	timeoutBackChannelLogoutDelivery = time.Second * 10
+
+	// backChannelLogoutWorkersMaximum is the maximum number of Logout Tokens delivered concurrently.
+	backChannelLogoutWorkersMaximum = 10
 )
 
 // Paths.
 const (
 	EndpointPathConsent                           = "/consent"
+	EndpointPathDevice                            = "/device"
 	EndpointPathWellKnownOpenIDConfiguration      = "/.well-known/openid-configuration"
 	EndpointPathWellKnownOAuthAuthorizationServer = "/.well-known/oauth-authorization-server"
 	EndpointPathJWKs                              = "/jwks.json"
@@ -120,6 +305,13 @@ const (
 	EndpointPathUserinfo      = EndpointPathRoot + "/" + EndpointUserinfo
 	EndpointPathIntrospection = EndpointPathRoot + "/" + EndpointIntrospection
 	EndpointPathRevocation    = EndpointPathRoot + "/" + EndpointRevocation
+	EndpointPathEndSession    = EndpointPathRoot + "/" + EndpointEndSession
+
+	EndpointPathDeviceAuthorization = EndpointPathRoot + "/" + EndpointDeviceAuthorization
+	EndpointPathDeviceUserCode      = EndpointPathRoot + "/device"
+
+	EndpointPathPushedAuthorizationRequest = EndpointPathRoot + "/" + EndpointPushedAuthorizationRequest
+	EndpointPathRegistration               = EndpointPathRoot + "/" + EndpointRegistration
 )
 
 // Authentication Method Reference Values https://datatracker.ietf.org/doc/html/rfc8176
@@ -222,3 +414,8 @@ const (
 	preconfigured = "pre-configured"
 	none          = "none"
 )
+
+const (
+	// requestObjectMaxSize is the maximum size in bytes of a Request Object retrieved using a 'request_uri'.
+	requestObjectMaxSize = 1 << 20
+)
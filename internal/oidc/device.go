@@ -0,0 +1,346 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/ory/fosite/handler/openid"
+	"github.com/ory/x/errorsx"
+
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// DeviceCodeStrategy generates and validates the device codes and user codes of the OAuth 2.0 Device Authorization Grant.
+type DeviceCodeStrategy interface {
+	DeviceCodeSignature(ctx context.Context, token string) (signature string)
+	GenerateDeviceCode(ctx context.Context) (token string, signature string, err error)
+	ValidateDeviceCode(ctx context.Context, r fosite.Requester, token string) (err error)
+
+	UserCodeSignature(ctx context.Context, code string) (signature string, err error)
+	GenerateUserCode(ctx context.Context) (code string, signature string, err error)
+}
+
+// DeviceCodeStorage persists the sessions of the OAuth 2.0 Device Authorization Grant.
+type DeviceCodeStorage interface {
+	CreateDeviceCodeSession(ctx context.Context, signature, userCodeSignature string, expiresAt time.Time, request fosite.Requester) (err error)
+	GetDeviceCodeSession(ctx context.Context, signature string, session fosite.Session) (device *model.OAuth2DeviceCodeSession, request fosite.Requester, err error)
+	CheckDeviceCodeSession(ctx context.Context, signature string, checkedAt time.Time, interval time.Duration) (err error)
+	InvalidateDeviceCodeSession(ctx context.Context, signature string) (err error)
+}
+
+// DeviceAuthorizeResponse is the successful response of the device authorization endpoint.
+//
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.2
+type DeviceAuthorizeResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval,omitempty"`
+}
+
+// NormalizeUserCode normalizes a user code entered by an end user by removing any separators and whitespace and
+// converting it to upper case.
+func NormalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', ' ', '\t':
+			return -1
+		default:
+			return r
+		}
+	}, strings.ToUpper(code))
+}
+
+// NewDeviceAuthorizeRequest validates a request to the device authorization endpoint and returns the fosite.Requester
+// which represents it.
+//
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.1
+func (p *OpenIDConnectProvider) NewDeviceAuthorizeRequest(ctx context.Context, r *http.Request, session fosite.Session) (requester fosite.Requester, err error) {
+	request := fosite.NewRequest()
+	request.Session = session
+
+	if r.Method != http.MethodPost {
+		return request, errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("HTTP method is '%s', expected 'POST'.", r.Method))
+	}
+
+	if err = r.ParseMultipartForm(1 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return request, errorsx.WithStack(fosite.ErrInvalidRequest.WithHint("Unable to parse HTTP body, make sure to send a properly formatted form request body.").WithWrap(err).WithDebug(err.Error()))
+	}
+
+	request.Form = r.PostForm
+
+	var client fosite.Client
+
+	if client, err = p.Config.GetClientAuthenticationStrategy(ctx)(ctx, r, request.Form); err != nil {
+		return request, err
+	}
+
+	request.Client = client
+
+	if !client.GetGrantTypes().Has(GrantTypeDeviceCode) {
+		return request, errorsx.WithStack(fosite.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use the authorization grant '%s'.", GrantTypeDeviceCode))
+	}
+
+	request.SetRequestedScopes(fosite.RemoveEmpty(strings.Split(request.Form.Get(FormParameterScope), " ")))
+	request.SetRequestedAudience(fosite.GetAudiences(request.Form))
+
+	for _, scope := range request.GetRequestedScopes() {
+		if !p.Config.GetScopeStrategy(ctx)(client.GetScopes(), scope) {
+			return request, errorsx.WithStack(fosite.ErrInvalidScope.WithHintf("The OAuth 2.0 Client is not allowed to request scope '%s'.", scope))
+		}
+	}
+
+	if err = p.Config.GetAudienceStrategy(ctx)(client.GetAudience(), request.GetRequestedAudience()); err != nil {
+		return request, err
+	}
+
+	return request, nil
+}
+
+// NewDeviceAuthorizeResponse generates the device code and user code for a device authorization request, persists the
+// device code session, and returns the DeviceAuthorizeResponse.
+func (p *OpenIDConnectProvider) NewDeviceAuthorizeResponse(ctx context.Context, requester fosite.Requester, verificationURI string) (response *DeviceAuthorizeResponse, err error) {
+	var (
+		deviceCode, deviceCodeSignature, userCode, userCodeSignature string
+	)
+
+	if deviceCode, deviceCodeSignature, err = p.Config.Strategy.DeviceCode.GenerateDeviceCode(ctx); err != nil {
+		return nil, errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if userCode, userCodeSignature, err = p.Config.Strategy.DeviceCode.GenerateUserCode(ctx); err != nil {
+		return nil, errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	lifespan := p.Config.GetDeviceCodeLifespan(ctx)
+
+	if err = p.Store.CreateDeviceCodeSession(ctx, deviceCodeSignature, userCodeSignature, requester.GetRequestedAt().Add(lifespan), requester.Sanitize([]string{FormParameterClientID, FormParameterScope, FormParameterAudience})); err != nil {
+		return nil, errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	return &DeviceAuthorizeResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: verificationURI + "?" + FormParameterUserCode + "=" + userCode,
+		ExpiresIn:               int64(lifespan / time.Second),
+		Interval:                int64(p.Config.GetDeviceCodePollingInterval(ctx) / time.Second),
+	}, nil
+}
+
+// DeviceCodeGrantStorage is the storage used by the DeviceCodeGrantHandler.
+type DeviceCodeGrantStorage interface {
+	DeviceCodeStorage
+	oauth2.AccessTokenStorage
+	oauth2.RefreshTokenStorage
+}
+
+// DeviceCodeGrantHandler is a fosite.TokenEndpointHandler which handles the token endpoint polling of the OAuth 2.0
+// Device Authorization Grant.
+//
+// https://datatracker.ietf.org/doc/html/rfc8628#section-3.4
+type DeviceCodeGrantHandler struct {
+	CoreStrategy        oauth2.CoreStrategy
+	DeviceCodeStrategy  DeviceCodeStrategy
+	IDTokenHandleHelper *openid.IDTokenHandleHelper
+	Storage             DeviceCodeGrantStorage
+	Config              *Config
+}
+
+// CanSkipClientAuth implements fosite.TokenEndpointHandler.
+func (h *DeviceCodeGrantHandler) CanSkipClientAuth(ctx context.Context, requester fosite.AccessRequester) bool {
+	return false
+}
+
+// CanHandleTokenEndpointRequest implements fosite.TokenEndpointHandler.
+func (h *DeviceCodeGrantHandler) CanHandleTokenEndpointRequest(ctx context.Context, requester fosite.AccessRequester) bool {
+	return requester.GetGrantTypes().ExactOne(GrantTypeDeviceCode)
+}
+
+// HandleTokenEndpointRequest implements fosite.TokenEndpointHandler.
+func (h *DeviceCodeGrantHandler) HandleTokenEndpointRequest(ctx context.Context, requester fosite.AccessRequester) (err error) {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return errorsx.WithStack(fosite.ErrUnknownRequest)
+	}
+
+	if !requester.GetClient().GetGrantTypes().Has(GrantTypeDeviceCode) {
+		return errorsx.WithStack(fosite.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use the authorization grant '%s'.", GrantTypeDeviceCode))
+	}
+
+	code := requester.GetRequestForm().Get(FormParameterDeviceCode)
+
+	if code == "" {
+		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("The '%s' parameter must be set but is not.", FormParameterDeviceCode))
+	}
+
+	signature := h.DeviceCodeStrategy.DeviceCodeSignature(ctx, code)
+
+	var (
+		device  *model.OAuth2DeviceCodeSession
+		request fosite.Requester
+	)
+
+	if device, request, err = h.Storage.GetDeviceCodeSession(ctx, signature, requester.GetSession()); err != nil {
+		if errors.Is(err, fosite.ErrNotFound) {
+			return errorsx.WithStack(fosite.ErrInvalidGrant.WithHint("The device code is not valid.").WithWrap(err).WithDebug(err.Error()))
+		}
+
+		return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if err = h.DeviceCodeStrategy.ValidateDeviceCode(ctx, request, code); err != nil {
+		return errorsx.WithStack(fosite.ErrInvalidGrant.WithHint("The device code is not valid.").WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if request.GetClient().GetID() != requester.GetClient().GetID() {
+		return errorsx.WithStack(fosite.ErrInvalidGrant.WithHint("The OAuth 2.0 Client ID from this request does not match the one from the device authorization request."))
+	}
+
+	if !device.Active {
+		return errorsx.WithStack(fosite.ErrInvalidGrant.WithHint("The device code has already been used."))
+	}
+
+	now := time.Now()
+
+	if device.IsExpired() {
+		return errorsx.WithStack(ErrDeviceExpiredToken)
+	}
+
+	switch device.Status {
+	case model.OAuth2DeviceCodeStatusAuthorized:
+		break
+	case model.OAuth2DeviceCodeStatusDenied:
+		if err = h.Storage.InvalidateDeviceCodeSession(ctx, signature); err != nil {
+			return h.handleInvalidateDeviceCodeSessionError(err)
+		}
+
+		return errorsx.WithStack(fosite.ErrAccessDenied.WithHint("The end user denied the device authorization request."))
+	default:
+		interval := device.GetPollingInterval(h.Config.GetDeviceCodePollingInterval(ctx))
+
+		// When the client polls too fast the interval is increased for this and all subsequent requests.
+		slowDown := now.Sub(device.CheckedAt) < interval
+
+		if slowDown {
+			interval += intervalDeviceCodePollingSlowDown
+		}
+
+		if err = h.Storage.CheckDeviceCodeSession(ctx, signature, now, interval); err != nil {
+			return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+		}
+
+		if slowDown {
+			return errorsx.WithStack(ErrDeviceSlowDown)
+		}
+
+		return errorsx.WithStack(ErrDeviceAuthorizationPending)
+	}
+
+	requester.SetRequestedScopes(request.GetRequestedScopes())
+	requester.SetRequestedAudience(request.GetRequestedAudience())
+
+	for _, scope := range request.GetGrantedScopes() {
+		requester.GrantScope(scope)
+	}
+
+	for _, audience := range request.GetGrantedAudience() {
+		requester.GrantAudience(audience)
+	}
+
+	requester.SetSession(request.GetSession())
+	requester.SetID(request.GetID())
+
+	atLifespan := fosite.GetEffectiveLifespan(requester.GetClient(), GrantTypeDeviceCode, fosite.AccessToken, h.Config.GetAccessTokenLifespan(ctx))
+	requester.GetSession().SetExpiresAt(fosite.AccessToken, now.UTC().Add(atLifespan).Round(time.Second))
+
+	rtLifespan := fosite.GetEffectiveLifespan(requester.GetClient(), GrantTypeDeviceCode, fosite.RefreshToken, h.Config.GetRefreshTokenLifespan(ctx))
+	if rtLifespan > -1 {
+		requester.GetSession().SetExpiresAt(fosite.RefreshToken, now.UTC().Add(rtLifespan).Round(time.Second))
+	}
+
+	return nil
+}
+
+// PopulateTokenEndpointResponse implements fosite.TokenEndpointHandler.
+func (h *DeviceCodeGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, requester fosite.AccessRequester, responder fosite.AccessResponder) (err error) {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return errorsx.WithStack(fosite.ErrUnknownRequest)
+	}
+
+	var (
+		access, accessSignature, refresh, refreshSignature string
+	)
+
+	if access, accessSignature, err = h.CoreStrategy.GenerateAccessToken(ctx, requester); err != nil {
+		return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if h.canIssueRefreshToken(ctx, requester) {
+		if refresh, refreshSignature, err = h.CoreStrategy.GenerateRefreshToken(ctx, requester); err != nil {
+			return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+		}
+	}
+
+	signature := h.DeviceCodeStrategy.DeviceCodeSignature(ctx, requester.GetRequestForm().Get(FormParameterDeviceCode))
+
+	if err = h.Storage.InvalidateDeviceCodeSession(ctx, signature); err != nil {
+		return h.handleInvalidateDeviceCodeSessionError(err)
+	}
+
+	if err = h.Storage.CreateAccessTokenSession(ctx, accessSignature, requester.Sanitize([]string{})); err != nil {
+		return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if refreshSignature != "" {
+		if err = h.Storage.CreateRefreshTokenSession(ctx, refreshSignature, requester.Sanitize([]string{})); err != nil {
+			return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+		}
+	}
+
+	atLifespan := fosite.GetEffectiveLifespan(requester.GetClient(), GrantTypeDeviceCode, fosite.AccessToken, h.Config.GetAccessTokenLifespan(ctx))
+
+	responder.SetAccessToken(access)
+	responder.SetTokenType("bearer")
+	responder.SetExpiresIn(atLifespan)
+	responder.SetScopes(requester.GetGrantedScopes())
+
+	if refresh != "" {
+		responder.SetExtra(FormParameterRefreshToken, refresh)
+	}
+
+	if requester.GetGrantedScopes().Has(ScopeOpenID) {
+		idLifespan := fosite.GetEffectiveLifespan(requester.GetClient(), GrantTypeDeviceCode, fosite.IDToken, h.Config.GetIDTokenLifespan(ctx))
+
+		if err = h.IDTokenHandleHelper.IssueExplicitIDToken(ctx, idLifespan, requester, responder); err != nil {
+			return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+		}
+	}
+
+	return nil
+}
+
+// handleInvalidateDeviceCodeSessionError returns the error for a failure to invalidate the device code session. The
+// device code session is only invalidated if it's still active, if it isn't then a concurrent request has already
+// used it.
+func (h *DeviceCodeGrantHandler) handleInvalidateDeviceCodeSessionError(err error) error {
+	if errors.Is(err, fosite.ErrInactiveToken) {
+		return errorsx.WithStack(fosite.ErrInvalidGrant.WithHint("The device code has already been used."))
+	}
+
+	return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+}
+
+func (h *DeviceCodeGrantHandler) canIssueRefreshToken(ctx context.Context, requester fosite.Requester) bool {
+	if scopes := h.Config.GetRefreshTokenScopes(ctx); len(scopes) > 0 && !requester.GetGrantedScopes().HasOneOf(scopes...) {
+		return false
+	}
+
+	return requester.GetClient().GetGrantTypes().Has(GrantTypeRefreshToken)
+}
@@ -0,0 +1,283 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestNormalizeUserCode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+	}{
+		{"ShouldNotModifyNormalized", "BCDFGHJK", "BCDFGHJK"},
+		{"ShouldRemoveSeparator", "BCDF-GHJK", "BCDFGHJK"},
+		{"ShouldUpperCase", "bcdf-ghjk", "BCDFGHJK"},
+		{"ShouldRemoveWhitespace", " bcdf ghjk\t", "BCDFGHJK"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizeUserCode(tc.have))
+		})
+	}
+}
+
+func TestHMACCoreStrategy_GenerateUserCode(t *testing.T) {
+	config := NewConfig(&schema.OpenIDConnectConfiguration{HMACSecret: "abc123"})
+
+	ctx := context.Background()
+
+	code, signature, err := config.Strategy.DeviceCode.GenerateUserCode(ctx)
+	require.NoError(t, err)
+
+	assert.Regexp(t, regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`), code)
+
+	actual, err := config.Strategy.DeviceCode.UserCodeSignature(ctx, code)
+	require.NoError(t, err)
+	assert.Equal(t, signature, actual)
+
+	actual, err = config.Strategy.DeviceCode.UserCodeSignature(ctx, NormalizeUserCode(code))
+	require.NoError(t, err)
+	assert.Equal(t, signature, actual)
+
+	other := NewConfig(&schema.OpenIDConnectConfiguration{HMACSecret: "xyz789"})
+
+	actual, err = other.Strategy.DeviceCode.UserCodeSignature(ctx, code)
+	require.NoError(t, err)
+	assert.NotEqual(t, signature, actual)
+}
+
+func TestDeviceCodeGrantHandler_HandleTokenEndpointRequest(t *testing.T) {
+	config := NewConfig(&schema.OpenIDConnectConfiguration{HMACSecret: "abc123", DeviceCodePollingInterval: time.Second * 5})
+
+	ctx := context.Background()
+
+	client := &Client{ID: "device", GrantTypes: []string{GrantTypeDeviceCode, GrantTypeRefreshToken}}
+
+	testCases := []struct {
+		name     string
+		client   fosite.Client
+		setup    func(device *model.OAuth2DeviceCodeSession)
+		used     bool
+		err      string
+		checks   int
+		interval time.Duration
+	}{
+		{
+			name:   "ShouldReturnAuthorizationPending",
+			client: client,
+			setup: func(device *model.OAuth2DeviceCodeSession) {
+				device.CheckedAt = time.Now().Add(time.Second * -10)
+			},
+			err:      "The authorization request is still pending as the end user hasn't yet completed the user-interaction steps.",
+			checks:   1,
+			interval: time.Second * 5,
+		},
+		{
+			name:   "ShouldReturnSlowDown",
+			client: client,
+			setup: func(device *model.OAuth2DeviceCodeSession) {
+				device.CheckedAt = time.Now().Add(time.Second * -1)
+			},
+			err:      "The authorization request is still pending and polling should continue, but the interval MUST be increased by 5 seconds for this and all subsequent requests.",
+			checks:   1,
+			interval: time.Second * 10,
+		},
+		{
+			name:   "ShouldReturnSlowDownWhenPollingFasterThanIncreasedInterval",
+			client: client,
+			setup: func(device *model.OAuth2DeviceCodeSession) {
+				device.CheckedAt = time.Now().Add(time.Second * -7)
+				device.PollingInterval = 10
+			},
+			err:      "The authorization request is still pending and polling should continue, but the interval MUST be increased by 5 seconds for this and all subsequent requests.",
+			checks:   1,
+			interval: time.Second * 15,
+		},
+		{
+			name:   "ShouldReturnAuthorizationPendingWithIncreasedInterval",
+			client: client,
+			setup: func(device *model.OAuth2DeviceCodeSession) {
+				device.CheckedAt = time.Now().Add(time.Second * -11)
+				device.PollingInterval = 10
+			},
+			err:      "The authorization request is still pending as the end user hasn't yet completed the user-interaction steps.",
+			checks:   1,
+			interval: time.Second * 10,
+		},
+		{
+			name:   "ShouldReturnInvalidGrantWhenUsedConcurrently",
+			client: client,
+			setup: func(device *model.OAuth2DeviceCodeSession) {
+				device.Status = model.OAuth2DeviceCodeStatusDenied
+			},
+			used: true,
+			err:  "The provided authorization grant (e.g., authorization code, resource owner credentials) or refresh token is invalid, expired, revoked, does not match the redirection URI used in the authorization request, or was issued to another client. The device code has already been used.",
+		},
+		{
+			name:   "ShouldReturnExpiredToken",
+			client: client,
+			setup: func(device *model.OAuth2DeviceCodeSession) {
+				device.ExpiresAt = time.Now().Add(time.Minute * -1)
+			},
+			err: "The device code has expired, and the device authorization session has concluded.",
+		},
+		{
+			name:   "ShouldReturnAccessDenied",
+			client: client,
+			setup: func(device *model.OAuth2DeviceCodeSession) {
+				device.Status = model.OAuth2DeviceCodeStatusDenied
+			},
+			err: "The resource owner or authorization server denied the request. The end user denied the device authorization request.",
+		},
+		{
+			name:   "ShouldReturnInvalidGrantInactive",
+			client: client,
+			setup: func(device *model.OAuth2DeviceCodeSession) {
+				device.Status = model.OAuth2DeviceCodeStatusAuthorized
+				device.Active = false
+			},
+			err: "The provided authorization grant (e.g., authorization code, resource owner credentials) or refresh token is invalid, expired, revoked, does not match the redirection URI used in the authorization request, or was issued to another client. The device code has already been used.",
+		},
+		{
+			name:   "ShouldReturnInvalidGrantClientMismatch",
+			client: &Client{ID: "other", GrantTypes: []string{GrantTypeDeviceCode}},
+			err:    "The provided authorization grant (e.g., authorization code, resource owner credentials) or refresh token is invalid, expired, revoked, does not match the redirection URI used in the authorization request, or was issued to another client. The OAuth 2.0 Client ID from this request does not match the one from the device authorization request.",
+		},
+		{
+			name:   "ShouldReturnUnauthorizedClient",
+			client: &Client{ID: "device", GrantTypes: []string{GrantTypeAuthorizationCode}},
+			err:    "The client is not authorized to request a token using this method. The OAuth 2.0 Client is not allowed to use the authorization grant 'urn:ietf:params:oauth:grant-type:device_code'.",
+		},
+		{
+			name:   "ShouldGrantAuthorized",
+			client: client,
+			setup: func(device *model.OAuth2DeviceCodeSession) {
+				device.Status = model.OAuth2DeviceCodeStatusAuthorized
+				device.GrantedScopes = []string{ScopeOpenID, ScopeOffline}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			code, signature, err := config.Strategy.DeviceCode.GenerateDeviceCode(ctx)
+			require.NoError(t, err)
+
+			now := time.Now()
+
+			device := &model.OAuth2DeviceCodeSession{
+				RequestID:       "abc",
+				ClientID:        client.ID,
+				Signature:       signature,
+				Status:          model.OAuth2DeviceCodeStatusPending,
+				RequestedAt:     now,
+				CheckedAt:       now,
+				ExpiresAt:       now.Add(time.Minute * 10),
+				RequestedScopes: []string{ScopeOpenID, ScopeOffline},
+				Active:          true,
+				Session:         []byte("{}"),
+			}
+
+			if tc.setup != nil {
+				tc.setup(device)
+			}
+
+			storage := &testDeviceCodeGrantStorage{sessions: map[string]*model.OAuth2DeviceCodeSession{signature: device}, client: client, used: tc.used}
+
+			handler := &DeviceCodeGrantHandler{
+				CoreStrategy:       config.Strategy.Core,
+				DeviceCodeStrategy: config.Strategy.DeviceCode,
+				Storage:            storage,
+				Config:             config,
+			}
+
+			requester := fosite.NewAccessRequest(NewSession())
+			requester.Client = tc.client
+			requester.GrantTypes = fosite.Arguments{GrantTypeDeviceCode}
+			requester.Form = url.Values{FormParameterDeviceCode: []string{code}}
+
+			err = handler.HandleTokenEndpointRequest(ctx, requester)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.Equal(t, "abc", requester.GetID())
+				assert.Equal(t, fosite.Arguments{ScopeOpenID, ScopeOffline}, requester.GetGrantedScopes())
+				assert.False(t, requester.GetSession().GetExpiresAt(fosite.AccessToken).IsZero())
+				assert.False(t, requester.GetSession().GetExpiresAt(fosite.RefreshToken).IsZero())
+			} else {
+				assert.Equal(t, tc.err, fosite.ErrorToRFC6749Error(err).WithExposeDebug(true).GetDescription())
+			}
+
+			assert.Equal(t, tc.checks, storage.checks)
+			assert.Equal(t, tc.interval, storage.interval)
+		})
+	}
+}
+
+type testDeviceCodeGrantStorage struct {
+	DeviceCodeGrantStorage
+
+	sessions map[string]*model.OAuth2DeviceCodeSession
+	client   fosite.Client
+	checks   int
+	interval time.Duration
+
+	// used simulates the device code session being used by a concurrent request after it was loaded.
+	used bool
+}
+
+func (s *testDeviceCodeGrantStorage) GetDeviceCodeSession(_ context.Context, signature string, session fosite.Session) (device *model.OAuth2DeviceCodeSession, request fosite.Requester, err error) {
+	var ok bool
+
+	if device, ok = s.sessions[signature]; !ok {
+		return nil, nil, fosite.ErrNotFound
+	}
+
+	return device, &fosite.Request{
+		ID:                device.RequestID,
+		RequestedAt:       device.RequestedAt,
+		Client:            s.client,
+		RequestedScope:    fosite.Arguments(device.RequestedScopes),
+		GrantedScope:      fosite.Arguments(device.GrantedScopes),
+		RequestedAudience: fosite.Arguments(device.RequestedAudience),
+		GrantedAudience:   fosite.Arguments(device.GrantedAudience),
+		Form:              url.Values{},
+		Session:           session,
+	}, nil
+}
+
+func (s *testDeviceCodeGrantStorage) CheckDeviceCodeSession(_ context.Context, signature string, checkedAt time.Time, interval time.Duration) (err error) {
+	s.checks++
+	s.interval = interval
+
+	return nil
+}
+
+func (s *testDeviceCodeGrantStorage) InvalidateDeviceCodeSession(_ context.Context, signature string) (err error) {
+	device, ok := s.sessions[signature]
+	if !ok {
+		return errors.New("not found")
+	}
+
+	if s.used || !device.Active {
+		return fosite.ErrInactiveToken
+	}
+
+	device.Active = false
+
+	return nil
+}
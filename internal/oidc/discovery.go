@@ -1,5 +1,31 @@
 package oidc
 
+var (
+	clientAuthMethodsSupported = []string{
+		ClientAuthMethodClientSecretBasic,
+		ClientAuthMethodClientSecretPost,
+		ClientAuthMethodClientSecretJWT,
+		ClientAuthMethodPrivateKeyJWT,
+		ClientAuthMethodNone,
+	}
+
+	clientAuthSigningAlgValuesSupported = []string{
+		SigningAlgorithmHMACWithSHA256,
+		SigningAlgorithmHMACWithSHA384,
+		SigningAlgorithmHMACWithSHA512,
+		SigningAlgorithmRSAWithSHA256,
+		SigningAlgorithmRSAWithSHA384,
+		SigningAlgorithmRSAWithSHA512,
+		SigningAlgorithmRSAPSSWithSHA256,
+		SigningAlgorithmRSAPSSWithSHA384,
+		SigningAlgorithmRSAPSSWithSHA512,
+		SigningAlgorithmECDSAWithP256AndSHA256,
+		SigningAlgorithmECDSAWithP384AndSHA384,
+		SigningAlgorithmECDSAWithP521AndSHA512,
+		SigningAlgorithmEdDSA,
+	}
+)
+
 // NewOpenIDConnectWellKnownConfiguration generates a new OpenIDConnectWellKnownConfiguration.
 func NewOpenIDConnectWellKnownConfiguration(enablePKCEPlainChallenge bool, clients map[string]*Client) (config OpenIDConnectWellKnownConfiguration) {
 	config = OpenIDConnectWellKnownConfiguration{
@@ -30,6 +56,7 @@ func NewOpenIDConnectWellKnownConfiguration(enablePKCEPlainChallenge bool, clien
 				ScopeEmail,
 			},
 			ClaimsSupported: []string{
+				ClaimAuthenticationContextClassReference,
 				ClaimAuthenticationMethodsReference,
 				ClaimAudience,
 				ClaimAuthorizedParty,
@@ -48,12 +75,19 @@ func NewOpenIDConnectWellKnownConfiguration(enablePKCEPlainChallenge bool, clien
 				ClaimGroups,
 				ClaimPreferredUsername,
 				ClaimFullName,
+				ClaimSessionID,
 			},
+			TokenEndpointAuthMethodsSupported:          clientAuthMethodsSupported,
+			TokenEndpointAuthSigningAlgValuesSupported: clientAuthSigningAlgValuesSupported,
 		},
 		OAuth2DiscoveryOptions: OAuth2DiscoveryOptions{
 			CodeChallengeMethodsSupported: []string{
 				PKCEChallengeMethodSHA256,
 			},
+			IntrospectionEndpointAuthMethodsSupported:          clientAuthMethodsSupported,
+			IntrospectionEndpointAuthSigningAlgValuesSupported: clientAuthSigningAlgValuesSupported,
+			RevocationEndpointAuthMethodsSupported:             clientAuthMethodsSupported,
+			RevocationEndpointAuthSigningAlgValuesSupported:    clientAuthSigningAlgValuesSupported,
 		},
 		OpenIDConnectDiscoveryOptions: OpenIDConnectDiscoveryOptions{
 			IDTokenSigningAlgValuesSupported: []string{
@@ -66,7 +100,39 @@ func NewOpenIDConnectWellKnownConfiguration(enablePKCEPlainChallenge bool, clien
 			RequestObjectSigningAlgValuesSupported: []string{
 				SigningAlgorithmNone,
 				SigningAlgorithmRSAWithSHA256,
+				SigningAlgorithmRSAWithSHA384,
+				SigningAlgorithmRSAWithSHA512,
+				SigningAlgorithmRSAPSSWithSHA256,
+				SigningAlgorithmRSAPSSWithSHA384,
+				SigningAlgorithmRSAPSSWithSHA512,
+				SigningAlgorithmECDSAWithP256AndSHA256,
+				SigningAlgorithmECDSAWithP384AndSHA384,
+				SigningAlgorithmECDSAWithP521AndSHA512,
+				SigningAlgorithmEdDSA,
 			},
+			RequestObjectEncryptionEncValuesSupported: []string{
+				ContentEncryptionAlgorithmA128CBCHS256,
+				ContentEncryptionAlgorithmA192CBCHS384,
+				ContentEncryptionAlgorithmA256CBCHS512,
+				ContentEncryptionAlgorithmA128GCM,
+				ContentEncryptionAlgorithmA192GCM,
+				ContentEncryptionAlgorithmA256GCM,
+			},
+			ACRValuesSupported: []string{
+				ACROneFactor,
+				ACRTwoFactor,
+			},
+			RequestParameterSupported:     true,
+			RequestURIParameterSupported:  true,
+			RequireRequestURIRegistration: true,
+		},
+		OpenIDConnectFrontChannelLogoutDiscoveryOptions: OpenIDConnectFrontChannelLogoutDiscoveryOptions{
+			FrontChannelLogoutSupported:        true,
+			FrontChannelLogoutSessionSupported: true,
+		},
+		OpenIDConnectBackChannelLogoutDiscoveryOptions: OpenIDConnectBackChannelLogoutDiscoveryOptions{
+			BackChannelLogoutSupported:        true,
+			BackChannelLogoutSessionSupported: true,
 		},
 	}
 
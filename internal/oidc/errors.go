@@ -2,6 +2,7 @@ package oidc
 
 import (
 	"errors"
+	"net/http"
 
 	"github.com/ory/fosite"
 )
@@ -17,3 +18,31 @@ var (
 	ErrConsentCouldNotLookup       = fosite.ErrServerError.WithHint("Failed to lookup the consent session.")
 	ErrConsentMalformedChallengeID = fosite.ErrServerError.WithHint("Malformed consent session challenge ID.")
 )
+
+// RFC8628 Device Authorization Grant errors.
+var (
+	ErrDeviceAuthorizationPending = &fosite.RFC6749Error{
+		ErrorField:       "authorization_pending",
+		DescriptionField: "The authorization request is still pending as the end user hasn't yet completed the user-interaction steps.",
+		CodeField:        http.StatusBadRequest,
+	}
+	ErrDeviceSlowDown = &fosite.RFC6749Error{
+		ErrorField:       "slow_down",
+		DescriptionField: "The authorization request is still pending and polling should continue, but the interval MUST be increased by 5 seconds for this and all subsequent requests.",
+		CodeField:        http.StatusBadRequest,
+	}
+	ErrDeviceExpiredToken = &fosite.RFC6749Error{
+		ErrorField:       "expired_token",
+		DescriptionField: "The device code has expired, and the device authorization session has concluded.",
+		CodeField:        http.StatusBadRequest,
+	}
+)
+
+// RFC8693 OAuth 2.0 Token Exchange errors.
+var (
+	ErrInvalidTarget = &fosite.RFC6749Error{
+		ErrorField:       "invalid_target",
+		DescriptionField: "The authorization server is unwilling or unable to issue a token for any target service indicated by the 'resource' or 'audience' parameters.",
+		CodeField:        http.StatusBadRequest,
+	}
+)
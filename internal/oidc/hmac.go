@@ -2,6 +2,9 @@ package oidc
 
 import (
 	"context"
+	hmac256 "crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
@@ -9,6 +12,8 @@ import (
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/token/hmac"
 	"github.com/ory/x/errorsx"
+
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // HMACCoreStrategy implements oauth2.CoreStrategy. It's a copy of the oauth2.HMACSHAStrategy.
@@ -109,6 +114,53 @@ func (h *HMACCoreStrategy) ValidateAuthorizeCode(ctx context.Context, r fosite.R
 	return h.Enigma.Validate(ctx, h.trimPrefix(token, "ac"))
 }
 
+// DeviceCodeSignature implements oidc.DeviceCodeStrategy.
+func (h *HMACCoreStrategy) DeviceCodeSignature(ctx context.Context, token string) string {
+	return h.Enigma.Signature(token)
+}
+
+// GenerateDeviceCode implements oidc.DeviceCodeStrategy.
+func (h *HMACCoreStrategy) GenerateDeviceCode(ctx context.Context) (token string, signature string, err error) {
+	token, sig, err := h.Enigma.Generate(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	return h.setPrefix(token, "dc"), sig, nil
+}
+
+// ValidateDeviceCode implements oidc.DeviceCodeStrategy. The expiration of the device code is not checked as the
+// RFC8628 expired_token error must be returned instead of a generic invalid_grant error.
+func (h *HMACCoreStrategy) ValidateDeviceCode(ctx context.Context, _ fosite.Requester, token string) (err error) {
+	return h.Enigma.Validate(ctx, h.trimPrefix(token, "dc"))
+}
+
+// UserCodeSignature implements oidc.DeviceCodeStrategy.
+func (h *HMACCoreStrategy) UserCodeSignature(ctx context.Context, code string) (signature string, err error) {
+	var secret []byte
+
+	if secret, err = h.Enigma.Config.GetGlobalSecret(ctx); err != nil {
+		return "", err
+	}
+
+	mac := hmac256.New(sha256.New, secret)
+
+	_, _ = mac.Write([]byte(NormalizeUserCode(code)))
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// GenerateUserCode implements oidc.DeviceCodeStrategy.
+func (h *HMACCoreStrategy) GenerateUserCode(ctx context.Context) (code string, signature string, err error) {
+	code = utils.RandomString(DeviceUserCodeLength, DeviceUserCodeCharset)
+
+	if signature, err = h.UserCodeSignature(ctx, code); err != nil {
+		return "", "", err
+	}
+
+	return fmt.Sprintf("%s-%s", code[:DeviceUserCodeLength/2], code[DeviceUserCodeLength/2:]), signature, nil
+}
+
 func (h *HMACCoreStrategy) getPrefix(part string) string {
 	if h.prefix == nil {
 		prefix := "ory_%s_"
@@ -3,24 +3,57 @@ package oidc
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ory/fosite/token/jwt"
 	"gopkg.in/square/go-jose.v2"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
-// NewKeyManagerWithConfiguration when provided a schema.OpenIDConnectConfiguration creates a new KeyManager and adds an
-// initial key to the manager.
+// NewKeyManagerWithConfiguration when provided a schema.OpenIDConnectConfiguration creates a new KeyManager and adds the
+// configured keys to the manager.
 func NewKeyManagerWithConfiguration(config *schema.OpenIDConnectConfiguration) (manager *KeyManager, err error) {
 	manager = NewKeyManager()
 
-	if _, err = manager.AddActiveJWK(config.IssuerCertificateChain, config.IssuerPrivateKey); err != nil {
-		return nil, err
+	if len(config.IssuerPrivateKeys) == 0 {
+		if _, err = manager.AddActiveJWK(config.IssuerCertificateChain, config.IssuerPrivateKey); err != nil {
+			return nil, err
+		}
+
+		return manager, nil
+	}
+
+	var jwk *JWK
+
+	for _, key := range config.IssuerPrivateKeys {
+		if jwk, err = NewJWKWithConfiguration(key); err != nil {
+			return nil, err
+		}
+
+		if jwk.use == KeyUseEncryption {
+			manager.addEncryptionJWK(jwk)
+
+			continue
+		}
+
+		manager.addSigningJWK(jwk)
+
+		if manager.jwk == nil && jwk.alg == SigningAlgorithmRSAWithSHA256 {
+			manager.jwk = jwk
+		}
+	}
+
+	if manager.jwk == nil {
+		return nil, fmt.Errorf("failed to initialize the key manager: a key with the '%s' algorithm is required", SigningAlgorithmRSAWithSHA256)
 	}
 
 	return manager, nil
@@ -33,27 +66,31 @@ func NewKeyManager() (manager *KeyManager) {
 	}
 }
 
-// Strategy returns the fosite jwt.JWTStrategy.
+// Strategy returns the fosite jwt.Signer which signs tokens with the active key for the algorithm requested by the
+// headers of the token.
 func (m *KeyManager) Strategy() (strategy jwt.Signer) {
 	if m.jwk == nil {
 		return nil
 	}
 
-	return m.jwk.Strategy()
+	return m
 }
 
-// GetKeySet returns the joseJSONWebKeySet containing the rsa.PublicKey types.
+// GetKeySet returns the joseJSONWebKeySet containing the public keys of all of the keys including those which are not
+// currently active.
 func (m *KeyManager) GetKeySet() (jwks *jose.JSONWebKeySet) {
 	return m.jwks
 }
 
 // GetActiveJWK obtains the currently active jose.JSONWebKey.
 func (m *KeyManager) GetActiveJWK() (jwk *jose.JSONWebKey, err error) {
-	if m.jwks == nil || m.jwk == nil {
+	active := m.GetActiveKey()
+
+	if m.jwks == nil || active == nil {
 		return nil, errors.New("could not obtain the active JWK from an improperly configured key manager")
 	}
 
-	jwks := m.jwks.Key(m.jwk.id)
+	jwks := m.jwks.Key(active.id)
 
 	if len(jwks) == 1 {
 		return &jwks[0], nil
@@ -66,36 +103,292 @@ func (m *KeyManager) GetActiveJWK() (jwk *jose.JSONWebKey, err error) {
 	return nil, errors.New("multiple keys with the same key id")
 }
 
-// GetActiveKeyID returns the key id of the currently active key.
+// GetActiveKeyID returns the key id of the currently active RS256 key.
 func (m *KeyManager) GetActiveKeyID() (keyID string) {
-	if m.jwk == nil {
-		return ""
+	return m.GetKeyID(SigningAlgorithmRSAWithSHA256)
+}
+
+// GetKeyID returns the key id of the currently active key for the provided algorithm.
+func (m *KeyManager) GetKeyID(alg string) (keyID string) {
+	if jwk := m.GetActiveKeyByAlgorithm(alg); jwk != nil {
+		return jwk.id
 	}
 
-	return m.jwk.id
+	return ""
 }
 
-// GetActivePrivateKey returns the rsa.PrivateKey of the currently active key.
+// GetActiveKey returns the *JWK of the currently active RS256 key.
+func (m *KeyManager) GetActiveKey() (jwk *JWK) {
+	return m.GetActiveKeyByAlgorithm(SigningAlgorithmRSAWithSHA256)
+}
+
+// GetActiveKeyByAlgorithm returns the *JWK of the currently active key for the provided algorithm. The active key is
+// the key with the latest not before time which is currently valid. If no key with the algorithm is currently valid nil
+// is returned.
+func (m *KeyManager) GetActiveKeyByAlgorithm(alg string) (jwk *JWK) {
+	now := time.Now()
+
+	for _, key := range m.keys {
+		if key.alg != alg || !key.IsValid(now) {
+			continue
+		}
+
+		if jwk == nil || key.notBefore.After(jwk.notBefore) {
+			jwk = key
+		}
+	}
+
+	return jwk
+}
+
+// GetActivePrivateKey returns the rsa.PrivateKey of the currently active RS256 key.
 func (m *KeyManager) GetActivePrivateKey() (key *rsa.PrivateKey, err error) {
-	if m.jwk == nil {
+	jwk := m.GetActiveKey()
+
+	if jwk == nil {
 		return nil, errors.New("failed to retrieve active private key")
 	}
 
-	return m.jwk.key, nil
+	var ok bool
+
+	if key, ok = jwk.key.(*rsa.PrivateKey); !ok {
+		return nil, errors.New("failed to retrieve active private key: the key is not a rsa private key")
+	}
+
+	return key, nil
+}
+
+// Algorithms returns the unique algorithms of the keys which can be used to sign tokens with the RS256 algorithm
+// always being first.
+func (m *KeyManager) Algorithms() (algs []string) {
+	if m.jwk != nil {
+		algs = append(algs, m.jwk.alg)
+	}
+
+	for _, key := range m.keys {
+		if !utils.IsStringInSlice(key.alg, algs) {
+			algs = append(algs, key.alg)
+		}
+	}
+
+	return algs
+}
+
+// EncryptionAlgorithms returns the unique key management algorithms of the keys with use='enc' which can be used to
+// encrypt Request Objects.
+func (m *KeyManager) EncryptionAlgorithms() (algs []string) {
+	for _, key := range m.encKeys {
+		if !utils.IsStringInSlice(key.alg, algs) {
+			algs = append(algs, key.alg)
+		}
+	}
+
+	return algs
 }
 
-// AddActiveJWK is used to add a cert and key pair.
-func (m *KeyManager) AddActiveJWK(chain schema.X509CertificateChain, key *rsa.PrivateKey) (jwk *JWK, err error) {
-	// TODO: Add a mutex when implementing key rotation to be utilized here and in methods which retrieve the JWK or JWKS.
+// Decrypt decrypts a compact serialized JWE using the key with use='enc' referenced by the kid header, or any such key
+// with the key management algorithm when the header is absent, and returns the payload.
+func (m *KeyManager) Decrypt(token string) (payload []byte, err error) {
+	var jwe *jose.JSONWebEncryption
+
+	if jwe, err = jose.ParseEncrypted(token); err != nil {
+		return nil, fmt.Errorf("failed to parse the token: %w", err)
+	}
+
+	kid, alg := jwe.Header.KeyID, jwe.Header.Algorithm
+
+	for _, key := range m.encKeys {
+		if kid != "" && key.id != kid {
+			continue
+		}
+
+		if key.alg != alg {
+			continue
+		}
+
+		if payload, err = jwe.Decrypt(key.key); err == nil {
+			return payload, nil
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the token: %w", err)
+	}
+
+	return nil, fmt.Errorf("failed to decrypt the token: could not find a key with id '%s' compatible with the algorithm '%s'", kid, alg)
+}
+
+// KeyManagementAlgorithms returns the key management algorithms which can be used to encrypt to a private key with the
+// default algorithm being first, or nil if the key type can't be used for encryption.
+func KeyManagementAlgorithms(key schema.CryptographicPrivateKey) (algs []string) {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		return []string{EncryptionAlgorithmRSAOAEP256, EncryptionAlgorithmRSAOAEP}
+	case *ecdsa.PrivateKey:
+		return []string{EncryptionAlgorithmECDHES, EncryptionAlgorithmECDHESWithA128KW, EncryptionAlgorithmECDHESWithA192KW, EncryptionAlgorithmECDHESWithA256KW}
+	default:
+		return nil
+	}
+}
+
+// AddActiveJWK is used to add a cert and key pair which becomes the default key.
+func (m *KeyManager) AddActiveJWK(chain schema.X509CertificateChain, key schema.CryptographicPrivateKey) (jwk *JWK, err error) {
 	if m.jwk, err = NewJWK(chain, key); err != nil {
 		return nil, err
 	}
 
-	m.jwks.Keys = append(m.jwks.Keys, *m.jwk.JSONWebKey())
+	m.addSigningJWK(m.jwk)
 
 	return m.jwk, nil
 }
 
+// AddJWK is used to add a cert and key pair which is published in the key set without it becoming the active key.
+func (m *KeyManager) AddJWK(chain schema.X509CertificateChain, key schema.CryptographicPrivateKey) (jwk *JWK, err error) {
+	if jwk, err = NewJWK(chain, key); err != nil {
+		return nil, err
+	}
+
+	m.jwks.Keys = append(m.jwks.Keys, *jwk.JSONWebKey())
+
+	return jwk, nil
+}
+
+func (m *KeyManager) addSigningJWK(jwk *JWK) {
+	m.keys = append(m.keys, jwk)
+	m.jwks.Keys = append(m.jwks.Keys, *jwk.JSONWebKey())
+}
+
+func (m *KeyManager) addEncryptionJWK(jwk *JWK) {
+	m.encKeys = append(m.encKeys, jwk)
+	m.jwks.Keys = append(m.jwks.Keys, *jwk.JSONWebKey())
+}
+
+func (m *KeyManager) getKeyByID(kid string) (jwk *JWK) {
+	for _, key := range m.keys {
+		if key.id == kid {
+			return key
+		}
+	}
+
+	return nil
+}
+
+// Generate implements jwt.Signer and signs the token with the active key for the algorithm requested by the alg
+// header, or the algorithm of the key referenced by the kid header, falling back to the active RS256 key. The kid
+// header is always set to the key id of the key used to sign the token.
+func (m *KeyManager) Generate(ctx context.Context, claims jwt.MapClaims, header jwt.Mapper) (token string, sig string, err error) {
+	if header == nil {
+		header = &jwt.Headers{}
+	}
+
+	var jwk *JWK
+
+	switch alg, _ := header.Get(JWTHeaderAlgorithm).(string); {
+	case alg != "":
+		jwk = m.GetActiveKeyByAlgorithm(alg)
+	default:
+		if kid, _ := header.Get(JWTHeaderKeyIdentifier).(string); kid != "" {
+			if jwk = m.getKeyByID(kid); jwk != nil {
+				jwk = m.GetActiveKeyByAlgorithm(jwk.alg)
+			}
+		}
+
+		if jwk == nil {
+			jwk = m.GetActiveKey()
+		}
+	}
+
+	if jwk == nil {
+		return "", "", errors.New("failed to sign the token: could not find a key for the requested algorithm")
+	}
+
+	header.Add(JWTHeaderKeyIdentifier, jwk.id)
+
+	return jwk.Strategy().Generate(ctx, claims, header)
+}
+
+// Validate implements jwt.Signer and validates a token and returns its signature or an error if the token is not valid.
+func (m *KeyManager) Validate(ctx context.Context, token string) (sig string, err error) {
+	if _, err = m.Decode(ctx, token); err != nil {
+		return "", err
+	}
+
+	return m.GetSignature(ctx, token)
+}
+
+// Decode implements jwt.Signer and decodes a token verifying the signature using the key referenced by the kid header.
+func (m *KeyManager) Decode(ctx context.Context, token string) (t *jwt.Token, err error) {
+	return jwt.ParseWithClaims(token, jwt.MapClaims{}, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header[JWTHeaderKeyIdentifier].(string)
+
+		if kid == "" {
+			kid = m.GetActiveKeyID()
+		}
+
+		keys := m.jwks.Key(kid)
+
+		if len(keys) != 1 {
+			return nil, fmt.Errorf("could not find the key with id '%s'", kid)
+		}
+
+		return &keys[0], nil
+	})
+}
+
+// GetSignature implements jwt.Signer and returns the signature of a token.
+func (m *KeyManager) GetSignature(ctx context.Context, token string) (sig string, err error) {
+	return (&jwt.DefaultSigner{}).GetSignature(ctx, token)
+}
+
+// Hash implements jwt.Signer and returns the hash of the input using the hash function of the ID Token signing
+// algorithm of the client in the context, or SHA256 if the context does not include one.
+func (m *KeyManager) Hash(ctx context.Context, in []byte) (sum []byte, err error) {
+	hash := getSigningAlgorithmHash(getIDTokenSigningAlgorithm(ctx)).New()
+
+	if _, err = hash.Write(in); err != nil {
+		return nil, err
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// GetSigningMethodLength implements jwt.Signer and returns the length of the hash used by the Hash method.
+func (m *KeyManager) GetSigningMethodLength(ctx context.Context) (size int) {
+	return getSigningAlgorithmHash(getIDTokenSigningAlgorithm(ctx)).Size()
+}
+
+// Verify validates the signature of a compact serialized JWS against the key set using the key id in the header and
+// returns the payload.
+func (m *KeyManager) Verify(token string) (payload []byte, err error) {
+	var jws *jose.JSONWebSignature
+
+	if jws, err = jose.ParseSigned(token); err != nil {
+		return nil, fmt.Errorf("failed to parse the token: %w", err)
+	}
+
+	if len(jws.Signatures) != 1 {
+		return nil, errors.New("failed to verify the token: the token must have exactly one signature")
+	}
+
+	kid := jws.Signatures[0].Header.KeyID
+
+	if kid == "" {
+		kid = m.GetActiveKeyID()
+	}
+
+	keys := m.jwks.Key(kid)
+
+	if len(keys) != 1 {
+		return nil, fmt.Errorf("failed to verify the token: could not find the key with id '%s'", kid)
+	}
+
+	if payload, err = jws.Verify(keys[0].Key); err != nil {
+		return nil, fmt.Errorf("failed to verify the token: %w", err)
+	}
+
+	return payload, nil
+}
+
 // JWTStrategy is a decorator struct for the fosite jwt.JWTStrategy.
 type JWTStrategy struct {
 	jwt.Signer
@@ -113,21 +406,54 @@ func (s *JWTStrategy) GetPublicKeyID(_ context.Context) (string, error) {
 	return s.id, nil
 }
 
-// NewJWK creates a new JWK.
-func NewJWK(chain schema.X509CertificateChain, key *rsa.PrivateKey) (j *JWK, err error) {
-	if key == nil {
+// NewJWK creates a new JWK using the default algorithm for the key.
+func NewJWK(chain schema.X509CertificateChain, key schema.CryptographicPrivateKey) (j *JWK, err error) {
+	return NewJWKWithConfiguration(schema.OpenIDConnectIssuerPrivateKey{Key: key, CertificateChain: chain})
+}
+
+// NewJWKWithConfiguration creates a new JWK from a schema.OpenIDConnectIssuerPrivateKey.
+func NewJWKWithConfiguration(config schema.OpenIDConnectIssuerPrivateKey) (j *JWK, err error) {
+	if isNilPrivateKey(config.Key) {
 		return nil, fmt.Errorf("JWK is not properly initialized: missing key")
 	}
 
 	j = &JWK{
-		key:   key,
-		chain: chain,
+		id:        config.KeyID,
+		alg:       config.Algorithm,
+		use:       config.Use,
+		key:       config.Key,
+		chain:     config.CertificateChain,
+		notBefore: config.NotBefore,
+		notAfter:  config.NotAfter,
+	}
+
+	if j.use == "" {
+		j.use = KeyUseSignature
+	}
+
+	if j.alg == "" {
+		switch j.use {
+		case KeyUseEncryption:
+			if algs := KeyManagementAlgorithms(config.Key); len(algs) != 0 {
+				j.alg = algs[0]
+			}
+		default:
+			j.alg = DefaultSigningAlgorithm(config.Key)
+		}
+
+		if j.alg == "" {
+			return nil, fmt.Errorf("JWK is not properly initialized: unsupported key type %T", config.Key)
+		}
+	}
+
+	if j.id != "" {
+		return j, nil
 	}
 
 	jwk := &jose.JSONWebKey{
-		Algorithm: SigningAlgorithmRSAWithSHA256,
-		Use:       "sig",
-		Key:       &key.PublicKey,
+		Algorithm: j.alg,
+		Use:       j.use,
+		Key:       config.Key.Public(),
 	}
 
 	var thumbprint []byte
@@ -142,18 +468,106 @@ func NewJWK(chain schema.X509CertificateChain, key *rsa.PrivateKey) (j *JWK, err
 		j.id = j.id[:6]
 	}
 
-	if len(j.id) >= 7 {
-		j.id = j.id[:6]
+	return j, nil
+}
+
+// DefaultSigningAlgorithm returns the default signing algorithm for a private key, or an empty string if the key type
+// is not supported.
+func DefaultSigningAlgorithm(key schema.CryptographicPrivateKey) (alg string) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return SigningAlgorithmRSAWithSHA256
+	case *ecdsa.PrivateKey:
+		switch k.Curve {
+		case elliptic.P256():
+			return SigningAlgorithmECDSAWithP256AndSHA256
+		case elliptic.P384():
+			return SigningAlgorithmECDSAWithP384AndSHA384
+		case elliptic.P521():
+			return SigningAlgorithmECDSAWithP521AndSHA512
+		}
+	case ed25519.PrivateKey:
+		return SigningAlgorithmEdDSA
 	}
 
-	return j, nil
+	return ""
+}
+
+func isNilPrivateKey(key schema.CryptographicPrivateKey) bool {
+	if key == nil {
+		return true
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k == nil
+	case *ecdsa.PrivateKey:
+		return k == nil
+	case ed25519.PrivateKey:
+		return len(k) == 0
+	}
+
+	return false
+}
+
+func getIDTokenSigningAlgorithm(ctx context.Context) (alg string) {
+	if ctx == nil {
+		return SigningAlgorithmRSAWithSHA256
+	}
+
+	if alg, _ = ctx.Value(IDTokenSigningAlgorithmContextKey).(string); alg == "" {
+		return SigningAlgorithmRSAWithSHA256
+	}
+
+	return alg
+}
+
+// getSigningAlgorithmHash returns the hash function used by a signing algorithm. The EdDSA algorithm uses SHA512 as
+// the Ed25519 signature scheme does.
+func getSigningAlgorithmHash(alg string) (hash crypto.Hash) {
+	switch alg {
+	case SigningAlgorithmRSAWithSHA384, SigningAlgorithmRSAPSSWithSHA384, SigningAlgorithmECDSAWithP384AndSHA384:
+		return crypto.SHA384
+	case SigningAlgorithmRSAWithSHA512, SigningAlgorithmRSAPSSWithSHA512, SigningAlgorithmECDSAWithP521AndSHA512, SigningAlgorithmEdDSA:
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
 }
 
 // JWK is a utility wrapper for JSON Web Key's.
 type JWK struct {
 	id    string
-	key   *rsa.PrivateKey
+	alg   string
+	use   string
+	key   schema.CryptographicPrivateKey
 	chain schema.X509CertificateChain
+
+	notBefore time.Time
+	notAfter  time.Time
+}
+
+// KeyID returns the key id of this JWK.
+func (j *JWK) KeyID() (id string) {
+	return j.id
+}
+
+// Algorithm returns the signing or key management algorithm of this JWK.
+func (j *JWK) Algorithm() (alg string) {
+	return j.alg
+}
+
+// IsValid returns true if this JWK may be used to sign tokens at the provided time.
+func (j *JWK) IsValid(now time.Time) bool {
+	if !j.notBefore.IsZero() && now.Before(j.notBefore) {
+		return false
+	}
+
+	if !j.notAfter.IsZero() && !now.Before(j.notAfter) {
+		return false
+	}
+
+	return true
 }
 
 // Strategy returns the relevant jwt.JWTStrategy for this JWT.
@@ -161,17 +575,18 @@ func (j *JWK) Strategy() (strategy jwt.Signer) {
 	return &JWTStrategy{id: j.id, Signer: &jwt.DefaultSigner{GetPrivateKey: j.GetPrivateKey}}
 }
 
+// GetPrivateKey returns the private key of this JWK as a *jose.JSONWebKey which includes the signing algorithm.
 func (j *JWK) GetPrivateKey(ctx context.Context) (key any, err error) {
-	return j.key, nil
+	return &jose.JSONWebKey{Key: j.key, KeyID: j.id, Algorithm: j.alg, Use: KeyUseSignature}, nil
 }
 
 // JSONWebKey returns the relevant *jose.JSONWebKey for this JWT.
 func (j *JWK) JSONWebKey() (jwk *jose.JSONWebKey) {
 	jwk = &jose.JSONWebKey{
-		Key:          &j.key.PublicKey,
+		Key:          j.key.Public(),
 		KeyID:        j.id,
-		Algorithm:    "RS256",
-		Use:          "sig",
+		Algorithm:    j.alg,
+		Use:          j.use,
 		Certificates: j.chain.Certificates(),
 	}
 
@@ -1,13 +1,22 @@
 package oidc
 
 import (
+	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ory/fosite/token/jwt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
@@ -46,3 +55,252 @@ func TestKeyManager_AddActiveJWK(t *testing.T) {
 	assert.NotNil(t, keySet)
 	assert.Equal(t, kid, manager.GetActiveKeyID())
 }
+
+func TestKeyManager_AddJWK(t *testing.T) {
+	manager := NewKeyManager()
+
+	active, err := manager.AddActiveJWK(schema.X509CertificateChain{}, mustParseRSAPrivateKey(exampleIssuerPrivateKey))
+	require.NoError(t, err)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	j, err := manager.AddJWK(schema.X509CertificateChain{}, key)
+	require.NoError(t, err)
+	require.NotNil(t, j)
+
+	assert.NotEqual(t, active.KeyID(), j.KeyID())
+	assert.Equal(t, active, manager.GetActiveKey())
+	assert.Equal(t, active.KeyID(), manager.GetActiveKeyID())
+	assert.Len(t, manager.jwks.Keys, 2)
+	assert.Len(t, manager.jwks.Key(j.KeyID()), 1)
+
+	_, err = manager.AddJWK(schema.X509CertificateChain{}, nil)
+	assert.EqualError(t, err, "JWK is not properly initialized: missing key")
+	assert.Len(t, manager.jwks.Keys, 2)
+}
+
+func TestKeyManager_Decrypt(t *testing.T) {
+	manager := NewKeyManager()
+
+	active, err := manager.AddActiveJWK(schema.X509CertificateChain{}, mustParseRSAPrivateKey(exampleIssuerPrivateKey))
+	require.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	assert.Len(t, manager.EncryptionAlgorithms(), 0)
+
+	enc, err := NewJWKWithConfiguration(schema.OpenIDConnectIssuerPrivateKey{Key: rsaKey, Use: KeyUseEncryption})
+	require.NoError(t, err)
+
+	manager.addEncryptionJWK(enc)
+
+	ec, err := NewJWKWithConfiguration(schema.OpenIDConnectIssuerPrivateKey{Key: ecKey, Use: KeyUseEncryption, Algorithm: EncryptionAlgorithmECDHESWithA128KW})
+	require.NoError(t, err)
+
+	manager.addEncryptionJWK(ec)
+
+	assert.Equal(t, []string{EncryptionAlgorithmRSAOAEP256, EncryptionAlgorithmECDHESWithA128KW}, manager.EncryptionAlgorithms())
+	assert.Equal(t, KeyUseEncryption, manager.jwks.Key(enc.KeyID())[0].Use)
+	assert.Equal(t, KeyUseSignature, manager.jwks.Key(active.KeyID())[0].Use)
+
+	encrypt := func(alg jose.KeyAlgorithm, key any, kid string) string {
+		encrypter, err := jose.NewEncrypter(jose.A256GCM, jose.Recipient{Algorithm: alg, Key: key, KeyID: kid}, nil)
+		require.NoError(t, err)
+
+		object, err := encrypter.Encrypt([]byte("payload"))
+		require.NoError(t, err)
+
+		token, err := object.CompactSerialize()
+		require.NoError(t, err)
+
+		return token
+	}
+
+	payload, err := manager.Decrypt(encrypt(jose.RSA_OAEP_256, rsaKey.Public(), enc.KeyID()))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), payload)
+
+	payload, err = manager.Decrypt(encrypt(jose.ECDH_ES_A128KW, ecKey.Public(), ""))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), payload)
+
+	_, err = manager.Decrypt(encrypt(jose.RSA_OAEP, rsaKey.Public(), enc.KeyID()))
+	assert.EqualError(t, err, fmt.Sprintf("failed to decrypt the token: could not find a key with id '%s' compatible with the algorithm 'RSA-OAEP'", enc.KeyID()))
+
+	_, err = manager.Decrypt(encrypt(jose.RSA_OAEP_256, active.key.Public(), active.KeyID()))
+	assert.EqualError(t, err, fmt.Sprintf("failed to decrypt the token: could not find a key with id '%s' compatible with the algorithm 'RSA-OAEP-256'", active.KeyID()))
+
+	_, err = manager.Decrypt("abc")
+	assert.EqualError(t, err, "failed to parse the token: square/go-jose: compact JWE format must have five parts")
+}
+
+func TestNewKeyManagerWithConfiguration(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaKey := mustParseRSAPrivateKey(exampleIssuerPrivateKey)
+
+	encKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	manager, err := NewKeyManagerWithConfiguration(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKey{
+			{KeyID: "ec", Key: ecKey},
+			{KeyID: "rsa", Key: rsaKey},
+			{KeyID: "ps", Algorithm: SigningAlgorithmRSAPSSWithSHA512, Key: rsaKey},
+			{KeyID: "ed", Key: edKey},
+			{KeyID: "enc", Use: KeyUseEncryption, Key: encKey},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{SigningAlgorithmRSAWithSHA256, SigningAlgorithmECDSAWithP384AndSHA384, SigningAlgorithmRSAPSSWithSHA512, SigningAlgorithmEdDSA}, manager.Algorithms())
+	assert.Equal(t, "rsa", manager.GetActiveKeyID())
+	assert.Equal(t, "ec", manager.GetKeyID(SigningAlgorithmECDSAWithP384AndSHA384))
+	assert.Equal(t, "ps", manager.GetKeyID(SigningAlgorithmRSAPSSWithSHA512))
+	assert.Equal(t, "ed", manager.GetKeyID(SigningAlgorithmEdDSA))
+	assert.Equal(t, "", manager.GetKeyID(SigningAlgorithmECDSAWithP256AndSHA256))
+	assert.Equal(t, "", manager.GetKeyID(EncryptionAlgorithmRSAOAEP256))
+	assert.Equal(t, []string{EncryptionAlgorithmRSAOAEP256}, manager.EncryptionAlgorithms())
+	assert.Len(t, manager.GetKeySet().Keys, 5)
+
+	for _, alg := range manager.Algorithms() {
+		t.Run(alg, func(t *testing.T) {
+			token, _, err := manager.Strategy().Generate(context.Background(), jwt.MapClaims{ClaimSubject: "abc"}, &jwt.Headers{Extra: map[string]any{JWTHeaderAlgorithm: alg}})
+			require.NoError(t, err)
+
+			decoded, err := manager.Strategy().Decode(context.Background(), token)
+			require.NoError(t, err)
+
+			assert.Equal(t, alg, decoded.Header[JWTHeaderAlgorithm])
+			assert.Equal(t, manager.GetKeyID(alg), decoded.Header[JWTHeaderKeyIdentifier])
+			assert.Equal(t, "abc", decoded.Claims[ClaimSubject])
+
+			_, err = manager.Verify(token)
+			assert.NoError(t, err)
+		})
+	}
+
+	_, _, err = manager.Strategy().Generate(context.Background(), jwt.MapClaims{}, &jwt.Headers{Extra: map[string]any{JWTHeaderAlgorithm: SigningAlgorithmECDSAWithP256AndSHA256}})
+	assert.EqualError(t, err, "failed to sign the token: could not find a key for the requested algorithm")
+
+	_, err = NewKeyManagerWithConfiguration(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKey{
+			{Key: ecKey},
+		},
+	})
+	assert.EqualError(t, err, "failed to initialize the key manager: a key with the 'RS256' algorithm is required")
+}
+
+func TestKeyManager_ShouldRotateKeys(t *testing.T) {
+	now := time.Now()
+
+	previous, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	next, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	manager, err := NewKeyManagerWithConfiguration(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKey{
+			{KeyID: "expired", Key: previous, NotAfter: now.Add(-time.Minute)},
+			{KeyID: "current", Key: mustParseRSAPrivateKey(exampleIssuerPrivateKey), NotBefore: now.Add(-time.Hour)},
+			{KeyID: "next", Key: next, NotBefore: now.Add(time.Hour)},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "current", manager.GetActiveKeyID())
+	assert.Len(t, manager.GetKeySet().Keys, 3)
+
+	// A token issued by the expired key is still able to be verified as the key is still published.
+	token, _, err := manager.getKeyByID("expired").Strategy().Generate(context.Background(), jwt.MapClaims{}, &jwt.Headers{Extra: map[string]any{JWTHeaderKeyIdentifier: "expired"}})
+	require.NoError(t, err)
+
+	_, err = manager.Strategy().Decode(context.Background(), token)
+	assert.NoError(t, err)
+
+	// A token requested with the kid of an inactive key is signed by the active key for the same algorithm.
+	token, _, err = manager.Strategy().Generate(context.Background(), jwt.MapClaims{}, &jwt.Headers{Extra: map[string]any{JWTHeaderKeyIdentifier: "expired"}})
+	require.NoError(t, err)
+
+	jws, err := jose.ParseSigned(token)
+	require.NoError(t, err)
+
+	assert.Equal(t, "current", jws.Signatures[0].Header.KeyID)
+
+	manager.keys[2].notBefore = now.Add(-time.Minute)
+
+	assert.Equal(t, "next", manager.GetActiveKeyID())
+
+	// When no key is valid no key is used to sign tokens with the algorithm.
+	manager.keys[1].notAfter, manager.keys[2].notAfter = now.Add(-time.Second), now.Add(-time.Second)
+
+	assert.Equal(t, "", manager.GetActiveKeyID())
+	assert.Nil(t, manager.GetActiveKey())
+
+	_, _, err = manager.Strategy().Generate(context.Background(), jwt.MapClaims{}, &jwt.Headers{Extra: map[string]any{JWTHeaderAlgorithm: SigningAlgorithmRSAWithSHA256}})
+	assert.EqualError(t, err, "failed to sign the token: could not find a key for the requested algorithm")
+}
+
+func TestKeyManager_Hash(t *testing.T) {
+	manager := NewKeyManager()
+
+	testCases := []struct {
+		name     string
+		have     string
+		expected crypto.Hash
+	}{
+		{"ShouldUseSHA256ByDefault", "", crypto.SHA256},
+		{"ShouldUseSHA256ForRS256", SigningAlgorithmRSAWithSHA256, crypto.SHA256},
+		{"ShouldUseSHA384ForRS384", SigningAlgorithmRSAWithSHA384, crypto.SHA384},
+		{"ShouldUseSHA512ForRS512", SigningAlgorithmRSAWithSHA512, crypto.SHA512},
+		{"ShouldUseSHA384ForPS384", SigningAlgorithmRSAPSSWithSHA384, crypto.SHA384},
+		{"ShouldUseSHA512ForPS512", SigningAlgorithmRSAPSSWithSHA512, crypto.SHA512},
+		{"ShouldUseSHA256ForES256", SigningAlgorithmECDSAWithP256AndSHA256, crypto.SHA256},
+		{"ShouldUseSHA384ForES384", SigningAlgorithmECDSAWithP384AndSHA384, crypto.SHA384},
+		{"ShouldUseSHA512ForES512", SigningAlgorithmECDSAWithP521AndSHA512, crypto.SHA512},
+		{"ShouldUseSHA512ForEdDSA", SigningAlgorithmEdDSA, crypto.SHA512},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), IDTokenSigningAlgorithmContextKey, tc.have)
+
+			hash := tc.expected.New()
+			hash.Write([]byte("abc"))
+
+			sum, err := manager.Hash(ctx, []byte("abc"))
+			require.NoError(t, err)
+
+			assert.Equal(t, hash.Sum(nil), sum)
+			assert.Equal(t, tc.expected.Size(), manager.GetSigningMethodLength(ctx))
+		})
+	}
+}
+
+func TestNewJWK(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	require.NoError(t, err)
+
+	_, err = NewJWK(schema.X509CertificateChain{}, ecKey)
+	assert.EqualError(t, err, "JWK is not properly initialized: unsupported key type *ecdsa.PrivateKey")
+
+	var key *rsa.PrivateKey
+
+	_, err = NewJWK(schema.X509CertificateChain{}, key)
+	assert.EqualError(t, err, "JWK is not properly initialized: missing key")
+
+	var edKey ed25519.PrivateKey
+
+	_, err = NewJWK(schema.X509CertificateChain{}, edKey)
+	assert.EqualError(t, err, "JWK is not properly initialized: missing key")
+}
@@ -0,0 +1,267 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/ory/fosite/token/jwt"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// IsValidPostLogoutRedirectURI returns true if the provided uri exactly matches one of the registered
+// post_logout_redirect_uris of this client.
+func (c *Client) IsValidPostLogoutRedirectURI(uri string) bool {
+	if uri == "" {
+		return false
+	}
+
+	for _, u := range c.PostLogoutRedirectURIs {
+		if u == uri {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetFrontChannelLogoutURI returns the front-channel logout uri for this client including the iss and sid query
+// parameters when the client requires them. Returns nil if the client has no front-channel logout uri.
+func (c *Client) GetFrontChannelLogoutURI(issuer, sid string) (uri *url.URL) {
+	if c.FrontChannelLogoutURI == "" {
+		return nil
+	}
+
+	var err error
+
+	if uri, err = url.Parse(c.FrontChannelLogoutURI); err != nil {
+		return nil
+	}
+
+	if c.FrontChannelLogoutSessionRequired {
+		query := uri.Query()
+
+		query.Set(ClaimIssuer, issuer)
+		query.Set(ClaimSessionID, sid)
+
+		uri.RawQuery = query.Encode()
+	}
+
+	return uri
+}
+
+// DecodeIDTokenHint verifies the signature and issuer of an ID Token previously issued by this provider and returns
+// the claims relevant to logout. Tokens which are not ID Tokens such as JWT Access Tokens and Logout Tokens are
+// rejected, as are tokens which don't include the client with the provided id in the audience. If the client id is
+// empty the client identified by the token must be in the audience. The expiration of the token is intentionally not
+// validated as per the OpenID Connect RP-Initiated Logout 1.0 specification.
+func (p *OpenIDConnectProvider) DecodeIDTokenHint(issuer, clientID, token string) (hint *IDTokenHint, err error) {
+	var jws *jose.JSONWebSignature
+
+	if jws, err = jose.ParseSigned(token); err != nil {
+		return nil, fmt.Errorf("failed to parse the token: %w", err)
+	}
+
+	if len(jws.Signatures) == 1 {
+		switch typ, _ := jws.Signatures[0].Header.ExtraHeaders[JWTHeaderType].(string); typ {
+		case "", JWTTypeJWT:
+			break
+		default:
+			return nil, fmt.Errorf("the token is not an ID Token: the token has the type '%s'", typ)
+		}
+	}
+
+	var payload []byte
+
+	if payload, err = p.KeyManager.Verify(token); err != nil {
+		return nil, err
+	}
+
+	claims := map[string]any{}
+
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to decode the token claims: %w", err)
+	}
+
+	if _, ok := claims[ClaimEvents]; ok {
+		return nil, errors.New("the token is not an ID Token: the token has the events claim")
+	}
+
+	hint = &IDTokenHint{}
+
+	hint.Issuer, _ = claims[ClaimIssuer].(string)
+	hint.Subject, _ = claims[ClaimSubject].(string)
+	hint.AuthorizedParty, _ = claims[ClaimAuthorizedParty].(string)
+	hint.SessionID, _ = claims[ClaimSessionID].(string)
+
+	switch aud := claims[ClaimAudience].(type) {
+	case string:
+		hint.Audience = []string{aud}
+	case []any:
+		for _, value := range aud {
+			if v, ok := value.(string); ok {
+				hint.Audience = append(hint.Audience, v)
+			}
+		}
+	}
+
+	if hint.Issuer != issuer {
+		return nil, fmt.Errorf("the token issuer '%s' does not match the expected issuer '%s'", hint.Issuer, issuer)
+	}
+
+	if hint.Subject == "" || len(hint.Audience) == 0 {
+		return nil, errors.New("the token is not an ID Token: the token does not have the sub and aud claims")
+	}
+
+	if clientID == "" {
+		clientID = hint.ClientID()
+	}
+
+	if !utils.IsStringInSlice(clientID, hint.Audience) {
+		return nil, fmt.Errorf("the client with id '%s' is not an audience of the token", clientID)
+	}
+
+	return hint, nil
+}
+
+// NewBackChannelLogoutToken generates a signed Logout Token for the provided client, subject, and session id.
+func (p *OpenIDConnectProvider) NewBackChannelLogoutToken(ctx context.Context, issuer, clientID, subject, sid string) (token string, err error) {
+	now := time.Now()
+
+	claims := jwt.MapClaims{
+		ClaimIssuer:         issuer,
+		ClaimAudience:       []string{clientID},
+		ClaimIssuedAt:       now.Unix(),
+		ClaimExpirationTime: now.Add(lifespanLogoutTokenDefault).Unix(),
+		ClaimJWTID:          uuid.New().String(),
+		ClaimSubject:        subject,
+		ClaimSessionID:      sid,
+		ClaimEvents: map[string]any{
+			EventBackChannelLogout: map[string]any{},
+		},
+	}
+
+	headers := ExplicitTypeHeaders{
+		JWTHeaderKeyIdentifier: p.KeyManager.GetActiveKeyID(),
+		JWTHeaderType:          JWTTypeLogout,
+	}
+
+	if token, _, err = p.KeyManager.Strategy().Generate(ctx, claims, headers); err != nil {
+		return "", fmt.Errorf("failed to sign the logout token: %w", err)
+	}
+
+	return token, nil
+}
+
+// SendBackChannelLogout generates a Logout Token and delivers it to the back-channel logout uri of the client. The
+// delivery is abandoned when the context is done.
+func (p *OpenIDConnectProvider) SendBackChannelLogout(ctx context.Context, issuer string, client *Client, subject, sid string) (err error) {
+	if client.BackChannelLogoutURI == "" {
+		return nil
+	}
+
+	var token string
+
+	if token, err = p.NewBackChannelLogoutToken(ctx, issuer, client.ID, subject, sid); err != nil {
+		return err
+	}
+
+	var (
+		req *retryablehttp.Request
+		res *http.Response
+	)
+
+	form := url.Values{FormParameterLogoutToken: []string{token}}
+
+	if req, err = retryablehttp.NewRequestWithContext(ctx, http.MethodPost, client.BackChannelLogoutURI, strings.NewReader(form.Encode())); err != nil {
+		return fmt.Errorf("failed to deliver the logout token to client '%s': %w", client.ID, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	if res, err = p.Config.GetHTTPClient(ctx).Do(req); err != nil {
+		return fmt.Errorf("failed to deliver the logout token to client '%s': %w", client.ID, err)
+	}
+
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	default:
+		return fmt.Errorf("failed to deliver the logout token to client '%s': the back-channel logout uri responded with status code %d", client.ID, res.StatusCode)
+	}
+}
+
+// DispatchBackChannelLogout delivers a Logout Token to each of the targets in the background and calls done with the
+// result of each delivery. The deliveries of all logouts share a bounded number of workers, so a single logout can't
+// spawn a delivery and its retries for every client at once.
+func (p *OpenIDConnectProvider) DispatchBackChannelLogout(issuer, sid string, targets []BackChannelLogoutTarget, done func(target BackChannelLogoutTarget, err error)) {
+	if len(targets) == 0 {
+		return
+	}
+
+	go func() {
+		for _, target := range targets {
+			p.backChannelLogoutWorkers <- struct{}{}
+
+			go func(target BackChannelLogoutTarget) {
+				defer func() {
+					<-p.backChannelLogoutWorkers
+				}()
+
+				ctx, cancel := context.WithTimeout(context.Background(), timeoutBackChannelLogoutDelivery)
+				defer cancel()
+
+				done(target, p.SendBackChannelLogout(ctx, issuer, target.Client, target.Subject, sid))
+			}(target)
+		}
+	}()
+}
+
+// WriteLogoutResponse renders the front-channel logout page which loads each of the front-channel logout uris and
+// subsequently redirects the user agent to the redirect uri. Returns false if the response could not be written.
+func (p *OpenIDConnectProvider) WriteLogoutResponse(ctx context.Context, redirectURI string, frontChannelLogoutURIs []*url.URL) bool {
+	ctxVal := ctx.Value(WriteLogoutResponseFnContextKey)
+	if ctxVal == nil {
+		return false
+	}
+
+	writeFn, ok := ctxVal.(func(templateData map[string]any, frameSources []string))
+	if !ok {
+		return false
+	}
+
+	var (
+		uris    []string
+		sources []string
+	)
+
+	for _, uri := range frontChannelLogoutURIs {
+		uris = append(uris, uri.String())
+
+		source := fmt.Sprintf("%s://%s", uri.Scheme, uri.Host)
+
+		if !utils.IsStringInSlice(source, sources) {
+			sources = append(sources, source)
+		}
+	}
+
+	data := map[string]any{
+		"RedirURL":               redirectURI,
+		"FrontChannelLogoutURIs": uris,
+	}
+
+	writeFn(data, sources)
+
+	return true
+}
@@ -0,0 +1,320 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite/token/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newLogoutTestProvider(t *testing.T, clients ...schema.OpenIDConnectClientConfiguration) *OpenIDConnectProvider {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerCertificateChain: schema.X509CertificateChain{},
+		IssuerPrivateKey:       mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		HMACSecret:             "asbdhaaskmdlkamdklasmdlkams",
+		Clients:                clients,
+	}, nil)
+
+	require.NoError(t, err)
+
+	return provider
+}
+
+func TestClient_IsValidPostLogoutRedirectURI(t *testing.T) {
+	client := &Client{
+		PostLogoutRedirectURIs: []string{"https://app.example.com/logged-out"},
+	}
+
+	assert.True(t, client.IsValidPostLogoutRedirectURI("https://app.example.com/logged-out"))
+	assert.False(t, client.IsValidPostLogoutRedirectURI("https://app.example.com/logged-out/"))
+	assert.False(t, client.IsValidPostLogoutRedirectURI("https://app.example.com/logged-out?a=b"))
+	assert.False(t, client.IsValidPostLogoutRedirectURI(""))
+}
+
+func TestClient_GetFrontChannelLogoutURI(t *testing.T) {
+	client := &Client{}
+
+	assert.Nil(t, client.GetFrontChannelLogoutURI("https://auth.example.com", "abc"))
+
+	client.FrontChannelLogoutURI = "https://app.example.com/logout?x=1"
+
+	assert.Equal(t, "https://app.example.com/logout?x=1", client.GetFrontChannelLogoutURI("https://auth.example.com", "abc").String())
+
+	client.FrontChannelLogoutSessionRequired = true
+
+	assert.Equal(t, "https://app.example.com/logout?iss=https%3A%2F%2Fauth.example.com&sid=abc&x=1", client.GetFrontChannelLogoutURI("https://auth.example.com", "abc").String())
+}
+
+func TestOpenIDConnectProvider_DecodeIDTokenHint(t *testing.T) {
+	provider := newLogoutTestProvider(t)
+
+	headers := &jwt.Headers{Extra: map[string]any{JWTHeaderKeyIdentifier: provider.KeyManager.GetActiveKeyID()}}
+
+	token, _, err := provider.KeyManager.Strategy().Generate(context.Background(), jwt.MapClaims{
+		ClaimIssuer:          "https://auth.example.com",
+		ClaimSubject:         "subject-a",
+		ClaimAudience:        []string{"client-a", "client-b"},
+		ClaimAuthorizedParty: "client-b",
+		ClaimSessionID:       "sid-a",
+		ClaimExpirationTime:  1,
+	}, headers)
+	require.NoError(t, err)
+
+	hint, err := provider.DecodeIDTokenHint("https://auth.example.com", "", token)
+	require.NoError(t, err)
+
+	assert.Equal(t, "subject-a", hint.Subject)
+	assert.Equal(t, []string{"client-a", "client-b"}, hint.Audience)
+	assert.Equal(t, "sid-a", hint.SessionID)
+	assert.Equal(t, "client-b", hint.ClientID())
+
+	hint, err = provider.DecodeIDTokenHint("https://auth.example.com", "client-a", token)
+	require.NoError(t, err)
+	assert.Equal(t, "subject-a", hint.Subject)
+
+	hint, err = provider.DecodeIDTokenHint("https://auth.example.com", "client-c", token)
+	assert.Nil(t, hint)
+	assert.EqualError(t, err, "the client with id 'client-c' is not an audience of the token")
+
+	hint, err = provider.DecodeIDTokenHint("https://other.example.com", "", token)
+	assert.Nil(t, hint)
+	assert.EqualError(t, err, "the token issuer 'https://auth.example.com' does not match the expected issuer 'https://other.example.com'")
+
+	hint, err = provider.DecodeIDTokenHint("https://auth.example.com", "", token[:len(token)-4]+"abcd")
+	assert.Nil(t, hint)
+	assert.EqualError(t, err, "failed to verify the token: square/go-jose: error in cryptographic primitive")
+
+	hint, err = provider.DecodeIDTokenHint("https://auth.example.com", "", "not-a-token")
+	assert.Nil(t, hint)
+	assert.Error(t, err)
+}
+
+func TestOpenIDConnectProvider_DecodeIDTokenHintShouldRejectOtherTokens(t *testing.T) {
+	provider := newLogoutTestProvider(t)
+
+	logout, err := provider.NewBackChannelLogoutToken(context.Background(), "https://auth.example.com", "client-a", "subject-a", "sid-a")
+	require.NoError(t, err)
+
+	hint, err := provider.DecodeIDTokenHint("https://auth.example.com", "client-a", logout)
+	assert.Nil(t, hint)
+	assert.EqualError(t, err, "the token is not an ID Token: the token has the type 'logout+jwt'")
+
+	generate := func(claims jwt.MapClaims, headers ExplicitTypeHeaders) string {
+		headers[JWTHeaderKeyIdentifier] = provider.KeyManager.GetActiveKeyID()
+
+		token, _, err := provider.KeyManager.Strategy().Generate(context.Background(), claims, headers)
+		require.NoError(t, err)
+
+		return token
+	}
+
+	hint, err = provider.DecodeIDTokenHint("https://auth.example.com", "client-a", generate(jwt.MapClaims{
+		ClaimIssuer:   "https://auth.example.com",
+		ClaimSubject:  "subject-a",
+		ClaimAudience: []string{"client-a"},
+	}, ExplicitTypeHeaders{JWTHeaderType: JWTTypeAccessToken}))
+	assert.Nil(t, hint)
+	assert.EqualError(t, err, "the token is not an ID Token: the token has the type 'at+jwt'")
+
+	hint, err = provider.DecodeIDTokenHint("https://auth.example.com", "client-a", generate(jwt.MapClaims{
+		ClaimIssuer:   "https://auth.example.com",
+		ClaimSubject:  "subject-a",
+		ClaimAudience: []string{"client-a"},
+		ClaimEvents:   map[string]any{EventBackChannelLogout: map[string]any{}},
+	}, ExplicitTypeHeaders{}))
+	assert.Nil(t, hint)
+	assert.EqualError(t, err, "the token is not an ID Token: the token has the events claim")
+
+	hint, err = provider.DecodeIDTokenHint("https://auth.example.com", "client-a", generate(jwt.MapClaims{
+		ClaimIssuer:   "https://auth.example.com",
+		ClaimAudience: []string{"client-a"},
+	}, ExplicitTypeHeaders{}))
+	assert.Nil(t, hint)
+	assert.EqualError(t, err, "the token is not an ID Token: the token does not have the sub and aud claims")
+}
+
+func TestIDTokenHint_ClientID(t *testing.T) {
+	assert.Equal(t, "", (&IDTokenHint{}).ClientID())
+	assert.Equal(t, "a", (&IDTokenHint{Audience: []string{"a", "b"}}).ClientID())
+	assert.Equal(t, "b", (&IDTokenHint{Audience: []string{"a", "b"}, AuthorizedParty: "b"}).ClientID())
+}
+
+func TestOpenIDConnectProvider_NewBackChannelLogoutToken(t *testing.T) {
+	provider := newLogoutTestProvider(t)
+
+	token, err := provider.NewBackChannelLogoutToken(context.Background(), "https://auth.example.com", "client-a", "subject-a", "sid-a")
+	require.NoError(t, err)
+
+	jws, err := jose.ParseSigned(token)
+	require.NoError(t, err)
+
+	assert.Equal(t, provider.KeyManager.GetActiveKeyID(), jws.Signatures[0].Header.KeyID)
+	assert.Equal(t, JWTTypeLogout, jws.Signatures[0].Header.ExtraHeaders[JWTHeaderType])
+
+	payload, err := provider.KeyManager.Verify(token)
+	require.NoError(t, err)
+
+	claims := map[string]any{}
+
+	require.NoError(t, json.Unmarshal(payload, &claims))
+
+	assert.Equal(t, "https://auth.example.com", claims[ClaimIssuer])
+	assert.Equal(t, []any{"client-a"}, claims[ClaimAudience])
+	assert.Equal(t, "subject-a", claims[ClaimSubject])
+	assert.Equal(t, "sid-a", claims[ClaimSessionID])
+	assert.Equal(t, map[string]any{EventBackChannelLogout: map[string]any{}}, claims[ClaimEvents])
+	assert.NotEmpty(t, claims[ClaimJWTID])
+	assert.NotContains(t, claims, ClaimNonce)
+}
+
+func TestOpenIDConnectProvider_SendBackChannelLogout(t *testing.T) {
+	provider := newLogoutTestProvider(t)
+
+	provider.Config.GetHTTPClient(context.Background()).RetryMax = 0
+
+	var received string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.PostFormValue(FormParameterLogoutToken)
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer server.Close()
+
+	client := &Client{ID: "client-a"}
+
+	assert.NoError(t, provider.SendBackChannelLogout(context.Background(), "https://auth.example.com", client, "subject-a", "sid-a"))
+	assert.Equal(t, "", received)
+
+	client.BackChannelLogoutURI = server.URL + "/logout"
+
+	assert.NoError(t, provider.SendBackChannelLogout(context.Background(), "https://auth.example.com", client, "subject-a", "sid-a"))
+	assert.NotEqual(t, "", received)
+
+	_, err := provider.KeyManager.Verify(received)
+	assert.NoError(t, err)
+
+	client.BackChannelLogoutURI = server.URL + "/fail"
+
+	assert.EqualError(t, provider.SendBackChannelLogout(context.Background(), "https://auth.example.com", client, "subject-a", "sid-a"),
+		"failed to deliver the logout token to client 'client-a': the back-channel logout uri responded with status code 400")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client.BackChannelLogoutURI = server.URL + "/logout"
+
+	assert.ErrorIs(t, provider.SendBackChannelLogout(ctx, "https://auth.example.com", client, "subject-a", "sid-a"), context.Canceled)
+}
+
+func TestOpenIDConnectProvider_DispatchBackChannelLogoutShouldBoundConcurrency(t *testing.T) {
+	provider := newLogoutTestProvider(t)
+
+	provider.Config.GetHTTPClient(context.Background()).RetryMax = 0
+
+	var (
+		active, peak int32
+		release      = make(chan struct{})
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&active, 1)
+
+		for {
+			previous := atomic.LoadInt32(&peak)
+
+			if current <= previous || atomic.CompareAndSwapInt32(&peak, previous, current) {
+				break
+			}
+		}
+
+		<-release
+
+		atomic.AddInt32(&active, -1)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	defer server.Close()
+
+	targets := make([]BackChannelLogoutTarget, backChannelLogoutWorkersMaximum*3)
+
+	for i := range targets {
+		targets[i] = BackChannelLogoutTarget{Client: &Client{ID: "client", BackChannelLogoutURI: server.URL}, Subject: "subject-a"}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		failed int32
+	)
+
+	wg.Add(len(targets))
+
+	provider.DispatchBackChannelLogout("https://auth.example.com", "sid-a", targets, func(target BackChannelLogoutTarget, err error) {
+		if err != nil {
+			atomic.AddInt32(&failed, 1)
+		}
+
+		wg.Done()
+	})
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&active) == backChannelLogoutWorkersMaximum
+	}, time.Second*5, time.Millisecond*10)
+
+	time.Sleep(time.Millisecond * 100)
+
+	assert.Equal(t, int32(backChannelLogoutWorkersMaximum), atomic.LoadInt32(&active))
+
+	close(release)
+
+	wg.Wait()
+
+	assert.Equal(t, int32(backChannelLogoutWorkersMaximum), atomic.LoadInt32(&peak))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&failed))
+}
+
+func TestOpenIDConnectProvider_WriteLogoutResponse(t *testing.T) {
+	provider := newLogoutTestProvider(t)
+
+	assert.False(t, provider.WriteLogoutResponse(context.Background(), "https://app.example.com", nil))
+
+	var (
+		data    map[string]any
+		sources []string
+	)
+
+	ctx := context.WithValue(context.Background(), WriteLogoutResponseFnContextKey, func(templateData map[string]any, frameSources []string) {
+		data, sources = templateData, frameSources
+	})
+
+	client := &Client{FrontChannelLogoutURI: "https://app.example.com/logout"}
+	other := &Client{FrontChannelLogoutURI: "https://app.example.com/other"}
+
+	assert.True(t, provider.WriteLogoutResponse(ctx, "https://app.example.com", []*url.URL{
+		client.GetFrontChannelLogoutURI("", ""), other.GetFrontChannelLogoutURI("", ""),
+	}))
+
+	assert.Equal(t, []string{"https://app.example.com"}, sources)
+	assert.Equal(t, "https://app.example.com", data["RedirURL"])
+	assert.Equal(t, []string{"https://app.example.com/logout", "https://app.example.com/other"}, data["FrontChannelLogoutURIs"])
+}
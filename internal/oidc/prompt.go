@@ -0,0 +1,95 @@
+package oidc
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ory/fosite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+)
+
+// GetPrompts returns the values of the prompt parameter of an authorization request form.
+func GetPrompts(form url.Values) (prompts []string) {
+	return fosite.RemoveEmpty(strings.Split(form.Get(FormParameterPrompt), " "))
+}
+
+// HasPrompt returns true if the prompt parameter of an authorization request form contains the provided prompt.
+func HasPrompt(form url.Values, prompt string) bool {
+	for _, p := range GetPrompts(form) {
+		if p == prompt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetMaxAge returns the value of the max_age parameter of an authorization request form, and false if the parameter
+// is absent or is not a non-negative number of seconds.
+func GetMaxAge(form url.Values) (maxAge time.Duration, ok bool) {
+	value, err := strconv.ParseInt(form.Get(FormParameterMaxAge), 10, 64)
+	if err != nil || value < 0 {
+		return 0, false
+	}
+
+	return time.Duration(value) * time.Second, true
+}
+
+// IsLoginPrompted returns true if an authorization request form may require the user to authenticate regardless of
+// their existing session, i.e. when the prompt parameter contains login or the max_age parameter is present.
+func IsLoginPrompted(form url.Values) bool {
+	if HasPrompt(form, PromptLogin) {
+		return true
+	}
+
+	_, ok := GetMaxAge(form)
+
+	return ok
+}
+
+// IsLoginRequired returns true if the prompt or max_age parameters of an authorization request form require the user
+// to authenticate again given the time they last authenticated and the time of the request. A max_age of 0 is the
+// same as a prompt of login.
+func IsLoginRequired(form url.Values, authTime, requestedAt time.Time) bool {
+	if HasPrompt(form, PromptLogin) && authTime.Before(requestedAt) {
+		return true
+	}
+
+	if maxAge, ok := GetMaxAge(form); ok && authTime.Add(maxAge).Before(requestedAt) {
+		return true
+	}
+
+	return false
+}
+
+// GetRequestedAuthenticationLevel returns the authentication level mapped to the first recognized value of the
+// acr_values parameter of an authorization request form, and authentication.NotAuthenticated if none of the values
+// are recognized.
+func GetRequestedAuthenticationLevel(form url.Values) authentication.Level {
+	for _, acr := range fosite.RemoveEmpty(strings.Split(form.Get(FormParameterACRValues), " ")) {
+		switch acr {
+		case ACROneFactor:
+			return authentication.OneFactor
+		case ACRTwoFactor:
+			return authentication.TwoFactor
+		}
+	}
+
+	return authentication.NotAuthenticated
+}
+
+// GetACR returns the Authentication Context Class Reference value which represents the authentication level, and an
+// empty string if the user is not authenticated.
+func GetACR(level authentication.Level) string {
+	switch level {
+	case authentication.OneFactor:
+		return ACROneFactor
+	case authentication.TwoFactor:
+		return ACRTwoFactor
+	default:
+		return ""
+	}
+}
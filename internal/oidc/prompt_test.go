@@ -0,0 +1,99 @@
+package oidc
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+)
+
+func TestGetPrompts(t *testing.T) {
+	assert.Len(t, GetPrompts(url.Values{}), 0)
+	assert.Equal(t, []string{PromptLogin, PromptConsent}, GetPrompts(url.Values{FormParameterPrompt: []string{"login  consent"}}))
+
+	assert.True(t, HasPrompt(url.Values{FormParameterPrompt: []string{"login consent"}}, PromptConsent))
+	assert.False(t, HasPrompt(url.Values{FormParameterPrompt: []string{"login consent"}}, PromptNone))
+}
+
+func TestGetMaxAge(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected time.Duration
+		ok       bool
+	}{
+		{"ShouldParseSeconds", "300", time.Minute * 5, true},
+		{"ShouldParseZero", "0", 0, true},
+		{"ShouldNotParseAbsent", "", 0, false},
+		{"ShouldNotParseNegative", "-1", 0, false},
+		{"ShouldNotParseInvalid", "abc", 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			form := url.Values{}
+
+			if tc.have != "" {
+				form.Set(FormParameterMaxAge, tc.have)
+			}
+
+			maxAge, ok := GetMaxAge(form)
+
+			assert.Equal(t, tc.expected, maxAge)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.ok, IsLoginPrompted(form))
+		})
+	}
+}
+
+func TestIsLoginRequired(t *testing.T) {
+	requestedAt := time.Unix(1000, 0)
+
+	testCases := []struct {
+		name     string
+		form     url.Values
+		authTime time.Time
+		expected bool
+	}{
+		{"ShouldNotRequireWithoutParameters", url.Values{}, time.Unix(10, 0), false},
+		{"ShouldRequireLoginPromptAuthenticatedBefore", url.Values{FormParameterPrompt: []string{PromptLogin}}, time.Unix(999, 0), true},
+		{"ShouldNotRequireLoginPromptAuthenticatedAfter", url.Values{FormParameterPrompt: []string{PromptLogin}}, time.Unix(1001, 0), false},
+		{"ShouldRequireMaxAgeExceeded", url.Values{FormParameterMaxAge: []string{"60"}}, time.Unix(900, 0), true},
+		{"ShouldNotRequireMaxAgeNotExceeded", url.Values{FormParameterMaxAge: []string{"60"}}, time.Unix(950, 0), false},
+		{"ShouldRequireMaxAgeZero", url.Values{FormParameterMaxAge: []string{"0"}}, time.Unix(999, 0), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsLoginRequired(tc.form, tc.authTime, requestedAt))
+		})
+	}
+}
+
+func TestGetRequestedAuthenticationLevel(t *testing.T) {
+	assert.Equal(t, authentication.NotAuthenticated, GetRequestedAuthenticationLevel(url.Values{}))
+	assert.Equal(t, authentication.NotAuthenticated, GetRequestedAuthenticationLevel(url.Values{FormParameterACRValues: []string{"urn:example:unknown"}}))
+	assert.Equal(t, authentication.OneFactor, GetRequestedAuthenticationLevel(url.Values{FormParameterACRValues: []string{ACROneFactor}}))
+	assert.Equal(t, authentication.TwoFactor, GetRequestedAuthenticationLevel(url.Values{FormParameterACRValues: []string{"urn:example:unknown " + ACRTwoFactor + " " + ACROneFactor}}))
+}
+
+func TestGetACR(t *testing.T) {
+	assert.Equal(t, "", GetACR(authentication.NotAuthenticated))
+	assert.Equal(t, ACROneFactor, GetACR(authentication.OneFactor))
+	assert.Equal(t, ACRTwoFactor, GetACR(authentication.TwoFactor))
+}
+
+func TestClient_IsAuthenticationLevelSufficientForForm(t *testing.T) {
+	client := &Client{Policy: authorization.OneFactor}
+
+	form := url.Values{FormParameterACRValues: []string{ACRTwoFactor}}
+
+	assert.True(t, client.IsAuthenticationLevelSufficientForForm(authentication.OneFactor, url.Values{}))
+	assert.False(t, client.IsAuthenticationLevelSufficientForForm(authentication.OneFactor, form))
+	assert.True(t, client.IsAuthenticationLevelSufficientForForm(authentication.TwoFactor, form))
+	assert.False(t, client.IsAuthenticationLevelSufficientForForm(authentication.NotAuthenticated, url.Values{}))
+}
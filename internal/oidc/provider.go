@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/ory/fosite"
 	"github.com/ory/fosite/handler/openid"
@@ -16,6 +17,8 @@ import (
 
 const (
 	WriteFormPostResponseFnContextKey ContextKey = iota
+	WriteLogoutResponseFnContextKey
+	IDTokenSigningAlgorithmContextKey
 )
 
 // NewOpenIDConnectProvider new-ups a OpenIDConnectProvider.
@@ -28,9 +31,16 @@ func NewOpenIDConnectProvider(config *schema.OpenIDConnectConfiguration, store s
 		JSONWriter: herodot.NewJSONWriter(nil),
 		Store:      NewStore(config, store),
 		Config:     NewConfig(config),
+
+		registration: config.DynamicClientRegistration.Enabled,
+
+		backChannelLogoutWorkers: make(chan struct{}, backChannelLogoutWorkersMaximum),
 	}
 
-	provider.OAuth2Provider = fosite.NewOAuth2Provider(provider.Store, provider.Config)
+	oauth2 := fosite.NewOAuth2Provider(provider.Store, provider.Config)
+
+	provider.OAuth2Provider = oauth2
+	provider.Config.Strategy.ClientAuthentication = NewClientAuthenticationStrategy(provider.Store, oauth2.DefaultClientAuthenticationStrategy).AuthenticateClient
 
 	if provider.KeyManager, err = NewKeyManagerWithConfiguration(config); err != nil {
 		return nil, err
@@ -41,10 +51,35 @@ func NewOpenIDConnectProvider(config *schema.OpenIDConnectConfiguration, store s
 		Config: provider.Config,
 	}
 
+	if provider.Claims, err = NewClaimsStrategy(config); err != nil {
+		return nil, err
+	}
+
+	provider.Config.Strategy.Core = NewJWTCoreStrategy(provider.Config.Strategy.Core, provider.KeyManager.Strategy(), provider.Store, provider.Config)
+
 	provider.Config.LoadHandlers(provider.Store, provider.KeyManager.Strategy())
 
 	provider.discovery = NewOpenIDConnectWellKnownConfiguration(config.EnablePKCEPlainChallenge, provider.Store.clients)
 
+	provider.discovery.IDTokenSigningAlgValuesSupported = provider.KeyManager.Algorithms()
+	provider.discovery.UserinfoSigningAlgValuesSupported = append([]string{SigningAlgorithmNone}, provider.discovery.IDTokenSigningAlgValuesSupported...)
+
+	// Request Objects can only be encrypted if at least one key with use='enc' is configured.
+	if provider.discovery.RequestObjectEncryptionAlgValuesSupported = provider.KeyManager.EncryptionAlgorithms(); len(provider.discovery.RequestObjectEncryptionAlgValuesSupported) == 0 {
+		provider.discovery.RequestObjectEncryptionEncValuesSupported = nil
+	}
+
+	scopes := make([]string, 0, len(config.Scopes))
+
+	for scope := range config.Scopes {
+		scopes = append(scopes, scope)
+	}
+
+	sort.Strings(scopes)
+
+	provider.discovery.ScopesSupported = append(provider.discovery.ScopesSupported, scopes...)
+	provider.discovery.ClaimsSupported = append(provider.discovery.ClaimsSupported, provider.Claims.GetClaimNames()...)
+
 	return provider, nil
 }
 
@@ -63,6 +98,12 @@ func (p *OpenIDConnectProvider) GetOAuth2WellKnownConfiguration(issuer string) O
 
 	options.AuthorizationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathAuthorization)
 	options.RevocationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathRevocation)
+	options.DeviceAuthorizationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathDeviceAuthorization)
+	options.PushedAuthorizationRequestEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathPushedAuthorizationRequest)
+
+	if p.registration {
+		options.RegistrationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathRegistration)
+	}
 
 	return options
 }
@@ -73,6 +114,7 @@ func (p *OpenIDConnectProvider) GetOpenIDConnectWellKnownConfiguration(issuer st
 		CommonDiscoveryOptions:                          p.discovery.CommonDiscoveryOptions,
 		OAuth2DiscoveryOptions:                          p.discovery.OAuth2DiscoveryOptions,
 		OpenIDConnectDiscoveryOptions:                   p.discovery.OpenIDConnectDiscoveryOptions,
+		OpenIDConnectRPInitiatedLogoutDiscoveryOptions:  p.discovery.OpenIDConnectRPInitiatedLogoutDiscoveryOptions,
 		OpenIDConnectFrontChannelLogoutDiscoveryOptions: p.discovery.OpenIDConnectFrontChannelLogoutDiscoveryOptions,
 		OpenIDConnectBackChannelLogoutDiscoveryOptions:  p.discovery.OpenIDConnectBackChannelLogoutDiscoveryOptions,
 	}
@@ -85,7 +127,14 @@ func (p *OpenIDConnectProvider) GetOpenIDConnectWellKnownConfiguration(issuer st
 
 	options.AuthorizationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathAuthorization)
 	options.RevocationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathRevocation)
+	options.DeviceAuthorizationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathDeviceAuthorization)
+	options.PushedAuthorizationRequestEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathPushedAuthorizationRequest)
 	options.UserinfoEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathUserinfo)
+	options.EndSessionEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathEndSession)
+
+	if p.registration {
+		options.RegistrationEndpoint = fmt.Sprintf("%s%s", issuer, EndpointPathRegistration)
+	}
 
 	return options
 }
@@ -146,7 +195,7 @@ func (p *OpenIDConnectProvider) writeFormPostResponse(ctx context.Context, reque
 	clientID := requester.GetClient().GetID()
 
 	var clientDescription string
-	if client, err := p.Store.GetFullClient(clientID); err == nil {
+	if client, err := p.Store.GetFullClient(ctx, clientID); err == nil {
 		clientDescription = client.Description
 	}
 
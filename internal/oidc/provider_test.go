@@ -122,8 +122,16 @@ func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOpenIDConnectWellKnow
 	assert.Equal(t, "https://example.com/api/oidc/userinfo", disco.UserinfoEndpoint)
 	assert.Equal(t, "https://example.com/api/oidc/introspection", disco.IntrospectionEndpoint)
 	assert.Equal(t, "https://example.com/api/oidc/revocation", disco.RevocationEndpoint)
+	assert.Equal(t, "https://example.com/api/oidc/device-authorization", disco.DeviceAuthorizationEndpoint)
+	assert.Equal(t, "https://example.com/api/oidc/pushed-authorization-request", disco.PushedAuthorizationRequestEndpoint)
+	assert.Equal(t, "https://example.com/api/oidc/logout", disco.EndSessionEndpoint)
 	assert.Equal(t, "", disco.RegistrationEndpoint)
 
+	assert.True(t, disco.FrontChannelLogoutSupported)
+	assert.True(t, disco.FrontChannelLogoutSessionSupported)
+	assert.True(t, disco.BackChannelLogoutSupported)
+	assert.True(t, disco.BackChannelLogoutSessionSupported)
+
 	assert.Len(t, disco.CodeChallengeMethodsSupported, 1)
 	assert.Contains(t, disco.CodeChallengeMethodsSupported, PKCEChallengeMethodSHA256)
 
@@ -159,11 +167,23 @@ func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOpenIDConnectWellKnow
 	assert.Contains(t, disco.UserinfoSigningAlgValuesSupported, SigningAlgorithmRSAWithSHA256)
 	assert.Contains(t, disco.UserinfoSigningAlgValuesSupported, SigningAlgorithmNone)
 
-	assert.Len(t, disco.RequestObjectSigningAlgValuesSupported, 2)
+	assert.Len(t, disco.RequestObjectSigningAlgValuesSupported, 11)
 	assert.Contains(t, disco.RequestObjectSigningAlgValuesSupported, SigningAlgorithmRSAWithSHA256)
+	assert.Contains(t, disco.RequestObjectSigningAlgValuesSupported, SigningAlgorithmECDSAWithP256AndSHA256)
+	assert.Contains(t, disco.RequestObjectSigningAlgValuesSupported, SigningAlgorithmEdDSA)
 	assert.Contains(t, disco.RequestObjectSigningAlgValuesSupported, SigningAlgorithmNone)
 
-	assert.Len(t, disco.ClaimsSupported, 18)
+	assert.Len(t, disco.RequestObjectEncryptionAlgValuesSupported, 0)
+	assert.Len(t, disco.RequestObjectEncryptionEncValuesSupported, 0)
+
+	assert.True(t, disco.RequestParameterSupported)
+	assert.True(t, disco.RequestURIParameterSupported)
+	assert.True(t, disco.RequireRequestURIRegistration)
+
+	assert.Equal(t, []string{ACROneFactor, ACRTwoFactor}, disco.ACRValuesSupported)
+
+	assert.Len(t, disco.ClaimsSupported, 20)
+	assert.Contains(t, disco.ClaimsSupported, ClaimAuthenticationContextClassReference)
 	assert.Contains(t, disco.ClaimsSupported, ClaimAuthenticationMethodsReference)
 	assert.Contains(t, disco.ClaimsSupported, ClaimAudience)
 	assert.Contains(t, disco.ClaimsSupported, ClaimAuthorizedParty)
@@ -182,6 +202,7 @@ func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOpenIDConnectWellKnow
 	assert.Contains(t, disco.ClaimsSupported, ClaimGroups)
 	assert.Contains(t, disco.ClaimsSupported, ClaimPreferredUsername)
 	assert.Contains(t, disco.ClaimsSupported, ClaimFullName)
+	assert.Contains(t, disco.ClaimsSupported, ClaimSessionID)
 }
 
 func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOAuth2WellKnownConfiguration(t *testing.T) {
@@ -211,6 +232,8 @@ func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOAuth2WellKnownConfig
 	assert.Equal(t, "https://example.com/api/oidc/token", disco.TokenEndpoint)
 	assert.Equal(t, "https://example.com/api/oidc/introspection", disco.IntrospectionEndpoint)
 	assert.Equal(t, "https://example.com/api/oidc/revocation", disco.RevocationEndpoint)
+	assert.Equal(t, "https://example.com/api/oidc/device-authorization", disco.DeviceAuthorizationEndpoint)
+	assert.Equal(t, "https://example.com/api/oidc/pushed-authorization-request", disco.PushedAuthorizationRequestEndpoint)
 	assert.Equal(t, "", disco.RegistrationEndpoint)
 
 	require.Len(t, disco.CodeChallengeMethodsSupported, 1)
@@ -241,7 +264,8 @@ func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOAuth2WellKnownConfig
 	assert.Contains(t, disco.ResponseTypesSupported, "code token id_token")
 	assert.Contains(t, disco.ResponseTypesSupported, "none")
 
-	assert.Len(t, disco.ClaimsSupported, 18)
+	assert.Len(t, disco.ClaimsSupported, 20)
+	assert.Contains(t, disco.ClaimsSupported, ClaimAuthenticationContextClassReference)
 	assert.Contains(t, disco.ClaimsSupported, ClaimAuthenticationMethodsReference)
 	assert.Contains(t, disco.ClaimsSupported, ClaimAudience)
 	assert.Contains(t, disco.ClaimsSupported, ClaimAuthorizedParty)
@@ -260,6 +284,7 @@ func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOAuth2WellKnownConfig
 	assert.Contains(t, disco.ClaimsSupported, ClaimGroups)
 	assert.Contains(t, disco.ClaimsSupported, ClaimPreferredUsername)
 	assert.Contains(t, disco.ClaimsSupported, ClaimFullName)
+	assert.Contains(t, disco.ClaimsSupported, ClaimSessionID)
 }
 
 func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOpenIDConnectWellKnownConfigurationWithPlainPKCE(t *testing.T) {
@@ -306,3 +331,94 @@ func mustParseRSAPrivateKey(data string) *rsa.PrivateKey {
 
 	return key
 }
+
+func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetWellKnownConfigurationWithDynamicClientRegistration(t *testing.T) {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerCertificateChain: schema.X509CertificateChain{},
+		IssuerPrivateKey:       mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		HMACSecret:             "asbdhaaskmdlkamdklasmdlkams",
+		DynamicClientRegistration: schema.OpenIDConnectDynamicClientRegistrationConfiguration{
+			Enabled:            true,
+			InitialAccessToken: "an-initial-access-token",
+		},
+	}, nil)
+
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://example.com/api/oidc/registration", provider.GetOpenIDConnectWellKnownConfiguration("https://example.com").RegistrationEndpoint)
+	assert.Equal(t, "https://example.com/api/oidc/registration", provider.GetOAuth2WellKnownConfiguration("https://example.com").RegistrationEndpoint)
+}
+
+func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOpenIDConnectWellKnownConfigurationWithCustomScopes(t *testing.T) {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerCertificateChain: schema.X509CertificateChain{},
+		IssuerPrivateKey:       mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		HMACSecret:             "asbdhaaskmdlkamdklasmdlkams",
+		Scopes: map[string]schema.OpenIDConnectScopeConfiguration{
+			"api:write": {},
+			"api:read":  {Description: "Read the API"},
+		},
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:         "a-client",
+				Secret:     MustDecodeSecret("$plaintext$a-client-secret"),
+				Policy:     "one_factor",
+				Scopes:     []string{"api:read", "api:write"},
+				GrantTypes: []string{GrantTypeClientCredentials},
+			},
+		},
+	}, nil)
+
+	assert.NoError(t, err)
+
+	disco := provider.GetOpenIDConnectWellKnownConfiguration("https://example.com")
+
+	assert.Equal(t, []string{ScopeOfflineAccess, ScopeOpenID, ScopeProfile, ScopeGroups, ScopeEmail, "api:read", "api:write"}, disco.ScopesSupported)
+}
+
+func TestOpenIDConnectProvider_NewOpenIDConnectProvider_GetOpenIDConnectWellKnownConfigurationWithCustomClaims(t *testing.T) {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerCertificateChain: schema.X509CertificateChain{},
+		IssuerPrivateKey:       mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		HMACSecret:             "asbdhaaskmdlkamdklasmdlkams",
+		Claims: map[string]schema.OpenIDConnectClaimConfiguration{
+			"roles":      {Attribute: ClaimAttributeGroups, Filter: "^app-"},
+			"department": {Value: "engineering"},
+		},
+		Scopes: map[string]schema.OpenIDConnectScopeConfiguration{
+			"roles": {Claims: []string{"roles", "department"}},
+		},
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:           "a-client",
+				Secret:       MustDecodeSecret("$plaintext$a-client-secret"),
+				Policy:       "one_factor",
+				Scopes:       []string{ScopeOpenID, "roles"},
+				RedirectURIs: []string{"https://google.com"},
+			},
+		},
+	}, nil)
+
+	require.NoError(t, err)
+
+	disco := provider.GetOpenIDConnectWellKnownConfiguration("https://example.com")
+
+	assert.Contains(t, disco.ScopesSupported, "roles")
+	assert.Contains(t, disco.ClaimsSupported, "roles")
+	assert.Contains(t, disco.ClaimsSupported, "department")
+	assert.Equal(t, []string{"department", "roles"}, disco.ClaimsSupported[len(disco.ClaimsSupported)-2:])
+}
+
+func TestOpenIDConnectProvider_NewOpenIDConnectProvider_ShouldErrorOnInvalidClaim(t *testing.T) {
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerCertificateChain: schema.X509CertificateChain{},
+		IssuerPrivateKey:       mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		HMACSecret:             "asbdhaaskmdlkamdklasmdlkams",
+		Claims: map[string]schema.OpenIDConnectClaimConfiguration{
+			"roles": {Attribute: ClaimAttributeGroups, Filter: "^(app"},
+		},
+	}, nil)
+
+	assert.Nil(t, provider)
+	assert.EqualError(t, err, "error occurred parsing the claim 'roles': option 'filter' could not be compiled: error parsing regexp: missing closing ): `^(app`")
+}
@@ -0,0 +1,59 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/oauth2"
+
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// RefreshTokenGrantHandler is a fosite.TokenEndpointHandler which handles the OAuth 2.0 Refresh Token grant. It extends
+// the oauth2.RefreshTokenGrantHandler so a refresh token which is reused during the reuse grace period does not revoke
+// the access tokens issued by the request which rotated it, which would otherwise be revoked as soon as they're issued
+// when a client refreshes the same token concurrently.
+type RefreshTokenGrantHandler struct {
+	*oauth2.RefreshTokenGrantHandler
+
+	Storage *Store
+}
+
+// PopulateTokenEndpointResponse implements fosite.TokenEndpointHandler.
+func (h *RefreshTokenGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, requester fosite.AccessRequester, responder fosite.AccessResponder) (err error) {
+	handler := *h.RefreshTokenGrantHandler
+
+	handler.TokenRevocationStorage = &refreshTokenRevocationStorage{
+		Store:     h.Storage,
+		signature: handler.RefreshTokenStrategy.RefreshTokenSignature(ctx, requester.GetRequestForm().Get(FormParameterRefreshToken)),
+	}
+
+	return handler.PopulateTokenEndpointResponse(ctx, requester, responder)
+}
+
+// refreshTokenRevocationStorage is the oauth2.TokenRevocationStorage used to rotate the refresh token with the given
+// signature.
+type refreshTokenRevocationStorage struct {
+	*Store
+
+	signature string
+}
+
+// RevokeAccessToken revokes the access tokens of the request unless the refresh token has already been rotated, in
+// which case it's being reused during the reuse grace period and the access tokens were issued when it was rotated.
+// This implements a portion of oauth2.TokenRevocationStorage.
+func (s *refreshTokenRevocationStorage) RevokeAccessToken(ctx context.Context, requestID string) (err error) {
+	session, err := s.loadSessionModelBySignature(ctx, storage.OAuth2SessionTypeRefreshToken, s.signature)
+
+	switch {
+	case err == nil:
+		if !session.Active && session.RotatedAt.Valid {
+			return nil
+		}
+	case !errors.Is(err, fosite.ErrNotFound):
+		return err
+	}
+
+	return s.Store.RevokeAccessToken(ctx, requestID)
+}
@@ -0,0 +1,76 @@
+package oidc
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func TestRefreshTokenRevocationStorage_RevokeAccessToken(t *testing.T) {
+	testCases := []struct {
+		name     string
+		session  *model.OAuth2Session
+		expected []string
+	}{
+		{
+			"ShouldRevokeWhenActive",
+			&model.OAuth2Session{RequestID: "req", Signature: "rt", Active: true},
+			[]string{"req"},
+		},
+		{
+			"ShouldNotRevokeWhenRotated",
+			&model.OAuth2Session{RequestID: "req", Signature: "rt", RotatedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+			nil,
+		},
+		{
+			"ShouldRevokeWhenNotFound",
+			nil,
+			[]string{"req"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := &testAccessTokenRevocationStorageProvider{
+				testRefreshTokenStorageProvider: testRefreshTokenStorageProvider{sessions: map[string]*model.OAuth2Session{}},
+			}
+
+			if tc.session != nil {
+				provider.sessions[tc.session.Signature] = tc.session
+			}
+
+			s := &refreshTokenRevocationStorage{
+				Store: NewStore(&schema.OpenIDConnectConfiguration{
+					IssuerCertificateChain: schema.X509CertificateChain{},
+					IssuerPrivateKey:       mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+				}, provider),
+				signature: "rt",
+			}
+
+			require.NoError(t, s.RevokeAccessToken(context.Background(), "req"))
+			assert.Equal(t, tc.expected, provider.revoked)
+		})
+	}
+}
+
+type testAccessTokenRevocationStorageProvider struct {
+	testRefreshTokenStorageProvider
+
+	revoked []string
+}
+
+func (p *testAccessTokenRevocationStorageProvider) RevokeOAuth2SessionByRequestID(_ context.Context, sessionType storage.OAuth2SessionType, requestID string) (err error) {
+	if sessionType == storage.OAuth2SessionTypeAccessToken {
+		p.revoked = append(p.revoked, requestID)
+	}
+
+	return nil
+}
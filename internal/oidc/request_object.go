@@ -0,0 +1,277 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/token/jwt"
+	"github.com/ory/x/errorsx"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewAuthorizeRequest wraps fosite.OAuth2Provider's NewAuthorizeRequest and resolves the signed and optionally
+// encrypted Request Object passed by value using the 'request' parameter or by reference using the 'request_uri'
+// parameter before the request is validated.
+//
+// https://openid.net/specs/openid-connect-core-1_0.html#JWTRequests
+func (p *OpenIDConnectProvider) NewAuthorizeRequest(ctx context.Context, r *http.Request) (requester fosite.AuthorizeRequester, err error) {
+	var original map[string]string
+
+	if original, err = p.handleRequestObject(ctx, r, false); err != nil {
+		return p.newRequestObjectErrorRequester(ctx, r), err
+	}
+
+	if requester, err = p.OAuth2Provider.NewAuthorizeRequest(ctx, r); err != nil {
+		return requester, err
+	}
+
+	// The original Request Object parameters are retained so the request is validated again when the authorization
+	// flow is resumed after the user has authenticated or consented.
+	for key, value := range original {
+		requester.GetRequestForm().Set(key, value)
+	}
+
+	return requester, nil
+}
+
+// NewPushedAuthorizeRequest wraps fosite.OAuth2Provider's NewPushedAuthorizeRequest and resolves the signed and
+// optionally encrypted Request Object passed by value using the 'request' parameter before the request is validated.
+//
+// https://datatracker.ietf.org/doc/html/rfc9126#section-3
+func (p *OpenIDConnectProvider) NewPushedAuthorizeRequest(ctx context.Context, r *http.Request) (requester fosite.AuthorizeRequester, err error) {
+	if _, err = p.handleRequestObject(ctx, r, true); err != nil {
+		return p.newRequestObjectErrorRequester(ctx, r), err
+	}
+
+	return p.OAuth2Provider.NewPushedAuthorizeRequest(ctx, r)
+}
+
+// handleRequestObject validates the Request Object of the request if present and merges its claims into the form of
+// the request. The original Request Object parameters are removed from the form and returned.
+func (p *OpenIDConnectProvider) handleRequestObject(ctx context.Context, r *http.Request, par bool) (original map[string]string, err error) {
+	if err = r.ParseMultipartForm(1 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequest.WithHint("Unable to parse HTTP body, make sure to send a properly formatted form request body.").WithWrap(err).WithDebug(err.Error()))
+	}
+
+	form := r.Form
+
+	assertion, location := form.Get(FormParameterRequest), form.Get(FormParameterRequestURI)
+
+	if location != "" && (par || strings.HasPrefix(location, p.Config.GetPushedAuthorizeRequestURIPrefix(ctx))) {
+		return nil, nil
+	}
+
+	clientID := form.Get(FormParameterClientID)
+
+	if clientID == "" && par {
+		clientID, _, _ = r.BasicAuth()
+	}
+
+	var client *Client
+
+	if client, err = p.Store.GetFullClient(ctx, clientID); err != nil {
+		// The request is rejected with the appropriate error when it's validated.
+		return nil, nil
+	}
+
+	switch {
+	case assertion == "" && location == "":
+		if client.RequireSignedRequestObject {
+			return nil, errorsx.WithStack(fosite.ErrInvalidRequest.WithHint("The OAuth 2.0 Client requires a signed Request Object but neither the 'request' or 'request_uri' parameters were provided."))
+		}
+
+		return nil, nil
+	case assertion != "" && location != "":
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequest.WithHint("OpenID Connect parameters 'request' and 'request_uri' were both given, but you can use at most one."))
+	case location != "":
+		original = map[string]string{FormParameterRequestURI: location}
+
+		if assertion, err = p.getRequestObjectByReference(ctx, client, location); err != nil {
+			return nil, err
+		}
+	default:
+		original = map[string]string{FormParameterRequest: assertion}
+	}
+
+	var claims jwt.MapClaims
+
+	if claims, err = p.validateRequestObject(ctx, client, assertion); err != nil {
+		return nil, err
+	}
+
+	if uri, ok := claims[FormParameterRequestURI].(string); ok && uri != "" {
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHint("The Request Object must not contain the 'request_uri' parameter."))
+	}
+
+	// The parameters of the Request Object supersede those using the OAuth 2.0 request syntax, including the scope.
+	//
+	// https://openid.net/specs/openid-connect-core-1_0.html#RequestObject
+	for key, value := range claims {
+		switch key {
+		case ClaimIssuer, ClaimAudience, ClaimExpirationTime, ClaimIssuedAt, ClaimNotBefore, ClaimJWTID, FormParameterRequest, FormParameterRequestURI:
+			continue
+		default:
+			form.Set(key, requestObjectClaimToFormValue(value))
+		}
+	}
+
+	form.Del(FormParameterRequest)
+	form.Del(FormParameterRequestURI)
+
+	return original, nil
+}
+
+// getRequestObjectByReference retrieves a Request Object from a 'request_uri' which has been registered by the client.
+func (p *OpenIDConnectProvider) getRequestObjectByReference(ctx context.Context, client *Client, location string) (assertion string, err error) {
+	if !utils.IsStringInSlice(location, client.RequestURIs) {
+		return "", errorsx.WithStack(fosite.ErrInvalidRequestURI.WithHintf("Request URI '%s' is not registered by the OAuth 2.0 Client.", location))
+	}
+
+	var (
+		request  *retryablehttp.Request
+		response *http.Response
+	)
+
+	if request, err = retryablehttp.NewRequestWithContext(ctx, http.MethodGet, location, nil); err != nil {
+		return "", errorsx.WithStack(fosite.ErrInvalidRequestURI.WithHintf("Unable to fetch the Request Object from the 'request_uri' because: %s.", err.Error()).WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if response, err = p.Config.GetHTTPClient(ctx).Do(request); err != nil {
+		return "", errorsx.WithStack(fosite.ErrInvalidRequestURI.WithHintf("Unable to fetch the Request Object from the 'request_uri' because: %s.", err.Error()).WithWrap(err).WithDebug(err.Error()))
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return "", errorsx.WithStack(fosite.ErrInvalidRequestURI.WithHintf("Unable to fetch the Request Object from the 'request_uri' because status code '%d' was expected, but got '%d'.", http.StatusOK, response.StatusCode))
+	}
+
+	var body []byte
+
+	if body, err = io.ReadAll(io.LimitReader(response.Body, requestObjectMaxSize+1)); err != nil {
+		return "", errorsx.WithStack(fosite.ErrInvalidRequestURI.WithHintf("Unable to fetch the Request Object from the 'request_uri' because reading the body failed with: %s.", err.Error()).WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if len(body) > requestObjectMaxSize {
+		return "", errorsx.WithStack(fosite.ErrInvalidRequestURI.WithHintf("Unable to fetch the Request Object from the 'request_uri' because the body exceeds the maximum size of %d bytes.", requestObjectMaxSize))
+	}
+
+	return strings.TrimSpace(string(body)), nil
+}
+
+// validateRequestObject decrypts the Request Object if it's encrypted, verifies the signature using the JSON Web Key
+// Set of the client, and validates the claims.
+func (p *OpenIDConnectProvider) validateRequestObject(ctx context.Context, client *Client, assertion string) (claims jwt.MapClaims, err error) {
+	if strings.Count(assertion, ".") == 4 {
+		var payload []byte
+
+		if payload, err = p.KeyManager.Decrypt(assertion); err != nil {
+			return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHint("Unable to decrypt the Request Object.").WithWrap(err).WithDebug(err.Error()))
+		}
+
+		assertion = string(payload)
+	}
+
+	var token *jwt.Token
+
+	token, err = jwt.ParseWithClaims(assertion, jwt.MapClaims{}, func(t *jwt.Token) (key any, err error) {
+		alg, _ := t.Header[JWTHeaderAlgorithm].(string)
+
+		if client.RequestObjectSigningAlgorithm != "" && client.RequestObjectSigningAlgorithm != alg {
+			return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHintf("The Request Object uses signing algorithm '%s', but the requested OAuth 2.0 Client enforces signing algorithm '%s'.", alg, client.RequestObjectSigningAlgorithm))
+		}
+
+		switch alg {
+		case SigningAlgorithmNone:
+			if client.RequireSignedRequestObject {
+				return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHint("The OAuth 2.0 Client requires a signed Request Object but the Request Object is not signed."))
+			}
+
+			return jwt.UnsafeAllowNoneSignatureType, nil
+		case SigningAlgorithmHMACWithSHA256, SigningAlgorithmHMACWithSHA384, SigningAlgorithmHMACWithSHA512:
+			return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHintf("The Request Object uses unsupported signing algorithm '%s'.", alg))
+		}
+
+		if client.JSONWebKeys == nil {
+			return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHint("The OAuth 2.0 Client has no JSON Web Keys set registered, but they are needed to verify the Request Object."))
+		}
+
+		kid, _ := t.Header[JWTHeaderKeyIdentifier].(string)
+
+		if jwk := client.getSigningJSONWebKey(kid, alg); jwk != nil {
+			return jwk, nil
+		}
+
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHintf("Unable to find a public key with use='sig' for kid '%s' compatible with the algorithm '%s' in the JSON Web Key Set of the OAuth 2.0 Client.", kid, alg))
+	})
+
+	if err != nil {
+		var e *jwt.ValidationError
+
+		if errors.As(err, &e) {
+			if e.Inner != nil {
+				return nil, e.Inner
+			}
+
+			return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHint("Unable to verify the integrity of the Request Object.").WithWrap(err).WithDebug(err.Error()))
+		}
+
+		return nil, err
+	}
+
+	claims = token.Claims
+
+	if value, ok := claims[ClaimClientIdentifier]; ok && value != client.ID {
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHint("Claim 'client_id' from the Request Object must match the 'client_id' of the OAuth 2.0 Client."))
+	}
+
+	if !claims.VerifyIssuer(client.ID, false) {
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHint("Claim 'iss' from the Request Object must match the 'client_id' of the OAuth 2.0 Client."))
+	}
+
+	if _, ok := claims[ClaimAudience]; ok {
+		if rctx, ok := ctx.(RootURLContext); ok && !claims.VerifyAudience(rctx.RootURL().String(), true) {
+			return nil, errorsx.WithStack(fosite.ErrInvalidRequestObject.WithHint("Claim 'aud' from the Request Object must match the issuer."))
+		}
+	}
+
+	return claims, nil
+}
+
+// newRequestObjectErrorRequester returns a fosite.AuthorizeRequester which is safe to use when writing an error that
+// occurred while handling the Request Object.
+func (p *OpenIDConnectProvider) newRequestObjectErrorRequester(ctx context.Context, r *http.Request) fosite.AuthorizeRequester {
+	requester := fosite.NewAuthorizeRequest()
+
+	requester.Form = r.Form
+	requester.State = r.Form.Get(FormParameterState)
+
+	if client, err := p.Store.GetClient(ctx, r.Form.Get(FormParameterClientID)); err == nil {
+		requester.Client = client
+	}
+
+	return requester
+}
+
+func requestObjectClaimToFormValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool, int64, float64, json.Number:
+		return fmt.Sprintf("%v", v)
+	default:
+		if data, err := json.Marshal(v); err == nil {
+			return string(data)
+		}
+
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,368 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	josejwt "gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestOpenIDConnectProvider_RequestObject(t *testing.T) {
+	issuerKey := mustParseRSAPrivateKey(exampleIssuerPrivateKey)
+	clientKey := mustParseRSAPrivateKey(exampleIssuerPrivateKey)
+
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: clientKey.Public(), KeyID: "abc", Use: "sig"}}})
+	require.NoError(t, err)
+
+	sign := func(alg jose.SignatureAlgorithm, claims map[string]any) string {
+		if alg == SigningAlgorithmNone {
+			return fmt.Sprintf("%s.%s.", encodeSegment(t, map[string]any{JWTHeaderAlgorithm: SigningAlgorithmNone}), encodeSegment(t, claims))
+		}
+
+		signer, err := jose.NewSigner(jose.SigningKey{Algorithm: alg, Key: clientKey}, (&jose.SignerOptions{}).WithHeader(jose.HeaderKey(JWTHeaderKeyIdentifier), "abc"))
+		require.NoError(t, err)
+
+		token, err := josejwt.Signed(signer).Claims(claims).CompactSerialize()
+		require.NoError(t, err)
+
+		return token
+	}
+
+	encryptionKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	encryptTo := func(key *rsa.PrivateKey, token string) string {
+		encrypter, err := jose.NewEncrypter(jose.A128GCM, jose.Recipient{Algorithm: jose.RSA_OAEP_256, Key: key.Public().(*rsa.PublicKey)}, nil)
+		require.NoError(t, err)
+
+		object, err := encrypter.Encrypt([]byte(token))
+		require.NoError(t, err)
+
+		serialized, err := object.CompactSerialize()
+		require.NoError(t, err)
+
+		return serialized
+	}
+
+	encrypt := func(token string) string {
+		return encryptTo(encryptionKey, token)
+	}
+
+	claims := func(id string) map[string]any {
+		return map[string]any{
+			ClaimIssuer:           id,
+			ClaimAudience:         "https://auth.example.com",
+			ClaimClientIdentifier: id,
+			ClaimExpirationTime:   time.Now().Add(time.Minute).Unix(),
+			FormParameterState:    "random-state",
+			FormParameterScope:    "openid profile",
+			FormParameterMaxAge:   300,
+		}
+	}
+
+	var served string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(served))
+	}))
+
+	defer server.Close()
+
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKeys: []schema.OpenIDConnectIssuerPrivateKey{
+			{Key: issuerKey},
+			{Key: encryptionKey, Use: KeyUseEncryption},
+		},
+		HMACSecret: "asbdhaaskmdlkamdklasmdlkams",
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:                            "signed",
+				Secret:                        MustDecodeSecret("$plaintext$a-client-secret"),
+				Policy:                        "one_factor",
+				RedirectURIs:                  []string{"https://app.example.com/callback"},
+				RequestURIs:                   []string{server.URL},
+				RequestObjectSigningAlgorithm: SigningAlgorithmRSAWithSHA256,
+				RequireSignedRequestObject:    true,
+				JSONWebKeys:                   string(jwks),
+			},
+			{
+				ID:           "unsigned",
+				Secret:       MustDecodeSecret("$plaintext$a-client-secret"),
+				Policy:       "one_factor",
+				RedirectURIs: []string{"https://app.example.com/callback"},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	ctx := &testRootURLContext{Context: context.Background(), root: &url.URL{Scheme: "https", Host: "auth.example.com"}}
+
+	testCases := []struct {
+		name     string
+		form     url.Values
+		served   string
+		par      bool
+		expected url.Values
+		original string
+		err      string
+		hint     string
+	}{
+		{
+			"ShouldIgnoreRequestWithoutRequestObject",
+			url.Values{FormParameterClientID: []string{"unsigned"}, FormParameterScope: []string{"openid"}},
+			"",
+			false,
+			url.Values{FormParameterClientID: []string{"unsigned"}, FormParameterScope: []string{"openid"}},
+			"",
+			"",
+			"",
+		},
+		{
+			"ShouldMergeSignedRequestObject",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterScope: []string{"openid email"}, FormParameterRequest: []string{sign(jose.RS256, claims("signed"))}},
+			"",
+			false,
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterScope: []string{"openid profile"}, FormParameterState: []string{"random-state"}, FormParameterMaxAge: []string{"300"}},
+			FormParameterRequest,
+			"",
+			"",
+		},
+		{
+			"ShouldReplaceScopeWithRequestObjectScope",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterScope: []string{"openid email groups"}, FormParameterRequest: []string{sign(jose.RS256, claims("signed"))}},
+			"",
+			false,
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterScope: []string{"openid profile"}, FormParameterState: []string{"random-state"}, FormParameterMaxAge: []string{"300"}},
+			FormParameterRequest,
+			"",
+			"",
+		},
+		{
+			"ShouldMergeSignedRequestObjectFromRequestURI",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterScope: []string{"openid"}, FormParameterRequestURI: []string{server.URL}},
+			sign(jose.RS256, claims("signed")),
+			false,
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterScope: []string{"openid profile"}, FormParameterState: []string{"random-state"}, FormParameterMaxAge: []string{"300"}},
+			FormParameterRequestURI,
+			"",
+			"",
+		},
+		{
+			"ShouldMergeEncryptedRequestObject",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterScope: []string{"openid"}, FormParameterRequest: []string{encrypt(sign(jose.RS256, claims("signed")))}},
+			"",
+			true,
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterScope: []string{"openid profile"}, FormParameterState: []string{"random-state"}, FormParameterMaxAge: []string{"300"}},
+			FormParameterRequest,
+			"",
+			"",
+		},
+		{
+			"ShouldMergeUnsignedRequestObject",
+			url.Values{FormParameterClientID: []string{"unsigned"}, FormParameterScope: []string{"openid"}, FormParameterRequest: []string{sign(SigningAlgorithmNone, claims("unsigned"))}},
+			"",
+			false,
+			url.Values{FormParameterClientID: []string{"unsigned"}, FormParameterScope: []string{"openid profile"}, FormParameterState: []string{"random-state"}, FormParameterMaxAge: []string{"300"}},
+			FormParameterRequest,
+			"",
+			"",
+		},
+		{
+			"ShouldRejectMissingRequestObject",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterScope: []string{"openid"}},
+			"",
+			false,
+			nil,
+			"",
+			"invalid_request",
+			"The OAuth 2.0 Client requires a signed Request Object but neither the 'request' or 'request_uri' parameters were provided.",
+		},
+		{
+			"ShouldRejectBothParameters",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterRequest: []string{"abc"}, FormParameterRequestURI: []string{server.URL}},
+			"",
+			false,
+			nil,
+			"",
+			"invalid_request",
+			"OpenID Connect parameters 'request' and 'request_uri' were both given, but you can use at most one.",
+		},
+		{
+			"ShouldRejectUnregisteredRequestURI",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterRequestURI: []string{"https://app.example.com/request"}},
+			"",
+			false,
+			nil,
+			"",
+			"invalid_request_uri",
+			"Request URI 'https://app.example.com/request' is not registered by the OAuth 2.0 Client.",
+		},
+		{
+			"ShouldRejectUnsignedRequestObjectWhenSignedRequired",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterRequest: []string{sign(SigningAlgorithmNone, claims("signed"))}},
+			"",
+			false,
+			nil,
+			"",
+			"invalid_request_object",
+			"The Request Object uses signing algorithm 'none', but the requested OAuth 2.0 Client enforces signing algorithm 'RS256'.",
+		},
+		{
+			"ShouldRejectWrongSigningAlgorithm",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterRequest: []string{sign(jose.PS256, claims("signed"))}},
+			"",
+			false,
+			nil,
+			"",
+			"invalid_request_object",
+			"The Request Object uses signing algorithm 'PS256', but the requested OAuth 2.0 Client enforces signing algorithm 'RS256'.",
+		},
+		{
+			"ShouldRejectMismatchedClientID",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterRequest: []string{sign(jose.RS256, claims("unsigned"))}},
+			"",
+			false,
+			nil,
+			"",
+			"invalid_request_object",
+			"Claim 'client_id' from the Request Object must match the 'client_id' of the OAuth 2.0 Client.",
+		},
+		{
+			"ShouldRejectInvalidAudience",
+			url.Values{FormParameterClientID: []string{"unsigned"}, FormParameterRequest: []string{sign(SigningAlgorithmNone, map[string]any{ClaimAudience: "https://example.com"})}},
+			"",
+			false,
+			nil,
+			"",
+			"invalid_request_object",
+			"Claim 'aud' from the Request Object must match the issuer.",
+		},
+		{
+			"ShouldRejectOversizedRequestObjectFromRequestURI",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterRequestURI: []string{server.URL}},
+			strings.Repeat("a", requestObjectMaxSize+1),
+			false,
+			nil,
+			"",
+			"invalid_request_uri",
+			"Unable to fetch the Request Object from the 'request_uri' because the body exceeds the maximum size of 1048576 bytes.",
+		},
+		{
+			"ShouldRejectRequestObjectEncryptedToSigningKey",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterRequest: []string{encryptTo(issuerKey, sign(jose.RS256, claims("signed")))}},
+			"",
+			false,
+			nil,
+			"",
+			"invalid_request_object",
+			"Unable to decrypt the Request Object.",
+		},
+		{
+			"ShouldRejectUndecryptableRequestObject",
+			url.Values{FormParameterClientID: []string{"signed"}, FormParameterRequest: []string{"a.b.c.d.e"}},
+			"",
+			false,
+			nil,
+			"",
+			"invalid_request_object",
+			"Unable to decrypt the Request Object.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			served = tc.served
+
+			r, err := http.NewRequest(http.MethodPost, "https://auth.example.com/api/oidc/authorization", strings.NewReader(tc.form.Encode()))
+			require.NoError(t, err)
+
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			original, err := provider.handleRequestObject(ctx, r, tc.par)
+
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, r.Form)
+
+				if tc.original == "" {
+					assert.Len(t, original, 0)
+				} else {
+					assert.Equal(t, map[string]string{tc.original: tc.form.Get(tc.original)}, original)
+				}
+			} else {
+				require.Error(t, err)
+
+				rfc := fosite.ErrorToRFC6749Error(err)
+
+				assert.Equal(t, tc.err, rfc.ErrorField)
+				assert.Equal(t, tc.hint, rfc.HintField)
+			}
+		})
+	}
+}
+
+func TestOpenIDConnectProvider_GetRequestObjectByReferenceShouldUseContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("abc"))
+	}))
+
+	defer server.Close()
+
+	provider, err := NewOpenIDConnectProvider(&schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		HMACSecret:       "asbdhaaskmdlkamdklasmdlkams",
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:           "signed",
+				Secret:       MustDecodeSecret("$plaintext$a-client-secret"),
+				Policy:       "one_factor",
+				RedirectURIs: []string{"https://app.example.com/callback"},
+				RequestURIs:  []string{server.URL},
+			},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	client, err := provider.Store.GetFullClient(context.Background(), "signed")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = provider.getRequestObjectByReference(ctx, client, server.URL)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, "invalid_request_uri", fosite.ErrorToRFC6749Error(err).ErrorField)
+}
+
+func TestRequestObjectClaimToFormValue(t *testing.T) {
+	assert.Equal(t, "abc", requestObjectClaimToFormValue("abc"))
+	assert.Equal(t, "300", requestObjectClaimToFormValue(int64(300)))
+	assert.Equal(t, "1.5", requestObjectClaimToFormValue(1.5))
+	assert.Equal(t, "true", requestObjectClaimToFormValue(true))
+	assert.Equal(t, `{"userinfo":{"email":null}}`, requestObjectClaimToFormValue(map[string]any{"userinfo": map[string]any{"email": nil}}))
+}
+
+func encodeSegment(t *testing.T, value any) string {
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
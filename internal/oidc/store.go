@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -25,6 +26,9 @@ func NewStore(config *schema.OpenIDConnectConfiguration, provider storage.Provid
 	store = &Store{
 		provider: provider,
 		clients:  map[string]*Client{},
+
+		refreshTokenAbsoluteLifespan: config.RefreshTokenAbsoluteLifespan,
+		refreshTokenReuseGracePeriod: config.RefreshTokenReuseGracePeriod,
 	}
 
 	for _, client := range config.Clients {
@@ -66,8 +70,8 @@ func (s *Store) GetSubject(ctx context.Context, sectorID, username string) (subj
 }
 
 // GetClientPolicy retrieves the policy from the client with the matching provided id.
-func (s *Store) GetClientPolicy(id string) (level authorization.Level) {
-	client, err := s.GetFullClient(id)
+func (s *Store) GetClientPolicy(ctx context.Context, id string) (level authorization.Level) {
+	client, err := s.GetFullClient(ctx, id)
 	if err != nil {
 		return authorization.TwoFactor
 	}
@@ -75,23 +79,105 @@ func (s *Store) GetClientPolicy(id string) (level authorization.Level) {
 	return client.Policy
 }
 
-// GetFullClient returns a fosite.Client asserted as an Client matching the provided id.
-func (s *Store) GetFullClient(id string) (client *Client, err error) {
-	client, ok := s.clients[id]
-	if !ok {
+// GetFullClient returns a fosite.Client asserted as an Client matching the provided id. Clients from the configuration
+// take precedence over clients managed via the storage provider.
+func (s *Store) GetFullClient(ctx context.Context, id string) (client *Client, err error) {
+	var ok bool
+
+	if client, ok = s.clients[id]; ok {
+		return client, nil
+	}
+
+	if s.provider == nil {
 		return nil, fosite.ErrNotFound
 	}
 
-	return client, nil
+	var c *model.OAuth2Client
+
+	if c, err = s.provider.LoadOAuth2Client(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fosite.ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return NewClientFromStorage(c)
+}
+
+// HasTwoFactorStorageClient returns true if any client managed via the storage provider requires the two_factor policy.
+// Clients from the configuration are not checked as they're known when the authorization.Authorizer is created. As the
+// clients may be registered using the CLI at any time the result is cached for a short period rather than only being
+// determined at startup, which avoids scanning every client for each request.
+func (s *Store) HasTwoFactorStorageClient(ctx context.Context) (has bool, err error) {
+	if s.provider == nil {
+		return false, nil
+	}
+
+	s.twoFactorStorageClientMutex.Lock()
+
+	defer s.twoFactorStorageClientMutex.Unlock()
+
+	now := time.Now()
+
+	if now.Before(s.twoFactorStorageClientExpires) {
+		return s.twoFactorStorageClient, nil
+	}
+
+	if has, err = s.loadTwoFactorStorageClient(ctx); err != nil {
+		return false, err
+	}
+
+	s.twoFactorStorageClient, s.twoFactorStorageClientExpires = has, now.Add(cacheTwoFactorStorageClientLifespan)
+
+	return has, nil
+}
+
+func (s *Store) loadTwoFactorStorageClient(ctx context.Context) (has bool, err error) {
+	var clients []model.OAuth2Client
+
+	limit := 20
+
+	for page := 0; true; page++ {
+		if clients, err = s.provider.LoadOAuth2Clients(ctx, limit, page); err != nil {
+			return false, err
+		}
+
+		for _, client := range clients {
+			var metadata ClientRegistrationMetadata
+
+			if err = json.Unmarshal(client.Metadata, &metadata); err != nil {
+				return false, fmt.Errorf("error unmarshalling the metadata of client with id '%s': %w", client.ClientID, err)
+			}
+
+			if authorization.NewLevel(metadata.Policy) != authorization.OneFactor {
+				return true, nil
+			}
+		}
+
+		if len(clients) < limit {
+			break
+		}
+	}
+
+	return false, nil
 }
 
-// IsValidClientID returns true if the provided id exists in the OpenIDConnectProvider.Clients map.
-func (s *Store) IsValidClientID(id string) (valid bool) {
-	_, err := s.GetFullClient(id)
+// IsValidClientID returns true if the provided id exists in the OpenIDConnectProvider.Clients map or the storage
+// provider.
+func (s *Store) IsValidClientID(ctx context.Context, id string) (valid bool) {
+	_, err := s.GetFullClient(ctx, id)
 
 	return err == nil
 }
 
+// IsConfiguredClientID returns true if the provided id exists in the OpenIDConnectProvider.Clients map.
+func (s *Store) IsConfiguredClientID(id string) (configured bool) {
+	_, configured = s.clients[id]
+
+	return configured
+}
+
 // BeginTX starts a transaction.
 // This implements a portion of fosite storage.Transactional interface.
 func (s *Store) BeginTX(ctx context.Context) (c context.Context, err error) {
@@ -112,8 +198,8 @@ func (s *Store) Rollback(ctx context.Context) (err error) {
 
 // GetClient loads the client by its ID or returns an error if the client does not exist or another error occurred.
 // This implements a portion of fosite.ClientManager.
-func (s *Store) GetClient(_ context.Context, id string) (client fosite.Client, err error) {
-	return s.GetFullClient(id)
+func (s *Store) GetClient(ctx context.Context, id string) (client fosite.Client, err error) {
+	return s.GetFullClient(ctx, id)
 }
 
 // ClientAssertionJWTValid returns an error if the JTI is known or the DB check failed and nil if the JTI is not known.
@@ -124,7 +210,7 @@ func (s *Store) ClientAssertionJWTValid(ctx context.Context, jti string) (err er
 	blacklistedJTI, err := s.provider.LoadOAuth2BlacklistedJTI(ctx, signature)
 
 	switch {
-	case errors.Is(sql.ErrNoRows, err):
+	case errors.Is(err, sql.ErrNoRows):
 		return nil
 	case err != nil:
 		return err
@@ -135,6 +221,21 @@ func (s *Store) ClientAssertionJWTValid(ctx context.Context, jti string) (err er
 	}
 }
 
+// ConsumeClientAssertionJWT marks a JTI as known for the given expiry time and returns fosite.ErrJTIKnown if it was
+// already known and has not expired. Unlike ClientAssertionJWTValid followed by SetClientAssertionJWT this is atomic, so
+// concurrent requests with the same JTI can't both succeed.
+func (s *Store) ConsumeClientAssertionJWT(ctx context.Context, jti string, exp time.Time) (err error) {
+	if err = s.provider.InsertOAuth2BlacklistedJTI(ctx, model.NewOAuth2BlacklistedJTI(jti, exp), time.Now()); err != nil {
+		if errors.Is(err, storage.ErrOAuth2BlacklistedJTIExists) {
+			return fosite.ErrJTIKnown
+		}
+
+		return err
+	}
+
+	return nil
+}
+
 // SetClientAssertionJWT marks a JTI as known for the given expiry time. Before inserting the new JTI, it will clean
 // up any existing JTIs that have expired as those tokens can not be replayed due to the expiry.
 // This implements a portion of fosite.ClientManager.
@@ -193,10 +294,29 @@ func (s *Store) GetAccessTokenSession(ctx context.Context, signature string, ses
 	return s.loadSessionBySignature(ctx, storage.OAuth2SessionTypeAccessToken, signature, session)
 }
 
-// CreateRefreshTokenSession stores the authorization request for a given refresh token.
+// CreateRefreshTokenSession stores the authorization request for a given refresh token. Refresh tokens issued by
+// rotation share the request id of the original grant and therefore belong to the same family, which is used to
+// enforce the absolute lifespan.
 // This implements a portion of oauth2.RefreshTokenStorage.
 func (s *Store) CreateRefreshTokenSession(ctx context.Context, signature string, request fosite.Requester) (err error) {
-	return s.saveSession(ctx, storage.OAuth2SessionTypeRefreshToken, signature, request)
+	var session *model.OAuth2Session
+
+	if session, err = model.NewOAuth2SessionFromRequest(signature, request); err != nil {
+		return err
+	}
+
+	var createdAt time.Time
+
+	switch createdAt, err = s.provider.LoadOAuth2RefreshTokenFamilyCreatedAt(ctx, request.GetID()); {
+	case err == nil:
+		session.FamilyCreatedAt = sql.NullTime{Time: createdAt, Valid: true}
+	case errors.Is(err, sql.ErrNoRows):
+		session.FamilyCreatedAt = sql.NullTime{Time: session.RequestedAt, Valid: true}
+	default:
+		return err
+	}
+
+	return s.provider.SaveOAuth2Session(ctx, storage.OAuth2SessionTypeRefreshToken, *session)
 }
 
 // DeleteRefreshTokenSession marks the authorization request for a given refresh token as deleted.
@@ -208,22 +328,47 @@ func (s *Store) DeleteRefreshTokenSession(ctx context.Context, signature string)
 // RevokeRefreshToken revokes a refresh token as specified in: https://tools.ietf.org/html/rfc7009#section-2.1
 // If the particular token is a refresh token and the authorization server supports the revocation of access tokens,
 // then the authorization server SHOULD also invalidate all access tokens based on the same authorization grant (see Implementation Note).
+// Every refresh token in the family of the request id is revoked.
 // This implements a portion of oauth2.TokenRevocationStorage.
 func (s *Store) RevokeRefreshToken(ctx context.Context, requestID string) (err error) {
-	return s.provider.DeactivateOAuth2SessionByRequestID(ctx, storage.OAuth2SessionTypeRefreshToken, requestID)
+	return s.revokeSessionByRequestID(ctx, storage.OAuth2SessionTypeRefreshToken, requestID)
 }
 
-// RevokeRefreshTokenMaybeGracePeriod revokes an access token as specified in: https://tools.ietf.org/html/rfc7009#section-2.1
-// If the token passed to the request is an access token, the server MAY revoke the respective refresh token as well.
+// RevokeRefreshTokenMaybeGracePeriod is called when a refresh token is rotated. Only the rotated refresh token is
+// marked as inactive and the time it was rotated is recorded, so it can still be used during the reuse grace period
+// by concurrent requests. Any use after the grace period is treated as reuse by GetRefreshTokenSession.
 // This implements a portion of oauth2.TokenRevocationStorage.
 func (s *Store) RevokeRefreshTokenMaybeGracePeriod(ctx context.Context, requestID string, signature string) (err error) {
-	return s.RevokeRefreshToken(ctx, requestID)
+	return s.provider.RotateOAuth2RefreshTokenSession(ctx, signature, time.Now().UTC())
 }
 
-// GetRefreshTokenSession gets the authorization request for a given refresh token.
+// GetRefreshTokenSession gets the authorization request for a given refresh token. The expiration of the refresh token
+// is limited by the absolute lifespan of its family. If the refresh token has been rotated and the reuse grace period
+// has elapsed the fosite.ErrInactiveToken error is returned with the request, which results in every access token and
+// refresh token in the family being revoked.
 // This implements a portion of oauth2.RefreshTokenStorage.
 func (s *Store) GetRefreshTokenSession(ctx context.Context, signature string, session fosite.Session) (request fosite.Requester, err error) {
-	return s.loadSessionBySignature(ctx, storage.OAuth2SessionTypeRefreshToken, signature, session)
+	var sessionModel *model.OAuth2Session
+
+	if sessionModel, err = s.loadSessionModelBySignature(ctx, storage.OAuth2SessionTypeRefreshToken, signature); err != nil {
+		return nil, err
+	}
+
+	var r *fosite.Request
+
+	if r, err = sessionModel.ToRequest(ctx, session, s); err != nil {
+		return nil, err
+	}
+
+	if session != nil && sessionModel.FamilyCreatedAt.Valid {
+		s.limitRefreshTokenExpiration(r, session, sessionModel.FamilyCreatedAt.Time)
+	}
+
+	if !sessionModel.Active && !s.isRefreshTokenReuseGracePeriod(r, sessionModel) {
+		return r, fosite.ErrInactiveToken
+	}
+
+	return r, nil
 }
 
 // CreatePKCERequestSession stores the authorization request for a given PKCE request.
@@ -266,6 +411,120 @@ func (s *Store) GetOpenIDConnectSession(ctx context.Context, authorizeCode strin
 	return s.loadSessionBySignature(ctx, storage.OAuth2SessionTypeOpenIDConnect, authorizeCode, request.GetSession())
 }
 
+// CreateDeviceCodeSession stores the device code session for a device authorization request.
+// This implements a portion of oidc.DeviceCodeStorage.
+func (s *Store) CreateDeviceCodeSession(ctx context.Context, signature, userCodeSignature string, expiresAt time.Time, request fosite.Requester) (err error) {
+	var session *model.OAuth2DeviceCodeSession
+
+	if session, err = model.NewOAuth2DeviceCodeSessionFromRequest(signature, userCodeSignature, expiresAt, request); err != nil {
+		return err
+	}
+
+	return s.provider.SaveOAuth2DeviceCodeSession(ctx, *session)
+}
+
+// GetDeviceCodeSession loads the device code session given the device code signature.
+// This implements a portion of oidc.DeviceCodeStorage.
+func (s *Store) GetDeviceCodeSession(ctx context.Context, signature string, session fosite.Session) (device *model.OAuth2DeviceCodeSession, request fosite.Requester, err error) {
+	if device, err = s.provider.LoadOAuth2DeviceCodeSession(ctx, signature); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil, fosite.ErrNotFound
+		default:
+			return nil, nil, err
+		}
+	}
+
+	if request, err = device.ToRequest(ctx, session, s); err != nil {
+		return nil, nil, err
+	}
+
+	return device, request, nil
+}
+
+// CheckDeviceCodeSession records the time the device code session was last polled by the client and the polling
+// interval the client must respect.
+// This implements a portion of oidc.DeviceCodeStorage.
+func (s *Store) CheckDeviceCodeSession(ctx context.Context, signature string, checkedAt time.Time, interval time.Duration) (err error) {
+	return s.provider.SaveOAuth2DeviceCodeSessionCheckedAt(ctx, signature, checkedAt, int(interval/time.Second))
+}
+
+// InvalidateDeviceCodeSession marks the device code session as used. If the device code session has already been used
+// fosite.ErrInactiveToken is returned.
+// This implements a portion of oidc.DeviceCodeStorage.
+func (s *Store) InvalidateDeviceCodeSession(ctx context.Context, signature string) (err error) {
+	if err = s.provider.DeactivateOAuth2DeviceCodeSession(ctx, signature); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fosite.ErrInactiveToken
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// CreatePARSession stores the pushed authorization request context. The requestURI is used to derive the key.
+// This implements a portion of fosite.PARStorage.
+func (s *Store) CreatePARSession(ctx context.Context, requestURI string, request fosite.AuthorizeRequester) (err error) {
+	var par *model.OAuth2PARContext
+
+	if par, err = model.NewOAuth2PARContext(requestURI, request); err != nil {
+		return err
+	}
+
+	return s.provider.SaveOAuth2PARContext(ctx, *par)
+}
+
+// GetPARSession gets the pushed authorization request context. The caller is expected to merge the AuthorizeRequest.
+// This implements a portion of fosite.PARStorage.
+func (s *Store) GetPARSession(ctx context.Context, requestURI string) (request fosite.AuthorizeRequester, err error) {
+	var par *model.OAuth2PARContext
+
+	if par, err = s.provider.LoadOAuth2PARContext(ctx, requestURI); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, fosite.ErrNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	switch {
+	case par.Revoked:
+		return nil, fosite.ErrInactiveToken.WithHint("The pushed authorization request context has already been used.")
+	case par.IsExpired():
+		return nil, fosite.ErrTokenExpired.WithHintf("The pushed authorization request context expired at '%s'.", par.ExpiresAt)
+	}
+
+	return par.ToAuthorizeRequest(ctx, NewSession(), s)
+}
+
+// DeletePARSession is called by fosite as soon as the pushed authorization request context has been loaded by the
+// authorization endpoint. The context is deliberately not revoked here as the request_uri is reused when the user is
+// redirected back to the authorization endpoint after authentication and consent. Instead the context is revoked via
+// RevokePARSession once the authorization response has been issued.
+// This implements a portion of fosite.PARStorage.
+func (s *Store) DeletePARSession(ctx context.Context, requestURI string) (err error) {
+	return nil
+}
+
+// RevokePARSession marks the pushed authorization request context as used so it can't be used again.
+func (s *Store) RevokePARSession(ctx context.Context, requestURI string) (err error) {
+	return s.provider.RevokeOAuth2PARContext(ctx, requestURI)
+}
+
+// RevokeAccessTokenJTI adds the JTI of a JWT access token to the denylist until it expires, so that it's rejected even
+// though the token signature is valid.
+func (s *Store) RevokeAccessTokenJTI(ctx context.Context, jti string, exp time.Time) (err error) {
+	return s.provider.SaveOAuth2AccessTokenRevokedJTI(ctx, model.NewOAuth2BlacklistedJTI(jti, exp))
+}
+
+// IsAccessTokenJTIRevoked returns true if the JTI of a JWT access token is on the denylist.
+func (s *Store) IsAccessTokenJTIRevoked(ctx context.Context, jti string) (revoked bool, err error) {
+	return s.provider.LoadOAuth2AccessTokenJTIRevoked(ctx, model.NewOAuth2BlacklistedJTI(jti, time.Time{}).Signature, time.Now())
+}
+
 // IsJWTUsed implements an interface required for RFC7523.
 func (s *Store) IsJWTUsed(ctx context.Context, jti string) (used bool, err error) {
 	if err = s.ClientAssertionJWTValid(ctx, jti); err != nil {
@@ -285,8 +544,23 @@ func (s *Store) loadSessionBySignature(ctx context.Context, sessionType storage.
 		sessionModel *model.OAuth2Session
 	)
 
-	sessionModel, err = s.provider.LoadOAuth2Session(ctx, sessionType, signature)
-	if err != nil {
+	if sessionModel, err = s.loadSessionModelBySignature(ctx, sessionType, signature); err != nil {
+		return nil, err
+	}
+
+	if r, err = sessionModel.ToRequest(ctx, session, s); err != nil {
+		return nil, err
+	}
+
+	if !sessionModel.Active && sessionType == storage.OAuth2SessionTypeAuthorizeCode {
+		return r, fosite.ErrInvalidatedAuthorizeCode
+	}
+
+	return r, nil
+}
+
+func (s *Store) loadSessionModelBySignature(ctx context.Context, sessionType storage.OAuth2SessionType, signature string) (session *model.OAuth2Session, err error) {
+	if session, err = s.provider.LoadOAuth2Session(ctx, sessionType, signature); err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return nil, fosite.ErrNotFound
@@ -295,15 +569,39 @@ func (s *Store) loadSessionBySignature(ctx context.Context, sessionType storage.
 		}
 	}
 
-	if r, err = sessionModel.ToRequest(ctx, session, s); err != nil {
-		return nil, err
+	return session, nil
+}
+
+// limitRefreshTokenExpiration ensures the refresh token expires no later than the absolute lifespan of the family
+// after the family was created, regardless of how often the refresh token has been rotated.
+func (s *Store) limitRefreshTokenExpiration(r *fosite.Request, session fosite.Session, familyCreatedAt time.Time) {
+	lifespan := s.refreshTokenAbsoluteLifespan
+
+	if client, ok := r.Client.(*Client); ok {
+		lifespan = client.GetRefreshTokenAbsoluteLifespan(lifespan)
 	}
 
-	if !sessionModel.Active && sessionType == storage.OAuth2SessionTypeAuthorizeCode {
-		return r, fosite.ErrInvalidatedAuthorizeCode
+	if lifespan <= 0 {
+		return
 	}
 
-	return r, nil
+	expiresAt := familyCreatedAt.Add(lifespan).UTC()
+
+	if current := session.GetExpiresAt(fosite.RefreshToken); current.IsZero() || expiresAt.Before(current) {
+		session.SetExpiresAt(fosite.RefreshToken, expiresAt)
+	}
+}
+
+// isRefreshTokenReuseGracePeriod returns true if the refresh token was rotated within the reuse grace period of the
+// client, or the global reuse grace period if the client has none.
+func (s *Store) isRefreshTokenReuseGracePeriod(r *fosite.Request, session *model.OAuth2Session) bool {
+	period := s.refreshTokenReuseGracePeriod
+
+	if client, ok := r.Client.(*Client); ok {
+		period = client.GetRefreshTokenReuseGracePeriod(period)
+	}
+
+	return period > 0 && session.RotatedAt.Valid && time.Since(session.RotatedAt.Time) <= period
 }
 
 func (s *Store) saveSession(ctx context.Context, sessionType storage.OAuth2SessionType, signature string, r fosite.Requester) (err error) {
@@ -2,13 +2,24 @@ package oidc
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/ory/fosite"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 func TestOpenIDConnectStore_GetClientPolicy(t *testing.T) {
@@ -33,13 +44,13 @@ func TestOpenIDConnectStore_GetClientPolicy(t *testing.T) {
 		},
 	}, nil)
 
-	policyOne := s.GetClientPolicy("myclient")
+	policyOne := s.GetClientPolicy(context.Background(), "myclient")
 	assert.Equal(t, authorization.OneFactor, policyOne)
 
-	policyTwo := s.GetClientPolicy("myotherclient")
+	policyTwo := s.GetClientPolicy(context.Background(), "myotherclient")
 	assert.Equal(t, authorization.TwoFactor, policyTwo)
 
-	policyInvalid := s.GetClientPolicy("invalidclient")
+	policyInvalid := s.GetClientPolicy(context.Background(), "invalidclient")
 	assert.Equal(t, authorization.TwoFactor, policyInvalid)
 }
 
@@ -83,7 +94,7 @@ func TestOpenIDConnectStore_GetInternalClient_ValidClient(t *testing.T) {
 		Clients:                []schema.OpenIDConnectClientConfiguration{c1},
 	}, nil)
 
-	client, err := s.GetFullClient(c1.ID)
+	client, err := s.GetFullClient(context.Background(), c1.ID)
 	require.NoError(t, err)
 	require.NotNil(t, client)
 	assert.Equal(t, client.ID, c1.ID)
@@ -111,11 +122,137 @@ func TestOpenIDConnectStore_GetInternalClient_InvalidClient(t *testing.T) {
 		Clients:                []schema.OpenIDConnectClientConfiguration{c1},
 	}, nil)
 
-	client, err := s.GetFullClient("another-client")
+	client, err := s.GetFullClient(context.Background(), "another-client")
 	assert.Nil(t, client)
 	assert.EqualError(t, err, "not_found")
 }
 
+func TestOpenIDConnectStore_GetFullClient_Storage(t *testing.T) {
+	provider := &testClientStorageProvider{clients: map[string]*model.OAuth2Client{
+		"storedclient": {
+			ClientID: "storedclient",
+			Source:   ClientSourceCLI,
+			Secret:   []byte("$plaintext$mysecret"),
+			Metadata: []byte(`{"client_name":"stored client desc","redirect_uris":["https://example.com/callback"],"scope":"openid profile","authelia_authorization_policy":"one_factor"}`),
+		},
+		"badclient": {
+			ClientID: "badclient",
+			Metadata: []byte(`{`),
+		},
+		"myclient": {
+			ClientID: "myclient",
+			Source:   ClientSourceRegistration,
+			Metadata: []byte(`{"client_name":"stored myclient desc","token_endpoint_auth_method":"none","authelia_authorization_policy":"two_factor"}`),
+		},
+	}}
+
+	s := NewStore(&schema.OpenIDConnectConfiguration{
+		IssuerCertificateChain: schema.X509CertificateChain{},
+		IssuerPrivateKey:       mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:          "myclient",
+				Description: "myclient desc",
+				Policy:      "one_factor",
+				Scopes:      []string{ScopeOpenID, ScopeProfile},
+				Secret:      MustDecodeSecret("$plaintext$mysecret"),
+			},
+		},
+	}, provider)
+
+	ctx := context.Background()
+
+	client, err := s.GetFullClient(ctx, "storedclient")
+	require.NoError(t, err)
+
+	assert.Equal(t, "storedclient", client.GetID())
+	assert.Equal(t, "stored client desc", client.Description)
+	assert.Equal(t, authorization.OneFactor, client.Policy)
+	assert.Equal(t, []string{"https://example.com/callback"}, client.GetRedirectURIs())
+
+	assert.True(t, s.IsValidClientID(ctx, "storedclient"))
+	assert.True(t, s.IsConfiguredClientID("myclient"))
+	assert.False(t, s.IsConfiguredClientID("storedclient"))
+	assert.Equal(t, authorization.OneFactor, s.GetClientPolicy(ctx, "storedclient"))
+
+	// Clients from the configuration take precedence over clients with the same id managed via the storage provider.
+	client, err = s.GetFullClient(ctx, "myclient")
+	require.NoError(t, err)
+
+	assert.Equal(t, "myclient desc", client.Description)
+	assert.Equal(t, authorization.OneFactor, client.Policy)
+	assert.False(t, client.IsPublic())
+	assert.Equal(t, authorization.OneFactor, s.GetClientPolicy(ctx, "myclient"))
+
+	client, err = s.GetFullClient(ctx, "missingclient")
+	assert.Nil(t, client)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	client, err = s.GetFullClient(ctx, "badclient")
+	assert.Nil(t, client)
+	assert.EqualError(t, err, "error unmarshalling the metadata of client with id 'badclient': unexpected end of JSON input")
+}
+
+func TestOpenIDConnectStore_HasTwoFactorStorageClient(t *testing.T) {
+	config := &schema.OpenIDConnectConfiguration{
+		IssuerPrivateKey: mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:     "myclient",
+				Policy: "two_factor",
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	provider := &testClientStorageProvider{clients: map[string]*model.OAuth2Client{}}
+
+	for i := 0; i < 25; i++ {
+		id := fmt.Sprintf("client%02d", i)
+
+		provider.clients[id] = &model.OAuth2Client{
+			ClientID: id,
+			Metadata: []byte(`{"authelia_authorization_policy":"one_factor"}`),
+		}
+	}
+
+	s := NewStore(config, provider)
+
+	has, err := s.HasTwoFactorStorageClient(ctx)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	provider.clients["client24"].Metadata = []byte(`{"authelia_authorization_policy":"two_factor"}`)
+
+	has, err = s.HasTwoFactorStorageClient(ctx)
+	assert.NoError(t, err)
+	assert.False(t, has, "the cached result should be used until it expires")
+
+	s.twoFactorStorageClientExpires = time.Time{}
+
+	has, err = s.HasTwoFactorStorageClient(ctx)
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	provider.clients["client24"].Metadata = []byte(`{`)
+	s.twoFactorStorageClientExpires = time.Time{}
+
+	has, err = s.HasTwoFactorStorageClient(ctx)
+	assert.EqualError(t, err, "error unmarshalling the metadata of client with id 'client24': unexpected end of JSON input")
+	assert.False(t, has)
+
+	provider.clients["client24"].Metadata = []byte(`{"authelia_authorization_policy":"one_factor"}`)
+
+	has, err = s.HasTwoFactorStorageClient(ctx)
+	assert.NoError(t, err)
+	assert.False(t, has, "errors should not be cached")
+
+	has, err = NewStore(config, nil).HasTwoFactorStorageClient(ctx)
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
+
 func TestOpenIDConnectStore_IsValidClientID(t *testing.T) {
 	s := NewStore(&schema.OpenIDConnectConfiguration{
 		IssuerCertificateChain: schema.X509CertificateChain{},
@@ -131,9 +268,430 @@ func TestOpenIDConnectStore_IsValidClientID(t *testing.T) {
 		},
 	}, nil)
 
-	validClient := s.IsValidClientID("myclient")
-	invalidClient := s.IsValidClientID("myinvalidclient")
+	validClient := s.IsValidClientID(context.Background(), "myclient")
+	invalidClient := s.IsValidClientID(context.Background(), "myinvalidclient")
 
 	assert.True(t, validClient)
 	assert.False(t, invalidClient)
 }
+
+func TestOpenIDConnectStore_PARSession(t *testing.T) {
+	provider := &testPARStorageProvider{contexts: map[string]*model.OAuth2PARContext{}}
+
+	s := NewStore(&schema.OpenIDConnectConfiguration{
+		IssuerCertificateChain: schema.X509CertificateChain{},
+		IssuerPrivateKey:       mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:          "myclient",
+				Description: "myclient desc",
+				Policy:      "one_factor",
+				Scopes:      []string{ScopeOpenID, ScopeProfile},
+				Secret:      MustDecodeSecret("$plaintext$mysecret"),
+			},
+		},
+	}, provider)
+
+	ctx := context.Background()
+
+	client, err := s.GetClient(ctx, "myclient")
+	require.NoError(t, err)
+
+	requestURI := urnPARPrefix + "abc123"
+
+	session := NewSession()
+	session.SetExpiresAt(fosite.PushedAuthorizeRequestContext, time.Now().Add(time.Minute))
+
+	request := fosite.NewAuthorizeRequest()
+	request.ID = "req123"
+	request.Client = client
+	request.ResponseTypes = fosite.Arguments{"code"}
+	request.State = "random-state"
+	request.RequestedScope = fosite.Arguments{ScopeOpenID, ScopeProfile}
+	request.Form = url.Values{
+		"response_type": []string{"code"},
+		"redirect_uri":  []string{"https://example.com/callback"},
+		"state":         []string{"random-state"},
+	}
+	request.Session = session
+
+	_, err = s.GetPARSession(ctx, requestURI)
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	require.NoError(t, s.CreatePARSession(ctx, requestURI, request))
+
+	actual, err := s.GetPARSession(ctx, requestURI)
+	require.NoError(t, err)
+
+	assert.Equal(t, "req123", actual.GetID())
+	assert.Equal(t, "myclient", actual.GetClient().GetID())
+	assert.Equal(t, fosite.Arguments{"code"}, actual.GetResponseTypes())
+	assert.Equal(t, "random-state", actual.GetState())
+	assert.Equal(t, fosite.Arguments{ScopeOpenID, ScopeProfile}, actual.GetRequestedScopes())
+	assert.Equal(t, "https://example.com/callback", actual.GetRedirectURI().String())
+
+	require.NoError(t, s.DeletePARSession(ctx, requestURI))
+
+	_, err = s.GetPARSession(ctx, requestURI)
+	assert.NoError(t, err)
+
+	require.NoError(t, s.RevokePARSession(ctx, requestURI))
+
+	_, err = s.GetPARSession(ctx, requestURI)
+	assert.ErrorIs(t, err, fosite.ErrInactiveToken)
+
+	provider.contexts[requestURI].Revoked = false
+	provider.contexts[requestURI].ExpiresAt = time.Now().Add(time.Minute * -1)
+
+	_, err = s.GetPARSession(ctx, requestURI)
+	assert.ErrorIs(t, err, fosite.ErrTokenExpired)
+}
+
+func TestOpenIDConnectStore_RefreshTokenRotation(t *testing.T) {
+	provider := &testRefreshTokenStorageProvider{sessions: map[string]*model.OAuth2Session{}}
+
+	s := NewStore(&schema.OpenIDConnectConfiguration{
+		IssuerCertificateChain:       schema.X509CertificateChain{},
+		IssuerPrivateKey:             mustParseRSAPrivateKey(exampleIssuerPrivateKey),
+		RefreshTokenAbsoluteLifespan: time.Hour * 24,
+		RefreshTokenReuseGracePeriod: time.Second * 30,
+		Clients: []schema.OpenIDConnectClientConfiguration{
+			{
+				ID:     "myclient",
+				Policy: "one_factor",
+				Secret: MustDecodeSecret("$plaintext$mysecret"),
+			},
+			{
+				ID:     "short",
+				Policy: "one_factor",
+				Secret: MustDecodeSecret("$plaintext$mysecret"),
+				TokenLifespans: schema.OpenIDConnectClientTokenLifespans{
+					RefreshTokenAbsolute: time.Hour,
+				},
+			},
+			{
+				ID:     "lenient",
+				Policy: "one_factor",
+				Secret: MustDecodeSecret("$plaintext$mysecret"),
+				TokenLifespans: schema.OpenIDConnectClientTokenLifespans{
+					RefreshTokenReuseGracePeriod: time.Minute * 5,
+				},
+			},
+		},
+	}, provider)
+
+	ctx := context.Background()
+
+	newRequest := func(clientID string, requestedAt time.Time) *fosite.Request {
+		client, err := s.GetClient(ctx, clientID)
+		require.NoError(t, err)
+
+		session := NewSession()
+		session.SetExpiresAt(fosite.RefreshToken, time.Now().Add(time.Hour*2).UTC().Round(time.Second))
+
+		request := fosite.NewRequest()
+		request.ID = "req-" + clientID
+		request.RequestedAt = requestedAt
+		request.Client = client
+		request.Session = session
+
+		return request
+	}
+
+	familyCreatedAt := time.Now().Add(-time.Hour * 23).UTC().Round(time.Second)
+
+	require.NoError(t, s.CreateRefreshTokenSession(ctx, "rt1", newRequest("myclient", familyCreatedAt)))
+	require.NoError(t, s.CreateRefreshTokenSession(ctx, "rt2", newRequest("myclient", time.Now().UTC())))
+
+	// Every refresh token issued by rotation is created at the same time as the family.
+	assert.Equal(t, familyCreatedAt, provider.sessions["rt1"].FamilyCreatedAt.Time)
+	assert.Equal(t, familyCreatedAt, provider.sessions["rt2"].FamilyCreatedAt.Time)
+
+	// The expiration is limited by the absolute lifespan of the family.
+	r, err := s.GetRefreshTokenSession(ctx, "rt1", NewSession())
+	require.NoError(t, err)
+	assert.Equal(t, familyCreatedAt.Add(time.Hour*24), r.GetSession().GetExpiresAt(fosite.RefreshToken))
+
+	// The client specific absolute lifespan takes precedence over the global absolute lifespan.
+	require.NoError(t, s.CreateRefreshTokenSession(ctx, "short1", newRequest("short", familyCreatedAt)))
+
+	r, err = s.GetRefreshTokenSession(ctx, "short1", NewSession())
+	require.NoError(t, err)
+	assert.Equal(t, familyCreatedAt.Add(time.Hour), r.GetSession().GetExpiresAt(fosite.RefreshToken))
+
+	// A rotated refresh token can still be used within the grace period.
+	require.NoError(t, s.RevokeRefreshTokenMaybeGracePeriod(ctx, "req-myclient", "rt1"))
+	assert.False(t, provider.sessions["rt1"].Active)
+	assert.True(t, provider.sessions["rt2"].Active)
+
+	r, err = s.GetRefreshTokenSession(ctx, "rt1", NewSession())
+	assert.NoError(t, err)
+	assert.Equal(t, "req-myclient", r.GetID())
+
+	// A rotated refresh token used after the grace period is reported as inactive along with the request.
+	provider.sessions["rt1"].RotatedAt.Time = time.Now().Add(-time.Minute)
+
+	r, err = s.GetRefreshTokenSession(ctx, "rt1", NewSession())
+	assert.ErrorIs(t, err, fosite.ErrInactiveToken)
+	require.NotNil(t, r)
+	assert.Equal(t, "req-myclient", r.GetID())
+
+	// The client specific reuse grace period takes precedence over the global reuse grace period.
+	require.NoError(t, s.CreateRefreshTokenSession(ctx, "lenient1", newRequest("lenient", familyCreatedAt)))
+	require.NoError(t, s.RevokeRefreshTokenMaybeGracePeriod(ctx, "req-lenient", "lenient1"))
+
+	provider.sessions["lenient1"].RotatedAt.Time = time.Now().Add(-time.Minute)
+
+	_, err = s.GetRefreshTokenSession(ctx, "lenient1", NewSession())
+	assert.NoError(t, err)
+
+	// Revoking the refresh token by request id revokes the whole family.
+	require.NoError(t, s.RevokeRefreshToken(ctx, r.GetID()))
+
+	_, err = s.GetRefreshTokenSession(ctx, "rt2", NewSession())
+	assert.ErrorIs(t, err, fosite.ErrNotFound)
+
+	_, err = s.GetRefreshTokenSession(ctx, "short1", NewSession())
+	assert.NoError(t, err)
+}
+
+func TestOpenIDConnectStore_ClientAssertionJWTValid(t *testing.T) {
+	s := NewStore(&schema.OpenIDConnectConfiguration{}, newTestSQLiteProvider(t))
+
+	ctx := context.Background()
+
+	assert.NoError(t, s.ClientAssertionJWTValid(ctx, "unseen"))
+
+	require.NoError(t, s.SetClientAssertionJWT(ctx, "seen", time.Now().Add(time.Minute)))
+	assert.ErrorIs(t, s.ClientAssertionJWTValid(ctx, "seen"), fosite.ErrJTIKnown)
+
+	require.NoError(t, s.SetClientAssertionJWT(ctx, "expired", time.Now().Add(-time.Minute)))
+	assert.NoError(t, s.ClientAssertionJWTValid(ctx, "expired"))
+}
+
+func TestOpenIDConnectStore_AccessTokenJTIRevocation(t *testing.T) {
+	s := NewStore(&schema.OpenIDConnectConfiguration{}, newTestSQLiteProvider(t))
+	ctx := context.Background()
+
+	revoked, err := s.IsAccessTokenJTIRevoked(ctx, "jti")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, s.RevokeAccessTokenJTI(ctx, "jti", time.Now().Add(time.Minute)))
+
+	revoked, err = s.IsAccessTokenJTIRevoked(ctx, "jti")
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	assert.NoError(t, s.ClientAssertionJWTValid(ctx, "jti"))
+
+	require.NoError(t, s.SetClientAssertionJWT(ctx, "assertion", time.Now().Add(time.Minute)))
+
+	revoked, err = s.IsAccessTokenJTIRevoked(ctx, "assertion")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, s.RevokeAccessTokenJTI(ctx, "expired", time.Now().Add(-time.Minute)))
+
+	revoked, err = s.IsAccessTokenJTIRevoked(ctx, "expired")
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestOpenIDConnectStore_DeviceCodeSession(t *testing.T) {
+	s := NewStore(&schema.OpenIDConnectConfiguration{}, newTestSQLiteProvider(t))
+	ctx := context.Background()
+
+	request := fosite.NewRequest()
+	request.Client = &Client{ID: "device"}
+	request.Session = NewSession()
+
+	require.NoError(t, s.CreateDeviceCodeSession(ctx, "signature", "user-code-signature", time.Now().Add(time.Minute), request))
+	require.NoError(t, s.CheckDeviceCodeSession(ctx, "signature", time.Now(), time.Second*10))
+
+	device, err := s.provider.LoadOAuth2DeviceCodeSession(ctx, "signature")
+	require.NoError(t, err)
+	assert.Equal(t, time.Second*10, device.GetPollingInterval(time.Second*5))
+	assert.True(t, device.Active)
+
+	require.NoError(t, s.InvalidateDeviceCodeSession(ctx, "signature"))
+	assert.ErrorIs(t, s.InvalidateDeviceCodeSession(ctx, "signature"), fosite.ErrInactiveToken)
+}
+
+type testPARStorageProvider struct {
+	storage.Provider
+
+	contexts map[string]*model.OAuth2PARContext
+}
+
+func (p *testPARStorageProvider) SaveOAuth2PARContext(_ context.Context, par model.OAuth2PARContext) (err error) {
+	p.contexts[par.Signature] = &par
+
+	return nil
+}
+
+func (p *testPARStorageProvider) LoadOAuth2PARContext(_ context.Context, signature string) (par *model.OAuth2PARContext, err error) {
+	var ok bool
+
+	if par, ok = p.contexts[signature]; !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return par, nil
+}
+
+func (p *testPARStorageProvider) RevokeOAuth2PARContext(_ context.Context, signature string) (err error) {
+	par, ok := p.contexts[signature]
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	par.Revoked = true
+
+	return nil
+}
+
+type testClientStorageProvider struct {
+	storage.Provider
+
+	clients map[string]*model.OAuth2Client
+}
+
+func (p *testClientStorageProvider) LoadOAuth2Client(_ context.Context, clientID string) (client *model.OAuth2Client, err error) {
+	var ok bool
+
+	if client, ok = p.clients[clientID]; !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return client, nil
+}
+
+func (p *testClientStorageProvider) LoadOAuth2Clients(_ context.Context, limit, page int) (clients []model.OAuth2Client, err error) {
+	for _, client := range p.clients {
+		clients = append(clients, *client)
+	}
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].ClientID < clients[j].ClientID
+	})
+
+	if limit*page >= len(clients) {
+		return nil, nil
+	}
+
+	clients = clients[limit*page:]
+
+	if len(clients) > limit {
+		clients = clients[:limit]
+	}
+
+	return clients, nil
+}
+
+type testRefreshTokenStorageProvider struct {
+	storage.Provider
+
+	sessions map[string]*model.OAuth2Session
+}
+
+func (p *testRefreshTokenStorageProvider) SaveOAuth2Session(_ context.Context, _ storage.OAuth2SessionType, session model.OAuth2Session) (err error) {
+	p.sessions[session.Signature] = &session
+
+	return nil
+}
+
+func (p *testRefreshTokenStorageProvider) LoadOAuth2Session(_ context.Context, _ storage.OAuth2SessionType, signature string) (session *model.OAuth2Session, err error) {
+	var ok bool
+
+	if session, ok = p.sessions[signature]; !ok || session.Revoked {
+		return nil, sql.ErrNoRows
+	}
+
+	return session, nil
+}
+
+func (p *testRefreshTokenStorageProvider) LoadOAuth2RefreshTokenFamilyCreatedAt(_ context.Context, requestID string) (createdAt time.Time, err error) {
+	for _, session := range p.sessions {
+		if session.RequestID == requestID && session.FamilyCreatedAt.Valid {
+			return session.FamilyCreatedAt.Time, nil
+		}
+	}
+
+	return createdAt, sql.ErrNoRows
+}
+
+func (p *testRefreshTokenStorageProvider) RotateOAuth2RefreshTokenSession(_ context.Context, signature string, rotatedAt time.Time) (err error) {
+	if session, ok := p.sessions[signature]; ok && session.Active {
+		session.Active = false
+		session.RotatedAt = sql.NullTime{Time: rotatedAt, Valid: true}
+	}
+
+	return nil
+}
+
+func (p *testRefreshTokenStorageProvider) RevokeOAuth2SessionByRequestID(_ context.Context, _ storage.OAuth2SessionType, requestID string) (err error) {
+	for _, session := range p.sessions {
+		if session.RequestID == requestID {
+			session.Revoked = true
+		}
+	}
+
+	return nil
+}
+
+func TestOpenIDConnectStore_ConsumeClientAssertionJWT(t *testing.T) {
+	s := NewStore(&schema.OpenIDConnectConfiguration{}, newTestSQLiteProvider(t))
+
+	ctx := context.Background()
+
+	assert.NoError(t, s.ConsumeClientAssertionJWT(ctx, "jti-a", time.Now().Add(time.Minute)))
+	assert.ErrorIs(t, s.ConsumeClientAssertionJWT(ctx, "jti-a", time.Now().Add(time.Minute)), fosite.ErrJTIKnown)
+	assert.ErrorIs(t, s.ClientAssertionJWTValid(ctx, "jti-a"), fosite.ErrJTIKnown)
+
+	assert.NoError(t, s.ConsumeClientAssertionJWT(ctx, "jti-expired", time.Now().Add(-time.Minute)))
+	assert.NoError(t, s.ConsumeClientAssertionJWT(ctx, "jti-expired", time.Now().Add(time.Minute)))
+	assert.ErrorIs(t, s.ConsumeClientAssertionJWT(ctx, "jti-expired", time.Now().Add(time.Minute)), fosite.ErrJTIKnown)
+
+	var (
+		wg       sync.WaitGroup
+		accepted int32
+	)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := s.ConsumeClientAssertionJWT(ctx, "jti-concurrent", time.Now().Add(time.Minute)); err != nil {
+				assert.ErrorIs(t, err, fosite.ErrJTIKnown)
+
+				return
+			}
+
+			atomic.AddInt32(&accepted, 1)
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), accepted)
+}
+
+func newTestSQLiteProvider(t *testing.T) storage.Provider {
+	provider := storage.NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a-long-encryption-key-used-only-for-tests",
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+		},
+	})
+
+	require.NoError(t, provider.StartupCheck())
+
+	t.Cleanup(func() {
+		_ = provider.Close()
+	})
+
+	return provider
+}
@@ -0,0 +1,255 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/ory/x/errorsx"
+
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// TokenExchangeClient is a fosite.Client which has a policy restricting the audience and scopes it may request using
+// the OAuth 2.0 Token Exchange grant.
+type TokenExchangeClient interface {
+	fosite.Client
+
+	GetTokenExchangeAudience() (audience fosite.Arguments)
+	GetTokenExchangeScopes() (scopes fosite.Arguments)
+}
+
+// TokenExchangeGrantHandler is a fosite.TokenEndpointHandler which handles the OAuth 2.0 Token Exchange grant. Only
+// access tokens issued by this provider are accepted as the subject token and actor token. The issued access token
+// represents the subject of the subject token and includes the 'act' claim which identifies the acting party.
+//
+// https://datatracker.ietf.org/doc/html/rfc8693
+type TokenExchangeGrantHandler struct {
+	CoreStrategy oauth2.CoreStrategy
+	Storage      oauth2.AccessTokenStorage
+	Config       *Config
+}
+
+// CanSkipClientAuth implements fosite.TokenEndpointHandler.
+func (h *TokenExchangeGrantHandler) CanSkipClientAuth(ctx context.Context, requester fosite.AccessRequester) bool {
+	return false
+}
+
+// CanHandleTokenEndpointRequest implements fosite.TokenEndpointHandler.
+func (h *TokenExchangeGrantHandler) CanHandleTokenEndpointRequest(ctx context.Context, requester fosite.AccessRequester) bool {
+	return requester.GetGrantTypes().ExactOne(GrantTypeTokenExchange)
+}
+
+// HandleTokenEndpointRequest implements fosite.TokenEndpointHandler.
+//
+// https://datatracker.ietf.org/doc/html/rfc8693#section-2.1
+func (h *TokenExchangeGrantHandler) HandleTokenEndpointRequest(ctx context.Context, requester fosite.AccessRequester) (err error) {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return errorsx.WithStack(fosite.ErrUnknownRequest)
+	}
+
+	client, ok := requester.GetClient().(TokenExchangeClient)
+	if !ok || !client.GetGrantTypes().Has(GrantTypeTokenExchange) {
+		return errorsx.WithStack(fosite.ErrUnauthorizedClient.WithHintf("The OAuth 2.0 Client is not allowed to use the authorization grant '%s'.", GrantTypeTokenExchange))
+	}
+
+	if client.IsPublic() {
+		return errorsx.WithStack(fosite.ErrInvalidGrant.WithHint("The OAuth 2.0 Client is marked as public and is thus not allowed to use authorization grant 'urn:ietf:params:oauth:grant-type:token-exchange'."))
+	}
+
+	form := requester.GetRequestForm()
+
+	if tokenType := form.Get(FormParameterRequestedTokenType); tokenType != "" && tokenType != TokenTypeAccessToken {
+		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("The '%s' parameter must be '%s' but it is '%s'.", FormParameterRequestedTokenType, TokenTypeAccessToken, tokenType))
+	}
+
+	var subject, actor fosite.Requester
+
+	if subject, err = h.validateToken(ctx, form, FormParameterSubjectToken, FormParameterSubjectTokenType); err != nil {
+		return err
+	}
+
+	if subject.GetClient().GetID() != client.GetID() && !subject.GetGrantedAudience().Has(client.GetID()) {
+		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHint("The 'subject_token' was not issued to the OAuth 2.0 Client and the OAuth 2.0 Client is not part of its audience."))
+	}
+
+	if form.Get(FormParameterActorToken) != "" {
+		if actor, err = h.validateToken(ctx, form, FormParameterActorToken, FormParameterActorTokenType); err != nil {
+			return err
+		}
+
+		if actor.GetClient().GetID() != client.GetID() {
+			return errorsx.WithStack(fosite.ErrInvalidRequest.WithHint("The 'actor_token' was not issued to the OAuth 2.0 Client."))
+		}
+	} else if form.Get(FormParameterActorTokenType) != "" {
+		return errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("The '%s' parameter must not be set when the '%s' parameter is not set.", FormParameterActorTokenType, FormParameterActorToken))
+	}
+
+	if err = h.grantScopes(ctx, client, subject, requester); err != nil {
+		return err
+	}
+
+	if err = h.grantAudience(ctx, client, requester); err != nil {
+		return err
+	}
+
+	session, ok := subject.GetSession().Clone().(*model.OpenIDSession)
+	if !ok {
+		return errorsx.WithStack(fosite.ErrServerError.WithDebugf("The session of the 'subject_token' has an unexpected type '%T'.", subject.GetSession()))
+	}
+
+	session.ClientID = client.GetID()
+
+	if session.Extra == nil {
+		session.Extra = map[string]any{}
+	}
+
+	session.Extra[ClaimActor] = NewActorClaim(client.GetID(), actor, session.Extra[ClaimActor])
+
+	expiresAt := time.Now().UTC().Add(fosite.GetEffectiveLifespan(client, GrantTypeTokenExchange, fosite.AccessToken, h.Config.GetAccessTokenLifespan(ctx))).Round(time.Second)
+
+	// The issued access token must not outlive the subject token it was exchanged for.
+	if subjectExpiresAt := subject.GetSession().GetExpiresAt(fosite.AccessToken); !subjectExpiresAt.IsZero() && subjectExpiresAt.Before(expiresAt) {
+		expiresAt = subjectExpiresAt
+	}
+
+	session.SetExpiresAt(fosite.AccessToken, expiresAt)
+	session.SetExpiresAt(fosite.RefreshToken, time.Time{})
+
+	requester.SetSession(session)
+
+	return nil
+}
+
+// PopulateTokenEndpointResponse implements fosite.TokenEndpointHandler.
+//
+// https://datatracker.ietf.org/doc/html/rfc8693#section-2.2.1
+func (h *TokenExchangeGrantHandler) PopulateTokenEndpointResponse(ctx context.Context, requester fosite.AccessRequester, responder fosite.AccessResponder) (err error) {
+	if !h.CanHandleTokenEndpointRequest(ctx, requester) {
+		return errorsx.WithStack(fosite.ErrUnknownRequest)
+	}
+
+	var access, signature string
+
+	if access, signature, err = h.CoreStrategy.GenerateAccessToken(ctx, requester); err != nil {
+		return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if err = h.Storage.CreateAccessTokenSession(ctx, signature, requester.Sanitize([]string{})); err != nil {
+		return errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	responder.SetAccessToken(access)
+	responder.SetTokenType("bearer")
+	responder.SetExpiresIn(time.Until(requester.GetSession().GetExpiresAt(fosite.AccessToken)))
+	responder.SetScopes(requester.GetGrantedScopes())
+	responder.SetExtra(FormParameterIssuedTokenType, TokenTypeAccessToken)
+
+	return nil
+}
+
+func (h *TokenExchangeGrantHandler) validateToken(ctx context.Context, form url.Values, parameter, parameterType string) (request fosite.Requester, err error) {
+	token := form.Get(parameter)
+
+	if token == "" {
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("The '%s' parameter must be set but is not.", parameter))
+	}
+
+	if tokenType := form.Get(parameterType); tokenType != TokenTypeAccessToken {
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("The '%s' parameter must be '%s' but it is '%s'.", parameterType, TokenTypeAccessToken, tokenType))
+	}
+
+	if request, err = h.Storage.GetAccessTokenSession(ctx, h.CoreStrategy.AccessTokenSignature(ctx, token), NewSession()); err != nil {
+		if errors.Is(err, fosite.ErrNotFound) {
+			return nil, errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("The '%s' is not valid.", parameter).WithWrap(err).WithDebug(err.Error()))
+		}
+
+		return nil, errorsx.WithStack(fosite.ErrServerError.WithWrap(err).WithDebug(err.Error()))
+	}
+
+	if err = h.CoreStrategy.ValidateAccessToken(ctx, request, token); err != nil {
+		return nil, errorsx.WithStack(fosite.ErrInvalidRequest.WithHintf("The '%s' is not valid.", parameter).WithWrap(err).WithDebug(err.Error()))
+	}
+
+	return request, nil
+}
+
+func (h *TokenExchangeGrantHandler) grantScopes(ctx context.Context, client TokenExchangeClient, subject fosite.Requester, requester fosite.AccessRequester) (err error) {
+	strategy := h.Config.GetScopeStrategy(ctx)
+
+	scopes := requester.GetRequestedScopes()
+
+	if len(scopes) == 0 {
+		for _, scope := range subject.GetGrantedScopes() {
+			if strategy(client.GetTokenExchangeScopes(), scope) {
+				requester.GrantScope(scope)
+			}
+		}
+
+		return nil
+	}
+
+	for _, scope := range scopes {
+		if !strategy(client.GetTokenExchangeScopes(), scope) {
+			return errorsx.WithStack(fosite.ErrInvalidScope.WithHintf("The OAuth 2.0 Client is not allowed to request scope '%s' using the token exchange grant.", scope))
+		}
+
+		if !subject.GetGrantedScopes().Has(scope) {
+			return errorsx.WithStack(fosite.ErrInvalidScope.WithHintf("The scope '%s' was not granted to the 'subject_token'.", scope))
+		}
+
+		requester.GrantScope(scope)
+	}
+
+	return nil
+}
+
+func (h *TokenExchangeGrantHandler) grantAudience(ctx context.Context, client TokenExchangeClient, requester fosite.AccessRequester) (err error) {
+	audience := requester.GetRequestedAudience()
+
+	for _, resource := range requester.GetRequestForm()[FormParameterResource] {
+		if resource != "" && !utils.IsStringInSlice(resource, audience) {
+			audience = append(audience, resource)
+		}
+	}
+
+	if len(audience) == 0 {
+		audience = client.GetTokenExchangeAudience()
+	} else if err = h.Config.GetAudienceStrategy(ctx)(client.GetTokenExchangeAudience(), audience); err != nil {
+		return errorsx.WithStack(ErrInvalidTarget.WithHint(fosite.ErrorToRFC6749Error(err).HintField).WithWrap(err).WithDebug(err.Error()))
+	}
+
+	requester.SetRequestedAudience(audience)
+
+	for _, aud := range audience {
+		requester.GrantAudience(aud)
+	}
+
+	return nil
+}
+
+// NewActorClaim creates the value of the 'act' claim for a token issued using the OAuth 2.0 Token Exchange grant. The
+// acting party is the subject of the actor token when one is provided, otherwise it's the client itself. The prior
+// value of the 'act' claim of the subject token is nested to preserve the chain of delegation.
+//
+// https://datatracker.ietf.org/doc/html/rfc8693#section-4.1
+func NewActorClaim(clientID string, actor fosite.Requester, prior any) (claim map[string]any) {
+	claim = map[string]any{
+		ClaimSubject:          clientID,
+		ClaimClientIdentifier: clientID,
+	}
+
+	if actor != nil && actor.GetSession() != nil && actor.GetSession().GetSubject() != "" {
+		claim[ClaimSubject] = actor.GetSession().GetSubject()
+	}
+
+	if prior != nil {
+		claim[ClaimActor] = prior
+	}
+
+	return claim
+}
@@ -0,0 +1,295 @@
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ory/fosite"
+	"github.com/ory/fosite/handler/oauth2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestTokenExchangeGrantHandler_HandleTokenEndpointRequest(t *testing.T) {
+	config := NewConfig(&schema.OpenIDConnectConfiguration{HMACSecret: "abc123", AccessTokenLifespan: time.Hour})
+
+	ctx := context.Background()
+
+	app := &Client{ID: "app", Audience: []string{"gateway"}, GrantTypes: []string{GrantTypeAuthorizationCode}}
+	gateway := &Client{
+		ID:         "gateway",
+		GrantTypes: []string{GrantTypeTokenExchange},
+		TokenExchange: ClientTokenExchange{
+			Audience: []string{"https://inventory.example.com", "https://billing.example.com"},
+			Scopes:   []string{"inventory.read", "billing.read"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		client   fosite.Client
+		form     func(subject, actor string) url.Values
+		prior    any
+		hint     string
+		err      string
+		scopes   fosite.Arguments
+		audience fosite.Arguments
+		act      map[string]any
+	}{
+		{
+			name:   "ShouldExchangeWithPolicyDefaults",
+			client: gateway,
+			form: func(subject, _ string) url.Values {
+				return url.Values{FormParameterSubjectToken: {subject}, FormParameterSubjectTokenType: {TokenTypeAccessToken}}
+			},
+			scopes:   fosite.Arguments{"inventory.read"},
+			audience: fosite.Arguments{"https://inventory.example.com", "https://billing.example.com"},
+			act:      map[string]any{ClaimSubject: "gateway", ClaimClientIdentifier: "gateway"},
+		},
+		{
+			name:   "ShouldExchangeNarrowed",
+			client: gateway,
+			form: func(subject, _ string) url.Values {
+				return url.Values{
+					FormParameterSubjectToken:     {subject},
+					FormParameterSubjectTokenType: {TokenTypeAccessToken},
+					FormParameterScope:            {"inventory.read"},
+					FormParameterResource:         {"https://inventory.example.com"},
+				}
+			},
+			scopes:   fosite.Arguments{"inventory.read"},
+			audience: fosite.Arguments{"https://inventory.example.com"},
+			act:      map[string]any{ClaimSubject: "gateway", ClaimClientIdentifier: "gateway"},
+		},
+		{
+			name:   "ShouldExchangeWithActorAndPriorActor",
+			client: gateway,
+			form: func(subject, actor string) url.Values {
+				return url.Values{
+					FormParameterSubjectToken:     {subject},
+					FormParameterSubjectTokenType: {TokenTypeAccessToken},
+					FormParameterActorToken:       {actor},
+					FormParameterActorTokenType:   {TokenTypeAccessToken},
+				}
+			},
+			prior:    map[string]any{ClaimSubject: "frontend"},
+			scopes:   fosite.Arguments{"inventory.read"},
+			audience: fosite.Arguments{"https://inventory.example.com", "https://billing.example.com"},
+			act:      map[string]any{ClaimSubject: "service-account", ClaimClientIdentifier: "gateway", ClaimActor: map[string]any{ClaimSubject: "frontend"}},
+		},
+		{
+			name:   "ShouldRejectScopeNotAllowedByPolicy",
+			client: gateway,
+			form: func(subject, _ string) url.Values {
+				return url.Values{FormParameterSubjectToken: {subject}, FormParameterSubjectTokenType: {TokenTypeAccessToken}, FormParameterScope: {"openid"}}
+			},
+			err:  "invalid_scope",
+			hint: "The OAuth 2.0 Client is not allowed to request scope 'openid' using the token exchange grant.",
+		},
+		{
+			name:   "ShouldRejectScopeNotGrantedToSubjectToken",
+			client: gateway,
+			form: func(subject, _ string) url.Values {
+				return url.Values{FormParameterSubjectToken: {subject}, FormParameterSubjectTokenType: {TokenTypeAccessToken}, FormParameterScope: {"billing.read"}}
+			},
+			err:  "invalid_scope",
+			hint: "The scope 'billing.read' was not granted to the 'subject_token'.",
+		},
+		{
+			name:   "ShouldRejectAudienceNotAllowedByPolicy",
+			client: gateway,
+			form: func(subject, _ string) url.Values {
+				return url.Values{FormParameterSubjectToken: {subject}, FormParameterSubjectTokenType: {TokenTypeAccessToken}, FormParameterAudience: {"https://admin.example.com"}}
+			},
+			err: "invalid_target",
+		},
+		{
+			name:   "ShouldRejectInvalidSubjectToken",
+			client: gateway,
+			form: func(_, _ string) url.Values {
+				return url.Values{FormParameterSubjectToken: {"authelia_at_invalid.invalid"}, FormParameterSubjectTokenType: {TokenTypeAccessToken}}
+			},
+			err:  "invalid_request",
+			hint: "The 'subject_token' is not valid.",
+		},
+		{
+			name:   "ShouldRejectMissingSubjectToken",
+			client: gateway,
+			form: func(_, _ string) url.Values {
+				return url.Values{FormParameterSubjectTokenType: {TokenTypeAccessToken}}
+			},
+			err:  "invalid_request",
+			hint: "The 'subject_token' parameter must be set but is not.",
+		},
+		{
+			name:   "ShouldRejectUnsupportedSubjectTokenType",
+			client: gateway,
+			form: func(subject, _ string) url.Values {
+				return url.Values{FormParameterSubjectToken: {subject}, FormParameterSubjectTokenType: {"urn:ietf:params:oauth:token-type:id_token"}}
+			},
+			err:  "invalid_request",
+			hint: "The 'subject_token_type' parameter must be 'urn:ietf:params:oauth:token-type:access_token' but it is 'urn:ietf:params:oauth:token-type:id_token'.",
+		},
+		{
+			name:   "ShouldRejectUnsupportedRequestedTokenType",
+			client: gateway,
+			form: func(subject, _ string) url.Values {
+				return url.Values{FormParameterSubjectToken: {subject}, FormParameterSubjectTokenType: {TokenTypeAccessToken}, FormParameterRequestedTokenType: {"urn:ietf:params:oauth:token-type:refresh_token"}}
+			},
+			err:  "invalid_request",
+			hint: "The 'requested_token_type' parameter must be 'urn:ietf:params:oauth:token-type:access_token' but it is 'urn:ietf:params:oauth:token-type:refresh_token'.",
+		},
+		{
+			name:   "ShouldRejectClientNotInSubjectTokenAudience",
+			client: &Client{ID: "other", GrantTypes: []string{GrantTypeTokenExchange}, TokenExchange: gateway.TokenExchange},
+			form: func(subject, _ string) url.Values {
+				return url.Values{FormParameterSubjectToken: {subject}, FormParameterSubjectTokenType: {TokenTypeAccessToken}}
+			},
+			err:  "invalid_request",
+			hint: "The 'subject_token' was not issued to the OAuth 2.0 Client and the OAuth 2.0 Client is not part of its audience.",
+		},
+		{
+			name:   "ShouldRejectClientWithoutGrantType",
+			client: &Client{ID: "gateway", GrantTypes: []string{GrantTypeClientCredentials}},
+			form: func(subject, _ string) url.Values {
+				return url.Values{FormParameterSubjectToken: {subject}, FormParameterSubjectTokenType: {TokenTypeAccessToken}}
+			},
+			err:  "unauthorized_client",
+			hint: "The OAuth 2.0 Client is not allowed to use the authorization grant 'urn:ietf:params:oauth:grant-type:token-exchange'.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := &testAccessTokenStorage{sessions: map[string]fosite.Requester{}}
+
+			subjectSession := NewSession()
+			subjectSession.SetSubject("0b9b9f4f-7d1a-4c93-9b0d-4d2f0b8c1a7e")
+			subjectSession.ClientID = app.ID
+			subjectSession.SetExpiresAt(fosite.AccessToken, time.Now().Add(time.Minute*30).UTC().Round(time.Second))
+
+			if tc.prior != nil {
+				subjectSession.Extra[ClaimActor] = tc.prior
+			}
+
+			subject := fosite.NewAccessRequest(subjectSession)
+			subject.Client = app
+			subject.GrantScope(ScopeOpenID)
+			subject.GrantScope("inventory.read")
+			subject.GrantAudience("gateway")
+
+			subjectToken := storage.issue(t, config.Strategy.Core, subject)
+
+			actorSession := NewSession()
+			actorSession.SetSubject("service-account")
+			actorSession.ClientID = gateway.ID
+			actorSession.SetExpiresAt(fosite.AccessToken, time.Now().Add(time.Minute*30).UTC().Round(time.Second))
+
+			actor := fosite.NewAccessRequest(actorSession)
+			actor.Client = gateway
+
+			actorToken := storage.issue(t, config.Strategy.Core, actor)
+
+			handler := &TokenExchangeGrantHandler{
+				CoreStrategy: config.Strategy.Core,
+				Storage:      storage,
+				Config:       config,
+			}
+
+			requester := fosite.NewAccessRequest(NewSession())
+			requester.Client = tc.client
+			requester.GrantTypes = fosite.Arguments{GrantTypeTokenExchange}
+			requester.Form = tc.form(subjectToken, actorToken)
+			requester.SetRequestedScopes(fosite.RemoveEmpty([]string{requester.Form.Get(FormParameterScope)}))
+			requester.SetRequestedAudience(fosite.GetAudiences(requester.Form))
+
+			err := handler.HandleTokenEndpointRequest(ctx, requester)
+
+			if tc.err != "" {
+				require.Error(t, err)
+
+				rfc := fosite.ErrorToRFC6749Error(err)
+
+				assert.Equal(t, tc.err, rfc.ErrorField)
+
+				if tc.hint != "" {
+					assert.Equal(t, tc.hint, rfc.HintField)
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.scopes, requester.GetGrantedScopes())
+			assert.Equal(t, tc.audience, requester.GetGrantedAudience())
+
+			session, ok := requester.GetSession().(*model.OpenIDSession)
+			require.True(t, ok)
+
+			assert.Equal(t, "gateway", session.ClientID)
+			assert.Equal(t, "0b9b9f4f-7d1a-4c93-9b0d-4d2f0b8c1a7e", session.GetSubject())
+			assert.Equal(t, tc.act, session.GetExtraClaims()[ClaimActor])
+			assert.Equal(t, subjectSession.GetExpiresAt(fosite.AccessToken), session.GetExpiresAt(fosite.AccessToken))
+
+			responder := fosite.NewAccessResponse()
+
+			require.NoError(t, handler.PopulateTokenEndpointResponse(ctx, requester, responder))
+
+			assert.NotEmpty(t, responder.GetAccessToken())
+			assert.Equal(t, TokenTypeAccessToken, responder.GetExtra(FormParameterIssuedTokenType))
+			assert.Len(t, storage.sessions, 3)
+		})
+	}
+}
+
+func TestNewActorClaim(t *testing.T) {
+	assert.Equal(t, map[string]any{ClaimSubject: "gateway", ClaimClientIdentifier: "gateway"}, NewActorClaim("gateway", nil, nil))
+
+	session := NewSession()
+	session.SetSubject("service-account")
+
+	actor := fosite.NewAccessRequest(session)
+
+	assert.Equal(t, map[string]any{
+		ClaimSubject:          "service-account",
+		ClaimClientIdentifier: "gateway",
+		ClaimActor:            map[string]any{ClaimSubject: "frontend"},
+	}, NewActorClaim("gateway", actor, map[string]any{ClaimSubject: "frontend"}))
+}
+
+type testAccessTokenStorage struct {
+	oauth2.AccessTokenStorage
+
+	sessions map[string]fosite.Requester
+}
+
+func (s *testAccessTokenStorage) issue(t *testing.T, strategy oauth2.CoreStrategy, requester fosite.Requester) (token string) {
+	token, signature, err := strategy.GenerateAccessToken(context.Background(), requester)
+	require.NoError(t, err)
+
+	s.sessions[signature] = requester
+
+	return token
+}
+
+func (s *testAccessTokenStorage) GetAccessTokenSession(_ context.Context, signature string, _ fosite.Session) (request fosite.Requester, err error) {
+	var ok bool
+
+	if request, ok = s.sessions[signature]; !ok {
+		return nil, fosite.ErrNotFound
+	}
+
+	return request, nil
+}
+
+func (s *testAccessTokenStorage) CreateAccessTokenSession(_ context.Context, signature string, request fosite.Requester) (err error) {
+	s.sessions[signature] = request
+
+	return nil
+}
@@ -1,7 +1,9 @@
 package oidc
 
 import (
+	"context"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/go-crypt/crypt/algorithm"
@@ -85,8 +87,19 @@ type OpenIDConnectProvider struct {
 	*Config
 
 	KeyManager *KeyManager
+	Claims     *ClaimsStrategy
 
-	discovery OpenIDConnectWellKnownConfiguration
+	discovery    OpenIDConnectWellKnownConfiguration
+	registration bool
+
+	backChannelLogoutWorkers chan struct{}
+}
+
+// BackChannelLogoutTarget is a client which must be notified of a logout using the back-channel, and the subject the
+// client knows the user by.
+type BackChannelLogoutTarget struct {
+	Client  *Client
+	Subject string
 }
 
 // Store is Authelia's internal representation of the fosite.Storage interface. It maps the following
@@ -97,6 +110,13 @@ type OpenIDConnectProvider struct {
 type Store struct {
 	provider storage.Provider
 	clients  map[string]*Client
+
+	twoFactorStorageClientMutex   sync.Mutex
+	twoFactorStorageClient        bool
+	twoFactorStorageClientExpires time.Time
+
+	refreshTokenAbsoluteLifespan time.Duration
+	refreshTokenReuseGracePeriod time.Duration
 }
 
 // Client represents the client internally.
@@ -114,11 +134,52 @@ type Client struct {
 	ResponseTypes []string
 	ResponseModes []fosite.ResponseModeType
 
-	UserinfoSigningAlgorithm string
+	PostLogoutRedirectURIs            []string
+	FrontChannelLogoutURI             string
+	FrontChannelLogoutSessionRequired bool
+	BackChannelLogoutURI              string
+
+	RequirePushedAuthorizationRequests bool
+
+	RequestURIs                   []string
+	RequestObjectSigningAlgorithm string
+	RequireSignedRequestObject    bool
+
+	TokenEndpointAuthMethod           string
+	TokenEndpointAuthSigningAlgorithm string
+	JSONWebKeys                       *jose.JSONWebKeySet
+
+	IDTokenSigningAlgorithm     string
+	UserinfoSigningAlgorithm    string
+	AccessTokenSigningAlgorithm string
 
 	Policy authorization.Level
 
 	Consent ClientConsent
+
+	TokenLifespans ClientTokenLifespans
+
+	TokenExchange ClientTokenExchange
+
+	Claims map[string]*ClaimDefinition
+}
+
+// ClientTokenLifespans represents the token lifespans specific to a client. A zero value for any lifespan means the
+// global lifespan for that token type is used.
+type ClientTokenLifespans struct {
+	AccessToken          time.Duration
+	IDToken              time.Duration
+	RefreshToken         time.Duration
+	RefreshTokenAbsolute time.Duration
+
+	RefreshTokenReuseGracePeriod time.Duration
+}
+
+// ClientTokenExchange represents the policy which restricts the audience and scopes a client may request using the
+// OAuth 2.0 Token Exchange grant.
+type ClientTokenExchange struct {
+	Audience []string
+	Scopes   []string
 }
 
 // NewClientConsent converts the schema.OpenIDConnectClientConsentConfig into a oidc.ClientConsent.
@@ -177,20 +238,56 @@ func (c ClientConsentMode) String() string {
 	}
 }
 
-// KeyManager keeps track of all of the active/inactive rsa keys and provides them to services requiring them.
-// It additionally allows us to add keys for the purpose of key rotation in the future.
+// ExplicitTypeHeaders is a jwt.Mapper which unlike jwt.Headers does not filter the typ header, which is required to be
+// explicitly set for Logout Tokens and JWT Access Tokens.
+type ExplicitTypeHeaders map[string]any
+
+// ToMap returns the headers as a map.
+func (h ExplicitTypeHeaders) ToMap() map[string]any {
+	return h
+}
+
+// Add a header value.
+func (h ExplicitTypeHeaders) Add(key string, value any) {
+	h[key] = value
+}
+
+// Get a header value.
+func (h ExplicitTypeHeaders) Get(key string) any {
+	return h[key]
+}
+
+// RootURLContext is a context.Context which provides the root url of the current request which is used as the issuer.
+type RootURLContext interface {
+	context.Context
+
+	RootURL() (issuerURL *url.URL)
+}
+
+// PlainTextDigest is an algorithm.Digest which is able to provide the plain text value, which is required for the
+// client_secret_jwt client authentication method.
+type PlainTextDigest interface {
+	PlainText() (value []byte, ok bool)
+}
+
+// KeyManager keeps track of all of the active/inactive keys and provides them to services requiring them. The key used
+// to sign a token is the active key for the requested algorithm, which allows keys to be published before they are used
+// and retained for verification after they are no longer used.
 type KeyManager struct {
-	jwk  *JWK
-	jwks *jose.JSONWebKeySet
+	jwk     *JWK
+	keys    []*JWK
+	encKeys []*JWK
+	jwks    *jose.JSONWebKeySet
 }
 
 // ConsentGetResponseBody schema of the response body of the consent GET endpoint.
 type ConsentGetResponseBody struct {
-	ClientID          string   `json:"client_id"`
-	ClientDescription string   `json:"client_description"`
-	Scopes            []string `json:"scopes"`
-	Audience          []string `json:"audience"`
-	PreConfiguration  bool     `json:"pre_configuration"`
+	ClientID          string            `json:"client_id"`
+	ClientDescription string            `json:"client_description"`
+	Scopes            []string          `json:"scopes"`
+	ScopeDescriptions map[string]string `json:"scope_descriptions,omitempty"`
+	Audience          []string          `json:"audience"`
+	PreConfiguration  bool              `json:"pre_configuration"`
 }
 
 // ConsentPostRequestBody schema of the request body of the consent POST endpoint.
@@ -201,6 +298,11 @@ type ConsentPostRequestBody struct {
 	PreConfigure bool   `json:"pre_configure"`
 }
 
+// DeviceUserCodePostRequestBody schema of the request body of the device user code POST endpoint.
+type DeviceUserCodePostRequestBody struct {
+	UserCode string `json:"user_code"`
+}
+
 // ConsentPostResponseBody schema of the response body of the consent POST endpoint.
 type ConsentPostResponseBody struct {
 	RedirectURI string `json:"redirect_uri"`
@@ -545,6 +647,12 @@ type OpenIDConnectDiscoveryOptions struct {
 	*/
 	ClaimLocalesSupported []string `json:"claims_locales_supported,omitempty"`
 
+	/*
+		OPTIONAL. Boolean value specifying whether the OP supports use of the request parameter, with true indicating
+		support. If omitted, the default value is false.
+	*/
+	RequestParameterSupported bool `json:"request_parameter_supported"`
+
 	/*
 		OPTIONAL. Boolean value specifying whether the OP supports use of the request_uri parameter, with true indicating
 		support. If omitted, the default value is true.
@@ -585,6 +693,19 @@ type OpenIDConnectFrontChannelLogoutDiscoveryOptions struct {
 	FrontChannelLogoutSessionSupported bool `json:"frontchannel_logout_session_supported"`
 }
 
+// OpenIDConnectRPInitiatedLogoutDiscoveryOptions represents the discovery options specific to
+// OpenID Connect RP-Initiated Logout 1.0 functionality.
+// See Also:
+//
+//	OpenID Connect RP-Initiated Logout: https://openid.net/specs/openid-connect-rpinitiated-1_0.html#OPMetadata
+type OpenIDConnectRPInitiatedLogoutDiscoveryOptions struct {
+	/*
+		REQUIRED. URL at the OP to which an RP can perform a redirect to request that the End-User be logged out at the
+		OP. This URL MUST use the https scheme and MAY contain port, path, and query parameter components.
+	*/
+	EndSessionEndpoint string `json:"end_session_endpoint,omitempty"`
+}
+
 // OpenIDConnectBackChannelLogoutDiscoveryOptions represents the discovery options specific to
 // OpenID Connect Back-Channel Logout functionality.
 // See Also:
@@ -623,10 +744,22 @@ type PushedAuthorizationDiscoveryOptions struct {
 	RequirePushedAuthorizationRequests bool `json:"require_pushed_authorization_requests"`
 }
 
+// OAuth2DeviceAuthorizationGrantDiscoveryOptions represents the well known discovery document specific to the
+// OAuth 2.0 Device Authorization Grant (RFC8628) implementation.
+//
+// OAuth 2.0 Device Authorization Grant: https://datatracker.ietf.org/doc/html/rfc8628#section-4
+type OAuth2DeviceAuthorizationGrantDiscoveryOptions struct {
+	/*
+		OPTIONAL. URL of the authorization server's device authorization endpoint.
+	*/
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+}
+
 // OAuth2WellKnownConfiguration represents the well known discovery document specific to OAuth 2.0.
 type OAuth2WellKnownConfiguration struct {
 	CommonDiscoveryOptions
 	OAuth2DiscoveryOptions
+	OAuth2DeviceAuthorizationGrantDiscoveryOptions
 	PushedAuthorizationDiscoveryOptions
 }
 
@@ -634,10 +767,34 @@ type OAuth2WellKnownConfiguration struct {
 type OpenIDConnectWellKnownConfiguration struct {
 	CommonDiscoveryOptions
 	OAuth2DiscoveryOptions
+	OAuth2DeviceAuthorizationGrantDiscoveryOptions
 	PushedAuthorizationDiscoveryOptions
 	OpenIDConnectDiscoveryOptions
+	OpenIDConnectRPInitiatedLogoutDiscoveryOptions
 	OpenIDConnectFrontChannelLogoutDiscoveryOptions
 	OpenIDConnectBackChannelLogoutDiscoveryOptions
 }
 
+// IDTokenHint represents the claims of an ID Token issued by this provider which was provided as a hint.
+type IDTokenHint struct {
+	Issuer          string
+	Subject         string
+	Audience        []string
+	AuthorizedParty string
+	SessionID       string
+}
+
+// ClientID returns the id of the client the ID Token was issued to.
+func (h *IDTokenHint) ClientID() string {
+	if h.AuthorizedParty != "" {
+		return h.AuthorizedParty
+	}
+
+	if len(h.Audience) != 0 {
+		return h.Audience[0]
+	}
+
+	return ""
+}
+
 type ContextKey int
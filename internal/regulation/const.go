@@ -17,4 +17,16 @@ const (
 
 	// AuthTypeDuo is the string representing an auth log for second-factor authentication via DUO.
 	AuthTypeDuo = "Duo"
+
+	// AuthTypeSessionBinding is the string representing an auth log for a session binding violation. These logs are
+	// not considered when regulating authentication attempts.
+	AuthTypeSessionBinding = "SessionBinding"
+
+	// AuthTypeDeviceUserCode is the string representing an auth log for an end user entering the user code of a device.
+	// These logs are regulated by the remote IP.
+	AuthTypeDeviceUserCode = "DeviceUserCode"
+
+	// AuthTypeRefreshTokenReuse is the string representing an auth log for the reuse of a rotated OAuth 2.0 Refresh
+	// Token. These logs are not considered when regulating authentication attempts.
+	AuthTypeRefreshTokenReuse = "RefreshTokenReuse"
 )
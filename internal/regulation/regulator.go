@@ -2,6 +2,7 @@ package regulation
 
 import (
 	"context"
+	"net"
 	"strings"
 	"time"
 
@@ -51,6 +52,26 @@ func (r *Regulator) Regulate(ctx context.Context, username string) (time.Time, e
 		return time.Time{}, nil
 	}
 
+	return r.regulate(attempts)
+}
+
+// RegulateRemoteIP regulates the attempts of a specific authentication type for a given remote IP. It's used for
+// attempts which are not necessarily made by a known user such as entering the user code of a device.
+// This method returns ErrUserIsBanned if the remote IP is banned along with the time until when it's banned.
+func (r *Regulator) RegulateRemoteIP(ctx context.Context, ip net.IP, authType string) (time.Time, error) {
+	if !r.enabled {
+		return time.Time{}, nil
+	}
+
+	attempts, err := r.storageProvider.LoadAuthenticationLogsByRemoteIP(ctx, model.NewNullIP(ip), authType, r.clock.Now().Add(-r.config.BanTime), 10, 0)
+	if err != nil {
+		return time.Time{}, nil
+	}
+
+	return r.regulate(attempts)
+}
+
+func (r *Regulator) regulate(attempts []model.AuthenticationAttempt) (time.Time, error) {
 	latestFailedAttempts := make([]model.AuthenticationAttempt, 0, r.config.MaxRetries)
 
 	for _, attempt := range attempts {
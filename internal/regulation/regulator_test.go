@@ -2,17 +2,20 @@ package regulation_test
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -305,3 +308,47 @@ func (s *RegulatorSuite) TestShouldHaveRegulatorDisabled() {
 	_, err = regulator.Regulate(s.ctx, "john")
 	assert.Equal(s.T(), regulation.ErrUserIsBanned, err)
 }
+
+func TestShouldNotCountSessionBindingViolationsTowardsRegulation(t *testing.T) {
+	provider := storage.NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a-long-encryption-key-used-only-for-tests",
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+		},
+	})
+
+	require.NoError(t, provider.StartupCheck())
+
+	defer provider.Close()
+
+	ctx := context.Background()
+	clock := utils.TestingClock{}
+	clock.Set(time.Now())
+
+	for i := 3; i > 0; i-- {
+		require.NoError(t, provider.AppendAuthenticationLog(ctx, model.AuthenticationAttempt{
+			Time:     clock.Now().Add(time.Duration(-i) * time.Minute),
+			Username: "john",
+			Type:     regulation.AuthType1FA,
+		}))
+	}
+
+	// The session binding violations are more recent than the failed attempts and would otherwise fill the window of
+	// attempts loaded by the regulator.
+	for i := 10; i > 0; i-- {
+		require.NoError(t, provider.AppendAuthenticationLog(ctx, model.AuthenticationAttempt{
+			Time:     clock.Now().Add(time.Duration(-i) * time.Second),
+			Username: "john",
+			Type:     regulation.AuthTypeSessionBinding,
+		}))
+	}
+
+	regulator := regulation.NewRegulator(schema.RegulationConfiguration{
+		MaxRetries: 3,
+		BanTime:    time.Hour,
+		FindTime:   time.Hour,
+	}, provider, &clock)
+
+	_, err := regulator.Regulate(ctx, "john")
+	assert.Equal(t, regulation.ErrUserIsBanned, err)
+}
@@ -12,9 +12,15 @@ const (
 	fileIndexHTML       = "index.html"
 	fileLogo            = "logo.png"
 	fileOIDFormPostHTML = "oidc_form_post.html"
+	fileOIDLogoutHTML   = "oidc_logout.html"
+
+	cspFrameSrcNone = "frame-src 'none'"
 
 	extHTML = ".html"
 	extJSON = ".json"
+
+	pathAuthzLegacy = "/api/verify"
+	prefixAuthz     = "/api/authz/"
 )
 
 var (
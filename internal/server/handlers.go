@@ -26,19 +26,13 @@ import (
 )
 
 // Replacement for the default error handler in fasthttp.
-func handleError() func(ctx *fasthttp.RequestCtx, err error) {
+func handleError(trustedProxies []*net.IPNet) func(ctx *fasthttp.RequestCtx, err error) {
 	headerXForwardedFor := []byte(fasthttp.HeaderXForwardedFor)
 
 	getRemoteIP := func(ctx *fasthttp.RequestCtx) string {
-		if hdr := ctx.Request.Header.PeekBytes(headerXForwardedFor); hdr != nil {
-			ips := strings.Split(string(hdr), ",")
+		ip, _ := utils.ParseXForwardedFor(ctx.RemoteIP(), ctx.Request.Header.PeekBytes(headerXForwardedFor), trustedProxies)
 
-			if len(ips) > 0 {
-				return strings.Trim(ips[0], " ")
-			}
-		}
-
-		return ctx.RemoteIP().String()
+		return ip.String()
 	}
 
 	return func(ctx *fasthttp.RequestCtx, err error) {
@@ -152,8 +146,32 @@ func handleRouter(config schema.Configuration, providers middlewares.Providers)
 
 	metricsVRMW := middlewares.NewMetricsVerifyRequest(providers.Metrics)
 
-	r.ANY("/api/verify", middlewares.Wrap(metricsVRMW, middleware(handlers.VerifyGET(config.AuthenticationBackend))))
-	r.ANY("/api/verify/{path:*}", middlewares.Wrap(metricsVRMW, middleware(handlers.VerifyGET(config.AuthenticationBackend))))
+	for name, endpoint := range config.Server.Endpoints.Authz {
+		var handler fasthttp.RequestHandler
+
+		switch endpoint.Implementation {
+		case schema.AuthzImplementationLegacy:
+			handler = middlewares.Wrap(metricsVRMW, middleware(handlers.VerifyGET(config.AuthenticationBackend)))
+		default:
+			authz := handlers.NewAuthzBuilder().WithConfig(&config).WithEndpointConfig(endpoint).Build()
+
+			handler = middlewares.Wrap(metricsVRMW, middleware(authz.Handler))
+		}
+
+		switch name {
+		case schema.AuthzEndpointNameLegacy:
+			r.ANY(pathAuthzLegacy, handler)
+			r.ANY(pathAuthzLegacy+"/{path:*}", handler)
+		default:
+			uri := prefixAuthz + name
+
+			r.ANY(uri, handler)
+
+			if endpoint.Implementation == schema.AuthzImplementationExtAuthz {
+				r.ANY(uri+"/{authz_path:*}", handler)
+			}
+		}
+	}
 
 	r.POST("/api/checks/safe-redirection", middlewareAPI(handlers.CheckSafeRedirectionPOST))
 
@@ -176,6 +194,21 @@ func handleRouter(config schema.Configuration, providers middlewares.Providers)
 	r.POST("/api/user/info", middleware1FA(handlers.UserInfoPOST))
 	r.POST("/api/user/info/2fa_method", middleware1FA(handlers.MethodPreferencePOST))
 
+	// Active sessions of the user.
+	r.GET("/api/user/sessions", middleware1FA(handlers.UserSessionsGET))
+	r.DELETE("/api/user/sessions/{session_id}", middleware1FA(handlers.UserSessionDELETE))
+
+	if config.Server.Endpoints.Admin.Enabled {
+		middlewareAdmin := middlewares.NewBridgeBuilder(config, providers).
+			WithPreMiddlewares(middlewares.SecurityHeaders, middlewares.SecurityHeadersNoStore, middlewares.SecurityHeadersCSPNone).
+			WithPostMiddlewares(middlewares.RequireAdmin(config.Server.Endpoints.Admin.Groups)).
+			Build()
+
+		// Administrative endpoints for the active sessions of users.
+		r.GET("/api/admin/users/{username}/sessions", middlewareAdmin(handlers.AdminUserSessionsGET))
+		r.DELETE("/api/admin/users/{username}/sessions", middlewareAdmin(handlers.AdminUserSessionsDELETE))
+	}
+
 	if !config.TOTP.Disable {
 		// TOTP related endpoints.
 		r.GET("/api/user/info/totp", middleware1FA(handlers.UserTOTPInfoGET))
@@ -224,6 +257,7 @@ func handleRouter(config schema.Configuration, providers middlewares.Providers)
 
 	if providers.OpenIDConnect != nil {
 		oidcFormPostTemplate := NewTemplate(&config).ParseFile(path.Join(assetsRoot, fileOIDFormPostHTML))
+		oidcLogoutTemplate := NewTemplate(&config).ParseFile(path.Join(assetsRoot, fileOIDLogoutHTML))
 
 		middlewareOIDC := middlewares.NewBridgeBuilder(config, providers).WithPreMiddlewares(
 			middlewares.SecurityHeaders, middlewares.SecurityHeadersCSPNone, middlewares.SecurityHeadersNoStore,
@@ -233,13 +267,28 @@ func handleRouter(config schema.Configuration, providers middlewares.Providers)
 					oidcFormPostTemplate.Handler(tmplData)(ctx)
 				}
 			},
+		).WithAutheliaCtxValue(oidc.WriteLogoutResponseFnContextKey,
+			func(ctx *middlewares.AutheliaCtx) any {
+				return func(tmplData map[string]any, frameSources []string) {
+					oidcLogoutTemplate.Handler(tmplData)(ctx)
+
+					setCSPFrameSources(ctx, frameSources)
+				}
+			},
 		).Build()
 
 		r.GET("/api/oidc/consent", middlewareOIDC(handlers.OpenIDConnectConsentGET))
 		r.POST("/api/oidc/consent", middlewareOIDC(handlers.OpenIDConnectConsentPOST))
 
+		// OpenID Connect 1.0 consents of the user.
+		r.GET("/api/user/oidc/consents", middleware1FA(handlers.UserOIDCConsentsGET))
+		r.DELETE("/api/user/oidc/consents/{consent_id}", middleware1FA(handlers.UserOIDCConsentDELETE))
+
 		allowedOrigins := utils.StringSliceFromURLs(config.IdentityProviders.OIDC.CORS.AllowedOrigins)
 
+		r.GET(oidc.EndpointPathEndSession, middlewareOIDC(handlers.OpenIDConnectEndSession))
+		r.POST(oidc.EndpointPathEndSession, middlewareOIDC(handlers.OpenIDConnectEndSession))
+
 		r.OPTIONS(oidc.EndpointPathWellKnownOpenIDConfiguration, policyCORSPublicGET.HandleOPTIONS)
 		r.GET(oidc.EndpointPathWellKnownOpenIDConfiguration, policyCORSPublicGET.Middleware(middlewareOIDC(handlers.OpenIDConnectConfigurationWellKnownGET)))
 
@@ -316,6 +365,44 @@ func handleRouter(config schema.Configuration, providers middlewares.Providers)
 		// TODO (james-d-elliott): Remove in GA. This is a legacy implementation of the above endpoint.
 		r.OPTIONS("/api/oidc/revoke", policyCORSRevocation.HandleOPTIONS)
 		r.POST("/api/oidc/revoke", policyCORSRevocation.Middleware(middlewareOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OAuthRevocationPOST))))
+
+		policyCORSDeviceAuthorization := middlewares.NewCORSPolicyBuilder().
+			WithAllowCredentials(true).
+			WithAllowedMethods(fasthttp.MethodOptions, fasthttp.MethodPost).
+			WithAllowedOrigins(allowedOrigins...).
+			WithEnabled(utils.IsStringInSlice(oidc.EndpointDeviceAuthorization, config.IdentityProviders.OIDC.CORS.Endpoints)).
+			Build()
+
+		r.OPTIONS(oidc.EndpointPathDeviceAuthorization, policyCORSDeviceAuthorization.HandleOPTIONS)
+		r.POST(oidc.EndpointPathDeviceAuthorization, policyCORSDeviceAuthorization.Middleware(middlewareOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OpenIDConnectDeviceAuthorizationPOST))))
+
+		r.POST(oidc.EndpointPathDeviceUserCode, middlewareOIDC(handlers.OpenIDConnectDeviceUserCodePOST))
+
+		policyCORSPushedAuthorizationRequest := middlewares.NewCORSPolicyBuilder().
+			WithAllowCredentials(true).
+			WithAllowedMethods(fasthttp.MethodOptions, fasthttp.MethodPost).
+			WithAllowedOrigins(allowedOrigins...).
+			WithEnabled(utils.IsStringInSlice(oidc.EndpointPushedAuthorizationRequest, config.IdentityProviders.OIDC.CORS.Endpoints)).
+			Build()
+
+		r.OPTIONS(oidc.EndpointPathPushedAuthorizationRequest, policyCORSPushedAuthorizationRequest.HandleOPTIONS)
+		r.POST(oidc.EndpointPathPushedAuthorizationRequest, policyCORSPushedAuthorizationRequest.Middleware(middlewareOIDC(middlewares.NewHTTPToAutheliaHandlerAdaptor(handlers.OpenIDConnectPushedAuthorizationRequest))))
+
+		if config.IdentityProviders.OIDC.DynamicClientRegistration.Enabled {
+			policyCORSRegistration := middlewares.NewCORSPolicyBuilder().
+				WithAllowedMethods(fasthttp.MethodOptions, fasthttp.MethodGet, fasthttp.MethodPost, fasthttp.MethodPut, fasthttp.MethodDelete).
+				WithAllowedOrigins(allowedOrigins...).
+				WithEnabled(utils.IsStringInSlice(oidc.EndpointRegistration, config.IdentityProviders.OIDC.CORS.Endpoints)).
+				Build()
+
+			r.OPTIONS(oidc.EndpointPathRegistration, policyCORSRegistration.HandleOPTIONS)
+			r.POST(oidc.EndpointPathRegistration, policyCORSRegistration.Middleware(middlewareAPI(handlers.OpenIDConnectRegistrationPOST)))
+
+			r.OPTIONS(oidc.EndpointPathRegistration+"/{client_id}", policyCORSRegistration.HandleOPTIONS)
+			r.GET(oidc.EndpointPathRegistration+"/{client_id}", policyCORSRegistration.Middleware(middlewareAPI(handlers.OpenIDConnectRegistrationClientGET)))
+			r.PUT(oidc.EndpointPathRegistration+"/{client_id}", policyCORSRegistration.Middleware(middlewareAPI(handlers.OpenIDConnectRegistrationClientPUT)))
+			r.DELETE(oidc.EndpointPathRegistration+"/{client_id}", policyCORSRegistration.Middleware(middlewareAPI(handlers.OpenIDConnectRegistrationClientDELETE)))
+		}
 	}
 
 	r.HandleMethodNotAllowed = true
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NewProxyProtocolListener wraps a net.Listener so connections from the trusted sources have their PROXY protocol v1
+// or v2 header parsed, and the source address in the header is used as the remote address of the connection.
+func NewProxyProtocolListener(listener net.Listener, config schema.ServerProxyProtocol) net.Listener {
+	return &ProxyProtocolListener{
+		Listener: listener,
+		config:   config,
+	}
+}
+
+// ProxyProtocolListener is a net.Listener which accepts connections which may start with a PROXY protocol header.
+type ProxyProtocolListener struct {
+	net.Listener
+
+	config schema.ServerProxyProtocol
+}
+
+// Accept waits for and returns the next connection to the listener. The PROXY protocol header is not read until the
+// first call to Read, RemoteAddr, or LocalAddr so a slow client does not block the accept loop.
+func (l *ProxyProtocolListener) Accept() (conn net.Conn, err error) {
+	if conn, err = l.Listener.Accept(); err != nil {
+		return nil, err
+	}
+
+	var ip net.IP
+
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		ip = addr.IP
+	}
+
+	if !utils.IsIPInNetworks(ip, l.config.TrustedSources) {
+		return conn, nil
+	}
+
+	return &ProxyProtocolConn{
+		Conn:     conn,
+		reader:   bufio.NewReader(conn),
+		required: l.config.Mode != schema.ProxyProtocolModeOptional,
+		timeout:  l.config.Timeout,
+	}, nil
+}
+
+// ProxyProtocolConn is a net.Conn which reads the PROXY protocol header before any other data.
+type ProxyProtocolConn struct {
+	net.Conn
+
+	reader   *bufio.Reader
+	required bool
+	timeout  time.Duration
+
+	once       sync.Once
+	err        error
+	remoteAddr net.Addr
+	localAddr  net.Addr
+
+	mu       sync.Mutex
+	deadline time.Time
+}
+
+// Read reads data from the connection after the PROXY protocol header.
+func (c *ProxyProtocolConn) Read(b []byte) (n int, err error) {
+	c.once.Do(c.readHeader)
+
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the source address from the PROXY protocol header if present, otherwise the remote address of
+// the underlying connection.
+func (c *ProxyProtocolConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr returns the destination address from the PROXY protocol header if present, otherwise the local address
+// of the underlying connection.
+func (c *ProxyProtocolConn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+
+	if c.localAddr != nil {
+		return c.localAddr
+	}
+
+	return c.Conn.LocalAddr()
+}
+
+// SetDeadline sets the read and write deadlines of the underlying connection.
+func (c *ProxyProtocolConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.deadline = t
+	c.mu.Unlock()
+
+	return c.Conn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline of the underlying connection.
+func (c *ProxyProtocolConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	c.deadline = t
+	c.mu.Unlock()
+
+	return c.Conn.SetReadDeadline(t)
+}
+
+func (c *ProxyProtocolConn) readHeader() {
+	if c.timeout > 0 {
+		_ = c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+
+		defer func() {
+			c.mu.Lock()
+			_ = c.Conn.SetReadDeadline(c.deadline)
+			c.mu.Unlock()
+		}()
+	}
+
+	if c.err = c.parseHeader(); c.err != nil && !errors.Is(c.err, io.EOF) {
+		logging.Logger().WithError(c.err).Errorf("Error reading the PROXY protocol header from connection with remote address '%s'", c.Conn.RemoteAddr())
+	}
+}
+
+func (c *ProxyProtocolConn) parseHeader() (err error) {
+	var b []byte
+
+	if b, err = c.reader.Peek(1); err != nil {
+		return err
+	}
+
+	switch b[0] {
+	case proxyProtocolV1Prefix[0]:
+		if b, err = c.reader.Peek(len(proxyProtocolV1Prefix)); err == nil && bytes.Equal(b, proxyProtocolV1Prefix) {
+			return c.parseHeaderV1()
+		}
+	case proxyProtocolV2Signature[0]:
+		if b, err = c.reader.Peek(len(proxyProtocolV2Signature)); err == nil && bytes.Equal(b, proxyProtocolV2Signature) {
+			return c.parseHeaderV2()
+		}
+	}
+
+	if c.required {
+		return errProxyProtocolHeaderMissing
+	}
+
+	return nil
+}
+
+func (c *ProxyProtocolConn) parseHeaderV1() (err error) {
+	var line []byte
+
+	for {
+		var b byte
+
+		if b, err = c.reader.ReadByte(); err != nil {
+			return fmt.Errorf("error reading v1 header: %w", err)
+		}
+
+		line = append(line, b)
+
+		if b == '\n' {
+			break
+		}
+
+		if len(line) >= proxyProtocolV1MaxLength {
+			return fmt.Errorf("error reading v1 header: header exceeds the maximum length of %d bytes", proxyProtocolV1MaxLength)
+		}
+	}
+
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return fmt.Errorf("error reading v1 header: header is not terminated by CRLF")
+	}
+
+	fields := bytes.Split(line[:len(line)-2], []byte{' '})
+
+	if len(fields) < 2 {
+		return fmt.Errorf("error reading v1 header: header is malformed")
+	}
+
+	switch string(fields[1]) {
+	case "UNKNOWN":
+		return nil
+	case "TCP4", "TCP6":
+		if len(fields) != 6 {
+			return fmt.Errorf("error reading v1 header: header has %d fields but 6 are expected", len(fields))
+		}
+	default:
+		return fmt.Errorf("error reading v1 header: protocol '%s' is not supported", fields[1])
+	}
+
+	var src, dst *net.TCPAddr
+
+	if src, err = parseProxyProtocolV1Address(fields[2], fields[4]); err != nil {
+		return fmt.Errorf("error reading v1 header: source address is invalid: %w", err)
+	}
+
+	if dst, err = parseProxyProtocolV1Address(fields[3], fields[5]); err != nil {
+		return fmt.Errorf("error reading v1 header: destination address is invalid: %w", err)
+	}
+
+	c.remoteAddr, c.localAddr = src, dst
+
+	return nil
+}
+
+func (c *ProxyProtocolConn) parseHeaderV2() (err error) {
+	header := make([]byte, proxyProtocolV2HeaderLength)
+
+	if _, err = io.ReadFull(c.reader, header); err != nil {
+		return fmt.Errorf("error reading v2 header: %w", err)
+	}
+
+	if version := header[12] >> 4; version != 2 {
+		return fmt.Errorf("error reading v2 header: version %d is not supported", version)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+
+	if _, err = io.ReadFull(c.reader, payload); err != nil {
+		return fmt.Errorf("error reading v2 header: %w", err)
+	}
+
+	switch command := header[12] & 0x0F; command {
+	case proxyProtocolV2CommandLocal:
+		return nil
+	case proxyProtocolV2CommandProxy:
+		break
+	default:
+		return fmt.Errorf("error reading v2 header: command %d is not supported", command)
+	}
+
+	var size int
+
+	switch header[13] {
+	case proxyProtocolV2FamilyTCP4:
+		size = net.IPv4len
+	case proxyProtocolV2FamilyTCP6:
+		size = net.IPv6len
+	default:
+		// Unsupported families such as UDP and UNIX sockets retain the underlying connection addresses.
+		return nil
+	}
+
+	if len(payload) < (size*2)+4 {
+		return fmt.Errorf("error reading v2 header: address block has a length of %d which is too short", len(payload))
+	}
+
+	c.remoteAddr = &net.TCPAddr{
+		IP:   net.IP(payload[:size]),
+		Port: int(binary.BigEndian.Uint16(payload[size*2:])),
+	}
+
+	c.localAddr = &net.TCPAddr{
+		IP:   net.IP(payload[size : size*2]),
+		Port: int(binary.BigEndian.Uint16(payload[(size*2)+2:])),
+	}
+
+	return nil
+}
+
+func parseProxyProtocolV1Address(rawIP, rawPort []byte) (addr *net.TCPAddr, err error) {
+	ip := net.ParseIP(string(rawIP))
+
+	if ip == nil {
+		return nil, fmt.Errorf("could not parse ip '%s'", rawIP)
+	}
+
+	var port uint64
+
+	if port, err = strconv.ParseUint(string(rawPort), 10, 16); err != nil {
+		return nil, fmt.Errorf("could not parse port '%s': %w", rawPort, err)
+	}
+
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+var (
+	proxyProtocolV1Prefix    = []byte("PROXY ")
+	proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+	errProxyProtocolHeaderMissing = errors.New("the PROXY protocol header is required but was not sent")
+)
+
+const (
+	proxyProtocolV1MaxLength    = 107
+	proxyProtocolV2HeaderLength = 16
+
+	proxyProtocolV2CommandLocal = 0x00
+	proxyProtocolV2CommandProxy = 0x01
+
+	proxyProtocolV2FamilyTCP4 = 0x11
+	proxyProtocolV2FamilyTCP6 = 0x21
+)
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestProxyProtocolListener(t *testing.T) {
+	loopback := &net.IPNet{IP: net.IPv4(127, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}
+	other := &net.IPNet{IP: net.IPv4(192, 0, 2, 0).To4(), Mask: net.CIDRMask(24, 32)}
+
+	v2TCP4 := []byte("\r\n\r\n\x00\r\nQUIT\n\x21\x11\x00\x0c\xc6\x33\x64\x01\xc0\x00\x02\x01\xd4\x31\x01\xbb")
+	v2Local := []byte("\r\n\r\n\x00\r\nQUIT\n\x20\x00\x00\x00")
+
+	testCases := []struct {
+		name           string
+		mode           string
+		sources        []*net.IPNet
+		have           []byte
+		expectedRemote string
+		expectedData   string
+		err            string
+	}{
+		{
+			name:           "ShouldParseV1TCP4",
+			mode:           schema.ProxyProtocolModeStrict,
+			sources:        []*net.IPNet{loopback},
+			have:           []byte("PROXY TCP4 198.51.100.1 192.0.2.1 54321 443\r\nGET / HTTP/1.1\r\n"),
+			expectedRemote: "198.51.100.1:54321",
+			expectedData:   "GET / HTTP/1.1\r\n",
+		},
+		{
+			name:           "ShouldParseV1TCP6",
+			mode:           schema.ProxyProtocolModeStrict,
+			sources:        []*net.IPNet{loopback},
+			have:           []byte("PROXY TCP6 2001:db8::1 2001:db8::2 54321 443\r\nGET / HTTP/1.1\r\n"),
+			expectedRemote: "[2001:db8::1]:54321",
+			expectedData:   "GET / HTTP/1.1\r\n",
+		},
+		{
+			name:         "ShouldParseV1Unknown",
+			mode:         schema.ProxyProtocolModeStrict,
+			sources:      []*net.IPNet{loopback},
+			have:         []byte("PROXY UNKNOWN\r\nGET / HTTP/1.1\r\n"),
+			expectedData: "GET / HTTP/1.1\r\n",
+		},
+		{
+			name:           "ShouldParseV2TCP4",
+			mode:           schema.ProxyProtocolModeStrict,
+			sources:        []*net.IPNet{loopback},
+			have:           append(append([]byte{}, v2TCP4...), []byte("GET / HTTP/1.1\r\n")...),
+			expectedRemote: "198.51.100.1:54321",
+			expectedData:   "GET / HTTP/1.1\r\n",
+		},
+		{
+			name:         "ShouldParseV2Local",
+			mode:         schema.ProxyProtocolModeStrict,
+			sources:      []*net.IPNet{loopback},
+			have:         append(append([]byte{}, v2Local...), []byte("GET / HTTP/1.1\r\n")...),
+			expectedData: "GET / HTTP/1.1\r\n",
+		},
+		{
+			name:         "ShouldAcceptMissingHeaderInOptionalMode",
+			mode:         schema.ProxyProtocolModeOptional,
+			sources:      []*net.IPNet{loopback},
+			have:         []byte("GET / HTTP/1.1\r\n"),
+			expectedData: "GET / HTTP/1.1\r\n",
+		},
+		{
+			name:    "ShouldRejectMissingHeaderInStrictMode",
+			mode:    schema.ProxyProtocolModeStrict,
+			sources: []*net.IPNet{loopback},
+			have:    []byte("GET / HTTP/1.1\r\n"),
+			err:     "the PROXY protocol header is required but was not sent",
+		},
+		{
+			name:    "ShouldRejectInvalidV1Address",
+			mode:    schema.ProxyProtocolModeStrict,
+			sources: []*net.IPNet{loopback},
+			have:    []byte("PROXY TCP4 abc 192.0.2.1 54321 443\r\nGET / HTTP/1.1\r\n"),
+			err:     "error reading v1 header: source address is invalid: could not parse ip 'abc'",
+		},
+		{
+			name:    "ShouldRejectInvalidV1Protocol",
+			mode:    schema.ProxyProtocolModeStrict,
+			sources: []*net.IPNet{loopback},
+			have:    []byte("PROXY UDP4 198.51.100.1 192.0.2.1 54321 443\r\nGET / HTTP/1.1\r\n"),
+			err:     "error reading v1 header: protocol 'UDP4' is not supported",
+		},
+		{
+			name:         "ShouldNotParseHeaderFromUntrustedSource",
+			mode:         schema.ProxyProtocolModeStrict,
+			sources:      []*net.IPNet{other},
+			have:         []byte("PROXY TCP4 198.51.100.1 192.0.2.1 54321 443\r\n"),
+			expectedData: "PROXY TCP4 198.51.100.1 192.0.2.1 54321 443\r\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			listener := NewProxyProtocolListener(raw, schema.ServerProxyProtocol{
+				Enabled:        true,
+				Mode:           tc.mode,
+				TrustedSources: tc.sources,
+				Timeout:        time.Second,
+			})
+
+			defer listener.Close()
+
+			client, err := net.Dial("tcp", raw.Addr().String())
+			require.NoError(t, err)
+
+			_, err = client.Write(tc.have)
+			require.NoError(t, err)
+			require.NoError(t, client.Close())
+
+			conn, err := listener.Accept()
+			require.NoError(t, err)
+
+			defer conn.Close()
+
+			data, err := io.ReadAll(conn)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedData, string(data))
+
+			if tc.expectedRemote == "" {
+				assert.Equal(t, client.LocalAddr().String(), conn.RemoteAddr().String())
+			} else {
+				assert.Equal(t, tc.expectedRemote, conn.RemoteAddr().String())
+			}
+		})
+	}
+}
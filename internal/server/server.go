@@ -20,7 +20,7 @@ import (
 // CreateDefaultServer Create Authelia's internal webserver with the given configuration and providers.
 func CreateDefaultServer(config schema.Configuration, providers middlewares.Providers) (server *fasthttp.Server, listener net.Listener, err error) {
 	server = &fasthttp.Server{
-		ErrorHandler:          handleError(),
+		ErrorHandler:          handleError(config.Server.TrustedProxies),
 		Handler:               handleRouter(config, providers),
 		NoDefaultServerHeader: true,
 		ReadBufferSize:        config.Server.Buffers.Read,
@@ -63,16 +63,20 @@ func CreateDefaultServer(config schema.Configuration, providers middlewares.Prov
 			server.TLSConfig.ClientCAs = caCertPool
 			server.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
 		}
-
-		if listener, err = tls.Listen("tcp", address, server.TLSConfig.Clone()); err != nil {
-			return nil, nil, fmt.Errorf("unable to initialize tcp listener: %w", err)
-		}
 	} else {
 		connectionType, connectionScheme = connNonTLS, schemeHTTP
+	}
 
-		if listener, err = net.Listen("tcp", address); err != nil {
-			return nil, nil, fmt.Errorf("unable to initialize tcp listener: %w", err)
-		}
+	if listener, err = net.Listen("tcp", address); err != nil {
+		return nil, nil, fmt.Errorf("unable to initialize tcp listener: %w", err)
+	}
+
+	if config.Server.ProxyProtocol.Enabled {
+		listener = NewProxyProtocolListener(listener, config.Server.ProxyProtocol)
+	}
+
+	if connectionType == connTLS {
+		listener = tls.NewListener(listener, server.TLSConfig.Clone())
 	}
 
 	if err = writeHealthCheckEnv(config.Server.DisableHealthcheck, connectionScheme, config.Server.Host,
@@ -97,8 +101,12 @@ func CreateMetricsServer(config schema.TelemetryMetricsConfig) (server *fasthttp
 		return nil, nil, err
 	}
 
+	if config.ProxyProtocol.Enabled {
+		listener = NewProxyProtocolListener(listener, config.ProxyProtocol)
+	}
+
 	server = &fasthttp.Server{
-		ErrorHandler:          handleError(),
+		ErrorHandler:          handleError(nil),
 		NoDefaultServerHeader: true,
 		Handler:               handleMetrics(),
 		ReadBufferSize:        config.Buffers.Read,
@@ -102,6 +102,18 @@ func (tmpl *Template) Handler(data ...map[string]any) middlewares.RequestHandler
 	}
 }
 
+// setCSPFrameSources replaces the restrictive frame-src directive of the Content-Security-Policy with the provided
+// sources, which is necessary for templates which load other origins in frames such as front-channel logout.
+func setCSPFrameSources(ctx *middlewares.AutheliaCtx, sources []string) {
+	if len(sources) == 0 {
+		return
+	}
+
+	csp := string(ctx.Response.Header.Peek(fasthttp.HeaderContentSecurityPolicy))
+
+	ctx.Response.Header.Set(fasthttp.HeaderContentSecurityPolicy, strings.Replace(csp, cspFrameSrcNone, "frame-src "+strings.Join(sources, " "), 1))
+}
+
 // NewTemplate returns a new Template instance.
 func NewTemplate(config *schema.Configuration) *Template {
 	return &Template{
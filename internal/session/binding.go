@@ -0,0 +1,136 @@
+package session
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// BindClient binds the user session to each of the client characteristics enabled in the configuration which the
+// user session is not already bound to. Returns true if the binding was changed.
+func (s *UserSession) BindClient(config schema.SessionBindingConfiguration, ip net.IP, userAgent []byte) (changed bool) {
+	if config.IP && s.Binding.IP == "" {
+		s.Binding.IP, changed = bindingIP(config, ip), true
+	}
+
+	if config.UserAgent && s.Binding.UserAgent == "" {
+		s.Binding.UserAgent, changed = bindingUserAgent(userAgent), true
+	}
+
+	return changed
+}
+
+// ClientBindingViolations returns the client characteristics of the request which do not match the characteristics
+// the user session is bound to. Characteristics the user session is not yet bound to are never violated.
+func (s *UserSession) ClientBindingViolations(config schema.SessionBindingConfiguration, ip net.IP, userAgent, token []byte) (violations []string) {
+	if config.IP && s.Binding.IP != "" && s.Binding.IP != bindingIP(config, ip) {
+		violations = append(violations, BindingViolationIP)
+	}
+
+	if config.UserAgent && s.Binding.UserAgent != "" && s.Binding.UserAgent != bindingUserAgent(userAgent) {
+		violations = append(violations, BindingViolationUserAgent)
+	}
+
+	if config.AntiReplay && s.Binding.AntiReplayToken != "" && !s.isAntiReplayTokenValid(token) {
+		violations = append(violations, BindingViolationAntiReplay)
+	}
+
+	return violations
+}
+
+// ResetClientBinding removes all of the client characteristics the user session is bound to.
+func (s *UserSession) ResetClientBinding() {
+	s.Binding = ClientBinding{}
+}
+
+// IsAntiReplayTokenRotationDue returns true if the anti-replay token has never been issued or was issued at least
+// the rotation interval before now.
+func (s *UserSession) IsAntiReplayTokenRotationDue(config schema.SessionBindingConfiguration, now time.Time) bool {
+	return s.Binding.AntiReplayToken == "" || !now.Before(time.Unix(s.Binding.AntiReplayTokenRotated, 0).Add(config.AntiReplayRotationInterval))
+}
+
+// RotateAntiReplayToken issues a new anti-replay token. The current token remains valid until the next rotation so
+// concurrent requests made with the current token are not treated as a replay.
+func (s *UserSession) RotateAntiReplayToken(now time.Time) (token string) {
+	token = utils.RandomString(antiReplayTokenLength, utils.CharSetAlphaNumeric)
+
+	s.Binding.AntiReplayTokenPrevious = s.Binding.AntiReplayToken
+	s.Binding.AntiReplayToken = token
+	s.Binding.AntiReplayTokenRotated = now.Unix()
+
+	return token
+}
+
+func (s *UserSession) isAntiReplayTokenValid(token []byte) bool {
+	if len(token) == 0 {
+		return false
+	}
+
+	if subtle.ConstantTimeCompare(token, []byte(s.Binding.AntiReplayToken)) == 1 {
+		return true
+	}
+
+	return s.Binding.AntiReplayTokenPrevious != "" && subtle.ConstantTimeCompare(token, []byte(s.Binding.AntiReplayTokenPrevious)) == 1
+}
+
+// GetAntiReplayToken returns the anti-replay token sent with the request.
+func (p *Session) GetAntiReplayToken(ctx *fasthttp.RequestCtx) []byte {
+	return ctx.Request.Header.Cookie(p.antiReplayCookieName())
+}
+
+// SetAntiReplayToken sets the anti-replay token cookie on the response.
+func (p *Session) SetAntiReplayToken(ctx *fasthttp.RequestCtx, token string, expiration time.Duration) {
+	cookie := fasthttp.AcquireCookie()
+	defer fasthttp.ReleaseCookie(cookie)
+
+	cookie.SetKey(p.antiReplayCookieName())
+	cookie.SetValue(token)
+	cookie.SetDomain(p.Config.Domain)
+	cookie.SetPath("/")
+	cookie.SetHTTPOnly(true)
+	cookie.SetSecure(true)
+
+	switch p.Config.SameSite {
+	case "strict":
+		cookie.SetSameSite(fasthttp.CookieSameSiteStrictMode)
+	case "none":
+		cookie.SetSameSite(fasthttp.CookieSameSiteNoneMode)
+	default:
+		cookie.SetSameSite(fasthttp.CookieSameSiteLaxMode)
+	}
+
+	if expiration > 0 {
+		cookie.SetMaxAge(int(expiration.Seconds()))
+	}
+
+	ctx.Response.Header.SetCookie(cookie)
+}
+
+func (p *Session) antiReplayCookieName() string {
+	return p.Config.Name + antiReplayCookieSuffix
+}
+
+func bindingIP(config schema.SessionBindingConfiguration, ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+
+	if ipv4 := ip.To4(); ipv4 != nil {
+		return (&net.IPNet{IP: ipv4.Mask(net.CIDRMask(config.IPv4Mask, 32)), Mask: net.CIDRMask(config.IPv4Mask, 32)}).String()
+	}
+
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(config.IPv6Mask, 128)), Mask: net.CIDRMask(config.IPv6Mask, 128)}).String()
+}
+
+func bindingUserAgent(userAgent []byte) string {
+	sum := sha256.Sum256(userAgent)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestShouldBindClientCharacteristics(t *testing.T) {
+	config := schema.SessionBindingConfiguration{IP: true, IPv4Mask: 24, IPv6Mask: 64, UserAgent: true}
+
+	userSession := NewDefaultUserSession()
+
+	assert.True(t, userSession.BindClient(config, net.ParseIP("192.168.1.20"), []byte("agent")))
+	assert.False(t, userSession.BindClient(config, net.ParseIP("10.0.0.1"), []byte("other")))
+
+	assert.Equal(t, "192.168.1.0/24", userSession.Binding.IP)
+	assert.Equal(t, "d4f0bc5a29de06b510f9aa428f1eedba926012b591fef7a518e776a7c9bd1824", userSession.Binding.UserAgent)
+
+	assert.Len(t, userSession.ClientBindingViolations(config, net.ParseIP("192.168.1.200"), []byte("agent"), nil), 0)
+	assert.Equal(t, []string{BindingViolationIP}, userSession.ClientBindingViolations(config, net.ParseIP("192.168.2.20"), []byte("agent"), nil))
+	assert.Equal(t, []string{BindingViolationIP, BindingViolationUserAgent}, userSession.ClientBindingViolations(config, net.ParseIP("10.0.0.1"), []byte("other"), nil))
+
+	userSession.ResetClientBinding()
+
+	assert.Len(t, userSession.ClientBindingViolations(config, net.ParseIP("10.0.0.1"), []byte("other"), nil), 0)
+}
+
+func TestShouldBindClientIPv6Prefix(t *testing.T) {
+	config := schema.SessionBindingConfiguration{IP: true, IPv4Mask: 32, IPv6Mask: 48}
+
+	userSession := NewDefaultUserSession()
+
+	require.True(t, userSession.BindClient(config, net.ParseIP("2001:db8:abcd:12::1"), nil))
+
+	assert.Equal(t, "2001:db8:abcd::/48", userSession.Binding.IP)
+	assert.Len(t, userSession.ClientBindingViolations(config, net.ParseIP("2001:db8:abcd:ff::2"), nil, nil), 0)
+	assert.Equal(t, []string{BindingViolationIP}, userSession.ClientBindingViolations(config, net.ParseIP("2001:db8:abce::1"), nil, nil))
+	assert.Equal(t, []string{BindingViolationIP}, userSession.ClientBindingViolations(config, net.ParseIP("192.168.1.20"), nil, nil))
+}
+
+func TestShouldRotateAntiReplayToken(t *testing.T) {
+	config := schema.SessionBindingConfiguration{AntiReplay: true, AntiReplayRotationInterval: time.Minute}
+	now := time.Unix(1600000000, 0)
+
+	userSession := NewDefaultUserSession()
+
+	assert.True(t, userSession.IsAntiReplayTokenRotationDue(config, now))
+	assert.Len(t, userSession.ClientBindingViolations(config, nil, nil, nil), 0)
+
+	first := userSession.RotateAntiReplayToken(now)
+
+	assert.Len(t, first, antiReplayTokenLength)
+	assert.False(t, userSession.IsAntiReplayTokenRotationDue(config, now.Add(time.Second*59)))
+	assert.True(t, userSession.IsAntiReplayTokenRotationDue(config, now.Add(time.Minute)))
+
+	assert.Len(t, userSession.ClientBindingViolations(config, nil, nil, []byte(first)), 0)
+	assert.Equal(t, []string{BindingViolationAntiReplay}, userSession.ClientBindingViolations(config, nil, nil, nil))
+	assert.Equal(t, []string{BindingViolationAntiReplay}, userSession.ClientBindingViolations(config, nil, nil, []byte("invalid")))
+
+	second := userSession.RotateAntiReplayToken(now.Add(time.Minute))
+
+	assert.NotEqual(t, first, second)
+	assert.Len(t, userSession.ClientBindingViolations(config, nil, nil, []byte(first)), 0)
+	assert.Len(t, userSession.ClientBindingViolations(config, nil, nil, []byte(second)), 0)
+
+	userSession.RotateAntiReplayToken(now.Add(time.Minute * 2))
+
+	assert.Equal(t, []string{BindingViolationAntiReplay}, userSession.ClientBindingViolations(config, nil, nil, []byte(first)))
+}
+
+func TestShouldSetAntiReplayTokenCookie(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.SameSite = "strict"
+
+	provider := NewProvider(configuration, nil, nil).sessions[testDomain]
+
+	ctx := &fasthttp.RequestCtx{}
+
+	provider.SetAntiReplayToken(ctx, "abc123", time.Hour)
+
+	cookie := &fasthttp.Cookie{}
+	require.NoError(t, cookie.ParseBytes(ctx.Response.Header.PeekCookie(testName+"_binding")))
+
+	assert.Equal(t, "abc123", string(cookie.Value()))
+	assert.Equal(t, testDomain, string(cookie.Domain()))
+	assert.True(t, cookie.HTTPOnly())
+	assert.True(t, cookie.Secure())
+	assert.Equal(t, fasthttp.CookieSameSiteStrictMode, cookie.SameSite())
+	assert.Equal(t, 3600, cookie.MaxAge())
+
+	next := &fasthttp.RequestCtx{}
+	next.Request.Header.SetCookie(testName+"_binding", "abc123")
+
+	assert.Equal(t, []byte("abc123"), provider.GetAntiReplayToken(next))
+}
@@ -12,6 +12,27 @@ const (
 )
 
 const (
-	userSessionStorerKey = "UserSession"
-	randomSessionChars   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_!#$%^*"
+	userSessionStorerKey    = "UserSession"
+	sessionVersionStorerKey = "SessionVersion"
+	randomSessionChars      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_!#$%^*"
+)
+
+const (
+	sqlSessionVersionLength = 8
+
+	// updateSessionAttempts is the maximum number of times UpdateSession attempts to save a session which is
+	// concurrently saved by other requests.
+	updateSessionAttempts = 3
+)
+
+const (
+	antiReplayCookieSuffix = "_binding"
+	antiReplayTokenLength  = 64
+)
+
+// Session binding violations.
+const (
+	BindingViolationIP         = "ip"
+	BindingViolationUserAgent  = "user agent"
+	BindingViolationAntiReplay = "anti-replay token"
 )
@@ -1,7 +1,6 @@
 package session
 
 import (
-	"crypto/sha256"
 	"fmt"
 
 	"github.com/fasthttp/session/v2"
@@ -9,15 +8,16 @@ import (
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
-// EncryptingSerializer a serializer encrypting the data with AES-GCM with 256-bit keys.
+// EncryptingSerializer a serializer encrypting the data with AES-GCM with 256-bit keys. The data is always encrypted
+// with the primary secret, and data encrypted with any of the previous secrets is accepted so it's encrypted with the
+// primary secret the next time the session is saved.
 type EncryptingSerializer struct {
-	key [32]byte
+	keyring *utils.Keyring
 }
 
 // NewEncryptingSerializer return new encrypt instance.
-func NewEncryptingSerializer(secret string) *EncryptingSerializer {
-	key := sha256.Sum256([]byte(secret))
-	return &EncryptingSerializer{key}
+func NewEncryptingSerializer(secret string, previous ...string) *EncryptingSerializer {
+	return &EncryptingSerializer{utils.NewKeyringFromSecrets(secret, previous...)}
 }
 
 // Encode encode and encrypt session.
@@ -31,7 +31,7 @@ func (e *EncryptingSerializer) Encode(src session.Dict) ([]byte, error) {
 		return nil, fmt.Errorf("unable to marshal session: %v", err)
 	}
 
-	encryptedDst, err := utils.Encrypt(dst, &e.key)
+	encryptedDst, err := e.keyring.Encrypt(dst)
 	if err != nil {
 		return nil, fmt.Errorf("unable to encrypt session: %v", err)
 	}
@@ -47,7 +47,7 @@ func (e *EncryptingSerializer) Decode(dst *session.Dict, src []byte) error {
 
 	dst.Reset()
 
-	decryptedSrc, err := utils.Decrypt(src, &e.key)
+	decryptedSrc, err := e.keyring.Decrypt(src)
 	if err != nil {
 		return fmt.Errorf("unable to decrypt session: %s", err)
 	}
@@ -1,11 +1,14 @@
 package session
 
 import (
+	"crypto/sha256"
 	"testing"
 
 	"github.com/fasthttp/session/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 func TestShouldEncryptAndDecrypt(t *testing.T) {
@@ -28,6 +31,41 @@ func TestShouldEncryptAndDecrypt(t *testing.T) {
 	assert.Equal(t, "value", decodedPayload.Get("key"))
 }
 
+func TestShouldDecryptWithPreviousSecrets(t *testing.T) {
+	payload := session.Dict{}
+	payload.Set("key", "value")
+
+	previous, err := NewEncryptingSerializer("apreviousecret").Encode(payload)
+	require.NoError(t, err)
+
+	key := sha256.Sum256([]byte("alegacysecret"))
+
+	dst, err := payload.MarshalMsg(nil)
+	require.NoError(t, err)
+
+	legacy, err := utils.Encrypt(dst, &key)
+	require.NoError(t, err)
+
+	serializer := NewEncryptingSerializer("asecret", "apreviousecret", "alegacysecret")
+
+	for _, src := range [][]byte{previous, legacy} {
+		decodedPayload := session.Dict{}
+		require.NoError(t, serializer.Decode(&decodedPayload, src))
+
+		assert.Equal(t, "value", decodedPayload.Get("key"))
+	}
+
+	encryptedDst, err := serializer.Encode(payload)
+	require.NoError(t, err)
+
+	decodedPayload := session.Dict{}
+	require.NoError(t, NewEncryptingSerializer("asecret").Decode(&decodedPayload, encryptedDst))
+	assert.Equal(t, "value", decodedPayload.Get("key"))
+
+	err = NewEncryptingSerializer("apreviousecret").Decode(&decodedPayload, encryptedDst)
+	assert.EqualError(t, err, "unable to decrypt session: cipher: message authentication failed")
+}
+
 func TestShouldNotSupportUnencryptedSessionForBackwardCompatibility(t *testing.T) {
 	payload := session.Dict{}
 	payload.Set("key", "value")
@@ -2,36 +2,33 @@ package session
 
 import (
 	"crypto/x509"
-	"encoding/json"
-	"time"
+	"fmt"
+	"strings"
 
 	fasthttpsession "github.com/fasthttp/session/v2"
 	"github.com/fasthttp/session/v2/providers/memory"
 	"github.com/fasthttp/session/v2/providers/redis"
-	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/model"
 )
 
-// Provider a session provider.
+// Provider contains the sessions for each of the configured session cookie domains.
 type Provider struct {
-	sessionHolder *fasthttpsession.Session
-	RememberMe    time.Duration
-	Inactivity    time.Duration
+	Config schema.SessionConfiguration
+
+	provider fasthttpsession.Provider
+	sessions map[string]*Session
 }
 
-// NewProvider instantiate a session provider given a configuration.
-func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *Provider {
+// NewProvider instantiate a session provider given a configuration. The storage is only used when the SQL session
+// store is enabled.
+func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool, storage SQLStorage) *Provider {
 	c := NewProviderConfig(config, certPool)
 
-	provider := new(Provider)
-	provider.sessionHolder = fasthttpsession.New(c.config)
-
 	logger := logging.Logger()
 
-	provider.Inactivity, provider.RememberMe = config.Inactivity, config.RememberMeDuration
-
 	var (
 		providerImpl fasthttpsession.Provider
 		err          error
@@ -43,11 +40,15 @@ func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *P
 		if err != nil {
 			logger.Fatal(err)
 		}
+	case c.redisClusterConfig != nil:
+		providerImpl = NewRedisClusterProvider(*c.redisClusterConfig)
 	case c.redisSentinelConfig != nil:
 		providerImpl, err = redis.NewFailoverCluster(*c.redisSentinelConfig)
 		if err != nil {
 			logger.Fatal(err)
 		}
+	case c.sqlConfig != nil:
+		providerImpl = NewSQLProvider(storage)
 	default:
 		providerImpl, err = memory.New(memory.Config{})
 		if err != nil {
@@ -55,105 +56,78 @@ func NewProvider(config schema.SessionConfiguration, certPool *x509.CertPool) *P
 		}
 	}
 
-	err = provider.sessionHolder.SetProvider(providerImpl)
-	if err != nil {
-		logger.Fatal(err)
-	}
-
-	return provider
-}
-
-// GetSession return the user session from a request.
-func (p *Provider) GetSession(ctx *fasthttp.RequestCtx) (UserSession, error) {
-	store, err := p.sessionHolder.Get(ctx)
-
-	if err != nil {
-		return NewDefaultUserSession(), err
+	provider := &Provider{
+		Config:   config,
+		provider: providerImpl,
+		sessions: map[string]*Session{},
 	}
 
-	userSessionJSON, ok := store.Get(userSessionStorerKey).([]byte)
+	for _, cookie := range config.GetCookies() {
+		cookieConfig := NewCookieConfig(cookie)
 
-	// If userSession is not yet defined we create the new session with default values
-	// and save it in the store.
-	if !ok {
-		userSession := NewDefaultUserSession()
+		cookieConfig.EncodeFunc, cookieConfig.DecodeFunc = c.config.EncodeFunc, c.config.DecodeFunc
+		cookieConfig.GCLifetime = c.config.GCLifetime
 
-		store.Set(userSessionStorerKey, userSession)
+		holder := fasthttpsession.New(cookieConfig)
 
-		return userSession, nil
-	}
-
-	var userSession UserSession
-	err = json.Unmarshal(userSessionJSON, &userSession)
+		if err = holder.SetProvider(providerImpl); err != nil {
+			logger.Fatal(err)
+		}
 
-	if err != nil {
-		return NewDefaultUserSession(), err
+		provider.sessions[strings.ToLower(cookie.Domain)] = &Session{
+			Config:        cookie,
+			sessionHolder: holder,
+		}
 	}
 
-	return userSession, nil
+	return provider
 }
 
-// SaveSession save the user session.
-func (p *Provider) SaveSession(ctx *fasthttp.RequestCtx, userSession UserSession) error {
-	store, err := p.sessionHolder.Get(ctx)
-
-	if err != nil {
-		return err
-	}
-
-	userSessionJSON, err := json.Marshal(userSession)
-
-	if err != nil {
-		return err
-	}
-
-	store.Set(userSessionStorerKey, userSessionJSON)
-
-	err = p.sessionHolder.Save(ctx, store)
-
-	if err != nil {
-		return err
+// StartupCheck implements the startup check provider interface. Only session providers which implement the startup
+// check provider interface are checked.
+func (p *Provider) StartupCheck() (err error) {
+	if check, ok := p.provider.(model.StartupCheck); ok {
+		return check.StartupCheck()
 	}
 
 	return nil
 }
 
-// RegenerateSession regenerate a session ID.
-func (p *Provider) RegenerateSession(ctx *fasthttp.RequestCtx) error {
-	err := p.sessionHolder.Regenerate(ctx)
-
-	return err
-}
-
-// DestroySession destroy a session ID and delete the cookie.
-func (p *Provider) DestroySession(ctx *fasthttp.RequestCtx) error {
-	return p.sessionHolder.Destroy(ctx)
-}
+// Get returns the *Session for the session cookie domain which the provided domain is equal to or a subdomain of. If
+// more than one session cookie domain matches the most specific one is returned. If there is only a single session
+// cookie domain configured it's always returned.
+func (p *Provider) Get(domain string) (*Session, error) {
+	domain = strings.ToLower(domain)
 
-// UpdateExpiration update the expiration of the cookie and session.
-func (p *Provider) UpdateExpiration(ctx *fasthttp.RequestCtx, expiration time.Duration) error {
-	store, err := p.sessionHolder.Get(ctx)
+	var (
+		match  *Session
+		length int
+	)
 
-	if err != nil {
-		return err
+	for cookieDomain, session := range p.sessions {
+		if (domain == cookieDomain || strings.HasSuffix(domain, "."+cookieDomain)) && len(cookieDomain) > length {
+			match, length = session, len(cookieDomain)
+		}
 	}
 
-	err = store.SetExpiration(expiration)
+	if match != nil {
+		return match, nil
+	}
 
-	if err != nil {
-		return err
+	if len(p.sessions) == 1 {
+		for _, session := range p.sessions {
+			return session, nil
+		}
 	}
 
-	return p.sessionHolder.Save(ctx, store)
+	return nil, fmt.Errorf("no session cookie domain is configured for the domain '%s'", domain)
 }
 
-// GetExpiration get the expiration of the current session.
-func (p *Provider) GetExpiration(ctx *fasthttp.RequestCtx) (time.Duration, error) {
-	store, err := p.sessionHolder.Get(ctx)
-
-	if err != nil {
-		return time.Duration(0), err
+// GetDomains returns the list of configured session cookie domains.
+func (p *Provider) GetDomains() (domains []string) {
+	for _, cookie := range p.Config.GetCookies() {
+		domains = append(domains, cookie.Domain)
 	}
 
-	return store.GetExpiration(), nil
+	return domains
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/fasthttp/session/v2"
 	"github.com/fasthttp/session/v2/providers/redis"
+	goredis "github.com/go-redis/redis/v8"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 
@@ -19,58 +20,27 @@ import (
 
 // NewProviderConfig creates a configuration for creating the session provider.
 func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPool) ProviderConfig {
-	c := session.NewDefaultConfig()
-
-	c.SessionIDGeneratorFunc = func() []byte {
-		bytes := make([]byte, 32)
-
-		_, _ = rand.Read(bytes)
-
-		for i, b := range bytes {
-			bytes[i] = randomSessionChars[b%byte(len(randomSessionChars))]
-		}
-
-		return bytes
-	}
-
-	// Override the cookie name.
-	c.CookieName = config.Name
-
-	// Set the cookie to the given domain.
-	c.Domain = config.Domain
-
-	// Set the cookie SameSite option.
-	switch config.SameSite {
-	case "strict":
-		c.CookieSameSite = fasthttp.CookieSameSiteStrictMode
-	case "none":
-		c.CookieSameSite = fasthttp.CookieSameSiteNoneMode
-	case "lax":
-		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
-	default:
-		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
-	}
-
-	// Only serve the header over HTTPS.
-	c.Secure = true
-
-	// Ignore the error as it will be handled by validator.
-	c.Expiration = config.Expiration
-
-	c.IsSecureFunc = func(*fasthttp.RequestCtx) bool {
-		return true
-	}
+	c := NewCookieConfig(schema.SessionCookieConfiguration{
+		Name:       config.Name,
+		Domain:     config.Domain,
+		SameSite:   config.SameSite,
+		Expiration: config.Expiration,
+	})
 
 	var redisConfig *redis.Config
 
 	var redisSentinelConfig *redis.FailoverConfig
 
+	var redisClusterConfig *RedisClusterConfig
+
+	var sqlConfig *schema.SessionSQLConfiguration
+
 	var providerName string
 
 	// If redis configuration is provided, then use the redis provider.
 	switch {
 	case config.Redis != nil:
-		serializer := NewEncryptingSerializer(config.Secret)
+		serializer := NewEncryptingSerializer(config.Secret, config.PreviousSecrets...)
 
 		var tlsConfig *tls.Config
 
@@ -78,7 +48,38 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 			tlsConfig = utils.NewTLSConfig(config.Redis.TLS, certPool)
 		}
 
-		if config.Redis.HighAvailability != nil && config.Redis.HighAvailability.SentinelName != "" {
+		switch {
+		case config.Redis.Cluster != nil:
+			addrs := make([]string, 0)
+
+			if config.Redis.Host != "" {
+				addrs = append(addrs, fmt.Sprintf("%s:%d", strings.ToLower(config.Redis.Host), config.Redis.Port))
+			}
+
+			for _, node := range config.Redis.Cluster.Nodes {
+				addr := fmt.Sprintf("%s:%d", strings.ToLower(node.Host), node.Port)
+				if !utils.IsStringInSlice(addr, addrs) {
+					addrs = append(addrs, addr)
+				}
+			}
+
+			providerName = "redis-cluster"
+			redisClusterConfig = &RedisClusterConfig{
+				Options: goredis.ClusterOptions{
+					Addrs:          addrs,
+					RouteByLatency: config.Redis.Cluster.RouteByLatency,
+					RouteRandomly:  config.Redis.Cluster.RouteRandomly,
+					Username:       config.Redis.Username,
+					Password:       config.Redis.Password,
+					PoolSize:       config.Redis.MaximumActiveConnections,
+					MinIdleConns:   config.Redis.MinimumIdleConnections,
+					IdleTimeout:    300,
+					TLSConfig:      tlsConfig,
+				},
+				Logger:    logging.LoggerCtxPrintf(logrus.TraceLevel),
+				KeyPrefix: "authelia-session",
+			}
+		case config.Redis.HighAvailability != nil && config.Redis.HighAvailability.SentinelName != "":
 			addrs := make([]string, 0)
 
 			if config.Redis.Host != "" {
@@ -110,7 +111,7 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 				TLSConfig:        tlsConfig,
 				KeyPrefix:        "authelia-session",
 			}
-		} else {
+		default:
 			providerName = "redis"
 			network := "tcp"
 
@@ -140,6 +141,17 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 
 		c.EncodeFunc = serializer.Encode
 		c.DecodeFunc = serializer.Decode
+	case config.SQL.Enabled:
+		serializer := NewEncryptingSerializer(config.Secret, config.PreviousSecrets...)
+
+		providerName = "sql"
+		sqlConfig = &config.SQL
+
+		sqlSerializer := NewSQLSerializer(serializer.Encode, serializer.Decode)
+
+		c.EncodeFunc = sqlSerializer.Encode
+		c.DecodeFunc = sqlSerializer.Decode
+		c.GCLifetime = config.SQL.CleanupInterval
 	default:
 		providerName = "memory"
 	}
@@ -148,6 +160,55 @@ func NewProviderConfig(config schema.SessionConfiguration, certPool *x509.CertPo
 		c,
 		redisConfig,
 		redisSentinelConfig,
+		redisClusterConfig,
+		sqlConfig,
 		providerName,
 	}
 }
+
+// NewCookieConfig creates the fasthttp session configuration for a session cookie.
+func NewCookieConfig(config schema.SessionCookieConfiguration) (c session.Config) {
+	c = session.NewDefaultConfig()
+
+	c.SessionIDGeneratorFunc = func() []byte {
+		bytes := make([]byte, 32)
+
+		_, _ = rand.Read(bytes)
+
+		for i, b := range bytes {
+			bytes[i] = randomSessionChars[b%byte(len(randomSessionChars))]
+		}
+
+		return bytes
+	}
+
+	// Override the cookie name.
+	c.CookieName = config.Name
+
+	// Set the cookie to the given domain.
+	c.Domain = config.Domain
+
+	// Set the cookie SameSite option.
+	switch config.SameSite {
+	case "strict":
+		c.CookieSameSite = fasthttp.CookieSameSiteStrictMode
+	case "none":
+		c.CookieSameSite = fasthttp.CookieSameSiteNoneMode
+	case "lax":
+		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
+	default:
+		c.CookieSameSite = fasthttp.CookieSameSiteLaxMode
+	}
+
+	// Only serve the header over HTTPS.
+	c.Secure = true
+
+	// Ignore the error as it will be handled by validator.
+	c.Expiration = config.Expiration
+
+	c.IsSecureFunc = func(*fasthttp.RequestCtx) bool {
+		return true
+	}
+
+	return c
+}
@@ -1,7 +1,6 @@
 package session
 
 import (
-	"crypto/sha256"
 	"crypto/tls"
 	"testing"
 	"time"
@@ -31,6 +30,24 @@ func TestShouldCreateInMemorySessionProvider(t *testing.T) {
 	assert.Equal(t, "memory", providerConfig.providerName)
 }
 
+func TestShouldCreateSQLSessionProvider(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.Secret = "abc"
+	configuration.SQL = schema.SessionSQLConfiguration{Enabled: true, CleanupInterval: time.Minute * 10}
+	providerConfig := NewProviderConfig(configuration, nil)
+
+	assert.Nil(t, providerConfig.redisConfig)
+	assert.Nil(t, providerConfig.redisSentinelConfig)
+	require.NotNil(t, providerConfig.sqlConfig)
+	assert.Equal(t, "sql", providerConfig.providerName)
+	assert.Equal(t, time.Minute*10, providerConfig.config.GCLifetime)
+	assert.NotNil(t, providerConfig.config.EncodeFunc)
+	assert.NotNil(t, providerConfig.config.DecodeFunc)
+}
+
 func TestShouldCreateRedisSessionProviderTLS(t *testing.T) {
 	configuration := schema.SessionConfiguration{}
 	configuration.Domain = testDomain
@@ -185,6 +202,64 @@ func TestShouldCreateRedisSentinelSessionProvider(t *testing.T) {
 	assert.Nil(t, pConfig.TLSConfig)
 }
 
+func TestShouldCreateRedisClusterSessionProvider(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.Redis = &schema.RedisSessionConfiguration{
+		Host:                     "REDIS.example.com",
+		Port:                     6379,
+		Username:                 "authelia",
+		Password:                 "pass",
+		MaximumActiveConnections: 8,
+		MinimumIdleConnections:   2,
+		TLS: &schema.TLSConfig{
+			ServerName:     "redis.fqdn.example.com",
+			MinimumVersion: schema.TLSVersion{Value: tls.VersionTLS13},
+		},
+		Cluster: &schema.RedisClusterConfiguration{
+			Nodes: []schema.RedisNode{
+				{
+					Host: "redis2.example.com",
+					Port: 6379,
+				},
+				{
+					Host: "redis.example.com",
+					Port: 6379,
+				},
+			},
+			RouteByLatency: true,
+			RouteRandomly:  true,
+		},
+	}
+	providerConfig := NewProviderConfig(configuration, nil)
+
+	assert.Nil(t, providerConfig.redisConfig)
+	assert.Nil(t, providerConfig.redisSentinelConfig)
+	assert.Equal(t, "redis-cluster", providerConfig.providerName)
+
+	require.NotNil(t, providerConfig.redisClusterConfig)
+
+	pConfig := providerConfig.redisClusterConfig
+	assert.Equal(t, []string{"redis.example.com:6379", "redis2.example.com:6379"}, pConfig.Options.Addrs)
+	assert.Equal(t, "authelia", pConfig.Options.Username)
+	assert.Equal(t, "pass", pConfig.Options.Password)
+	assert.True(t, pConfig.Options.RouteByLatency)
+	assert.True(t, pConfig.Options.RouteRandomly)
+	assert.Equal(t, 8, pConfig.Options.PoolSize)
+	assert.Equal(t, 2, pConfig.Options.MinIdleConns)
+	assert.Equal(t, "authelia-session", pConfig.KeyPrefix)
+	assert.NotNil(t, pConfig.Logger)
+
+	require.NotNil(t, pConfig.Options.TLSConfig)
+	assert.Equal(t, uint16(tls.VersionTLS13), pConfig.Options.TLSConfig.MinVersion)
+	assert.Equal(t, "redis.fqdn.example.com", pConfig.Options.TLSConfig.ServerName)
+
+	assert.NotNil(t, providerConfig.config.EncodeFunc)
+	assert.NotNil(t, providerConfig.config.DecodeFunc)
+}
+
 func TestShouldSetCookieSameSite(t *testing.T) {
 	configuration := schema.SessionConfiguration{}
 	configuration.Domain = testDomain
@@ -278,8 +353,7 @@ func TestShouldUseEncryptingSerializerWithRedis(t *testing.T) {
 	require.NoError(t, err)
 
 	// Now we try to decrypt what has been serialized.
-	key := sha256.Sum256([]byte("abc"))
-	decrypted, err := utils.Decrypt(encoded, &key)
+	decrypted, err := utils.NewKeyringFromSecrets("abc").Decrypt(encoded)
 	require.NoError(t, err)
 
 	decoded := session.Dict{}
@@ -0,0 +1,139 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+)
+
+// NewRedisClusterProvider creates a new RedisClusterProvider which speaks the Redis Cluster protocol.
+func NewRedisClusterProvider(config RedisClusterConfig) *RedisClusterProvider {
+	if config.Logger != nil {
+		goredis.SetLogger(config.Logger)
+	}
+
+	return &RedisClusterProvider{
+		keyPrefix: config.KeyPrefix,
+		db:        goredis.NewClusterClient(&config.Options),
+	}
+}
+
+// RedisClusterProvider is a fasthttp/session Provider which stores the session data in a Redis Cluster.
+type RedisClusterProvider struct {
+	keyPrefix string
+	db        *goredis.ClusterClient
+}
+
+func (p *RedisClusterProvider) key(id []byte) string {
+	return p.keyPrefix + ":" + string(id)
+}
+
+// Get returns the data for the given session id. If the session does not exist nil is returned.
+func (p *RedisClusterProvider) Get(id []byte) (data []byte, err error) {
+	if data, err = p.db.Get(context.Background(), p.key(id)).Bytes(); err != nil && err != goredis.Nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// Save saves the session data and expiration for the given session id.
+func (p *RedisClusterProvider) Save(id, data []byte, expiration time.Duration) (err error) {
+	return p.db.Set(context.Background(), p.key(id), data, expiration).Err()
+}
+
+// Regenerate moves the session data from the given session id to the new session id and updates the expiration. The
+// keys are likely to be in different hash slots so the data is copied and the original key deleted instead of renamed.
+func (p *RedisClusterProvider) Regenerate(id, newID []byte, expiration time.Duration) (err error) {
+	ctx := context.Background()
+
+	var data []byte
+
+	if data, err = p.db.Get(ctx, p.key(id)).Bytes(); err != nil {
+		if err == goredis.Nil {
+			return nil
+		}
+
+		return err
+	}
+
+	if err = p.db.Set(ctx, p.key(newID), data, expiration).Err(); err != nil {
+		return err
+	}
+
+	return p.db.Del(ctx, p.key(id)).Err()
+}
+
+// Destroy destroys the session data for the given session id.
+func (p *RedisClusterProvider) Destroy(id []byte) (err error) {
+	return p.db.Del(context.Background(), p.key(id)).Err()
+}
+
+// Count returns the number of sessions stored across all of the master nodes of the cluster.
+func (p *RedisClusterProvider) Count() int {
+	var count int64
+
+	err := p.db.ForEachMaster(context.Background(), func(ctx context.Context, client *goredis.Client) error {
+		iter := client.Scan(ctx, 0, p.key([]byte("*")), 0).Iterator()
+
+		for iter.Next(ctx) {
+			atomic.AddInt64(&count, 1)
+		}
+
+		return iter.Err()
+	})
+
+	if err != nil {
+		return 0
+	}
+
+	return int(count)
+}
+
+// NeedGC indicates the provider does not need the expired session data to be removed as Redis handles expiration.
+func (p *RedisClusterProvider) NeedGC() bool {
+	return false
+}
+
+// GC does nothing as Redis handles expiration.
+func (p *RedisClusterProvider) GC() (err error) {
+	return nil
+}
+
+// StartupCheck implements the startup check provider interface. It checks the cluster reports a healthy state and
+// each shard of the cluster is reachable.
+func (p *RedisClusterProvider) StartupCheck() (err error) {
+	ctx := context.Background()
+
+	var info string
+
+	if info, err = p.db.ClusterInfo(ctx).Result(); err != nil {
+		return fmt.Errorf("error retrieving redis cluster info: %w", err)
+	}
+
+	if state := redisClusterInfoValue(info, "cluster_state"); state != "ok" {
+		return fmt.Errorf("error checking redis cluster health: the cluster state is '%s' but it should be 'ok'", state)
+	}
+
+	return p.db.ForEachShard(ctx, func(ctx context.Context, client *goredis.Client) (err error) {
+		if err = client.Ping(ctx).Err(); err != nil {
+			return fmt.Errorf("error pinging redis cluster node '%s': %w", client.Options().Addr, err)
+		}
+
+		return nil
+	})
+}
+
+func redisClusterInfoValue(info, key string) (value string) {
+	for _, line := range strings.Split(info, "\n") {
+		if k, v, found := strings.Cut(strings.TrimSpace(line), ":"); found && k == key {
+			return v
+		}
+	}
+
+	return ""
+}
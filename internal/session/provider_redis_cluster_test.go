@@ -0,0 +1,376 @@
+package session
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestRedisClusterInfoValue(t *testing.T) {
+	info := "cluster_state:fail\r\ncluster_slots_assigned:16384\r\ncluster_slots_ok:16384\r\ncluster_known_nodes:6\r\n"
+
+	assert.Equal(t, "fail", redisClusterInfoValue(info, "cluster_state"))
+	assert.Equal(t, "6", redisClusterInfoValue(info, "cluster_known_nodes"))
+	assert.Equal(t, "", redisClusterInfoValue(info, "cluster_size"))
+	assert.Equal(t, "", redisClusterInfoValue("", "cluster_state"))
+}
+
+func TestRedisClusterProviderShouldFailStartupCheckWhenUnreachable(t *testing.T) {
+	provider := NewRedisClusterProvider(RedisClusterConfig{
+		Options: goredis.ClusterOptions{
+			Addrs:       []string{"127.0.0.1:1"},
+			DialTimeout: time.Second,
+			MaxRetries:  -1,
+		},
+		KeyPrefix: "authelia-session",
+	})
+
+	assert.False(t, provider.NeedGC())
+	assert.NoError(t, provider.GC())
+	assert.Equal(t, "authelia-session:abc", provider.key([]byte("abc")))
+
+	err := provider.StartupCheck()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error retrieving redis cluster info: ")
+}
+
+func TestRedisClusterProvider(t *testing.T) {
+	server := newTestRedisClusterServer(t)
+
+	provider := NewRedisClusterProvider(RedisClusterConfig{
+		Options: goredis.ClusterOptions{
+			Addrs: []string{server.addr()},
+		},
+		KeyPrefix: "authelia-session",
+	})
+
+	data, err := provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, provider.Save([]byte("abc"), []byte("data"), time.Hour))
+
+	assert.Equal(t, []byte("data"), server.get("authelia-session:abc"))
+	assert.Equal(t, time.Hour, server.expiration("authelia-session:abc"))
+
+	data, err = provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("data"), data)
+
+	server.set("other:abc", []byte("other"))
+
+	assert.Equal(t, 1, provider.Count())
+
+	require.NoError(t, provider.Regenerate([]byte("abc"), []byte("def"), time.Minute))
+
+	assert.Nil(t, server.get("authelia-session:abc"))
+	assert.Equal(t, []byte("data"), server.get("authelia-session:def"))
+	assert.Equal(t, time.Minute, server.expiration("authelia-session:def"))
+
+	// Regenerating a session which doesn't exist does nothing.
+	require.NoError(t, provider.Regenerate([]byte("abc"), []byte("ghi"), time.Minute))
+	assert.Nil(t, server.get("authelia-session:ghi"))
+
+	require.NoError(t, provider.Destroy([]byte("def")))
+
+	assert.Nil(t, server.get("authelia-session:def"))
+	assert.Equal(t, []byte("other"), server.get("other:abc"))
+	assert.Equal(t, 0, provider.Count())
+}
+
+func TestRedisClusterProviderStartupCheck(t *testing.T) {
+	server := newTestRedisClusterServer(t)
+
+	provider := NewRedisClusterProvider(RedisClusterConfig{
+		Options: goredis.ClusterOptions{
+			Addrs: []string{server.addr()},
+		},
+		KeyPrefix: "authelia-session",
+	})
+
+	assert.NoError(t, provider.StartupCheck())
+
+	server.setState("fail")
+
+	assert.EqualError(t, provider.StartupCheck(), "error checking redis cluster health: the cluster state is 'fail' but it should be 'ok'")
+}
+
+func TestShouldUseRedisClusterProviderForSessions(t *testing.T) {
+	server := newTestRedisClusterServer(t)
+
+	host, port, err := net.SplitHostPort(server.addr())
+	require.NoError(t, err)
+
+	p, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.Redis = &schema.RedisSessionConfiguration{
+		Cluster: &schema.RedisClusterConfiguration{
+			Nodes: []schema.RedisNode{{Host: host, Port: p}},
+		},
+	}
+
+	provider := NewProvider(configuration, nil, nil)
+
+	assert.IsType(t, &RedisClusterProvider{}, provider.provider)
+	assert.NoError(t, provider.StartupCheck())
+
+	ctx := &fasthttp.RequestCtx{}
+
+	session, err := provider.sessions[testDomain].GetSession(ctx)
+	require.NoError(t, err)
+
+	session.Username = testUsername
+	session.AuthenticationLevel = authentication.OneFactor
+
+	require.NoError(t, provider.sessions[testDomain].SaveSession(ctx, session))
+
+	assert.Equal(t, 1, server.count("authelia-session:"))
+
+	session, err = provider.sessions[testDomain].GetSession(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, testUsername, session.Username)
+	assert.Equal(t, authentication.OneFactor, session.AuthenticationLevel)
+
+	require.NoError(t, provider.sessions[testDomain].DestroySession(ctx))
+
+	assert.Equal(t, 0, server.count("authelia-session:"))
+}
+
+// testRedisClusterServer is a minimal single node Redis Cluster which implements only the commands used by the
+// RedisClusterProvider.
+type testRedisClusterServer struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	state       string
+	data        map[string][]byte
+	expirations map[string]time.Duration
+}
+
+func newTestRedisClusterServer(t *testing.T) *testRedisClusterServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &testRedisClusterServer{
+		listener:    listener,
+		state:       "ok",
+		data:        map[string][]byte{},
+		expirations: map[string]time.Duration{},
+	}
+
+	go server.serve()
+
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	return server
+}
+
+func (s *testRedisClusterServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *testRedisClusterServer) setState(state string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = state
+}
+
+func (s *testRedisClusterServer) get(key string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.data[key]
+}
+
+func (s *testRedisClusterServer) set(key string, value []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[key] = value
+}
+
+func (s *testRedisClusterServer) expiration(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.expirations[key]
+}
+
+func (s *testRedisClusterServer) count(prefix string) (count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (s *testRedisClusterServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *testRedisClusterServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+
+	for {
+		args, err := readTestRedisCommand(r)
+		if err != nil {
+			return
+		}
+
+		if _, err = conn.Write([]byte(s.exec(args))); err != nil {
+			return
+		}
+	}
+}
+
+func (s *testRedisClusterServer) exec(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch strings.ToLower(args[0]) {
+	case "ping":
+		return "+PONG\r\n"
+	case "cluster":
+		switch strings.ToLower(args[1]) {
+		case "slots":
+			host, port, _ := net.SplitHostPort(s.addr())
+
+			return fmt.Sprintf("*1\r\n*3\r\n:0\r\n:16383\r\n*3\r\n%s:%s\r\n%s", testRedisBulk(host), port, testRedisBulk("node"))
+		case "info":
+			return testRedisBulk(fmt.Sprintf("cluster_state:%s\r\ncluster_known_nodes:1\r\n", s.state))
+		}
+	case "get":
+		if value, ok := s.data[args[1]]; ok {
+			return testRedisBulk(string(value))
+		}
+
+		return "$-1\r\n"
+	case "set":
+		s.data[args[1]] = []byte(args[2])
+
+		if len(args) == 5 {
+			value, _ := strconv.Atoi(args[4])
+
+			switch strings.ToLower(args[3]) {
+			case "ex":
+				s.expirations[args[1]] = time.Duration(value) * time.Second
+			case "px":
+				s.expirations[args[1]] = time.Duration(value) * time.Millisecond
+			}
+		}
+
+		return "+OK\r\n"
+	case "del":
+		deleted := 0
+
+		for _, key := range args[1:] {
+			if _, ok := s.data[key]; ok {
+				delete(s.data, key)
+				delete(s.expirations, key)
+
+				deleted++
+			}
+		}
+
+		return fmt.Sprintf(":%d\r\n", deleted)
+	case "scan":
+		var pattern string
+
+		for i := 2; i < len(args)-1; i++ {
+			if strings.ToLower(args[i]) == "match" {
+				pattern = args[i+1]
+			}
+		}
+
+		var keys []string
+
+		for key := range s.data {
+			if matched, _ := path.Match(pattern, key); matched {
+				keys = append(keys, testRedisBulk(key))
+			}
+		}
+
+		return fmt.Sprintf("*2\r\n%s*%d\r\n%s", testRedisBulk("0"), len(keys), strings.Join(keys, ""))
+	}
+
+	return fmt.Sprintf("-ERR unknown command '%s'\r\n", args[0])
+}
+
+func testRedisBulk(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+func readTestRedisCommand(r *bufio.Reader) (args []string, err error) {
+	var line string
+
+	if line, err = r.ReadString('\n'); err != nil {
+		return nil, err
+	}
+
+	var n int
+
+	if _, err = fmt.Sscanf(line, "*%d\r\n", &n); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < n; i++ {
+		if line, err = r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+
+		var size int
+
+		if _, err = fmt.Sscanf(line, "$%d\r\n", &size); err != nil {
+			return nil, err
+		}
+
+		buf := make([]byte, size+2)
+
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+
+		args = append(args, string(buf[:size]))
+	}
+
+	return args, nil
+}
@@ -0,0 +1,173 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/fasthttp/session/v2"
+)
+
+var errSQLSessionDataVersionMissing = errors.New("the session data is not prefixed with the version")
+
+// SQLStorage is the storage used by the SQLProvider to persist the session data. The session data is saved with
+// optimistic concurrency control, i.e. SaveSessionData must only save the data if the version matches the version of
+// the stored session data and return an error otherwise, where a version of 0 indicates a new session.
+type SQLStorage interface {
+	SaveSessionData(ctx context.Context, sessionID string, data []byte, expiresAt time.Time, version int64) (err error)
+	LoadSessionData(ctx context.Context, sessionID string) (data []byte, version int64, err error)
+	RegenerateSessionData(ctx context.Context, sessionID, newSessionID string, expiresAt time.Time) (err error)
+	CountSessionData(ctx context.Context) (count int, err error)
+	DeleteSessionData(ctx context.Context, sessionID string) (err error)
+	DeleteExpiredSessionData(ctx context.Context, now time.Time) (deleted int64, err error)
+}
+
+// NewSQLProvider creates a new SQLProvider. The session data is stored keyed by the hex encoded SHA256 hash of the
+// session ID so the session ID itself is never persisted.
+func NewSQLProvider(storage SQLStorage) *SQLProvider {
+	return &SQLProvider{
+		storage: storage,
+		now:     time.Now,
+	}
+}
+
+// SQLProvider is a fasthttp/session Provider which stores the session data in the storage provider.
+type SQLProvider struct {
+	storage SQLStorage
+	now     func() time.Time
+}
+
+// Get returns the data for the given session id prefixed with the version of the session data. If the session does not
+// exist or has expired nil is returned.
+func (p *SQLProvider) Get(id []byte) (data []byte, err error) {
+	var version int64
+
+	if data, version, err = p.storage.LoadSessionData(context.Background(), sqlSessionID(id)); err != nil || data == nil {
+		return nil, err
+	}
+
+	return sqlSessionDataWithVersion(version, data), nil
+}
+
+// Save saves the session data and expiration for the given session id. The data must be prefixed with the version of
+// the session data the changes are based on as returned by Get, and the session data is only saved if it has not
+// been saved by another request since, i.e. when concurrent requests modify the same session only the first is saved
+// and the others return an error instead of silently discarding the changes of the first.
+func (p *SQLProvider) Save(id, data []byte, expiration time.Duration) (err error) {
+	var version int64
+
+	if version, data, err = sqlSessionDataVersion(data); err != nil {
+		return err
+	}
+
+	return p.storage.SaveSessionData(context.Background(), sqlSessionID(id), data, p.expiresAt(expiration), version)
+}
+
+// Destroy destroys the session data for the given session id.
+func (p *SQLProvider) Destroy(id []byte) (err error) {
+	return p.storage.DeleteSessionData(context.Background(), sqlSessionID(id))
+}
+
+// Regenerate moves the session data from the given session id to the new session id and updates the expiration.
+func (p *SQLProvider) Regenerate(id, newID []byte, expiration time.Duration) (err error) {
+	return p.storage.RegenerateSessionData(context.Background(), sqlSessionID(id), sqlSessionID(newID), p.expiresAt(expiration))
+}
+
+// Count returns the number of sessions which have not expired.
+func (p *SQLProvider) Count() int {
+	count, err := p.storage.CountSessionData(context.Background())
+	if err != nil {
+		return 0
+	}
+
+	return count
+}
+
+// NeedGC indicates the provider needs the expired session data to be periodically removed.
+func (p *SQLProvider) NeedGC() bool {
+	return true
+}
+
+// GC removes the session data which has expired.
+func (p *SQLProvider) GC() (err error) {
+	_, err = p.storage.DeleteExpiredSessionData(context.Background(), p.now())
+
+	return err
+}
+
+func (p *SQLProvider) expiresAt(expiration time.Duration) time.Time {
+	if expiration <= 0 {
+		return time.Time{}
+	}
+
+	return p.now().Add(expiration)
+}
+
+// NewSQLSerializer returns a SQLSerializer which wraps the provided session encode and decode functions.
+func NewSQLSerializer(encode func(src session.Dict) ([]byte, error), decode func(dst *session.Dict, src []byte) error) *SQLSerializer {
+	return &SQLSerializer{encode: encode, decode: decode}
+}
+
+// SQLSerializer is the serializer used with the SQLProvider. It carries the version of the session data between the
+// SQLProvider and the session store, so the version the changes of a request are based on is known when it's saved.
+type SQLSerializer struct {
+	encode func(src session.Dict) ([]byte, error)
+	decode func(dst *session.Dict, src []byte) error
+}
+
+// Encode encodes the session and prefixes it with the version of the session data.
+func (s *SQLSerializer) Encode(src session.Dict) (dst []byte, err error) {
+	version, _ := src.Get(sessionVersionStorerKey).(int64)
+
+	if dst, err = s.encode(src); err != nil {
+		return nil, err
+	}
+
+	return sqlSessionDataWithVersion(version, dst), nil
+}
+
+// Decode removes the version prefix of the session data, decodes the session, and sets the version in the session.
+func (s *SQLSerializer) Decode(dst *session.Dict, src []byte) (err error) {
+	if len(src) == 0 {
+		return s.decode(dst, src)
+	}
+
+	var version int64
+
+	if version, src, err = sqlSessionDataVersion(src); err != nil {
+		return err
+	}
+
+	if err = s.decode(dst, src); err != nil {
+		return err
+	}
+
+	dst.Set(sessionVersionStorerKey, version)
+
+	return nil
+}
+
+func sqlSessionDataWithVersion(version int64, data []byte) []byte {
+	value := make([]byte, sqlSessionVersionLength, sqlSessionVersionLength+len(data))
+
+	binary.BigEndian.PutUint64(value, uint64(version))
+
+	return append(value, data...)
+}
+
+func sqlSessionDataVersion(value []byte) (version int64, data []byte, err error) {
+	if len(value) < sqlSessionVersionLength {
+		return 0, nil, errSQLSessionDataVersionMissing
+	}
+
+	return int64(binary.BigEndian.Uint64(value[:sqlSessionVersionLength])), value[sqlSessionVersionLength:], nil
+}
+
+func sqlSessionID(id []byte) string {
+	sum := sha256.Sum256(id)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,473 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type testSQLStorageRow struct {
+	data      []byte
+	expiresAt time.Time
+	version   int64
+}
+
+type testSQLStorage struct {
+	mu   sync.Mutex
+	now  time.Time
+	rows map[string]testSQLStorageRow
+	err  error
+}
+
+func newTestSQLStorage(now time.Time) *testSQLStorage {
+	return &testSQLStorage{now: now, rows: map[string]testSQLStorageRow{}}
+}
+
+func (s *testSQLStorage) expired(row testSQLStorageRow) bool {
+	return !row.expiresAt.IsZero() && !row.expiresAt.After(s.now)
+}
+
+func (s *testSQLStorage) SaveSessionData(_ context.Context, sessionID string, data []byte, expiresAt time.Time, version int64) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	row, ok := s.rows[sessionID]
+
+	switch {
+	case version == 0 && ok && !s.expired(row):
+		return storage.ErrSessionDataVersionConflict
+	case version != 0 && (!ok || row.version != version):
+		return storage.ErrSessionDataVersionConflict
+	}
+
+	s.rows[sessionID] = testSQLStorageRow{data: data, expiresAt: expiresAt, version: version + 1}
+
+	return nil
+}
+
+func (s *testSQLStorage) LoadSessionData(_ context.Context, sessionID string) (data []byte, version int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if row, ok := s.rows[sessionID]; ok && !s.expired(row) {
+		return row.data, row.version, s.err
+	}
+
+	return nil, 0, s.err
+}
+
+func (s *testSQLStorage) RegenerateSessionData(_ context.Context, sessionID, newSessionID string, expiresAt time.Time) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if row, ok := s.rows[sessionID]; ok {
+		delete(s.rows, sessionID)
+
+		row.expiresAt = expiresAt
+		s.rows[newSessionID] = row
+	}
+
+	return s.err
+}
+
+func (s *testSQLStorage) CountSessionData(_ context.Context) (count int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	for _, row := range s.rows {
+		if !s.expired(row) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (s *testSQLStorage) DeleteSessionData(_ context.Context, sessionID string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.rows, sessionID)
+
+	return s.err
+}
+
+func (s *testSQLStorage) DeleteExpiredSessionData(_ context.Context, now time.Time) (deleted int64, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, row := range s.rows {
+		if !row.expiresAt.IsZero() && !row.expiresAt.After(now) {
+			delete(s.rows, id)
+			deleted++
+		}
+	}
+
+	return deleted, s.err
+}
+
+func TestSQLProviderShouldStoreHashedSessionID(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	storage := newTestSQLStorage(now)
+
+	provider := NewSQLProvider(storage)
+	provider.now = func() time.Time { return now }
+
+	require.NoError(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(0, []byte("data")), time.Minute))
+
+	require.Len(t, storage.rows, 1)
+
+	row, ok := storage.rows["ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"]
+	require.True(t, ok)
+	assert.Equal(t, []byte("data"), row.data)
+	assert.Equal(t, now.Add(time.Minute), row.expiresAt)
+	assert.Equal(t, int64(1), row.version)
+
+	data, err := provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, sqlSessionDataWithVersion(1, []byte("data")), data)
+	assert.Equal(t, 1, provider.Count())
+
+	require.NoError(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(1, []byte("updated")), 0))
+
+	require.Len(t, storage.rows, 1)
+
+	data, err = provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, sqlSessionDataWithVersion(2, []byte("updated")), data)
+	assert.True(t, storage.rows[sqlSessionID([]byte("abc"))].expiresAt.IsZero())
+}
+
+func TestSQLProviderShouldRegenerateAndDestroy(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	storage := newTestSQLStorage(now)
+
+	provider := NewSQLProvider(storage)
+	provider.now = func() time.Time { return now }
+
+	require.NoError(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(0, []byte("data")), time.Minute))
+	require.NoError(t, provider.Regenerate([]byte("abc"), []byte("xyz"), time.Hour))
+
+	data, err := provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	data, err = provider.Get([]byte("xyz"))
+	assert.NoError(t, err)
+	assert.Equal(t, sqlSessionDataWithVersion(1, []byte("data")), data)
+	assert.Equal(t, now.Add(time.Hour), storage.rows[sqlSessionID([]byte("xyz"))].expiresAt)
+
+	require.NoError(t, provider.Destroy([]byte("xyz")))
+
+	assert.Len(t, storage.rows, 0)
+	assert.Equal(t, 0, provider.Count())
+}
+
+func TestSQLProviderShouldRemoveExpiredSessionData(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	storage := newTestSQLStorage(now)
+
+	provider := NewSQLProvider(storage)
+	provider.now = func() time.Time { return now }
+
+	require.NoError(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(0, []byte("data")), time.Minute))
+	require.NoError(t, provider.Save([]byte("xyz"), sqlSessionDataWithVersion(0, []byte("data")), time.Hour))
+	require.NoError(t, provider.Save([]byte("def"), sqlSessionDataWithVersion(0, []byte("data")), 0))
+
+	assert.True(t, provider.NeedGC())
+	assert.Equal(t, 3, provider.Count())
+
+	now = now.Add(time.Minute * 2)
+	storage.now = now
+
+	data, err := provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+	assert.Equal(t, 2, provider.Count())
+
+	require.NoError(t, provider.GC())
+
+	assert.Len(t, storage.rows, 2)
+
+	_, ok := storage.rows[sqlSessionID([]byte("abc"))]
+	assert.False(t, ok)
+}
+
+func TestSQLProviderShouldReturnErrors(t *testing.T) {
+	storage := newTestSQLStorage(time.Now())
+	storage.err = errors.New("failed to connect")
+
+	provider := NewSQLProvider(storage)
+
+	assert.EqualError(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(0, []byte("data")), time.Minute), "failed to connect")
+	assert.EqualError(t, provider.Save([]byte("abc"), []byte("data"), time.Minute), "the session data is not prefixed with the version")
+	assert.EqualError(t, provider.Regenerate([]byte("abc"), []byte("xyz"), time.Minute), "failed to connect")
+	assert.EqualError(t, provider.Destroy([]byte("abc")), "failed to connect")
+	assert.EqualError(t, provider.GC(), "failed to connect")
+	assert.Equal(t, 0, provider.Count())
+
+	_, err := provider.Get([]byte("abc"))
+	assert.EqualError(t, err, "failed to connect")
+}
+
+func TestSQLProviderShouldRejectConcurrentSavesWithSQLiteStorage(t *testing.T) {
+	store := storage.NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey: "a-long-encryption-key-used-only-for-tests",
+			Local:         &schema.LocalStorageConfiguration{Path: filepath.Join(t.TempDir(), "db.sqlite3")},
+		},
+	})
+
+	require.NoError(t, store.StartupCheck())
+
+	defer store.Close()
+
+	provider := NewSQLProvider(store)
+
+	require.NoError(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(0, []byte("first")), time.Minute))
+	assert.ErrorIs(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(0, []byte("duplicate")), time.Minute), storage.ErrSessionDataVersionConflict)
+	require.NoError(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(1, []byte("second")), time.Minute))
+	assert.ErrorIs(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(1, []byte("stale")), time.Minute), storage.ErrSessionDataVersionConflict)
+
+	data, err := provider.Get([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, sqlSessionDataWithVersion(2, []byte("second")), data)
+
+	values := []string{"one", "two", "three", "four", "five"}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		saved []string
+	)
+
+	for _, value := range values {
+		wg.Add(1)
+
+		go func(value string) {
+			defer wg.Done()
+
+			if err := provider.Save([]byte("abc"), sqlSessionDataWithVersion(2, []byte(value)), time.Minute); err != nil {
+				assert.ErrorIs(t, err, storage.ErrSessionDataVersionConflict)
+
+				return
+			}
+
+			mu.Lock()
+			saved = append(saved, value)
+			mu.Unlock()
+		}(value)
+	}
+
+	wg.Wait()
+
+	require.Len(t, saved, 1)
+
+	data, err = provider.Get([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, sqlSessionDataWithVersion(3, []byte(saved[0])), data)
+	assert.Equal(t, 1, provider.Count())
+
+	provider.now = func() time.Time { return time.Now().Add(-time.Hour) }
+
+	require.NoError(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(3, []byte("expired")), time.Minute))
+
+	data, err = provider.Get([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	provider.now = time.Now
+
+	require.NoError(t, provider.Save([]byte("abc"), sqlSessionDataWithVersion(0, []byte("new")), time.Minute))
+
+	data, err = provider.Get([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, sqlSessionDataWithVersion(1, []byte("new")), data)
+}
+
+func TestShouldPersistEncryptedUserSessionWithSQLProvider(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.Secret = "abc"
+	configuration.SQL = schema.SessionSQLConfiguration{Enabled: true, CleanupInterval: time.Minute}
+
+	storage := newTestSQLStorage(time.Now())
+
+	provider := NewProvider(configuration, nil, storage).sessions[testDomain]
+
+	session, _ := provider.GetSession(ctx)
+
+	session.Username = testUsername
+	session.AuthenticationLevel = authentication.TwoFactor
+
+	require.NoError(t, provider.SaveSession(ctx, session))
+
+	require.Len(t, storage.rows, 1)
+
+	for _, row := range storage.rows {
+		assert.NotContains(t, string(row.data), testUsername)
+	}
+
+	cookie := &fasthttp.Cookie{}
+	require.NoError(t, cookie.ParseBytes(ctx.Response.Header.PeekCookie(testName)))
+
+	next := &fasthttp.RequestCtx{}
+	next.Request.Header.SetCookie(testName, string(cookie.Value()))
+
+	session, err := NewProvider(configuration, nil, storage).sessions[testDomain].GetSession(next)
+
+	require.NoError(t, err)
+	assert.Equal(t, testUsername, session.Username)
+	assert.Equal(t, authentication.TwoFactor, session.AuthenticationLevel)
+}
+
+func TestShouldRejectConcurrentUserSessionChangesWithSQLProvider(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.Secret = "abc"
+	configuration.SQL = schema.SessionSQLConfiguration{Enabled: true, CleanupInterval: time.Minute}
+
+	store := newTestSQLStorage(time.Now())
+
+	provider := NewProvider(configuration, nil, store).sessions[testDomain]
+
+	ctx := &fasthttp.RequestCtx{}
+
+	session, _ := provider.GetSession(ctx)
+
+	session.Username = testUsername
+	session.AuthenticationLevel = authentication.OneFactor
+
+	require.NoError(t, provider.SaveSession(ctx, session))
+
+	cookie := &fasthttp.Cookie{}
+	require.NoError(t, cookie.ParseBytes(ctx.Response.Header.PeekCookie(testName)))
+
+	first, second := &fasthttp.RequestCtx{}, &fasthttp.RequestCtx{}
+	first.Request.Header.SetCookie(testName, string(cookie.Value()))
+	second.Request.Header.SetCookie(testName, string(cookie.Value()))
+
+	firstSession, err := provider.GetSession(first)
+	require.NoError(t, err)
+
+	secondSession, err := provider.GetSession(second)
+	require.NoError(t, err)
+
+	firstSession.AuthenticationLevel = authentication.TwoFactor
+
+	require.NoError(t, provider.SaveSession(first, firstSession))
+	require.NoError(t, provider.UpdateExpiration(first, time.Minute))
+	require.NoError(t, provider.SaveSession(first, firstSession))
+
+	secondSession.DisplayName = "John Smith"
+
+	assert.ErrorIs(t, provider.SaveSession(second, secondSession), storage.ErrSessionDataVersionConflict)
+
+	next := &fasthttp.RequestCtx{}
+	next.Request.Header.SetCookie(testName, string(cookie.Value()))
+
+	session, err = provider.GetSession(next)
+	require.NoError(t, err)
+
+	assert.Equal(t, authentication.TwoFactor, session.AuthenticationLevel)
+	assert.Equal(t, "", session.DisplayName)
+
+	session.DisplayName = "John Smith"
+
+	require.NoError(t, provider.SaveSession(next, session))
+}
+
+func TestShouldRetryConcurrentUserSessionUpdatesWithSQLProvider(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.Secret = "abc"
+	configuration.SQL = schema.SessionSQLConfiguration{Enabled: true, CleanupInterval: time.Minute}
+
+	store := newTestSQLStorage(time.Now())
+
+	provider := NewProvider(configuration, nil, store).sessions[testDomain]
+
+	ctx := &fasthttp.RequestCtx{}
+
+	session, _ := provider.GetSession(ctx)
+
+	session.Username = testUsername
+	session.AuthenticationLevel = authentication.OneFactor
+
+	require.NoError(t, provider.SaveSession(ctx, session))
+
+	cookie := &fasthttp.Cookie{}
+	require.NoError(t, cookie.ParseBytes(ctx.Response.Header.PeekCookie(testName)))
+
+	first, second, third := &fasthttp.RequestCtx{}, &fasthttp.RequestCtx{}, &fasthttp.RequestCtx{}
+	first.Request.Header.SetCookie(testName, string(cookie.Value()))
+	second.Request.Header.SetCookie(testName, string(cookie.Value()))
+	third.Request.Header.SetCookie(testName, string(cookie.Value()))
+
+	firstSession, err := provider.GetSession(first)
+	require.NoError(t, err)
+
+	secondSession, err := provider.GetSession(second)
+	require.NoError(t, err)
+
+	thirdSession, err := provider.GetSession(third)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.UpdateSession(first, &firstSession, func(userSession *UserSession) {
+		userSession.AuthenticationLevel = authentication.TwoFactor
+	}))
+
+	// The second request loaded the session before the first request saved it so the update is applied to the session
+	// saved by the first request instead of discarding its changes.
+	require.NoError(t, provider.UpdateSession(second, &secondSession, func(userSession *UserSession) {
+		userSession.LastActivity = 100
+	}))
+
+	assert.Equal(t, authentication.TwoFactor, secondSession.AuthenticationLevel)
+	assert.Equal(t, int64(100), secondSession.LastActivity)
+
+	next := &fasthttp.RequestCtx{}
+	next.Request.Header.SetCookie(testName, string(cookie.Value()))
+
+	session, err = provider.GetSession(next)
+	require.NoError(t, err)
+
+	assert.Equal(t, authentication.TwoFactor, session.AuthenticationLevel)
+	assert.Equal(t, int64(100), session.LastActivity)
+
+	require.NoError(t, provider.DestroySession(next))
+
+	// The update isn't applied to the session once it has been destroyed by another request.
+	assert.ErrorIs(t, provider.UpdateSession(third, &thirdSession, func(userSession *UserSession) {
+		userSession.LastActivity = 200
+	}), storage.ErrSessionDataVersionConflict)
+
+	assert.Len(t, store.rows, 0)
+}
@@ -21,7 +21,7 @@ func TestShouldInitializerSession(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil).sessions[testDomain]
 	session, err := provider.GetSession(ctx)
 	require.NoError(t, err)
 
@@ -36,7 +36,7 @@ func TestShouldUpdateSession(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil).sessions[testDomain]
 	session, _ := provider.GetSession(ctx)
 
 	session.Username = testUsername
@@ -66,7 +66,7 @@ func TestShouldSetSessionAuthenticationLevels(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil).sessions[testDomain]
 	session, _ := provider.GetSession(ctx)
 
 	session.SetOneFactor(timeOneFactor, &authentication.UserDetails{Username: testUsername}, false)
@@ -139,7 +139,7 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil).sessions[testDomain]
 	session, _ := provider.GetSession(ctx)
 
 	session.SetOneFactor(timeOneFactor, &authentication.UserDetails{Username: testUsername}, false)
@@ -290,6 +290,23 @@ func TestShouldSetSessionAuthenticationLevelsAMR(t *testing.T) {
 		session.AuthenticationMethodRefs)
 }
 
+func TestShouldTrackOpenIDConnectSession(t *testing.T) {
+	session := NewDefaultUserSession()
+
+	assert.Equal(t, "", session.OpenIDConnect.SessionID)
+
+	sid := session.GetOpenIDConnectSessionID()
+
+	assert.Len(t, sid, 36)
+	assert.Equal(t, sid, session.GetOpenIDConnectSessionID())
+
+	assert.True(t, session.AddOpenIDConnectClient("client-a", "subject-a"))
+	assert.False(t, session.AddOpenIDConnectClient("client-a", "subject-a"))
+	assert.True(t, session.AddOpenIDConnectClient("client-b", "subject-a"))
+
+	assert.Equal(t, []OpenIDConnectSessionClient{{ID: "client-a", Subject: "subject-a"}, {ID: "client-b", Subject: "subject-a"}}, session.OpenIDConnect.Clients)
+}
+
 func TestShouldDestroySessionAndWipeSessionData(t *testing.T) {
 	ctx := &fasthttp.RequestCtx{}
 	configuration := schema.SessionConfiguration{}
@@ -297,7 +314,7 @@ func TestShouldDestroySessionAndWipeSessionData(t *testing.T) {
 	configuration.Name = testName
 	configuration.Expiration = testExpiration
 
-	provider := NewProvider(configuration, nil)
+	provider := NewProvider(configuration, nil, nil).sessions[testDomain]
 	session, err := provider.GetSession(ctx)
 	require.NoError(t, err)
 
@@ -320,3 +337,69 @@ func TestShouldDestroySessionAndWipeSessionData(t *testing.T) {
 	assert.Equal(t, "", newUserSession.Username)
 	assert.Equal(t, authentication.NotAuthenticated, newUserSession.AuthenticationLevel)
 }
+
+func TestShouldGetSessionForCookieDomain(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+	configuration.Cookies = []schema.SessionCookieConfiguration{
+		{
+			Domain:     "example.org",
+			Name:       "authelia_org",
+			Expiration: testExpiration,
+		},
+		{
+			Domain:     "secure.example.org",
+			Name:       "authelia_secure_org",
+			Expiration: testExpiration,
+		},
+	}
+
+	provider := NewProvider(configuration, nil, nil)
+
+	assert.Equal(t, []string{testDomain, "example.org", "secure.example.org"}, provider.GetDomains())
+	assert.NoError(t, provider.StartupCheck())
+
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+		err      string
+	}{
+		{"ShouldMatchExactDomain", "example.org", "authelia_org", ""},
+		{"ShouldMatchSubdomain", "app.example.org", "authelia_org", ""},
+		{"ShouldMatchMostSpecificDomain", "app.secure.example.org", "authelia_secure_org", ""},
+		{"ShouldMatchCaseInsensitive", "APP.Example.com", testName, ""},
+		{"ShouldNotMatchSuffixWithoutDot", "notexample.org", "", "no session cookie domain is configured for the domain 'notexample.org'"},
+		{"ShouldNotMatchUnknownDomain", "example.net", "", "no session cookie domain is configured for the domain 'example.net'"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			session, err := provider.Get(tc.have)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, session)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, session.Config.Name)
+		})
+	}
+}
+
+func TestShouldGetOnlySessionForAnyDomain(t *testing.T) {
+	configuration := schema.SessionConfiguration{}
+	configuration.Domain = testDomain
+	configuration.Name = testName
+	configuration.Expiration = testExpiration
+
+	session, err := NewProvider(configuration, nil, nil).Get("example.net")
+
+	require.NoError(t, err)
+	assert.Equal(t, testDomain, session.Config.Domain)
+}
@@ -0,0 +1,211 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	fasthttpsession "github.com/fasthttp/session/v2"
+	"github.com/valyala/fasthttp"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// Session is the session cookie and store for a specific session cookie domain.
+type Session struct {
+	Config schema.SessionCookieConfiguration
+
+	sessionHolder *fasthttpsession.Session
+}
+
+// GetSession return the user session from a request.
+func (p *Session) GetSession(ctx *fasthttp.RequestCtx) (UserSession, error) {
+	store, err := p.sessionHolder.Get(ctx)
+
+	if err != nil {
+		return NewDefaultUserSession(), err
+	}
+
+	p.setVersion(ctx, store)
+
+	userSessionJSON, ok := store.Get(userSessionStorerKey).([]byte)
+
+	// If userSession is not yet defined we create the new session with default values
+	// and save it in the store.
+	if !ok {
+		userSession := NewDefaultUserSession()
+
+		store.Set(userSessionStorerKey, userSession)
+
+		return userSession, nil
+	}
+
+	var userSession UserSession
+	err = json.Unmarshal(userSessionJSON, &userSession)
+
+	if err != nil {
+		return NewDefaultUserSession(), err
+	}
+
+	return userSession, nil
+}
+
+// SaveSession save the user session.
+func (p *Session) SaveSession(ctx *fasthttp.RequestCtx, userSession UserSession) error {
+	store, err := p.sessionHolder.Get(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	userSessionJSON, err := json.Marshal(userSession)
+
+	if err != nil {
+		return err
+	}
+
+	store.Set(userSessionStorerKey, userSessionJSON)
+
+	return p.save(ctx, store)
+}
+
+// UpdateSession applies the update to the user session and saves it. If the session was saved by another request since
+// it was loaded by this request the session is loaded again, the update is applied to the reloaded session, and the
+// save is retried, so concurrent requests which update the same session don't discard each others changes. The user
+// session is replaced with the session which was saved. If the session no longer belongs to the same user after it
+// was reloaded, for example because it was destroyed, the update is not applied and the conflict is returned.
+func (p *Session) UpdateSession(ctx *fasthttp.RequestCtx, userSession *UserSession, update func(userSession *UserSession)) (err error) {
+	username := userSession.Username
+
+	for attempt := 1; ; attempt++ {
+		update(userSession)
+
+		if err = p.SaveSession(ctx, *userSession); err == nil || attempt == updateSessionAttempts || !errors.Is(err, storage.ErrSessionDataVersionConflict) {
+			return err
+		}
+
+		// Forget the version this request loaded so the version of the reloaded session is used for the next save.
+		ctx.RemoveUserValue(p)
+
+		var reloaded UserSession
+
+		if reloaded, err = p.GetSession(ctx); err != nil {
+			return err
+		}
+
+		if reloaded.Username != username {
+			return storage.ErrSessionDataVersionConflict
+		}
+
+		*userSession = reloaded
+	}
+}
+
+// RegenerateSession regenerate a session ID.
+func (p *Session) RegenerateSession(ctx *fasthttp.RequestCtx) error {
+	id := string(ctx.Request.Header.Cookie(p.Config.Name))
+
+	if err := p.sessionHolder.Regenerate(ctx); err != nil {
+		return err
+	}
+
+	if version, ok := ctx.UserValue(p).(sessionVersion); ok && version.id == id {
+		ctx.SetUserValue(p, sessionVersion{id: string(ctx.Request.Header.Cookie(p.Config.Name)), version: version.version})
+	}
+
+	return nil
+}
+
+// DestroySession destroy a session ID and delete the cookie.
+func (p *Session) DestroySession(ctx *fasthttp.RequestCtx) error {
+	ctx.RemoveUserValue(p)
+
+	return p.sessionHolder.Destroy(ctx)
+}
+
+// UpdateExpiration update the expiration of the cookie and session.
+func (p *Session) UpdateExpiration(ctx *fasthttp.RequestCtx, expiration time.Duration) error {
+	store, err := p.sessionHolder.Get(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	err = store.SetExpiration(expiration)
+
+	if err != nil {
+		return err
+	}
+
+	return p.save(ctx, store)
+}
+
+// GetExpiration get the expiration of the current session.
+func (p *Session) GetExpiration(ctx *fasthttp.RequestCtx) (time.Duration, error) {
+	store, err := p.sessionHolder.Get(ctx)
+
+	if err != nil {
+		return time.Duration(0), err
+	}
+
+	return store.GetExpiration(), nil
+}
+
+// GetSessionIDHash returns the hex encoded SHA256 hash of the session ID of the request, or an empty string if the
+// request does not have a session cookie. The hash identifies the session without disclosing the session ID itself.
+func (p *Session) GetSessionIDHash(ctx *fasthttp.RequestCtx) string {
+	id := ctx.Request.Header.Cookie(p.Config.Name)
+
+	if len(id) == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256(id)
+
+	return hex.EncodeToString(sum[:])
+}
+
+// setVersion records the version of the session data the first time it's loaded by a request. This is only the case
+// for providers which version the session data such as the SQLProvider.
+func (p *Session) setVersion(ctx *fasthttp.RequestCtx, store *fasthttpsession.Store) {
+	version, ok := store.Get(sessionVersionStorerKey).(int64)
+	if !ok {
+		return
+	}
+
+	id := string(store.GetSessionID())
+
+	if current, ok := ctx.UserValue(p).(sessionVersion); ok && current.id == id {
+		return
+	}
+
+	ctx.SetUserValue(p, sessionVersion{id: id, version: version})
+}
+
+// save saves the session store. When the session data is versioned the version recorded when it was first loaded by the
+// request is used instead of the version which was just loaded, so the save fails if another request saved the session
+// in the meantime rather than silently discarding the changes of the other request.
+func (p *Session) save(ctx *fasthttp.RequestCtx, store *fasthttpsession.Store) (err error) {
+	id := string(store.GetSessionID())
+
+	version, versioned := store.Get(sessionVersionStorerKey).(int64)
+
+	if current, ok := ctx.UserValue(p).(sessionVersion); ok && current.id == id {
+		version, versioned = current.version, true
+
+		store.Set(sessionVersionStorerKey, version)
+	}
+
+	if err = p.sessionHolder.Save(ctx, store); err != nil {
+		return err
+	}
+
+	if versioned {
+		ctx.SetUserValue(p, sessionVersion{id: id, version: version + 1})
+	}
+
+	return nil
+}
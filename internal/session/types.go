@@ -5,9 +5,11 @@ import (
 
 	session "github.com/fasthttp/session/v2"
 	"github.com/fasthttp/session/v2/providers/redis"
+	goredis "github.com/go-redis/redis/v8"
 	"github.com/go-webauthn/webauthn/webauthn"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/oidc"
 )
 
@@ -16,9 +18,24 @@ type ProviderConfig struct {
 	config              session.Config
 	redisConfig         *redis.Config
 	redisSentinelConfig *redis.FailoverConfig
+	redisClusterConfig  *RedisClusterConfig
+	sqlConfig           *schema.SessionSQLConfiguration
 	providerName        string
 }
 
+// RedisClusterConfig is the configuration used to create the Redis Cluster session provider.
+type RedisClusterConfig struct {
+	Options   goredis.ClusterOptions
+	Logger    redis.Logger
+	KeyPrefix string
+}
+
+// sessionVersion is the version of the session data with the given id as known by a request.
+type sessionVersion struct {
+	id      string
+	version int64
+}
+
 // UserSession is the structure representing the session of a user.
 type UserSession struct {
 	Username    string
@@ -44,6 +61,45 @@ type UserSession struct {
 	PasswordResetUsername *string
 
 	RefreshTTL time.Time
+
+	// ActiveSessionRecorded is true when this session has been recorded as an active session. Sessions created before
+	// active sessions were recorded don't have an active session, which must not be mistaken for a revoked session.
+	ActiveSessionRecorded bool
+
+	// Binding holds the client characteristics this session is bound to.
+	Binding ClientBinding
+
+	// OpenIDConnect holds the OpenID Connect 1.0 session id and the clients which were issued tokens for this session.
+	OpenIDConnect OpenIDConnectSession
+}
+
+// OpenIDConnectSession is the OpenID Connect 1.0 state of a UserSession used to perform logout.
+type OpenIDConnectSession struct {
+	SessionID string
+	Clients   []OpenIDConnectSessionClient
+
+	// FrontChannelLogoutURIs are the front-channel logout uris of a session which was terminated by the portal which
+	// must still be loaded by the user agent using the End Session endpoint.
+	FrontChannelLogoutURIs []string
+
+	// PostLogoutRedirectURI is the uri the user agent is redirected to after loading the FrontChannelLogoutURIs.
+	PostLogoutRedirectURI string
+}
+
+// OpenIDConnectSessionClient is a client which was issued tokens during a UserSession.
+type OpenIDConnectSessionClient struct {
+	ID      string
+	Subject string
+}
+
+// ClientBinding is the client characteristics a UserSession is bound to.
+type ClientBinding struct {
+	IP        string
+	UserAgent string
+
+	AntiReplayToken         string
+	AntiReplayTokenPrevious string
+	AntiReplayTokenRotated  int64
 }
 
 // Identity identity of the user who is being verified.
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/oidc"
 )
 
 // NewDefaultUserSession create a default user session.
@@ -22,6 +25,15 @@ func (s *UserSession) IsAnonymous() bool {
 	return s.Username == "" || s.AuthenticationLevel == authentication.NotAuthenticated
 }
 
+// ResetAuthentication resets the authentication level, the authentication times, and the AMR's so the user must
+// authenticate again. The identity of the user and the OpenID Connect 1.0 session state are retained so they are
+// restored when the same user authenticates again.
+func (s *UserSession) ResetAuthentication() {
+	s.AuthenticationLevel = authentication.NotAuthenticated
+	s.FirstFactorAuthnTimestamp, s.SecondFactorAuthnTimestamp = 0, 0
+	s.AuthenticationMethodRefs = oidc.AuthenticationMethodsReferences{}
+}
+
 // SetOneFactor sets the 1FA AMR's and expected property values for one factor authentication.
 func (s *UserSession) SetOneFactor(now time.Time, details *authentication.UserDetails, keepMeLoggedIn bool) {
 	s.FirstFactorAuthnTimestamp = now.Unix()
@@ -76,3 +88,26 @@ func (s *UserSession) AuthenticatedTime(level authorization.Level) (authenticate
 		return time.Unix(0, 0), errors.New("invalid authorization level")
 	}
 }
+
+// GetOpenIDConnectSessionID returns the OpenID Connect 1.0 session id for this session, generating one if necessary.
+func (s *UserSession) GetOpenIDConnectSessionID() string {
+	if s.OpenIDConnect.SessionID == "" {
+		s.OpenIDConnect.SessionID = uuid.New().String()
+	}
+
+	return s.OpenIDConnect.SessionID
+}
+
+// AddOpenIDConnectClient records a client and the subject it was issued tokens for during this session. Returns true
+// if the client was not previously recorded.
+func (s *UserSession) AddOpenIDConnectClient(id, subject string) bool {
+	for _, client := range s.OpenIDConnect.Clients {
+		if client.ID == id && client.Subject == subject {
+			return false
+		}
+	}
+
+	s.OpenIDConnect.Clients = append(s.OpenIDConnect.Clients, OpenIDConnectSessionClient{ID: id, Subject: subject})
+
+	return true
+}
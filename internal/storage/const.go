@@ -5,9 +5,11 @@ import (
 )
 
 const (
+	tableActiveSessions       = "active_sessions"
 	tableAuthenticationLogs   = "authentication_logs"
 	tableDuoDevices           = "duo_devices"
 	tableIdentityVerification = "identity_verification"
+	tableSessionData          = "session_data"
 	tableTOTPConfigurations   = "totp_configurations"
 	tableUserOpaqueIdentifier = "user_opaque_identifier"
 	tableUserPreferences      = "user_preferences"
@@ -21,7 +23,11 @@ const (
 	tableOAuth2RefreshTokenSession  = "oauth2_refresh_token_session" //nolint:gosec // This is not a hardcoded credential.
 	tableOAuth2PKCERequestSession   = "oauth2_pkce_request_session"
 	tableOAuth2OpenIDConnectSession = "oauth2_openid_connect_session"
+	tableOAuth2DeviceCodeSession    = "oauth2_device_code_session"
+	tableOAuth2PARContext           = "oauth2_par_context"
 	tableOAuth2BlacklistedJTI       = "oauth2_blacklisted_jti"
+	tableOAuth2RevokedJTI           = "oauth2_access_token_revoked_jti"
+	tableOAuth2Client               = "oauth2_client"
 
 	tableMigrations = "migrations"
 	tableEncryption = "encryption"
@@ -37,6 +43,8 @@ const (
 	OAuth2SessionTypeRefreshToken
 	OAuth2SessionTypePKCEChallenge
 	OAuth2SessionTypeOpenIDConnect
+	OAuth2SessionTypeDeviceCode
+	OAuth2SessionTypePAR
 )
 
 // String returns a string representation of this OAuth2SessionType.
@@ -52,6 +60,10 @@ func (s OAuth2SessionType) String() string {
 		return "pkce challenge"
 	case OAuth2SessionTypeOpenIDConnect:
 		return "openid connect"
+	case OAuth2SessionTypeDeviceCode:
+		return "device code"
+	case OAuth2SessionTypePAR:
+		return "pushed authorization request context"
 	default:
 		return "invalid"
 	}
@@ -70,6 +82,10 @@ func (s OAuth2SessionType) Table() string {
 		return tableOAuth2PKCERequestSession
 	case OAuth2SessionTypeOpenIDConnect:
 		return tableOAuth2OpenIDConnectSession
+	case OAuth2SessionTypeDeviceCode:
+		return tableOAuth2DeviceCodeSession
+	case OAuth2SessionTypePAR:
+		return tableOAuth2PARContext
 	default:
 		return ""
 	}
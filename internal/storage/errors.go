@@ -17,6 +17,15 @@ var (
 	// ErrNoDuoDevice error thrown when no Duo device and method has been found in DB.
 	ErrNoDuoDevice = errors.New("no Duo device and method saved")
 
+	// ErrNoActiveSession error thrown when no active session has been found in DB.
+	ErrNoActiveSession = errors.New("no active session found")
+
+	// ErrSessionDataVersionConflict error thrown when the session data was saved by another request since it was loaded.
+	ErrSessionDataVersionConflict = errors.New("session data was modified by another request")
+
+	// ErrOAuth2BlacklistedJTIExists error thrown when a JTI which has not expired has already been saved in DB.
+	ErrOAuth2BlacklistedJTIExists = errors.New("oauth2 blacklisted JTI already exists")
+
 	// ErrNoAvailableMigrations is returned when no available migrations can be found.
 	ErrNoAvailableMigrations = errors.New("no available migrations")
 
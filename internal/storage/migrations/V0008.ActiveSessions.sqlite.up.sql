@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS active_sessions (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    session_id CHAR(64) NOT NULL,
+    cookie_domain VARCHAR(255) NOT NULL,
+    username VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_activity_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ip VARCHAR(39) NOT NULL,
+    user_agent TEXT NOT NULL,
+    auth_level INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX active_sessions_session_id_key ON active_sessions (session_id);
+CREATE INDEX active_sessions_username_idx ON active_sessions (username);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS session_data (
+    session_id CHAR(64) NOT NULL PRIMARY KEY,
+    data BLOB NOT NULL,
+    expires_at TIMESTAMP NULL DEFAULT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE INDEX session_data_expires_at_idx ON session_data (expires_at);
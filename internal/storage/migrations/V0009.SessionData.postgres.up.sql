@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS session_data (
+    session_id CHAR(64) CONSTRAINT session_data_pkey PRIMARY KEY,
+    data BYTEA NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NULL DEFAULT NULL
+);
+
+CREATE INDEX session_data_expires_at_idx ON session_data (expires_at);
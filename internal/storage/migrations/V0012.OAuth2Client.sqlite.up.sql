@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS oauth2_client (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    client_id VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    source VARCHAR(20) NOT NULL,
+    registration_token_signature VARCHAR(255) NULL DEFAULT NULL,
+    secret BLOB NULL DEFAULT NULL,
+    metadata TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX oauth2_client_client_id_key ON oauth2_client (client_id);
+CREATE INDEX oauth2_client_registration_token_signature_idx ON oauth2_client (registration_token_signature);
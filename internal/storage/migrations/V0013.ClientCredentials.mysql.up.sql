@@ -0,0 +1,3 @@
+ALTER TABLE oauth2_access_token_session
+    MODIFY challenge_id CHAR(36) NULL DEFAULT NULL,
+    MODIFY subject CHAR(36) NULL DEFAULT NULL;
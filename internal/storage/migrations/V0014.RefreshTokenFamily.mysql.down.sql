@@ -0,0 +1,3 @@
+ALTER TABLE oauth2_refresh_token_session
+    DROP COLUMN family_created_at,
+    DROP COLUMN rotated_at;
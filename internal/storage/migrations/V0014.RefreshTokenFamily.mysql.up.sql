@@ -0,0 +1,6 @@
+ALTER TABLE oauth2_refresh_token_session
+    ADD COLUMN family_created_at TIMESTAMP NULL DEFAULT NULL,
+    ADD COLUMN rotated_at TIMESTAMP NULL DEFAULT NULL;
+
+UPDATE oauth2_refresh_token_session
+SET family_created_at = requested_at;
@@ -0,0 +1,8 @@
+ALTER TABLE oauth2_refresh_token_session
+    ADD COLUMN family_created_at DATETIME NULL DEFAULT NULL;
+
+ALTER TABLE oauth2_refresh_token_session
+    ADD COLUMN rotated_at DATETIME NULL DEFAULT NULL;
+
+UPDATE oauth2_refresh_token_session
+SET family_created_at = requested_at;
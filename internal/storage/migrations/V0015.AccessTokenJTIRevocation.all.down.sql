@@ -0,0 +1 @@
+DROP TABLE IF EXISTS oauth2_access_token_revoked_jti;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS oauth2_access_token_revoked_jti (
+    id INTEGER NOT NULL PRIMARY KEY AUTO_INCREMENT,
+    signature VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_520_ci;
+
+CREATE UNIQUE INDEX oauth2_access_token_revoked_jti_signature_key ON oauth2_access_token_revoked_jti (signature);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS oauth2_access_token_revoked_jti (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    signature VARCHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX oauth2_access_token_revoked_jti_signature_key ON oauth2_access_token_revoked_jti (signature);
@@ -0,0 +1,2 @@
+ALTER TABLE oauth2_device_code_session
+    DROP COLUMN polling_interval;
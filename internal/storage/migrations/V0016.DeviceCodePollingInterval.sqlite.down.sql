@@ -0,0 +1,50 @@
+PRAGMA foreign_keys=off;
+
+DROP INDEX IF EXISTS oauth2_device_code_session_signature_key;
+DROP INDEX IF EXISTS oauth2_device_code_session_user_code_signature_idx;
+DROP INDEX IF EXISTS oauth2_device_code_session_request_id_idx;
+DROP INDEX IF EXISTS oauth2_device_code_session_client_id_idx;
+
+ALTER TABLE oauth2_device_code_session
+    RENAME TO _bkp_DOWN_V0016_oauth2_device_code_session;
+
+CREATE TABLE IF NOT EXISTS oauth2_device_code_session (
+    id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    challenge_id CHAR(36) NULL DEFAULT NULL,
+    request_id VARCHAR(40) NOT NULL,
+    client_id VARCHAR(255) NOT NULL,
+    signature VARCHAR(255) NOT NULL,
+    user_code_signature VARCHAR(255) NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    subject CHAR(36) NULL DEFAULT NULL,
+    requested_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    checked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    requested_scopes TEXT NOT NULL,
+    granted_scopes TEXT NOT NULL,
+    requested_audience TEXT NULL DEFAULT '',
+    granted_audience TEXT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT FALSE,
+    form_data TEXT NOT NULL,
+    session_data BLOB NOT NULL,
+    CONSTRAINT oauth2_device_code_session_challenge_id_fkey
+        FOREIGN KEY (challenge_id)
+            REFERENCES oauth2_consent_session (challenge_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT oauth2_device_code_session_subject_fkey
+        FOREIGN KEY (subject)
+            REFERENCES user_opaque_identifier (identifier) ON UPDATE CASCADE ON DELETE RESTRICT
+);
+
+CREATE UNIQUE INDEX oauth2_device_code_session_signature_key ON oauth2_device_code_session (signature);
+CREATE INDEX oauth2_device_code_session_user_code_signature_idx ON oauth2_device_code_session (user_code_signature);
+CREATE INDEX oauth2_device_code_session_request_id_idx ON oauth2_device_code_session (request_id);
+CREATE INDEX oauth2_device_code_session_client_id_idx ON oauth2_device_code_session (client_id);
+
+INSERT INTO oauth2_device_code_session (id, challenge_id, request_id, client_id, signature, user_code_signature, status, subject, requested_at, checked_at, expires_at, requested_scopes, granted_scopes, requested_audience, granted_audience, active, form_data, session_data)
+SELECT id, challenge_id, request_id, client_id, signature, user_code_signature, status, subject, requested_at, checked_at, expires_at, requested_scopes, granted_scopes, requested_audience, granted_audience, active, form_data, session_data
+FROM _bkp_DOWN_V0016_oauth2_device_code_session
+ORDER BY id;
+
+DROP TABLE IF EXISTS _bkp_DOWN_V0016_oauth2_device_code_session;
+
+PRAGMA foreign_keys=on;
@@ -0,0 +1,2 @@
+ALTER TABLE oauth2_device_code_session
+    ADD COLUMN polling_interval INTEGER NOT NULL DEFAULT 0;
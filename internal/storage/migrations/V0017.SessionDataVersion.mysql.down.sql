@@ -0,0 +1,2 @@
+ALTER TABLE session_data
+    DROP COLUMN version;
@@ -0,0 +1,2 @@
+ALTER TABLE session_data
+    ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
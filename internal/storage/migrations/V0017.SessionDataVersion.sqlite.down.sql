@@ -0,0 +1,22 @@
+PRAGMA foreign_keys=off;
+
+DROP INDEX IF EXISTS session_data_expires_at_idx;
+
+ALTER TABLE session_data
+    RENAME TO _bkp_DOWN_V0017_session_data;
+
+CREATE TABLE IF NOT EXISTS session_data (
+    session_id CHAR(64) NOT NULL PRIMARY KEY,
+    data BLOB NOT NULL,
+    expires_at TIMESTAMP NULL DEFAULT NULL
+);
+
+CREATE INDEX session_data_expires_at_idx ON session_data (expires_at);
+
+INSERT INTO session_data (session_id, data, expires_at)
+SELECT session_id, data, expires_at
+FROM _bkp_DOWN_V0017_session_data;
+
+DROP TABLE IF EXISTS _bkp_DOWN_V0017_session_data;
+
+PRAGMA foreign_keys=on;
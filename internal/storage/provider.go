@@ -51,12 +51,17 @@ type Provider interface {
 
 	SaveOAuth2ConsentPreConfiguration(ctx context.Context, config model.OAuth2ConsentPreConfig) (insertedID int64, err error)
 	LoadOAuth2ConsentPreConfigurations(ctx context.Context, clientID string, subject uuid.UUID) (rows *ConsentPreConfigRows, err error)
+	LoadOAuth2ConsentPreConfigurationsByUsername(ctx context.Context, username string) (configs []model.OAuth2ConsentPreConfig, err error)
+	RevokeOAuth2ConsentPreConfiguration(ctx context.Context, id int64) (err error)
+	RevokeOAuth2SessionsByConsentPreConfiguration(ctx context.Context, id int64) (err error)
 
 	SaveOAuth2ConsentSession(ctx context.Context, consent model.OAuth2ConsentSession) (err error)
 	SaveOAuth2ConsentSessionSubject(ctx context.Context, consent model.OAuth2ConsentSession) (err error)
 	SaveOAuth2ConsentSessionResponse(ctx context.Context, consent model.OAuth2ConsentSession, rejection bool) (err error)
 	SaveOAuth2ConsentSessionGranted(ctx context.Context, id int) (err error)
 	LoadOAuth2ConsentSessionByChallengeID(ctx context.Context, challengeID uuid.UUID) (consent *model.OAuth2ConsentSession, err error)
+	LoadOAuth2ConsentSessionsByUsername(ctx context.Context, username string) (consents []model.OAuth2ConsentSession, err error)
+	RevokeOAuth2SessionsByConsentSession(ctx context.Context, id int) (err error)
 
 	SaveOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, session model.OAuth2Session) (err error)
 	RevokeOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, signature string) (err error)
@@ -64,10 +69,51 @@ type Provider interface {
 	DeactivateOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, signature string) (err error)
 	DeactivateOAuth2SessionByRequestID(ctx context.Context, sessionType OAuth2SessionType, requestID string) (err error)
 	LoadOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, signature string) (session *model.OAuth2Session, err error)
+	RotateOAuth2RefreshTokenSession(ctx context.Context, signature string, rotatedAt time.Time) (err error)
+	LoadOAuth2RefreshTokenFamilyCreatedAt(ctx context.Context, requestID string) (createdAt time.Time, err error)
+
+	SaveOAuth2DeviceCodeSession(ctx context.Context, session model.OAuth2DeviceCodeSession) (err error)
+	SaveOAuth2DeviceCodeSessionChallengeID(ctx context.Context, id int, challengeID uuid.UUID) (err error)
+	SaveOAuth2DeviceCodeSessionResponse(ctx context.Context, session model.OAuth2DeviceCodeSession) (err error)
+	SaveOAuth2DeviceCodeSessionCheckedAt(ctx context.Context, signature string, checkedAt time.Time, interval int) (err error)
+	DeactivateOAuth2DeviceCodeSession(ctx context.Context, signature string) (err error)
+	LoadOAuth2DeviceCodeSession(ctx context.Context, signature string) (session *model.OAuth2DeviceCodeSession, err error)
+	LoadOAuth2DeviceCodeSessionByUserCodeSignature(ctx context.Context, signature string) (session *model.OAuth2DeviceCodeSession, err error)
+	LoadOAuth2DeviceCodeSessionByChallengeID(ctx context.Context, challengeID uuid.UUID) (session *model.OAuth2DeviceCodeSession, err error)
+
+	SaveOAuth2PARContext(ctx context.Context, par model.OAuth2PARContext) (err error)
+	LoadOAuth2PARContext(ctx context.Context, signature string) (par *model.OAuth2PARContext, err error)
+	RevokeOAuth2PARContext(ctx context.Context, signature string) (err error)
+
+	SaveOAuth2Client(ctx context.Context, client model.OAuth2Client) (err error)
+	UpdateOAuth2Client(ctx context.Context, client model.OAuth2Client) (err error)
+	LoadOAuth2Client(ctx context.Context, clientID string) (client *model.OAuth2Client, err error)
+	LoadOAuth2Clients(ctx context.Context, limit, page int) (clients []model.OAuth2Client, err error)
+	DeleteOAuth2Client(ctx context.Context, clientID string) (err error)
 
 	SaveOAuth2BlacklistedJTI(ctx context.Context, blacklistedJTI model.OAuth2BlacklistedJTI) (err error)
+	InsertOAuth2BlacklistedJTI(ctx context.Context, blacklistedJTI model.OAuth2BlacklistedJTI, now time.Time) (err error)
 	LoadOAuth2BlacklistedJTI(ctx context.Context, signature string) (blacklistedJTI *model.OAuth2BlacklistedJTI, err error)
 
+	SaveOAuth2AccessTokenRevokedJTI(ctx context.Context, revokedJTI model.OAuth2BlacklistedJTI) (err error)
+	LoadOAuth2AccessTokenJTIRevoked(ctx context.Context, signature string, now time.Time) (revoked bool, err error)
+
+	SaveActiveSession(ctx context.Context, session model.ActiveSession) (err error)
+	UpdateActiveSessionAuthLevel(ctx context.Context, sessionID, newSessionID string, level int) (err error)
+	UpdateActiveSessionActivity(ctx context.Context, sessionID string, lastActivityAt, expiresAt time.Time) (err error)
+	LoadActiveSession(ctx context.Context, sessionID string) (session *model.ActiveSession, err error)
+	LoadActiveSessionsByUsername(ctx context.Context, username string, now time.Time) (sessions []model.ActiveSession, err error)
+	RevokeActiveSession(ctx context.Context, sessionID string) (err error)
+	RevokeActiveSessionByID(ctx context.Context, username string, id int) (err error)
+	RevokeActiveSessionsByUsername(ctx context.Context, username string) (revoked int64, err error)
+
+	SaveSessionData(ctx context.Context, sessionID string, data []byte, expiresAt time.Time, version int64) (err error)
+	LoadSessionData(ctx context.Context, sessionID string) (data []byte, version int64, err error)
+	RegenerateSessionData(ctx context.Context, sessionID, newSessionID string, expiresAt time.Time) (err error)
+	CountSessionData(ctx context.Context) (count int, err error)
+	DeleteSessionData(ctx context.Context, sessionID string) (err error)
+	DeleteExpiredSessionData(ctx context.Context, now time.Time) (deleted int64, err error)
+
 	SchemaTables(ctx context.Context) (tables []string, err error)
 	SchemaVersion(ctx context.Context) (version int, err error)
 	SchemaLatestVersion() (version int, err error)
@@ -78,6 +124,7 @@ type Provider interface {
 	SchemaMigrationsDown(ctx context.Context, version int) (migrations []model.SchemaMigration, err error)
 
 	SchemaEncryptionChangeKey(ctx context.Context, key string) (err error)
+	SchemaEncryptionReEncrypt(ctx context.Context) (err error)
 	SchemaEncryptionCheckKey(ctx context.Context, verbose bool) (result EncryptionValidationResult, err error)
 
 	Close() (err error)
@@ -87,4 +134,5 @@ type Provider interface {
 type RegulatorProvider interface {
 	AppendAuthenticationLog(ctx context.Context, attempt model.AuthenticationAttempt) (err error)
 	LoadAuthenticationLogs(ctx context.Context, username string, fromDate time.Time, limit, page int) (attempts []model.AuthenticationAttempt, err error)
+	LoadAuthenticationLogsByRemoteIP(ctx context.Context, ip model.NullIP, authType string, fromDate time.Time, limit, page int) (attempts []model.AuthenticationAttempt, err error)
 }
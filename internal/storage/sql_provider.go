@@ -2,7 +2,6 @@ package storage
 
 import (
 	"context"
-	"crypto/sha256"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -16,6 +15,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // NewSQLProvider generates a generic SQLProvider to be used with other SQL provider NewUp's.
@@ -24,15 +24,35 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 
 	provider = SQLProvider{
 		db:         db,
-		key:        sha256.Sum256([]byte(config.Storage.EncryptionKey)),
+		keyring:    utils.NewKeyringFromSecrets(config.Storage.EncryptionKey, config.Storage.PreviousEncryptionKeys...),
 		name:       name,
 		driverName: driverName,
 		config:     config,
 		errOpen:    err,
 		log:        logging.Logger(),
 
+		sqlInsertActiveSession:                   fmt.Sprintf(queryFmtInsertActiveSession, tableActiveSessions),
+		sqlSelectActiveSession:                   fmt.Sprintf(queryFmtSelectActiveSession, tableActiveSessions),
+		sqlSelectActiveSessionsByUsername:        fmt.Sprintf(queryFmtSelectActiveSessionsByUsername, tableActiveSessions),
+		sqlUpdateActiveSessionAuthLevel:          fmt.Sprintf(queryFmtUpdateActiveSessionAuthLevel, tableActiveSessions),
+		sqlUpdateActiveSessionActivity:           fmt.Sprintf(queryFmtUpdateActiveSessionActivity, tableActiveSessions),
+		sqlDeleteActiveSession:                   fmt.Sprintf(queryFmtDeleteActiveSession, tableActiveSessions),
+		sqlDeleteActiveSessionByID:               fmt.Sprintf(queryFmtDeleteActiveSessionByID, tableActiveSessions),
+		sqlDeleteActiveSessionsByUsername:        fmt.Sprintf(queryFmtDeleteActiveSessionsByUsername, tableActiveSessions),
+		sqlDeleteExpiredActiveSessionsByUsername: fmt.Sprintf(queryFmtDeleteExpiredActiveSessionsByUsername, tableActiveSessions),
+
+		sqlSelectSessionData:                   fmt.Sprintf(queryFmtSelectSessionData, tableSessionData),
+		sqlInsertSessionData:                   fmt.Sprintf(queryFmtInsertSessionData, tableSessionData),
+		sqlUpdateSessionData:                   fmt.Sprintf(queryFmtUpdateSessionData, tableSessionData),
+		sqlUpdateSessionDataSessionID:          fmt.Sprintf(queryFmtUpdateSessionDataSessionID, tableSessionData),
+		sqlCountSessionData:                    fmt.Sprintf(queryFmtCountSessionData, tableSessionData),
+		sqlDeleteSessionData:                   fmt.Sprintf(queryFmtDeleteSessionData, tableSessionData),
+		sqlDeleteExpiredSessionData:            fmt.Sprintf(queryFmtDeleteExpiredSessionData, tableSessionData),
+		sqlDeleteExpiredSessionDataBySessionID: fmt.Sprintf(queryFmtDeleteExpiredSessionDataBySessionID, tableSessionData),
+
 		sqlInsertAuthenticationAttempt:            fmt.Sprintf(queryFmtInsertAuthenticationLogEntry, tableAuthenticationLogs),
 		sqlSelectAuthenticationAttemptsByUsername: fmt.Sprintf(queryFmtSelect1FAAuthenticationLogEntryByUsername, tableAuthenticationLogs),
+		sqlSelectAuthenticationAttemptsByRemoteIP: fmt.Sprintf(queryFmtSelectAuthenticationLogEntryByRemoteIP, tableAuthenticationLogs),
 
 		sqlInsertIdentityVerification:  fmt.Sprintf(queryFmtInsertIdentityVerification, tableIdentityVerification),
 		sqlConsumeIdentityVerification: fmt.Sprintf(queryFmtConsumeIdentityVerification, tableIdentityVerification),
@@ -73,12 +93,22 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlInsertOAuth2ConsentPreConfiguration:  fmt.Sprintf(queryFmtInsertOAuth2ConsentPreConfiguration, tableOAuth2ConsentPreConfiguration),
 		sqlSelectOAuth2ConsentPreConfigurations: fmt.Sprintf(queryFmtSelectOAuth2ConsentPreConfigurations, tableOAuth2ConsentPreConfiguration),
 
+		sqlSelectOAuth2ConsentPreConfigurationsByUsername: fmt.Sprintf(queryFmtSelectOAuth2ConsentPreConfigurationsByUsername, tableOAuth2ConsentPreConfiguration, tableUserOpaqueIdentifier),
+		sqlRevokeOAuth2ConsentPreConfiguration:            fmt.Sprintf(queryFmtRevokeOAuth2ConsentPreConfiguration, tableOAuth2ConsentPreConfiguration),
+
+		sqlRevokeOAuth2AccessTokenSessionsByConsentPreConfiguration:  fmt.Sprintf(queryFmtRevokeOAuth2SessionsByConsentPreConfiguration, tableOAuth2AccessTokenSession, tableOAuth2ConsentSession),
+		sqlRevokeOAuth2RefreshTokenSessionsByConsentPreConfiguration: fmt.Sprintf(queryFmtRevokeOAuth2SessionsByConsentPreConfiguration, tableOAuth2RefreshTokenSession, tableOAuth2ConsentSession),
+
 		sqlInsertOAuth2ConsentSession:              fmt.Sprintf(queryFmtInsertOAuth2ConsentSession, tableOAuth2ConsentSession),
 		sqlUpdateOAuth2ConsentSessionSubject:       fmt.Sprintf(queryFmtUpdateOAuth2ConsentSessionSubject, tableOAuth2ConsentSession),
 		sqlUpdateOAuth2ConsentSessionResponse:      fmt.Sprintf(queryFmtUpdateOAuth2ConsentSessionResponse, tableOAuth2ConsentSession),
 		sqlUpdateOAuth2ConsentSessionGranted:       fmt.Sprintf(queryFmtUpdateOAuth2ConsentSessionGranted, tableOAuth2ConsentSession),
 		sqlSelectOAuth2ConsentSessionByChallengeID: fmt.Sprintf(queryFmtSelectOAuth2ConsentSessionByChallengeID, tableOAuth2ConsentSession),
 
+		sqlSelectOAuth2ConsentSessionsByUsername:            fmt.Sprintf(queryFmtSelectOAuth2ConsentSessionsByUsername, tableOAuth2ConsentSession, tableUserOpaqueIdentifier, tableOAuth2RefreshTokenSession),
+		sqlRevokeOAuth2AccessTokenSessionsByConsentSession:  fmt.Sprintf(queryFmtRevokeOAuth2SessionsByConsentSession, tableOAuth2AccessTokenSession, tableOAuth2ConsentSession),
+		sqlRevokeOAuth2RefreshTokenSessionsByConsentSession: fmt.Sprintf(queryFmtRevokeOAuth2SessionsByConsentSession, tableOAuth2RefreshTokenSession, tableOAuth2ConsentSession),
+
 		sqlInsertOAuth2AuthorizeCodeSession:                fmt.Sprintf(queryFmtInsertOAuth2Session, tableOAuth2AuthorizeCodeSession),
 		sqlSelectOAuth2AuthorizeCodeSession:                fmt.Sprintf(queryFmtSelectOAuth2Session, tableOAuth2AuthorizeCodeSession),
 		sqlRevokeOAuth2AuthorizeCodeSession:                fmt.Sprintf(queryFmtRevokeOAuth2Session, tableOAuth2AuthorizeCodeSession),
@@ -93,12 +123,14 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlDeactivateOAuth2AccessTokenSession:            fmt.Sprintf(queryFmtDeactivateOAuth2Session, tableOAuth2AccessTokenSession),
 		sqlDeactivateOAuth2AccessTokenSessionByRequestID: fmt.Sprintf(queryFmtDeactivateOAuth2SessionByRequestID, tableOAuth2AccessTokenSession),
 
-		sqlInsertOAuth2RefreshTokenSession:                fmt.Sprintf(queryFmtInsertOAuth2Session, tableOAuth2RefreshTokenSession),
-		sqlSelectOAuth2RefreshTokenSession:                fmt.Sprintf(queryFmtSelectOAuth2Session, tableOAuth2RefreshTokenSession),
+		sqlInsertOAuth2RefreshTokenSession:                fmt.Sprintf(queryFmtInsertOAuth2RefreshTokenSession, tableOAuth2RefreshTokenSession),
+		sqlSelectOAuth2RefreshTokenSession:                fmt.Sprintf(queryFmtSelectOAuth2RefreshTokenSession, tableOAuth2RefreshTokenSession),
 		sqlRevokeOAuth2RefreshTokenSession:                fmt.Sprintf(queryFmtRevokeOAuth2Session, tableOAuth2RefreshTokenSession),
 		sqlRevokeOAuth2RefreshTokenSessionByRequestID:     fmt.Sprintf(queryFmtRevokeOAuth2SessionByRequestID, tableOAuth2RefreshTokenSession),
 		sqlDeactivateOAuth2RefreshTokenSession:            fmt.Sprintf(queryFmtDeactivateOAuth2Session, tableOAuth2RefreshTokenSession),
 		sqlDeactivateOAuth2RefreshTokenSessionByRequestID: fmt.Sprintf(queryFmtDeactivateOAuth2SessionByRequestID, tableOAuth2RefreshTokenSession),
+		sqlRotateOAuth2RefreshTokenSession:                fmt.Sprintf(queryFmtRotateOAuth2RefreshTokenSession, tableOAuth2RefreshTokenSession),
+		sqlSelectOAuth2RefreshTokenFamilyCreatedAt:        fmt.Sprintf(queryFmtSelectOAuth2RefreshTokenFamilyCreatedAt, tableOAuth2RefreshTokenSession),
 
 		sqlInsertOAuth2PKCERequestSession:                fmt.Sprintf(queryFmtInsertOAuth2Session, tableOAuth2PKCERequestSession),
 		sqlSelectOAuth2PKCERequestSession:                fmt.Sprintf(queryFmtSelectOAuth2Session, tableOAuth2PKCERequestSession),
@@ -114,9 +146,32 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlDeactivateOAuth2OpenIDConnectSession:            fmt.Sprintf(queryFmtDeactivateOAuth2Session, tableOAuth2OpenIDConnectSession),
 		sqlDeactivateOAuth2OpenIDConnectSessionByRequestID: fmt.Sprintf(queryFmtDeactivateOAuth2SessionByRequestID, tableOAuth2OpenIDConnectSession),
 
+		sqlInsertOAuth2DeviceCodeSession:                    fmt.Sprintf(queryFmtInsertOAuth2DeviceCodeSession, tableOAuth2DeviceCodeSession),
+		sqlSelectOAuth2DeviceCodeSession:                    fmt.Sprintf(queryFmtSelectOAuth2DeviceCodeSession, tableOAuth2DeviceCodeSession),
+		sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature: fmt.Sprintf(queryFmtSelectOAuth2DeviceCodeSessionByUserCodeSignature, tableOAuth2DeviceCodeSession),
+		sqlSelectOAuth2DeviceCodeSessionByChallengeID:       fmt.Sprintf(queryFmtSelectOAuth2DeviceCodeSessionByChallengeID, tableOAuth2DeviceCodeSession),
+		sqlUpdateOAuth2DeviceCodeSessionChallengeID:         fmt.Sprintf(queryFmtUpdateOAuth2DeviceCodeSessionChallengeID, tableOAuth2DeviceCodeSession),
+		sqlUpdateOAuth2DeviceCodeSessionResponse:            fmt.Sprintf(queryFmtUpdateOAuth2DeviceCodeSessionResponse, tableOAuth2DeviceCodeSession),
+		sqlUpdateOAuth2DeviceCodeSessionCheckedAt:           fmt.Sprintf(queryFmtUpdateOAuth2DeviceCodeSessionCheckedAt, tableOAuth2DeviceCodeSession),
+		sqlDeactivateOAuth2DeviceCodeSession:                fmt.Sprintf(queryFmtDeactivateOAuth2DeviceCodeSession, tableOAuth2DeviceCodeSession),
+
+		sqlInsertOAuth2PARContext: fmt.Sprintf(queryFmtInsertOAuth2PARContext, tableOAuth2PARContext),
+		sqlSelectOAuth2PARContext: fmt.Sprintf(queryFmtSelectOAuth2PARContext, tableOAuth2PARContext),
+		sqlRevokeOAuth2PARContext: fmt.Sprintf(queryFmtRevokeOAuth2PARContext, tableOAuth2PARContext),
+
+		sqlInsertOAuth2Client:  fmt.Sprintf(queryFmtInsertOAuth2Client, tableOAuth2Client),
+		sqlUpdateOAuth2Client:  fmt.Sprintf(queryFmtUpdateOAuth2Client, tableOAuth2Client),
+		sqlSelectOAuth2Client:  fmt.Sprintf(queryFmtSelectOAuth2Client, tableOAuth2Client),
+		sqlSelectOAuth2Clients: fmt.Sprintf(queryFmtSelectOAuth2Clients, tableOAuth2Client),
+		sqlDeleteOAuth2Client:  fmt.Sprintf(queryFmtDeleteOAuth2Client, tableOAuth2Client),
+
 		sqlUpsertOAuth2BlacklistedJTI: fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI),
+		sqlInsertOAuth2BlacklistedJTI: fmt.Sprintf(queryFmtInsertOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI),
 		sqlSelectOAuth2BlacklistedJTI: fmt.Sprintf(queryFmtSelectOAuth2BlacklistedJTI, tableOAuth2BlacklistedJTI),
 
+		sqlUpsertOAuth2AccessTokenRevokedJTI: fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTI, tableOAuth2RevokedJTI),
+		sqlSelectOAuth2AccessTokenRevokedJTI: fmt.Sprintf(queryFmtSelectOAuth2AccessTokenRevokedJTI, tableOAuth2RevokedJTI),
+
 		sqlInsertMigration:       fmt.Sprintf(queryFmtInsertMigration, tableMigrations),
 		sqlSelectMigrations:      fmt.Sprintf(queryFmtSelectMigrations, tableMigrations),
 		sqlSelectLatestMigration: fmt.Sprintf(queryFmtSelectLatestMigration, tableMigrations),
@@ -133,7 +188,7 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 // SQLProvider is a storage provider persisting data in a SQL database.
 type SQLProvider struct {
 	db         *sqlx.DB
-	key        [32]byte
+	keyring    *utils.Keyring
 	name       string
 	driverName string
 	schema     string
@@ -142,9 +197,31 @@ type SQLProvider struct {
 
 	log *logrus.Logger
 
+	// Table: active_sessions.
+	sqlInsertActiveSession                   string
+	sqlSelectActiveSession                   string
+	sqlSelectActiveSessionsByUsername        string
+	sqlUpdateActiveSessionAuthLevel          string
+	sqlUpdateActiveSessionActivity           string
+	sqlDeleteActiveSession                   string
+	sqlDeleteActiveSessionByID               string
+	sqlDeleteActiveSessionsByUsername        string
+	sqlDeleteExpiredActiveSessionsByUsername string
+
+	// Table: session_data.
+	sqlSelectSessionData                   string
+	sqlInsertSessionData                   string
+	sqlUpdateSessionData                   string
+	sqlUpdateSessionDataSessionID          string
+	sqlCountSessionData                    string
+	sqlDeleteSessionData                   string
+	sqlDeleteExpiredSessionData            string
+	sqlDeleteExpiredSessionDataBySessionID string
+
 	// Table: authentication_logs.
 	sqlInsertAuthenticationAttempt            string
 	sqlSelectAuthenticationAttemptsByUsername string
+	sqlSelectAuthenticationAttemptsByRemoteIP string
 
 	// Table: identity_verification.
 	sqlInsertIdentityVerification  string
@@ -201,6 +278,12 @@ type SQLProvider struct {
 	sqlInsertOAuth2ConsentPreConfiguration  string
 	sqlSelectOAuth2ConsentPreConfigurations string
 
+	sqlSelectOAuth2ConsentPreConfigurationsByUsername string
+	sqlRevokeOAuth2ConsentPreConfiguration            string
+
+	sqlRevokeOAuth2AccessTokenSessionsByConsentPreConfiguration  string
+	sqlRevokeOAuth2RefreshTokenSessionsByConsentPreConfiguration string
+
 	// Table: oauth2_consent_session.
 	sqlInsertOAuth2ConsentSession              string
 	sqlUpdateOAuth2ConsentSessionSubject       string
@@ -208,6 +291,10 @@ type SQLProvider struct {
 	sqlUpdateOAuth2ConsentSessionGranted       string
 	sqlSelectOAuth2ConsentSessionByChallengeID string
 
+	sqlSelectOAuth2ConsentSessionsByUsername            string
+	sqlRevokeOAuth2AccessTokenSessionsByConsentSession  string
+	sqlRevokeOAuth2RefreshTokenSessionsByConsentSession string
+
 	// Table: oauth2_authorization_code_session.
 	sqlInsertOAuth2AuthorizeCodeSession                string
 	sqlSelectOAuth2AuthorizeCodeSession                string
@@ -231,6 +318,8 @@ type SQLProvider struct {
 	sqlRevokeOAuth2RefreshTokenSessionByRequestID     string
 	sqlDeactivateOAuth2RefreshTokenSession            string
 	sqlDeactivateOAuth2RefreshTokenSessionByRequestID string
+	sqlRotateOAuth2RefreshTokenSession                string
+	sqlSelectOAuth2RefreshTokenFamilyCreatedAt        string
 
 	// Table: oauth2_pkce_request_session.
 	sqlInsertOAuth2PKCERequestSession                string
@@ -248,9 +337,36 @@ type SQLProvider struct {
 	sqlDeactivateOAuth2OpenIDConnectSession            string
 	sqlDeactivateOAuth2OpenIDConnectSessionByRequestID string
 
+	// Table: oauth2_device_code_session.
+	sqlInsertOAuth2DeviceCodeSession                    string
+	sqlSelectOAuth2DeviceCodeSession                    string
+	sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature string
+	sqlSelectOAuth2DeviceCodeSessionByChallengeID       string
+	sqlUpdateOAuth2DeviceCodeSessionChallengeID         string
+	sqlUpdateOAuth2DeviceCodeSessionResponse            string
+	sqlUpdateOAuth2DeviceCodeSessionCheckedAt           string
+	sqlDeactivateOAuth2DeviceCodeSession                string
+
+	// Table: oauth2_par_context.
+	sqlInsertOAuth2PARContext string
+	sqlSelectOAuth2PARContext string
+	sqlRevokeOAuth2PARContext string
+
+	// Table: oauth2_client.
+	sqlInsertOAuth2Client  string
+	sqlUpdateOAuth2Client  string
+	sqlSelectOAuth2Client  string
+	sqlSelectOAuth2Clients string
+	sqlDeleteOAuth2Client  string
+
 	sqlUpsertOAuth2BlacklistedJTI string
+	sqlInsertOAuth2BlacklistedJTI string
 	sqlSelectOAuth2BlacklistedJTI string
 
+	// Table: oauth2_access_token_revoked_jti.
+	sqlUpsertOAuth2AccessTokenRevokedJTI string
+	sqlSelectOAuth2AccessTokenRevokedJTI string
+
 	// Utility.
 	sqlSelectExistingTables string
 	sqlFmtRenameTable       string
@@ -307,9 +423,9 @@ func (p *SQLProvider) StartupCheck() (err error) {
 
 // BeginTX begins a transaction.
 func (p *SQLProvider) BeginTX(ctx context.Context) (c context.Context, err error) {
-	var tx *sql.Tx
+	var tx *sqlx.Tx
 
-	if tx, err = p.db.Begin(); err != nil {
+	if tx, err = p.db.Beginx(); err != nil {
 		return nil, err
 	}
 
@@ -318,7 +434,7 @@ func (p *SQLProvider) BeginTX(ctx context.Context) (c context.Context, err error
 
 // Commit performs a database commit.
 func (p *SQLProvider) Commit(ctx context.Context) (err error) {
-	tx, ok := ctx.Value(ctxKeyTransaction).(*sql.Tx)
+	tx, ok := ctx.Value(ctxKeyTransaction).(*sqlx.Tx)
 
 	if !ok {
 		return errors.New("could not retrieve tx")
@@ -329,7 +445,7 @@ func (p *SQLProvider) Commit(ctx context.Context) (err error) {
 
 // Rollback performs a database rollback.
 func (p *SQLProvider) Rollback(ctx context.Context) (err error) {
-	tx, ok := ctx.Value(ctxKeyTransaction).(*sql.Tx)
+	tx, ok := ctx.Value(ctxKeyTransaction).(*sqlx.Tx)
 
 	if !ok {
 		return errors.New("could not retrieve tx")
@@ -338,6 +454,15 @@ func (p *SQLProvider) Rollback(ctx context.Context) (err error) {
 	return tx.Rollback()
 }
 
+// executor returns the transaction started by BeginTX for the given context, or the database if there is none.
+func (p *SQLProvider) executor(ctx context.Context) sqlx.ExtContext {
+	if tx, ok := ctx.Value(ctxKeyTransaction).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return p.db
+}
+
 // SaveUserOpaqueIdentifier saves a new opaque user identifier to the database.
 func (p *SQLProvider) SaveUserOpaqueIdentifier(ctx context.Context, opaqueID model.UserOpaqueIdentifier) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlInsertUserOpaqueIdentifier, opaqueID.Service, opaqueID.SectorID, opaqueID.Username, opaqueID.Identifier); err != nil {
@@ -452,6 +577,38 @@ func (p *SQLProvider) LoadOAuth2ConsentSessionByChallengeID(ctx context.Context,
 	return consent, nil
 }
 
+// LoadOAuth2ConsentSessionsByUsername returns the granted OAuth2.0 consent sessions of a user which were not
+// pre-configured and still have an active refresh token.
+func (p *SQLProvider) LoadOAuth2ConsentSessionsByUsername(ctx context.Context, username string) (consents []model.OAuth2ConsentSession, err error) {
+	consents = []model.OAuth2ConsentSession{}
+
+	if err = p.db.SelectContext(ctx, &consents, p.sqlSelectOAuth2ConsentSessionsByUsername, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting oauth2 consent sessions for user '%s': %w", username, err)
+	}
+
+	return consents, nil
+}
+
+// RevokeOAuth2SessionsByConsentSession revokes the access token and refresh token sessions which were granted using
+// the consent session with the given id.
+func (p *SQLProvider) RevokeOAuth2SessionsByConsentSession(ctx context.Context, id int) (err error) {
+	db := p.executor(ctx)
+
+	if _, err = db.ExecContext(ctx, p.sqlRevokeOAuth2AccessTokenSessionsByConsentSession, id); err != nil {
+		return fmt.Errorf("error revoking oauth2 access token sessions with consent session id '%d': %w", id, err)
+	}
+
+	if _, err = db.ExecContext(ctx, p.sqlRevokeOAuth2RefreshTokenSessionsByConsentSession, id); err != nil {
+		return fmt.Errorf("error revoking oauth2 refresh token sessions with consent session id '%d': %w", id, err)
+	}
+
+	return nil
+}
+
 // SaveOAuth2ConsentPreConfiguration inserts an OAuth2.0 consent pre-configuration.
 func (p *SQLProvider) SaveOAuth2ConsentPreConfiguration(ctx context.Context, config model.OAuth2ConsentPreConfig) (insertedID int64, err error) {
 	switch p.name {
@@ -491,6 +648,47 @@ func (p *SQLProvider) LoadOAuth2ConsentPreConfigurations(ctx context.Context, cl
 	return &ConsentPreConfigRows{rows: r}, nil
 }
 
+// LoadOAuth2ConsentPreConfigurationsByUsername returns the OAuth2.0 consent pre-configurations of a user which have
+// not been revoked and have not expired.
+func (p *SQLProvider) LoadOAuth2ConsentPreConfigurationsByUsername(ctx context.Context, username string) (configs []model.OAuth2ConsentPreConfig, err error) {
+	configs = []model.OAuth2ConsentPreConfig{}
+
+	if err = p.db.SelectContext(ctx, &configs, p.sqlSelectOAuth2ConsentPreConfigurationsByUsername, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting oauth2 consent pre-configurations for user '%s': %w", username, err)
+	}
+
+	return configs, nil
+}
+
+// RevokeOAuth2ConsentPreConfiguration revokes an OAuth2.0 consent pre-configuration given its id.
+func (p *SQLProvider) RevokeOAuth2ConsentPreConfiguration(ctx context.Context, id int64) (err error) {
+	if _, err = p.executor(ctx).ExecContext(ctx, p.sqlRevokeOAuth2ConsentPreConfiguration, id); err != nil {
+		return fmt.Errorf("error revoking oauth2 consent pre-configuration with id '%d': %w", id, err)
+	}
+
+	return nil
+}
+
+// RevokeOAuth2SessionsByConsentPreConfiguration revokes the access token and refresh token sessions which were
+// granted using the consent pre-configuration with the given id.
+func (p *SQLProvider) RevokeOAuth2SessionsByConsentPreConfiguration(ctx context.Context, id int64) (err error) {
+	db := p.executor(ctx)
+
+	if _, err = db.ExecContext(ctx, p.sqlRevokeOAuth2AccessTokenSessionsByConsentPreConfiguration, id); err != nil {
+		return fmt.Errorf("error revoking oauth2 access token sessions with consent pre-configuration id '%d': %w", id, err)
+	}
+
+	if _, err = db.ExecContext(ctx, p.sqlRevokeOAuth2RefreshTokenSessionsByConsentPreConfiguration, id); err != nil {
+		return fmt.Errorf("error revoking oauth2 refresh token sessions with consent pre-configuration id '%d': %w", id, err)
+	}
+
+	return nil
+}
+
 // SaveOAuth2Session saves a OAuth2Session to the database.
 func (p *SQLProvider) SaveOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, session model.OAuth2Session) (err error) {
 	var query string
@@ -507,21 +705,26 @@ func (p *SQLProvider) SaveOAuth2Session(ctx context.Context, sessionType OAuth2S
 	case OAuth2SessionTypeOpenIDConnect:
 		query = p.sqlInsertOAuth2OpenIDConnectSession
 	default:
-		return fmt.Errorf("error inserting oauth2 session for subject '%s' and request id '%s': unknown oauth2 session type '%s'", session.Subject, session.RequestID, sessionType)
+		return fmt.Errorf("error inserting oauth2 session for subject '%s' and request id '%s': unknown oauth2 session type '%s'", session.Subject.String, session.RequestID, sessionType)
 	}
 
 	if session.Session, err = p.encrypt(session.Session); err != nil {
-		return fmt.Errorf("error encrypting the oauth2 %s session data for subject '%s' and request id '%s' and challenge id '%s': %w", sessionType, session.Subject, session.RequestID, session.ChallengeID.String(), err)
+		return fmt.Errorf("error encrypting the oauth2 %s session data for subject '%s' and request id '%s' and challenge id '%s': %w", sessionType, session.Subject.String, session.RequestID, session.ChallengeID.UUID.String(), err)
 	}
 
-	_, err = p.db.ExecContext(ctx, query,
+	args := []any{
 		session.ChallengeID, session.RequestID, session.ClientID, session.Signature,
 		session.Subject, session.RequestedAt, session.RequestedScopes, session.GrantedScopes,
 		session.RequestedAudience, session.GrantedAudience,
-		session.Active, session.Revoked, session.Form, session.Session)
+		session.Active, session.Revoked, session.Form, session.Session,
+	}
 
-	if err != nil {
-		return fmt.Errorf("error inserting oauth2 %s session data for subject '%s' and request id '%s' and challenge id '%s': %w", sessionType, session.Subject, session.RequestID, session.ChallengeID.String(), err)
+	if sessionType == OAuth2SessionTypeRefreshToken {
+		args = append(args, session.FamilyCreatedAt)
+	}
+
+	if _, err = p.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("error inserting oauth2 %s session data for subject '%s' and request id '%s' and challenge id '%s': %w", sessionType, session.Subject.String, session.RequestID, session.ChallengeID.UUID.String(), err)
 	}
 
 	return nil
@@ -631,6 +834,26 @@ func (p *SQLProvider) DeactivateOAuth2SessionByRequestID(ctx context.Context, se
 	return nil
 }
 
+// RotateOAuth2RefreshTokenSession marks an active refresh token OAuth2Session as inactive in the database and records
+// the time it was rotated.
+func (p *SQLProvider) RotateOAuth2RefreshTokenSession(ctx context.Context, signature string, rotatedAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlRotateOAuth2RefreshTokenSession, rotatedAt, signature); err != nil {
+		return fmt.Errorf("error rotating oauth2 %s session with signature '%s': %w", OAuth2SessionTypeRefreshToken.String(), signature, err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2RefreshTokenFamilyCreatedAt loads the time the first refresh token with the given request id was issued
+// from the database. All refresh tokens issued by rotation share the request id of the original grant.
+func (p *SQLProvider) LoadOAuth2RefreshTokenFamilyCreatedAt(ctx context.Context, requestID string) (createdAt time.Time, err error) {
+	if err = p.db.GetContext(ctx, &createdAt, p.sqlSelectOAuth2RefreshTokenFamilyCreatedAt, requestID); err != nil {
+		return createdAt, fmt.Errorf("error selecting oauth2 %s family created at with request id '%s': %w", OAuth2SessionTypeRefreshToken.String(), requestID, err)
+	}
+
+	return createdAt, nil
+}
+
 // LoadOAuth2Session saves a OAuth2Session from the database.
 func (p *SQLProvider) LoadOAuth2Session(ctx context.Context, sessionType OAuth2SessionType, signature string) (session *model.OAuth2Session, err error) {
 	var query string
@@ -657,12 +880,270 @@ func (p *SQLProvider) LoadOAuth2Session(ctx context.Context, sessionType OAuth2S
 	}
 
 	if session.Session, err = p.decrypt(session.Session); err != nil {
-		return nil, fmt.Errorf("error decrypting the oauth2 %s session data with signature '%s' for subject '%s' and request id '%s': %w", sessionType.String(), signature, session.Subject, session.RequestID, err)
+		return nil, fmt.Errorf("error decrypting the oauth2 %s session data with signature '%s' for subject '%s' and request id '%s': %w", sessionType.String(), signature, session.Subject.String, session.RequestID, err)
+	}
+
+	return session, nil
+}
+
+// SaveOAuth2DeviceCodeSession saves a OAuth2DeviceCodeSession to the database.
+func (p *SQLProvider) SaveOAuth2DeviceCodeSession(ctx context.Context, session model.OAuth2DeviceCodeSession) (err error) {
+	if session.Session, err = p.encrypt(session.Session); err != nil {
+		return fmt.Errorf("error encrypting the oauth2 device code session data for client id '%s' and request id '%s': %w", session.ClientID, session.RequestID, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2DeviceCodeSession,
+		session.RequestID, session.ClientID, session.Signature, session.UserCodeSignature, session.Status,
+		session.RequestedAt, session.CheckedAt, session.ExpiresAt, session.RequestedScopes, session.GrantedScopes,
+		session.RequestedAudience, session.GrantedAudience, session.Active, session.Form, session.Session); err != nil {
+		return fmt.Errorf("error inserting oauth2 device code session for client id '%s' and request id '%s': %w", session.ClientID, session.RequestID, err)
+	}
+
+	return nil
+}
+
+// SaveOAuth2DeviceCodeSessionChallengeID updates a pending OAuth2DeviceCodeSession with the challenge ID of the consent
+// session the user is responding to.
+func (p *SQLProvider) SaveOAuth2DeviceCodeSessionChallengeID(ctx context.Context, id int, challengeID uuid.UUID) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2DeviceCodeSessionChallengeID, challengeID, id); err != nil {
+		return fmt.Errorf("error updating oauth2 device code session with id '%d' challenge id '%s': %w", id, challengeID, err)
+	}
+
+	return nil
+}
+
+// SaveOAuth2DeviceCodeSessionResponse updates a pending OAuth2DeviceCodeSession with the response of the user. If the
+// session is no longer pending an error wrapping sql.ErrNoRows is returned.
+func (p *SQLProvider) SaveOAuth2DeviceCodeSessionResponse(ctx context.Context, session model.OAuth2DeviceCodeSession) (err error) {
+	if session.Session, err = p.encrypt(session.Session); err != nil {
+		return fmt.Errorf("error encrypting the oauth2 device code session data for client id '%s' and request id '%s': %w", session.ClientID, session.RequestID, err)
+	}
+
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2DeviceCodeSessionResponse,
+		session.Status, session.Subject, session.GrantedScopes, session.GrantedAudience, session.Session, session.ID); err != nil {
+		return fmt.Errorf("error updating oauth2 device code session response for client id '%s' and request id '%s': %w", session.ClientID, session.RequestID, err)
+	}
+
+	var affected int64
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating oauth2 device code session response for client id '%s' and request id '%s': %w", session.ClientID, session.RequestID, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("error updating oauth2 device code session response for client id '%s' and request id '%s': %w", session.ClientID, session.RequestID, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// SaveOAuth2DeviceCodeSessionCheckedAt updates the time a OAuth2DeviceCodeSession was last polled by the client and
+// the polling interval in seconds the client must respect.
+func (p *SQLProvider) SaveOAuth2DeviceCodeSessionCheckedAt(ctx context.Context, signature string, checkedAt time.Time, interval int) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2DeviceCodeSessionCheckedAt, checkedAt, interval, signature); err != nil {
+		return fmt.Errorf("error updating oauth2 device code session checked at with signature '%s': %w", signature, err)
+	}
+
+	return nil
+}
+
+// DeactivateOAuth2DeviceCodeSession marks an active OAuth2DeviceCodeSession as inactive in the database. If the
+// session is not active an error wrapping sql.ErrNoRows is returned so only one caller can ever deactivate it.
+func (p *SQLProvider) DeactivateOAuth2DeviceCodeSession(ctx context.Context, signature string) (err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlDeactivateOAuth2DeviceCodeSession, signature); err != nil {
+		return fmt.Errorf("error deactivating oauth2 device code session with signature '%s': %w", signature, err)
+	}
+
+	var affected int64
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error deactivating oauth2 device code session with signature '%s': %w", signature, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("error deactivating oauth2 device code session with signature '%s': %w", signature, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// LoadOAuth2DeviceCodeSession loads a OAuth2DeviceCodeSession from the database given the device code signature.
+func (p *SQLProvider) LoadOAuth2DeviceCodeSession(ctx context.Context, signature string) (session *model.OAuth2DeviceCodeSession, err error) {
+	return p.loadOAuth2DeviceCodeSession(ctx, p.sqlSelectOAuth2DeviceCodeSession, "signature", signature)
+}
+
+// LoadOAuth2DeviceCodeSessionByUserCodeSignature loads an active OAuth2DeviceCodeSession from the database given the
+// user code signature.
+func (p *SQLProvider) LoadOAuth2DeviceCodeSessionByUserCodeSignature(ctx context.Context, signature string) (session *model.OAuth2DeviceCodeSession, err error) {
+	return p.loadOAuth2DeviceCodeSession(ctx, p.sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature, "user code signature", signature)
+}
+
+// LoadOAuth2DeviceCodeSessionByChallengeID loads an active OAuth2DeviceCodeSession from the database given the consent
+// session challenge ID.
+func (p *SQLProvider) LoadOAuth2DeviceCodeSessionByChallengeID(ctx context.Context, challengeID uuid.UUID) (session *model.OAuth2DeviceCodeSession, err error) {
+	return p.loadOAuth2DeviceCodeSession(ctx, p.sqlSelectOAuth2DeviceCodeSessionByChallengeID, "challenge id", challengeID)
+}
+
+func (p *SQLProvider) loadOAuth2DeviceCodeSession(ctx context.Context, query, name string, value any) (session *model.OAuth2DeviceCodeSession, err error) {
+	session = &model.OAuth2DeviceCodeSession{}
+
+	if err = p.db.GetContext(ctx, session, query, value); err != nil {
+		return nil, fmt.Errorf("error selecting oauth2 device code session with %s '%v': %w", name, value, err)
+	}
+
+	if session.Session, err = p.decrypt(session.Session); err != nil {
+		return nil, fmt.Errorf("error decrypting the oauth2 device code session data with %s '%v' for client id '%s' and request id '%s': %w", name, value, session.ClientID, session.RequestID, err)
 	}
 
 	return session, nil
 }
 
+// SaveOAuth2PARContext saves a OAuth2PARContext to the database.
+func (p *SQLProvider) SaveOAuth2PARContext(ctx context.Context, par model.OAuth2PARContext) (err error) {
+	if par.Session, err = p.encrypt(par.Session); err != nil {
+		return fmt.Errorf("error encrypting the oauth2 pushed authorization request context data for client id '%s' and request id '%s': %w", par.ClientID, par.RequestID, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2PARContext,
+		par.Signature, par.RequestID, par.ClientID, par.RequestedAt, par.ExpiresAt, par.Scopes, par.Audience,
+		par.ResponseMode, par.DefaultResponseMode, par.Revoked, par.Form, par.Session); err != nil {
+		return fmt.Errorf("error inserting oauth2 pushed authorization request context for client id '%s' and request id '%s': %w", par.ClientID, par.RequestID, err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2PARContext loads a OAuth2PARContext from the database given the request URI signature.
+func (p *SQLProvider) LoadOAuth2PARContext(ctx context.Context, signature string) (par *model.OAuth2PARContext, err error) {
+	par = &model.OAuth2PARContext{}
+
+	if err = p.db.GetContext(ctx, par, p.sqlSelectOAuth2PARContext, signature); err != nil {
+		return nil, fmt.Errorf("error selecting oauth2 pushed authorization request context with signature '%s': %w", signature, err)
+	}
+
+	if par.Session, err = p.decrypt(par.Session); err != nil {
+		return nil, fmt.Errorf("error decrypting the oauth2 pushed authorization request context data with signature '%s' for client id '%s' and request id '%s': %w", signature, par.ClientID, par.RequestID, err)
+	}
+
+	return par, nil
+}
+
+// RevokeOAuth2PARContext marks a OAuth2PARContext as revoked in the database.
+func (p *SQLProvider) RevokeOAuth2PARContext(ctx context.Context, signature string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlRevokeOAuth2PARContext, signature); err != nil {
+		return fmt.Errorf("error revoking oauth2 pushed authorization request context with signature '%s': %w", signature, err)
+	}
+
+	return nil
+}
+
+// SaveOAuth2Client saves a new OAuth2Client to the database.
+func (p *SQLProvider) SaveOAuth2Client(ctx context.Context, client model.OAuth2Client) (err error) {
+	if client.Secret, err = p.encryptOAuth2ClientSecret(client.Secret); err != nil {
+		return fmt.Errorf("error encrypting the oauth2 client secret for client id '%s': %w", client.ClientID, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2Client,
+		client.ClientID, client.CreatedAt, client.UpdatedAt, client.Source, client.RegistrationTokenSignature,
+		client.Secret, client.Metadata); err != nil {
+		return fmt.Errorf("error inserting oauth2 client with client id '%s': %w", client.ClientID, err)
+	}
+
+	return nil
+}
+
+// UpdateOAuth2Client updates the secret, registration token signature, and metadata of an existing OAuth2Client in the
+// database.
+func (p *SQLProvider) UpdateOAuth2Client(ctx context.Context, client model.OAuth2Client) (err error) {
+	if client.Secret, err = p.encryptOAuth2ClientSecret(client.Secret); err != nil {
+		return fmt.Errorf("error encrypting the oauth2 client secret for client id '%s': %w", client.ClientID, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateOAuth2Client,
+		client.UpdatedAt, client.RegistrationTokenSignature, client.Secret, client.Metadata, client.ClientID); err != nil {
+		return fmt.Errorf("error updating oauth2 client with client id '%s': %w", client.ClientID, err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2Client loads an OAuth2Client from the database given the client id.
+func (p *SQLProvider) LoadOAuth2Client(ctx context.Context, clientID string) (client *model.OAuth2Client, err error) {
+	client = &model.OAuth2Client{}
+
+	if err = p.db.GetContext(ctx, client, p.sqlSelectOAuth2Client, clientID); err != nil {
+		return nil, fmt.Errorf("error selecting oauth2 client with client id '%s': %w", clientID, err)
+	}
+
+	if client.Secret, err = p.decryptOAuth2ClientSecret(client.Secret); err != nil {
+		return nil, fmt.Errorf("error decrypting the oauth2 client secret for client id '%s': %w", clientID, err)
+	}
+
+	return client, nil
+}
+
+// LoadOAuth2Clients loads a page of OAuth2Client's from the database.
+func (p *SQLProvider) LoadOAuth2Clients(ctx context.Context, limit, page int) (clients []model.OAuth2Client, err error) {
+	clients = make([]model.OAuth2Client, 0, limit)
+
+	if err = p.db.SelectContext(ctx, &clients, p.sqlSelectOAuth2Clients, limit, limit*page); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting oauth2 clients: %w", err)
+	}
+
+	for i, client := range clients {
+		if clients[i].Secret, err = p.decryptOAuth2ClientSecret(client.Secret); err != nil {
+			return nil, fmt.Errorf("error decrypting the oauth2 client secret for client id '%s': %w", client.ClientID, err)
+		}
+	}
+
+	return clients, nil
+}
+
+// DeleteOAuth2Client deletes an OAuth2Client from the database given the client id.
+func (p *SQLProvider) DeleteOAuth2Client(ctx context.Context, clientID string) (err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlDeleteOAuth2Client, clientID); err != nil {
+		return fmt.Errorf("error deleting oauth2 client with client id '%s': %w", clientID, err)
+	}
+
+	var affected int64
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error deleting oauth2 client with client id '%s': %w", clientID, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("error deleting oauth2 client with client id '%s': %w", clientID, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (p *SQLProvider) encryptOAuth2ClientSecret(secret []byte) (cipherText []byte, err error) {
+	if len(secret) == 0 {
+		return nil, nil
+	}
+
+	return p.encrypt(secret)
+}
+
+func (p *SQLProvider) decryptOAuth2ClientSecret(cipherText []byte) (secret []byte, err error) {
+	if len(cipherText) == 0 {
+		return nil, nil
+	}
+
+	return p.decrypt(cipherText)
+}
+
 // SaveOAuth2BlacklistedJTI saves a OAuth2BlacklistedJTI to the database.
 func (p *SQLProvider) SaveOAuth2BlacklistedJTI(ctx context.Context, blacklistedJTI model.OAuth2BlacklistedJTI) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlUpsertOAuth2BlacklistedJTI, blacklistedJTI.Signature, blacklistedJTI.ExpiresAt); err != nil {
@@ -672,17 +1153,59 @@ func (p *SQLProvider) SaveOAuth2BlacklistedJTI(ctx context.Context, blacklistedJ
 	return nil
 }
 
+// InsertOAuth2BlacklistedJTI saves a OAuth2BlacklistedJTI to the database unless a OAuth2BlacklistedJTI with the same
+// signature which has not expired at the provided time exists, in which case ErrOAuth2BlacklistedJTIExists is returned.
+// The check and the insert are a single statement so concurrent requests with the same JTI can't both succeed.
+func (p *SQLProvider) InsertOAuth2BlacklistedJTI(ctx context.Context, blacklistedJTI model.OAuth2BlacklistedJTI, now time.Time) (err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlInsertOAuth2BlacklistedJTI, blacklistedJTI.Signature, blacklistedJTI.ExpiresAt, now); err != nil {
+		return fmt.Errorf("error inserting oauth2 blacklisted JTI with signature '%s': %w", blacklistedJTI.Signature, err)
+	}
+
+	var affected int64
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error inserting oauth2 blacklisted JTI with signature '%s': %w", blacklistedJTI.Signature, err)
+	}
+
+	if affected == 0 {
+		return ErrOAuth2BlacklistedJTIExists
+	}
+
+	return nil
+}
+
 // LoadOAuth2BlacklistedJTI loads a OAuth2BlacklistedJTI from the database.
 func (p *SQLProvider) LoadOAuth2BlacklistedJTI(ctx context.Context, signature string) (blacklistedJTI *model.OAuth2BlacklistedJTI, err error) {
 	blacklistedJTI = &model.OAuth2BlacklistedJTI{}
 
 	if err = p.db.GetContext(ctx, blacklistedJTI, p.sqlSelectOAuth2BlacklistedJTI, signature); err != nil {
-		return nil, fmt.Errorf("error selecting oauth2 blacklisted JTI with signature '%s': %w", blacklistedJTI.Signature, err)
+		return nil, fmt.Errorf("error selecting oauth2 blacklisted JTI with signature '%s': %w", signature, err)
 	}
 
 	return blacklistedJTI, nil
 }
 
+// SaveOAuth2AccessTokenRevokedJTI saves the signature of the JTI of a revoked JWT access token to the database.
+func (p *SQLProvider) SaveOAuth2AccessTokenRevokedJTI(ctx context.Context, revokedJTI model.OAuth2BlacklistedJTI) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertOAuth2AccessTokenRevokedJTI, revokedJTI.Signature, revokedJTI.ExpiresAt); err != nil {
+		return fmt.Errorf("error inserting oauth2 revoked access token JTI with signature '%s': %w", revokedJTI.Signature, err)
+	}
+
+	return nil
+}
+
+// LoadOAuth2AccessTokenJTIRevoked returns true if the signature of the JTI of a JWT access token has been revoked and
+// the revocation has not yet expired.
+func (p *SQLProvider) LoadOAuth2AccessTokenJTIRevoked(ctx context.Context, signature string, now time.Time) (revoked bool, err error) {
+	if err = p.db.GetContext(ctx, &revoked, p.sqlSelectOAuth2AccessTokenRevokedJTI, signature, now); err != nil {
+		return false, fmt.Errorf("error selecting oauth2 revoked access token JTI with signature '%s': %w", signature, err)
+	}
+
+	return revoked, nil
+}
+
 // SavePreferred2FAMethod save the preferred method for 2FA to the database.
 func (p *SQLProvider) SavePreferred2FAMethod(ctx context.Context, username string, method string) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlUpsertPreferred2FAMethod, username, method); err != nil {
@@ -961,6 +1484,224 @@ func (p *SQLProvider) LoadPreferredDuoDevice(ctx context.Context, username strin
 	return device, nil
 }
 
+// SaveActiveSession saves a new active session and removes any expired active sessions for the same user.
+func (p *SQLProvider) SaveActiveSession(ctx context.Context, session model.ActiveSession) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteExpiredActiveSessionsByUsername, session.Username, session.CreatedAt); err != nil {
+		return fmt.Errorf("error deleting expired active sessions for user '%s': %w", session.Username, err)
+	}
+
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertActiveSession,
+		session.SessionID, session.CookieDomain, session.Username, session.CreatedAt, session.LastActivityAt,
+		session.ExpiresAt, session.IP, session.UserAgent, session.AuthLevel); err != nil {
+		return fmt.Errorf("error inserting active session for user '%s': %w", session.Username, err)
+	}
+
+	return nil
+}
+
+// UpdateActiveSessionAuthLevel updates the authentication level of an active session along with the session id, as
+// the session id is regenerated whenever the authentication level changes.
+func (p *SQLProvider) UpdateActiveSessionAuthLevel(ctx context.Context, sessionID, newSessionID string, level int) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateActiveSessionAuthLevel, newSessionID, level, sessionID); err != nil {
+		return fmt.Errorf("error updating active session authentication level: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateActiveSessionActivity updates the last activity and expiration of an active session.
+func (p *SQLProvider) UpdateActiveSessionActivity(ctx context.Context, sessionID string, lastActivityAt, expiresAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateActiveSessionActivity, lastActivityAt, expiresAt, sessionID); err != nil {
+		return fmt.Errorf("error updating active session activity: %w", err)
+	}
+
+	return nil
+}
+
+// LoadActiveSession loads an active session given the session id. If the active session does not exist a nil
+// session and nil error is returned.
+func (p *SQLProvider) LoadActiveSession(ctx context.Context, sessionID string) (session *model.ActiveSession, err error) {
+	session = &model.ActiveSession{}
+
+	if err = p.db.GetContext(ctx, session, p.sqlSelectActiveSession, sessionID); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("error selecting active session: %w", err)
+		}
+	}
+
+	return session, nil
+}
+
+// LoadActiveSessionsByUsername loads the active sessions which have not expired at the provided time for a given
+// username.
+func (p *SQLProvider) LoadActiveSessionsByUsername(ctx context.Context, username string, now time.Time) (sessions []model.ActiveSession, err error) {
+	if err = p.db.SelectContext(ctx, &sessions, p.sqlSelectActiveSessionsByUsername, username, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting active sessions for user '%s': %w", username, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeActiveSession deletes an active session given the session id.
+func (p *SQLProvider) RevokeActiveSession(ctx context.Context, sessionID string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteActiveSession, sessionID); err != nil {
+		return fmt.Errorf("error deleting active session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeActiveSessionByID deletes an active session given the id and username. If no active session matches
+// ErrNoActiveSession is returned.
+func (p *SQLProvider) RevokeActiveSessionByID(ctx context.Context, username string, id int) (err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlDeleteActiveSessionByID, id, username); err != nil {
+		return fmt.Errorf("error deleting active session with id '%d' for user '%s': %w", id, username, err)
+	}
+
+	var affected int64
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error deleting active session with id '%d' for user '%s': %w", id, username, err)
+	}
+
+	if affected == 0 {
+		return ErrNoActiveSession
+	}
+
+	return nil
+}
+
+// RevokeActiveSessionsByUsername deletes all active sessions for a given username returning the number revoked.
+func (p *SQLProvider) RevokeActiveSessionsByUsername(ctx context.Context, username string) (revoked int64, err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlDeleteActiveSessionsByUsername, username); err != nil {
+		return 0, fmt.Errorf("error deleting active sessions for user '%s': %w", username, err)
+	}
+
+	if revoked, err = result.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("error deleting active sessions for user '%s': %w", username, err)
+	}
+
+	return revoked, nil
+}
+
+// SaveSessionData saves the serialized data of a session. The version is the version of the session data the changes
+// are based on, where 0 indicates a new session. The session data is only saved if it has not been saved by another
+// request since that version was loaded, otherwise ErrSessionDataVersionConflict is returned. A zero expiresAt stores
+// the session data without an expiration.
+func (p *SQLProvider) SaveSessionData(ctx context.Context, sessionID string, data []byte, expiresAt time.Time, version int64) (err error) {
+	expires := sql.NullTime{Time: expiresAt, Valid: !expiresAt.IsZero()}
+
+	if version == 0 {
+		if _, err = p.db.ExecContext(ctx, p.sqlDeleteExpiredSessionDataBySessionID, sessionID, time.Now()); err != nil {
+			return fmt.Errorf("error deleting expired session data: %w", err)
+		}
+
+		if _, err = p.db.ExecContext(ctx, p.sqlInsertSessionData, sessionID, data, expires); err != nil {
+			// The insert fails on the primary key when another request saved the new session first.
+			if current, _, errLoad := p.LoadSessionData(ctx, sessionID); errLoad == nil && current != nil {
+				return ErrSessionDataVersionConflict
+			}
+
+			return fmt.Errorf("error inserting session data: %w", err)
+		}
+
+		return nil
+	}
+
+	var (
+		result   sql.Result
+		affected int64
+	)
+
+	if result, err = p.db.ExecContext(ctx, p.sqlUpdateSessionData, data, expires, sessionID, version); err != nil {
+		return fmt.Errorf("error updating session data: %w", err)
+	}
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating session data: %w", err)
+	}
+
+	if affected == 0 {
+		return ErrSessionDataVersionConflict
+	}
+
+	return nil
+}
+
+// LoadSessionData loads the serialized data and version of a session which has not expired. If the session data does
+// not exist a nil slice, 0 version, and nil error is returned.
+func (p *SQLProvider) LoadSessionData(ctx context.Context, sessionID string) (data []byte, version int64, err error) {
+	row := struct {
+		Data    []byte `db:"data"`
+		Version int64  `db:"version"`
+	}{}
+
+	if err = p.db.GetContext(ctx, &row, p.sqlSelectSessionData, sessionID, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, 0, nil
+		default:
+			return nil, 0, fmt.Errorf("error selecting session data: %w", err)
+		}
+	}
+
+	return row.Data, row.Version, nil
+}
+
+// RegenerateSessionData moves the session data from one session id to another updating the expiration.
+func (p *SQLProvider) RegenerateSessionData(ctx context.Context, sessionID, newSessionID string, expiresAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateSessionDataSessionID, newSessionID, sql.NullTime{Time: expiresAt, Valid: !expiresAt.IsZero()}, sessionID); err != nil {
+		return fmt.Errorf("error updating session data session id: %w", err)
+	}
+
+	return nil
+}
+
+// CountSessionData returns the number of sessions which have not expired.
+func (p *SQLProvider) CountSessionData(ctx context.Context) (count int, err error) {
+	if err = p.db.GetContext(ctx, &count, p.sqlCountSessionData, time.Now()); err != nil {
+		return 0, fmt.Errorf("error counting session data: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteSessionData deletes the serialized data of a session.
+func (p *SQLProvider) DeleteSessionData(ctx context.Context, sessionID string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteSessionData, sessionID); err != nil {
+		return fmt.Errorf("error deleting session data: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredSessionData deletes the serialized data of all sessions which expired before now returning the number
+// deleted.
+func (p *SQLProvider) DeleteExpiredSessionData(ctx context.Context, now time.Time) (deleted int64, err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlDeleteExpiredSessionData, now); err != nil {
+		return 0, fmt.Errorf("error deleting expired session data: %w", err)
+	}
+
+	if deleted, err = result.RowsAffected(); err != nil {
+		return 0, fmt.Errorf("error deleting expired session data: %w", err)
+	}
+
+	return deleted, nil
+}
+
 // AppendAuthenticationLog append a mark to the authentication log.
 func (p *SQLProvider) AppendAuthenticationLog(ctx context.Context, attempt model.AuthenticationAttempt) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlInsertAuthenticationAttempt,
@@ -986,3 +1727,19 @@ func (p *SQLProvider) LoadAuthenticationLogs(ctx context.Context, username strin
 
 	return attempts, nil
 }
+
+// LoadAuthenticationLogsByRemoteIP retrieve the latest authentications of a specific type from a remote IP from the
+// authentication log.
+func (p *SQLProvider) LoadAuthenticationLogsByRemoteIP(ctx context.Context, ip model.NullIP, authType string, fromDate time.Time, limit, page int) (attempts []model.AuthenticationAttempt, err error) {
+	attempts = make([]model.AuthenticationAttempt, 0, limit)
+
+	if err = p.db.SelectContext(ctx, &attempts, p.sqlSelectAuthenticationAttemptsByRemoteIP, fromDate, ip, authType, limit, limit*page); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoAuthenticationLogs
+		}
+
+		return nil, fmt.Errorf("error selecting %s authentication logs for remote ip '%s': %w", authType, ip.IP, err)
+	}
+
+	return attempts, nil
+}
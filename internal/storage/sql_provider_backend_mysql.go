@@ -28,6 +28,7 @@ func NewMySQLProvider(config *schema.Configuration, caCertPool *x509.CertPool) (
 
 	// Specific alterations to this provider.
 	provider.sqlFmtRenameTable = queryFmtMySQLRenameTable
+	provider.sqlInsertOAuth2BlacklistedJTI = fmt.Sprintf(queryFmtInsertOAuth2BlacklistedJTIMySQL, tableOAuth2BlacklistedJTI)
 
 	return provider
 }
@@ -37,6 +37,7 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlUpsertPreferred2FAMethod = fmt.Sprintf(queryFmtUpsertPreferred2FAMethodPostgreSQL, tableUserPreferences)
 	provider.sqlUpsertEncryptionValue = fmt.Sprintf(queryFmtUpsertEncryptionValuePostgreSQL, tableEncryption)
 	provider.sqlUpsertOAuth2BlacklistedJTI = fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTIPostgreSQL, tableOAuth2BlacklistedJTI)
+	provider.sqlUpsertOAuth2AccessTokenRevokedJTI = fmt.Sprintf(queryFmtUpsertOAuth2BlacklistedJTIPostgreSQL, tableOAuth2RevokedJTI)
 	provider.sqlInsertOAuth2ConsentPreConfiguration = fmt.Sprintf(queryFmtInsertOAuth2ConsentPreConfigurationPostgreSQL, tableOAuth2ConsentPreConfiguration)
 
 	// PostgreSQL requires rebinding of any query that contains a '?' placeholder to use the '$#' notation placeholders.
@@ -70,8 +71,28 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlSelectDuoDevice = provider.db.Rebind(provider.sqlSelectDuoDevice)
 	provider.sqlDeleteDuoDevice = provider.db.Rebind(provider.sqlDeleteDuoDevice)
 
+	provider.sqlInsertActiveSession = provider.db.Rebind(provider.sqlInsertActiveSession)
+	provider.sqlSelectActiveSession = provider.db.Rebind(provider.sqlSelectActiveSession)
+	provider.sqlSelectActiveSessionsByUsername = provider.db.Rebind(provider.sqlSelectActiveSessionsByUsername)
+	provider.sqlUpdateActiveSessionAuthLevel = provider.db.Rebind(provider.sqlUpdateActiveSessionAuthLevel)
+	provider.sqlUpdateActiveSessionActivity = provider.db.Rebind(provider.sqlUpdateActiveSessionActivity)
+	provider.sqlDeleteActiveSession = provider.db.Rebind(provider.sqlDeleteActiveSession)
+	provider.sqlDeleteActiveSessionByID = provider.db.Rebind(provider.sqlDeleteActiveSessionByID)
+	provider.sqlDeleteActiveSessionsByUsername = provider.db.Rebind(provider.sqlDeleteActiveSessionsByUsername)
+	provider.sqlDeleteExpiredActiveSessionsByUsername = provider.db.Rebind(provider.sqlDeleteExpiredActiveSessionsByUsername)
+
+	provider.sqlSelectSessionData = provider.db.Rebind(provider.sqlSelectSessionData)
+	provider.sqlInsertSessionData = provider.db.Rebind(provider.sqlInsertSessionData)
+	provider.sqlUpdateSessionData = provider.db.Rebind(provider.sqlUpdateSessionData)
+	provider.sqlUpdateSessionDataSessionID = provider.db.Rebind(provider.sqlUpdateSessionDataSessionID)
+	provider.sqlCountSessionData = provider.db.Rebind(provider.sqlCountSessionData)
+	provider.sqlDeleteSessionData = provider.db.Rebind(provider.sqlDeleteSessionData)
+	provider.sqlDeleteExpiredSessionData = provider.db.Rebind(provider.sqlDeleteExpiredSessionData)
+	provider.sqlDeleteExpiredSessionDataBySessionID = provider.db.Rebind(provider.sqlDeleteExpiredSessionDataBySessionID)
+
 	provider.sqlInsertAuthenticationAttempt = provider.db.Rebind(provider.sqlInsertAuthenticationAttempt)
 	provider.sqlSelectAuthenticationAttemptsByUsername = provider.db.Rebind(provider.sqlSelectAuthenticationAttemptsByUsername)
+	provider.sqlSelectAuthenticationAttemptsByRemoteIP = provider.db.Rebind(provider.sqlSelectAuthenticationAttemptsByRemoteIP)
 
 	provider.sqlInsertMigration = provider.db.Rebind(provider.sqlInsertMigration)
 	provider.sqlSelectMigrations = provider.db.Rebind(provider.sqlSelectMigrations)
@@ -80,12 +101,19 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlSelectEncryptionValue = provider.db.Rebind(provider.sqlSelectEncryptionValue)
 
 	provider.sqlSelectOAuth2ConsentPreConfigurations = provider.db.Rebind(provider.sqlSelectOAuth2ConsentPreConfigurations)
+	provider.sqlSelectOAuth2ConsentPreConfigurationsByUsername = provider.db.Rebind(provider.sqlSelectOAuth2ConsentPreConfigurationsByUsername)
+	provider.sqlRevokeOAuth2ConsentPreConfiguration = provider.db.Rebind(provider.sqlRevokeOAuth2ConsentPreConfiguration)
+	provider.sqlRevokeOAuth2AccessTokenSessionsByConsentPreConfiguration = provider.db.Rebind(provider.sqlRevokeOAuth2AccessTokenSessionsByConsentPreConfiguration)
+	provider.sqlRevokeOAuth2RefreshTokenSessionsByConsentPreConfiguration = provider.db.Rebind(provider.sqlRevokeOAuth2RefreshTokenSessionsByConsentPreConfiguration)
 
 	provider.sqlInsertOAuth2ConsentSession = provider.db.Rebind(provider.sqlInsertOAuth2ConsentSession)
 	provider.sqlUpdateOAuth2ConsentSessionSubject = provider.db.Rebind(provider.sqlUpdateOAuth2ConsentSessionSubject)
 	provider.sqlUpdateOAuth2ConsentSessionResponse = provider.db.Rebind(provider.sqlUpdateOAuth2ConsentSessionResponse)
 	provider.sqlUpdateOAuth2ConsentSessionGranted = provider.db.Rebind(provider.sqlUpdateOAuth2ConsentSessionGranted)
 	provider.sqlSelectOAuth2ConsentSessionByChallengeID = provider.db.Rebind(provider.sqlSelectOAuth2ConsentSessionByChallengeID)
+	provider.sqlSelectOAuth2ConsentSessionsByUsername = provider.db.Rebind(provider.sqlSelectOAuth2ConsentSessionsByUsername)
+	provider.sqlRevokeOAuth2AccessTokenSessionsByConsentSession = provider.db.Rebind(provider.sqlRevokeOAuth2AccessTokenSessionsByConsentSession)
+	provider.sqlRevokeOAuth2RefreshTokenSessionsByConsentSession = provider.db.Rebind(provider.sqlRevokeOAuth2RefreshTokenSessionsByConsentSession)
 
 	provider.sqlInsertOAuth2AuthorizeCodeSession = provider.db.Rebind(provider.sqlInsertOAuth2AuthorizeCodeSession)
 	provider.sqlRevokeOAuth2AuthorizeCodeSession = provider.db.Rebind(provider.sqlRevokeOAuth2AuthorizeCodeSession)
@@ -107,6 +135,8 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlDeactivateOAuth2RefreshTokenSession = provider.db.Rebind(provider.sqlDeactivateOAuth2RefreshTokenSession)
 	provider.sqlDeactivateOAuth2RefreshTokenSessionByRequestID = provider.db.Rebind(provider.sqlDeactivateOAuth2RefreshTokenSessionByRequestID)
 	provider.sqlSelectOAuth2RefreshTokenSession = provider.db.Rebind(provider.sqlSelectOAuth2RefreshTokenSession)
+	provider.sqlRotateOAuth2RefreshTokenSession = provider.db.Rebind(provider.sqlRotateOAuth2RefreshTokenSession)
+	provider.sqlSelectOAuth2RefreshTokenFamilyCreatedAt = provider.db.Rebind(provider.sqlSelectOAuth2RefreshTokenFamilyCreatedAt)
 
 	provider.sqlInsertOAuth2PKCERequestSession = provider.db.Rebind(provider.sqlInsertOAuth2PKCERequestSession)
 	provider.sqlRevokeOAuth2PKCERequestSession = provider.db.Rebind(provider.sqlRevokeOAuth2PKCERequestSession)
@@ -115,6 +145,19 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlDeactivateOAuth2PKCERequestSessionByRequestID = provider.db.Rebind(provider.sqlDeactivateOAuth2PKCERequestSessionByRequestID)
 	provider.sqlSelectOAuth2PKCERequestSession = provider.db.Rebind(provider.sqlSelectOAuth2PKCERequestSession)
 
+	provider.sqlInsertOAuth2DeviceCodeSession = provider.db.Rebind(provider.sqlInsertOAuth2DeviceCodeSession)
+	provider.sqlSelectOAuth2DeviceCodeSession = provider.db.Rebind(provider.sqlSelectOAuth2DeviceCodeSession)
+	provider.sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature = provider.db.Rebind(provider.sqlSelectOAuth2DeviceCodeSessionByUserCodeSignature)
+	provider.sqlSelectOAuth2DeviceCodeSessionByChallengeID = provider.db.Rebind(provider.sqlSelectOAuth2DeviceCodeSessionByChallengeID)
+	provider.sqlUpdateOAuth2DeviceCodeSessionChallengeID = provider.db.Rebind(provider.sqlUpdateOAuth2DeviceCodeSessionChallengeID)
+	provider.sqlUpdateOAuth2DeviceCodeSessionResponse = provider.db.Rebind(provider.sqlUpdateOAuth2DeviceCodeSessionResponse)
+	provider.sqlUpdateOAuth2DeviceCodeSessionCheckedAt = provider.db.Rebind(provider.sqlUpdateOAuth2DeviceCodeSessionCheckedAt)
+	provider.sqlDeactivateOAuth2DeviceCodeSession = provider.db.Rebind(provider.sqlDeactivateOAuth2DeviceCodeSession)
+
+	provider.sqlInsertOAuth2PARContext = provider.db.Rebind(provider.sqlInsertOAuth2PARContext)
+	provider.sqlSelectOAuth2PARContext = provider.db.Rebind(provider.sqlSelectOAuth2PARContext)
+	provider.sqlRevokeOAuth2PARContext = provider.db.Rebind(provider.sqlRevokeOAuth2PARContext)
+
 	provider.sqlInsertOAuth2OpenIDConnectSession = provider.db.Rebind(provider.sqlInsertOAuth2OpenIDConnectSession)
 	provider.sqlRevokeOAuth2OpenIDConnectSession = provider.db.Rebind(provider.sqlRevokeOAuth2OpenIDConnectSession)
 	provider.sqlRevokeOAuth2OpenIDConnectSessionByRequestID = provider.db.Rebind(provider.sqlRevokeOAuth2OpenIDConnectSessionByRequestID)
@@ -122,7 +165,15 @@ func NewPostgreSQLProvider(config *schema.Configuration, caCertPool *x509.CertPo
 	provider.sqlDeactivateOAuth2OpenIDConnectSessionByRequestID = provider.db.Rebind(provider.sqlDeactivateOAuth2OpenIDConnectSessionByRequestID)
 	provider.sqlSelectOAuth2OpenIDConnectSession = provider.db.Rebind(provider.sqlSelectOAuth2OpenIDConnectSession)
 
+	provider.sqlInsertOAuth2Client = provider.db.Rebind(provider.sqlInsertOAuth2Client)
+	provider.sqlUpdateOAuth2Client = provider.db.Rebind(provider.sqlUpdateOAuth2Client)
+	provider.sqlSelectOAuth2Client = provider.db.Rebind(provider.sqlSelectOAuth2Client)
+	provider.sqlSelectOAuth2Clients = provider.db.Rebind(provider.sqlSelectOAuth2Clients)
+	provider.sqlDeleteOAuth2Client = provider.db.Rebind(provider.sqlDeleteOAuth2Client)
+
 	provider.sqlSelectOAuth2BlacklistedJTI = provider.db.Rebind(provider.sqlSelectOAuth2BlacklistedJTI)
+	provider.sqlInsertOAuth2BlacklistedJTI = provider.db.Rebind(provider.sqlInsertOAuth2BlacklistedJTI)
+	provider.sqlSelectOAuth2AccessTokenRevokedJTI = provider.db.Rebind(provider.sqlSelectOAuth2AccessTokenRevokedJTI)
 
 	provider.schema = config.Storage.PostgreSQL.Schema
 
@@ -3,7 +3,6 @@ package storage
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -14,12 +13,12 @@ import (
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
-// SchemaEncryptionChangeKey uses the currently configured key to decrypt values in the database and the key provided
+// SchemaEncryptionChangeKey uses the currently configured keys to decrypt values in the database and the key provided
 // by this command to encrypt the values again and update them using a transaction.
 func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, key string) (err error) {
-	skey := sha256.Sum256([]byte(key))
+	keyring := utils.NewKeyringFromSecrets(key)
 
-	if bytes.Equal(skey[:], p.key[:]) {
+	if bytes.Equal(keyring.Primary()[:], p.keyring.Primary()[:]) {
 		return fmt.Errorf("error changing the storage encryption key: the old key and the new key are the same")
 	}
 
@@ -27,6 +26,27 @@ func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, key string)
 		return fmt.Errorf("error changing the storage encryption key: %w", err)
 	}
 
+	return p.schemaEncryptionReEncrypt(ctx, keyring)
+}
+
+// SchemaEncryptionReEncrypt uses the currently configured keys to decrypt values in the database and the currently
+// configured primary key to encrypt the values again and update them using a transaction. This completes a rotation
+// of the encryption key after the previous key has been added to the previous keys.
+func (p *SQLProvider) SchemaEncryptionReEncrypt(ctx context.Context) (err error) {
+	var result EncryptionValidationResult
+
+	if result, err = p.SchemaEncryptionCheckKey(ctx, false); err != nil {
+		return fmt.Errorf("error re-encrypting the storage: %w", err)
+	}
+
+	if !result.Success() {
+		return fmt.Errorf("error re-encrypting the storage: %w", ErrSchemaEncryptionInvalidKey)
+	}
+
+	return p.schemaEncryptionReEncrypt(ctx, p.keyring)
+}
+
+func (p *SQLProvider) schemaEncryptionReEncrypt(ctx context.Context, keyring *utils.Keyring) (err error) {
 	tx, err := p.db.Beginx()
 	if err != nil {
 		return fmt.Errorf("error beginning transaction to change encryption key: %w", err)
@@ -35,6 +55,7 @@ func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, key string)
 	encChangeFuncs := []EncryptionChangeKeyFunc{
 		schemaEncryptionChangeKeyTOTP,
 		schemaEncryptionChangeKeyWebauthn,
+		schemaEncryptionChangeKeyOAuth2Client,
 	}
 
 	for i := 0; true; i++ {
@@ -48,7 +69,7 @@ func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, key string)
 	}
 
 	for _, encChangeFunc := range encChangeFuncs {
-		if err = encChangeFunc(ctx, p, tx, skey); err != nil {
+		if err = encChangeFunc(ctx, p, tx, keyring); err != nil {
 			if rerr := tx.Rollback(); rerr != nil {
 				return fmt.Errorf("rollback error %v: rollback due to error: %w", rerr, err)
 			}
@@ -57,7 +78,7 @@ func (p *SQLProvider) SchemaEncryptionChangeKey(ctx context.Context, key string)
 		}
 	}
 
-	if err = p.setNewEncryptionCheckValue(ctx, tx, &skey); err != nil {
+	if err = p.setNewEncryptionCheckValue(ctx, tx, keyring); err != nil {
 		if rerr := tx.Rollback(); rerr != nil {
 			return fmt.Errorf("rollback error %v: rollback due to error: %w", rerr, err)
 		}
@@ -91,6 +112,7 @@ func (p *SQLProvider) SchemaEncryptionCheckKey(ctx context.Context, verbose bool
 		encCheckFuncs := []EncryptionCheckKeyFunc{
 			schemaEncryptionCheckKeyTOTP,
 			schemaEncryptionCheckKeyWebauthn,
+			schemaEncryptionCheckKeyOAuth2Client,
 		}
 
 		for i := 0; true; i++ {
@@ -113,7 +135,7 @@ func (p *SQLProvider) SchemaEncryptionCheckKey(ctx context.Context, verbose bool
 	return result, nil
 }
 
-func schemaEncryptionChangeKeyTOTP(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, key [32]byte) (err error) {
+func schemaEncryptionChangeKeyTOTP(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, keyring *utils.Keyring) (err error) {
 	var count int
 
 	if err = tx.GetContext(ctx, &count, fmt.Sprintf(queryFmtSelectRowCount, tableTOTPConfigurations)); err != nil {
@@ -141,7 +163,7 @@ func schemaEncryptionChangeKeyTOTP(ctx context.Context, provider *SQLProvider, t
 			return fmt.Errorf("error decrypting TOTP configuration secret with id '%d': %w", c.ID, err)
 		}
 
-		if c.Secret, err = utils.Encrypt(c.Secret, &key); err != nil {
+		if c.Secret, err = keyring.Encrypt(c.Secret); err != nil {
 			return fmt.Errorf("error encrypting TOTP configuration secret with id '%d': %w", c.ID, err)
 		}
 
@@ -153,7 +175,7 @@ func schemaEncryptionChangeKeyTOTP(ctx context.Context, provider *SQLProvider, t
 	return nil
 }
 
-func schemaEncryptionChangeKeyWebauthn(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, key [32]byte) (err error) {
+func schemaEncryptionChangeKeyWebauthn(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, keyring *utils.Keyring) (err error) {
 	var count int
 
 	if err = tx.GetContext(ctx, &count, fmt.Sprintf(queryFmtSelectRowCount, tableWebauthnDevices)); err != nil {
@@ -181,7 +203,7 @@ func schemaEncryptionChangeKeyWebauthn(ctx context.Context, provider *SQLProvide
 			return fmt.Errorf("error decrypting Webauthn device public key with id '%d': %w", d.ID, err)
 		}
 
-		if d.PublicKey, err = utils.Encrypt(d.PublicKey, &key); err != nil {
+		if d.PublicKey, err = keyring.Encrypt(d.PublicKey); err != nil {
 			return fmt.Errorf("error encrypting Webauthn device public key with id '%d': %w", d.ID, err)
 		}
 
@@ -193,8 +215,48 @@ func schemaEncryptionChangeKeyWebauthn(ctx context.Context, provider *SQLProvide
 	return nil
 }
 
+func schemaEncryptionChangeKeyOAuth2Client(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, keyring *utils.Keyring) (err error) {
+	var count int
+
+	if err = tx.GetContext(ctx, &count, fmt.Sprintf(queryFmtSelectRowCount, tableOAuth2Client)); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return nil
+	}
+
+	clients := make([]encOAuth2Client, 0, count)
+
+	if err = tx.SelectContext(ctx, &clients, fmt.Sprintf(queryFmtSelectOAuth2ClientsEncryptedData, tableOAuth2Client)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+
+		return fmt.Errorf("error selecting oauth2 clients: %w", err)
+	}
+
+	query := provider.db.Rebind(fmt.Sprintf(queryFmtUpdateOAuth2ClientSecret, tableOAuth2Client))
+
+	for _, c := range clients {
+		if c.Secret, err = provider.decrypt(c.Secret); err != nil {
+			return fmt.Errorf("error decrypting oauth2 client secret with id '%d': %w", c.ID, err)
+		}
+
+		if c.Secret, err = keyring.Encrypt(c.Secret); err != nil {
+			return fmt.Errorf("error encrypting oauth2 client secret with id '%d': %w", c.ID, err)
+		}
+
+		if _, err = tx.ExecContext(ctx, query, c.Secret, c.ID); err != nil {
+			return fmt.Errorf("error updating oauth2 client secret with id '%d': %w", c.ID, err)
+		}
+	}
+
+	return nil
+}
+
 func schemaEncryptionChangeKeyOpenIDConnect(typeOAuth2Session OAuth2SessionType) EncryptionChangeKeyFunc {
-	return func(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, key [32]byte) (err error) {
+	return func(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, keyring *utils.Keyring) (err error) {
 		var count int
 
 		if err = tx.GetContext(ctx, &count, fmt.Sprintf(queryFmtSelectRowCount, typeOAuth2Session.Table())); err != nil {
@@ -218,7 +280,7 @@ func schemaEncryptionChangeKeyOpenIDConnect(typeOAuth2Session OAuth2SessionType)
 				return fmt.Errorf("error decrypting oauth2 %s session data with id '%d': %w", typeOAuth2Session.String(), s.ID, err)
 			}
 
-			if s.Session, err = utils.Encrypt(s.Session, &key); err != nil {
+			if s.Session, err = keyring.Encrypt(s.Session); err != nil {
 				return fmt.Errorf("error encrypting oauth2 %s session data with id '%d': %w", typeOAuth2Session.String(), s.ID, err)
 			}
 
@@ -252,9 +314,7 @@ func schemaEncryptionCheckKeyTOTP(ctx context.Context, provider *SQLProvider) (t
 			return tableTOTPConfigurations, EncryptionValidationTableResult{Error: fmt.Errorf("error scanning TOTP configuration to struct: %w", err)}
 		}
 
-		if _, err = provider.decrypt(config.Secret); err != nil {
-			result.Invalid++
-		}
+		result.count(provider.keyring.DecryptPrimary(config.Secret))
 	}
 
 	_ = rows.Close()
@@ -283,9 +343,7 @@ func schemaEncryptionCheckKeyWebauthn(ctx context.Context, provider *SQLProvider
 			return tableWebauthnDevices, EncryptionValidationTableResult{Error: fmt.Errorf("error scanning Webauthn device to struct: %w", err)}
 		}
 
-		if _, err = provider.decrypt(device.PublicKey); err != nil {
-			result.Invalid++
-		}
+		result.count(provider.keyring.DecryptPrimary(device.PublicKey))
 	}
 
 	_ = rows.Close()
@@ -293,6 +351,35 @@ func schemaEncryptionCheckKeyWebauthn(ctx context.Context, provider *SQLProvider
 	return tableWebauthnDevices, result
 }
 
+func schemaEncryptionCheckKeyOAuth2Client(ctx context.Context, provider *SQLProvider) (table string, result EncryptionValidationTableResult) {
+	var (
+		rows *sqlx.Rows
+		err  error
+	)
+
+	if rows, err = provider.db.QueryxContext(ctx, fmt.Sprintf(queryFmtSelectOAuth2ClientsEncryptedData, tableOAuth2Client)); err != nil {
+		return tableOAuth2Client, EncryptionValidationTableResult{Error: fmt.Errorf("error selecting oauth2 clients: %w", err)}
+	}
+
+	var client encOAuth2Client
+
+	for rows.Next() {
+		result.Total++
+
+		if err = rows.StructScan(&client); err != nil {
+			_ = rows.Close()
+
+			return tableOAuth2Client, EncryptionValidationTableResult{Error: fmt.Errorf("error scanning oauth2 client to struct: %w", err)}
+		}
+
+		result.count(provider.keyring.DecryptPrimary(client.Secret))
+	}
+
+	_ = rows.Close()
+
+	return tableOAuth2Client, result
+}
+
 func schemaEncryptionCheckKeyOpenIDConnect(typeOAuth2Session OAuth2SessionType) EncryptionCheckKeyFunc {
 	return func(ctx context.Context, provider *SQLProvider) (table string, result EncryptionValidationTableResult) {
 		var (
@@ -315,9 +402,7 @@ func schemaEncryptionCheckKeyOpenIDConnect(typeOAuth2Session OAuth2SessionType)
 				return typeOAuth2Session.Table(), EncryptionValidationTableResult{Error: fmt.Errorf("error scanning oauth2 %s session to struct: %w", typeOAuth2Session.String(), err)}
 			}
 
-			if _, err = provider.decrypt(session.Session); err != nil {
-				result.Invalid++
-			}
+			result.count(provider.keyring.DecryptPrimary(session.Session))
 		}
 
 		_ = rows.Close()
@@ -327,11 +412,11 @@ func schemaEncryptionCheckKeyOpenIDConnect(typeOAuth2Session OAuth2SessionType)
 }
 
 func (p *SQLProvider) encrypt(clearText []byte) (cipherText []byte, err error) {
-	return utils.Encrypt(clearText, &p.key)
+	return p.keyring.Encrypt(clearText)
 }
 
 func (p *SQLProvider) decrypt(cipherText []byte) (clearText []byte, err error) {
-	return utils.Decrypt(cipherText, &p.key)
+	return p.keyring.Decrypt(cipherText)
 }
 
 func (p *SQLProvider) getEncryptionValue(ctx context.Context, name string) (value []byte, err error) {
@@ -345,13 +430,13 @@ func (p *SQLProvider) getEncryptionValue(ctx context.Context, name string) (valu
 	return p.decrypt(encryptedValue)
 }
 
-func (p *SQLProvider) setNewEncryptionCheckValue(ctx context.Context, conn SQLXConnection, key *[32]byte) (err error) {
+func (p *SQLProvider) setNewEncryptionCheckValue(ctx context.Context, conn SQLXConnection, keyring *utils.Keyring) (err error) {
 	valueClearText, err := uuid.NewRandom()
 	if err != nil {
 		return err
 	}
 
-	value, err := utils.Encrypt([]byte(valueClearText.String()), key)
+	value, err := keyring.Encrypt([]byte(valueClearText.String()))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+const (
+	testEncryptionKeyPrevious = "a-long-encryption-key-used-only-for-tests"
+	testEncryptionKeyPrimary  = "another-long-encryption-key-used-only-for-tests"
+)
+
+func newTestEncryptionSQLiteProvider(t *testing.T, path, key string, previous ...string) *SQLiteProvider {
+	provider := NewSQLiteProvider(&schema.Configuration{
+		Storage: schema.StorageConfiguration{
+			EncryptionKey:          key,
+			PreviousEncryptionKeys: previous,
+			Local:                  &schema.LocalStorageConfiguration{Path: path},
+		},
+	})
+
+	require.NoError(t, provider.StartupCheck())
+
+	return provider
+}
+
+func TestSchemaEncryptionReEncryptShouldReEncryptRowsWithPreviousKey(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "db.sqlite3")
+	now := time.Now().UTC().Truncate(time.Second)
+	subject, challengeID := uuid.New(), uuid.New()
+
+	provider := newTestEncryptionSQLiteProvider(t, path, testEncryptionKeyPrevious)
+
+	require.NoError(t, provider.SaveTOTPConfiguration(ctx, model.TOTPConfiguration{
+		CreatedAt: now, Username: "john", Issuer: "Authelia", Algorithm: "SHA1", Digits: 6, Period: 30, Secret: []byte("totp-secret"),
+	}))
+	require.NoError(t, provider.SaveOAuth2Client(ctx, model.OAuth2Client{
+		ClientID: "confidential", CreatedAt: now, UpdatedAt: now, Source: "registration", Secret: []byte("client-secret"), Metadata: []byte("{}"),
+	}))
+	require.NoError(t, provider.SaveOAuth2Client(ctx, model.OAuth2Client{
+		ClientID: "public", CreatedAt: now, UpdatedAt: now, Source: "registration", Metadata: []byte("{}"),
+	}))
+	require.NoError(t, provider.SaveUserOpaqueIdentifier(ctx, model.UserOpaqueIdentifier{Service: "openid", Username: "john", Identifier: subject}))
+	require.NoError(t, provider.SaveOAuth2ConsentSession(ctx, model.OAuth2ConsentSession{
+		ChallengeID: challengeID, ClientID: "confidential", Subject: uuid.NullUUID{UUID: subject, Valid: true}, RequestedAt: now,
+	}))
+	require.NoError(t, provider.SaveOAuth2Session(ctx, OAuth2SessionTypeAccessToken, model.OAuth2Session{
+		ChallengeID: uuid.NullUUID{UUID: challengeID, Valid: true}, RequestID: "req1", ClientID: "confidential", Signature: "sig1",
+		RequestedAt: now, Subject: sql.NullString{String: subject.String(), Valid: true}, Active: true, Session: []byte(`{"session":1}`),
+	}))
+
+	require.NoError(t, provider.Close())
+
+	// Rows encrypted with the previous key can still be read after the key has been rotated.
+	provider = newTestEncryptionSQLiteProvider(t, path, testEncryptionKeyPrimary, testEncryptionKeyPrevious)
+
+	assertTestEncryptionSQLiteProviderRows(t, provider)
+
+	result, err := provider.SchemaEncryptionCheckKey(ctx, true)
+	require.NoError(t, err)
+
+	assert.True(t, result.Success())
+	assert.Equal(t, 1, result.Tables[tableTOTPConfigurations].Stale)
+	assert.Equal(t, 1, result.Tables[tableOAuth2Client].Stale)
+	assert.Equal(t, 1, result.Tables[tableOAuth2AccessTokenSession].Stale)
+
+	require.NoError(t, provider.SchemaEncryptionReEncrypt(ctx))
+
+	result, err = provider.SchemaEncryptionCheckKey(ctx, true)
+	require.NoError(t, err)
+
+	assert.True(t, result.Success())
+
+	for table, tableResult := range result.Tables {
+		assert.Equal(t, 0, tableResult.Stale, table)
+	}
+
+	require.NoError(t, provider.Close())
+
+	// After re-encryption the previous key is no longer required.
+	provider = newTestEncryptionSQLiteProvider(t, path, testEncryptionKeyPrimary)
+
+	defer provider.Close()
+
+	assertTestEncryptionSQLiteProviderRows(t, provider)
+}
+
+func assertTestEncryptionSQLiteProviderRows(t *testing.T, provider *SQLiteProvider) {
+	ctx := context.Background()
+
+	totp, err := provider.LoadTOTPConfiguration(ctx, "john")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("totp-secret"), totp.Secret)
+
+	client, err := provider.LoadOAuth2Client(ctx, "confidential")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("client-secret"), client.Secret)
+
+	client, err = provider.LoadOAuth2Client(ctx, "public")
+	require.NoError(t, err)
+	assert.Len(t, client.Secret, 0)
+
+	session, err := provider.LoadOAuth2Session(ctx, OAuth2SessionTypeAccessToken, "sig1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte(`{"session":1}`), session.Session)
+}
@@ -211,6 +211,14 @@ const (
 		ORDER BY time DESC
 		LIMIT ?
 		OFFSET ?;`
+
+	queryFmtSelectAuthenticationLogEntryByRemoteIP = `
+		SELECT time, successful, username
+		FROM %s
+		WHERE time > ? AND remote_ip = ? AND auth_type = ? AND banned = FALSE
+		ORDER BY time DESC
+		LIMIT ?
+		OFFSET ?;`
 )
 
 const (
@@ -237,6 +245,24 @@ const (
 		WHERE client_id = ? AND subject = ? AND
 			  revoked = FALSE AND (expires_at IS NULL OR expires_at >= CURRENT_TIMESTAMP);`
 
+	queryFmtSelectOAuth2ConsentPreConfigurationsByUsername = `
+		SELECT c.id, c.client_id, c.subject, c.created_at, c.expires_at, c.revoked, c.scopes, c.audience
+		FROM %s c
+		INNER JOIN %s u ON u.identifier = c.subject
+		WHERE u.username = ? AND
+			  c.revoked = FALSE AND (c.expires_at IS NULL OR c.expires_at >= CURRENT_TIMESTAMP)
+		ORDER BY c.created_at DESC;`
+
+	queryFmtRevokeOAuth2ConsentPreConfiguration = `
+		UPDATE %s
+		SET revoked = TRUE
+		WHERE id = ?;`
+
+	queryFmtRevokeOAuth2SessionsByConsentPreConfiguration = `
+		UPDATE %s
+		SET revoked = TRUE
+		WHERE challenge_id IN (SELECT challenge_id FROM %s WHERE preconfiguration = ?);`
+
 	queryFmtInsertOAuth2ConsentPreConfiguration = `
 		INSERT INTO %s (client_id, subject, created_at, expires_at, revoked, scopes, audience)
 		VALUES(?, ?, ?, ?, ?, ?, ?);`
@@ -246,6 +272,20 @@ const (
 		VALUES($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id;`
 
+	queryFmtSelectOAuth2ConsentSessionsByUsername = `
+		SELECT c.id, c.challenge_id, c.client_id, c.subject, c.authorized, c.granted, c.requested_at, c.responded_at,
+		c.form_data, c.requested_scopes, c.granted_scopes, c.requested_audience, c.granted_audience, c.preconfiguration
+		FROM %s c
+		INNER JOIN %s u ON u.identifier = c.subject
+		WHERE u.username = ? AND c.granted = TRUE AND c.preconfiguration IS NULL AND
+			  EXISTS (SELECT 1 FROM %s r WHERE r.challenge_id = c.challenge_id AND r.active = TRUE AND r.revoked = FALSE)
+		ORDER BY c.responded_at DESC;`
+
+	queryFmtRevokeOAuth2SessionsByConsentSession = `
+		UPDATE %s
+		SET revoked = TRUE
+		WHERE challenge_id IN (SELECT challenge_id FROM %s WHERE id = ?);`
+
 	queryFmtSelectOAuth2ConsentSessionByChallengeID = `
 		SELECT id, challenge_id, client_id, subject, authorized, granted, requested_at, responded_at,
 		form_data, requested_scopes, granted_scopes, requested_audience, granted_audience, preconfiguration
@@ -284,6 +324,20 @@ const (
 		FROM %s
 		WHERE signature = ? AND revoked = FALSE;`
 
+	queryFmtSelectOAuth2RefreshTokenSession = `
+		SELECT id, challenge_id, request_id, client_id, signature, subject, requested_at,
+		requested_scopes, granted_scopes, requested_audience, granted_audience,
+		active, revoked, form_data, session_data, family_created_at, rotated_at
+		FROM %s
+		WHERE signature = ? AND revoked = FALSE;`
+
+	queryFmtSelectOAuth2RefreshTokenFamilyCreatedAt = `
+		SELECT family_created_at
+		FROM %s
+		WHERE request_id = ? AND family_created_at IS NOT NULL
+		ORDER BY id ASC
+		LIMIT 1;`
+
 	queryFmtSelectOAuth2SessionEncryptedData = `
 		SELECT id, session_data
 		FROM %s;`
@@ -294,6 +348,17 @@ const (
 		active, revoked, form_data, session_data)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
 
+	queryFmtInsertOAuth2RefreshTokenSession = `
+		INSERT INTO %s (challenge_id, request_id, client_id, signature, subject, requested_at,
+		requested_scopes, granted_scopes, requested_audience, granted_audience,
+		active, revoked, form_data, session_data, family_created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtRotateOAuth2RefreshTokenSession = `
+		UPDATE %s
+		SET active = FALSE, rotated_at = ?
+		WHERE signature = ? AND active = TRUE;`
+
 	queryFmtRevokeOAuth2Session = `
 		UPDATE %s
 		SET revoked = TRUE
@@ -314,6 +379,106 @@ const (
 		SET active = FALSE
 		WHERE request_id = ?;`
 
+	queryFmtSelectOAuth2DeviceCodeSession = `
+		SELECT id, challenge_id, request_id, client_id, signature, user_code_signature, status, subject,
+		requested_at, checked_at, expires_at, polling_interval, requested_scopes, granted_scopes, requested_audience, granted_audience,
+		active, form_data, session_data
+		FROM %s
+		WHERE signature = ?;`
+
+	queryFmtSelectOAuth2DeviceCodeSessionByUserCodeSignature = `
+		SELECT id, challenge_id, request_id, client_id, signature, user_code_signature, status, subject,
+		requested_at, checked_at, expires_at, polling_interval, requested_scopes, granted_scopes, requested_audience, granted_audience,
+		active, form_data, session_data
+		FROM %s
+		WHERE user_code_signature = ? AND active = TRUE;`
+
+	queryFmtSelectOAuth2DeviceCodeSessionByChallengeID = `
+		SELECT id, challenge_id, request_id, client_id, signature, user_code_signature, status, subject,
+		requested_at, checked_at, expires_at, polling_interval, requested_scopes, granted_scopes, requested_audience, granted_audience,
+		active, form_data, session_data
+		FROM %s
+		WHERE challenge_id = ? AND active = TRUE;`
+
+	queryFmtInsertOAuth2DeviceCodeSession = `
+		INSERT INTO %s (request_id, client_id, signature, user_code_signature, status,
+		requested_at, checked_at, expires_at, requested_scopes, granted_scopes, requested_audience, granted_audience,
+		active, form_data, session_data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpdateOAuth2DeviceCodeSessionChallengeID = `
+		UPDATE %s
+		SET challenge_id = ?
+		WHERE id = ? AND status = 0;`
+
+	queryFmtUpdateOAuth2DeviceCodeSessionResponse = `
+		UPDATE %s
+		SET status = ?, subject = ?, granted_scopes = ?, granted_audience = ?, session_data = ?
+		WHERE id = ? AND status = 0;`
+
+	queryFmtUpdateOAuth2DeviceCodeSessionCheckedAt = `
+		UPDATE %s
+		SET checked_at = ?, polling_interval = ?
+		WHERE signature = ?;`
+
+	queryFmtDeactivateOAuth2DeviceCodeSession = `
+		UPDATE %s
+		SET active = FALSE
+		WHERE signature = ? AND active = TRUE;`
+
+	queryFmtSelectOAuth2PARContext = `
+		SELECT id, signature, request_id, client_id, requested_at, expires_at, scopes, audience,
+		response_mode, response_mode_default, revoked, form_data, session_data
+		FROM %s
+		WHERE signature = ?;`
+
+	queryFmtInsertOAuth2PARContext = `
+		INSERT INTO %s (signature, request_id, client_id, requested_at, expires_at, scopes, audience,
+		response_mode, response_mode_default, revoked, form_data, session_data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtRevokeOAuth2PARContext = `
+		UPDATE %s
+		SET revoked = TRUE
+		WHERE signature = ?;`
+
+	queryFmtSelectOAuth2Client = `
+		SELECT id, client_id, created_at, updated_at, source, registration_token_signature, secret, metadata
+		FROM %s
+		WHERE client_id = ?;`
+
+	queryFmtSelectOAuth2Clients = `
+		SELECT id, client_id, created_at, updated_at, source, registration_token_signature, secret, metadata
+		FROM %s
+		ORDER BY id
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtInsertOAuth2Client = `
+		INSERT INTO %s (client_id, created_at, updated_at, source, registration_token_signature, secret, metadata)
+		VALUES (?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpdateOAuth2Client = `
+		UPDATE %s
+		SET updated_at = ?, registration_token_signature = ?, secret = ?, metadata = ?
+		WHERE client_id = ?;`
+
+	queryFmtDeleteOAuth2Client = `
+		DELETE FROM %s
+		WHERE client_id = ?;`
+
+	//nolint:gosec // These are not hardcoded credentials it's a query to obtain credentials.
+	queryFmtSelectOAuth2ClientsEncryptedData = `
+		SELECT id, secret
+		FROM %s
+		WHERE secret IS NOT NULL;`
+
+	//nolint:gosec // These are not hardcoded credentials it's a query to obtain credentials.
+	queryFmtUpdateOAuth2ClientSecret = `
+		UPDATE %s
+		SET secret = ?
+		WHERE id = ?;`
+
 	queryFmtSelectOAuth2BlacklistedJTI = `
 		SELECT id, signature, expires_at
 		FROM %s
@@ -323,11 +488,26 @@ const (
 		REPLACE INTO %s (signature, expires_at)
 		VALUES(?, ?);`
 
+	queryFmtInsertOAuth2BlacklistedJTI = `
+		INSERT INTO %s (signature, expires_at)
+		VALUES (?, ?)
+			ON CONFLICT (signature)
+			DO UPDATE SET expires_at = excluded.expires_at
+			WHERE %s.expires_at <= ?;`
+
+	queryFmtInsertOAuth2BlacklistedJTIMySQL = `
+		INSERT INTO %s (signature, expires_at)
+		VALUES (?, ?)
+			ON DUPLICATE KEY UPDATE expires_at = IF(expires_at <= ?, VALUES(expires_at), expires_at);`
+
 	queryFmtUpsertOAuth2BlacklistedJTIPostgreSQL = `
 		INSERT INTO %s (signature, expires_at)
 		VALUES ($1, $2)
 			ON CONFLICT (signature)
 			DO UPDATE SET expires_at = $2;`
+
+	queryFmtSelectOAuth2AccessTokenRevokedJTI = `
+		SELECT EXISTS (SELECT id FROM %s WHERE signature = ? AND expires_at > ?);`
 )
 
 const (
@@ -349,3 +529,84 @@ const (
 		SELECT id, service, sector_id, username, identifier
 		FROM %s;`
 )
+
+const (
+	queryFmtInsertActiveSession = `
+		INSERT INTO %s (session_id, cookie_domain, username, created_at, last_activity_at, expires_at, ip, user_agent, auth_level)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtSelectActiveSession = `
+		SELECT id, session_id, cookie_domain, username, created_at, last_activity_at, expires_at, ip, user_agent, auth_level
+		FROM %s
+		WHERE session_id = ?;`
+
+	queryFmtSelectActiveSessionsByUsername = `
+		SELECT id, session_id, cookie_domain, username, created_at, last_activity_at, expires_at, ip, user_agent, auth_level
+		FROM %s
+		WHERE username = ? AND expires_at > ?
+		ORDER BY last_activity_at DESC;`
+
+	queryFmtUpdateActiveSessionAuthLevel = `
+		UPDATE %s
+		SET session_id = ?, auth_level = ?
+		WHERE session_id = ?;`
+
+	queryFmtUpdateActiveSessionActivity = `
+		UPDATE %s
+		SET last_activity_at = ?, expires_at = ?
+		WHERE session_id = ?;`
+
+	queryFmtDeleteActiveSession = `
+		DELETE FROM %s
+		WHERE session_id = ?;`
+
+	queryFmtDeleteActiveSessionByID = `
+		DELETE FROM %s
+		WHERE id = ? AND username = ?;`
+
+	queryFmtDeleteActiveSessionsByUsername = `
+		DELETE FROM %s
+		WHERE username = ?;`
+
+	queryFmtDeleteExpiredActiveSessionsByUsername = `
+		DELETE FROM %s
+		WHERE username = ? AND expires_at <= ?;`
+)
+
+const (
+	queryFmtSelectSessionData = `
+		SELECT data, version
+		FROM %s
+		WHERE session_id = ? AND (expires_at IS NULL OR expires_at > ?);`
+
+	queryFmtInsertSessionData = `
+		INSERT INTO %s (session_id, data, expires_at, version)
+		VALUES (?, ?, ?, 1);`
+
+	queryFmtUpdateSessionData = `
+		UPDATE %s
+		SET data = ?, expires_at = ?, version = version + 1
+		WHERE session_id = ? AND version = ?;`
+
+	queryFmtDeleteExpiredSessionDataBySessionID = `
+		DELETE FROM %s
+		WHERE session_id = ? AND expires_at IS NOT NULL AND expires_at <= ?;`
+
+	queryFmtUpdateSessionDataSessionID = `
+		UPDATE %s
+		SET session_id = ?, expires_at = ?
+		WHERE session_id = ?;`
+
+	queryFmtCountSessionData = `
+		SELECT COUNT(session_id)
+		FROM %s
+		WHERE expires_at IS NULL OR expires_at > ?;`
+
+	queryFmtDeleteSessionData = `
+		DELETE FROM %s
+		WHERE session_id = ?;`
+
+	queryFmtDeleteExpiredSessionData = `
+		DELETE FROM %s
+		WHERE expires_at IS NOT NULL AND expires_at <= ?;`
+)
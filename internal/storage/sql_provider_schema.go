@@ -250,7 +250,7 @@ func (p *SQLProvider) schemaMigrateApply(ctx context.Context, conn SQLXConnectio
 
 	if migration.Version == 1 && migration.Up {
 		// Add the schema encryption value if upgrading to v1.
-		if err = p.setNewEncryptionCheckValue(ctx, conn, &p.key); err != nil {
+		if err = p.setNewEncryptionCheckValue(ctx, conn, p.keyring); err != nil {
 			return err
 		}
 	}
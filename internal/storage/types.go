@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/jmoiron/sqlx"
+
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // SQLXConnection is a *sqlx.DB or *sqlx.Tx.
@@ -22,7 +24,7 @@ type SQLXConnection interface {
 }
 
 // EncryptionChangeKeyFunc handles encryption key changes for a specific table or tables.
-type EncryptionChangeKeyFunc func(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, key [32]byte) (err error)
+type EncryptionChangeKeyFunc func(ctx context.Context, provider *SQLProvider, tx *sqlx.Tx, keyring *utils.Keyring) (err error)
 
 // EncryptionCheckKeyFunc handles encryption key checking for a specific table or tables.
 type EncryptionCheckKeyFunc func(ctx context.Context, provider *SQLProvider) (table string, result EncryptionValidationTableResult)
@@ -37,6 +39,11 @@ type encWebauthnDevice struct {
 	PublicKey []byte `db:"public_key"`
 }
 
+type encOAuth2Client struct {
+	ID     int    `db:"id"`
+	Secret []byte `db:"secret"`
+}
+
 type encTOTPConfiguration struct {
 	ID     int    `db:"id" json:"-"`
 	Secret []byte `db:"secret" json:"-"`
@@ -79,6 +86,16 @@ type EncryptionValidationTableResult struct {
 	Error   error
 	Total   int
 	Invalid int
+	Stale   int
+}
+
+func (r *EncryptionValidationTableResult) count(_ []byte, primary bool, err error) {
+	switch {
+	case err != nil:
+		r.Invalid++
+	case !primary:
+		r.Stale++
+	}
 }
 
 // ResultDescriptor returns a string representing the result.
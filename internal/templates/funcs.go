@@ -7,6 +7,7 @@ import (
 	"encoding/base32"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"hash"
 	"os"
@@ -49,9 +50,17 @@ func FuncMap() map[string]any {
 		"b64dec":     FuncB64Dec,
 		"b32enc":     FuncB32Enc,
 		"b32dec":     FuncB32Dec,
+		"toJson":     FuncToJSON,
 	}
 }
 
+// FuncToJSON is a helper function that provides similar functionality to the helm toJson func.
+func FuncToJSON(v any) string {
+	data, _ := json.Marshal(v)
+
+	return string(data)
+}
+
 // FuncB64Enc is a helper function that provides similar functionality to the helm b64enc func.
 func FuncB64Enc(input string) string {
 	return base64.StdEncoding.EncodeToString([]byte(input))
@@ -469,3 +469,22 @@ func TestFuncStringSQuote(t *testing.T) {
 		})
 	}
 }
+
+func TestFuncToJSON(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     any
+		expected string
+	}{
+		{"ShouldEncodeSlice", []string{"admins", "dev"}, `["admins","dev"]`},
+		{"ShouldEncodeNilSlice", []string(nil), `null`},
+		{"ShouldEncodeString", "abc", `"abc"`},
+		{"ShouldEncodeInvalid", make(chan int), ``},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FuncToJSON(tc.have))
+		})
+	}
+}
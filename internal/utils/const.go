@@ -22,6 +22,12 @@ const (
 	wss    = "wss"
 )
 
+const (
+	keyringKeyIDLength = 8
+)
+
+var keyringHeader = []byte("$AK1$")
+
 // X.509 consts.
 const (
 	BlockTypeRSAPrivateKey      = "RSA PRIVATE KEY"
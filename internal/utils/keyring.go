@@ -0,0 +1,131 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// NewKeyring creates a new Keyring given the primary key and the previous keys. The primary key is used for
+// encryption, and both the primary and previous keys are accepted for decryption.
+func NewKeyring(primary [32]byte, previous ...[32]byte) (keyring *Keyring) {
+	keyring = &Keyring{
+		keys: make([]keyringKey, 0, len(previous)+1),
+	}
+
+	keyring.add(primary)
+
+	for _, key := range previous {
+		keyring.add(key)
+	}
+
+	return keyring
+}
+
+// NewKeyringFromSecrets creates a new Keyring given the primary secret and the previous secrets. Each secret is
+// converted to a 256-bit key using SHA256.
+func NewKeyringFromSecrets(primary string, previous ...string) (keyring *Keyring) {
+	keys := make([][32]byte, len(previous))
+
+	for i, secret := range previous {
+		keys[i] = sha256.Sum256([]byte(secret))
+	}
+
+	return NewKeyring(sha256.Sum256([]byte(primary)), keys...)
+}
+
+// Keyring is a set of 256-bit AES-GCM keys used to rotate keys without invalidating existing ciphertexts. Ciphertexts
+// produced by the Keyring take the form header|id|nonce|ciphertext|tag where '|' indicates concatenation, and the id is
+// the ID of the key used to encrypt the data. Ciphertexts produced by Encrypt without a Keyring are also accepted.
+type Keyring struct {
+	keys []keyringKey
+}
+
+type keyringKey struct {
+	id  []byte
+	key [32]byte
+}
+
+func (k *Keyring) add(key [32]byte) {
+	for _, existing := range k.keys {
+		if existing.key == key {
+			return
+		}
+	}
+
+	k.keys = append(k.keys, keyringKey{id: keyringKeyID(&key), key: key})
+}
+
+// Primary returns the primary key.
+func (k *Keyring) Primary() (key *[32]byte) {
+	return &k.keys[0].key
+}
+
+// Encrypt encrypts data using the primary key and embeds the ID of the primary key in the ciphertext.
+func (k *Keyring) Encrypt(plaintext []byte) (ciphertext []byte, err error) {
+	if ciphertext, err = Encrypt(plaintext, &k.keys[0].key); err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, len(keyringHeader)+keyringKeyIDLength+len(ciphertext))
+
+	result = append(result, keyringHeader...)
+	result = append(result, k.keys[0].id...)
+
+	return append(result, ciphertext...), nil
+}
+
+// Decrypt decrypts data using the key with the ID embedded in the ciphertext. If the ciphertext doesn't have a key ID
+// or the key ID is unknown each key is attempted starting with the primary key.
+func (k *Keyring) Decrypt(ciphertext []byte) (plaintext []byte, err error) {
+	plaintext, _, err = k.DecryptPrimary(ciphertext)
+
+	return plaintext, err
+}
+
+// DecryptPrimary is the same as Decrypt but also indicates if the ciphertext was encrypted with the primary key and
+// includes the primary key ID. If this is false the data should be encrypted again to complete a key rotation.
+func (k *Keyring) DecryptPrimary(ciphertext []byte) (plaintext []byte, primary bool, err error) {
+	if id, body, ok := keyringParse(ciphertext); ok {
+		for i, key := range k.keys {
+			if !bytes.Equal(key.id, id) {
+				continue
+			}
+
+			if plaintext, err = Decrypt(body, &key.key); err == nil {
+				return plaintext, i == 0, nil
+			}
+
+			break
+		}
+	}
+
+	var errPrimary error
+
+	for i, key := range k.keys {
+		if plaintext, err = Decrypt(ciphertext, &key.key); err == nil {
+			return plaintext, false, nil
+		}
+
+		if i == 0 {
+			errPrimary = err
+		}
+	}
+
+	return nil, false, errPrimary
+}
+
+func keyringParse(ciphertext []byte) (id, body []byte, ok bool) {
+	if len(ciphertext) < len(keyringHeader)+keyringKeyIDLength || !bytes.HasPrefix(ciphertext, keyringHeader) {
+		return nil, nil, false
+	}
+
+	n := len(keyringHeader)
+
+	return ciphertext[n : n+keyringKeyIDLength], ciphertext[n+keyringKeyIDLength:], true
+}
+
+func keyringKeyID(key *[32]byte) (id []byte) {
+	sum := sha256.Sum256(key[:])
+
+	return sum[:keyringKeyIDLength]
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringShouldEncryptWithPrimaryKey(t *testing.T) {
+	keyring := NewKeyringFromSecrets("primary", "previous")
+
+	ciphertext, err := keyring.Encrypt([]byte("abc"))
+	require.NoError(t, err)
+
+	key := sha256.Sum256([]byte("primary"))
+
+	assert.Equal(t, key, *keyring.Primary())
+	assert.Equal(t, keyringHeader, ciphertext[:len(keyringHeader)])
+	assert.Equal(t, keyringKeyID(&key), ciphertext[len(keyringHeader):len(keyringHeader)+keyringKeyIDLength])
+
+	plaintext, primary, err := keyring.DecryptPrimary(ciphertext)
+	require.NoError(t, err)
+	assert.True(t, primary)
+	assert.Equal(t, []byte("abc"), plaintext)
+
+	plaintext, err = Decrypt(ciphertext[len(keyringHeader)+keyringKeyIDLength:], &key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("abc"), plaintext)
+}
+
+func TestKeyringShouldDecryptWithPreviousKeys(t *testing.T) {
+	previous := NewKeyringFromSecrets("previous")
+
+	ciphertext, err := previous.Encrypt([]byte("abc"))
+	require.NoError(t, err)
+
+	legacyKey := sha256.Sum256([]byte("legacy"))
+
+	legacy, err := Encrypt([]byte("xyz"), &legacyKey)
+	require.NoError(t, err)
+
+	keyring := NewKeyringFromSecrets("primary", "previous", "legacy", "primary")
+
+	assert.Len(t, keyring.keys, 3)
+
+	plaintext, primary, err := keyring.DecryptPrimary(ciphertext)
+	require.NoError(t, err)
+	assert.False(t, primary)
+	assert.Equal(t, []byte("abc"), plaintext)
+
+	plaintext, primary, err = keyring.DecryptPrimary(legacy)
+	require.NoError(t, err)
+	assert.False(t, primary)
+	assert.Equal(t, []byte("xyz"), plaintext)
+
+	plaintext, err = NewKeyringFromSecrets("legacy").Decrypt(legacy)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("xyz"), plaintext)
+}
+
+func TestKeyringShouldFailToDecryptWithUnknownKeys(t *testing.T) {
+	ciphertext, err := NewKeyringFromSecrets("other").Encrypt([]byte("abc"))
+	require.NoError(t, err)
+
+	keyring := NewKeyringFromSecrets("primary", "previous")
+
+	_, err = keyring.Decrypt(ciphertext)
+	assert.EqualError(t, err, "cipher: message authentication failed")
+
+	_, err = keyring.Decrypt([]byte("abc"))
+	assert.EqualError(t, err, "malformed ciphertext")
+
+	_, err = keyring.Decrypt(append([]byte{}, keyringHeader...))
+	assert.EqualError(t, err, "malformed ciphertext")
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"bytes"
+	"net"
+)
+
+// IsIPInNetworks returns true if the net.IP is contained in any of the provided networks.
+func IsIPInNetworks(ip net.IP, networks []*net.IPNet) (contains bool) {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseXForwardedFor returns the client net.IP given the direct peer net.IP, the value of the X-Forwarded-For header,
+// and a list of trusted networks. The header is only considered if the direct peer is trusted, in which case the header
+// is walked from right to left until the first untrusted hop which is returned. If every hop is trusted the left-most
+// valid hop is returned. The trusted return value indicates if the direct peer was trusted.
+func ParseXForwardedFor(peer net.IP, header []byte, trustedNetworks []*net.IPNet) (ip net.IP, trusted bool) {
+	if !IsIPInNetworks(peer, trustedNetworks) {
+		return peer, false
+	}
+
+	if len(header) == 0 {
+		return peer, true
+	}
+
+	ip = peer
+
+	hops := bytes.Split(header, []byte{','})
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := net.ParseIP(string(bytes.TrimSpace(hops[i])))
+
+		if hop == nil {
+			break
+		}
+
+		ip = hop
+
+		if !IsIPInNetworks(hop, trustedNetworks) {
+			break
+		}
+	}
+
+	return ip, true
+}
+
+// ParseIPNetwork parses a string which is either a CIDR or IP address into a *net.IPNet. IP addresses are parsed as a
+// network containing only that IP address.
+func ParseIPNetwork(value string) (network *net.IPNet, err error) {
+	if ip := net.ParseIP(value); ip != nil {
+		if ipv4 := ip.To4(); ipv4 != nil {
+			return &net.IPNet{IP: ipv4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+
+	if _, network, err = net.ParseCIDR(value); err != nil {
+		return nil, err
+	}
+
+	return network, nil
+}
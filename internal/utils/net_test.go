@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIPNetwork(t *testing.T) {
+	testCases := []struct {
+		name     string
+		have     string
+		expected string
+		err      string
+	}{
+		{"ShouldParseIPv4CIDR", "10.0.0.0/8", "10.0.0.0/8", ""},
+		{"ShouldParseIPv4CIDRWithHostBits", "10.1.2.3/8", "10.0.0.0/8", ""},
+		{"ShouldParseIPv4Address", "192.168.1.1", "192.168.1.1/32", ""},
+		{"ShouldParseIPv6CIDR", "fc00::/7", "fc00::/7", ""},
+		{"ShouldParseIPv6Address", "::1", "::1/128", ""},
+		{"ShouldNotParseInvalidCIDR", "10.0.0.0/33", "", "invalid CIDR address: 10.0.0.0/33"},
+		{"ShouldNotParseInvalidValue", "example.com", "", "invalid CIDR address: example.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := ParseIPNetwork(tc.have)
+
+			if tc.err == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, actual.String())
+			} else {
+				assert.EqualError(t, err, tc.err)
+				assert.Nil(t, actual)
+			}
+		})
+	}
+}
+
+func TestParseXForwardedFor(t *testing.T) {
+	trusted := []*net.IPNet{
+		mustParseIPNetwork(t, "127.0.0.0/8"),
+		mustParseIPNetwork(t, "10.0.0.0/8"),
+	}
+
+	testCases := []struct {
+		name            string
+		peer            string
+		header          string
+		expected        string
+		expectedTrusted bool
+	}{
+		{"ShouldIgnoreHeaderFromUntrustedPeer", "192.168.1.1", "1.1.1.1", "192.168.1.1", false},
+		{"ShouldReturnPeerWhenNoHeader", "127.0.0.1", "", "127.0.0.1", true},
+		{"ShouldReturnOnlyHop", "127.0.0.1", "1.1.1.1", "1.1.1.1", true},
+		{"ShouldReturnFirstUntrustedHopFromRight", "127.0.0.1", "1.1.1.1, 2.2.2.2, 10.0.0.5", "2.2.2.2", true},
+		{"ShouldNotTrustSpoofedLeftMostHop", "127.0.0.1", "6.6.6.6,1.1.1.1", "1.1.1.1", true},
+		{"ShouldReturnLeftMostHopWhenAllTrusted", "127.0.0.1", "10.0.0.1, 10.0.0.2", "10.0.0.1", true},
+		{"ShouldStopAtInvalidHop", "127.0.0.1", "1.1.1.1, bad, 10.0.0.2", "10.0.0.2", true},
+		{"ShouldReturnPeerWhenOnlyHopInvalid", "127.0.0.1", "bad", "127.0.0.1", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var header []byte
+
+			if tc.header != "" {
+				header = []byte(tc.header)
+			}
+
+			ip, isTrusted := ParseXForwardedFor(net.ParseIP(tc.peer), header, trusted)
+
+			assert.Equal(t, tc.expected, ip.String())
+			assert.Equal(t, tc.expectedTrusted, isTrusted)
+		})
+	}
+}
+
+func TestIsIPInNetworks(t *testing.T) {
+	networks := []*net.IPNet{mustParseIPNetwork(t, "10.0.0.0/8")}
+
+	assert.True(t, IsIPInNetworks(net.ParseIP("10.1.1.1"), networks))
+	assert.False(t, IsIPInNetworks(net.ParseIP("11.1.1.1"), networks))
+	assert.False(t, IsIPInNetworks(nil, networks))
+	assert.False(t, IsIPInNetworks(net.ParseIP("10.1.1.1"), nil))
+}
+
+func mustParseIPNetwork(t *testing.T, value string) *net.IPNet {
+	network, err := ParseIPNetwork(value)
+	require.NoError(t, err)
+
+	return network
+}
@@ -9,6 +9,7 @@ import { Route, BrowserRouter as Router, Routes } from "react-router-dom";
 import NotificationBar from "@components/NotificationBar";
 import {
     ConsentRoute,
+    DeviceRoute,
     IndexRoute,
     LogoutRoute,
     RegisterOneTimePasswordRoute,
@@ -31,6 +32,7 @@ import RegisterOneTimePassword from "@views/DeviceRegistration/RegisterOneTimePa
 import RegisterWebauthn from "@views/DeviceRegistration/RegisterWebauthn";
 import BaseLoadingPage from "@views/LoadingPage/BaseLoadingPage";
 import ConsentView from "@views/LoginPortal/ConsentView/ConsentView";
+import DeviceView from "@views/LoginPortal/DeviceView/DeviceView";
 import LoginPortal from "@views/LoginPortal/LoginPortal";
 import SignOut from "@views/LoginPortal/SignOut/SignOut";
 import ResetPasswordStep1 from "@views/ResetPassword/ResetPasswordStep1";
@@ -93,6 +95,7 @@ const App: React.FC<Props> = (props: Props) => {
                                 <Route path={RegisterOneTimePasswordRoute} element={<RegisterOneTimePassword />} />
                                 <Route path={LogoutRoute} element={<SignOut />} />
                                 <Route path={ConsentRoute} element={<ConsentView />} />
+                                <Route path={DeviceRoute} element={<DeviceView />} />
                                 <Route
                                     path={`${IndexRoute}*`}
                                     element={
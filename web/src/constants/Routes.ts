@@ -1,6 +1,7 @@
 export const IndexRoute: string = "/";
 export const AuthenticatedRoute: string = "/authenticated";
 export const ConsentRoute: string = "/consent";
+export const DeviceRoute: string = "/device";
 
 export const SecondFactorRoute: string = "/2fa/";
 export const SecondFactorWebauthnSubRoute: string = "webauthn";
@@ -0,0 +1,8 @@
+import { useSearchParams } from "react-router-dom";
+
+export function useLoginHint() {
+    const [searchParams] = useSearchParams();
+    const loginHint = searchParams.get("login_hint");
+
+    return loginHint === null ? undefined : loginHint;
+}
@@ -16,6 +16,7 @@ export interface ConsentGetResponseBody {
     client_id: string;
     client_description: string;
     scopes: string[];
+    scope_descriptions?: Record<string, string>;
     audience: string[];
     pre_configuration: boolean;
 }
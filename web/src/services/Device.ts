@@ -0,0 +1,16 @@
+import { DeviceUserCodePath } from "@services/Api";
+import { Post } from "@services/Client";
+
+interface DeviceUserCodePostRequestBody {
+    user_code: string;
+}
+
+interface DeviceUserCodePostResponseBody {
+    redirect: string;
+}
+
+export function postDeviceUserCode(userCode: string) {
+    const body: DeviceUserCodePostRequestBody = { user_code: userCode };
+
+    return Post<DeviceUserCodePostResponseBody>(DeviceUserCodePath, body);
+}
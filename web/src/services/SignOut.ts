@@ -1,7 +1,7 @@
 import { LogoutPath } from "@services/Api";
 import { PostWithOptionalResponse } from "@services/Client";
 
-export type SignOutResponse = { safeTargetURL: boolean } | undefined;
+export type SignOutResponse = { safeTargetURL: boolean; frontChannelLogoutURL?: string } | undefined;
 
 export type SignOutBody = {
     targetURL?: string;
@@ -106,7 +106,7 @@ const ConsentView = function (props: Props) {
             case "email":
                 return translate("Access your email addresses");
             default:
-                return id;
+                return response?.scope_descriptions?.[id] ?? id;
         }
     };
 
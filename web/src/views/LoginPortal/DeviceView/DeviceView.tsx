@@ -0,0 +1,107 @@
+import React, { useState } from "react";
+
+import { Button, Grid, Theme, Typography } from "@mui/material";
+import makeStyles from "@mui/styles/makeStyles";
+import { useTranslation } from "react-i18next";
+import { useSearchParams } from "react-router-dom";
+
+import FixedTextField from "@components/FixedTextField";
+import { useNotifications } from "@hooks/NotificationsContext";
+import { useRedirector } from "@hooks/Redirector";
+import LoginLayout from "@layouts/LoginLayout";
+import { postDeviceUserCode } from "@services/Device";
+
+const DeviceView = function () {
+    const styles = useStyles();
+    const [searchParams] = useSearchParams();
+    const [userCode, setUserCode] = useState(searchParams.get("user_code") ?? "");
+    const [error, setError] = useState(false);
+    const { createErrorNotification } = useNotifications();
+    const redirect = useRedirector();
+    const { t: translate } = useTranslation();
+
+    const status = searchParams.get("status");
+
+    const doSubmitUserCode = async () => {
+        if (userCode === "") {
+            setError(true);
+            return;
+        }
+
+        try {
+            const res = await postDeviceUserCode(userCode);
+
+            if (res.redirect) {
+                redirect(res.redirect);
+            } else {
+                throw new Error("Unable to redirect the user");
+            }
+        } catch (err) {
+            setError(true);
+            createErrorNotification(translate("The code is invalid or has expired"));
+        }
+    };
+
+    if (status !== null) {
+        return (
+            <LoginLayout title={translate("Device Authorization")} id="device-stage">
+                <Grid container className={styles.root} spacing={2}>
+                    <Grid item xs={12}>
+                        <Typography id="device-status">
+                            {status === "authorized"
+                                ? translate("The device has been authorized, you may now close this window")
+                                : translate("The device has been denied access, you may now close this window")}
+                        </Typography>
+                    </Grid>
+                </Grid>
+            </LoginLayout>
+        );
+    }
+
+    return (
+        <LoginLayout title={translate("Device Authorization")} id="device-stage">
+            <Grid container className={styles.root} spacing={2}>
+                <Grid item xs={12}>
+                    <Typography>{translate("Enter the code displayed on your device")}</Typography>
+                </Grid>
+                <Grid item xs={12}>
+                    <FixedTextField
+                        id="user-code-textfield"
+                        label={translate("Code")}
+                        variant="outlined"
+                        fullWidth
+                        error={error}
+                        value={userCode}
+                        onChange={(e) => setUserCode(e.target.value)}
+                        onKeyPress={(ev) => {
+                            if (ev.key === "Enter") {
+                                doSubmitUserCode();
+                                ev.preventDefault();
+                            }
+                        }}
+                    />
+                </Grid>
+                <Grid item xs={12}>
+                    <Button
+                        id="submit-button"
+                        variant="contained"
+                        color="primary"
+                        fullWidth
+                        onClick={() => doSubmitUserCode()}
+                    >
+                        {translate("Continue")}
+                    </Button>
+                </Grid>
+            </Grid>
+        </LoginLayout>
+    );
+};
+
+export default DeviceView;
+
+const useStyles = makeStyles((theme: Theme) => ({
+    root: {
+        marginTop: theme.spacing(2),
+        marginBottom: theme.spacing(2),
+    },
+}));
@@ -9,6 +9,7 @@ import { useNavigate } from "react-router-dom";
 
 import FixedTextField from "@components/FixedTextField";
 import { ResetPasswordStep1Route } from "@constants/Routes";
+import { useLoginHint } from "@hooks/LoginHint";
 import { useNotifications } from "@hooks/NotificationsContext";
 import { useRedirectionURL } from "@hooks/RedirectionURL";
 import { useRequestMethod } from "@hooks/RequestMethod";
@@ -35,10 +36,11 @@ const FirstFactorForm = function (props: Props) {
     const redirectionURL = useRedirectionURL();
     const requestMethod = useRequestMethod();
     const [workflow] = useWorkflow();
+    const loginHint = useLoginHint();
 
     const loginChannel = useMemo(() => new BroadcastChannel<boolean>("login"), []);
     const [rememberMe, setRememberMe] = useState(false);
-    const [username, setUsername] = useState("");
+    const [username, setUsername] = useState(loginHint ?? "");
     const [usernameError, setUsernameError] = useState(false);
     const [password, setPassword] = useState("");
     const [passwordError, setPasswordError] = useState(false);
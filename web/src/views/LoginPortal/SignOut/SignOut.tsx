@@ -23,6 +23,7 @@ const SignOut = function (props: Props) {
     const redirector = useRedirector();
     const [timedOut, setTimedOut] = useState(false);
     const [safeRedirect, setSafeRedirect] = useState(false);
+    const [frontChannelLogoutURL, setFrontChannelLogoutURL] = useState<string | undefined>(undefined);
     const { t: translate } = useTranslation();
 
     const doSignOut = useCallback(async () => {
@@ -31,6 +32,9 @@ const SignOut = function (props: Props) {
             if (res !== undefined && res.safeTargetURL) {
                 setSafeRedirect(true);
             }
+            if (res !== undefined && res.frontChannelLogoutURL) {
+                setFrontChannelLogoutURL(res.frontChannelLogoutURL);
+            }
             setTimeout(() => {
                 if (!mounted) {
                     return;
@@ -41,14 +45,24 @@ const SignOut = function (props: Props) {
             console.error(err);
             createErrorNotification(translate("There was an issue signing out"));
         }
-    }, [createErrorNotification, redirectionURL, setSafeRedirect, setTimedOut, mounted, translate]);
+    }, [
+        createErrorNotification,
+        redirectionURL,
+        setSafeRedirect,
+        setFrontChannelLogoutURL,
+        setTimedOut,
+        mounted,
+        translate,
+    ]);
 
     useEffect(() => {
         doSignOut();
     }, [doSignOut]);
 
     if (timedOut) {
-        if (redirectionURL && safeRedirect) {
+        if (frontChannelLogoutURL) {
+            redirector(frontChannelLogoutURL);
+        } else if (redirectionURL && safeRedirect) {
             redirector(redirectionURL);
         } else {
             return <Navigate to={IndexRoute} />;
@@ -46,6 +46,7 @@ export default defineConfig(({ mode }) => {
                 input: {
                     main: resolve(__dirname, "index.html"),
                     oidc_form_post: resolve(__dirname, "oidc_form_post.html"),
+                    oidc_logout: resolve(__dirname, "oidc_logout.html"),
                 },
                 output: {
                     entryFileNames: `static/js/[name].[hash].js`,